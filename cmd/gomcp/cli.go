@@ -0,0 +1,373 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/tools"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+// Exit codes of the command line.
+const (
+	exitOK     = 0
+	exitFailed = 1
+	exitUsage  = 2
+)
+
+const usageHeader = `Usage:
+  gomcp                          Run the MCP server on stdin and stdout
+  gomcp <verb> <noun> [flags]    Run a scaffolding tool without an MCP client
+  gomcp tui [--dir=<dir>]        Scaffold a project and its domains in a full-screen form
+  gomcp wizard [--dir=<dir>]     Scaffold a project and its domains by answering prompts
+  gomcp help [<verb> <noun>]     List the commands, or the flags of one
+
+`
+
+// runCLI runs a scaffolding tool from the command line, e.g. "gomcp scaffold domain
+// --name=post --fields=Title:string". A command names its tool with the words of the tool
+// name, and its flags are the fields of the tool input. It returns the exit code.
+func runCLI(args []string, workingDir string, strict bool, stdout, stderr io.Writer) int {
+	if args[0] == "help" || args[0] == "-h" || args[0] == "--help" || args[0] == "-help" {
+		if len(args) >= 3 {
+			return runCLI(append(args[1:3], "-h"), workingDir, strict, stdout, stderr)
+		}
+		printUsage(stdout)
+		return exitOK
+	}
+	if len(args) < 2 {
+		fmt.Fprintf(stderr, "gomcp: unknown command %q\n\n", args[0])
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	tool := args[0] + "_" + strings.ReplaceAll(args[1], "-", "_")
+	inputType, ok := tools.ToolInputType(tool)
+	if !ok {
+		fmt.Fprintf(stderr, "gomcp: unknown command %q\n\n", args[0]+" "+args[1])
+		printUsage(stderr)
+		return exitUsage
+	}
+
+	fs := flag.NewFlagSet("gomcp "+args[0]+" "+args[1], flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	inputFile := fs.String("input", "", "JSON file with the tool input, or - for stdin; flags override its fields")
+	asJSON := fs.Bool("json", false, "Print the result as JSON")
+	dir := fs.String("dir", workingDir, "Project directory to scaffold in")
+	fields := addInputFlags(fs, inputType, args[1])
+	fs.Usage = func() {
+		fmt.Fprintf(fs.Output(), "Usage: gomcp %s %s [flags]\n\nRuns the %s tool. Lists take comma-separated values, and lists of objects\nalso take a JSON array; fields that are objects take JSON.\n\nFlags:\n", args[0], args[1], tool)
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args[2:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if fs.NArg() > 0 {
+		fmt.Fprintf(stderr, "gomcp: unexpected argument %q\n", fs.Arg(0))
+		return exitUsage
+	}
+
+	input, err := buildInput(*inputFile, fields)
+	if err != nil {
+		fmt.Fprintf(stderr, "gomcp: %v\n", err)
+		return exitUsage
+	}
+
+	registry := tools.NewRegistry(*dir)
+	registry.Strict = strict
+	result, err := registry.Run(tool, input)
+	if err != nil {
+		fmt.Fprintf(stderr, "gomcp: %v\n", err)
+		return exitUsage
+	}
+
+	if *asJSON {
+		out, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Fprintln(stdout, string(out))
+	} else {
+		printResult(stdout, result)
+	}
+	if !result.Success {
+		return exitFailed
+	}
+	return exitOK
+}
+
+// printUsage lists the commands, one per tool.
+func printUsage(w io.Writer) {
+	fmt.Fprint(w, usageHeader)
+	fmt.Fprintln(w, "Commands:")
+	for _, tool := range tools.CommandTools() {
+		verb, noun, _ := strings.Cut(tool, "_")
+		fmt.Fprintf(w, "  %s %s\n", verb, strings.ReplaceAll(noun, "_", "-"))
+	}
+	fmt.Fprint(w, `
+Examples:
+  gomcp scaffold project --name=blog --module-path=example.com/blog --in-current-dir
+  gomcp scaffold domain --name=post --fields=Title:string,Body:string --route-group=authenticated
+  gomcp scaffold domain --input=post.json --dry-run
+`)
+}
+
+// printResult prints a scaffold result for people.
+func printResult(w io.Writer, result types.ScaffoldResult) {
+	if result.Success {
+		fmt.Fprintln(w, result.Message)
+	} else {
+		fmt.Fprintf(w, "Error: %s\n", result.Message)
+	}
+	for _, c := range result.Conflicts {
+		fmt.Fprintf(w, "  conflict: %s (%s)\n", c.Path, c.Description)
+	}
+	printList(w, "Created", result.FilesCreated)
+	updated := make([]string, len(result.FilesUpdated))
+	for i, path := range result.FilesUpdated {
+		updated[i] = path
+		if backup, ok := result.Backups[path]; ok {
+			updated[i] = fmt.Sprintf("%s (backup: %s)", path, backup)
+		}
+	}
+	printList(w, "Updated", updated)
+	printList(w, "Next steps", result.NextSteps)
+}
+
+func printList(w io.Writer, title string, items []string) {
+	if len(items) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "\n%s:\n", title)
+	for _, item := range items {
+		fmt.Fprintf(w, "  %s\n", item)
+	}
+}
+
+// inputFlag sets a field of the tool input from the command line.
+type inputFlag struct {
+	key string
+	typ reflect.Type
+	// value is the JSON value of the field, nil until the flag is set.
+	value any
+}
+
+// addInputFlags adds a flag for each field of the tool input, named after its JSON key
+// with dashes for underscores. --name stands for the name field of the command's noun
+// (domain_name for "scaffold domain"), or for the only name field of the input.
+func addInputFlags(fs *flag.FlagSet, inputType reflect.Type, noun string) []*inputFlag {
+	var fields []*inputFlag
+	var names []*inputFlag
+	var nounName *inputFlag
+	for i := 0; i < inputType.NumField(); i++ {
+		field := inputType.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if !field.IsExported() || key == "" || key == "-" {
+			continue
+		}
+		typ := field.Type
+		if typ.Kind() == reflect.Pointer {
+			typ = typ.Elem()
+		}
+		name := strings.ReplaceAll(key, "_", "-")
+		if fs.Lookup(name) != nil {
+			continue // Shadowed by a flag of the command line itself, so only --input sets it
+		}
+		f := &inputFlag{key: key, typ: typ}
+		fields = append(fields, f)
+		fs.Var(f, name, f.usage())
+
+		if strings.HasSuffix(key, "_name") && typ.Kind() == reflect.String {
+			names = append(names, f)
+			if key == strings.ReplaceAll(noun, "-", "_")+"_name" {
+				nounName = f
+			}
+		}
+	}
+
+	if nounName == nil && len(names) == 1 {
+		nounName = names[0]
+	}
+	if nounName != nil && fs.Lookup("name") == nil {
+		fs.Var(nounName, "name", "Same as --"+strings.ReplaceAll(nounName.key, "_", "-")+" (`string`)")
+	}
+	return fields
+}
+
+// usage describes the values the flag takes.
+func (f *inputFlag) usage() string {
+	switch f.typ.Kind() {
+	case reflect.Slice:
+		if f.typ.Elem().Kind() == reflect.Struct {
+			if keys := leadingStringKeys(f.typ.Elem()); len(keys) > 0 {
+				return fmt.Sprintf("%s (`list` of %s items, or a JSON array)", f.key, strings.Join(keys, ":"))
+			}
+			return fmt.Sprintf("%s (JSON `array`)", f.key)
+		}
+		return fmt.Sprintf("%s (`list`)", f.key)
+	case reflect.Map, reflect.Struct:
+		return fmt.Sprintf("%s (JSON `object`)", f.key)
+	case reflect.Bool:
+		return f.key
+	}
+	return fmt.Sprintf("%s (`%s`)", f.key, f.typ.Kind())
+}
+
+func (f *inputFlag) String() string {
+	if f == nil || f.value == nil {
+		return ""
+	}
+	out, _ := json.Marshal(f.value)
+	return string(out)
+}
+
+// IsBoolFlag lets boolean fields be set with a bare --flag.
+func (f *inputFlag) IsBoolFlag() bool {
+	return f.typ.Kind() == reflect.Bool
+}
+
+func (f *inputFlag) Set(s string) error {
+	switch f.typ.Kind() {
+	case reflect.String:
+		f.value = s
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		f.value = b
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if _, err := strconv.ParseInt(s, 10, 64); err != nil {
+			return err
+		}
+		f.value = json.Number(s)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if _, err := strconv.ParseUint(s, 10, 64); err != nil {
+			return err
+		}
+		f.value = json.Number(s)
+	case reflect.Float32, reflect.Float64:
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return err
+		}
+		f.value = json.Number(s)
+	case reflect.Slice:
+		// Repeated flags add to the list
+		items, _ := f.value.([]any)
+		added, err := f.listItems(s)
+		if err != nil {
+			return err
+		}
+		f.value = append(items, added...)
+	default:
+		var v any
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return fmt.Errorf("expected JSON: %w", err)
+		}
+		f.value = v
+	}
+	return nil
+}
+
+// listItems parses the items of a list flag: a JSON array or object, or comma-separated
+// values. Items of lists of objects are colon-separated values of their leading string
+// fields, e.g. Title:string for fields or belongs_to:User for relationships.
+func (f *inputFlag) listItems(s string) ([]any, error) {
+	trimmed := strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(trimmed, "["):
+		var items []any
+		if err := json.Unmarshal([]byte(trimmed), &items); err != nil {
+			return nil, fmt.Errorf("expected a JSON array: %w", err)
+		}
+		return items, nil
+	case strings.HasPrefix(trimmed, "{"):
+		var item map[string]any
+		if err := json.Unmarshal([]byte(trimmed), &item); err != nil {
+			return nil, fmt.Errorf("expected a JSON object: %w", err)
+		}
+		return []any{item}, nil
+	}
+
+	elem := f.typ.Elem()
+	var keys []string
+	if elem.Kind() == reflect.Struct {
+		keys = leadingStringKeys(elem)
+	}
+	var items []any
+	for _, part := range strings.Split(s, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		switch elem.Kind() {
+		case reflect.String:
+			items = append(items, part)
+		case reflect.Struct:
+			if len(keys) == 0 {
+				return nil, fmt.Errorf("expected a JSON array")
+			}
+			values := strings.SplitN(part, ":", len(keys))
+			item := map[string]any{}
+			for i, v := range values {
+				item[keys[i]] = v
+			}
+			items = append(items, item)
+		default:
+			var v any
+			if err := json.Unmarshal([]byte(part), &v); err != nil {
+				return nil, fmt.Errorf("invalid item %q: %w", part, err)
+			}
+			items = append(items, v)
+		}
+	}
+	return items, nil
+}
+
+// leadingStringKeys returns the JSON keys of the first two fields of a struct when they
+// are strings, which the colon-separated items of list flags set.
+func leadingStringKeys(t reflect.Type) []string {
+	var keys []string
+	for i := 0; i < t.NumField() && len(keys) < 2; i++ {
+		field := t.Field(i)
+		key, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.Type.Kind() != reflect.String || key == "" || key == "-" {
+			break
+		}
+		keys = append(keys, key)
+	}
+	return keys
+}
+
+// buildInput builds the JSON tool input from the --input file and the flags that were set.
+func buildInput(inputFile string, fields []*inputFlag) ([]byte, error) {
+	input := map[string]any{}
+	if inputFile != "" {
+		var data []byte
+		var err error
+		if inputFile == "-" {
+			data, err = io.ReadAll(os.Stdin)
+		} else {
+			data, err = os.ReadFile(inputFile)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading input: %w", err)
+		}
+		if err := json.Unmarshal(data, &input); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", inputFile, err)
+		}
+	}
+	for _, f := range fields {
+		if f.value != nil {
+			input[f.key] = f.value
+		}
+	}
+	return json.Marshal(input)
+}
@@ -0,0 +1,127 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestRunCLI(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) (int, string, string) {
+		var stdout, stderr bytes.Buffer
+		code := runCLI(args, dir, false, &stdout, &stderr)
+		return code, stdout.String(), stderr.String()
+	}
+
+	code, out, errOut := run("scaffold", "project", "--name=blog", "--module-path=example.com/blog", "--in-current-dir")
+	if code != exitOK {
+		t.Fatalf("scaffold project exited with %d: %s%s", code, out, errOut)
+	}
+
+	code, out, errOut = run("scaffold", "domain", "--name=post", "--fields=Title:string,Views:int", "--with-soft-delete=false")
+	if code != exitOK {
+		t.Fatalf("scaffold domain exited with %d: %s%s", code, out, errOut)
+	}
+	if !strings.Contains(out, "Created:\n  internal/models/post.go") {
+		t.Errorf("expected the created files, got:\n%s", out)
+	}
+	model, err := os.ReadFile(filepath.Join(dir, "internal", "models", "post.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(model), "Views int") || strings.Contains(string(model), "DeletedAt") {
+		t.Errorf("expected the fields without soft delete, got:\n%s", model)
+	}
+
+	// Scaffolding the domain again conflicts with its files
+	code, out, _ = run("scaffold", "domain", "--name=post", "--fields=Title:string", "--json")
+	var result types.ScaffoldResult
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		t.Fatalf("expected a JSON result, got %q: %v", out, err)
+	}
+	if code != exitFailed || result.Success || len(result.Conflicts) == 0 {
+		t.Errorf("expected a conflict and exit code %d, got %d: %+v", exitFailed, code, result)
+	}
+
+	tests := []struct {
+		name string
+		args []string
+		want string
+	}{
+		{"unknown command", []string{"scaffold", "domian"}, `unknown command "scaffold domian"`},
+		{"missing noun", []string{"scaffold"}, `unknown command "scaffold"`},
+		{"unknown flag", []string{"scaffold", "domain", "--nmae=x"}, "flag provided but not defined: -nmae"},
+		{"bad int", []string{"scaffold", "seed", "--count=many"}, `invalid value "many" for flag -count`},
+		{"bad input file", []string{"scaffold", "domain", "--input=missing.json"}, "reading input"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			code, _, errOut := run(tt.args...)
+			if code != exitUsage || !strings.Contains(errOut, tt.want) {
+				t.Errorf("expected exit code %d and %q, got %d: %s", exitUsage, tt.want, code, errOut)
+			}
+		})
+	}
+}
+
+func TestPrintResult(t *testing.T) {
+	var out bytes.Buffer
+	printResult(&out, types.ScaffoldResult{
+		Success:      true,
+		Message:      "Synced",
+		FilesUpdated: []string{"cmd/web/main.go", "internal/web/post/post.go"},
+		Backups:      map[string]string{"internal/web/post/post.go": ".mcp/backups/1/internal/web/post/post.go"},
+	})
+	want := "Synced\n\nUpdated:\n  cmd/web/main.go\n  internal/web/post/post.go (backup: .mcp/backups/1/internal/web/post/post.go)\n"
+	if out.String() != want {
+		t.Errorf("printResult() =\n%s\nwant:\n%s", out.String(), want)
+	}
+}
+
+func TestInputFlag_Set(t *testing.T) {
+	fieldsType := reflect.TypeFor[[]types.FieldDef]()
+	tests := []struct {
+		name   string
+		typ    reflect.Type
+		values []string
+		want   any
+	}{
+		{"string", reflect.TypeFor[string](), []string{"post"}, "post"},
+		{"bool", reflect.TypeFor[bool](), []string{"false"}, false},
+		{"int", reflect.TypeFor[int](), []string{"25"}, json.Number("25")},
+		{"list", reflect.TypeFor[[]string](), []string{"Status, Name", "Price"}, []any{"Status", "Name", "Price"}},
+		{
+			"list of objects",
+			fieldsType,
+			[]string{"Title:string,Price:float64"},
+			[]any{map[string]any{"name": "Title", "type": "string"}, map[string]any{"name": "Price", "type": "float64"}},
+		},
+		{
+			"JSON array and object",
+			fieldsType,
+			[]string{`[{"name": "Title", "type": "string", "required": true}]`, `{"name": "Body", "type": "string"}`},
+			[]any{map[string]any{"name": "Title", "type": "string", "required": true}, map[string]any{"name": "Body", "type": "string"}},
+		},
+		{"object", reflect.TypeFor[map[string]string](), []string{`{"a": "b"}`}, map[string]any{"a": "b"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f := &inputFlag{key: "field", typ: tt.typ}
+			for _, v := range tt.values {
+				if err := f.Set(v); err != nil {
+					t.Fatalf("Set(%q) error = %v", v, err)
+				}
+			}
+			if !reflect.DeepEqual(f.value, tt.want) {
+				t.Errorf("value = %#v, want %#v", f.value, tt.want)
+			}
+		})
+	}
+}
@@ -1,10 +1,13 @@
-// Package main is the entry point for the MCP scaffolding server.
+// Package main is the entry point for the MCP scaffolding server. With arguments it runs
+// a scaffolding tool from the command line instead, e.g. gomcp scaffold domain --name=post.
 package main
 
 import (
 	"context"
 	"log"
 	"os"
+	"path/filepath"
+	"strconv"
 
 	"github.com/dbb1dev/go-mcp/internal/server"
 	"github.com/dbb1dev/go-mcp/internal/tools"
@@ -22,6 +25,22 @@ func main() {
 		}
 	}
 
+	// Fail scaffolds on warnings instead of leaving partial wiring behind
+	strict, _ := strconv.ParseBool(os.Getenv("MCP_SCAFFOLD_STRICT"))
+
+	// Scaffold interactively, in a full-screen form or with line-based prompts
+	if args := os.Args[1:]; len(args) > 0 && args[0] == "tui" {
+		os.Exit(runTUI(args[1:], workingDir, strict, os.Stdin, os.Stdout, os.Stderr))
+	}
+	if args := os.Args[1:]; len(args) > 0 && args[0] == "wizard" {
+		os.Exit(runWizard(args[1:], workingDir, strict, os.Stdin, os.Stdout, os.Stderr))
+	}
+
+	// Run a tool directly when given a command, for scripts and Makefiles
+	if args := os.Args[1:]; len(args) > 0 && args[0] != "serve" {
+		os.Exit(runCLI(args, workingDir, strict, os.Stdout, os.Stderr))
+	}
+
 	// Create server configuration
 	cfg := &server.Config{
 		WorkingDir: workingDir,
@@ -32,6 +51,9 @@ func main() {
 
 	// Create tool registry and register all tools
 	registry := tools.NewRegistry(workingDir)
+	registry.Strict = strict
+	// Directories the workdir argument of a tool call may select, to manage several projects
+	registry.Roots = filepath.SplitList(os.Getenv("MCP_SCAFFOLD_ROOTS"))
 	registry.RegisterAll(srv)
 
 	// Run the server with stdio transport
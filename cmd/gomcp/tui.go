@@ -0,0 +1,650 @@
+package main
+
+import (
+	"bufio"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/dbb1dev/go-mcp/internal/tools"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+)
+
+// ANSI escape sequences the TUI draws with.
+const (
+	ansiAltScreen  = "\x1b[?1049h\x1b[?25l"
+	ansiMainScreen = "\x1b[?25h\x1b[?1049l"
+	ansiClear      = "\x1b[H\x1b[2J"
+	ansiBold       = "\x1b[1m"
+	ansiReverse    = "\x1b[7m"
+	ansiDim        = "\x1b[2m"
+	ansiReset      = "\x1b[0m"
+)
+
+// tuiHelp lists the keys, at the bottom of the screen.
+const tuiHelp = "↑/↓ move  enter edit/run  ←/→ change  space toggle  d delete  [/] file  pgup/pgdn scroll  q quit"
+
+// tuiScreen is the form the TUI shows.
+type tuiScreen int
+
+const (
+	tuiProjectScreen tuiScreen = iota
+	tuiDomainScreen
+)
+
+// tui is the full-screen form of "gomcp tui": the project or domain being scaffolded on the
+// left, and on the right a live preview of the files a dry run of the tool would write, with
+// the rendered content of the selected one. It keeps no terminal state, so update and view
+// can run without a terminal; runTUI draws it with ANSI escape codes.
+type tui struct {
+	wizard  *wizard // runs the tools and lists the models
+	screen  tuiScreen
+	project types.ScaffoldProjectInput
+	domain  types.ScaffoldDomainInput
+
+	cursor  int
+	editing bool
+	buffer  string
+	commit  func(string) // stores the edited value
+
+	files      []string          // files of the dry run
+	renders    map[string]string // rendered content by file
+	previewErr string
+	file       int // selected file
+	scroll     int // first content line shown
+	status     string
+	quit       bool
+}
+
+// tuiRow is a row of the form. What a key does depends on the handlers it has.
+type tuiRow struct {
+	label  string
+	value  string
+	header bool         // the cursor skips headers
+	edit   func(string) // Enter edits the value as text
+	cycle  func(int)    // ←/→ step through the choices
+	toggle func()       // Space flips it
+	remove func()       // d removes it
+	action func()       // Enter runs it
+}
+
+func (r tuiRow) selectable() bool {
+	return !r.header
+}
+
+// runTUI runs the full-screen interface of "gomcp tui". It returns the exit code.
+func runTUI(args []string, workingDir string, strict bool, stdin *os.File, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gomcp tui", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dir := fs.String("dir", workingDir, "Project directory to scaffold in")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), "Usage: gomcp tui [flags]\n\nScaffolds a project and its domains in a full-screen form, previewing the files a dry run\nwould write as you edit.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+	if info, err := stdin.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		fmt.Fprintln(stderr, "gomcp: tui needs a terminal; use gomcp wizard to answer prompts line by line")
+		return exitUsage
+	}
+
+	restore, err := rawMode(stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "gomcp: %v; use gomcp wizard to answer prompts line by line\n", err)
+		return exitFailed
+	}
+	defer restore()
+	fmt.Fprint(stdout, ansiAltScreen)
+	defer fmt.Fprint(stdout, ansiMainScreen)
+
+	registry := tools.NewRegistry(*dir)
+	registry.Strict = strict
+	t := newTUI(registry)
+	keys := bufio.NewReader(stdin)
+	for !t.quit {
+		width, height := terminalSize(stdin)
+		fmt.Fprint(stdout, ansiClear+strings.Join(t.view(width, height), "\r\n"))
+		key, err := readKey(keys)
+		if err != nil {
+			break
+		}
+		t.update(key)
+	}
+	return exitOK
+}
+
+// rawMode puts the terminal in raw mode with stty, so keys arrive one by one without echo,
+// and returns the function that restores the previous mode.
+func rawMode(tty *os.File) (func(), error) {
+	saved, err := stty(tty, "-g")
+	if err != nil {
+		return nil, fmt.Errorf("reading the terminal mode: %w", err)
+	}
+	if _, err := stty(tty, "raw", "-echo"); err != nil {
+		return nil, fmt.Errorf("setting the terminal to raw mode: %w", err)
+	}
+	return func() { stty(tty, strings.TrimSpace(saved)) }, nil
+}
+
+// terminalSize returns the width and height of the terminal, or 80x24 if stty can't tell.
+func terminalSize(tty *os.File) (int, int) {
+	out, err := stty(tty, "size")
+	if err == nil {
+		if rows, cols, ok := strings.Cut(strings.TrimSpace(out), " "); ok {
+			height, errRows := strconv.Atoi(rows)
+			width, errCols := strconv.Atoi(cols)
+			if errRows == nil && errCols == nil && width > 0 && height > 0 {
+				return width, height
+			}
+		}
+	}
+	return 80, 24
+}
+
+func stty(tty *os.File, args ...string) (string, error) {
+	cmd := exec.Command("stty", args...)
+	cmd.Stdin = tty
+	out, err := cmd.Output()
+	return string(out), err
+}
+
+// readKey reads a key press from a terminal in raw mode: a printable character as itself, or
+// the name of a special key such as "up", "enter" or "ctrl+c".
+func readKey(r *bufio.Reader) (string, error) {
+	c, _, err := r.ReadRune()
+	if err != nil {
+		return "", err
+	}
+	switch c {
+	case '\r', '\n':
+		return "enter", nil
+	case '\t':
+		return "tab", nil
+	case 0x7f, 0x08:
+		return "backspace", nil
+	case 0x03:
+		return "ctrl+c", nil
+	case ' ':
+		return "space", nil
+	case 0x1b:
+		// A lone escape, or the start of an escape sequence sent with it
+		if r.Buffered() == 0 {
+			return "esc", nil
+		}
+		next, _, err := r.ReadRune()
+		if err != nil || (next != '[' && next != 'O') {
+			return "esc", nil
+		}
+		var seq strings.Builder
+		for {
+			b, err := r.ReadByte()
+			if err != nil {
+				return "esc", nil
+			}
+			seq.WriteByte(b)
+			if b >= 0x40 && b <= 0x7e {
+				break
+			}
+		}
+		switch seq.String() {
+		case "A":
+			return "up", nil
+		case "B":
+			return "down", nil
+		case "C":
+			return "right", nil
+		case "D":
+			return "left", nil
+		case "Z":
+			return "shift+tab", nil
+		case "3~":
+			return "delete", nil
+		case "5~":
+			return "pgup", nil
+		case "6~":
+			return "pgdown", nil
+		}
+		return "esc", nil
+	}
+	return string(c), nil
+}
+
+func newTUI(registry *tools.Registry) *tui {
+	t := &tui{wizard: &wizard{registry: registry}, screen: tuiDomainScreen}
+	if _, err := os.Stat(filepath.Join(registry.WorkingDir, "go.mod")); err != nil {
+		name := filepath.Base(registry.WorkingDir)
+		t.screen = tuiProjectScreen
+		t.project = types.ScaffoldProjectInput{
+			ProjectName:  name,
+			ModulePath:   "example.com/" + utils.ToKebabCase(name),
+			ProjectType:  "web",
+			DatabaseType: "sqlite",
+			InCurrentDir: true,
+		}
+		t.status = fmt.Sprintf("No go.mod in %s, so let's scaffold a project there.", registry.WorkingDir)
+	}
+	t.refresh()
+	t.moveCursor(0)
+	return t
+}
+
+// rows lays out the form of the current screen.
+func (t *tui) rows() []tuiRow {
+	if t.screen == tuiProjectScreen {
+		p := &t.project
+		return []tuiRow{
+			{label: "Project", header: true},
+			{label: "Name", value: p.ProjectName, edit: func(s string) { p.ProjectName = s }},
+			{label: "Module path", value: p.ModulePath, edit: func(s string) { p.ModulePath = s }},
+			{label: "Type", value: p.ProjectType, cycle: func(d int) { p.ProjectType = cycle(wizardProjectTypes, p.ProjectType, d) }},
+			{label: "Database", value: p.DatabaseType, cycle: func(d int) { p.DatabaseType = cycle(wizardDatabases, p.DatabaseType, d) }},
+			{label: "Auth", value: yesNo(p.WithAuth), toggle: func() { p.WithAuth = !p.WithAuth }},
+			{label: "", header: true},
+			{label: "[ Create project ]", action: t.createProject},
+		}
+	}
+
+	d := &t.domain
+	rows := []tuiRow{
+		{label: "Domain", header: true},
+		{label: "Name", value: d.DomainName, edit: func(s string) { d.DomainName = utils.ToSnakeCase(s) }},
+		{label: "Fields", header: true},
+	}
+	for i := range d.Fields {
+		f := &d.Fields[i]
+		value := f.Type
+		if f.Required {
+			value += " required"
+		}
+		rows = append(rows, tuiRow{
+			label:  "  " + f.Name,
+			value:  value,
+			edit:   func(s string) { f.Name = utils.ToPascalCase(s) },
+			cycle:  func(n int) { f.Type = cycle(wizardFieldTypes, f.Type, n) },
+			toggle: func() { f.Required = !f.Required },
+			remove: func() { d.Fields = slices.Delete(d.Fields, i, i+1) },
+		})
+	}
+	rows = append(rows,
+		tuiRow{label: "  + Add field", action: func() {
+			t.startEdit("", func(s string) {
+				if s != "" {
+					d.Fields = append(d.Fields, types.FieldDef{Name: utils.ToPascalCase(s), Type: "string"})
+				}
+			})
+		}},
+		tuiRow{label: "Relationships", header: true},
+	)
+	models := t.wizard.models(d.DomainName)
+	for i := range d.Relationships {
+		r := &d.Relationships[i]
+		rows = append(rows, tuiRow{
+			label:  "  " + r.Model,
+			value:  r.Type,
+			edit:   func(s string) { r.Model = utils.ToPascalCase(s) },
+			cycle:  func(n int) { r.Type = cycle(wizardRelationships, r.Type, n) },
+			toggle: func() { r.Model = cycle(models, r.Model, 1) },
+			remove: func() { d.Relationships = slices.Delete(d.Relationships, i, i+1) },
+		})
+	}
+	rows = append(rows,
+		tuiRow{label: "  + Add relationship", action: func() {
+			model := ""
+			if len(models) > 0 {
+				model = models[0]
+			}
+			t.startEdit(model, func(s string) {
+				if s != "" {
+					d.Relationships = append(d.Relationships, types.RelationshipDef{Type: "belongs_to", Model: utils.ToPascalCase(s)})
+				}
+			})
+		}},
+		tuiRow{label: "Options", header: true},
+		tuiRow{label: "Route group", value: orDefault(d.RouteGroup, "public"), cycle: func(n int) {
+			d.RouteGroup = cycle(wizardRouteGroups, orDefault(d.RouteGroup, "public"), n)
+		}},
+		tuiRow{label: "Layout", value: orDefault(d.Layout, "dashboard"), cycle: func(n int) {
+			d.Layout = cycle(wizardLayouts, orDefault(d.Layout, "dashboard"), n)
+		}},
+		tuiRow{label: "Soft delete", value: yesNo(d.WithSoftDelete == nil || *d.WithSoftDelete), toggle: func() {
+			softDelete := d.WithSoftDelete != nil && !*d.WithSoftDelete
+			d.WithSoftDelete = &softDelete
+		}},
+		tuiRow{label: "", header: true},
+		tuiRow{label: "[ Create domain ]", action: t.createDomain},
+		tuiRow{label: "[ New domain ]", action: func() {
+			t.domain = types.ScaffoldDomainInput{}
+			t.status = ""
+		}},
+	)
+	return rows
+}
+
+// update applies a key press to the form.
+func (t *tui) update(key string) {
+	if t.editing {
+		switch key {
+		case "enter":
+			t.editing = false
+			t.commit(strings.TrimSpace(t.buffer))
+			t.refresh()
+		case "esc":
+			t.editing = false
+		case "ctrl+c":
+			t.quit = true
+		case "backspace":
+			if r := []rune(t.buffer); len(r) > 0 {
+				t.buffer = string(r[:len(r)-1])
+			}
+		case "space":
+			t.buffer += " "
+		default:
+			if r := []rune(key); len(r) == 1 && unicode.IsPrint(r[0]) {
+				t.buffer += key
+			}
+		}
+		return
+	}
+
+	rows := t.rows()
+	row := rows[t.cursor]
+	switch key {
+	case "q", "ctrl+c":
+		t.quit = true
+	case "up", "k", "shift+tab":
+		t.moveCursor(-1)
+	case "down", "j", "tab":
+		t.moveCursor(1)
+	case "left", "right":
+		if row.cycle != nil {
+			row.cycle(map[string]int{"left": -1, "right": 1}[key])
+			t.refresh()
+		}
+	case "space":
+		if row.toggle != nil {
+			row.toggle()
+			t.refresh()
+		}
+	case "enter":
+		if row.edit != nil {
+			t.startEdit(row.value, row.edit)
+		} else if row.action != nil {
+			row.action()
+			t.refresh()
+		}
+	case "d", "delete":
+		if row.remove != nil {
+			row.remove()
+			t.refresh()
+			t.moveCursor(0)
+		}
+	case "[", "]":
+		if len(t.files) > 0 {
+			step := map[string]int{"[": -1, "]": 1}[key]
+			t.file = (t.file + step + len(t.files)) % len(t.files)
+			t.scroll = 0
+		}
+	case "pgup":
+		t.scroll = max(0, t.scroll-10)
+	case "pgdown":
+		t.scroll += 10
+	}
+}
+
+// moveCursor moves the cursor by step selectable rows, or onto the nearest selectable row
+// for a step of 0, after the rows changed.
+func (t *tui) moveCursor(step int) {
+	rows := t.rows()
+	t.cursor = min(max(t.cursor, 0), len(rows)-1)
+	if step == 0 {
+		if rows[t.cursor].selectable() {
+			return
+		}
+		step = 1
+	}
+	for i := t.cursor + step; i >= 0 && i < len(rows); i += step {
+		if rows[i].selectable() {
+			t.cursor = i
+			return
+		}
+	}
+	if !rows[t.cursor].selectable() {
+		t.moveCursor(-step)
+	}
+}
+
+func (t *tui) startEdit(value string, commit func(string)) {
+	t.editing = true
+	t.buffer = value
+	t.commit = commit
+}
+
+func (t *tui) createProject() {
+	result := t.wizard.runTool("scaffold_project", t.project)
+	if !result.Success {
+		t.status = "Error: " + result.Message
+		return
+	}
+	t.status = result.Message
+	t.screen = tuiDomainScreen
+	t.cursor = 0
+	t.moveCursor(0)
+}
+
+func (t *tui) createDomain() {
+	result := t.wizard.runTool("scaffold_domain", t.domain)
+	if !result.Success {
+		t.status = "Error: " + result.Message
+		return
+	}
+	t.status = result.Message
+	if len(result.NextSteps) > 0 {
+		t.status += ". Next: " + strings.Join(result.NextSteps, ", ")
+	}
+	t.domain = types.ScaffoldDomainInput{}
+	t.cursor = 0
+	t.moveCursor(0)
+}
+
+// refresh updates the preview with a dry run of the tool and the rendered templates.
+func (t *tui) refresh() {
+	selected := ""
+	if t.file < len(t.files) {
+		selected = t.files[t.file]
+	}
+	t.files, t.renders, t.previewErr = nil, map[string]string{}, ""
+
+	if t.screen == tuiProjectScreen {
+		input := t.project
+		input.DryRun = true
+		result := t.wizard.runTool("scaffold_project", input)
+		if !result.Success {
+			t.previewErr = result.Message
+			return
+		}
+		t.files = result.FilesCreated
+	} else {
+		if t.domain.DomainName == "" || len(t.domain.Fields) == 0 {
+			t.previewErr = "Name the domain and add a field to preview its files."
+			return
+		}
+		input := t.domain
+		input.DryRun = true
+		result := t.wizard.runTool("scaffold_domain", input)
+		if !result.Success {
+			t.previewErr = result.Message
+			return
+		}
+		t.files = slices.Concat(result.FilesCreated, result.FilesUpdated)
+		for _, template := range []string{"domain", "views"} {
+			preview, err := t.wizard.registry.RenderPreview(types.RenderPreviewInput{Template: template, Domain: &input})
+			if err != nil || !preview.Success {
+				continue
+			}
+			for _, render := range preview.Renders {
+				if render.Path != "" {
+					t.renders[filepath.ToSlash(render.Path)] = render.Output
+				}
+			}
+		}
+	}
+
+	// Keep the selected file across changes
+	if t.file = slices.Index(t.files, selected); t.file < 0 {
+		t.file, t.scroll = 0, 0
+	}
+}
+
+// view draws the screen as lines of at most width columns.
+func (t *tui) view(width, height int) []string {
+	width, height = max(width, 40), max(height, 10)
+	left := min(48, max(32, width/3))
+	right := width - left - 3
+
+	title := "gomcp tui  " + t.wizard.registry.WorkingDir
+	lines := []string{ansiReverse + fit(" "+title, width) + ansiReset}
+
+	form := t.formLines(left)
+	preview := t.previewLines(right, height-3)
+	for i := 0; i < height-3; i++ {
+		l, r := strings.Repeat(" ", left), ""
+		if i < len(form) {
+			l = form[i]
+		}
+		if i < len(preview) {
+			r = preview[i]
+		}
+		lines = append(lines, l+" "+ansiDim+"│"+ansiReset+" "+r)
+	}
+
+	lines = append(lines, fit(t.status, width), ansiDim+fit(tuiHelp, width)+ansiReset)
+	return lines
+}
+
+func (t *tui) formLines(width int) []string {
+	var lines []string
+	for i, row := range t.rows() {
+		value := row.value
+		if i == t.cursor && t.editing {
+			value = t.buffer + "▏"
+		}
+		switch {
+		case row.header:
+			lines = append(lines, ansiBold+fit(row.label, width)+ansiReset)
+		case i == t.cursor:
+			lines = append(lines, ansiReverse+fit(fmt.Sprintf("%-15s %s", row.label, value), width)+ansiReset)
+		default:
+			lines = append(lines, fit(fmt.Sprintf("%-15s %s", row.label, value), width))
+		}
+	}
+	return lines
+}
+
+func (t *tui) previewLines(width, height int) []string {
+	if t.previewErr != "" {
+		lines := []string{ansiBold + fit("Preview", width) + ansiReset}
+		for _, line := range wrap(t.previewErr, width) {
+			lines = append(lines, fit(line, width))
+		}
+		return lines
+	}
+
+	header := fmt.Sprintf("Preview: %d file(s)", len(t.files))
+	if t.screen == tuiProjectScreen {
+		header = fmt.Sprintf("Preview: would create %d file(s)", len(t.files))
+	}
+	lines := []string{ansiBold + fit(header, width) + ansiReset}
+
+	// The file list, scrolled to show the selected file
+	listHeight := min(len(t.files), max(3, height/3))
+	first := min(max(0, t.file-listHeight/2), len(t.files)-listHeight)
+	for i := first; i < first+listHeight; i++ {
+		line := fit("  "+t.files[i], width)
+		if i == t.file {
+			line = ansiReverse + fit("> "+t.files[i], width) + ansiReset
+		}
+		lines = append(lines, line)
+	}
+	if len(t.files) == 0 {
+		return lines
+	}
+
+	lines = append(lines, ansiDim+strings.Repeat("─", width)+ansiReset)
+	content, ok := t.renders[t.files[t.file]]
+	if !ok {
+		return append(lines, fit("(no template preview for this file)", width))
+	}
+	contentLines := strings.Split(strings.ReplaceAll(content, "\t", "    "), "\n")
+	t.scroll = min(t.scroll, max(0, len(contentLines)-1))
+	for _, line := range contentLines[t.scroll:] {
+		if len(lines) >= height {
+			break
+		}
+		lines = append(lines, fit(line, width))
+	}
+	return lines
+}
+
+// cycle steps from current through choices, starting at the first choice when current isn't one.
+func cycle(choices []string, current string, step int) string {
+	if len(choices) == 0 {
+		return current
+	}
+	i := slices.Index(choices, current)
+	if i < 0 {
+		return choices[0]
+	}
+	return choices[(i+step+len(choices))%len(choices)]
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// fit pads or truncates s to width columns.
+func fit(s string, width int) string {
+	r := []rune(s)
+	if len(r) > width {
+		if width < 1 {
+			return ""
+		}
+		return string(r[:width-1]) + "…"
+	}
+	return s + strings.Repeat(" ", width-len(r))
+}
+
+// wrap splits s into lines of at most width columns at spaces.
+func wrap(s string, width int) []string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		if line != "" && len([]rune(line))+1+len([]rune(word)) > width {
+			lines = append(lines, line)
+			line = ""
+		}
+		if line != "" {
+			line += " "
+		}
+		line += word
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return lines
+}
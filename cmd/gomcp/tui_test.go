@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/tools"
+)
+
+// press sends key names to the TUI, typing any other string one character at a time.
+func press(tu *tui, keys ...string) {
+	for _, key := range keys {
+		switch key {
+		case "up", "down", "left", "right", "enter", "esc", "space", "backspace", "d", "[", "]":
+			tu.update(key)
+		default:
+			for _, r := range key {
+				tu.update(string(r))
+			}
+		}
+	}
+}
+
+// moveTo moves the cursor down to the row with the label.
+func moveTo(t *testing.T, tu *tui, label string) {
+	t.Helper()
+	for range tu.rows() {
+		if strings.TrimSpace(tu.rows()[tu.cursor].label) == label {
+			return
+		}
+		tu.update("down")
+	}
+	t.Fatalf("no row %q below the cursor", label)
+}
+
+func TestTUI(t *testing.T) {
+	dir := t.TempDir()
+	tu := newTUI(tools.NewRegistry(dir))
+	if tu.screen != tuiProjectScreen {
+		t.Fatal("expected the project form without a go.mod")
+	}
+
+	// Project: name it and create it
+	press(tu, "enter")
+	for range tu.buffer {
+		press(tu, "backspace")
+	}
+	press(tu, "blog", "enter", "down", "enter")
+	for range tu.buffer {
+		press(tu, "backspace")
+	}
+	press(tu, "example.com/blog", "enter")
+	if !strings.Contains(strings.Join(tu.files, "\n"), "go.mod") {
+		t.Errorf("expected the preview to list go.mod, got %v (%s)", tu.files, tu.previewErr)
+	}
+	moveTo(t, tu, "[ Create project ]")
+	press(tu, "enter")
+	if tu.screen != tuiDomainScreen {
+		t.Fatalf("expected the domain form after creating the project: %s", tu.status)
+	}
+	goMod, err := os.ReadFile(filepath.Join(dir, "go.mod"))
+	if err != nil || !strings.Contains(string(goMod), "module example.com/blog") {
+		t.Fatalf("expected go.mod for example.com/blog, got %s (%v)", goMod, err)
+	}
+
+	// Domain "author" with a required Name field
+	press(tu, "enter", "author", "enter", "down", "enter", "name", "enter", "space")
+	if got := tu.rows()[tu.cursor]; got.label != "  Name" || got.value != "string required" {
+		t.Errorf("expected the cursor on the required Name field, got %q %q", got.label, got.value)
+	}
+	if tu.files[0] != "internal/models/author.go" || !strings.Contains(tu.renders["internal/models/author.go"], "Name string") {
+		t.Errorf("expected the preview of the author model, got %v: %s", tu.files, tu.renders["internal/models/author.go"])
+	}
+	moveTo(t, tu, "[ Create domain ]")
+	press(tu, "enter")
+	if !strings.Contains(tu.status, "author") || strings.HasPrefix(tu.status, "Error") {
+		t.Fatalf("expected the author domain to be created, got %s", tu.status)
+	}
+
+	// Domain "post": a field, a removed field and a belongs_to the author picked from the models
+	press(tu, "enter", "post", "enter", "down", "enter", "title", "enter", "right", "left")
+	press(tu, "down", "enter", "views", "enter", "d")
+	moveTo(t, tu, "+ Add relationship")
+	press(tu, "enter")
+	if tu.buffer != "Author" {
+		t.Errorf("expected the relationship to start with the Author model, got %q", tu.buffer)
+	}
+	press(tu, "enter")
+	if model := tu.renders["internal/models/post.go"]; !strings.Contains(model, "AuthorID") || strings.Contains(model, "Views") {
+		t.Errorf("expected the preview of the post model with the author and without Views, got:\n%s", model)
+	}
+
+	// The preview pane shows the selected file, and the screen fits the terminal
+	press(tu, "]")
+	screen := tu.view(120, 40)
+	if len(screen) != 40 {
+		t.Errorf("expected 40 lines, got %d", len(screen))
+	}
+	ansi := regexp.MustCompile(`\x1b\[[0-9;?]*[a-zA-Z]`)
+	for i, line := range screen {
+		if n := len([]rune(ansi.ReplaceAllString(line, ""))); n > 120 {
+			t.Errorf("line %d is %d columns wide", i, n)
+		}
+	}
+	if !strings.Contains(strings.Join(screen, "\n"), "> "+tu.files[1]) {
+		t.Errorf("expected the second file to be selected, got:\n%s", strings.Join(screen, "\n"))
+	}
+
+	moveTo(t, tu, "[ Create domain ]")
+	press(tu, "enter")
+	model, err := os.ReadFile(filepath.Join(dir, "internal", "models", "post.go"))
+	if err != nil {
+		t.Fatalf("expected the post model: %v (%s)", err, tu.status)
+	}
+	if !strings.Contains(string(model), "Title string") || !strings.Contains(string(model), "AuthorID") || strings.Contains(string(model), "Views") {
+		t.Errorf("expected Title and the author relationship without Views, got:\n%s", model)
+	}
+
+	press(tu, "q")
+	if !tu.quit {
+		t.Error("expected q to quit")
+	}
+}
+
+func TestReadKey(t *testing.T) {
+	keys := bufio.NewReader(strings.NewReader("a\r\x1b[A\x1b[B\x1b[Z\x1b[6~\x7f \x03"))
+	var got []string
+	for {
+		key, err := readKey(keys)
+		if err != nil {
+			break
+		}
+		got = append(got, key)
+	}
+	want := "a enter up down shift+tab pgdown backspace space ctrl+c"
+	if strings.Join(got, " ") != want {
+		t.Errorf("expected %s, got %s", want, strings.Join(got, " "))
+	}
+}
+
+func TestRunTUI_NotATerminal(t *testing.T) {
+	stdin, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer stdin.Close()
+	defer w.Close()
+	var stdout, stderr strings.Builder
+	if code := runTUI(nil, t.TempDir(), false, stdin, &stdout, &stderr); code != exitUsage || !strings.Contains(stderr.String(), "gomcp wizard") {
+		t.Errorf("expected exit code %d pointing to gomcp wizard, got %d: %s", exitUsage, code, stderr.String())
+	}
+}
@@ -0,0 +1,381 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/tools"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+)
+
+// errQuit stops the wizard when the user quits or the input ends.
+var errQuit = errors.New("quit")
+
+// Choices of the wizard's pickers. Any other value can still be typed in.
+var (
+	wizardFieldTypes    = []string{"string", "int", "int64", "uint", "float64", "bool", "time.Time", "*time.Time", "*string", "[]string"}
+	wizardRelationships = []string{"belongs_to", "has_one", "has_many", "many_to_many"}
+	wizardDatabases     = []string{"sqlite", "postgres", "mysql"}
+	wizardProjectTypes  = []string{"web", "api"}
+	wizardRouteGroups   = []string{"public", "authenticated", "admin"}
+	wizardLayouts       = []string{"dashboard", "base", "auth", "none"}
+)
+
+// wizard walks through scaffolding a project and its domains with line-based prompts: it reads
+// one answer per line, so it works over plain SSH sessions and piped input. Each change to a
+// domain is previewed with a dry run of the tool, so mistakes show before any file is written.
+type wizard struct {
+	registry *tools.Registry
+	in       *bufio.Scanner
+	out      io.Writer
+}
+
+// runWizard runs the prompt-driven wizard of "gomcp wizard". It returns the exit code.
+func runWizard(args []string, workingDir string, strict bool, stdin io.Reader, stdout, stderr io.Writer) int {
+	fs := flag.NewFlagSet("gomcp wizard", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	dir := fs.String("dir", workingDir, "Project directory to scaffold in")
+	fs.Usage = func() {
+		fmt.Fprint(fs.Output(), "Usage: gomcp wizard [flags]\n\nScaffolds a project and its domains by asking questions, one answer per line, and previews\neach domain with a dry run.\n\nFlags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitUsage
+	}
+
+	registry := tools.NewRegistry(*dir)
+	registry.Strict = strict
+	w := &wizard{registry: registry, in: bufio.NewScanner(stdin), out: stdout}
+
+	if err := w.run(); err != nil {
+		if errors.Is(err, errQuit) {
+			fmt.Fprintln(stdout, "Bye.")
+			return exitOK
+		}
+		fmt.Fprintf(stderr, "gomcp: %v\n", err)
+		return exitFailed
+	}
+	return exitOK
+}
+
+func (w *wizard) run() error {
+	if _, err := os.Stat(filepath.Join(w.registry.WorkingDir, "go.mod")); err != nil {
+		if err := w.project(); err != nil {
+			return err
+		}
+	}
+	for {
+		name, err := w.ask("\nDomain name (singular, e.g. product; empty to finish)", "")
+		if err != nil || name == "" {
+			return err
+		}
+		if err := w.domain(name); err != nil {
+			return err
+		}
+	}
+}
+
+// project asks for the settings of a new project in the working directory and scaffolds it.
+func (w *wizard) project() error {
+	fmt.Fprintf(w.out, "No go.mod in %s, so let's scaffold a project there.\n\n", w.registry.WorkingDir)
+	name, err := w.ask("Project name", filepath.Base(w.registry.WorkingDir))
+	if err != nil {
+		return err
+	}
+	input := types.ScaffoldProjectInput{ProjectName: name, InCurrentDir: true}
+	if input.ModulePath, err = w.ask("Module path", "example.com/"+utils.ToKebabCase(name)); err != nil {
+		return err
+	}
+	if input.ProjectType, err = w.choose("Project type", wizardProjectTypes, "web"); err != nil {
+		return err
+	}
+	if input.DatabaseType, err = w.choose("Database", wizardDatabases, "sqlite"); err != nil {
+		return err
+	}
+	if input.WithAuth, err = w.confirm("With authentication", false); err != nil {
+		return err
+	}
+
+	input.DryRun = true
+	preview := w.runTool("scaffold_project", input)
+	if !preview.Success {
+		return fmt.Errorf("scaffolding the project: %s", preview.Message)
+	}
+	fmt.Fprintf(w.out, "\n%s\n", preview.Message)
+	ok, err := w.confirm("Create the project", true)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return errQuit
+	}
+	input.DryRun = false
+	result := w.runTool("scaffold_project", input)
+	printResult(w.out, result)
+	if !result.Success {
+		return fmt.Errorf("scaffolding the project failed")
+	}
+	return nil
+}
+
+// domain edits a domain until it is created or discarded, showing a preview after each change.
+func (w *wizard) domain(name string) error {
+	input := types.ScaffoldDomainInput{DomainName: name}
+	for {
+		w.preview(input)
+		action, err := w.choose("Next", []string{"add field", "add relationship", "remove", "options", "create", "discard"}, "add field")
+		if err != nil {
+			return err
+		}
+		switch action {
+		case "add field":
+			err = w.addField(&input)
+		case "add relationship":
+			err = w.addRelationship(&input)
+		case "remove":
+			err = w.remove(&input)
+		case "options":
+			err = w.options(&input)
+		case "create":
+			result := w.runTool("scaffold_domain", input)
+			fmt.Fprintln(w.out)
+			printResult(w.out, result)
+			if result.Success {
+				return nil
+			}
+		case "discard":
+			return nil
+		default:
+			fmt.Fprintf(w.out, "Unknown choice %q\n", action)
+		}
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (w *wizard) addField(input *types.ScaffoldDomainInput) error {
+	name, err := w.ask("Field name", "")
+	if err != nil || name == "" {
+		return err
+	}
+	field := types.FieldDef{Name: utils.ToPascalCase(name)}
+	if field.Type, err = w.choose("Type", wizardFieldTypes, "string"); err != nil {
+		return err
+	}
+	if field.Required, err = w.confirm("Required", false); err != nil {
+		return err
+	}
+	input.Fields = append(input.Fields, field)
+	return nil
+}
+
+func (w *wizard) addRelationship(input *types.ScaffoldDomainInput) error {
+	rel := types.RelationshipDef{}
+	var err error
+	if rel.Type, err = w.choose("Relationship", wizardRelationships, "belongs_to"); err != nil {
+		return err
+	}
+	models := w.models(input.DomainName)
+	if len(models) == 0 {
+		rel.Model, err = w.ask("Related model (e.g. User)", "")
+	} else {
+		rel.Model, err = w.choose("Related model", models, models[0])
+	}
+	if err != nil || rel.Model == "" {
+		return err
+	}
+	rel.Model = utils.ToPascalCase(rel.Model)
+	input.Relationships = append(input.Relationships, rel)
+	return nil
+}
+
+// models lists the models of the scaffolded domains other than the one being edited.
+func (w *wizard) models(domain string) []string {
+	result, err := w.registry.ListDomains()
+	if err != nil {
+		return nil
+	}
+	var models []string
+	for _, d := range result.Domains {
+		if d.HasModel && d.Name != domain {
+			models = append(models, utils.ToModelName(d.Name))
+		}
+	}
+	return models
+}
+
+func (w *wizard) remove(input *types.ScaffoldDomainInput) error {
+	var items []string
+	for _, f := range input.Fields {
+		items = append(items, "field "+f.Name)
+	}
+	for _, r := range input.Relationships {
+		items = append(items, r.Type+" "+r.Model)
+	}
+	if len(items) == 0 {
+		fmt.Fprintln(w.out, "Nothing to remove.")
+		return nil
+	}
+	item, err := w.choose("Remove", items, "")
+	if err != nil || item == "" {
+		return err
+	}
+	for i, it := range items {
+		if it != item {
+			continue
+		}
+		if i < len(input.Fields) {
+			input.Fields = append(input.Fields[:i], input.Fields[i+1:]...)
+		} else {
+			i -= len(input.Fields)
+			input.Relationships = append(input.Relationships[:i], input.Relationships[i+1:]...)
+		}
+		return nil
+	}
+	fmt.Fprintf(w.out, "No %q to remove.\n", item)
+	return nil
+}
+
+func (w *wizard) options(input *types.ScaffoldDomainInput) error {
+	var err error
+	if input.RouteGroup, err = w.choose("Route group", wizardRouteGroups, orDefault(input.RouteGroup, "public")); err != nil {
+		return err
+	}
+	if input.Layout, err = w.choose("Layout", wizardLayouts, orDefault(input.Layout, "dashboard")); err != nil {
+		return err
+	}
+	softDelete, err := w.confirm("Soft delete", input.WithSoftDelete == nil || *input.WithSoftDelete)
+	if err != nil {
+		return err
+	}
+	input.WithSoftDelete = &softDelete
+	return nil
+}
+
+// preview shows the model as it stands and the files a dry run of scaffold_domain would create.
+func (w *wizard) preview(input types.ScaffoldDomainInput) {
+	fmt.Fprintf(w.out, "\n── %s ──\ntype %s struct {\n", input.DomainName, utils.ToModelName(input.DomainName))
+	for _, f := range input.Fields {
+		required := ""
+		if f.Required {
+			required = " // required"
+		}
+		fmt.Fprintf(w.out, "\t%s %s%s\n", f.Name, f.Type, required)
+	}
+	for _, r := range input.Relationships {
+		if r.Type == "has_many" || r.Type == "many_to_many" {
+			fmt.Fprintf(w.out, "\t%s []%s // %s\n", utils.Pluralize(r.Model), r.Model, r.Type)
+		} else {
+			fmt.Fprintf(w.out, "\t%s %s // %s\n", r.Model, r.Model, r.Type)
+		}
+	}
+	fmt.Fprintln(w.out, "}")
+	fmt.Fprintf(w.out, "route group: %s, layout: %s, soft delete: %t\n",
+		orDefault(input.RouteGroup, "public"), orDefault(input.Layout, "dashboard"), input.WithSoftDelete == nil || *input.WithSoftDelete)
+
+	if len(input.Fields) == 0 {
+		fmt.Fprintln(w.out, "Add a field to preview the files.")
+		return
+	}
+	input.DryRun = true
+	result := w.runTool("scaffold_domain", input)
+	if !result.Success {
+		fmt.Fprintf(w.out, "Error: %s\n", result.Message)
+		return
+	}
+	fmt.Fprintf(w.out, "Would create %d files:\n", len(result.FilesCreated))
+	for _, file := range result.FilesCreated {
+		fmt.Fprintf(w.out, "  %s\n", file)
+	}
+}
+
+// runTool runs a tool with its input, reporting errors in the result.
+func (w *wizard) runTool(tool string, input any) types.ScaffoldResult {
+	data, err := json.Marshal(input)
+	if err == nil {
+		var result types.ScaffoldResult
+		if result, err = w.registry.Run(tool, data); err == nil {
+			return result
+		}
+	}
+	return types.NewErrorResult(err.Error())
+}
+
+// ask reads a line of input, returning def for an empty line.
+func (w *wizard) ask(prompt, def string) (string, error) {
+	if def != "" {
+		fmt.Fprintf(w.out, "%s [%s]: ", prompt, def)
+	} else {
+		fmt.Fprintf(w.out, "%s: ", prompt)
+	}
+	if !w.in.Scan() {
+		fmt.Fprintln(w.out)
+		if err := w.in.Err(); err != nil {
+			return "", err
+		}
+		return "", errQuit
+	}
+	answer := strings.TrimSpace(w.in.Text())
+	if answer == "" {
+		return def, nil
+	}
+	return answer, nil
+}
+
+// choose picks one of the choices by number or by value. Values not in the list are
+// returned as typed, so the tool's validation can judge them.
+func (w *wizard) choose(prompt string, choices []string, def string) (string, error) {
+	for i, c := range choices {
+		fmt.Fprintf(w.out, "  %d) %s\n", i+1, c)
+	}
+	answer, err := w.ask(prompt, def)
+	if err != nil {
+		return "", err
+	}
+	if n, err := strconv.Atoi(answer); err == nil && n >= 1 && n <= len(choices) {
+		return choices[n-1], nil
+	}
+	return answer, nil
+}
+
+// confirm asks a yes or no question.
+func (w *wizard) confirm(prompt string, def bool) (bool, error) {
+	hint := "y/N"
+	if def {
+		hint = "Y/n"
+	}
+	for {
+		answer, err := w.ask(prompt+" ("+hint+")", "")
+		if err != nil {
+			return false, err
+		}
+		switch strings.ToLower(answer) {
+		case "":
+			return def, nil
+		case "y", "yes":
+			return true, nil
+		case "n", "no":
+			return false, nil
+		}
+	}
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestRunWizard(t *testing.T) {
+	dir := t.TempDir()
+	answers := []string{
+		// Project: name, module path, project type, database, auth, create
+		"blog", "example.com/blog", "", "1", "n", "y",
+		// Domain "author" with a required Name field
+		"author", "1", "name", "", "y", "5",
+		// Domain "post": a field, a belongs_to the author picked from the list, and a removed field
+		"post", "1", "title", "", "", "1", "views", "2", "", "3", "2", "2", "1", "1", "5",
+		// Finish
+		"",
+	}
+	var stdout, stderr bytes.Buffer
+	code := runWizard([]string{"--dir=" + dir}, "", false, strings.NewReader(strings.Join(answers, "\n")+"\n"), &stdout, &stderr)
+	if code != exitOK {
+		t.Fatalf("runWizard exited with %d: %s\n%s", code, stderr.String(), stdout.String())
+	}
+
+	out := stdout.String()
+	for _, want := range []string{
+		"Dry run: Would create project 'blog'",
+		"Successfully created domain 'author'",
+		"\tViews int\n",
+		"  1) Author\n",
+		"\tAuthor Author // belongs_to\n",
+		"Would create 11 files:\n  internal/models/post.go",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected the output to contain %q, got:\n%s", want, out)
+		}
+	}
+
+	model, err := os.ReadFile(filepath.Join(dir, "internal", "models", "post.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(model), "Title string") || !strings.Contains(string(model), "AuthorID") || strings.Contains(string(model), "Views") {
+		t.Errorf("expected Title and the author relationship without Views, got:\n%s", model)
+	}
+}
+
+func TestRunWizard_EndOfInput(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	code := runWizard([]string{"--dir=" + t.TempDir()}, "", false, strings.NewReader("blog\n"), &stdout, &stderr)
+	if code != exitOK || !strings.HasSuffix(stdout.String(), "Bye.\n") {
+		t.Errorf("expected the wizard to quit with exit code %d, got %d: %s%s", exitOK, code, stdout.String(), stderr.String())
+	}
+}
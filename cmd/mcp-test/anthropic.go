@@ -0,0 +1,111 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/anthropics/anthropic-sdk-go"
+	"github.com/anthropics/anthropic-sdk-go/option"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultAnthropicModel = string(anthropic.ModelClaudeSonnet4_5_20250929)
+
+// anthropicProvider talks to Claude with the Anthropic SDK.
+type anthropicProvider struct {
+	client    anthropic.Client
+	model     string
+	maxTokens int
+	system    string
+	tools     []anthropic.ToolUnionParam
+	messages  []anthropic.MessageParam
+}
+
+func newAnthropicProvider(opts providerOptions, tools []*mcp.Tool) (provider, error) {
+	return &anthropicProvider{
+		client:    anthropic.NewClient(option.WithAPIKey(opts.apiKey), option.WithMaxRetries(*retries)),
+		model:     opts.model,
+		maxTokens: opts.maxTokens,
+		system:    opts.system,
+		tools:     convertToAnthropicTools(tools),
+		messages:  []anthropic.MessageParam{anthropic.NewUserMessage(anthropic.NewTextBlock(opts.task))},
+	}, nil
+}
+
+func (p *anthropicProvider) Name() string { return "Claude" }
+
+func (p *anthropicProvider) Send(ctx context.Context) (reply, error) {
+	response, err := p.client.Messages.New(ctx, anthropic.MessageNewParams{
+		Model:     anthropic.Model(p.model),
+		MaxTokens: int64(p.maxTokens),
+		System:    []anthropic.TextBlockParam{{Text: p.system}},
+		Messages:  p.messages,
+		Tools:     p.tools,
+	})
+	if err != nil {
+		return reply{}, err
+	}
+
+	// Add assistant response to messages
+	p.messages = append(p.messages, response.ToParam())
+
+	var r reply
+	var text strings.Builder
+	for _, block := range response.Content {
+		switch b := block.AsAny().(type) {
+		case anthropic.TextBlock:
+			text.WriteString(b.Text)
+		case anthropic.ToolUseBlock:
+			var input map[string]any
+			if err := json.Unmarshal(b.Input, &input); err != nil {
+				return reply{}, fmt.Errorf("parsing input of %s: %w", b.Name, err)
+			}
+			r.ToolCalls = append(r.ToolCalls, toolCall{ID: b.ID, Name: b.Name, Input: input})
+		}
+	}
+	r.Text = text.String()
+	return r, nil
+}
+
+func (p *anthropicProvider) AddToolResults(results []toolResult) {
+	var blocks []anthropic.ContentBlockParamUnion
+	for _, result := range results {
+		blocks = append(blocks, anthropic.NewToolResultBlock(result.Call.ID, result.Output, result.IsError))
+	}
+	p.messages = append(p.messages, anthropic.NewUserMessage(blocks...))
+}
+
+func convertToAnthropicTools(mcpTools []*mcp.Tool) []anthropic.ToolUnionParam {
+	var tools []anthropic.ToolUnionParam
+	for _, t := range mcpTools {
+		// Convert MCP InputSchema to JSON
+		schemaJSON, err := json.Marshal(t.InputSchema)
+		if err != nil {
+			log.Printf("Warning: skipping tool %s due to schema error: %v", t.Name, err)
+			continue
+		}
+
+		// Parse as raw JSON for Anthropic
+		var schema anthropic.ToolInputSchemaParam
+		if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+			log.Printf("Warning: skipping tool %s due to schema parse error: %v", t.Name, err)
+			continue
+		}
+
+		description := t.Description
+		tool := anthropic.ToolParam{
+			Name:        t.Name,
+			Description: anthropic.String(description),
+			InputSchema: schema,
+		}
+		tools = append(tools, anthropic.ToolUnionParam{OfTool: &tool})
+	}
+	return tools
+}
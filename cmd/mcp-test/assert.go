@@ -0,0 +1,265 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// routesResourceURI is the resource of the MCP server that lists the scaffolded routes.
+const routesResourceURI = "scaffold://routes"
+
+// routeMethods are the HTTP methods of the routes assertion.
+var routeMethods = map[string]bool{"GET": true, "POST": true, "PUT": true, "PATCH": true, "DELETE": true}
+
+// compile checks the regular expressions and routes of an expectation when its scenario is
+// loaded, so a typo fails before any tool runs.
+func (e *expectation) compile() error {
+	for file, patterns := range e.FileMatches {
+		for _, pattern := range patterns {
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("file_matches of %s: %w", file, err)
+			}
+		}
+	}
+	for _, route := range e.Routes {
+		if _, _, err := parseRoute(route); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseRoute splits a route of the routes assertion into its method, "" for any, and path.
+func parseRoute(route string) (method, path string, err error) {
+	fields := strings.Fields(route)
+	switch {
+	case len(fields) == 1 && strings.HasPrefix(fields[0], "/"):
+		return "", fields[0], nil
+	case len(fields) == 2 && routeMethods[strings.ToUpper(fields[0])] && strings.HasPrefix(fields[1], "/"):
+		return strings.ToUpper(fields[0]), fields[1], nil
+	}
+	return "", "", fmt.Errorf(`invalid route %q, want "METHOD /path" or "/path"`, route)
+}
+
+// checkResult checks the result of a tool call against the assertions of its step.
+func checkResult(expect expectation, output string, callErr error) error {
+	succeeded, message := callErr == nil, ""
+	if callErr != nil {
+		message = callErr.Error()
+	}
+	// Scaffolding tools report failures in their result rather than as tool errors
+	var result struct {
+		Success *bool  `json:"success"`
+		Message string `json:"message"`
+	}
+	if json.Unmarshal([]byte(output), &result) == nil {
+		if result.Success != nil && !*result.Success {
+			succeeded = false
+		}
+		if result.Message != "" {
+			message = result.Message
+		}
+	}
+
+	wantSuccess := expect.Success == nil || *expect.Success
+	switch {
+	case wantSuccess && !succeeded:
+		return fmt.Errorf("expected success, got failure: %s", message)
+	case !wantSuccess && succeeded:
+		return fmt.Errorf("expected failure, got success: %s", message)
+	}
+
+	for _, want := range expect.Contains {
+		if !strings.Contains(output, want) {
+			return fmt.Errorf("expected output to contain %q, got: %s", want, truncate(output, 500))
+		}
+	}
+	return nil
+}
+
+// checkTree checks the working directory of a job against the assertions of an expectation.
+func checkTree(ctx context.Context, j *job, session *mcp.ClientSession, expect expectation) error {
+	for _, file := range expect.Files {
+		if _, err := os.Stat(filepath.Join(j.workdir, file)); err != nil {
+			return fmt.Errorf("expected %s to exist%s", file, siblings(j.workdir, file))
+		}
+	}
+
+	for _, file := range sortedKeys(expect.FileContains) {
+		content, err := readExpectedFile(j.workdir, file)
+		if err != nil {
+			return err
+		}
+		for _, want := range expect.FileContains[file] {
+			if !strings.Contains(content, want) {
+				return fmt.Errorf("expected %s to contain %q%s", file, want, closestLine(content, want))
+			}
+		}
+	}
+
+	for _, file := range sortedKeys(expect.FileMatches) {
+		content, err := readExpectedFile(j.workdir, file)
+		if err != nil {
+			return err
+		}
+		for _, pattern := range expect.FileMatches[file] {
+			if !regexp.MustCompile(pattern).MatchString(content) {
+				return fmt.Errorf("expected %s to match `%s`%s", file, pattern, closestLine(content, regexpSyntax.ReplaceAllString(pattern, " ")))
+			}
+		}
+	}
+
+	if len(expect.Routes) > 0 {
+		if err := checkRoutes(ctx, session, expect.Routes); err != nil {
+			return err
+		}
+	}
+
+	if expect.GoVet {
+		if err := goVet(ctx, j); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRoutes checks that the routes are registered in cmd/web/main.go, using the routes
+// the MCP server finds in the project.
+func checkRoutes(ctx context.Context, session *mcp.ClientSession, want []string) error {
+	result, err := session.ReadResource(ctx, &mcp.ReadResourceParams{URI: routesResourceURI})
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", routesResourceURI, err)
+	}
+	var routes []struct {
+		Method     string `json:"method"`
+		Path       string `json:"path"`
+		Handler    string `json:"handler"`
+		RouteGroup string `json:"route_group"`
+	}
+	if len(result.Contents) > 0 {
+		if err := json.Unmarshal([]byte(result.Contents[0].Text), &routes); err != nil {
+			return fmt.Errorf("decoding %s: %w", routesResourceURI, err)
+		}
+	}
+
+	for _, route := range want {
+		method, path, _ := parseRoute(route)
+		found := false
+		for _, r := range routes {
+			// Controllers that aren't mounted in main.go have no route group
+			if r.RouteGroup != "" && r.Path == path && (method == "" || r.Method == method) {
+				found = true
+				break
+			}
+		}
+		if found {
+			continue
+		}
+
+		registered := make([]string, 0, len(routes))
+		for _, r := range routes {
+			if r.RouteGroup != "" {
+				registered = append(registered, fmt.Sprintf("  %s %s (%s)", r.Method, r.Path, r.Handler))
+			}
+		}
+		if len(registered) == 0 {
+			return fmt.Errorf("expected route %s to be registered in cmd/web/main.go, which registers no scaffolded routes", route)
+		}
+		return fmt.Errorf("expected route %s to be registered in cmd/web/main.go, which registers:\n%s", route, strings.Join(registered, "\n"))
+	}
+	return nil
+}
+
+// goVet tidies the module in the job's working directory and vets it.
+func goVet(ctx context.Context, j *job) error {
+	for _, args := range [][]string{{"mod", "tidy"}, {"vet", "./..."}} {
+		cmd := exec.CommandContext(ctx, "go", args...)
+		cmd.Dir = j.workdir
+		if output, err := cmd.CombinedOutput(); err != nil {
+			return fmt.Errorf("expected go %s to pass: %w\n%s", strings.Join(args, " "), err, strings.TrimSpace(string(output)))
+		}
+	}
+	j.log.Printf("go vet ./...: OK")
+	return nil
+}
+
+// readExpectedFile reads a file of an assertion.
+func readExpectedFile(workdir, file string) (string, error) {
+	content, err := os.ReadFile(filepath.Join(workdir, file))
+	if err != nil {
+		return "", fmt.Errorf("expected %s to exist%s", file, siblings(workdir, file))
+	}
+	return string(content), nil
+}
+
+// siblings describes the files next to a missing file, which often reveal a misnamed one.
+func siblings(workdir, file string) string {
+	dir := filepath.Dir(file)
+	entries, err := os.ReadDir(filepath.Join(workdir, dir))
+	if err != nil {
+		return fmt.Sprintf(", and its directory %s doesn't exist either", dir)
+	}
+	names := make([]string, 0, len(entries))
+	for _, e := range entries {
+		name := e.Name()
+		if e.IsDir() {
+			name += "/"
+		}
+		names = append(names, name)
+	}
+	if len(names) == 0 {
+		return fmt.Sprintf(", and its directory %s is empty", dir)
+	}
+	return fmt.Sprintf(", its directory %s has: %s", dir, strings.Join(names, ", "))
+}
+
+// closestLine describes the line of content most like want, to show how a file missed an
+// assertion. Lines are scored by the characters they share in order with want, so a line
+// holding a near miss wins over short lines that are merely few edits away.
+func closestLine(content, want string) string {
+	dmp := diffmatchpatch.New()
+	best, bestLine, bestScore := 0, "", 0
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		score := 0
+		for _, d := range dmp.DiffMain(line, want, false) {
+			if d.Type == diffmatchpatch.DiffEqual {
+				score += len(d.Text)
+			}
+		}
+		if score > bestScore || (score == bestScore && score > 0 && len(line) < len(bestLine)) {
+			best, bestLine, bestScore = i+1, line, score
+		}
+	}
+	if bestScore == 0 {
+		return ""
+	}
+	return fmt.Sprintf("; closest line %d: %s", best, truncate(bestLine, 200))
+}
+
+// regexpSyntax matches the escapes and operators of a regular expression, which closestLine
+// drops to compare its literal text with the lines of a file.
+var regexpSyntax = regexp.MustCompile(`\\[a-zA-Z]|[\\^$.|?*+()\[\]{}]`)
+
+func sortedKeys(m map[string][]string) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
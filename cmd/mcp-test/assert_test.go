@@ -0,0 +1,151 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseRoute(t *testing.T) {
+	tests := []struct {
+		route      string
+		wantMethod string
+		wantPath   string
+		wantErr    bool
+	}{
+		{"GET /products", "GET", "/products", false},
+		{"delete /products/{id}", "DELETE", "/products/{id}", false},
+		{"/products", "", "/products", false},
+		{"FETCH /products", "", "", true},
+		{"GET products", "", "", true},
+		{"", "", "", true},
+	}
+	for _, tt := range tests {
+		method, path, err := parseRoute(tt.route)
+		if (err != nil) != tt.wantErr || method != tt.wantMethod || path != tt.wantPath {
+			t.Errorf("parseRoute(%q) = %q, %q, %v", tt.route, method, path, err)
+		}
+	}
+}
+
+func TestCheckResult(t *testing.T) {
+	no := false
+	tests := []struct {
+		name    string
+		expect  expectation
+		output  string
+		callErr error
+		wantErr string
+	}{
+		{"success", expectation{}, `{"success": true, "message": "done"}`, nil, ""},
+		{"unexpected failure", expectation{}, `{"success": false, "message": "invalid name"}`, nil, "expected success, got failure: invalid name"},
+		{"tool error", expectation{}, "", errors.New("tool returned error"), "expected success, got failure: tool returned error"},
+		{"expected failure", expectation{Success: &no}, `{"success": false}`, nil, ""},
+		{"unexpected success", expectation{Success: &no}, `{"success": true, "message": "done"}`, nil, "expected failure, got success: done"},
+		{"contains", expectation{Contains: []string{"Created"}}, `{"success": true, "message": "Created 3 files"}`, nil, ""},
+		{"missing text", expectation{Contains: []string{"Updated"}}, `{"success": true}`, nil, `expected output to contain "Updated"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkResult(tt.expect, tt.output, tt.callErr)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkResult() error = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkResult() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCheckTree(t *testing.T) {
+	workdir := writeTree(t, map[string]string{
+		"internal/models/product.go": "package models\n\nimport \"time\"\n\ntype Product struct {\n\tName  string `gorm:\"uniqueIndex\"`\n\tPrice float64\n}\n",
+	})
+	j := &job{workdir: workdir}
+
+	tests := []struct {
+		name    string
+		expect  expectation
+		wantErr string
+	}{
+		{
+			name: "passing assertions",
+			expect: expectation{
+				Files:        []string{"internal/models/product.go"},
+				FileContains: map[string][]string{"internal/models/product.go": {"Price float64"}},
+				FileMatches:  map[string][]string{"internal/models/product.go": {`Name\s+string\s+` + "`" + `[^` + "`" + `]*uniqueIndex`}},
+			},
+		},
+		{
+			name:    "missing file",
+			expect:  expectation{Files: []string{"internal/models/products.go"}},
+			wantErr: "expected internal/models/products.go to exist, its directory internal/models has: product.go",
+		},
+		{
+			name:    "missing directory",
+			expect:  expectation{Files: []string{"internal/web/product/product.go"}},
+			wantErr: "its directory internal/web/product doesn't exist either",
+		},
+		{
+			name:    "missing text",
+			expect:  expectation{FileContains: map[string][]string{"internal/models/product.go": {"Price float32"}}},
+			wantErr: `expected internal/models/product.go to contain "Price float32"; closest line 7: Price float64`,
+		},
+		{
+			name:    "regexp mismatch",
+			expect:  expectation{FileMatches: map[string][]string{"internal/models/product.go": {`Name\s+int\b`}}},
+			wantErr: "closest line 6: Name  string `gorm:\"uniqueIndex\"`",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkTree(context.Background(), j, nil, tt.expect)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("checkTree() error = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("checkTree() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoadScenario_Assertions(t *testing.T) {
+	tests := []struct {
+		name    string
+		yaml    string
+		wantErr string
+	}{
+		{"task with assertions", "task: scaffold a blog\nexpect:\n  routes: [GET /posts]\n  go_vet: true\n", ""},
+		{"invalid regexp", "steps:\n- tool: scaffold_project\n  expect:\n    file_matches:\n      go.mod: ['module (']\n", "step 1: file_matches of go.mod"},
+		{"invalid route", "task: scaffold a blog\nexpect:\n  routes: [posts]\n", `invalid route "posts"`},
+		{"steps and task", "task: scaffold a blog\nsteps:\n- tool: scaffold_project\n", "both steps and a task"},
+		{"success after the steps", "task: scaffold a blog\nexpect:\n  success: false\n", "only apply to the expect of steps"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "scenario.yaml")
+			if err := os.WriteFile(path, []byte(tt.yaml), 0644); err != nil {
+				t.Fatal(err)
+			}
+			_, err := loadScenario(path)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("loadScenario() error = %v", err)
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("loadScenario() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
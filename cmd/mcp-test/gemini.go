@@ -0,0 +1,199 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const defaultGeminiModel = "gemini-2.5-pro"
+
+// geminiBaseURL is the Gemini API endpoint of generateContent.
+const geminiBaseURL = "https://generativelanguage.googleapis.com/v1beta"
+
+// geminiProvider talks to the Gemini generateContent API.
+type geminiProvider struct {
+	apiKey    string
+	model     string
+	maxTokens int
+	system    string
+	tools     []geminiFunction
+	// contents holds the conversation. Model turns are kept as received, so the thought
+	// signatures of their function calls go back to the API unchanged.
+	contents []json.RawMessage
+}
+
+type geminiFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+type geminiContent struct {
+	Role  string       `json:"role"`
+	Parts []geminiPart `json:"parts"`
+}
+
+type geminiPart struct {
+	Text             string                  `json:"text,omitempty"`
+	FunctionCall     *geminiFunctionCall     `json:"functionCall,omitempty"`
+	FunctionResponse *geminiFunctionResponse `json:"functionResponse,omitempty"`
+}
+
+type geminiFunctionCall struct {
+	ID   string         `json:"id,omitempty"`
+	Name string         `json:"name"`
+	Args map[string]any `json:"args"`
+}
+
+type geminiFunctionResponse struct {
+	ID       string         `json:"id,omitempty"`
+	Name     string         `json:"name"`
+	Response map[string]any `json:"response"`
+}
+
+type geminiResponse struct {
+	Candidates []struct {
+		Content      json.RawMessage `json:"content"`
+		FinishReason string          `json:"finishReason"`
+	} `json:"candidates"`
+}
+
+func newGeminiProvider(opts providerOptions, tools []*mcp.Tool) (provider, error) {
+	p := &geminiProvider{apiKey: opts.apiKey, model: opts.model, maxTokens: opts.maxTokens, system: opts.system}
+	if err := p.add(geminiContent{Role: "user", Parts: []geminiPart{{Text: opts.task}}}); err != nil {
+		return nil, err
+	}
+	for _, t := range tools {
+		schema, err := toolSchema(t)
+		if err != nil {
+			log.Printf("Warning: skipping tool %s due to schema error: %v", t.Name, err)
+			continue
+		}
+		p.tools = append(p.tools, geminiFunction{Name: t.Name, Description: t.Description, Parameters: geminiSchema(schema)})
+	}
+	return p, nil
+}
+
+func (p *geminiProvider) Name() string { return "Gemini" }
+
+func (p *geminiProvider) Send(ctx context.Context) (reply, error) {
+	request := map[string]any{
+		"systemInstruction": geminiContent{Parts: []geminiPart{{Text: p.system}}},
+		"contents":          p.contents,
+		"tools":             []map[string]any{{"functionDeclarations": p.tools}},
+	}
+	if p.maxTokens > 0 {
+		request["generationConfig"] = map[string]any{"maxOutputTokens": p.maxTokens}
+	}
+
+	var response geminiResponse
+	err := postJSON(ctx, fmt.Sprintf("%s/models/%s:generateContent", geminiBaseURL, p.model), map[string]string{
+		"x-goog-api-key": p.apiKey,
+	}, request, &response)
+	if err != nil {
+		return reply{}, err
+	}
+	if len(response.Candidates) == 0 || len(response.Candidates[0].Content) == 0 {
+		return reply{}, fmt.Errorf("response has no candidates")
+	}
+
+	raw := response.Candidates[0].Content
+	var content geminiContent
+	if err := json.Unmarshal(raw, &content); err != nil {
+		return reply{}, fmt.Errorf("decoding response content: %w", err)
+	}
+	p.contents = append(p.contents, raw)
+
+	var r reply
+	var text strings.Builder
+	for _, part := range content.Parts {
+		switch {
+		case part.FunctionCall != nil:
+			id := part.FunctionCall.ID
+			if id == "" {
+				id = part.FunctionCall.Name
+			}
+			r.ToolCalls = append(r.ToolCalls, toolCall{ID: id, Name: part.FunctionCall.Name, Input: part.FunctionCall.Args})
+		case part.Text != "":
+			text.WriteString(part.Text)
+		}
+	}
+	r.Text = text.String()
+	return r, nil
+}
+
+func (p *geminiProvider) AddToolResults(results []toolResult) {
+	var parts []geminiPart
+	for _, result := range results {
+		response := map[string]any{"output": result.Output}
+		if result.IsError {
+			response = map[string]any{"error": result.Output}
+		}
+		id := result.Call.ID
+		if id == result.Call.Name {
+			id = ""
+		}
+		parts = append(parts, geminiPart{FunctionResponse: &geminiFunctionResponse{ID: id, Name: result.Call.Name, Response: response}})
+	}
+	if err := p.add(geminiContent{Role: "user", Parts: parts}); err != nil {
+		log.Printf("Warning: dropping tool results: %v", err)
+	}
+}
+
+// add appends a turn to the conversation.
+func (p *geminiProvider) add(content geminiContent) error {
+	raw, err := json.Marshal(content)
+	if err != nil {
+		return err
+	}
+	p.contents = append(p.contents, raw)
+	return nil
+}
+
+// geminiSchema converts a JSON Schema to the OpenAPI subset of Gemini function parameters:
+// one type per schema (a "null" type makes it nullable), and no keywords such as
+// additionalProperties or $schema, which Gemini rejects.
+func geminiSchema(schema map[string]any) map[string]any {
+	out := map[string]any{}
+	switch t := schema["type"].(type) {
+	case string:
+		out["type"] = strings.ToUpper(t)
+	case []any:
+		for _, v := range t {
+			if s, ok := v.(string); ok {
+				if s == "null" {
+					out["nullable"] = true
+				} else if out["type"] == nil {
+					out["type"] = strings.ToUpper(s)
+				}
+			}
+		}
+	}
+	for _, key := range []string{"description", "enum", "required"} {
+		if v, ok := schema[key]; ok {
+			out[key] = v
+		}
+	}
+	if properties, ok := schema["properties"].(map[string]any); ok && len(properties) > 0 {
+		converted := make(map[string]any, len(properties))
+		for name, property := range properties {
+			if p, ok := property.(map[string]any); ok {
+				converted[name] = geminiSchema(p)
+			}
+		}
+		out["properties"] = converted
+	}
+	if items, ok := schema["items"].(map[string]any); ok {
+		out["items"] = geminiSchema(items)
+	}
+	return out
+}
@@ -0,0 +1,238 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// Placeholders replace the parts of generated files that change from run to run, so a golden
+// tree recorded with one module path matches runs with another.
+const (
+	goldenModulePlaceholder    = "{{module}}"
+	goldenTimestampPlaceholder = "{{timestamp}}"
+)
+
+// goldenTimestamp matches RFC 3339 timestamps, such as the scaffolded_at times of
+// .mcp/scaffold-metadata.json.
+var goldenTimestamp = regexp.MustCompile(`\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}(\.\d+)?(Z|[+-]\d{2}:\d{2})`)
+
+// goldenModule matches the module directive of a go.mod file.
+var goldenModule = regexp.MustCompile(`(?m)^module\s+(\S+)`)
+
+// goldenContextLines is the number of unchanged lines shown around each change of a diff.
+const goldenContextLines = 3
+
+// readGoldenTree reads the files of a tree, normalized, keyed by slash-separated relative path.
+func readGoldenTree(root string) (map[string]string, error) {
+	files := map[string]string{}
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[filepath.ToSlash(rel)] = string(content)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Module paths come from the go.mod files of the tree, longest first so that nested
+	// modules are replaced before their parents
+	var modules []string
+	for rel, content := range files {
+		if filepath.Base(rel) != "go.mod" {
+			continue
+		}
+		if m := goldenModule.FindStringSubmatch(content); m != nil && m[1] != goldenModulePlaceholder {
+			modules = append(modules, m[1])
+		}
+	}
+	sort.Slice(modules, func(i, j int) bool { return len(modules[i]) > len(modules[j]) })
+
+	for rel, content := range files {
+		if strings.IndexByte(content, 0) >= 0 {
+			continue // Binary files are compared as they are
+		}
+		for _, module := range modules {
+			content = strings.ReplaceAll(content, module, goldenModulePlaceholder)
+		}
+		files[rel] = goldenTimestamp.ReplaceAllString(content, goldenTimestampPlaceholder)
+	}
+	return files, nil
+}
+
+// compareGolden compares the working directory with a golden tree. It returns a report of the
+// added, missing and modified files with their diffs, or "" when the trees match.
+func compareGolden(workdir, goldenDir string) (string, error) {
+	if _, err := os.Stat(goldenDir); err != nil {
+		return "", fmt.Errorf("golden tree %s not found, record it with --update-golden: %w", goldenDir, err)
+	}
+	golden, err := readGoldenTree(goldenDir)
+	if err != nil {
+		return "", fmt.Errorf("reading golden tree: %w", err)
+	}
+	actual, err := readGoldenTree(workdir)
+	if err != nil {
+		return "", fmt.Errorf("reading working directory: %w", err)
+	}
+
+	var added, missing, modified []string
+	for rel := range actual {
+		if _, ok := golden[rel]; !ok {
+			added = append(added, rel)
+		}
+	}
+	for rel, content := range golden {
+		got, ok := actual[rel]
+		switch {
+		case !ok:
+			missing = append(missing, rel)
+		case got != content:
+			modified = append(modified, rel)
+		}
+	}
+	if len(added)+len(missing)+len(modified) == 0 {
+		return "", nil
+	}
+	sort.Strings(added)
+	sort.Strings(missing)
+	sort.Strings(modified)
+
+	var report strings.Builder
+	fmt.Fprintf(&report, "Working directory %s differs from golden tree %s:\n", workdir, goldenDir)
+	for _, rel := range added {
+		fmt.Fprintf(&report, "  added:    %s\n", rel)
+	}
+	for _, rel := range missing {
+		fmt.Fprintf(&report, "  missing:  %s\n", rel)
+	}
+	for _, rel := range modified {
+		fmt.Fprintf(&report, "  modified: %s\n", rel)
+	}
+	for _, rel := range modified {
+		report.WriteString("\n")
+		report.WriteString(goldenDiff(rel, golden[rel], actual[rel]))
+	}
+	return report.String(), nil
+}
+
+// goldenDiff returns a line diff of a modified file, with goldenContextLines unchanged lines
+// around each change.
+func goldenDiff(rel, golden, actual string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- golden/%s\n+++ workdir/%s\n", rel, rel)
+	if strings.IndexByte(golden, 0) >= 0 || strings.IndexByte(actual, 0) >= 0 {
+		sb.WriteString("Binary files differ\n")
+		return sb.String()
+	}
+
+	dmp := diffmatchpatch.New()
+	a, b, lines := dmp.DiffLinesToChars(golden, actual)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lines)
+
+	for i, d := range diffs {
+		text := strings.Split(strings.TrimSuffix(d.Text, "\n"), "\n")
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			for _, line := range text {
+				sb.WriteString("-" + line + "\n")
+			}
+		case diffmatchpatch.DiffInsert:
+			for _, line := range text {
+				sb.WriteString("+" + line + "\n")
+			}
+		case diffmatchpatch.DiffEqual:
+			// Keep the lines next to the changes before and after this run of unchanged lines
+			var head, tail []string
+			if i > 0 {
+				head = text[:min(goldenContextLines, len(text))]
+				text = text[len(head):]
+			}
+			if i < len(diffs)-1 {
+				tail = text[max(0, len(text)-goldenContextLines):]
+				text = text[:len(text)-len(tail)]
+			}
+			for _, line := range head {
+				sb.WriteString(" " + line + "\n")
+			}
+			if len(text) > 0 {
+				fmt.Fprintf(&sb, "@@ %d unchanged lines @@\n", len(text))
+			}
+			for _, line := range tail {
+				sb.WriteString(" " + line + "\n")
+			}
+		}
+	}
+	return sb.String()
+}
+
+// updateGolden replaces the golden tree with the normalized files of the working directory.
+func updateGolden(workdir, goldenDir string) error {
+	files, err := readGoldenTree(workdir)
+	if err != nil {
+		return fmt.Errorf("reading working directory: %w", err)
+	}
+	if err := os.RemoveAll(goldenDir); err != nil {
+		return err
+	}
+	for rel, content := range files {
+		path := filepath.Join(goldenDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkGolden compares the job's working directory with its golden tree, or records it with
+// --update-golden. The diff report of a mismatch is logged and kept for --golden-report.
+func checkGolden(j *job) error {
+	if *updateGoldenFlag {
+		if err := updateGolden(j.workdir, j.golden); err != nil {
+			return fmt.Errorf("updating golden tree: %w", err)
+		}
+		j.log.Printf("Updated golden tree %s", j.golden)
+		return nil
+	}
+
+	report, err := compareGolden(j.workdir, j.golden)
+	if err != nil {
+		return err
+	}
+	if report == "" {
+		j.log.Printf("Golden tree %s: OK", j.golden)
+		return nil
+	}
+
+	j.goldenReport = report
+	j.log.Print(report)
+	return fmt.Errorf("working directory differs from golden tree %s, run with --update-golden to accept the changes", j.golden)
+}
@@ -0,0 +1,122 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func writeTree(t *testing.T, files map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	for rel, content := range files {
+		path := filepath.Join(root, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestReadGoldenTree_Normalizes(t *testing.T) {
+	root := writeTree(t, map[string]string{
+		"go.mod":                      "module example.com/blog\n\ngo 1.24\n",
+		"cmd/web/main.go":             "import \"example.com/blog/internal/config\"\n",
+		".mcp/scaffold-metadata.json": `{"scaffolded_at": "2026-10-17T09:30:00.123456+02:00"}`,
+		".git/HEAD":                   "ref: refs/heads/main\n",
+	})
+
+	files, err := readGoldenTree(root)
+	if err != nil {
+		t.Fatalf("readGoldenTree() error = %v", err)
+	}
+	if _, ok := files[".git/HEAD"]; ok {
+		t.Error("readGoldenTree() should skip .git")
+	}
+	if got, want := files["go.mod"], "module {{module}}\n\ngo 1.24\n"; got != want {
+		t.Errorf("go.mod = %q, want %q", got, want)
+	}
+	if got, want := files["cmd/web/main.go"], "import \"{{module}}/internal/config\"\n"; got != want {
+		t.Errorf("main.go = %q, want %q", got, want)
+	}
+	if got, want := files[".mcp/scaffold-metadata.json"], `{"scaffolded_at": "{{timestamp}}"}`; got != want {
+		t.Errorf("metadata = %q, want %q", got, want)
+	}
+}
+
+func TestCompareGolden(t *testing.T) {
+	golden := writeTree(t, map[string]string{
+		"go.mod":      "module {{module}}\n",
+		"a.go":        "package a\n\n1\n2\n3\n4\n5\n6\n7\n8\n",
+		"removed.txt": "gone\n",
+	})
+
+	same := writeTree(t, map[string]string{
+		"go.mod":      "module example.com/other\n",
+		"a.go":        "package a\n\n1\n2\n3\n4\n5\n6\n7\n8\n",
+		"removed.txt": "gone\n",
+	})
+	report, err := compareGolden(same, golden)
+	if err != nil {
+		t.Fatalf("compareGolden() error = %v", err)
+	}
+	if report != "" {
+		t.Errorf("compareGolden() = %q, want no differences", report)
+	}
+
+	changed := writeTree(t, map[string]string{
+		"go.mod":    "module example.com/other\n",
+		"a.go":      "package a\n\n1\n2\n3\n4\nfive\n6\n7\n8\n",
+		"added.txt": "new\n",
+	})
+	report, err = compareGolden(changed, golden)
+	if err != nil {
+		t.Fatalf("compareGolden() error = %v", err)
+	}
+	for _, want := range []string{
+		"added:    added.txt",
+		"missing:  removed.txt",
+		"modified: a.go",
+		"--- golden/a.go\n+++ workdir/a.go\n@@ 3 unchanged lines @@\n 2\n 3\n 4\n-5\n+five\n 6\n 7\n 8\n",
+	} {
+		if !strings.Contains(report, want) {
+			t.Errorf("compareGolden() report missing %q, got:\n%s", want, report)
+		}
+	}
+}
+
+func TestUpdateGolden(t *testing.T) {
+	workdir := writeTree(t, map[string]string{
+		"go.mod":  "module example.com/blog\n",
+		"main.go": "import \"example.com/blog/internal\"\n",
+	})
+	golden := filepath.Join(t.TempDir(), "golden")
+	if err := os.MkdirAll(golden, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(golden, "stale.txt"), []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := updateGolden(workdir, golden); err != nil {
+		t.Fatalf("updateGolden() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(golden, "stale.txt")); !os.IsNotExist(err) {
+		t.Error("updateGolden() should remove files no longer generated")
+	}
+	report, err := compareGolden(workdir, golden)
+	if err != nil {
+		t.Fatalf("compareGolden() error = %v", err)
+	}
+	if report != "" {
+		t.Errorf("compareGolden() after update = %q, want no differences", report)
+	}
+}
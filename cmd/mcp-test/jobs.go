@@ -0,0 +1,204 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// A job is one run of the harness, a scenario or a task, in a working directory of its own.
+type job struct {
+	name string
+	// kind is "scenario" or "task".
+	kind    string
+	workdir string
+	// golden is the golden tree of the job, or "" when the run isn't compared.
+	golden string
+	drive  func(ctx context.Context, j *job, session *mcp.ClientSession, tools []*mcp.Tool) error
+
+	log *log.Logger
+	// output holds everything the job logged, for the reports.
+	output syncBuffer
+	// goldenReport is the diff report of the job's working directory and its golden tree.
+	goldenReport string
+}
+
+// jobResult is the outcome of a job.
+type jobResult struct {
+	Name     string        `json:"name"`
+	Kind     string        `json:"kind"`
+	Workdir  string        `json:"workdir"`
+	Passed   bool          `json:"passed"`
+	Error    string        `json:"error,omitempty"`
+	Duration time.Duration `json:"-"`
+	Seconds  float64       `json:"duration_seconds"`
+	Output   string        `json:"-"`
+}
+
+// syncBuffer is a bytes.Buffer that is safe for concurrent writes.
+type syncBuffer struct {
+	mu  sync.Mutex
+	buf bytes.Buffer
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// stringList is a flag that can be given more than once.
+type stringList []string
+
+func (l *stringList) String() string { return strings.Join(*l, ", ") }
+
+func (l *stringList) Set(value string) error {
+	*l = append(*l, value)
+	return nil
+}
+
+// scenarioFiles returns the files of the --scenario flags, which hold comma-separated files or
+// glob patterns.
+func scenarioFiles(values []string) ([]string, error) {
+	var files []string
+	for _, value := range values {
+		for _, pattern := range strings.Split(value, ",") {
+			pattern = strings.TrimSpace(pattern)
+			if pattern == "" {
+				continue
+			}
+			matches, err := filepath.Glob(pattern)
+			if err != nil {
+				return nil, fmt.Errorf("invalid scenario pattern %q: %w", pattern, err)
+			}
+			if len(matches) == 0 {
+				return nil, fmt.Errorf("no scenario files match %q", pattern)
+			}
+			files = append(files, matches...)
+		}
+	}
+	return files, nil
+}
+
+// setupJobs names the jobs uniquely and gives them their working directories, golden trees and
+// loggers. A single job runs in --workdir and compares with --golden; several jobs each run in a
+// directory of --workdir named after them and compare with the tree of the same name in --golden.
+// Several jobs prefix their log lines with their names, since they run concurrently.
+func setupJobs(jobs []*job) {
+	seen := map[string]int{}
+	for _, j := range jobs {
+		seen[j.name]++
+		if n := seen[j.name]; n > 1 {
+			j.name = fmt.Sprintf("%s-%d", j.name, n)
+		}
+	}
+
+	for _, j := range jobs {
+		j.workdir, j.golden = *workdir, *goldenDir
+		prefix := ""
+		if len(jobs) > 1 {
+			j.workdir = filepath.Join(*workdir, j.name)
+			if *goldenDir != "" {
+				j.golden = filepath.Join(*goldenDir, j.name)
+			}
+			prefix = "[" + j.name + "] "
+		}
+		j.log = log.New(io.MultiWriter(os.Stderr, &j.output), prefix, log.LstdFlags)
+	}
+}
+
+// runJobs runs the jobs, at most parallel at a time, and returns their results in order.
+func runJobs(ctx context.Context, jobs []*job, parallel int) []jobResult {
+	if parallel < 1 {
+		parallel = 1
+	}
+	results := make([]jobResult, len(jobs))
+	sem := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, j := range jobs {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			start := time.Now()
+			err := run(ctx, j)
+			result := jobResult{
+				Name:     j.name,
+				Kind:     j.kind,
+				Workdir:  j.workdir,
+				Passed:   err == nil,
+				Duration: time.Since(start),
+			}
+			result.Seconds = result.Duration.Seconds()
+			if err != nil {
+				result.Error = err.Error()
+				j.log.Printf("FAIL: %v", err)
+			}
+			result.Output = j.output.String()
+			results[i] = result
+		}()
+	}
+	wg.Wait()
+	return results
+}
+
+// printSummary prints the results of the jobs and returns the number of failed jobs.
+func printSummary(results []jobResult) int {
+	failed := 0
+	var total time.Duration
+	fmt.Fprintln(os.Stderr, "\n=== Results ===")
+	for _, r := range results {
+		total += r.Duration
+		if r.Passed {
+			fmt.Fprintf(os.Stderr, "PASS  %s (%s)\n", r.Name, r.Duration.Round(time.Millisecond))
+			continue
+		}
+		failed++
+		message, _, _ := strings.Cut(r.Error, "\n")
+		fmt.Fprintf(os.Stderr, "FAIL  %s (%s): %s\n", r.Name, r.Duration.Round(time.Millisecond), message)
+	}
+	fmt.Fprintf(os.Stderr, "%d passed, %d failed\n", len(results)-failed, failed)
+	return failed
+}
+
+// writeGoldenReports writes the golden diff reports of the jobs to --golden-report.
+func writeGoldenReports(jobs []*job) error {
+	sorted := append([]*job(nil), jobs...)
+	sort.SliceStable(sorted, func(a, b int) bool { return sorted[a].name < sorted[b].name })
+
+	var reports []string
+	for _, j := range sorted {
+		if j.goldenReport != "" {
+			reports = append(reports, j.goldenReport)
+		}
+	}
+	if len(reports) == 0 {
+		return nil
+	}
+	if err := os.WriteFile(*goldenReport, []byte(strings.Join(reports, "\n")), 0644); err != nil {
+		return fmt.Errorf("writing golden report: %w", err)
+	}
+	return nil
+}
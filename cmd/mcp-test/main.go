@@ -3,8 +3,41 @@
 // license that can be found in the LICENSE file.
 
 // The mcp-test command is a reusable test harness that connects to an MCP server
-// using Claude as the orchestrator. It validates that MCP tools work correctly
-// by having Claude execute scaffolding commands and verifying the results.
+// using an LLM as the orchestrator. It validates that MCP tools work correctly
+// by having the model execute scaffolding commands and verifying the results.
+//
+// The model is Claude by default; --provider=openai or --provider=gemini use
+// OpenAI or Gemini instead, each converting the MCP tool schemas to its own
+// tool format. OPENAI_BASE_URL points the openai provider at another server
+// with an OpenAI-compatible API.
+//
+// --provider=local drives the harness with a local OpenAI-compatible server for
+// offline development: Ollama by default, or another server such as LM Studio
+// with --base-url or LOCAL_BASE_URL. It needs no API key, and budgets replies to
+// 2048 tokens (--max-tokens) and tool output to 4000 bytes for smaller models.
+// Failed model requests are retried with exponential backoff (--retries), which
+// covers local servers still loading a model.
+//
+// With --golden=dir the working directory is compared with a golden tree after
+// the run, with module paths and timestamps normalized, and a diff report of the
+// added, missing and modified files fails the run; --update-golden records the
+// tree instead. Golden trees kept in the repository belong under testdata.
+//
+// With --scenario=file.yaml the harness replays the tool calls of a scenario
+// file instead, checking each result against the step's expectations, so the
+// scaffolding flow runs deterministically without an API key (see scenario).
+// Expectations are declarative assertions: files that must exist, contain a
+// text or match a regular expression, routes that must be registered in
+// cmd/web/main.go, and a clean go vet. A scenario file can also hold a task for
+// the model with the assertions its result must pass, and failures describe
+// what was found instead, such as the closest line of a file.
+//
+// --task and --scenario can be given more than once, and --scenario also takes
+// comma-separated files and globs. Each task or scenario is a job, and several
+// jobs run concurrently (at most --parallel at a time) in directories of
+// --workdir named after them, each compared with the tree of the same name in
+// --golden. A summary of the results follows the run, which fails when any job
+// does; --junit and --json-report write the results as JUnit XML or JSON for CI.
 //
 // Usage: mcp-test [flags]
 //
@@ -12,6 +45,17 @@
 //
 //	export ANTHROPIC_API_KEY=sk-...
 //	mcp-test --workdir=/tmp/wizard-test-project --task="scaffold a wizard project"
+//
+//	export OPENAI_API_KEY=sk-...
+//	mcp-test --provider=openai --task="scaffold a wizard project"
+//
+//	ollama pull qwen2.5-coder:7b
+//	mcp-test --provider=local --task="scaffold a wizard project"
+//	mcp-test --provider=local --base-url=http://localhost:1234/v1 --model=qwen2.5-coder-7b-instruct --task="..."
+//
+//	mcp-test --scenario=cmd/mcp-test/scenarios/crud.yaml
+//	mcp-test --scenario=cmd/mcp-test/scenarios/crud.yaml --golden=cmd/mcp-test/testdata/golden/crud
+//	mcp-test --scenario='cmd/mcp-test/scenarios/*.yaml' --golden=cmd/mcp-test/testdata/golden --junit=report.xml
 package main
 
 import (
@@ -23,52 +67,158 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"runtime"
+	"sort"
 	"strings"
 	"time"
 
-	"github.com/anthropics/anthropic-sdk-go"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 var (
-	workdir     = flag.String("workdir", "/tmp/mcp-test", "Working directory for scaffolding")
-	task        = flag.String("task", "", "Task description for Claude to execute")
-	mcpBinary   = flag.String("mcp", "", "Path to MCP server binary (default: gomcp in PATH)")
-	model       = flag.String("model", string(anthropic.ModelClaudeSonnet4_5_20250929), "Claude model to use")
-	maxTurns    = flag.Int("max-turns", 20, "Maximum conversation turns")
-	verbose     = flag.Bool("verbose", false, "Enable verbose logging")
-	verifyBuild = flag.Bool("verify-build", true, "Run 'go build' after scaffolding to verify")
+	workdir          = flag.String("workdir", "/tmp/mcp-test", "Working directory for scaffolding; several jobs run in directories of it named after them")
+	tasks            stringList
+	scenarios        stringList
+	mcpBinary        = flag.String("mcp", "", "Path to MCP server binary (default: gomcp in PATH)")
+	providerID       = flag.String("provider", "anthropic", "LLM provider: anthropic, openai, gemini or local (an OpenAI-compatible server such as Ollama)")
+	model            = flag.String("model", "", "Model to use (default: the provider's default model)")
+	baseURL          = flag.String("base-url", "", "API endpoint of the openai and local providers (default: the provider's endpoint)")
+	maxTokens        = flag.Int("max-tokens", 0, "Maximum tokens of each model reply (default: the provider's budget)")
+	maxTurns         = flag.Int("max-turns", 20, "Maximum conversation turns")
+	retries          = flag.Int("retries", 3, "Retries of failed model requests, with exponential backoff")
+	verbose          = flag.Bool("verbose", false, "Enable verbose logging")
+	verifyBuild      = flag.Bool("verify-build", true, "Run 'go build' after scaffolding to verify")
+	goldenDir        = flag.String("golden", "", "Golden tree to compare the working directory with after the run")
+	updateGoldenFlag = flag.Bool("update-golden", false, "Record the working directory as the --golden tree instead of comparing")
+	goldenReport     = flag.String("golden-report", "", "File to write the diff report to when the working directory differs from --golden")
+	parallel         = flag.Int("parallel", runtime.GOMAXPROCS(0), "Maximum number of jobs to run at once")
+	junitReport      = flag.String("junit", "", "File to write a JUnit XML report of the results to")
+	jsonReport       = flag.String("json-report", "", "File to write a JSON report of the results to")
 )
 
+func init() {
+	flag.Var(&tasks, "task", "Task description for the model to execute (repeatable)")
+	flag.Var(&scenarios, "scenario", "Scenario file (YAML or JSON) to replay instead of running a model (repeatable; comma-separated files and globs)")
+}
+
 func main() {
 	flag.Parse()
 
-	if *task == "" {
+	if len(tasks) == 0 && len(scenarios) == 0 {
 		fmt.Fprintln(os.Stderr, "Usage: mcp-test --task=\"<task description>\"")
+		fmt.Fprintln(os.Stderr, "       mcp-test --scenario=<file.yaml>")
 		fmt.Fprintln(os.Stderr)
 		fmt.Fprintln(os.Stderr, "Example:")
 		fmt.Fprintln(os.Stderr, "  mcp-test --workdir=/tmp/wizard-test --task=\"scaffold a project with wizard\"")
 		os.Exit(2)
 	}
 
-	if os.Getenv("ANTHROPIC_API_KEY") == "" {
-		log.Fatal("ANTHROPIC_API_KEY environment variable is required")
+	files, err := scenarioFiles(scenarios)
+	if err != nil {
+		log.Fatal(err)
+	}
+	var loaded []*scenario
+	needsModel := len(tasks) > 0
+	for _, file := range files {
+		sc, err := loadScenario(file)
+		if err != nil {
+			log.Fatal(err)
+		}
+		loaded = append(loaded, sc)
+		needsModel = needsModel || sc.Task != ""
+	}
+
+	var config providerConfig
+	var apiKey string
+	if needsModel {
+		var ok bool
+		if config, ok = providers[*providerID]; !ok {
+			names := make([]string, 0, len(providers))
+			for name := range providers {
+				names = append(names, name)
+			}
+			sort.Strings(names)
+			log.Fatalf("unknown provider %q, want one of: %s", *providerID, strings.Join(names, ", "))
+		}
+		if apiKey, err = config.apiKey(); err != nil {
+			log.Fatal(err)
+		}
+		if *model == "" {
+			*model = config.defaultModel
+		}
+		if *maxTokens == 0 {
+			*maxTokens = config.defaultMaxTokens
+		}
+	}
+
+	var jobs []*job
+	for _, sc := range loaded {
+		// Scenarios replay their tool calls without a model, unless they hold a task
+		kind := "scenario"
+		drive := func(ctx context.Context, j *job, session *mcp.ClientSession, tools []*mcp.Tool) error {
+			return runScenario(ctx, j, session, sc)
+		}
+		if sc.Task != "" {
+			kind = "task"
+			drive = func(ctx context.Context, j *job, session *mcp.ClientSession, tools []*mcp.Tool) error {
+				if err := converse(ctx, j, session, tools, config, apiKey, sc.Task); err != nil {
+					return err
+				}
+				if err := checkTree(ctx, j, session, sc.Expect); err != nil {
+					return fmt.Errorf("scenario %s: %w", sc.Name, err)
+				}
+				return nil
+			}
+		}
+		jobs = append(jobs, &job{name: sc.Name, kind: kind, drive: drive})
+	}
+	for i, t := range tasks {
+		jobs = append(jobs, &job{
+			name: fmt.Sprintf("task-%d", i+1),
+			kind: "task",
+			drive: func(ctx context.Context, j *job, session *mcp.ClientSession, tools []*mcp.Tool) error {
+				return converse(ctx, j, session, tools, config, apiKey, t)
+			},
+		})
 	}
 
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
 
-	if err := run(ctx); err != nil {
-		log.Fatal(err)
+	setupJobs(jobs)
+	started := time.Now()
+	results := runJobs(ctx, jobs, *parallel)
+	elapsed := time.Since(started)
+
+	if *goldenReport != "" {
+		if err := writeGoldenReports(jobs); err != nil {
+			log.Print(err)
+		}
+	}
+	if *junitReport != "" {
+		if err := writeJUnitReport(*junitReport, results, started, elapsed); err != nil {
+			log.Printf("writing JUnit report: %v", err)
+		}
+	}
+	if *jsonReport != "" {
+		if err := writeJSONReport(*jsonReport, results, started, elapsed); err != nil {
+			log.Printf("writing JSON report: %v", err)
+		}
+	}
+
+	if failed := printSummary(results); failed > 0 {
+		os.Exit(1)
 	}
 }
 
-func run(ctx context.Context) error {
+// run starts the MCP server in the job's working directory, lets the job call its tools and
+// verifies the build of the result.
+func run(ctx context.Context, j *job) error {
 	// Setup working directory
-	if err := os.MkdirAll(*workdir, 0755); err != nil {
+	if err := os.MkdirAll(j.workdir, 0755); err != nil {
 		return fmt.Errorf("creating workdir: %w", err)
 	}
-	log.Printf("Working directory: %s", *workdir)
+	j.log.Printf("Working directory: %s", j.workdir)
 
 	// Find MCP binary
 	mcpPath := *mcpBinary
@@ -79,11 +229,11 @@ func run(ctx context.Context) error {
 			return fmt.Errorf("gomcp not found in PATH, use --mcp flag to specify: %w", err)
 		}
 	}
-	log.Printf("MCP server: %s", mcpPath)
+	j.log.Printf("MCP server: %s", mcpPath)
 
 	// Start MCP server
 	cmd := exec.Command(mcpPath)
-	cmd.Dir = *workdir
+	cmd.Dir = j.workdir
 	transport := &mcp.CommandTransport{Command: cmd}
 
 	mcpClient := mcp.NewClient(&mcp.Implementation{
@@ -96,120 +246,114 @@ func run(ctx context.Context) error {
 		return fmt.Errorf("connecting to MCP server: %w", err)
 	}
 	defer session.Close()
-	log.Printf("Connected to MCP server")
+	j.log.Printf("Connected to MCP server")
 
 	// Get available tools
 	tools, err := collectTools(ctx, session)
 	if err != nil {
 		return fmt.Errorf("getting tools: %w", err)
 	}
-	log.Printf("Found %d MCP tools", len(tools))
+	j.log.Printf("Found %d MCP tools", len(tools))
 
 	if *verbose {
 		for _, tool := range tools {
-			log.Printf("  - %s: %s", tool.Name, truncate(tool.Description, 60))
+			j.log.Printf("  - %s: %s", tool.Name, truncate(tool.Description, 60))
 		}
 	}
 
-	// Convert MCP tools to Anthropic tool format
-	anthropicTools := convertToAnthropicTools(tools)
+	if err := j.drive(ctx, j, session, tools); err != nil {
+		return err
+	}
 
-	// Create Claude client
-	claudeClient := anthropic.NewClient()
+	// Compare with the golden tree before the build check, which tidies go.mod and go.sum
+	if j.golden != "" {
+		if err := checkGolden(j); err != nil {
+			return err
+		}
+	}
 
-	// Build system prompt
-	systemPrompt := buildSystemPrompt()
+	// Verify build if requested
+	if *verifyBuild {
+		if err := verifyGoBuild(ctx, j); err != nil {
+			return fmt.Errorf("build verification failed: %w", err)
+		}
+	}
 
-	// Run conversation loop
-	messages := []anthropic.MessageParam{
-		anthropic.NewUserMessage(anthropic.NewTextBlock(*task)),
+	j.log.Printf("\n=== Test completed successfully ===")
+	return nil
+}
+
+// converse has the provider's model run the task with the MCP tools.
+func converse(ctx context.Context, j *job, session *mcp.ClientSession, tools []*mcp.Tool, config providerConfig, apiKey, task string) error {
+	// Start the conversation with the provider, which converts the MCP tools to its tool format
+	llm, err := config.new(providerOptions{
+		apiKey:    apiKey,
+		baseURL:   config.baseURL(),
+		model:     *model,
+		maxTokens: *maxTokens,
+		system:    buildSystemPrompt(),
+		task:      task,
+	}, tools)
+	if err != nil {
+		return fmt.Errorf("setting up %s: %w", *providerID, err)
 	}
+	j.log.Printf("Provider: %s (%s)", *providerID, *model)
 
+	// Run conversation loop
 	for turn := 0; turn < *maxTurns; turn++ {
-		log.Printf("\n=== Turn %d ===", turn+1)
-
-		// Call Claude
-		response, err := claudeClient.Messages.New(ctx, anthropic.MessageNewParams{
-			Model:     anthropic.Model(*model),
-			MaxTokens: 4096,
-			System:    []anthropic.TextBlockParam{{Text: systemPrompt}},
-			Messages:  messages,
-			Tools:     anthropicTools,
-		})
+		j.log.Printf("\n=== Turn %d ===", turn+1)
+
+		// Call the model
+		response, err := llm.Send(ctx)
 		if err != nil {
-			return fmt.Errorf("calling Claude: %w", err)
+			return fmt.Errorf("calling %s: %w", llm.Name(), err)
 		}
 
-		// Process response
-		var toolUses []anthropic.ToolUseBlock
-		var textContent strings.Builder
-
-		for _, block := range response.Content {
-			switch b := block.AsAny().(type) {
-			case anthropic.TextBlock:
-				textContent.WriteString(b.Text)
-				if *verbose {
-					log.Printf("[Claude]: %s", b.Text)
-				}
-			case anthropic.ToolUseBlock:
-				toolUses = append(toolUses, b)
-				log.Printf("[Tool Call]: %s", b.Name)
-				if *verbose {
-					inputJSON, _ := json.MarshalIndent(b.Input, "", "  ")
-					log.Printf("  Input: %s", string(inputJSON))
-				}
+		if *verbose && response.Text != "" {
+			j.log.Printf("[%s]: %s", llm.Name(), response.Text)
+		}
+		for _, call := range response.ToolCalls {
+			j.log.Printf("[Tool Call]: %s", call.Name)
+			if *verbose {
+				inputJSON, _ := json.MarshalIndent(call.Input, "", "  ")
+				j.log.Printf("  Input: %s", string(inputJSON))
 			}
 		}
 
-		// Add assistant response to messages
-		messages = append(messages, response.ToParam())
-
 		// Check if done
-		if response.StopReason == "end_turn" && len(toolUses) == 0 {
-			log.Printf("\n=== Claude completed task ===")
-			if textContent.Len() > 0 {
-				fmt.Println(textContent.String())
+		if len(response.ToolCalls) == 0 {
+			j.log.Printf("\n=== %s completed task ===", llm.Name())
+			if response.Text != "" {
+				j.log.Print(response.Text)
 			}
 			break
 		}
 
 		// Execute tool calls
-		if len(toolUses) > 0 {
-			var toolResults []anthropic.ContentBlockParamUnion
-
-			for _, toolUse := range toolUses {
-				result, err := executeToolCall(ctx, session, toolUse)
-				if err != nil {
-					log.Printf("[Tool Error]: %s: %v", toolUse.Name, err)
-					toolResults = append(toolResults, anthropic.NewToolResultBlock(
-						toolUse.ID,
-						fmt.Sprintf("Error: %v", err),
-						true, // is_error
-					))
-				} else {
-					if *verbose {
-						log.Printf("[Tool Result]: %s", truncate(result, 500))
-					}
-					toolResults = append(toolResults, anthropic.NewToolResultBlock(
-						toolUse.ID,
-						result,
-						false,
-					))
+		var toolResults []toolResult
+		for _, call := range response.ToolCalls {
+			if call.InputErr != nil {
+				j.log.Printf("[Tool Error]: %s: %v", call.Name, call.InputErr)
+				toolResults = append(toolResults, toolResult{Call: call, Output: fmt.Sprintf("Error: %v, retry the call with valid JSON", call.InputErr), IsError: true})
+				continue
+			}
+			result, err := executeToolCall(ctx, session, call)
+			if err != nil {
+				j.log.Printf("[Tool Error]: %s: %v", call.Name, err)
+				toolResults = append(toolResults, toolResult{Call: call, Output: fmt.Sprintf("Error: %v", err), IsError: true})
+			} else {
+				if *verbose {
+					j.log.Printf("[Tool Result]: %s", truncate(result, 500))
+				}
+				// Models with small context windows only get the start of long results
+				if config.maxToolOutput > 0 {
+					result = truncate(result, config.maxToolOutput)
 				}
+				toolResults = append(toolResults, toolResult{Call: call, Output: result})
 			}
-
-			messages = append(messages, anthropic.NewUserMessage(toolResults...))
 		}
+		llm.AddToolResults(toolResults)
 	}
-
-	// Verify build if requested
-	if *verifyBuild {
-		if err := verifyGoBuild(ctx); err != nil {
-			return fmt.Errorf("build verification failed: %w", err)
-		}
-	}
-
-	log.Printf("\n=== Test completed successfully ===")
 	return nil
 }
 
@@ -224,45 +368,11 @@ func collectTools(ctx context.Context, session *mcp.ClientSession) ([]*mcp.Tool,
 	return tools, nil
 }
 
-func convertToAnthropicTools(mcpTools []*mcp.Tool) []anthropic.ToolUnionParam {
-	var tools []anthropic.ToolUnionParam
-	for _, t := range mcpTools {
-		// Convert MCP InputSchema to JSON
-		schemaJSON, err := json.Marshal(t.InputSchema)
-		if err != nil {
-			log.Printf("Warning: skipping tool %s due to schema error: %v", t.Name, err)
-			continue
-		}
-
-		// Parse as raw JSON for Anthropic
-		var schema anthropic.ToolInputSchemaParam
-		if err := json.Unmarshal(schemaJSON, &schema); err != nil {
-			log.Printf("Warning: skipping tool %s due to schema parse error: %v", t.Name, err)
-			continue
-		}
-
-		description := t.Description
-		tool := anthropic.ToolParam{
-			Name:        t.Name,
-			Description: anthropic.String(description),
-			InputSchema: schema,
-		}
-		tools = append(tools, anthropic.ToolUnionParam{OfTool: &tool})
-	}
-	return tools
-}
-
-func executeToolCall(ctx context.Context, session *mcp.ClientSession, toolUse anthropic.ToolUseBlock) (string, error) {
-	// Parse input JSON to map
-	var inputMap map[string]any
-	if err := json.Unmarshal(toolUse.Input, &inputMap); err != nil {
-		return "", fmt.Errorf("parsing tool input: %w", err)
-	}
-
+func executeToolCall(ctx context.Context, session *mcp.ClientSession, call toolCall) (string, error) {
 	// Call MCP tool
 	result, err := session.CallTool(ctx, &mcp.CallToolParams{
-		Name:      toolUse.Name,
-		Arguments: inputMap,
+		Name:      call.Name,
+		Arguments: call.Input,
 	})
 	if err != nil {
 		return "", err
@@ -311,33 +421,33 @@ When you're done scaffolding, summarize:
 - Files that were generated`
 }
 
-func verifyGoBuild(ctx context.Context) error {
+func verifyGoBuild(ctx context.Context, j *job) error {
 	// Check if go.mod exists
-	goModPath := filepath.Join(*workdir, "go.mod")
+	goModPath := filepath.Join(j.workdir, "go.mod")
 	if _, err := os.Stat(goModPath); os.IsNotExist(err) {
-		log.Printf("No go.mod found, skipping build verification")
+		j.log.Printf("No go.mod found, skipping build verification")
 		return nil
 	}
 
-	log.Printf("\n=== Verifying Go build ===")
+	j.log.Printf("\n=== Verifying Go build ===")
 
 	// Run go mod tidy
 	tidyCmd := exec.CommandContext(ctx, "go", "mod", "tidy")
-	tidyCmd.Dir = *workdir
+	tidyCmd.Dir = j.workdir
 	tidyOutput, err := tidyCmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("go mod tidy failed: %w\n%s", err, string(tidyOutput))
 	}
-	log.Printf("go mod tidy: OK")
+	j.log.Printf("go mod tidy: OK")
 
 	// Run go build
 	buildCmd := exec.CommandContext(ctx, "go", "build", "./...")
-	buildCmd.Dir = *workdir
+	buildCmd.Dir = j.workdir
 	buildOutput, err := buildCmd.CombinedOutput()
 	if err != nil {
 		return fmt.Errorf("go build failed: %w\n%s", err, string(buildOutput))
 	}
-	log.Printf("go build ./...: OK")
+	j.log.Printf("go build ./...: OK")
 
 	return nil
 }
@@ -0,0 +1,162 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultOpenAIModel   = "gpt-4.1"
+	defaultOpenAIBaseURL = "https://api.openai.com/v1"
+
+	// The local provider defaults to Ollama; LM Studio serves http://localhost:1234/v1.
+	defaultLocalModel   = "qwen2.5-coder:7b"
+	defaultLocalBaseURL = "http://localhost:11434/v1"
+)
+
+// openAIProvider talks to the OpenAI Chat Completions API. OPENAI_BASE_URL points it at
+// another server with a compatible API, such as OpenRouter; the local provider is the same
+// client for servers such as Ollama and LM Studio.
+type openAIProvider struct {
+	name      string
+	apiKey    string
+	baseURL   string
+	model     string
+	maxTokens int
+	// maxTokensField is the request field of maxTokens: OpenAI has replaced max_tokens with
+	// max_completion_tokens, which compatible servers don't all know yet.
+	maxTokensField string
+	tools          []openAITool
+	messages       []openAIMessage
+}
+
+type openAITool struct {
+	Type     string         `json:"type"`
+	Function openAIFunction `json:"function"`
+}
+
+type openAIFunction struct {
+	Name        string         `json:"name"`
+	Description string         `json:"description,omitempty"`
+	Parameters  map[string]any `json:"parameters,omitempty"`
+}
+
+type openAIMessage struct {
+	Role       string           `json:"role"`
+	Content    *string          `json:"content"`
+	ToolCalls  []openAIToolCall `json:"tool_calls,omitempty"`
+	ToolCallID string           `json:"tool_call_id,omitempty"`
+}
+
+type openAIToolCall struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Function struct {
+		Name string `json:"name"`
+		// Arguments is the JSON object of the call's input, encoded as a string.
+		Arguments string `json:"arguments"`
+	} `json:"function"`
+}
+
+type openAIResponse struct {
+	Choices []struct {
+		Message      openAIMessage `json:"message"`
+		FinishReason string        `json:"finish_reason"`
+	} `json:"choices"`
+}
+
+func newOpenAIProvider(opts providerOptions, tools []*mcp.Tool) (provider, error) {
+	return newChatCompletionsProvider("OpenAI", "max_completion_tokens", opts, tools), nil
+}
+
+func newLocalProvider(opts providerOptions, tools []*mcp.Tool) (provider, error) {
+	return newChatCompletionsProvider("Local model", "max_tokens", opts, tools), nil
+}
+
+func newChatCompletionsProvider(name, maxTokensField string, opts providerOptions, tools []*mcp.Tool) *openAIProvider {
+	p := &openAIProvider{
+		name:           name,
+		apiKey:         opts.apiKey,
+		baseURL:        strings.TrimSuffix(opts.baseURL, "/"),
+		model:          opts.model,
+		maxTokens:      opts.maxTokens,
+		maxTokensField: maxTokensField,
+		messages: []openAIMessage{
+			{Role: "system", Content: &opts.system},
+			{Role: "user", Content: &opts.task},
+		},
+	}
+	for _, t := range tools {
+		schema, err := toolSchema(t)
+		if err != nil {
+			log.Printf("Warning: skipping tool %s due to schema error: %v", t.Name, err)
+			continue
+		}
+		p.tools = append(p.tools, openAITool{
+			Type:     "function",
+			Function: openAIFunction{Name: t.Name, Description: t.Description, Parameters: schema},
+		})
+	}
+	return p
+}
+
+func (p *openAIProvider) Name() string { return p.name }
+
+func (p *openAIProvider) Send(ctx context.Context) (reply, error) {
+	request := map[string]any{
+		"model":    p.model,
+		"messages": p.messages,
+		"tools":    p.tools,
+	}
+	if p.maxTokens > 0 {
+		request[p.maxTokensField] = p.maxTokens
+	}
+	headers := map[string]string{}
+	if p.apiKey != "" {
+		headers["Authorization"] = "Bearer " + p.apiKey
+	}
+
+	var response openAIResponse
+	err := postJSON(ctx, p.baseURL+"/chat/completions", headers, request, &response)
+	if err != nil {
+		return reply{}, err
+	}
+	if len(response.Choices) == 0 {
+		return reply{}, fmt.Errorf("response has no choices")
+	}
+
+	message := response.Choices[0].Message
+	p.messages = append(p.messages, message)
+
+	var r reply
+	if message.Content != nil {
+		r.Text = *message.Content
+	}
+	for _, call := range message.ToolCalls {
+		c := toolCall{ID: call.ID, Name: call.Function.Name}
+		if call.Function.Arguments != "" {
+			if err := json.Unmarshal([]byte(call.Function.Arguments), &c.Input); err != nil {
+				c.InputErr = fmt.Errorf("input is not a JSON object: %w", err)
+			}
+		}
+		r.ToolCalls = append(r.ToolCalls, c)
+	}
+	return r, nil
+}
+
+func (p *openAIProvider) AddToolResults(results []toolResult) {
+	for _, result := range results {
+		// Chat Completions has no error flag: the output of failed calls starts with "Error:"
+		content := result.Output
+		p.messages = append(p.messages, openAIMessage{Role: "tool", Content: &content, ToolCallID: result.Call.ID})
+	}
+}
@@ -0,0 +1,209 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// A provider is the LLM API that orchestrates the MCP tools. It keeps the conversation,
+// so the loop in run only deals with the text and tool calls of each reply.
+type provider interface {
+	// Name names the provider in logs and errors.
+	Name() string
+	// Send sends the conversation so far and adds the model's reply to it.
+	Send(ctx context.Context) (reply, error)
+	// AddToolResults adds the results of the tool calls of the last reply to the conversation.
+	AddToolResults(results []toolResult)
+}
+
+// reply is a model's answer: text, tool calls to run, or both.
+type reply struct {
+	Text      string
+	ToolCalls []toolCall
+}
+
+// toolCall is a call of an MCP tool requested by the model.
+type toolCall struct {
+	// ID matches the result to the call; providers without call IDs use the tool name.
+	ID    string
+	Name  string
+	Input map[string]any
+	// InputErr is set when the model sent input that isn't a JSON object, which smaller
+	// models do now and then. The call isn't run, and the error goes back to the model.
+	InputErr error
+}
+
+// toolResult is the output of a tool call sent back to the model.
+type toolResult struct {
+	Call    toolCall
+	Output  string
+	IsError bool
+}
+
+// providerOptions configure the conversation of a provider.
+type providerOptions struct {
+	apiKey string
+	// baseURL is the API endpoint of providers with a configurable one.
+	baseURL string
+	model   string
+	// maxTokens limits the length of each reply; 0 leaves it to the API.
+	maxTokens int
+	system    string
+	task      string
+}
+
+// providerConfig describes a supported provider.
+type providerConfig struct {
+	apiKeyEnv string
+	// apiKeyOptional is set for servers that don't check API keys, such as local ones.
+	apiKeyOptional bool
+	// baseURLEnv names the environment variable that overrides defaultBaseURL.
+	baseURLEnv       string
+	defaultModel     string
+	defaultBaseURL   string
+	defaultMaxTokens int
+	// maxToolOutput truncates the tool output sent to the model, for models with small
+	// context windows; 0 sends it whole.
+	maxToolOutput int
+	new           func(opts providerOptions, tools []*mcp.Tool) (provider, error)
+}
+
+// providers are the supported values of --provider.
+var providers = map[string]providerConfig{
+	"anthropic": {apiKeyEnv: "ANTHROPIC_API_KEY", defaultModel: defaultAnthropicModel, defaultMaxTokens: 4096, new: newAnthropicProvider},
+	"openai":    {apiKeyEnv: "OPENAI_API_KEY", baseURLEnv: "OPENAI_BASE_URL", defaultModel: defaultOpenAIModel, defaultBaseURL: defaultOpenAIBaseURL, new: newOpenAIProvider},
+	"gemini":    {apiKeyEnv: "GEMINI_API_KEY", defaultModel: defaultGeminiModel, new: newGeminiProvider},
+	"local": {
+		apiKeyEnv:        "LOCAL_API_KEY",
+		apiKeyOptional:   true,
+		baseURLEnv:       "LOCAL_BASE_URL",
+		defaultModel:     defaultLocalModel,
+		defaultBaseURL:   defaultLocalBaseURL,
+		defaultMaxTokens: 2048,
+		maxToolOutput:    4000,
+		new:              newLocalProvider,
+	},
+}
+
+// apiKey returns the API key of a provider from its environment variable.
+func (c providerConfig) apiKey() (string, error) {
+	key := os.Getenv(c.apiKeyEnv)
+	if key == "" && !c.apiKeyOptional {
+		return "", fmt.Errorf("%s environment variable is required", c.apiKeyEnv)
+	}
+	return key, nil
+}
+
+// baseURL returns the API endpoint of a provider: the --base-url flag, its environment
+// variable or its default.
+func (c providerConfig) baseURL() string {
+	if *baseURL != "" {
+		return *baseURL
+	}
+	if c.baseURLEnv != "" {
+		if url := os.Getenv(c.baseURLEnv); url != "" {
+			return url
+		}
+	}
+	return c.defaultBaseURL
+}
+
+// toolSchema returns the input schema of an MCP tool as a JSON object.
+func toolSchema(t *mcp.Tool) (map[string]any, error) {
+	schemaJSON, err := json.Marshal(t.InputSchema)
+	if err != nil {
+		return nil, err
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(schemaJSON, &schema); err != nil {
+		return nil, err
+	}
+	return schema, nil
+}
+
+// retryBaseDelay is the wait before the first retry of a request; it doubles with every retry.
+var retryBaseDelay = time.Second
+
+// postJSON posts body as JSON to url and decodes the JSON response into out. Failed
+// connections, rate limits and server errors are retried up to --retries times with
+// exponential backoff, since local servers refuse or fail requests while they load a model.
+func postJSON(ctx context.Context, url string, headers map[string]string, body, out any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("encoding request: %w", err)
+	}
+
+	for attempt := 0; ; attempt++ {
+		respBody, retryAfter, err := post(ctx, url, headers, payload)
+		if err == nil {
+			if err := json.Unmarshal(respBody, out); err != nil {
+				return fmt.Errorf("decoding response: %w", err)
+			}
+			return nil
+		}
+		if retryAfter < 0 || attempt >= *retries || ctx.Err() != nil {
+			return err
+		}
+
+		delay := retryBaseDelay << attempt
+		if retryAfter > delay {
+			delay = retryAfter
+		}
+		log.Printf("Request failed (%v), retrying in %s", err, delay)
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// post sends one request and returns the response body. On failure, retryAfter is the wait
+// the server asked for before a retry (0 when it didn't say), or negative when the request
+// must not be retried.
+func post(ctx context.Context, url string, headers map[string]string, payload []byte) (body []byte, retryAfter time.Duration, err error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return nil, -1, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for name, value := range headers {
+		req.Header.Set(name, value)
+	}
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer resp.Body.Close()
+
+	body, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("reading response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		err := fmt.Errorf("%s: %s", resp.Status, truncate(string(body), 1000))
+		if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusRequestTimeout && resp.StatusCode < 500 {
+			return nil, -1, err
+		}
+		if seconds, convErr := strconv.Atoi(resp.Header.Get("Retry-After")); convErr == nil {
+			retryAfter = time.Duration(seconds) * time.Second
+		}
+		return nil, retryAfter, err
+	}
+	return body, 0, nil
+}
@@ -0,0 +1,73 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPostJSON_Retries(t *testing.T) {
+	retryBaseDelay = time.Millisecond
+	defer func() { retryBaseDelay = time.Second }()
+
+	tests := []struct {
+		name      string
+		statuses  []int
+		wantCalls int
+		wantErr   string
+	}{
+		{"succeeds after server errors", []int{503, 500, 200}, 3, ""},
+		{"retries rate limits", []int{429, 200}, 2, ""},
+		{"gives up after the retries", []int{503, 503, 503, 503, 200}, 4, "503 Service Unavailable"},
+		{"doesn't retry client errors", []int{400, 200}, 1, "400 Bad Request"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			calls := 0
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				status := tt.statuses[calls]
+				calls++
+				w.WriteHeader(status)
+				w.Write([]byte(`{"ok": true}`))
+			}))
+			defer server.Close()
+
+			var out struct {
+				OK bool `json:"ok"`
+			}
+			err := postJSON(context.Background(), server.URL, nil, map[string]any{}, &out)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("postJSON() error = %v", err)
+				}
+				if !out.OK {
+					t.Error("postJSON() didn't decode the response")
+				}
+			} else if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("postJSON() error = %v, want %q", err, tt.wantErr)
+			}
+			if calls != tt.wantCalls {
+				t.Errorf("postJSON() made %d requests, want %d", calls, tt.wantCalls)
+			}
+		})
+	}
+}
+
+func TestProviderConfig_APIKey(t *testing.T) {
+	t.Setenv("LOCAL_API_KEY", "")
+	t.Setenv("OPENAI_API_KEY", "")
+
+	if _, err := providers["local"].apiKey(); err != nil {
+		t.Errorf("local apiKey() error = %v, want none without a key", err)
+	}
+	if _, err := providers["openai"].apiKey(); err == nil {
+		t.Error("openai apiKey() should require OPENAI_API_KEY")
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"os"
+	"time"
+)
+
+// junitTestSuites is the root of a JUnit XML report, as read by CI systems.
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	Time      string          `xml:"time,attr"`
+	Timestamp string          `xml:"timestamp,attr"`
+	Cases     []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	Classname string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut *junitText    `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Text    string `xml:",cdata"`
+}
+
+// junitText is text kept as CDATA, so the lines of logs stay readable in the report.
+type junitText struct {
+	Text string `xml:",cdata"`
+}
+
+// junitSeconds formats a duration the way JUnit reports do.
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
+
+// writeJUnitReport writes the results as a JUnit XML report with one test case per job.
+func writeJUnitReport(path string, results []jobResult, started time.Time, elapsed time.Duration) error {
+	suite := junitTestSuite{
+		Name:      "mcp-test",
+		Tests:     len(results),
+		Time:      junitSeconds(elapsed),
+		Timestamp: started.UTC().Format(time.RFC3339),
+	}
+	for _, r := range results {
+		tc := junitTestCase{
+			Name:      r.Name,
+			Classname: "mcp-test." + r.Kind,
+			Time:      junitSeconds(r.Duration),
+		}
+		if r.Output != "" {
+			tc.SystemOut = &junitText{Text: r.Output}
+		}
+		if !r.Passed {
+			suite.Failures++
+			tc.Failure = &junitFailure{Message: r.Error, Text: r.Error}
+		}
+		suite.Cases = append(suite.Cases, tc)
+	}
+
+	report := junitTestSuites{
+		Tests:    suite.Tests,
+		Failures: suite.Failures,
+		Time:     suite.Time,
+		Suites:   []junitTestSuite{suite},
+	}
+	data, err := xml.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return err
+	}
+	data = append([]byte(xml.Header), data...)
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// writeJSONReport writes the results as a JSON report.
+func writeJSONReport(path string, results []jobResult, started time.Time, elapsed time.Duration) error {
+	failed := 0
+	for _, r := range results {
+		if !r.Passed {
+			failed++
+		}
+	}
+	data, err := json.MarshalIndent(map[string]any{
+		"started_at":       started.UTC().Format(time.RFC3339),
+		"duration_seconds": elapsed.Seconds(),
+		"passed":           len(results) - failed,
+		"failed":           failed,
+		"results":          results,
+	}, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
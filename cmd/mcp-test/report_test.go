@@ -0,0 +1,86 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+var testResults = []jobResult{
+	{Name: "crud", Kind: "scenario", Workdir: "/tmp/w/crud", Passed: true, Duration: 1500 * time.Millisecond, Seconds: 1.5, Output: "line one\nline two\n"},
+	{Name: "task-1", Kind: "task", Workdir: "/tmp/w/task-1", Error: "step 2 (scaffold_domain): expected success", Duration: time.Second, Seconds: 1},
+}
+
+func TestWriteJUnitReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.xml")
+	started := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	if err := writeJUnitReport(path, testResults, started, 2500*time.Millisecond); err != nil {
+		t.Fatalf("writeJUnitReport() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report junitTestSuites
+	if err := xml.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not valid XML: %v", err)
+	}
+	if report.Tests != 2 || report.Failures != 1 || report.Time != "2.500" {
+		t.Errorf("testsuites = tests %d, failures %d, time %s; want 2, 1, 2.500", report.Tests, report.Failures, report.Time)
+	}
+	if len(report.Suites) != 1 || len(report.Suites[0].Cases) != 2 {
+		t.Fatalf("report = %+v, want one suite with two cases", report)
+	}
+	suite := report.Suites[0]
+	if suite.Timestamp != "2026-01-02T03:04:05Z" {
+		t.Errorf("timestamp = %q", suite.Timestamp)
+	}
+
+	passed, failed := suite.Cases[0], suite.Cases[1]
+	if passed.Name != "crud" || passed.Classname != "mcp-test.scenario" || passed.Time != "1.500" || passed.Failure != nil {
+		t.Errorf("passed case = %+v", passed)
+	}
+	if passed.SystemOut == nil || passed.SystemOut.Text != "line one\nline two\n" {
+		t.Errorf("passed case system-out = %+v, want the job output", passed.SystemOut)
+	}
+	if failed.Failure == nil || failed.Failure.Message != testResults[1].Error {
+		t.Errorf("failed case failure = %+v, want %q", failed.Failure, testResults[1].Error)
+	}
+	if failed.SystemOut != nil {
+		t.Errorf("failed case system-out = %+v, want none without output", failed.SystemOut)
+	}
+}
+
+func TestWriteJSONReport(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "report.json")
+	if err := writeJSONReport(path, testResults, time.Now(), 2*time.Second); err != nil {
+		t.Fatalf("writeJSONReport() error = %v", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var report struct {
+		Passed  int         `json:"passed"`
+		Failed  int         `json:"failed"`
+		Results []jobResult `json:"results"`
+	}
+	if err := json.Unmarshal(data, &report); err != nil {
+		t.Fatalf("report is not valid JSON: %v", err)
+	}
+	if report.Passed != 1 || report.Failed != 1 || len(report.Results) != 2 {
+		t.Fatalf("report = %+v, want 1 passed and 1 failed", report)
+	}
+	if got := report.Results[1]; got.Name != "task-1" || got.Passed || got.Error == "" || got.Seconds != 1 {
+		t.Errorf("results[1] = %+v", got)
+	}
+}
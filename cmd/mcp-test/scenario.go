@@ -0,0 +1,169 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// A scenario is a fixed sequence of tool calls replayed without an LLM, so the scaffolding
+// flow runs deterministically, e.g. in CI:
+//
+//	name: blog
+//	steps:
+//	  - tool: scaffold_project
+//	    input: {project_name: blog, module_path: example.com/blog, in_current_dir: true}
+//	    expect:
+//	      files: [go.mod, cmd/web/main.go]
+//	  - tool: scaffold_domain
+//	    input:
+//	      domain_name: post
+//	      fields:
+//	        - {name: Title, type: string}
+//	    expect:
+//	      file_contains:
+//	        internal/models/post.go: ["Title string"]
+//	expect:
+//	  routes: [GET /posts, POST /posts]
+//	  go_vet: true
+//
+// A scenario can instead hold a task for the model, with the assertions its result must pass:
+//
+//	name: wizard
+//	task: Scaffold a blog with a multi-step wizard to create posts
+//	expect:
+//	  file_matches:
+//	    internal/web/post/wizard.go: ['func \(c \*Controller\) Wizard\w*\(']
+type scenario struct {
+	Name  string         `json:"name"`
+	Task  string         `json:"task"`
+	Steps []scenarioStep `json:"steps"`
+	// Expect holds the assertions checked after the steps or the task. They can't check
+	// the result of a tool call, so success and contains are only for steps.
+	Expect expectation `json:"expect"`
+}
+
+// scenarioStep is a tool call of a scenario and what it must produce.
+type scenarioStep struct {
+	// Name describes the step in logs; it defaults to the tool name.
+	Name   string         `json:"name"`
+	Tool   string         `json:"tool"`
+	Input  map[string]any `json:"input"`
+	Expect expectation    `json:"expect"`
+}
+
+// expectation holds the assertions of a step. Paths are relative to the working directory.
+type expectation struct {
+	// Success is whether the tool must succeed (the default) or fail.
+	Success *bool `json:"success"`
+	// Contains are texts the tool's output must contain, such as a part of its message.
+	Contains []string `json:"contains"`
+	// Files must exist after the step.
+	Files []string `json:"files"`
+	// FileContains maps files to texts they must contain after the step.
+	FileContains map[string][]string `json:"file_contains"`
+	// FileMatches maps files to regular expressions they must match after the step.
+	FileMatches map[string][]string `json:"file_matches"`
+	// Routes must be registered in cmd/web/main.go, as "METHOD /path" or "/path" for any
+	// method, e.g. "GET /products/{id}".
+	Routes []string `json:"routes"`
+	// GoVet runs 'go mod tidy' and 'go vet ./...', which must pass.
+	GoVet bool `json:"go_vet"`
+}
+
+// loadScenario reads a scenario from a YAML or JSON file.
+func loadScenario(path string) (*scenario, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	data := content
+	if ext := strings.ToLower(filepath.Ext(path)); ext == ".yaml" || ext == ".yml" {
+		doc, err := decodeYAML(string(content))
+		if err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+		if data, err = json.Marshal(doc); err != nil {
+			return nil, fmt.Errorf("parsing %s: %w", path, err)
+		}
+	}
+
+	// Unknown fields are most likely misspelled assertions, which would silently pass
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var s scenario
+	if err := decoder.Decode(&s); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+
+	switch {
+	case len(s.Steps) == 0 && s.Task == "":
+		return nil, fmt.Errorf("%s: scenario has no steps or task", path)
+	case len(s.Steps) > 0 && s.Task != "":
+		return nil, fmt.Errorf("%s: scenario has both steps and a task", path)
+	case s.Expect.Success != nil || len(s.Expect.Contains) > 0:
+		return nil, fmt.Errorf("%s: success and contains only apply to the expect of steps", path)
+	}
+	for i, step := range s.Steps {
+		if step.Tool == "" {
+			return nil, fmt.Errorf("%s: step %d has no tool", path, i+1)
+		}
+		if err := step.Expect.compile(); err != nil {
+			return nil, fmt.Errorf("%s: step %d: %w", path, i+1, err)
+		}
+	}
+	if err := s.Expect.compile(); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	if s.Name == "" {
+		s.Name = strings.TrimSuffix(filepath.Base(path), filepath.Ext(path))
+	}
+	return &s, nil
+}
+
+// runScenario replays the steps of a scenario and stops at the first failed assertion.
+func runScenario(ctx context.Context, j *job, session *mcp.ClientSession, s *scenario) error {
+	j.log.Printf("Scenario: %s (%d steps)", s.Name, len(s.Steps))
+
+	for i, step := range s.Steps {
+		name := step.Name
+		if name == "" {
+			name = step.Tool
+		}
+		j.log.Printf("\n=== Step %d: %s ===", i+1, name)
+		if *verbose {
+			inputJSON, _ := json.MarshalIndent(step.Input, "", "  ")
+			j.log.Printf("  Input: %s", string(inputJSON))
+		}
+
+		output, err := executeToolCall(ctx, session, toolCall{Name: step.Tool, Input: step.Input})
+		if *verbose {
+			j.log.Printf("[Tool Result]: %s", truncate(output, 500))
+		}
+		if err := checkResult(step.Expect, output, err); err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, name, err)
+		}
+		if err := checkTree(ctx, j, session, step.Expect); err != nil {
+			return fmt.Errorf("step %d (%s): %w", i+1, name, err)
+		}
+		j.log.Printf("[Step %d]: OK", i+1)
+	}
+
+	if err := checkTree(ctx, j, session, s.Expect); err != nil {
+		return fmt.Errorf("scenario %s: %w", s.Name, err)
+	}
+
+	j.log.Printf("\n=== Scenario %s passed ===", s.Name)
+	return nil
+}
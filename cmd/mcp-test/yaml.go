@@ -0,0 +1,461 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// decodeYAML decodes the subset of YAML scenario files use into maps, slices and scalars:
+// block mappings and sequences, flow collections ([a, b] and {a: 1}), plain, single- and
+// double-quoted scalars, literal (|) and folded (>) block scalars, and comments. Anchors,
+// tags and multiple documents are not supported.
+func decodeYAML(data string) (any, error) {
+	p := &yamlParser{lines: strings.Split(strings.ReplaceAll(data, "\r\n", "\n"), "\n")}
+	for i, line := range p.lines {
+		if strings.HasPrefix(strings.TrimLeft(line, " "), "\t") && strings.TrimSpace(line) != "" {
+			return nil, fmt.Errorf("line %d: tabs are not allowed for indentation", i+1)
+		}
+	}
+	// A document start marker is allowed before the document
+	if i, ok := p.next(); ok && p.text(i) == "---" {
+		p.pos = i + 1
+	}
+	i, ok := p.next()
+	if !ok {
+		return nil, nil
+	}
+	value, err := p.node(p.indent(i))
+	if err != nil {
+		return nil, err
+	}
+	if i, ok := p.next(); ok {
+		return nil, fmt.Errorf("line %d: unexpected %q", i+1, p.text(i))
+	}
+	return value, nil
+}
+
+// yamlParser walks the lines of a YAML document.
+type yamlParser struct {
+	lines []string
+	pos   int
+}
+
+// next returns the index of the next line with content, skipping blank and comment lines.
+func (p *yamlParser) next() (int, bool) {
+	for i := p.pos; i < len(p.lines); i++ {
+		if p.text(i) != "" {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+// indent returns the number of leading spaces of a line.
+func (p *yamlParser) indent(i int) int {
+	return len(p.lines[i]) - len(strings.TrimLeft(p.lines[i], " "))
+}
+
+// text returns a line without its indentation and comment.
+func (p *yamlParser) text(i int) string {
+	return strings.TrimSpace(stripYAMLComment(p.lines[i]))
+}
+
+// node parses the block node starting at the next line, indented by indent.
+func (p *yamlParser) node(indent int) (any, error) {
+	i, ok := p.next()
+	if !ok || p.indent(i) < indent {
+		return nil, nil
+	}
+	text := p.text(i)
+	if isYAMLSequenceItem(text) {
+		return p.sequence(p.indent(i))
+	}
+	if _, _, ok := splitYAMLKey(text); ok {
+		return p.mapping(p.indent(i))
+	}
+	p.pos = i + 1
+	return parseYAMLValue(text, i)
+}
+
+// mapping parses the entries of a block mapping indented by indent.
+func (p *yamlParser) mapping(indent int) (map[string]any, error) {
+	m := map[string]any{}
+	for {
+		i, ok := p.next()
+		if !ok || p.indent(i) < indent {
+			return m, nil
+		}
+		text := p.text(i)
+		if p.indent(i) > indent || isYAMLSequenceItem(text) {
+			return nil, fmt.Errorf("line %d: unexpected %q", i+1, text)
+		}
+		key, rest, ok := splitYAMLKey(text)
+		if !ok {
+			return nil, fmt.Errorf("line %d: expected a key: value entry, got %q", i+1, text)
+		}
+		if _, exists := m[key]; exists {
+			return nil, fmt.Errorf("line %d: duplicate key %q", i+1, key)
+		}
+		p.pos = i + 1
+
+		value, err := p.value(indent, rest, i)
+		if err != nil {
+			return nil, err
+		}
+		m[key] = value
+	}
+}
+
+// sequence parses the items of a block sequence indented by indent.
+func (p *yamlParser) sequence(indent int) ([]any, error) {
+	var items []any
+	for {
+		i, ok := p.next()
+		if !ok || p.indent(i) < indent {
+			return items, nil
+		}
+		text := p.text(i)
+		if p.indent(i) > indent || !isYAMLSequenceItem(text) {
+			// A sequence under a mapping key may share the key's indentation
+			if p.indent(i) == indent {
+				return items, nil
+			}
+			return nil, fmt.Errorf("line %d: unexpected %q", i+1, text)
+		}
+
+		rest := strings.TrimSpace(strings.TrimPrefix(text, "-"))
+		if _, _, ok := splitYAMLKey(rest); ok || isYAMLSequenceItem(rest) {
+			// "- key: value" and "- - item" start a block indented like the text after the dash
+			itemIndent := indent + strings.Index(p.lines[i][indent:], rest)
+			p.lines[i] = strings.Repeat(" ", itemIndent) + p.lines[i][itemIndent:]
+			item, err := p.node(itemIndent)
+			if err != nil {
+				return nil, err
+			}
+			items = append(items, item)
+			continue
+		}
+
+		p.pos = i + 1
+		item, err := p.value(indent, rest, i)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+}
+
+// value parses the value of a mapping entry or sequence item whose inline text is rest: an
+// inline value, a block scalar or a nested block.
+func (p *yamlParser) value(indent int, rest string, line int) (any, error) {
+	switch {
+	case rest == "":
+		next, ok := p.next()
+		if !ok {
+			return nil, nil
+		}
+		if p.indent(next) > indent {
+			return p.node(p.indent(next))
+		}
+		if p.indent(next) == indent && isYAMLSequenceItem(p.text(next)) {
+			return p.sequence(indent)
+		}
+		return nil, nil
+	case strings.HasPrefix(rest, "|") || strings.HasPrefix(rest, ">"):
+		return p.blockScalar(indent, rest, line)
+	default:
+		return parseYAMLValue(rest, line)
+	}
+}
+
+// blockScalar parses a literal (|) or folded (>) block scalar. The "-" and "+" indicators
+// strip or keep the final line breaks.
+func (p *yamlParser) blockScalar(indent int, header string, line int) (string, error) {
+	chomp := strings.TrimLeft(header, "|>")
+	if chomp != "" && chomp != "-" && chomp != "+" {
+		return "", fmt.Errorf("line %d: unsupported block scalar header %q", line+1, header)
+	}
+
+	var lines []string
+	blockIndent := -1
+	for ; p.pos < len(p.lines); p.pos++ {
+		raw := p.lines[p.pos]
+		if strings.TrimSpace(raw) == "" {
+			lines = append(lines, "")
+			continue
+		}
+		lineIndent := len(raw) - len(strings.TrimLeft(raw, " "))
+		if blockIndent == -1 {
+			blockIndent = lineIndent
+		}
+		if lineIndent <= indent || lineIndent < blockIndent {
+			break
+		}
+		lines = append(lines, raw[blockIndent:])
+	}
+	// Trailing blank lines belong to the chomping, not the content
+	trailing := 0
+	for len(lines) > 0 && lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+		trailing++
+	}
+	if blockIndent == -1 || len(lines) == 0 {
+		return "", nil
+	}
+
+	var text string
+	if header[0] == '|' {
+		text = strings.Join(lines, "\n")
+	} else {
+		// Folding joins lines with spaces; blank lines become line breaks
+		var b strings.Builder
+		for i, l := range lines {
+			switch {
+			case l == "":
+				b.WriteString("\n")
+			case i > 0 && lines[i-1] != "":
+				b.WriteString(" ")
+			}
+			b.WriteString(l)
+		}
+		text = b.String()
+	}
+
+	switch chomp {
+	case "-":
+		return text, nil
+	case "+":
+		return text + strings.Repeat("\n", trailing+1), nil
+	default:
+		return text + "\n", nil
+	}
+}
+
+// parseYAMLValue parses an inline value: a flow collection or a scalar.
+func parseYAMLValue(text string, line int) (any, error) {
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		value, rest, err := parseYAMLFlow(text)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: %w", line+1, err)
+		}
+		if strings.TrimSpace(rest) != "" {
+			return nil, fmt.Errorf("line %d: unexpected %q after flow collection", line+1, rest)
+		}
+		return value, nil
+	}
+	value, err := parseYAMLScalar(text)
+	if err != nil {
+		return nil, fmt.Errorf("line %d: %w", line+1, err)
+	}
+	return value, nil
+}
+
+// parseYAMLFlow parses a flow collection at the start of s and returns the text after it.
+func parseYAMLFlow(s string) (any, string, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, "", fmt.Errorf("unexpected end of flow collection")
+	}
+	switch s[0] {
+	case '[':
+		items := []any{}
+		s = strings.TrimSpace(s[1:])
+		for {
+			if strings.HasPrefix(s, "]") {
+				return items, s[1:], nil
+			}
+			item, rest, err := parseYAMLFlow(s)
+			if err != nil {
+				return nil, "", err
+			}
+			items = append(items, item)
+			if s, err = flowSeparator(rest, ']'); err != nil {
+				return nil, "", err
+			}
+		}
+	case '{':
+		m := map[string]any{}
+		s = strings.TrimSpace(s[1:])
+		for {
+			if strings.HasPrefix(s, "}") {
+				return m, s[1:], nil
+			}
+			end := flowScalarEnd(s, true)
+			key, err := parseYAMLScalar(strings.TrimSpace(s[:end]))
+			if err != nil {
+				return nil, "", err
+			}
+			rest := strings.TrimSpace(s[end:])
+			if !strings.HasPrefix(rest, ":") {
+				return nil, "", fmt.Errorf("expected ':' after flow mapping key %v", key)
+			}
+			value, rest, err := parseYAMLFlow(rest[1:])
+			if err != nil {
+				return nil, "", err
+			}
+			m[fmt.Sprint(key)] = value
+			if s, err = flowSeparator(rest, '}'); err != nil {
+				return nil, "", err
+			}
+		}
+	default:
+		end := flowScalarEnd(s, false)
+		value, err := parseYAMLScalar(strings.TrimSpace(s[:end]))
+		return value, s[end:], err
+	}
+}
+
+// flowSeparator skips the comma after a flow collection entry, or stops before its closing bracket.
+func flowSeparator(s string, closing byte) (string, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.HasPrefix(s, ","):
+		return strings.TrimSpace(s[1:]), nil
+	case len(s) > 0 && s[0] == closing:
+		return s, nil
+	}
+	return "", fmt.Errorf("expected ',' or '%c' in flow collection, got %q", closing, s)
+}
+
+// flowScalarEnd returns the length of the scalar at the start of s within a flow collection.
+func flowScalarEnd(s string, isKey bool) int {
+	if len(s) > 0 && (s[0] == '"' || s[0] == '\'') {
+		if end := quotedEnd(s); end > 0 {
+			return end
+		}
+	}
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case ',', ']', '}':
+			return i
+		case ':':
+			if isKey && (i+1 == len(s) || s[i+1] == ' ') {
+				return i
+			}
+		}
+	}
+	return len(s)
+}
+
+// quotedEnd returns the length of the quoted scalar at the start of s, or 0 when unterminated.
+func quotedEnd(s string) int {
+	quote := s[0]
+	for i := 1; i < len(s); i++ {
+		switch {
+		case quote == '"' && s[i] == '\\':
+			i++
+		case s[i] == quote && quote == '\'' && i+1 < len(s) && s[i+1] == '\'':
+			i++
+		case s[i] == quote:
+			return i + 1
+		}
+	}
+	return 0
+}
+
+// yamlNumber matches the plain scalars that are numbers.
+var yamlNumber = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+
+// parseYAMLScalar parses a quoted or plain scalar, resolving plain booleans, nulls and numbers.
+func parseYAMLScalar(s string) (any, error) {
+	switch {
+	case strings.HasPrefix(s, `"`):
+		if quotedEnd(s) != len(s) {
+			return nil, fmt.Errorf("invalid double-quoted scalar %s", s)
+		}
+		value, err := strconv.Unquote(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid double-quoted scalar %s: %w", s, err)
+		}
+		return value, nil
+	case strings.HasPrefix(s, "'"):
+		if quotedEnd(s) != len(s) {
+			return nil, fmt.Errorf("invalid single-quoted scalar %s", s)
+		}
+		return strings.ReplaceAll(s[1:len(s)-1], "''", "'"), nil
+	}
+
+	switch strings.ToLower(s) {
+	case "", "~", "null":
+		return nil, nil
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	}
+	if yamlNumber.MatchString(s) {
+		if n, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return n, nil
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return f, nil
+		}
+	}
+	return s, nil
+}
+
+// isYAMLSequenceItem reports whether a line is an item of a block sequence.
+func isYAMLSequenceItem(text string) bool {
+	return text == "-" || strings.HasPrefix(text, "- ")
+}
+
+// splitYAMLKey splits a "key: value" line into its unquoted key and value text.
+func splitYAMLKey(text string) (key, rest string, ok bool) {
+	if strings.HasPrefix(text, "[") || strings.HasPrefix(text, "{") {
+		return "", "", false
+	}
+	end := -1
+	if text != "" && (text[0] == '"' || text[0] == '\'') {
+		q := quotedEnd(text)
+		if q == 0 || q == len(text) || text[q] != ':' {
+			return "", "", false
+		}
+		end = q
+	} else {
+		for i := 0; i < len(text); i++ {
+			if text[i] == ':' && (i+1 == len(text) || text[i+1] == ' ') {
+				end = i
+				break
+			}
+		}
+	}
+	if end <= 0 || (end+1 < len(text) && text[end+1] != ' ') {
+		return "", "", false
+	}
+	k, err := parseYAMLScalar(strings.TrimSpace(text[:end]))
+	if err != nil {
+		return "", "", false
+	}
+	return fmt.Sprint(k), strings.TrimSpace(text[end+1:]), true
+}
+
+// stripYAMLComment removes a comment from a line: a # at the start or after a space,
+// outside quotes.
+func stripYAMLComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		c := line[i]
+		switch {
+		case quote == '"' && c == '\\':
+			i++
+		case quote != 0:
+			if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			// Quotes only start a scalar at its beginning, not inside plain text such as don't
+			if i == 0 || strings.ContainsRune(" [{,:-", rune(line[i-1])) {
+				quote = c
+			}
+		case c == '#' && (i == 0 || line[i-1] == ' ' || line[i-1] == '\t'):
+			return line[:i]
+		}
+	}
+	return line
+}
@@ -0,0 +1,90 @@
+// Copyright 2025 Doug Barrett. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package main
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want any
+	}{
+		{
+			name: "mappings and sequences",
+			yaml: "name: crud # comment\nsteps:\n- tool: scaffold_project\n  input:\n    with_auth: true\n    count: 25\n- tool: scaffold_domain\n",
+			want: map[string]any{
+				"name": "crud",
+				"steps": []any{
+					map[string]any{"tool": "scaffold_project", "input": map[string]any{"with_auth": true, "count": int64(25)}},
+					map[string]any{"tool": "scaffold_domain"},
+				},
+			},
+		},
+		{
+			name: "flow collections",
+			yaml: "fields: [{name: Title, type: string}, {name: Price, type: float64}]\nempty: []\nurl: {u: http://localhost:8080}\n",
+			want: map[string]any{
+				"fields": []any{
+					map[string]any{"name": "Title", "type": "string"},
+					map[string]any{"name": "Price", "type": "float64"},
+				},
+				"empty": []any{},
+				"url":   map[string]any{"u": "http://localhost:8080"},
+			},
+		},
+		{
+			name: "scalars",
+			yaml: "a: 'it''s'\nb: \"q # not a comment\\n\"\nc: ~\nd: 2.5\ne: 1.0.0\nf: don't\n",
+			want: map[string]any{"a": "it's", "b": "q # not a comment\n", "c": nil, "d": 2.5, "e": "1.0.0", "f": "don't"},
+		},
+		{
+			name: "block scalars",
+			yaml: "literal: |\n  line one\n    indented\n\n  line three\nfolded: >-\n  one\n  two\n\n  three\nnext: x\n",
+			want: map[string]any{"literal": "line one\n  indented\n\nline three\n", "folded": "one two\nthree", "next": "x"},
+		},
+		{
+			name: "nested sequences",
+			yaml: "---\n- - a\n  - b\n- c: 1\n  d:\n    - e\n",
+			want: []any{[]any{"a", "b"}, map[string]any{"c": int64(1), "d": []any{"e"}}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := decodeYAML(tt.yaml)
+			if err != nil {
+				t.Fatalf("decodeYAML() error = %v", err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("decodeYAML() = %#v, want %#v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDecodeYAML_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		yaml string
+		want string
+	}{
+		{"bad indentation", "a: 1\n  b: 2\n", `line 2: unexpected "b: 2"`},
+		{"duplicate key", "a: 1\na: 2\n", `line 2: duplicate key "a"`},
+		{"unterminated flow", "a: [1, 2\n", "line 1: expected ',' or ']'"},
+		{"tab indentation", "a:\n\tb: 1\n", "line 2: tabs are not allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := decodeYAML(tt.yaml)
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Errorf("decodeYAML() error = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,242 @@
+// Package config reads .gomcp.toml, the project configuration of the scaffolder. Its
+// defaults fill in the inputs a tool call leaves empty, so every scaffold_domain call
+// doesn't have to repeat the project's layout, route group and form style.
+//
+//	[defaults]
+//	layout = "dashboard"
+//	route_group = "authenticated"
+//	form_style = "page"
+//	database_type = "postgres"
+//
+//	[templates]
+//	dir = ".gomcp/templates"
+//
+//	[project]
+//	root = "apps/web"
+//
+// In a monorepo the file sits at the repository root, and project.root names the
+// directory of the scaffolded project, where go.mod, main.go and .mcp/ are looked up.
+package config
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/utils"
+)
+
+// FileName is the name of the configuration file in the project directory.
+const FileName = ".gomcp.toml"
+
+// Config is the project configuration.
+type Config struct {
+	// Dir is the directory of the configuration file, empty without one.
+	Dir string
+	// Defaults are the values of tool inputs that are left empty.
+	Defaults Defaults
+	// TemplatesDir is the directory of template overrides, relative to Dir.
+	// A file there replaces the built-in template of the same path, e.g. domain/model.go.tmpl.
+	TemplatesDir string
+	// ProjectRoot is the directory of the project, relative to Dir. Empty means Dir itself.
+	ProjectRoot string
+}
+
+// Defaults are the default values of tool inputs.
+type Defaults struct {
+	// Layout is the view layout: dashboard, base, auth or none.
+	Layout string
+	// RouteGroup is the middleware context: public, authenticated or admin.
+	RouteGroup string
+	// FormStyle is how forms are displayed: modal or page.
+	FormStyle string
+	// DatabaseType is the database of new projects: sqlite, postgres or mysql.
+	DatabaseType string
+}
+
+// Load reads the configuration of the project in dir, from the nearest configuration file
+// in dir or its parents, up to the root of the git repository. A project without a
+// configuration file has an empty configuration.
+func Load(dir string) (Config, error) {
+	file := find(dir)
+	if file == "" {
+		return Config{}, nil
+	}
+	content, err := os.ReadFile(file)
+	if err != nil {
+		return Config{}, fmt.Errorf("reading %s: %w", FileName, err)
+	}
+	cfg, err := Parse(content)
+	if err != nil {
+		return Config{}, fmt.Errorf("%s: %w", file, err)
+	}
+	cfg.Dir = filepath.Dir(file)
+	return cfg, nil
+}
+
+// find returns the path of the nearest configuration file, or "" when there is none.
+func find(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+	for {
+		file := filepath.Join(dir, FileName)
+		if _, err := os.Stat(file); err == nil {
+			return file
+		}
+		if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+			return ""
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return ""
+		}
+		dir = parent
+	}
+}
+
+// ProjectDir returns the directory of the project to scaffold in for the working directory
+// dir. That is project.root when dir holds the configuration file, and dir otherwise, so
+// a server started at the root of a monorepo works in its project.
+func ProjectDir(dir string) string {
+	cfg, err := Load(dir)
+	if err != nil || cfg.ProjectRoot == "" {
+		return dir
+	}
+	if abs, err := filepath.Abs(dir); err != nil || abs != cfg.Dir {
+		return dir
+	}
+	return filepath.Join(dir, cfg.ProjectRoot)
+}
+
+// Parse parses a configuration file. It accepts the subset of TOML the configuration
+// uses: tables, string values and comments. Unknown tables and keys are errors, so a
+// misspelled default doesn't go unnoticed.
+func Parse(content []byte) (Config, error) {
+	var cfg Config
+	keys := map[string]map[string]*string{
+		"defaults": {
+			"layout":        &cfg.Defaults.Layout,
+			"route_group":   &cfg.Defaults.RouteGroup,
+			"form_style":    &cfg.Defaults.FormStyle,
+			"database_type": &cfg.Defaults.DatabaseType,
+		},
+		"templates": {
+			"dir": &cfg.TemplatesDir,
+		},
+		"project": {
+			"root": &cfg.ProjectRoot,
+		},
+	}
+
+	table := ""
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(stripComment(scanner.Text()))
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			if !strings.HasSuffix(line, "]") {
+				return Config{}, fmt.Errorf("line %d: invalid table header %q", n, line)
+			}
+			table = strings.TrimSpace(line[1 : len(line)-1])
+			if keys[table] == nil {
+				return Config{}, fmt.Errorf("line %d: unknown table [%s]", n, table)
+			}
+			continue
+		}
+
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			return Config{}, fmt.Errorf("line %d: expected key = \"value\", got %q", n, line)
+		}
+		key = strings.TrimSpace(key)
+		if table == "" {
+			return Config{}, fmt.Errorf("line %d: %s must be in a table, e.g. [defaults]", n, key)
+		}
+		field, ok := keys[table][key]
+		if !ok {
+			return Config{}, fmt.Errorf("line %d: unknown key %s in [%s]", n, key, table)
+		}
+		s, err := parseString(strings.TrimSpace(value))
+		if err != nil {
+			return Config{}, fmt.Errorf("line %d: %s: %w", n, key, err)
+		}
+		*field = s
+	}
+	if err := scanner.Err(); err != nil {
+		return Config{}, err
+	}
+	return cfg, cfg.validate()
+}
+
+// validate checks the defaults against the values the tools accept.
+func (c Config) validate() error {
+	if err := utils.ValidateLayout(c.Defaults.Layout); err != nil {
+		return fmt.Errorf("defaults.layout: %w", err)
+	}
+	if err := utils.ValidateRouteGroup(c.Defaults.RouteGroup); err != nil {
+		return fmt.Errorf("defaults.route_group: %w", err)
+	}
+	if err := utils.ValidateFormStyle(c.Defaults.FormStyle); err != nil {
+		return fmt.Errorf("defaults.form_style: %w", err)
+	}
+	if err := utils.ValidateDatabaseType(c.Defaults.DatabaseType); err != nil {
+		return fmt.Errorf("defaults.database_type: %w", err)
+	}
+	for _, dir := range []struct{ key, value string }{{"templates.dir", c.TemplatesDir}, {"project.root", c.ProjectRoot}} {
+		if filepath.IsAbs(dir.value) || strings.HasPrefix(filepath.Clean(dir.value), "..") {
+			return fmt.Errorf("%s must be a directory below %s, got %q", dir.key, FileName, dir.value)
+		}
+	}
+	return nil
+}
+
+// parseString parses a basic ("...") or literal ('...') TOML string.
+func parseString(value string) (string, error) {
+	switch {
+	case len(value) >= 2 && value[0] == '"' && value[len(value)-1] == '"':
+		s, err := strconv.Unquote(value)
+		if err != nil {
+			return "", fmt.Errorf("invalid string %s", value)
+		}
+		return s, nil
+	case len(value) >= 2 && value[0] == '\'' && value[len(value)-1] == '\'':
+		return value[1 : len(value)-1], nil
+	}
+	return "", fmt.Errorf("expected a quoted string, got %s", value)
+}
+
+// stripComment removes a # comment that isn't inside a string.
+func stripComment(line string) string {
+	var quote byte
+	for i := 0; i < len(line); i++ {
+		switch c := line[i]; {
+		case quote != 0:
+			if c == '\\' && quote == '"' {
+				i++
+			} else if c == quote {
+				quote = 0
+			}
+		case c == '"' || c == '\'':
+			quote = c
+		case c == '#':
+			return line[:i]
+		}
+	}
+	return line
+}
+
+// Or returns value, or def when value is empty.
+func Or(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	content := `# Project defaults
+[defaults]
+layout = "base"
+route_group = "authenticated"  # behind the login
+form_style = 'page'
+database_type = "postgres"
+
+[templates]
+dir = ".gomcp/templates"
+`
+	cfg, err := Parse([]byte(content))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+	want := Config{
+		Defaults:     Defaults{Layout: "base", RouteGroup: "authenticated", FormStyle: "page", DatabaseType: "postgres"},
+		TemplatesDir: ".gomcp/templates",
+	}
+	if cfg != want {
+		t.Errorf("Parse() = %+v, want %+v", cfg, want)
+	}
+}
+
+func TestParse_Errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr string
+	}{
+		{"unknown table", "[default]\n", "line 1: unknown table [default]"},
+		{"unknown key", "[defaults]\nlayuot = \"base\"\n", "line 2: unknown key layuot in [defaults]"},
+		{"key outside a table", "layout = \"base\"\n", "line 1: layout must be in a table"},
+		{"unquoted value", "[defaults]\nlayout = base\n", "line 2: layout: expected a quoted string, got base"},
+		{"missing value", "[defaults]\nlayout\n", `line 2: expected key = "value"`},
+		{"invalid layout", "[defaults]\nlayout = \"sidebar\"\n", "defaults.layout: invalid layout 'sidebar'"},
+		{"invalid route group", "[defaults]\nroute_group = \"private\"\n", "defaults.route_group: invalid route_group 'private'"},
+		{"invalid form style", "[defaults]\nform_style = \"drawer\"\n", "defaults.form_style: invalid form_style 'drawer'"},
+		{"invalid database", "[defaults]\ndatabase_type = \"oracle\"\n", "defaults.database_type: invalid database type 'oracle'"},
+		{"templates outside the project", "[templates]\ndir = \"../templates\"\n", "templates.dir must be a directory below .gomcp.toml"},
+		{"project outside the repository", "[project]\nroot = \"/srv/web\"\n", "project.root must be a directory below .gomcp.toml"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse([]byte(tt.content))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir := t.TempDir()
+	cfg, err := Load(dir)
+	if err != nil || cfg != (Config{}) {
+		t.Errorf("expected an empty config without %s, got %+v, %v", FileName, cfg, err)
+	}
+
+	if err := os.WriteFile(filepath.Join(dir, FileName), []byte("[defaults]\nform_style = \"modal\" # \"page\" for long forms\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	cfg, err = Load(dir)
+	if err != nil || cfg.Defaults.FormStyle != "modal" {
+		t.Errorf("Load() = %+v, %v", cfg, err)
+	}
+}
+
+func TestProjectDir(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.WriteFile(filepath.Join(repo, FileName), []byte("[project]\nroot = \"apps/web\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	web := filepath.Join(repo, "apps", "web")
+	if err := os.MkdirAll(web, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := ProjectDir(repo); got != web {
+		t.Errorf("ProjectDir(repo) = %q, want %q", got, web)
+	}
+	// Directories below the configuration file are projects of their own
+	if got := ProjectDir(web); got != web {
+		t.Errorf("ProjectDir(web) = %q, want %q", got, web)
+	}
+	if cfg, err := Load(web); err != nil || cfg.Dir != repo {
+		t.Errorf("expected the configuration of the repository from its project, got %+v, %v", cfg, err)
+	}
+}
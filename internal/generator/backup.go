@@ -0,0 +1,37 @@
+package generator
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/dbb1dev/go-mcp/internal/utils"
+)
+
+// SetBackupDir sets the directory, relative to the base path, that keeps the previous version
+// of every file the generator overwrites, so a bad regeneration can be undone without git.
+// The backup of each overwritten file is reported in Backups.
+func (g *Generator) SetBackupDir(dir string) {
+	g.backupDir = dir
+}
+
+// backup copies a file about to be overwritten into the backup directory.
+func (g *Generator) backup(relPath string) error {
+	if g.backupDir == "" {
+		return nil
+	}
+	if err := utils.CopyFile(g.FullPath(relPath), g.FullPath(filepath.Join(g.backupDir, relPath))); err != nil {
+		return fmt.Errorf("failed to back up %s: %w", relPath, err)
+	}
+	return nil
+}
+
+// updated records an overwritten file, and the path of its backup.
+func (g *Generator) updated(relPath string) {
+	g.filesUpdated = append(g.filesUpdated, relPath)
+	if g.backupDir != "" && !g.dryRun {
+		if g.backups == nil {
+			g.backups = map[string]string{}
+		}
+		g.backups[relPath] = filepath.Join(g.backupDir, relPath)
+	}
+}
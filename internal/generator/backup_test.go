@@ -0,0 +1,93 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestGenerator_Backup tests that overwritten files are backed up and reported.
+func TestGenerator_Backup(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "generator-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	for _, buffered := range []bool{false, true} {
+		if err := os.WriteFile(filepath.Join(tmpDir, "existing.txt"), []byte("original"), 0644); err != nil {
+			t.Fatalf("Failed to create existing file: %v", err)
+		}
+		name := "direct"
+		if buffered {
+			name = "buffered"
+		}
+		backupDir := filepath.Join("backups", name)
+		gen := NewGenerator(testFS, tmpDir)
+		gen.SetForceOverwrite(true)
+		gen.SetBackupDir(backupDir)
+		gen.SetBuffered(buffered)
+
+		if err := gen.GenerateFileFromString("existing.txt", "replaced"); err != nil {
+			t.Fatalf("GenerateFileFromString() error = %v", err)
+		}
+		if err := gen.GenerateFileFromString(name+".txt", "new"); err != nil {
+			t.Fatalf("GenerateFileFromString() error = %v", err)
+		}
+		if err := gen.Flush(); err != nil {
+			t.Fatalf("Flush() error = %v", err)
+		}
+
+		backup := filepath.Join(backupDir, "existing.txt")
+		if data, err := os.ReadFile(filepath.Join(tmpDir, backup)); err != nil || string(data) != "original" {
+			t.Errorf("buffered=%t: backup = %q, %v; want the previous version", buffered, string(data), err)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, backupDir, name+".txt")); !os.IsNotExist(err) {
+			t.Errorf("buffered=%t: new files should not be backed up", buffered)
+		}
+		result := gen.Result()
+		if len(result.FilesUpdated) != 1 || result.FilesUpdated[0] != "existing.txt" {
+			t.Errorf("buffered=%t: FilesUpdated = %v, want [existing.txt]", buffered, result.FilesUpdated)
+		}
+		if len(result.Backups) != 1 || result.Backups["existing.txt"] != backup {
+			t.Errorf("buffered=%t: Backups = %v, want existing.txt: %s", buffered, result.Backups, backup)
+		}
+	}
+}
+
+// TestGenerator_Backup_Rollback tests that a failed Flush removes the backups it made.
+func TestGenerator_Backup_Rollback(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "generator-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	if err := os.MkdirAll(filepath.Join(tmpDir, "sub"), 0755); err != nil {
+		t.Fatalf("Failed to create directory: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(tmpDir, "sub", "existing.txt"), []byte("original"), 0644); err != nil {
+		t.Fatalf("Failed to create existing file: %v", err)
+	}
+	// A file where a directory is needed makes the last write fail
+	if err := os.WriteFile(filepath.Join(tmpDir, "blocker"), []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to create blocker file: %v", err)
+	}
+
+	gen := NewGenerator(testFS, tmpDir)
+	gen.SetBuffered(true)
+	gen.SetForceOverwrite(true)
+	gen.SetBackupDir(filepath.Join(".mcp", "backups", "20240101-120000.000"))
+	gen.GenerateFileFromString("sub/existing.txt", "replaced")
+	gen.GenerateFileFromString("blocker/file.txt", "fails")
+
+	if err := gen.Flush(); err == nil {
+		t.Fatal("Flush() error = nil, want a write failure")
+	}
+	if gen.DirExists(".mcp") {
+		t.Error("Flush() should remove the backup directory when it rolls back")
+	}
+	if backups := gen.Result().Backups; len(backups) != 0 {
+		t.Errorf("Backups = %v, want none after a rollback", backups)
+	}
+}
@@ -0,0 +1,160 @@
+package generator
+
+import (
+	"errors"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/utils"
+)
+
+// buffer keeps a generated file until Flush. A file generated twice keeps its last content.
+func (g *Generator) buffer(relPath, content string, existed bool) {
+	for i := range g.pending {
+		if g.pending[i].path == relPath {
+			g.pending[i].content = content
+			return
+		}
+	}
+	g.pending = append(g.pending, pendingFile{path: relPath, content: content})
+	if existed {
+		g.updated(relPath)
+	} else {
+		g.filesCreated = append(g.filesCreated, relPath)
+	}
+}
+
+// isPending reports whether a file is buffered.
+func (g *Generator) isPending(relPath string) bool {
+	for _, f := range g.pending {
+		if f.path == relPath {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks the buffered files before they are written: every Go file must parse.
+func (g *Generator) Validate() error {
+	var errs []error
+	fset := token.NewFileSet()
+	for _, f := range g.pending {
+		if filepath.Ext(f.path) != ".go" {
+			continue
+		}
+		if _, err := parser.ParseFile(fset, f.path, f.content, parser.SkipObjectResolution); err != nil {
+			errs = append(errs, fmt.Errorf("generated %s is not valid Go: %w", f.path, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Flush validates the buffered files and writes them in one pass. Nothing is written when a
+// file is invalid, and when a write fails the files and directories written so far are
+// restored, so the project is left as it was. The buffer is empty afterwards.
+func (g *Generator) Flush() error {
+	if err := g.Validate(); err != nil {
+		return err
+	}
+	pending, pendingDirs := g.pending, g.pendingDirs
+	g.pending, g.pendingDirs = nil, nil
+
+	// Remember what the writes change, to put it back on failure
+	newDirs := map[string]bool{}
+	dirs := pendingDirs
+	for _, f := range pending {
+		dirs = append(dirs, filepath.Dir(f.path))
+	}
+	for _, dir := range dirs {
+		for ; dir != "." && dir != string(filepath.Separator) && !g.DirExists(dir); dir = filepath.Dir(dir) {
+			newDirs[dir] = true
+		}
+	}
+	if !g.DirExists(".") {
+		newDirs["."] = true
+	}
+	if g.backupDir != "" {
+		for dir := g.backupDir; dir != "." && !g.DirExists(dir); dir = filepath.Dir(dir) {
+			newDirs[dir] = true
+		}
+	}
+	originals := map[string][]byte{}
+
+	for _, dir := range pendingDirs {
+		if err := utils.EnsureDir(g.FullPath(dir)); err != nil {
+			return g.rollbackFlush(fmt.Errorf("failed to create directory %s: %w", dir, err), originals, newDirs)
+		}
+	}
+	for _, f := range pending {
+		fullPath := g.FullPath(f.path)
+		original, err := os.ReadFile(fullPath)
+		if err != nil {
+			original = nil
+		}
+		if original != nil {
+			if err := g.backup(f.path); err != nil {
+				return g.rollbackFlush(err, originals, newDirs)
+			}
+		}
+		originals[f.path] = original
+		if err := utils.WriteFileString(fullPath, f.content, true); err != nil {
+			return g.rollbackFlush(err, originals, newDirs)
+		}
+	}
+	return nil
+}
+
+// rollbackFlush undoes the writes of a failed Flush: files that existed get their original
+// content back, new files and new directories are removed, and so is the backup directory
+// when the Flush created it.
+func (g *Generator) rollbackFlush(cause error, originals map[string][]byte, newDirs map[string]bool) error {
+	var errs []string
+	n := 0
+	for relPath, content := range originals {
+		fullPath := g.FullPath(relPath)
+		var err error
+		if content == nil {
+			if _, statErr := os.Lstat(fullPath); statErr != nil {
+				continue // the failed write didn't create it
+			}
+			err = os.Remove(fullPath)
+		} else {
+			err = os.WriteFile(fullPath, content, 0644)
+		}
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		n++
+	}
+
+	// The backups are of files that got their content back
+	if newDirs[g.backupDir] {
+		if err := os.RemoveAll(g.FullPath(g.backupDir)); err != nil {
+			errs = append(errs, err.Error())
+		}
+		g.backups = nil
+	}
+
+	// Deepest first, so a directory is empty by the time it is removed
+	dirs := make([]string, 0, len(newDirs))
+	for dir := range newDirs {
+		dirs = append(dirs, dir)
+	}
+	sort.Slice(dirs, func(i, j int) bool { return len(dirs[i]) > len(dirs[j]) })
+	for _, dir := range dirs {
+		if err := os.Remove(g.FullPath(dir)); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err.Error())
+		}
+	}
+
+	if len(errs) > 0 {
+		return fmt.Errorf("%w; rollback failed: %s", cause, strings.Join(errs, "; "))
+	}
+	return fmt.Errorf("%w; rolled back %d file(s)", cause, n)
+}
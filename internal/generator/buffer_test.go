@@ -0,0 +1,109 @@
+package generator
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestGenerator_Buffered_Flush tests that buffered files are written by Flush only.
+func TestGenerator_Buffered_Flush(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "generator-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	gen := NewGenerator(testFS, tmpDir)
+	gen.SetBuffered(true)
+
+	if err := gen.EnsureDir("empty"); err != nil {
+		t.Fatalf("EnsureDir() error = %v", err)
+	}
+	if err := gen.GenerateFileFromString("nested/main.go", "package main\n"); err != nil {
+		t.Fatalf("GenerateFileFromString() error = %v", err)
+	}
+	if err := gen.WriteFile("notes.txt", "notes"); err != nil {
+		t.Fatalf("WriteFile() error = %v", err)
+	}
+
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 0 {
+		t.Errorf("Buffered generator should not write before Flush, found %d entries", len(entries))
+	}
+	if got := len(gen.Result().FilesCreated); got != 2 {
+		t.Errorf("FilesCreated should track 2 buffered files, got %d", got)
+	}
+
+	if err := gen.Flush(); err != nil {
+		t.Fatalf("Flush() error = %v", err)
+	}
+	for _, path := range []string{"nested/main.go", "notes.txt"} {
+		if _, err := os.Stat(filepath.Join(tmpDir, path)); err != nil {
+			t.Errorf("Flush() should write %s: %v", path, err)
+		}
+	}
+	if !gen.DirExists("empty") {
+		t.Error("Flush() should create the buffered directory")
+	}
+}
+
+// TestGenerator_Buffered_InvalidGo tests that Flush writes nothing when a Go file doesn't parse.
+func TestGenerator_Buffered_InvalidGo(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "generator-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	gen := NewGenerator(testFS, tmpDir)
+	gen.SetBuffered(true)
+	gen.GenerateFileFromString("good.go", "package good\n")
+	gen.GenerateFileFromString("bad/bad.go", "package bad\n\nfunc {\n")
+
+	err = gen.Flush()
+	if err == nil || !strings.Contains(err.Error(), "bad/bad.go is not valid Go") {
+		t.Fatalf("Flush() error = %v, want the invalid file named", err)
+	}
+	entries, _ := os.ReadDir(tmpDir)
+	if len(entries) != 0 {
+		t.Errorf("Flush() should write nothing when validation fails, found %d entries", len(entries))
+	}
+}
+
+// TestGenerator_Buffered_Rollback tests that a failed write restores the files written before it.
+func TestGenerator_Buffered_Rollback(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "generator-test-*")
+	if err != nil {
+		t.Fatalf("Failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	existingPath := filepath.Join(tmpDir, "existing.txt")
+	if err := os.WriteFile(existingPath, []byte("original"), 0644); err != nil {
+		t.Fatalf("Failed to create existing file: %v", err)
+	}
+	// A file where a directory is needed makes the last write fail
+	if err := os.WriteFile(filepath.Join(tmpDir, "blocker"), []byte("x"), 0644); err != nil {
+		t.Fatalf("Failed to create blocker file: %v", err)
+	}
+
+	gen := NewGenerator(testFS, tmpDir)
+	gen.SetBuffered(true)
+	gen.SetForceOverwrite(true)
+	gen.GenerateFileFromString("existing.txt", "replaced")
+	gen.GenerateFileFromString("new/dir/file.txt", "new")
+	gen.GenerateFileFromString("blocker/file.txt", "fails")
+
+	err = gen.Flush()
+	if err == nil || !strings.Contains(err.Error(), "rolled back") {
+		t.Fatalf("Flush() error = %v, want a rolled back write failure", err)
+	}
+	if data, _ := os.ReadFile(existingPath); string(data) != "original" {
+		t.Errorf("existing.txt = %q, want its original content restored", string(data))
+	}
+	if gen.DirExists("new") {
+		t.Error("Flush() should remove the directories it created")
+	}
+}
@@ -1,6 +1,8 @@
 package generator
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 
 	"github.com/dbb1dev/go-mcp/internal/types"
@@ -15,10 +17,35 @@ type ProjectData struct {
 	ModulePath string
 	// DatabaseType is sqlite, postgres, or mysql.
 	DatabaseType string
+	// ProjectType is web or api.
+	ProjectType string
 	// WithAuth enables authentication scaffolding.
 	WithAuth bool
 	// WithUserManagement enables admin user management.
 	WithUserManagement bool
+	// Navigation is none, boost, or unpoly.
+	Navigation string
+	// WithDarkMode enables the color scheme toggle and the class-based dark variant.
+	WithDarkMode bool
+	// CSSPipeline is tailwind, standalone, daisyui or postcss.
+	CSSPipeline string
+	// JSBundler is none, esbuild or vite.
+	JSBundler string
+	// IconSet is heroicons or lucide.
+	IconSet string
+	// WithNPM is set when the project installs its frontend toolchain from package.json:
+	// with the daisyui and postcss CSS pipelines and with a JS bundler.
+	WithNPM bool
+	// WithHTTPCaching enables the ETag and Cache-Control middleware.
+	WithHTTPCaching bool
+	// WithOtel enables OpenTelemetry tracing.
+	WithOtel bool
+	// WithDiagnostics enables the pprof and expvar admin routes.
+	WithDiagnostics bool
+	// ErrorReporting is none or sentry.
+	ErrorReporting string
+	// DI is imperative or wire.
+	DI string
 }
 
 // NewProjectData creates ProjectData from ScaffoldProjectInput.
@@ -27,14 +54,61 @@ func NewProjectData(input types.ScaffoldProjectInput) ProjectData {
 	if dbType == "" {
 		dbType = "sqlite"
 	}
+	projectType := input.ProjectType
+	if projectType == "" {
+		projectType = "web"
+	}
+	navigation := input.Navigation
+	if navigation == "" {
+		navigation = "none"
+	}
+	errorReporting := input.ErrorReporting
+	if errorReporting == "" {
+		errorReporting = "none"
+	}
+	di := input.DI
+	if di == "" {
+		di = "imperative"
+	}
+	cssPipeline := input.CSSPipeline
+	if cssPipeline == "" {
+		cssPipeline = "tailwind"
+	}
+	jsBundler := input.JSBundler
+	if jsBundler == "" {
+		jsBundler = "none"
+	}
+	iconSet := input.IconSet
+	if iconSet == "" {
+		iconSet = DefaultIconSet
+	}
 	return ProjectData{
-		ProjectName:  input.ProjectName,
-		ModulePath:   input.ModulePath,
-		DatabaseType: dbType,
-		WithAuth:     input.WithAuth,
+		ProjectName:        input.ProjectName,
+		ModulePath:         input.ModulePath,
+		DatabaseType:       dbType,
+		ProjectType:        projectType,
+		WithAuth:           input.WithAuth,
+		WithUserManagement: input.WithUserManagement,
+		Navigation:         navigation,
+		WithDarkMode:       input.WithDarkMode,
+		CSSPipeline:        cssPipeline,
+		JSBundler:          jsBundler,
+		IconSet:            iconSet,
+		WithNPM:            UsesNPM(cssPipeline, jsBundler),
+		WithHTTPCaching:    input.WithHTTPCaching,
+		WithOtel:           input.WithOtel,
+		WithDiagnostics:    input.WithDiagnostics,
+		ErrorReporting:     errorReporting,
+		DI:                 di,
 	}
 }
 
+// UsesNPM reports whether a project with the given CSS pipeline and JS bundler installs
+// its frontend toolchain from package.json.
+func UsesNPM(cssPipeline, jsBundler string) bool {
+	return cssPipeline == "daisyui" || cssPipeline == "postcss" || (jsBundler != "" && jsBundler != "none")
+}
+
 // FieldData is the template data for a model field.
 type FieldData struct {
 	// Name is the field name in PascalCase.
@@ -57,6 +131,21 @@ type FieldData struct {
 	Options []string
 	// HasOptions indicates if the field has predefined options.
 	HasOptions bool
+	// InlineEdit indicates the field can be edited in place from the list view.
+	InlineEdit bool
+	// IsEmbedded indicates the field is an embedded value type stored as prefixed columns.
+	IsEmbedded bool
+	// EmbeddedFields are the fields of the embedded value type.
+	EmbeddedFields []FieldData
+	// EmbeddedTypeExists indicates the value type is already declared in the models package and is reused.
+	EmbeddedTypeExists bool
+	// Step is the index of the form step showing the field, in multi-step forms.
+	Step int
+	// Min and Max bound the value of a number field or the length of a string field; empty when unset.
+	Min string
+	Max string
+	// Pattern is the regular expression a string field must match as a whole.
+	Pattern string
 }
 
 // NewFieldData creates FieldData from a FieldDef.
@@ -76,6 +165,33 @@ func NewFieldData(field types.FieldDef) FieldData {
 		formType = inferFormType(field.Type)
 	}
 
+	if len(field.Embedded) > 0 {
+		gormTags := "embedded;embeddedPrefix:" + utils.ToSnakeCase(field.Name) + "_"
+		if field.GORMTags != "" {
+			gormTags += ";" + field.GORMTags
+		}
+		embedded := make([]FieldData, len(field.Embedded))
+		for i, sub := range field.Embedded {
+			embedded[i] = NewFieldData(types.FieldDef{
+				Name:     sub.Name,
+				Type:     sub.Type,
+				JSONTag:  sub.JSONTag,
+				Required: sub.Required,
+				Label:    sub.Label,
+			})
+		}
+		return FieldData{
+			Name:           field.Name,
+			Type:           field.Type,
+			GORMTags:       gormTags,
+			JSONName:       jsonTag,
+			FormType:       "fieldset",
+			Label:          label,
+			IsEmbedded:     true,
+			EmbeddedFields: embedded,
+		}
+	}
+
 	return FieldData{
 		Name:       field.Name,
 		Type:       field.Type,
@@ -87,9 +203,20 @@ func NewFieldData(field types.FieldDef) FieldData {
 		Label:      label,
 		Options:    field.Options,
 		HasOptions: len(field.Options) > 0,
+		Min:        formatBound(field.Min),
+		Max:        formatBound(field.Max),
+		Pattern:    field.Pattern,
 	}
 }
 
+// formatBound formats a min or max rule as a Go and HTML number, or "" when unset.
+func formatBound(bound *float64) string {
+	if bound == nil {
+		return ""
+	}
+	return strconv.FormatFloat(*bound, 'f', -1, 64)
+}
+
 // NewFieldDataList creates a list of FieldData from FieldDefs.
 func NewFieldDataList(fields []types.FieldDef) []FieldData {
 	result := make([]FieldData, len(fields))
@@ -156,10 +283,69 @@ type DomainData struct {
 	WithSearch bool
 	// Layout specifies the view layout: dashboard, base, auth, none. Defaults to "dashboard".
 	Layout string
-	// RouteGroup specifies the middleware context: public, authenticated, admin. Defaults to "public".
+	// RouteGroup specifies the middleware context: public, authenticated, admin or api_vN. Defaults to "public".
 	RouteGroup string
+	// APIVersion is the version of an api_vN route group (e.g., "v1"), empty otherwise.
+	APIVersion string
+	// APIPrefix is the mount prefix of the API version (e.g., "/api/v1"), empty otherwise.
+	APIPrefix string
+	// WithVersionedDTO generates a response DTO frozen for APIVersion.
+	WithVersionedDTO bool
 	// FormStyle specifies how forms are displayed: modal or page. Defaults to "modal".
 	FormStyle string
+	// Icon is the icon of the sidebar link and empty states, empty for the defaults.
+	Icon string
+	// Steps for template compatibility; multi-step forms come from scaffold_form.
+	Steps []FormStepData
+	// WithOptimisticLocking adds a version column checked and incremented on update.
+	WithOptimisticLocking bool
+	// WithObservability instruments the service with OpenTelemetry spans and metrics.
+	WithObservability bool
+	// WithModeration adds a moderation status, a moderation queue and approve/reject endpoints.
+	WithModeration bool
+	// Filters are the list filters shown above the list view.
+	Filters []FilterData
+	// WithBulkActions adds row selection, a bulk toolbar and a bulk endpoint.
+	WithBulkActions bool
+	// BulkActions are the custom bulk actions, next to the built-in delete and export.
+	BulkActions []BulkActionData
+	// WithScheduling adds publish/unpublish dates and a publish state flipped by the scheduler.
+	WithScheduling bool
+	// WithCache generates a caching decorator around the repository.
+	WithCache bool
+	// TenantScoped adds a TenantID column; the tenancy callbacks scope the domain's queries by it.
+	TenantScoped bool
+	// WithRealtime publishes realtime events from the service and reloads the list view on them.
+	WithRealtime bool
+	// WithTests generates repository tests against in-memory SQLite.
+	WithTests bool
+	// OwnedByUser adds a UserID owner and limits the queries of the session user to their records.
+	OwnedByUser bool
+	// Hooks are the GORM hooks of the model, which dispatch domain events to the service's handlers.
+	Hooks []HookData
+	// FullTextSearch replaces the LIKE search with an FTS5 table (SQLite) or a tsvector column (Postgres).
+	FullTextSearch bool
+	// SearchFields are the fields matched by full-text search, in order of weight.
+	SearchFields []SearchFieldData
+	// Messages collects the translation keys of the views in projects with i18n; nil renders plain text.
+	Messages *Messages
+	// WithLogging passes the project's *slog.Logger to the repository, service and controller constructors.
+	WithLogging bool
+	// WithTestContainers opens the database of repository tests with internal/testutil, which can
+	// run them on a Postgres or MySQL container.
+	WithTestContainers bool
+	// WithSEO applies the [meta] of config/en/pages/{SEOPage}.toml to the routes and titles the
+	// show page after the record, in projects with the internal/web/seo package.
+	WithSEO bool
+	// WithBreadcrumbs starts the breadcrumb trail of the dashboard layout at the list page and
+	// labels the record's crumb after it, in projects with the internal/web/breadcrumbs package.
+	WithBreadcrumbs bool
+	// SEOPage is the name of the page file of the domain's [meta] defaults (e.g., "products").
+	SEOPage string
+	// SEOTitleField and SEODescriptionField are the string fields of a record's page title and
+	// description, empty when the model has none.
+	SEOTitleField       string
+	SEODescriptionField string
 }
 
 // NewDomainData creates DomainData from ScaffoldDomainInput and module path.
@@ -191,28 +377,327 @@ func NewDomainData(input types.ScaffoldDomainInput, modulePath string) DomainDat
 	// Default form style to "modal"
 	formStyle := input.GetFormStyle()
 
+	// Mark fields that are editable in place from the list view
+	fields := NewFieldDataList(input.Fields)
+	for _, name := range input.InlineEditFields() {
+		for i := range fields {
+			if fields[i].Name == name {
+				fields[i].InlineEdit = true
+			}
+		}
+	}
+
+	// Versioned API route groups are mounted under /api/vN
+	apiVersion := utils.APIVersionFromRouteGroup(routeGroup)
+	apiPrefix := ""
+	if apiVersion != "" {
+		apiPrefix = "/api/" + apiVersion
+	}
+
+	tableName := input.TableName
+	if tableName == "" {
+		tableName = utils.ToTableName(input.DomainName)
+	}
+
 	urlPath := utils.ToURLPath(input.DomainName)
 	return DomainData{
-		ModulePath:           modulePath,
-		DomainName:           input.DomainName,
-		ModelName:            utils.ToModelName(input.DomainName),
-		PackageName:          utils.ToPackageName(input.DomainName),
-		VariableName:         utils.ToVariableName(input.DomainName),
-		TableName:            utils.ToTableName(input.DomainName),
-		URLPath:              urlPath,
-		URLPathSegment:       strings.TrimPrefix(urlPath, "/"),
-		Fields:               NewFieldDataList(input.Fields),
-		Relationships:        relationships,
-		HasRelationships:     len(relationships) > 0,
-		PreloadRelationships: preloadRels,
-		WithSoftDelete:       input.GetWithSoftDelete(),
-		WithCrudViews:        withCrudViews,
-		WithPagination:       withCrudViews, // Enable pagination when CRUD views are generated
-		WithSearch:           withCrudViews, // Enable search when CRUD views are generated
-		Layout:               layout,
-		RouteGroup:           routeGroup,
-		FormStyle:            formStyle,
+		ModulePath:            modulePath,
+		DomainName:            input.DomainName,
+		ModelName:             utils.ToModelName(input.DomainName),
+		PackageName:           utils.ToPackageName(input.DomainName),
+		VariableName:          utils.ToVariableName(input.DomainName),
+		TableName:             tableName,
+		URLPath:               urlPath,
+		URLPathSegment:        strings.TrimPrefix(urlPath, "/"),
+		Fields:                fields,
+		Relationships:         relationships,
+		HasRelationships:      len(relationships) > 0,
+		PreloadRelationships:  preloadRels,
+		WithSoftDelete:        input.GetWithSoftDelete(),
+		WithCrudViews:         withCrudViews,
+		WithPagination:        withCrudViews, // Enable pagination when CRUD views are generated
+		WithSearch:            withCrudViews, // Enable search when CRUD views are generated
+		Layout:                layout,
+		RouteGroup:            routeGroup,
+		APIVersion:            apiVersion,
+		APIPrefix:             apiPrefix,
+		WithVersionedDTO:      input.WithVersionedDTO && apiVersion != "",
+		FormStyle:             formStyle,
+		Icon:                  input.Icon,
+		WithOptimisticLocking: input.WithOptimisticLocking,
+		WithObservability:     input.WithObservability,
+		WithModeration:        input.WithModeration,
+		Filters:               NewFilterDataList(input.Filters, fields),
+		WithBulkActions:       input.GetWithBulkActions(),
+		BulkActions:           NewBulkActionDataList(input.BulkActions, fields),
+		WithScheduling:        input.WithScheduling,
+		WithCache:             input.WithCache,
+		TenantScoped:          input.TenantScoped,
+		WithRealtime:          input.WithRealtime,
+		OwnedByUser:           input.GetOwnedByUser(),
+		WithTests:             input.WithTests,
+		Hooks:                 NewHookDataList(input.DomainName, input.WithHooks),
+		FullTextSearch:        input.GetSearch() == "fulltext",
+		SearchFields:          NewSearchFieldDataList(input.SearchFields, fields),
+		SEOPage:               utils.ToTableName(input.DomainName),
+		SEOTitleField:         seoField(fields, "Title", "Name", "Subject", "Label"),
+		SEODescriptionField:   seoField(fields, "Description", "Summary", "Excerpt", "Bio"),
+	}
+}
+
+// seoField returns the first of names that is a string field, or "".
+func seoField(fields []FieldData, names ...string) string {
+	for _, name := range names {
+		for _, field := range fields {
+			if field.Name == name && field.Type == "string" && !field.IsEmbedded {
+				return name
+			}
+		}
 	}
+	return ""
+}
+
+// SearchFieldData is the template data for a field matched by full-text search.
+type SearchFieldData struct {
+	// Name is the model field name (e.g., "Title").
+	Name string
+	// Column is the database column (e.g., "title").
+	Column string
+	// Weight is the Postgres tsvector weight: A for the first field, then B, C and D.
+	Weight string
+}
+
+// NewSearchFieldDataList creates SearchFieldData for the named fields, in order.
+// Without names, all string fields except images are searched.
+func NewSearchFieldDataList(names []string, fields []FieldData) []SearchFieldData {
+	if len(names) == 0 {
+		for _, field := range fields {
+			if field.Type == "string" && !field.IsEmbedded && field.FormType != "image" {
+				names = append(names, field.Name)
+			}
+		}
+	}
+	var result []SearchFieldData
+	for _, name := range names {
+		for _, field := range fields {
+			if field.Name == name && !field.IsEmbedded {
+				weight := "D"
+				if i := len(result); i < 3 {
+					weight = string(rune('A' + i))
+				}
+				result = append(result, SearchFieldData{
+					Name:   field.Name,
+					Column: utils.ToSnakeCase(field.Name),
+					Weight: weight,
+				})
+				break
+			}
+		}
+	}
+	return result
+}
+
+// BulkActionData is the template data for a custom bulk action.
+type BulkActionData struct {
+	// Name is the action name sent by the toolbar (e.g., "mark_shipped").
+	Name string
+	// MethodName is the service method (e.g., "BulkMarkShipped").
+	MethodName string
+	// Label is the button label.
+	Label string
+	// Field is the name of the field the action sets (e.g., "Status").
+	Field string
+	// Column is the database column of the field (e.g., "status").
+	Column string
+	// Value is the Go literal the field is set to (e.g., `"shipped"`, "true").
+	Value string
+	// Confirm is the confirmation prompt, if any.
+	Confirm string
+}
+
+// NewBulkActionData creates BulkActionData for an action on field.
+func NewBulkActionData(action types.BulkActionDef, field FieldData) BulkActionData {
+	label := action.Label
+	if label == "" {
+		label = utils.ToLabel(action.Name)
+	}
+
+	value := action.Value
+	switch field.Type {
+	case "string":
+		value = strconv.Quote(value)
+	case "bool":
+		b, _ := strconv.ParseBool(value)
+		value = strconv.FormatBool(b)
+	}
+
+	return BulkActionData{
+		Name:       action.Name,
+		MethodName: "Bulk" + utils.ToPascalCase(action.Name),
+		Label:      label,
+		Field:      field.Name,
+		Column:     utils.ToSnakeCase(field.Name),
+		Value:      value,
+		Confirm:    action.Confirm,
+	}
+}
+
+// NewBulkActionDataList creates BulkActionData for the actions on known fields.
+func NewBulkActionDataList(actions []types.BulkActionDef, fields []FieldData) []BulkActionData {
+	var result []BulkActionData
+	for _, action := range actions {
+		for _, field := range fields {
+			if field.Name == action.Field && !field.IsEmbedded {
+				result = append(result, NewBulkActionData(action, field))
+				break
+			}
+		}
+	}
+	return result
+}
+
+// hookTimings describes when each GORM hook runs, by its with_hooks name.
+var hookTimings = map[string]string{
+	"before_save":   "before %s is saved",
+	"before_create": "before %s is created",
+	"after_create":  "after %s is created",
+	"before_update": "before %s is updated",
+	"after_update":  "after %s is updated",
+	"after_save":    "after %s is saved",
+	"before_delete": "before %s is deleted",
+	"after_delete":  "after %s is deleted",
+	"after_find":    "after %s is loaded",
+}
+
+// HookData is the template data for a GORM hook that dispatches a domain event.
+type HookData struct {
+	// Name is the hook name (e.g., "before_create").
+	Name string
+	// Method is the GORM hook method (e.g., "BeforeCreate").
+	Method string
+	// Event is the dispatched event (e.g., "product.before_create").
+	Event string
+	// Handler is the handler function in the service package (e.g., "onBeforeCreate").
+	Handler string
+	// When describes when the hook runs (e.g., "before the Product is created").
+	When string
+}
+
+// NewHookDataList creates HookData for the named hooks of a domain, skipping unknown hooks.
+func NewHookDataList(domainName string, hooks []string) []HookData {
+	var result []HookData
+	for _, name := range hooks {
+		timing, ok := hookTimings[name]
+		if !ok {
+			continue
+		}
+		method := utils.ToPascalCase(name)
+		result = append(result, HookData{
+			Name:    name,
+			Method:  method,
+			Event:   utils.ToSnakeCase(domainName) + "." + name,
+			Handler: "on" + method,
+			When:    fmt.Sprintf(timing, "the "+utils.ToModelName(domainName)),
+		})
+	}
+	return result
+}
+
+// FilterData is the template data for a list filter.
+type FilterData struct {
+	// Name is the filter field name in List{Model}Filter (e.g., "PriceGte").
+	Name string
+	// Field is the name of the filtered model field (e.g., "Price").
+	Field string
+	// Type is the filter value type: the field type without pointer (e.g., "float64", "time.Time").
+	Type string
+	// Column is the database column (e.g., "price").
+	Column string
+	// Param is the query parameter (e.g., "price_gte").
+	Param string
+	// Operator is the comparison: eq, contains, gt, gte, lt, lte.
+	Operator string
+	// Widget is the filter control: text, select, number, date, checkbox.
+	Widget string
+	// Options are the choices of a select widget.
+	Options []string
+	// Label is the display label.
+	Label string
+}
+
+// FilterDefaults returns the operator and widget used when a filter on a field
+// of fieldType leaves them out. Fields with options default to a select and
+// times to a "from" date.
+func FilterDefaults(fieldType string, hasOptions bool) (operator, widget string) {
+	switch {
+	case hasOptions || fieldType == "bool":
+		return "eq", "select"
+	case fieldType == "string":
+		return "contains", "text"
+	case fieldType == "time.Time" || fieldType == "*time.Time":
+		return "gte", "date"
+	default:
+		return "eq", "number"
+	}
+}
+
+// NewFilterData creates FilterData for a filter on field.
+func NewFilterData(filter types.FilterDef, field FieldData) FilterData {
+	operator, widget := FilterDefaults(field.Type, len(filter.Options) > 0 || field.HasOptions)
+	if filter.Operator != "" {
+		operator = filter.Operator
+	}
+	if filter.Widget != "" {
+		widget = filter.Widget
+	}
+	options := filter.Options
+	if len(options) == 0 {
+		options = field.Options
+	}
+
+	label := filter.Label
+	if label == "" {
+		label = field.Label
+		switch operator {
+		case "gt", "gte":
+			label += " from"
+		case "lt", "lte":
+			label += " to"
+		}
+	}
+
+	// eq and contains filters use the field name; ranges get an operator suffix
+	name, param := field.Name, field.JSONName
+	if operator != "eq" && operator != "contains" {
+		name += utils.ToPascalCase(operator)
+		param += "_" + operator
+	}
+
+	return FilterData{
+		Name:     name,
+		Field:    field.Name,
+		Type:     strings.TrimPrefix(field.Type, "*"),
+		Column:   utils.ToSnakeCase(field.Name),
+		Param:    param,
+		Operator: operator,
+		Widget:   widget,
+		Options:  options,
+		Label:    label,
+	}
+}
+
+// NewFilterDataList creates FilterData for the filters on known fields.
+func NewFilterDataList(filters []types.FilterDef, fields []FieldData) []FilterData {
+	var result []FilterData
+	for _, filter := range filters {
+		for _, field := range fields {
+			if field.Name == filter.Field && !field.IsEmbedded {
+				result = append(result, NewFilterData(filter, field))
+				break
+			}
+		}
+	}
+	return result
 }
 
 // ColumnData is the template data for a table column.
@@ -289,6 +774,8 @@ type ViewData struct {
 	WithSorting bool
 	// WithBulkActions enables bulk actions (for table views).
 	WithBulkActions bool
+	// WithRealtime reloads the list on realtime events (for list views).
+	WithRealtime bool
 	// WithSoftDelete indicates if soft delete is enabled.
 	WithSoftDelete bool
 	// RowActions is the list of row actions (for table views).
@@ -305,6 +792,20 @@ type ViewData struct {
 	Layout string
 	// FormStyle specifies how forms are displayed: modal or page. Defaults to "modal".
 	FormStyle string
+	// Steps for template compatibility; multi-step forms come from scaffold_form.
+	Steps []FormStepData
+	// Icon for template compatibility.
+	Icon string
+	// WithOptimisticLocking indicates the model has a version column for optimistic locking.
+	WithOptimisticLocking bool
+	// Filters are the list filters shown above the list view.
+	Filters []FilterData
+	// BulkActions are the custom bulk actions of the domain.
+	BulkActions []BulkActionData
+	// FullTextSearch shows highlighted search snippets (scaffold_domain lists only).
+	FullTextSearch bool
+	// WithScheduling shows publish state badges and publish date inputs (scaffold_domain views only).
+	WithScheduling bool
 }
 
 // FormData is the template data for form scaffolding.
@@ -341,6 +842,24 @@ type FormData struct {
 	IsEdit bool
 	// FormStyle specifies how forms are displayed: modal or page. Defaults to "modal".
 	FormStyle string
+	// WithOptimisticLocking indicates the model has a version column for optimistic locking.
+	WithOptimisticLocking bool
+	// WithScheduling for template compatibility.
+	WithScheduling bool
+	// Steps are the sections of a multi-step form; empty for a single form.
+	Steps []FormStepData
+	// LastStep is the index of the last step, which shows the submit button.
+	LastStep int
+}
+
+// FormStepData is the template data for a step of a multi-step form.
+type FormStepData struct {
+	// Title is the step title.
+	Title string
+	// Description is the optional help under the progress indicator.
+	Description string
+	// ErrorKeys are the error keys of the step's fields, which open the form on the step.
+	ErrorKeys []string
 }
 
 // NewFormData creates FormData from ScaffoldFormInput.
@@ -350,6 +869,7 @@ func NewFormData(input types.ScaffoldFormInput, modulePath string) FormData {
 		method = "PUT"
 	}
 	urlPath := utils.ToURLPath(input.Domain)
+	fields, steps := NewFormStepDataList(input.Steps, NewFieldDataList(input.Fields))
 	return FormData{
 		ModulePath:     modulePath,
 		DomainName:     input.Domain,
@@ -360,7 +880,9 @@ func NewFormData(input types.ScaffoldFormInput, modulePath string) FormData {
 		URLPathSegment: strings.TrimPrefix(urlPath, "/"),
 		FormName:       input.FormName,
 		Action:         input.Action,
-		Fields:         NewFieldDataList(input.Fields),
+		Fields:         fields,
+		Steps:          steps,
+		LastStep:       len(steps) - 1,
 		SubmitEndpoint: input.SubmitEndpoint,
 		Method:         method,
 		IsCreate:       input.Action == "create",
@@ -369,6 +891,39 @@ func NewFormData(input types.ScaffoldFormInput, modulePath string) FormData {
 	}
 }
 
+// NewFormStepDataList creates FormStepData for the steps and returns the fields in step order,
+// each marked with its step. Without steps, the fields are returned unchanged.
+func NewFormStepDataList(steps []types.FormStepDef, fields []FieldData) ([]FieldData, []FormStepData) {
+	if len(steps) == 0 {
+		return fields, nil
+	}
+	byName := make(map[string]FieldData, len(fields))
+	for _, field := range fields {
+		byName[field.Name] = field
+	}
+	ordered := make([]FieldData, 0, len(fields))
+	result := make([]FormStepData, len(steps))
+	for i, step := range steps {
+		result[i] = FormStepData{Title: step.Title, Description: step.Description}
+		for _, name := range step.Fields {
+			field, ok := byName[name]
+			if !ok {
+				continue
+			}
+			field.Step = i
+			ordered = append(ordered, field)
+			if field.IsEmbedded {
+				for _, sub := range field.EmbeddedFields {
+					result[i].ErrorKeys = append(result[i].ErrorKeys, field.JSONName+"_"+sub.JSONName)
+				}
+			} else {
+				result[i].ErrorKeys = append(result[i].ErrorKeys, field.JSONName)
+			}
+		}
+	}
+	return ordered, result
+}
+
 // TableData is the template data for table scaffolding.
 type TableData struct {
 	// ModulePath is the Go module path.
@@ -457,6 +1012,20 @@ type ModalData struct {
 	IsSheet bool
 	// IsConfirm is true for confirm modals.
 	IsConfirm bool
+	// ElementID is the id prefix of the modal's elements (e.g., "product-create").
+	ElementID string
+	// Domain is the domain whose form the modal shows, empty for standalone modals.
+	Domain string
+	// Action is the domain form: create or edit.
+	Action string
+	// IsEdit is true for the edit form.
+	IsEdit bool
+	// ModelName is the model of Domain.
+	ModelName string
+	// DomainURL is the URL path of Domain (e.g., "/products").
+	DomainURL string
+	// ListID is the id of Domain's list, reloaded once the form is saved.
+	ListID string
 }
 
 // NewModalData creates ModalData from ScaffoldModalInput.
@@ -473,6 +1042,7 @@ func NewModalData(modulePath string, input types.ScaffoldModalInput) ModalData {
 		IsDialog:       input.ModalType == "dialog",
 		IsSheet:        input.ModalType == "sheet",
 		IsConfirm:      input.ModalType == "confirm",
+		ElementID:      utils.ToKebabCase(input.ModalName),
 	}
 }
 
@@ -548,8 +1118,67 @@ type PageData struct {
 	WithSearch bool
 	// EmptyStateMessage for template compatibility.
 	EmptyStateMessage string
+	// Relationships is an empty list for template compatibility.
+	Relationships []RelationshipData
+	// Filters is an empty list for template compatibility.
+	Filters []FilterData
+	// WithBulkActions for template compatibility.
+	WithBulkActions bool
+	// WithRealtime for template compatibility.
+	WithRealtime bool
+	// BulkActions is an empty list for template compatibility.
+	BulkActions []BulkActionData
+	// FullTextSearch for template compatibility.
+	FullTextSearch bool
+	// WithScheduling for template compatibility.
+	WithScheduling bool
 	// FormStyle specifies how forms are displayed: modal or page. Defaults to "modal".
 	FormStyle string
+	// Icon for template compatibility.
+	Icon string
+}
+
+// StaticPageData is the template data for the static page presets of scaffold_page
+// (about, pricing, contact, terms).
+type StaticPageData struct {
+	// ModulePath is the Go module path.
+	ModulePath string
+	// ProjectName is the project name, shown in the site header, footer and page text.
+	ProjectName string
+	// Preset is the page preset: about, pricing, contact or terms.
+	Preset string
+	// PageName is the snake_case page name, which names the Go, templ and TOML files.
+	PageName string
+	// FuncName is the handler name (e.g., "About").
+	FuncName string
+	// VariableName is the prefix of the page's unexported types and templates (e.g., "about").
+	VariableName string
+	// Route is the URL route.
+	Route string
+	// Title is the page title.
+	Title string
+	// Date is the generation date, shown as the last update of the terms.
+	Date string
+	// WithI18n reads the page file of the request's locale when it exists.
+	WithI18n bool
+	// WithSEO wraps the page routes in seo.Defaults, which applies the [meta] of the page file.
+	WithSEO bool
+}
+
+// BlogData is the template data for scaffold_blog.
+type BlogData struct {
+	// ModulePath is the Go module path.
+	ModulePath string
+	// Title is the title of the blog, shown on the list pages and in the feeds.
+	Title string
+	// Path is where the blog is mounted (e.g., "/blog").
+	Path string
+	// PostsPerPage is the page size of the list pages.
+	PostsPerPage int
+	// JoinTable is the many_to_many join table of posts and tags.
+	JoinTable string
+	// WithSEO gives the post pages the title, summary and og:type of the post.
+	WithSEO bool
 }
 
 // SectionData is the template data for a page section.
@@ -650,6 +1279,200 @@ type SeedData struct {
 	HasRelationships bool
 	// HasDistributions is true if there are distributions.
 	HasDistributions bool
+	// TenantScoped seeds each tenant when the seeder is called without one.
+	TenantScoped bool
+	// WithLogging passes slog.Default() to the repository constructors, which take a logger.
+	WithLogging bool
+	// Source is the CSV or JSON fixture file the seeder reads instead of generating data.
+	Source string
+	// KeyField is the field that identifies a fixture row, so seeding again updates the record.
+	KeyField string
+	// KeyColumn is the database column of KeyField.
+	KeyColumn string
+}
+
+// MockAPIData is the template data for the mock API server.
+type MockAPIData struct {
+	// ModulePath is the Go module path.
+	ModulePath string
+	// Port is the default port the mock server listens on.
+	Port int
+	// Count is the default number of fake records per domain.
+	Count int
+	// LatencyMS is the default artificial response delay in milliseconds.
+	LatencyMS int
+	// Domains are the domains served by the mock, built from their scaffold inputs.
+	Domains []DomainData
+}
+
+// TypeScriptData is the template data for the TypeScript types index.
+type TypeScriptData struct {
+	// Domains are the domains the index exports, built from their scaffold inputs.
+	Domains []DomainData
+}
+
+// ClientData is the template data for the generated API client package.
+type ClientData struct {
+	// ModulePath is the Go module path.
+	ModulePath string
+	// Domains are the domains the client covers, built from their scaffold inputs.
+	Domains []DomainData
+}
+
+// SearchIndexData is the template data for indexing a domain in a search engine.
+type SearchIndexData struct {
+	DomainData
+	// Engine is the default search engine: meilisearch or typesense.
+	Engine string
+	// SearchFields are the searchable fields; documents hold them plus the record ID.
+	SearchFields []FieldData
+}
+
+// FactoryData is the template data for a domain's test data factory.
+type FactoryData struct {
+	DomainData
+	// Parents are the belongs_to relationships of the model, other than belongs_to self.
+	Parents []FactoryParentData
+}
+
+// FactoryParentData is a belongs_to parent of a factory's model.
+type FactoryParentData struct {
+	// FieldName is the association field (e.g., "Category").
+	FieldName string
+	// ForeignKey is the foreign key field (e.g., "CategoryID").
+	ForeignKey string
+	// Model is the parent model (e.g., "Category").
+	Model string
+	// HasFactory is set when the parent has a factory, which Create uses for records without a
+	// parent. Parents without one (e.g., User of the auth system) must be set with the With method.
+	HasFactory bool
+}
+
+// TenancyData is the template data for the multi-tenancy subsystem.
+type TenancyData struct {
+	// ModulePath is the Go module path.
+	ModulePath string
+	// Resolution is how requests name their tenant: subdomain, header or both.
+	Resolution string
+	// ResolutionLabel describes Resolution in doc comments.
+	ResolutionLabel string
+	// Header is the default tenant header (e.g., "X-Tenant").
+	Header string
+	// Tenants are the tenants created by the tenant seeder.
+	Tenants []TenantSeedData
+}
+
+// TenantSeedData is a tenant created by the tenant seeder.
+type TenantSeedData struct {
+	// Slug identifies the tenant (e.g., "acme").
+	Slug string
+	// Name is the display name (e.g., "Acme").
+	Name string
+}
+
+// WebhooksData is the template data for the webhook subsystem.
+type WebhooksData struct {
+	// ModulePath is the Go module path.
+	ModulePath string
+	// MaxAttempts is how many times a delivery is tried before it is given up.
+	MaxAttempts int
+	// Providers are the inbound webhook receivers.
+	Providers []WebhookProviderData
+	// HasStripe, HasGitHub and HasGeneric report which providers are in Providers.
+	HasStripe  bool
+	HasGitHub  bool
+	HasGeneric bool
+	// WithCSRF exempts the receivers from the CSRF protection of web projects.
+	WithCSRF bool
+}
+
+// WebhookProviderData is an inbound webhook receiver.
+type WebhookProviderData struct {
+	// Name is the provider (stripe, github or generic); receivers are mounted at /webhooks/{name}.
+	Name string
+	// Label is the display name (e.g., "GitHub").
+	Label string
+	// SecretEnv is the environment variable holding the signing secret (e.g., "STRIPE_WEBHOOK_SECRET").
+	SecretEnv string
+	// Verifier is the function of the webhooks package that builds the provider's verifier (e.g., "StripeVerifier").
+	Verifier string
+}
+
+// WebhookDomainData is the template data for a domain service that emits webhook events.
+type WebhookDomainData struct {
+	// ModulePath is the Go module path.
+	ModulePath string
+	// PackageName is the domain package (e.g., "order").
+	PackageName string
+	// ModelName is the model struct name (e.g., "Order").
+	ModelName string
+	// VariableName is the model variable name (e.g., "order").
+	VariableName string
+	// EventPrefix prefixes the event types (e.g., "order" for "order.created").
+	EventPrefix string
+	// WithBulkActions emits deleted events for bulk deletes.
+	WithBulkActions bool
+}
+
+// SettingsData is the template data for the application settings subsystem.
+type SettingsData struct {
+	// ModulePath is the Go module path.
+	ModulePath string
+	// SiteName is the project name, the site name until it is saved on the settings page.
+	SiteName string
+}
+
+// I18nData is the template data for the i18n subsystem.
+type I18nData struct {
+	// ModulePath is the Go module path.
+	ModulePath string
+	// DefaultLocale is the locale used when a request matches no other (e.g., "en").
+	DefaultLocale string
+}
+
+// DeployData is the template data for deployment and CI files.
+type DeployData struct {
+	// ModulePath is the Go module path.
+	ModulePath string
+	// ProjectName is the project name, used for the image, database and config path.
+	ProjectName string
+	// ProjectType is "web" or "api"; API projects have no templ views or CSS to build.
+	ProjectType string
+	// DatabaseType is sqlite, postgres or mysql.
+	DatabaseType string
+	// WithRedis adds a Redis service and sets REDIS_URL.
+	WithRedis bool
+	// WithUploads stores uploaded images on the data volume.
+	WithUploads bool
+	// CSSPipeline is tailwind, standalone, daisyui or postcss; daisyui and postcss install
+	// the CSS toolchain from package.json and build with npm run build:css.
+	CSSPipeline string
+	// JSBundler is none, esbuild or vite; the bundlers build assets/dist with npm run build:js.
+	JSBundler string
+	// WithNPM is set when the project has a package.json to install.
+	WithNPM bool
+	// WithHealth is set when the project has the readiness endpoint (/readyz) of scaffold_health.
+	WithHealth bool
+	// WithTestContainers is set when the project has the testcontainers harness (internal/testutil),
+	// which adds a step running the repository tests on a database container.
+	WithTestContainers bool
+	// WithVolume is set when the app keeps data on disk (SQLite or uploads), which limits the
+	// kubernetes Deployment to one replica.
+	WithVolume bool
+	// AppName is the project name as a kubernetes resource or image name (e.g., "my-shop").
+	AppName string
+	// Host is the Ingress host of the kubernetes base.
+	Host string
+	// Environment is the kubernetes overlay being generated (e.g., "staging").
+	Environment string
+	// EnvironmentHost is the Ingress host of the overlay.
+	EnvironmentHost string
+}
+
+// HealthData is the template data for the health endpoints.
+type HealthData struct {
+	// ModulePath is the Go module path.
+	ModulePath string
 }
 
 // AuthData is the template data for auth scaffolding.
@@ -660,20 +1483,29 @@ type AuthData struct {
 	ProjectName string
 	// SessionType is cookie or jwt.
 	SessionType string
+	// ErrorReporting is none or sentry; with sentry, signed-in users are attached to reported errors.
+	ErrorReporting string
+	// WithDarkMode applies the color scheme of the toggle to the auth pages.
+	WithDarkMode bool
+	// CSSPipeline is tailwind, standalone, daisyui or postcss; daisyui loads the daisyUI CDN.
+	CSSPipeline string
+	// JSBundler is none, esbuild or vite; the bundlers load htmx from the assets/js bundle.
+	JSBundler string
 }
 
 // NewAuthData creates AuthData.
 func NewAuthData(modulePath, projectName string) AuthData {
 	return AuthData{
-		ModulePath:  modulePath,
-		ProjectName: projectName,
-		SessionType: "cookie", // default to cookie-based sessions
+		ModulePath:     modulePath,
+		ProjectName:    projectName,
+		SessionType:    "cookie", // default to cookie-based sessions
+		ErrorReporting: "none",
 	}
 }
 
 // RelationshipData is the template data for a model relationship.
 type RelationshipData struct {
-	// Type is the relationship type: belongs_to, has_one, has_many, many_to_many.
+	// Type is the relationship type: belongs_to, has_one, has_many, many_to_many, polymorphic.
 	Type string
 	// Model is the related model name in PascalCase (e.g., "User").
 	Model string
@@ -685,6 +1517,18 @@ type RelationshipData struct {
 	References string
 	// JoinTable is the join table name (for many_to_many).
 	JoinTable string
+	// JoinModel is the explicit join model name (for many_to_many with join fields, e.g., "ProjectUser").
+	JoinModel string
+	// JoinForeignKey is the join model column referencing the domain model (e.g., "ProjectID").
+	JoinForeignKey string
+	// JoinReferenceKey is the join model column referencing the related model (e.g., "UserID").
+	JoinReferenceKey string
+	// JoinFields are the extra columns of the join model.
+	JoinFields []FieldData
+	// NestedForm is true for has_many children edited as rows in the parent's form.
+	NestedForm bool
+	// NestedFields are the child fields shown in each nested form row.
+	NestedFields []FieldData
 	// OnDelete is the delete behavior.
 	OnDelete string
 	// Preload indicates if the relationship should be preloaded by default.
@@ -697,6 +1541,16 @@ type RelationshipData struct {
 	IsHasMany bool
 	// IsManyToMany is true for many_to_many relationships.
 	IsManyToMany bool
+	// IsPolymorphic is true for polymorphic relationships.
+	IsPolymorphic bool
+	// IsSelfReferential is true for belongs_to self relationships (ParentID/Parent/Children).
+	IsSelfReferential bool
+	// TypeColumn is the owner type field name (for polymorphic, e.g., "CommentableType").
+	TypeColumn string
+	// IndexName is the composite index on the owner columns (for polymorphic).
+	IndexName string
+	// Owners are the owner models of a polymorphic relationship.
+	Owners []PolymorphicOwnerData
 	// GORMTag is the complete GORM struct tag for the relationship.
 	GORMTag string
 	// ForeignKeyField is the FK field definition (for belongs_to).
@@ -724,9 +1578,21 @@ func NewRelationshipData(rel types.RelationshipDef, domainName string) Relations
 		onDelete = "CASCADE"
 	}
 
+	// belongs_to self builds a tree of the domain's own model
+	isSelf := rel.Type == "belongs_to" && strings.EqualFold(rel.Model, "self")
+
 	// Determine field name based on relationship type
 	switch rel.Type {
 	case "belongs_to":
+		if isSelf {
+			// belongs_to self: ParentID/Parent plus Children on the same model
+			modelName = utils.ToModelName(domainName)
+			fieldName = "Parent"
+			if foreignKey == "" {
+				foreignKey = "ParentID"
+			}
+			break
+		}
 		// belongs_to: field name is singular (e.g., "User")
 		if foreignKey == "" {
 			foreignKey = modelName + "ID"
@@ -745,14 +1611,32 @@ func NewRelationshipData(rel types.RelationshipDef, domainName string) Relations
 	case "many_to_many":
 		// many_to_many: field name is plural (e.g., "Tags")
 		fieldName = utils.Pluralize(modelName)
+	case "polymorphic":
+		// polymorphic: the model is the polymorphic name (e.g., "Commentable"),
+		// stored as CommentableID and CommentableType columns
+		foreignKey = modelName + "ID"
+	}
+
+	// many_to_many with join fields gets an explicit join model on a named join table
+	joinTable := rel.JoinTable
+	var joinModel, joinForeignKey, joinReferenceKey string
+	var joinFields []FieldData
+	if rel.Type == "many_to_many" && len(rel.JoinFields) > 0 {
+		if joinTable == "" {
+			joinTable = DefaultJoinTable(domainName, rel.Model)
+		}
+		joinModel = utils.ToModelName(domainName) + modelName
+		joinForeignKey = utils.ToModelName(domainName) + "ID"
+		joinReferenceKey = modelName + "ID"
+		joinFields = NewFieldDataList(rel.JoinFields)
 	}
 
 	// Build GORM tag
-	gormTag := buildGORMTag(rel.Type, foreignKey, references, rel.JoinTable, onDelete)
+	gormTag := buildGORMTag(rel.Type, foreignKey, references, joinTable, onDelete)
 
 	// Create FK field for belongs_to relationships
 	var fkField *FieldData
-	if rel.Type == "belongs_to" {
+	if rel.Type == "belongs_to" && !isSelf {
 		fkField = &FieldData{
 			Name:      foreignKey,
 			Type:      "uint",
@@ -771,23 +1655,51 @@ func NewRelationshipData(rel types.RelationshipDef, domainName string) Relations
 		displayField = "Name"
 	}
 
+	var typeColumn, indexName string
+	var owners []PolymorphicOwnerData
+	if rel.Type == "polymorphic" {
+		typeColumn = modelName + "Type"
+		indexName = "idx_" + utils.ToTableName(domainName) + "_" + utils.ToSnakeCase(modelName)
+		owners = NewPolymorphicOwnerDataList(rel.Models)
+	}
+
 	return RelationshipData{
-		Type:            rel.Type,
-		Model:           modelName,
-		FieldName:       fieldName,
-		ForeignKey:      foreignKey,
-		References:      references,
-		JoinTable:       rel.JoinTable,
-		OnDelete:        onDelete,
-		Preload:         preload,
-		IsBelongsTo:     rel.Type == "belongs_to",
-		IsHasOne:        rel.Type == "has_one",
-		IsHasMany:       rel.Type == "has_many",
-		IsManyToMany:    rel.Type == "many_to_many",
-		GORMTag:         gormTag,
-		ForeignKeyField: fkField,
-		DisplayField:    displayField,
+		Type:              rel.Type,
+		Model:             modelName,
+		FieldName:         fieldName,
+		ForeignKey:        foreignKey,
+		References:        references,
+		JoinTable:         joinTable,
+		JoinModel:         joinModel,
+		JoinForeignKey:    joinForeignKey,
+		JoinReferenceKey:  joinReferenceKey,
+		JoinFields:        joinFields,
+		NestedForm:        rel.NestedForm && rel.Type == "has_many",
+		OnDelete:          onDelete,
+		Preload:           preload,
+		IsBelongsTo:       rel.Type == "belongs_to" && !isSelf,
+		IsHasOne:          rel.Type == "has_one",
+		IsHasMany:         rel.Type == "has_many",
+		IsManyToMany:      rel.Type == "many_to_many",
+		IsPolymorphic:     rel.Type == "polymorphic",
+		IsSelfReferential: isSelf,
+		TypeColumn:        typeColumn,
+		IndexName:         indexName,
+		Owners:            owners,
+		GORMTag:           gormTag,
+		ForeignKeyField:   fkField,
+		DisplayField:      displayField,
+	}
+}
+
+// DefaultJoinTable returns the join table name used for a many_to_many relationship
+// without an explicit join_table: both table names in alphabetical order (e.g., "projects_users").
+func DefaultJoinTable(domainName, relatedModel string) string {
+	names := []string{utils.ToSnakeCase(utils.Pluralize(domainName)), utils.ToSnakeCase(utils.Pluralize(relatedModel))}
+	if names[0] > names[1] {
+		names[0], names[1] = names[1], names[0]
 	}
+	return names[0] + "_" + names[1]
 }
 
 // buildGORMTag builds the GORM struct tag for a relationship.
@@ -816,6 +1728,26 @@ func buildGORMTag(relType, foreignKey, references, joinTable, onDelete string) s
 	return strings.Join(parts, ";")
 }
 
+// PolymorphicOwnerData is the template data for an owner model of a polymorphic relationship.
+type PolymorphicOwnerData struct {
+	// Model is the owner model name in PascalCase (e.g., "Post").
+	Model string
+	// TypeValue is the value stored in the owner type column (the owner's table name, e.g., "posts").
+	TypeValue string
+}
+
+// NewPolymorphicOwnerDataList creates PolymorphicOwnerData for each owner model.
+func NewPolymorphicOwnerDataList(models []string) []PolymorphicOwnerData {
+	result := make([]PolymorphicOwnerData, len(models))
+	for i, model := range models {
+		result[i] = PolymorphicOwnerData{
+			Model:     utils.ToModelName(model),
+			TypeValue: utils.ToTableName(model),
+		}
+	}
+	return result
+}
+
 // NewRelationshipDataList creates a list of RelationshipData from RelationshipDefs.
 func NewRelationshipDataList(rels []types.RelationshipDef, domainName string) []RelationshipData {
 	result := make([]RelationshipData, len(rels))
@@ -857,6 +1789,21 @@ type WizardStepData struct {
 	IsSelect bool
 	// IsHasMany is true if this is a has_many step.
 	IsHasMany bool
+	// Condition is the show condition of the step, e.g. delivery == "ship".
+	// Empty when the step always shows.
+	Condition string
+	// ConditionField is the field of an earlier step the condition reads.
+	ConditionField string
+	// ConditionOp is the comparison of the condition: == or !=.
+	ConditionOp string
+	// ConditionValue is the value the answer is compared with.
+	ConditionValue string
+	// AlpineCondition is the condition as an Alpine.js expression, e.g. delivery === 'ship'.
+	AlpineCondition string
+	// TestSkipped is true if the generated walkthrough test skips this step.
+	TestSkipped bool
+	// TestNextStep is the step the walkthrough test goes to after this one.
+	TestNextStep int
 }
 
 // WizardData is the template data for wizard scaffolding.
@@ -896,6 +1843,10 @@ type WizardData struct {
 	SuccessRedirect string
 	// WithDrafts enables database draft persistence.
 	WithDrafts bool
+	// WithTests generates an integration test for the wizard.
+	WithTests bool
+	// WithLogging passes a logger to the domain repository and service in the wizard test.
+	WithLogging bool
 
 	// Feature flags based on step types
 	// HasSelectSteps is true if any step is a select type.
@@ -906,6 +1857,12 @@ type WizardData struct {
 	HasSummaryStep bool
 	// HasFormSteps is true if any step is a form type.
 	HasFormSteps bool
+	// HasConditions is true if any step has a condition.
+	HasConditions bool
+	// ConditionFields are the fields read by step conditions, kept in the wizard's Alpine state.
+	ConditionFields map[string]bool
+	// TestAnswers are the answers the walkthrough test gives to the condition fields.
+	TestAnswers map[string]string
 }
 
 // NewWizardData creates WizardData from a ScaffoldWizardInput.
@@ -922,6 +1879,7 @@ func NewWizardData(input types.ScaffoldWizardInput, modulePath string) WizardDat
 	hasHasManySteps := false
 	hasSummaryStep := false
 	hasFormSteps := false
+	conditionFields := make(map[string]bool)
 
 	for i, step := range input.Steps {
 		stepType := step.Type
@@ -963,6 +1921,44 @@ func NewWizardData(input types.ScaffoldWizardInput, modulePath string) WizardDat
 			IsSelect:       stepType == "select",
 			IsHasMany:      stepType == "has_many",
 		}
+
+		// Process the show condition
+		if field, op, value, err := utils.ParseWizardCondition(step.Condition); err == nil {
+			steps[i].Condition = strings.TrimSpace(step.Condition)
+			steps[i].ConditionField = field
+			steps[i].ConditionOp = op
+			steps[i].ConditionValue = value
+			steps[i].AlpineCondition = fmt.Sprintf("%s %s= '%s'", field, op, value)
+			conditionFields[field] = true
+		}
+	}
+
+	// The walkthrough test answers each condition field with the value of its first
+	// == condition, so it takes that branch and skips the steps of the others.
+	testAnswers := make(map[string]string)
+	for _, step := range steps {
+		if _, ok := testAnswers[step.ConditionField]; step.ConditionOp == "==" && !ok {
+			testAnswers[step.ConditionField] = step.ConditionValue
+		}
+	}
+	for field := range conditionFields {
+		if _, ok := testAnswers[field]; !ok {
+			testAnswers[field] = "test " + field
+		}
+	}
+	for i, step := range steps {
+		if step.Condition != "" {
+			steps[i].TestSkipped = (testAnswers[step.ConditionField] == step.ConditionValue) != (step.ConditionOp == "==")
+		}
+	}
+	for i := range steps {
+		steps[i].TestNextStep = len(steps)
+		for next := i + 1; next < len(steps); next++ {
+			if !steps[next].TestSkipped {
+				steps[i].TestNextStep = next + 1
+				break
+			}
+		}
 	}
 
 	// Determine success redirect
@@ -988,10 +1984,14 @@ func NewWizardData(input types.ScaffoldWizardInput, modulePath string) WizardDat
 		FormStyle:        input.GetFormStyle(),
 		SuccessRedirect:  successRedirect,
 		WithDrafts:       input.GetWithDrafts(),
+		WithTests:        input.WithTests,
 		HasSelectSteps:   hasSelectSteps,
 		HasHasManySteps:  hasHasManySteps,
 		HasSummaryStep:   hasSummaryStep,
 		HasFormSteps:     hasFormSteps,
+		HasConditions:    len(conditionFields) > 0,
+		ConditionFields:  conditionFields,
+		TestAnswers:      testAnswers,
 	}
 }
 
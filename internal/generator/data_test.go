@@ -1,6 +1,7 @@
 package generator
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/dbb1dev/go-mcp/internal/types"
@@ -191,6 +192,36 @@ func TestNewFieldData(t *testing.T) {
 	}
 }
 
+// TestNewFieldData_Embedded tests embedded value type fields.
+func TestNewFieldData_Embedded(t *testing.T) {
+	field := types.FieldDef{
+		Name: "ShippingAddress",
+		Type: "Address",
+		Embedded: []types.EmbeddedFieldDef{
+			{Name: "Street", Type: "string", Required: true},
+			{Name: "Zip", Type: "string"},
+		},
+	}
+
+	data := NewFieldData(field)
+
+	if !data.IsEmbedded {
+		t.Error("IsEmbedded = false, want true")
+	}
+	if data.GORMTags != "embedded;embeddedPrefix:shipping_address_" {
+		t.Errorf("GORMTags = %q, want %q", data.GORMTags, "embedded;embeddedPrefix:shipping_address_")
+	}
+	if data.FormType != "fieldset" {
+		t.Errorf("FormType = %q, want %q", data.FormType, "fieldset")
+	}
+	if len(data.EmbeddedFields) != 2 {
+		t.Fatalf("len(EmbeddedFields) = %d, want 2", len(data.EmbeddedFields))
+	}
+	if data.EmbeddedFields[0].JSONName != "street" || !data.EmbeddedFields[0].Required {
+		t.Errorf("EmbeddedFields[0] = %+v, want required street", data.EmbeddedFields[0])
+	}
+}
+
 // TestNewFieldDataList tests creating list of FieldData.
 func TestNewFieldDataList(t *testing.T) {
 	fields := []types.FieldDef{
@@ -346,6 +377,96 @@ func TestNewDomainData_Defaults(t *testing.T) {
 	}
 }
 
+// TestNewDomainData_InlineEdit tests that inline editable fields are marked.
+func TestNewDomainData_InlineEdit(t *testing.T) {
+	input := types.ScaffoldDomainInput{
+		DomainName: "task",
+		Fields: []types.FieldDef{
+			{Name: "Name", Type: "string"},
+			{Name: "Status", Type: "string"},
+			{Name: "Notes", Type: "string"},
+		},
+		InlineEdit: []string{"Status", "Name"},
+	}
+
+	data := NewDomainData(input, "github.com/example/app")
+
+	want := map[string]bool{"Name": true, "Status": true, "Notes": false}
+	for _, f := range data.Fields {
+		if f.InlineEdit != want[f.Name] {
+			t.Errorf("Field %s InlineEdit = %v, want %v", f.Name, f.InlineEdit, want[f.Name])
+		}
+	}
+}
+
+func TestNewDomainData_Filters(t *testing.T) {
+	input := types.ScaffoldDomainInput{
+		DomainName: "product",
+		Fields: []types.FieldDef{
+			{Name: "Name", Type: "string"},
+			{Name: "Status", Type: "string", Options: []string{"draft", "active"}},
+			{Name: "Price", Type: "float64"},
+			{Name: "PublishedAt", Type: "*time.Time"},
+		},
+		Filters: []types.FilterDef{
+			{Field: "Name"},
+			{Field: "Status"},
+			{Field: "Price", Operator: "gte"},
+			{Field: "Price", Operator: "lte", Label: "Max price"},
+			{Field: "PublishedAt", Operator: "gte"},
+			{Field: "Unknown"},
+		},
+	}
+
+	data := NewDomainData(input, "github.com/example/app")
+
+	want := []FilterData{
+		{Name: "Name", Field: "Name", Type: "string", Column: "name", Param: "name", Operator: "contains", Widget: "text", Label: "Name"},
+		{Name: "Status", Field: "Status", Type: "string", Column: "status", Param: "status", Operator: "eq", Widget: "select", Options: []string{"draft", "active"}, Label: "Status"},
+		{Name: "PriceGte", Field: "Price", Type: "float64", Column: "price", Param: "price_gte", Operator: "gte", Widget: "number", Label: "Price from"},
+		{Name: "PriceLte", Field: "Price", Type: "float64", Column: "price", Param: "price_lte", Operator: "lte", Widget: "number", Label: "Max price"},
+		{Name: "PublishedAtGte", Field: "PublishedAt", Type: "time.Time", Column: "published_at", Param: "published_at_gte", Operator: "gte", Widget: "date", Label: "Published At from"},
+	}
+	if !reflect.DeepEqual(data.Filters, want) {
+		t.Errorf("Filters = %+v, want %+v", data.Filters, want)
+	}
+}
+
+func TestNewDomainData_FullTextSearch(t *testing.T) {
+	fields := []types.FieldDef{
+		{Name: "Title", Type: "string"},
+		{Name: "Cover", Type: "string", FormType: "image"},
+		{Name: "Views", Type: "int"},
+		{Name: "Body", Type: "string", FormType: "textarea"},
+	}
+
+	data := NewDomainData(types.ScaffoldDomainInput{DomainName: "article", Fields: fields, Search: "fulltext"}, "github.com/example/app")
+	if !data.FullTextSearch {
+		t.Error("FullTextSearch should be true")
+	}
+	want := []SearchFieldData{
+		{Name: "Title", Column: "title", Weight: "A"},
+		{Name: "Body", Column: "body", Weight: "B"},
+	}
+	if !reflect.DeepEqual(data.SearchFields, want) {
+		t.Errorf("SearchFields = %+v, want %+v", data.SearchFields, want)
+	}
+
+	data = NewDomainData(types.ScaffoldDomainInput{DomainName: "article", Fields: fields, Search: "fulltext", SearchFields: []string{"Body", "Title"}}, "github.com/example/app")
+	want = []SearchFieldData{
+		{Name: "Body", Column: "body", Weight: "A"},
+		{Name: "Title", Column: "title", Weight: "B"},
+	}
+	if !reflect.DeepEqual(data.SearchFields, want) {
+		t.Errorf("SearchFields = %+v, want %+v", data.SearchFields, want)
+	}
+
+	data = NewDomainData(types.ScaffoldDomainInput{DomainName: "article", Fields: fields}, "github.com/example/app")
+	if data.FullTextSearch {
+		t.Error("FullTextSearch should default to false")
+	}
+}
+
 // TestNewColumnData tests ColumnData creation.
 func TestNewColumnData(t *testing.T) {
 	tests := []struct {
@@ -972,6 +1093,100 @@ func TestNewRelationshipDataList(t *testing.T) {
 	}
 }
 
+// TestNewRelationshipData_Polymorphic tests polymorphic owner columns and owner types.
+func TestNewRelationshipData_Polymorphic(t *testing.T) {
+	rel := types.RelationshipDef{Type: "polymorphic", Model: "commentable", Models: []string{"Post", "video_clip"}}
+
+	data := NewRelationshipData(rel, "comment")
+
+	if !data.IsPolymorphic {
+		t.Error("IsPolymorphic = false, want true")
+	}
+	if data.FieldName != "Commentable" {
+		t.Errorf("FieldName = %q, want %q", data.FieldName, "Commentable")
+	}
+	if data.ForeignKey != "CommentableID" {
+		t.Errorf("ForeignKey = %q, want %q", data.ForeignKey, "CommentableID")
+	}
+	if data.TypeColumn != "CommentableType" {
+		t.Errorf("TypeColumn = %q, want %q", data.TypeColumn, "CommentableType")
+	}
+	if data.IndexName != "idx_comments_commentable" {
+		t.Errorf("IndexName = %q, want %q", data.IndexName, "idx_comments_commentable")
+	}
+	if data.Preload {
+		t.Error("Preload = true, want false")
+	}
+
+	want := []PolymorphicOwnerData{
+		{Model: "Post", TypeValue: "posts"},
+		{Model: "VideoClip", TypeValue: "video_clips"},
+	}
+	if len(data.Owners) != len(want) {
+		t.Fatalf("len(Owners) = %d, want %d", len(data.Owners), len(want))
+	}
+	for i, owner := range data.Owners {
+		if owner != want[i] {
+			t.Errorf("Owners[%d] = %+v, want %+v", i, owner, want[i])
+		}
+	}
+}
+
+// TestNewRelationshipData_SelfReferential tests belongs_to self trees.
+func TestNewRelationshipData_SelfReferential(t *testing.T) {
+	rel := types.RelationshipDef{Type: "belongs_to", Model: "self"}
+
+	data := NewRelationshipData(rel, "category")
+
+	if !data.IsSelfReferential {
+		t.Error("IsSelfReferential = false, want true")
+	}
+	if data.IsBelongsTo {
+		t.Error("IsBelongsTo = true, want false")
+	}
+	if data.Model != "Category" {
+		t.Errorf("Model = %q, want %q", data.Model, "Category")
+	}
+	if data.FieldName != "Parent" {
+		t.Errorf("FieldName = %q, want %q", data.FieldName, "Parent")
+	}
+	if data.ForeignKey != "ParentID" {
+		t.Errorf("ForeignKey = %q, want %q", data.ForeignKey, "ParentID")
+	}
+}
+
+// TestNewRelationshipData_JoinModel tests many_to_many relationships with join fields.
+func TestNewRelationshipData_JoinModel(t *testing.T) {
+	rel := types.RelationshipDef{
+		Type:       "many_to_many",
+		Model:      "User",
+		JoinFields: []types.FieldDef{{Name: "Role", Type: "string"}},
+	}
+
+	data := NewRelationshipData(rel, "project")
+
+	if data.JoinModel != "ProjectUser" {
+		t.Errorf("JoinModel = %q, want %q", data.JoinModel, "ProjectUser")
+	}
+	if data.JoinTable != "projects_users" {
+		t.Errorf("JoinTable = %q, want %q", data.JoinTable, "projects_users")
+	}
+	if data.GORMTag != "many2many:projects_users" {
+		t.Errorf("GORMTag = %q, want %q", data.GORMTag, "many2many:projects_users")
+	}
+	if data.JoinForeignKey != "ProjectID" || data.JoinReferenceKey != "UserID" {
+		t.Errorf("join keys = %q/%q, want ProjectID/UserID", data.JoinForeignKey, data.JoinReferenceKey)
+	}
+	if len(data.JoinFields) != 1 || data.JoinFields[0].JSONName != "role" {
+		t.Errorf("JoinFields = %+v, want a single role field", data.JoinFields)
+	}
+
+	rel.JoinTable = "project_members"
+	if data := NewRelationshipData(rel, "project"); data.JoinTable != "project_members" {
+		t.Errorf("JoinTable = %q, want the explicit join_table", data.JoinTable)
+	}
+}
+
 // TestNewRelationshipDataList_Empty tests empty relationship list.
 func TestNewRelationshipDataList_Empty(t *testing.T) {
 	result := NewRelationshipDataList(nil, "order")
@@ -2,8 +2,8 @@
 package generator
 
 import (
-	"embed"
 	"fmt"
+	"io/fs"
 	"path/filepath"
 	"strings"
 
@@ -22,8 +22,8 @@ type FileConflict struct {
 
 // Generator handles template-based file generation.
 type Generator struct {
-	// fs is the embedded filesystem containing templates.
-	fs embed.FS
+	// fs is the filesystem containing templates.
+	fs fs.FS
 	// basePath is the base directory for generated files.
 	basePath string
 	// dryRun if true, no files are written.
@@ -36,10 +36,28 @@ type Generator struct {
 	filesCreated []string
 	// filesUpdated tracks updated files.
 	filesUpdated []string
+	// backups maps the updated files to their backup, when a backup directory is set.
+	backups map[string]string
 	// conflicts tracks files that would be overwritten.
 	conflicts []FileConflict
 	// generatedContent stores generated file content when storeContent is true.
 	generatedContent map[string]string
+	// generatedTemplates stores the template of each generated file when storeContent is true.
+	generatedTemplates map[string]string
+	// buffered if true, files are kept in pending until Flush writes them.
+	buffered bool
+	// pending holds the buffered files in the order they were generated.
+	pending []pendingFile
+	// pendingDirs holds the directories EnsureDir buffered.
+	pendingDirs []string
+	// backupDir keeps the previous version of overwritten files when not empty.
+	backupDir string
+}
+
+// pendingFile is a generated file that is not written yet.
+type pendingFile struct {
+	path    string
+	content string
 }
 
 // GeneratorResult contains the results of generation.
@@ -48,6 +66,8 @@ type GeneratorResult struct {
 	FilesCreated []string
 	// FilesUpdated is the list of updated files.
 	FilesUpdated []string
+	// Backups maps the updated files to the path of their previous version, when backed up.
+	Backups map[string]string
 	// Conflicts is the list of files that would be overwritten.
 	Conflicts []FileConflict
 	// HasConflicts is true if there are any conflicts.
@@ -55,9 +75,9 @@ type GeneratorResult struct {
 }
 
 // NewGenerator creates a new Generator.
-func NewGenerator(fs embed.FS, basePath string) *Generator {
+func NewGenerator(templateFS fs.FS, basePath string) *Generator {
 	return &Generator{
-		fs:           fs,
+		fs:           templateFS,
 		basePath:     basePath,
 		filesCreated: make([]string, 0),
 		filesUpdated: make([]string, 0),
@@ -75,6 +95,12 @@ func (g *Generator) SetDryRun(dryRun bool) {
 	g.dryRun = dryRun
 }
 
+// SetBuffered sets whether generated files are kept in memory until Flush writes them, so a
+// scaffold that fails midway, or finds a conflict, leaves the project untouched.
+func (g *Generator) SetBuffered(buffered bool) {
+	g.buffered = buffered
+}
+
 // IsDryRun returns whether dry run mode is enabled.
 func (g *Generator) IsDryRun() bool {
 	return g.dryRun
@@ -98,6 +124,12 @@ func (g *Generator) GetFileContent(outputPath string) string {
 	return g.generatedContent[outputPath]
 }
 
+// GetFileTemplate returns the template a file was generated from, empty for files generated
+// from a string. Only works if SetStoreContent(true) was called before generation.
+func (g *Generator) GetFileTemplate(outputPath string) string {
+	return g.generatedTemplates[outputPath]
+}
+
 // BasePath returns the base path for generation.
 func (g *Generator) BasePath() string {
 	return g.basePath
@@ -110,12 +142,16 @@ func (g *Generator) EnsureDir(relPath string) error {
 	if g.dryRun {
 		return nil
 	}
+	if g.buffered {
+		g.pendingDirs = append(g.pendingDirs, relPath)
+		return nil
+	}
 
 	return utils.EnsureDir(fullPath)
 }
 
 // GenerateFile generates a file from a template.
-// templatePath is the path within the embedded FS.
+// templatePath is the path within the template FS.
 // outputPath is the path relative to basePath.
 // data is the template data.
 func (g *Generator) GenerateFile(templatePath, outputPath string, data any) error {
@@ -141,6 +177,10 @@ func (g *Generator) GenerateFileWithDescription(templatePath, outputPath string,
 			g.generatedContent = make(map[string]string)
 		}
 		g.generatedContent[outputPath] = content
+		if g.generatedTemplates == nil {
+			g.generatedTemplates = make(map[string]string)
+		}
+		g.generatedTemplates[outputPath] = templatePath
 	}
 
 	// If file exists and we're not forcing overwrite, record as conflict
@@ -165,6 +205,10 @@ func (g *Generator) GenerateFileWithDescription(templatePath, outputPath string,
 		}
 		return nil
 	}
+	if g.buffered {
+		g.buffer(outputPath, content, fileExists)
+		return nil
+	}
 
 	// Ensure parent directory exists
 	dir := filepath.Dir(fullOutputPath)
@@ -172,13 +216,20 @@ func (g *Generator) GenerateFileWithDescription(templatePath, outputPath string,
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
+	// Keep the previous version of an overwritten file
+	if fileExists {
+		if err := g.backup(outputPath); err != nil {
+			return err
+		}
+	}
+
 	// Write file
 	if err := utils.WriteFileString(fullOutputPath, content, true); err != nil {
 		return fmt.Errorf("failed to write file %s: %w", fullOutputPath, err)
 	}
 
 	if fileExists {
-		g.filesUpdated = append(g.filesUpdated, outputPath)
+		g.updated(outputPath)
 	} else {
 		g.filesCreated = append(g.filesCreated, outputPath)
 	}
@@ -222,7 +273,7 @@ func inferFileDescription(path string) string {
 func (g *Generator) GenerateFileIfNotExists(templatePath, outputPath string, data any) error {
 	fullOutputPath := filepath.Join(g.basePath, outputPath)
 
-	if utils.FileExists(fullOutputPath) {
+	if utils.FileExists(fullOutputPath) || g.isPending(outputPath) {
 		return nil
 	}
 
@@ -263,6 +314,10 @@ func (g *Generator) GenerateFileFromStringWithDescription(outputPath, content, d
 		}
 		return nil
 	}
+	if g.buffered {
+		g.buffer(outputPath, content, fileExists)
+		return nil
+	}
 
 	// Ensure parent directory exists
 	dir := filepath.Dir(fullOutputPath)
@@ -270,13 +325,20 @@ func (g *Generator) GenerateFileFromStringWithDescription(outputPath, content, d
 		return fmt.Errorf("failed to create directory %s: %w", dir, err)
 	}
 
+	// Keep the previous version of an overwritten file
+	if fileExists {
+		if err := g.backup(outputPath); err != nil {
+			return err
+		}
+	}
+
 	// Write file
 	if err := utils.WriteFileString(fullOutputPath, content, true); err != nil {
 		return fmt.Errorf("failed to write file %s: %w", fullOutputPath, err)
 	}
 
 	if fileExists {
-		g.filesUpdated = append(g.filesUpdated, outputPath)
+		g.updated(outputPath)
 	} else {
 		g.filesCreated = append(g.filesCreated, outputPath)
 	}
@@ -289,6 +351,7 @@ func (g *Generator) Result() GeneratorResult {
 	return GeneratorResult{
 		FilesCreated: g.filesCreated,
 		FilesUpdated: g.filesUpdated,
+		Backups:      g.backups,
 		Conflicts:    g.conflicts,
 		HasConflicts: len(g.conflicts) > 0,
 	}
@@ -308,7 +371,10 @@ func (g *Generator) Conflicts() []FileConflict {
 func (g *Generator) Reset() {
 	g.filesCreated = make([]string, 0)
 	g.filesUpdated = make([]string, 0)
+	g.backups = nil
 	g.conflicts = make([]FileConflict, 0)
+	g.pending = nil
+	g.pendingDirs = nil
 }
 
 // FullPath returns the full path for a relative path.
@@ -354,13 +420,22 @@ func (g *Generator) WriteFile(relPath, content string) error {
 		}
 		return nil
 	}
+	if g.buffered {
+		g.buffer(relPath, content, fileExists)
+		return nil
+	}
 
+	if fileExists {
+		if err := g.backup(relPath); err != nil {
+			return err
+		}
+	}
 	if err := utils.WriteFileString(fullPath, content, true); err != nil {
 		return err
 	}
 
 	if fileExists {
-		g.filesUpdated = append(g.filesUpdated, relPath)
+		g.updated(relPath)
 	} else {
 		g.filesCreated = append(g.filesCreated, relPath)
 	}
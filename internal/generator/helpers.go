@@ -1,6 +1,8 @@
 package generator
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
 	"text/template"
 
@@ -33,6 +35,9 @@ func TemplateFuncMap() template.FuncMap {
 		"toServiceVariableName":    utils.ToServiceVariableName,
 		"toControllerVariableName": utils.ToControllerVariableName,
 
+		// Icons
+		"iconSet": IconSetIcons,
+
 		// Pluralization
 		"pluralize":   utils.Pluralize,
 		"singularize": utils.Singularize,
@@ -189,6 +194,12 @@ func TemplateFuncMap() template.FuncMap {
 			return t
 		},
 
+		// Bundled version of a stack component pinned in project templates (see Stack)
+		"stackVersion": StackVersion,
+
+		// Attributes that reload a list container on the domain's realtime events
+		"realtimeListAttrs": RealtimeListAttrs,
+
 		// Form component type mapping
 		"formComponent": func(formType string) string {
 			componentMap := map[string]string{
@@ -266,6 +277,252 @@ func TemplateFuncMap() template.FuncMap {
 			return "nil"
 		},
 
+		// Go literal for a field in generated tests; variants 1 and 2 give different values that
+		// keep to the field's rules
+		"testValue": func(field FieldData, variant int) string {
+			if field.IsEmbedded {
+				return "models." + field.Type + "{}"
+			}
+			n := strconv.Itoa(variant)
+			var value string
+			switch baseType := strings.TrimPrefix(field.Type, "*"); baseType {
+			case "string":
+				value = strconv.Quote(testText(field, variant))
+			case "int":
+				value = testNumber(field, variant)
+			case "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+				value = baseType + "(" + testNumber(field, variant) + ")"
+			case "bool":
+				value = strconv.FormatBool(variant%2 == 1)
+			case "time.Time":
+				value = "time.Date(2024, " + n + ", 1, 12, 0, 0, 0, time.UTC)"
+			case "[]byte":
+				value = `[]byte("data ` + n + `")`
+			case "[]string":
+				value = `[]string{"item ` + n + `"}`
+			case "[]int":
+				value = "[]int{" + n + "}"
+			default:
+				return "*new(" + field.Type + ")"
+			}
+			if strings.HasPrefix(field.Type, "*") {
+				return "ptr(" + value + ")"
+			}
+			return value
+		},
+
+		// Values of a field that each break one of its rules, for rejection cases in generated tests
+		"testRuleCases": testRuleCases,
+
+		// Quoted form value the controller parses into testValue; empty for fields it doesn't parse
+		// from a single form value (embedded structs, uploads, pointers and slices)
+		"testFormValue": func(field FieldData, variant int) string {
+			if field.IsEmbedded || field.FormType == "image" {
+				return ""
+			}
+			switch field.Type {
+			case "string":
+				return strconv.Quote(testText(field, variant))
+			case "int", "int64", "uint", "float64":
+				return strconv.Quote(testNumber(field, variant))
+			case "bool":
+				return strconv.Quote(strconv.FormatBool(variant%2 == 1))
+			case "time.Time":
+				return strconv.Quote(fmt.Sprintf("2024-%02d-01T12:00", variant))
+			}
+			return ""
+		},
+
+		// gofakeit expression for a field in factories: strings are faked after the field's name,
+		// unique strings get a sequence number and nullable fields are wrapped in ptr
+		"factoryValue": func(field FieldData) string {
+			baseType := strings.TrimPrefix(field.Type, "*")
+			var value string
+			switch baseType {
+			case "string":
+				if len(field.Options) > 0 {
+					options := make([]string, len(field.Options))
+					for i, option := range field.Options {
+						options[i] = strconv.Quote(option)
+					}
+					value = "gofakeit.RandomString([]string{" + strings.Join(options, ", ") + "})"
+					break
+				}
+				name := strings.ToLower(field.Name)
+				switch {
+				case field.FormType == "image":
+					value = "gofakeit.ImageURL(640, 480)"
+				case strings.Contains(name, "email"):
+					value = "gofakeit.Email()"
+				case strings.Contains(name, "username"):
+					value = "gofakeit.Username()"
+				case strings.Contains(name, "firstname"):
+					value = "gofakeit.FirstName()"
+				case strings.Contains(name, "lastname"):
+					value = "gofakeit.LastName()"
+				case strings.Contains(name, "phone"):
+					value = "gofakeit.Phone()"
+				case strings.Contains(name, "url"), strings.Contains(name, "website"):
+					value = "gofakeit.URL()"
+				case strings.Contains(name, "street"), strings.Contains(name, "address"):
+					value = "gofakeit.Street()"
+				case strings.Contains(name, "city"):
+					value = "gofakeit.City()"
+				case strings.Contains(name, "country"):
+					value = "gofakeit.Country()"
+				case strings.Contains(name, "zip"), strings.Contains(name, "postal"):
+					value = "gofakeit.Zip()"
+				case strings.Contains(name, "company"):
+					value = "gofakeit.Company()"
+				case strings.Contains(name, "color"):
+					value = "gofakeit.Color()"
+				case strings.Contains(name, "title"):
+					value = "gofakeit.Sentence(3)"
+				case strings.Contains(name, "description"), strings.Contains(name, "body"), strings.Contains(name, "content"),
+					strings.Contains(name, "bio"), strings.Contains(name, "note"), strings.Contains(name, "summary"):
+					value = `gofakeit.Paragraph(1, 3, 12, " ")`
+				case strings.HasSuffix(name, "name"):
+					value = "gofakeit.Name()"
+				default:
+					value = "gofakeit.Word()"
+				}
+				if strings.Contains(field.GORMTags, "unique") {
+					value = "unique(" + value + ")"
+				}
+			case "int":
+				value = "gofakeit.Number(1, 1000)"
+			case "int8", "uint8":
+				value = baseType + "(gofakeit.Number(1, 100))"
+			case "int16", "int32", "int64", "uint", "uint16", "uint32", "uint64":
+				value = baseType + "(gofakeit.Number(1, 1000))"
+			case "float32":
+				value = "float32(gofakeit.Price(1, 1000))"
+			case "float64":
+				value = "gofakeit.Price(1, 1000)"
+			case "bool":
+				value = "gofakeit.Bool()"
+			case "time.Time":
+				value = "gofakeit.Date()"
+			case "[]byte":
+				value = "[]byte(gofakeit.Sentence(5))"
+			case "[]string":
+				value = "[]string{gofakeit.Word(), gofakeit.Word()}"
+			case "[]int":
+				value = "[]int{gofakeit.Number(1, 100), gofakeit.Number(1, 100)}"
+			default:
+				return "*new(" + field.Type + ")"
+			}
+			if strings.HasPrefix(field.Type, "*") {
+				return "ptr(" + value + ")"
+			}
+			return value
+		},
+
+		// Call parsing the fixture column in variable into a field of goType (see seed/fixtures.go.tmpl);
+		// empty for types fixtures can't hold
+		"fixtureParse": func(goType, variable string) string {
+			baseType := strings.TrimPrefix(goType, "*")
+			var parse string
+			switch baseType {
+			case "string":
+				parse = "fixtureString"
+			case "int", "int8", "int16", "int32", "int64":
+				parse = "fixtureInt[" + baseType + "]"
+			case "uint", "uint8", "uint16", "uint32", "uint64":
+				parse = "fixtureUint[" + baseType + "]"
+			case "float32", "float64":
+				parse = "fixtureFloat[" + baseType + "]"
+			case "bool":
+				parse = "fixtureBool"
+			case "time.Time":
+				parse = "fixtureTime"
+			default:
+				return ""
+			}
+			if strings.HasPrefix(goType, "*") {
+				return "fixtureNullable(" + variable + ", " + parse + ")"
+			}
+			return parse + "(" + variable + ")"
+		},
+
+		// JSON field the mock API searches: "name" when present, else the first string field
+		"mockSearchField": func(fields []FieldData) string {
+			first := ""
+			for _, f := range fields {
+				if f.Type != "string" || f.IsEmbedded {
+					continue
+				}
+				if f.JSONName == "name" {
+					return f.JSONName
+				}
+				if first == "" {
+					first = f.JSONName
+				}
+			}
+			return first
+		},
+
+		// Go type of a field in the generated API client: embedded value types are declared
+		// per model (e.g., "CustomerAddress") and other custom types are left as raw JSON
+		"clientType": func(model string, f FieldData) string {
+			if f.IsEmbedded {
+				return model + f.Type
+			}
+			if isClientBuiltin(f.Type) {
+				return f.Type
+			}
+			return "json.RawMessage"
+		},
+
+		// Check if any field of the generated API client is left as raw JSON
+		"clientUsesRaw": func(fields []FieldData) bool {
+			for _, f := range fields {
+				if !f.IsEmbedded && !isClientBuiltin(f.Type) {
+					return true
+				}
+			}
+			return false
+		},
+
+		// Embedded value types the generated API client declares, one per type
+		"clientEmbeddedTypes": func(fields []FieldData) []FieldData {
+			var result []FieldData
+			seen := make(map[string]bool)
+			for _, f := range fields {
+				if f.IsEmbedded && !seen[f.Type] {
+					seen[f.Type] = true
+					result = append(result, f)
+				}
+			}
+			return result
+		},
+
+		// TypeScript type of a field's JSON value: embedded value types are declared per model
+		// (e.g., "CustomerAddress"), pointers and slices may be null, custom types are unknown
+		"tsType": func(model string, f FieldData) string {
+			if f.IsEmbedded {
+				return model + f.Type
+			}
+			return tsType(f.Type)
+		},
+
+		// Zod schema of a field's JSON value, matching tsType
+		"zodType": func(model string, f FieldData) string {
+			if f.IsEmbedded {
+				return model + f.Type + "Schema"
+			}
+			return zodType(f.Type)
+		},
+
+		// Comma-separated full-text search columns, optionally qualified (e.g., "new.title, new.body")
+		"searchColumns": func(fields []SearchFieldData, prefix string) string {
+			columns := make([]string, len(fields))
+			for i, f := range fields {
+				columns[i] = prefix + f.Column
+			}
+			return strings.Join(columns, ", ")
+		},
+
 		// GORM tag helpers
 		"gormTag": func(fieldType string, required bool, tags string) string {
 			var parts []string
@@ -372,6 +629,90 @@ func TemplateFuncMap() template.FuncMap {
 			return false
 		},
 
+		// Check if any field is an embedded value type
+		"hasEmbedded": func(fields []FieldData) bool {
+			for _, f := range fields {
+				if f.IsEmbedded {
+					return true
+				}
+			}
+			return false
+		},
+
+		// Check if any field is an uploaded image
+		"hasImages": func(fields []FieldData) bool {
+			for _, f := range fields {
+				if f.FormType == "image" {
+					return true
+				}
+			}
+			return false
+		},
+
+		// Name of the first image field (shown as the cover in lists), or ""
+		"firstImage": func(fields []FieldData) string {
+			for _, f := range fields {
+				if f.FormType == "image" {
+					return f.Name
+				}
+			}
+			return ""
+		},
+
+		// Check if a list can be sorted by the field's column
+		"isSortable": func(f FieldData) bool {
+			if f.IsEmbedded || f.FormType == "image" {
+				return false
+			}
+			switch strings.TrimPrefix(f.Type, "*") {
+			case "string", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64", "bool", "time.Time":
+				return true
+			}
+			return false
+		},
+
+		// Embedded value types to declare, one field per type
+		"embeddedTypes": func(fields []FieldData) []FieldData {
+			var result []FieldData
+			seen := make(map[string]bool)
+			for _, f := range fields {
+				if f.IsEmbedded && !f.EmbeddedTypeExists && !seen[f.Type] {
+					seen[f.Type] = true
+					result = append(result, f)
+				}
+			}
+			return result
+		},
+
+		// Check if any field has a rule the service enforces: required strings, min, max or pattern
+		"hasValidation": func(fields []FieldData) bool {
+			for _, f := range fields {
+				if hasFieldRules(f) {
+					return true
+				}
+			}
+			return false
+		},
+
+		// Check if a field has a rule the service enforces
+		"hasFieldRules": hasFieldRules,
+
+		// HTML validation attributes of a field's rules as a templ.Attributes literal, or ""
+		"validationAttrs": validationAttrs,
+
+		// Go string literal of s
+		"goQuote": strconv.Quote,
+
+		// Check if any field is editable in place from the list view
+		"hasInlineEdit": func(fields []FieldData) bool {
+			for _, f := range fields {
+				if f.InlineEdit {
+					return true
+				}
+			}
+			return false
+		},
+
 		// Check if there are any belongs_to relationships
 		"hasBelongsTo": func(relationships []RelationshipData) bool {
 			for _, r := range relationships {
@@ -382,6 +723,44 @@ func TemplateFuncMap() template.FuncMap {
 			return false
 		},
 
+		// Check if there is a belongs_to self (tree) relationship
+		"hasSelfReference": func(relationships []RelationshipData) bool {
+			for _, r := range relationships {
+				if r.IsSelfReferential {
+					return true
+				}
+			}
+			return false
+		},
+
+		// Check if any many_to_many relationship has an explicit join model
+		"hasJoinModel": func(relationships []RelationshipData) bool {
+			for _, r := range relationships {
+				if r.JoinModel != "" {
+					return true
+				}
+			}
+			return false
+		},
+
+		// Check if any has_many relationship is edited as nested form rows
+		"hasNestedForms": func(relationships []RelationshipData) bool {
+			for _, r := range relationships {
+				if r.NestedForm {
+					return true
+				}
+			}
+			return false
+		},
+
+		// Translatable strings (see Messages): with i18n they render i18n.T calls and record
+		// the key, otherwise the English text
+		"hasMessages": func(data interface{}) bool { return messagesOf(data) != nil },
+		"msg":         msgText,
+		"msgAttr":     msgAttr,
+		"msgExpr":     msgExpr,
+		"msgf":        msgFormat,
+
 		// Filter belongs_to relationships
 		"belongsToRelationships": func(relationships []RelationshipData) []RelationshipData {
 			var result []RelationshipData
@@ -394,3 +773,109 @@ func TemplateFuncMap() template.FuncMap {
 		},
 	}
 }
+
+// isClientBuiltin reports whether a field type needs no declaration in the generated API
+// client: builtin and time types, optionally as pointers or slices.
+func isClientBuiltin(goType string) bool {
+	base := strings.TrimPrefix(strings.TrimPrefix(goType, "*"), "[]")
+	switch base {
+	case "string", "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64", "bool", "byte", "time.Time":
+		return true
+	}
+	return false
+}
+
+// tsType returns the TypeScript type of the JSON encoding of a Go field type.
+func tsType(goType string) string {
+	if base, ok := strings.CutPrefix(goType, "*"); ok {
+		return tsType(base) + " | null"
+	}
+	if goType == "[]byte" {
+		return "string" // base64
+	}
+	if elem, ok := strings.CutPrefix(goType, "[]"); ok {
+		return tsType(elem) + "[] | null"
+	}
+	switch goType {
+	case "string", "time.Time":
+		return "string"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64", "float32", "float64":
+		return "number"
+	case "bool":
+		return "boolean"
+	}
+	return "unknown"
+}
+
+// zodType returns the zod schema of the JSON encoding of a Go field type.
+func zodType(goType string) string {
+	if base, ok := strings.CutPrefix(goType, "*"); ok {
+		return zodType(base) + ".nullable()"
+	}
+	if goType == "[]byte" {
+		return "z.string()"
+	}
+	if elem, ok := strings.CutPrefix(goType, "[]"); ok {
+		return "z.array(" + zodType(elem) + ").nullable()"
+	}
+	switch goType {
+	case "string":
+		return "z.string()"
+	case "time.Time":
+		return "z.string().datetime({ offset: true })"
+	case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+		return "z.number().int()"
+	case "float32", "float64":
+		return "z.number()"
+	case "bool":
+		return "z.boolean()"
+	}
+	return "z.unknown()"
+}
+
+// RealtimeListAttrs returns the attributes of a list container, starting with a space, that
+// connect it to the realtime event stream of a domain and reload it on every event. The reload
+// is skipped while an inline editor is open in the list, so edits in progress are kept.
+// scaffold_realtime adds them to existing list views, so they must match the list template.
+func RealtimeListAttrs(variableName, domainName string) string {
+	topic := utils.ToSnakeCase(domainName)
+	return fmt.Sprintf(` hx-ext="sse" sse-connect="%s" hx-trigger="sse:%s" x-data x-on:sse:%s.debounce.250ms="$el.querySelector('form') || htmx.ajax('GET', window.location.href, '#%s-list')"`,
+		utils.ToRealtimeStreamPath(domainName), topic, topic, variableName)
+}
+
+// hasFieldRules reports whether the service checks the field: a required string, or a
+// min, max or pattern rule.
+func hasFieldRules(f FieldData) bool {
+	if f.IsEmbedded {
+		return false
+	}
+	return (f.Required && f.Type == "string" && f.FormType != "image") || f.Min != "" || f.Max != "" || f.Pattern != ""
+}
+
+// validationAttrs returns the HTML validation attributes of a field's min, max and pattern
+// rules as a templ.Attributes literal: minlength, maxlength and pattern for strings, min and
+// max (and step "any" for floats) for numbers. It returns "" for fields without rules.
+func validationAttrs(f FieldData) string {
+	var attrs []string
+	add := func(name, value string) {
+		if value != "" {
+			attrs = append(attrs, strconv.Quote(name)+": "+strconv.Quote(value))
+		}
+	}
+	if f.Type == "string" {
+		add("minlength", f.Min)
+		add("maxlength", f.Max)
+		add("pattern", f.Pattern)
+	} else {
+		add("min", f.Min)
+		add("max", f.Max)
+		// Without step the browser only accepts min plus whole numbers
+		if len(attrs) > 0 && strings.HasPrefix(f.Type, "float") {
+			add("step", "any")
+		}
+	}
+	if len(attrs) == 0 {
+		return ""
+	}
+	return "templ.Attributes{" + strings.Join(attrs, ", ") + "}"
+}
@@ -35,7 +35,7 @@ func TestTemplateFuncMap(t *testing.T) {
 		// Arithmetic helpers
 		"add", "sub", "mul", "div", "mod",
 		// Code generation helpers
-		"goType", "formComponent", "inputType", "fakerFunc", "gormTag",
+		"goType", "stackVersion", "formComponent", "inputType", "fakerFunc", "testValue", "testFormValue", "testRuleCases", "factoryValue", "fixtureParse", "gormTag",
 		// Comment helpers
 		"comment", "blockComment",
 		// Indent helper
@@ -424,6 +424,137 @@ func TestHelperFunctions_FakerFunc(t *testing.T) {
 	}
 }
 
+// TestHelperFunctions_TestValue tests testValue literals.
+func TestHelperFunctions_TestValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   FieldData
+		variant int
+		want    string
+	}{
+		{"string", FieldData{Type: "string", Label: "Name"}, 1, `"Name 1"`},
+		{"string variant", FieldData{Type: "string", Label: "Name"}, 2, `"Name 2"`},
+		{"select", FieldData{Type: "string", Options: []string{"draft", "live"}}, 2, `"live"`},
+		{"int", FieldData{Type: "int"}, 1, "1"},
+		{"int64", FieldData{Type: "int64"}, 2, "int64(2)"},
+		{"float64", FieldData{Type: "float64"}, 1, "float64(1.5)"},
+		{"bool", FieldData{Type: "bool"}, 2, "false"},
+		{"time", FieldData{Type: "time.Time"}, 1, "time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)"},
+		{"pointer", FieldData{Type: "*string", Label: "Note"}, 1, `ptr("Note 1")`},
+		{"slice", FieldData{Type: "[]string"}, 1, `[]string{"item 1"}`},
+		{"embedded", FieldData{Type: "Address", IsEmbedded: true}, 1, "models.Address{}"},
+		{"unknown", FieldData{Type: "json.RawMessage"}, 1, "*new(json.RawMessage)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]interface{}{"Field": tt.field, "Variant": tt.variant}
+			result, err := ExecuteTemplateString("test", `[[testValue .Field .Variant]]`, data)
+			if err != nil {
+				t.Fatalf("Error: %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("testValue(%s, %d) = %q, want %q", tt.field.Type, tt.variant, result, tt.want)
+			}
+		})
+	}
+}
+
+// TestHelperFunctions_TestFormValue tests testFormValue form values.
+func TestHelperFunctions_TestFormValue(t *testing.T) {
+	tests := []struct {
+		name    string
+		field   FieldData
+		variant int
+		want    string
+	}{
+		{"string", FieldData{Type: "string", Label: "Name"}, 1, `"Name 1"`},
+		{"select", FieldData{Type: "string", Options: []string{"draft", "live"}}, 2, `"live"`},
+		{"int", FieldData{Type: "int"}, 2, `"2"`},
+		{"float64", FieldData{Type: "float64"}, 1, `"1.5"`},
+		{"bool", FieldData{Type: "bool"}, 1, `"true"`},
+		{"time", FieldData{Type: "time.Time"}, 2, `"2024-02-01T12:00"`},
+		{"image", FieldData{Type: "string", FormType: "image"}, 1, ""},
+		{"pointer", FieldData{Type: "*time.Time"}, 1, ""},
+		{"embedded", FieldData{Type: "Address", IsEmbedded: true}, 1, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := map[string]interface{}{"Field": tt.field, "Variant": tt.variant}
+			result, err := ExecuteTemplateString("test", `[[testFormValue .Field .Variant]]`, data)
+			if err != nil {
+				t.Fatalf("Error: %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("testFormValue(%s, %d) = %q, want %q", tt.field.Type, tt.variant, result, tt.want)
+			}
+		})
+	}
+}
+
+// TestHelperFunctions_FactoryValue tests factoryValue gofakeit expressions.
+func TestHelperFunctions_FactoryValue(t *testing.T) {
+	tests := []struct {
+		name  string
+		field FieldData
+		want  string
+	}{
+		{"string", FieldData{Name: "Sku", Type: "string"}, "gofakeit.Word()"},
+		{"email", FieldData{Name: "ContactEmail", Type: "string"}, "gofakeit.Email()"},
+		{"name", FieldData{Name: "Name", Type: "string"}, "gofakeit.Name()"},
+		{"unique", FieldData{Name: "Email", Type: "string", GORMTags: "uniqueIndex"}, "unique(gofakeit.Email())"},
+		{"select", FieldData{Name: "Status", Type: "string", Options: []string{"draft", "live"}}, `gofakeit.RandomString([]string{"draft", "live"})`},
+		{"image", FieldData{Name: "Photo", Type: "string", FormType: "image"}, "gofakeit.ImageURL(640, 480)"},
+		{"int64", FieldData{Name: "Stock", Type: "int64"}, "int64(gofakeit.Number(1, 1000))"},
+		{"float64", FieldData{Name: "Price", Type: "float64"}, "gofakeit.Price(1, 1000)"},
+		{"nullable", FieldData{Name: "PublishedAt", Type: "*time.Time"}, "ptr(gofakeit.Date())"},
+		{"unknown", FieldData{Name: "Data", Type: "map[string]any"}, "*new(map[string]any)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := ExecuteTemplateString("test", `[[factoryValue .]]`, tt.field)
+			if err != nil {
+				t.Fatalf("Error: %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("factoryValue(%s %s) = %q, want %q", tt.field.Name, tt.field.Type, result, tt.want)
+			}
+		})
+	}
+}
+
+// TestHelperFunctions_FixtureParse tests fixtureParse calls.
+func TestHelperFunctions_FixtureParse(t *testing.T) {
+	tests := []struct {
+		goType string
+		want   string
+	}{
+		{"string", "fixtureString(value)"},
+		{"int64", "fixtureInt[int64](value)"},
+		{"uint", "fixtureUint[uint](value)"},
+		{"float64", "fixtureFloat[float64](value)"},
+		{"bool", "fixtureBool(value)"},
+		{"time.Time", "fixtureTime(value)"},
+		{"*time.Time", "fixtureNullable(value, fixtureTime)"},
+		{"*int", "fixtureNullable(value, fixtureInt[int])"},
+		{"[]string", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.goType, func(t *testing.T) {
+			result, err := ExecuteTemplateString("test", `[[fixtureParse . "value"]]`, tt.goType)
+			if err != nil {
+				t.Fatalf("Error: %v", err)
+			}
+			if result != tt.want {
+				t.Errorf("fixtureParse(%s) = %q, want %q", tt.goType, result, tt.want)
+			}
+		})
+	}
+}
+
 // TestHelperFunctions_GormTag tests gormTag function.
 func TestHelperFunctions_GormTag(t *testing.T) {
 	tests := []struct {
@@ -637,3 +768,184 @@ func TestHelperFunctions_Len(t *testing.T) {
 		})
 	}
 }
+
+// TestHelperFunctions_HasInlineEdit tests the hasInlineEdit function.
+func TestHelperFunctions_HasInlineEdit(t *testing.T) {
+	funcMap := TemplateFuncMap()
+	hasInlineEdit := funcMap["hasInlineEdit"].(func([]FieldData) bool)
+
+	if hasInlineEdit([]FieldData{{Name: "Name"}, {Name: "Status"}}) {
+		t.Error("hasInlineEdit should be false when no field is inline editable")
+	}
+	if !hasInlineEdit([]FieldData{{Name: "Name"}, {Name: "Status", InlineEdit: true}}) {
+		t.Error("hasInlineEdit should be true when a field is inline editable")
+	}
+}
+
+// TestHelperFunctions_Validation tests the hasValidation and validationAttrs functions.
+func TestHelperFunctions_Validation(t *testing.T) {
+	funcMap := TemplateFuncMap()
+	hasValidation := funcMap["hasValidation"].(func([]FieldData) bool)
+	validationAttrs := funcMap["validationAttrs"].(func(FieldData) string)
+
+	if hasValidation([]FieldData{{Name: "Name", Type: "string"}, {Name: "Count", Type: "int", Required: true}}) {
+		t.Error("hasValidation should be false without required strings or rules")
+	}
+	if !hasValidation([]FieldData{{Name: "Name", Type: "string", Required: true}}) {
+		t.Error("hasValidation should be true for a required string")
+	}
+	if !hasValidation([]FieldData{{Name: "Count", Type: "int", Min: "1"}}) {
+		t.Error("hasValidation should be true for a min rule")
+	}
+
+	tests := []struct {
+		field FieldData
+		want  string
+	}{
+		{FieldData{Type: "string"}, ""},
+		{FieldData{Type: "string", Min: "3", Max: "20", Pattern: `[a-z]+\d`}, `templ.Attributes{"minlength": "3", "maxlength": "20", "pattern": "[a-z]+\\d"}`},
+		{FieldData{Type: "int", Min: "1", Max: "5"}, `templ.Attributes{"min": "1", "max": "5"}`},
+		{FieldData{Type: "float64", Min: "0.5"}, `templ.Attributes{"min": "0.5", "step": "any"}`},
+	}
+	for _, tt := range tests {
+		if got := validationAttrs(tt.field); got != tt.want {
+			t.Errorf("validationAttrs(%+v) = %s, want %s", tt.field, got, tt.want)
+		}
+	}
+}
+
+// TestHelperFunctions_EmbeddedTypes tests the embeddedTypes function.
+func TestHelperFunctions_EmbeddedTypes(t *testing.T) {
+	funcMap := TemplateFuncMap()
+	embeddedTypes := funcMap["embeddedTypes"].(func([]FieldData) []FieldData)
+
+	fields := []FieldData{
+		{Name: "Name", Type: "string"},
+		{Name: "ShippingAddress", Type: "Address", IsEmbedded: true},
+		{Name: "BillingAddress", Type: "Address", IsEmbedded: true},
+		{Name: "Location", Type: "GeoPoint", IsEmbedded: true, EmbeddedTypeExists: true},
+	}
+
+	result := embeddedTypes(fields)
+	if len(result) != 1 {
+		t.Fatalf("len(result) = %d, want 1", len(result))
+	}
+	if result[0].Name != "ShippingAddress" {
+		t.Errorf("result[0].Name = %q, want %q", result[0].Name, "ShippingAddress")
+	}
+}
+
+// TestHelperFunctions_HasSelfReference tests the hasSelfReference function.
+func TestHelperFunctions_HasSelfReference(t *testing.T) {
+	funcMap := TemplateFuncMap()
+	hasSelfReference := funcMap["hasSelfReference"].(func([]RelationshipData) bool)
+
+	if hasSelfReference([]RelationshipData{{Type: "belongs_to", IsBelongsTo: true}}) {
+		t.Error("hasSelfReference should be false without a self relationship")
+	}
+	if !hasSelfReference([]RelationshipData{{Type: "belongs_to", IsSelfReferential: true}}) {
+		t.Error("hasSelfReference should be true with a self relationship")
+	}
+}
+
+// TestHelperFunctions_HasJoinModel tests the hasJoinModel function.
+func TestHelperFunctions_HasJoinModel(t *testing.T) {
+	funcMap := TemplateFuncMap()
+	hasJoinModel := funcMap["hasJoinModel"].(func([]RelationshipData) bool)
+
+	if hasJoinModel([]RelationshipData{{Type: "many_to_many", IsManyToMany: true}}) {
+		t.Error("hasJoinModel should be false for a plain many_to_many")
+	}
+	if !hasJoinModel([]RelationshipData{{Type: "many_to_many", IsManyToMany: true, JoinModel: "ProjectUser"}}) {
+		t.Error("hasJoinModel should be true with a join model")
+	}
+}
+
+// TestHelperFunctions_HasNestedForms tests the hasNestedForms function.
+func TestHelperFunctions_HasNestedForms(t *testing.T) {
+	funcMap := TemplateFuncMap()
+	hasNestedForms := funcMap["hasNestedForms"].(func([]RelationshipData) bool)
+
+	if hasNestedForms([]RelationshipData{{Type: "has_many", IsHasMany: true}}) {
+		t.Error("hasNestedForms should be false for a plain has_many")
+	}
+	if !hasNestedForms([]RelationshipData{{Type: "has_many", IsHasMany: true, NestedForm: true}}) {
+		t.Error("hasNestedForms should be true with a nested form")
+	}
+}
+
+// TestHelperFunctions_MockSearchField tests the mockSearchField function.
+func TestHelperFunctions_MockSearchField(t *testing.T) {
+	funcMap := TemplateFuncMap()
+	mockSearchField := funcMap["mockSearchField"].(func([]FieldData) string)
+
+	tests := []struct {
+		name   string
+		fields []FieldData
+		want   string
+	}{
+		{"prefers name", []FieldData{{Type: "string", JSONName: "title"}, {Type: "string", JSONName: "name"}}, "name"},
+		{"first string field", []FieldData{{Type: "int", JSONName: "count"}, {Type: "string", JSONName: "title"}}, "title"},
+		{"no string field", []FieldData{{Type: "float64", JSONName: "price"}}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mockSearchField(tt.fields); got != tt.want {
+				t.Errorf("mockSearchField() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestHelperFunctions_SearchColumns tests the searchColumns function.
+func TestHelperFunctions_SearchColumns(t *testing.T) {
+	funcMap := TemplateFuncMap()
+	searchColumns := funcMap["searchColumns"].(func([]SearchFieldData, string) string)
+
+	fields := []SearchFieldData{{Column: "title"}, {Column: "body"}}
+	if got := searchColumns(fields, ""); got != "title, body" {
+		t.Errorf("searchColumns() = %q, want %q", got, "title, body")
+	}
+	if got := searchColumns(fields, "new."); got != "new.title, new.body" {
+		t.Errorf("searchColumns() = %q, want %q", got, "new.title, new.body")
+	}
+}
+
+// TestHelperFunctions_Messages tests the translatable string functions.
+func TestHelperFunctions_Messages(t *testing.T) {
+	content := `[[msg $ "title" "Blog Posts"]]|[[msgAttr $ "common.save" "Save"]]|[[msgExpr $ "confirm_delete" "Sure?"]]|[[msgf $ "total" "%d total" "props.TotalItems"]]|[[msg $ "title" "Blog Posts"]]`
+
+	plain, err := ExecuteTemplateString("test", content, DomainData{})
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	if want := `Blog Posts|"Save"|"Sure?"|{ fmt.Sprintf("%d total", props.TotalItems) }|Blog Posts`; plain != want {
+		t.Errorf("without messages got %q, want %q", plain, want)
+	}
+
+	data := DomainData{Messages: NewMessages("admin/blog_post")}
+	translated, err := ExecuteTemplateString("test", content, data)
+	if err != nil {
+		t.Fatalf("Error: %v", err)
+	}
+	want := `{ i18n.T(ctx, "blog_post.title") }|{ i18n.T(ctx, "common.save") }|i18n.T(ctx, "blog_post.confirm_delete")|{ i18n.T(ctx, "blog_post.total", props.TotalItems) }|{ i18n.T(ctx, "blog_post.title") }`
+	if translated != want {
+		t.Errorf("with messages got %q, want %q", translated, want)
+	}
+
+	wantEntries := []MessageEntry{
+		{Key: "blog_post.title", Text: "Blog Posts"},
+		{Key: "common.save", Text: "Save"},
+		{Key: "blog_post.confirm_delete", Text: "Sure?"},
+		{Key: "blog_post.total", Text: "%d total"},
+	}
+	entries := data.Messages.Entries()
+	if len(entries) != len(wantEntries) {
+		t.Fatalf("got %d entries, want %d: %v", len(entries), len(wantEntries), entries)
+	}
+	for i, entry := range entries {
+		if entry != wantEntries[i] {
+			t.Errorf("entry %d = %v, want %v", i, entry, wantEntries[i])
+		}
+	}
+}
@@ -0,0 +1,29 @@
+package generator
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestIconSets(t *testing.T) {
+	names := IconNames()
+	for set := range iconSets {
+		icons := IconSetIcons(set)
+		if len(icons) != len(names) {
+			t.Fatalf("%s draws %d icons, want %d", set, len(icons), len(names))
+		}
+		for i, icon := range icons {
+			if icon.Name != names[i] {
+				t.Errorf("%s icon %d = %q, want %q", set, i, icon.Name, names[i])
+			}
+			// The icons are embedded in Go raw strings
+			if icon.SVG == "" || strings.Contains(icon.SVG, "`") {
+				t.Errorf("%s icon %q has invalid markup %q", set, icon.Name, icon.SVG)
+			}
+		}
+	}
+
+	if got := IconSetIcons("feather"); len(got) != len(names) || got[0].SVG != iconSets[DefaultIconSet][got[0].Name] {
+		t.Error("an unknown set should fall back to the default set")
+	}
+}
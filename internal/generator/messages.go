@@ -0,0 +1,97 @@
+package generator
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/utils"
+)
+
+// commonMessagePrefix marks keys shared by every domain, such as "common.save".
+const commonMessagePrefix = "common."
+
+// MessageEntry is a translation key with its English text.
+type MessageEntry struct {
+	Key  string
+	Text string
+}
+
+// Messages collects the translatable strings of a domain's views while they are rendered.
+// The msg template functions record each key with its English text, so the message catalog
+// written next to the views always has the keys they use.
+type Messages struct {
+	prefix  string
+	entries []MessageEntry
+	index   map[string]int
+}
+
+// NewMessages creates a collector whose keys are namespaced by the domain (e.g., "blog_post.title").
+func NewMessages(domain string) *Messages {
+	return &Messages{prefix: utils.ToSnakeCase(utils.ParseDomainPath(domain)), index: map[string]int{}}
+}
+
+// Entries returns the recorded keys in the order the views use them.
+func (m *Messages) Entries() []MessageEntry {
+	return m.entries
+}
+
+// add records a key, prefixing it with the domain unless it is a common key, and returns the full key.
+func (m *Messages) add(key, text string) string {
+	if !strings.HasPrefix(key, commonMessagePrefix) {
+		key = m.prefix + "." + key
+	}
+	if _, ok := m.index[key]; !ok {
+		m.index[key] = len(m.entries)
+		m.entries = append(m.entries, MessageEntry{Key: key, Text: text})
+	}
+	return key
+}
+
+// messagesOf returns the Messages collector of template data, or nil when the data has none,
+// in which case the views render plain text.
+func messagesOf(data interface{}) *Messages {
+	v := reflect.Indirect(reflect.ValueOf(data))
+	if v.Kind() != reflect.Struct {
+		return nil
+	}
+	f := v.FieldByName("Messages")
+	if !f.IsValid() {
+		return nil
+	}
+	m, _ := f.Interface().(*Messages)
+	return m
+}
+
+// msgText renders a translatable templ text node.
+func msgText(data interface{}, key, text string) string {
+	if m := messagesOf(data); m != nil {
+		return fmt.Sprintf("{ i18n.T(ctx, %q) }", m.add(key, text))
+	}
+	return text
+}
+
+// msgAttr renders a translatable templ attribute value.
+func msgAttr(data interface{}, key, text string) string {
+	if m := messagesOf(data); m != nil {
+		return fmt.Sprintf("{ i18n.T(ctx, %q) }", m.add(key, text))
+	}
+	return strconv.Quote(text)
+}
+
+// msgExpr renders a translatable Go string expression.
+func msgExpr(data interface{}, key, text string) string {
+	if m := messagesOf(data); m != nil {
+		return fmt.Sprintf("i18n.T(ctx, %q)", m.add(key, text))
+	}
+	return strconv.Quote(text)
+}
+
+// msgFormat renders a translatable templ text node formatted with args, a Go expression list.
+func msgFormat(data interface{}, key, text, args string) string {
+	if m := messagesOf(data); m != nil {
+		return fmt.Sprintf("{ i18n.T(ctx, %q, %s) }", m.add(key, text), args)
+	}
+	return fmt.Sprintf("{ fmt.Sprintf(%q, %s) }", text, args)
+}
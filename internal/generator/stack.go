@@ -0,0 +1,32 @@
+package generator
+
+// StackComponent is a frontend or templating dependency whose version is pinned
+// in generated projects (CDN URLs, go.mod, Taskfile).
+type StackComponent struct {
+	// Name is the component identifier used by templates and upgrade_stack.
+	Name string
+	// Version is the version bundled with this scaffolder, without a "v" prefix.
+	Version string
+}
+
+// Stack lists the component versions that project templates pin.
+// Templates read them through the stackVersion function, and upgrade_stack
+// moves existing projects to them, so bump versions here only.
+var Stack = []StackComponent{
+	{Name: "templ", Version: "0.3.857"},
+	{Name: "htmx", Version: "2.0.0"},
+	{Name: "alpine", Version: "3.14.8"},
+	{Name: "tailwind", Version: "4.1.14"},
+	{Name: "unpoly", Version: "3.9.5"},
+}
+
+// StackVersion returns the bundled version of a stack component, or an empty
+// string for unknown components.
+func StackVersion(name string) string {
+	for _, c := range Stack {
+		if c.Name == name {
+			return c.Version
+		}
+	}
+	return ""
+}
@@ -2,8 +2,8 @@ package generator
 
 import (
 	"bytes"
-	"embed"
 	"fmt"
+	"io/fs"
 	"strings"
 	"text/template"
 )
@@ -15,10 +15,10 @@ const (
 	RightDelim = "]]"
 )
 
-// LoadTemplate loads a template from the embedded filesystem.
+// LoadTemplate loads a template from the template filesystem.
 // The template uses [[ ]] delimiters instead of {{ }}.
-func LoadTemplate(fs embed.FS, name string) (*template.Template, error) {
-	content, err := fs.ReadFile(name)
+func LoadTemplate(fsys fs.FS, name string) (*template.Template, error) {
+	content, err := fs.ReadFile(fsys, name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read template %s: %w", name, err)
 	}
@@ -40,9 +40,9 @@ func ParseTemplate(name, content string) (*template.Template, error) {
 	return parsed, nil
 }
 
-// ExecuteTemplate loads and executes a template from the embedded filesystem.
-func ExecuteTemplate(fs embed.FS, name string, data any) (string, error) {
-	tmpl, err := LoadTemplate(fs, name)
+// ExecuteTemplate loads and executes a template from the template filesystem.
+func ExecuteTemplate(fsys fs.FS, name string, data any) (string, error) {
+	tmpl, err := LoadTemplate(fsys, name)
 	if err != nil {
 		return "", err
 	}
@@ -71,8 +71,8 @@ func ExecuteTemplateString(name, content string, data any) (string, error) {
 }
 
 // MustLoadTemplate loads a template or panics.
-func MustLoadTemplate(fs embed.FS, name string) *template.Template {
-	tmpl, err := LoadTemplate(fs, name)
+func MustLoadTemplate(fsys fs.FS, name string) *template.Template {
+	tmpl, err := LoadTemplate(fsys, name)
 	if err != nil {
 		panic(err)
 	}
@@ -88,15 +88,15 @@ func MustParseTemplate(name, content string) *template.Template {
 	return tmpl
 }
 
-// TemplateExists checks if a template exists in the embedded filesystem.
-func TemplateExists(fs embed.FS, name string) bool {
-	_, err := fs.ReadFile(name)
+// TemplateExists checks if a template exists in the template filesystem.
+func TemplateExists(fsys fs.FS, name string) bool {
+	_, err := fs.ReadFile(fsys, name)
 	return err == nil
 }
 
-// ListTemplates lists all templates matching a pattern in the embedded filesystem.
-func ListTemplates(fs embed.FS, dir string) ([]string, error) {
-	entries, err := fs.ReadDir(dir)
+// ListTemplates lists all templates matching a pattern in the template filesystem.
+func ListTemplates(fsys fs.FS, dir string) ([]string, error) {
+	entries, err := fs.ReadDir(fsys, dir)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read template directory %s: %w", dir, err)
 	}
@@ -122,8 +122,8 @@ type TemplateInfo struct {
 }
 
 // GetTemplateInfo returns information about a template.
-func GetTemplateInfo(fs embed.FS, name string) (*TemplateInfo, error) {
-	content, err := fs.ReadFile(name)
+func GetTemplateInfo(fsys fs.FS, name string) (*TemplateInfo, error) {
+	content, err := fs.ReadFile(fsys, name)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read template %s: %w", name, err)
 	}
@@ -0,0 +1,273 @@
+package generator
+
+import (
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// testRuleCase is a value of a field that breaks one of its rules, for the rejection cases
+// of generated service tests.
+type testRuleCase struct {
+	// Name describes the case, e.g. "rejects a Sku shorter than 3 characters".
+	Name string
+	// Value is the Go literal of the value.
+	Value string
+	// Message is the message the service reports for the field.
+	Message string
+}
+
+// testText returns the text of a string field in generated tests. Variants 1 and 2 give
+// different values that keep to the field's options, length bounds and pattern.
+func testText(field FieldData, variant int) string {
+	if len(field.Options) > 0 {
+		return field.Options[(variant-1)%len(field.Options)]
+	}
+	min, max := testBound(field.Min), testBound(field.Max)
+	if field.Pattern != "" {
+		if value, ok := samplePattern(field.Pattern, variant, min, max); ok {
+			return value
+		}
+	}
+	n := strconv.Itoa(variant)
+	value := field.Label + " " + n
+	if max >= 0 && utf8.RuneCountInString(value) > max {
+		// Keep the variant number so the values still differ
+		runes := []rune(field.Label)
+		if keep := max - len(n); keep > 0 && keep < len(runes) {
+			value = string(runes[:keep]) + n
+		} else if keep > 0 {
+			value = field.Label + n
+		} else {
+			value = n[:max]
+		}
+	}
+	if pad := min - utf8.RuneCountInString(value); pad > 0 {
+		value += strings.Repeat(n, pad)
+	}
+	return value
+}
+
+// testNumber returns the decimal text of a number field in generated tests: the variant
+// number (plus .5 for floats), moved inside the field's min and max.
+func testNumber(field FieldData, variant int) string {
+	value := float64(variant)
+	isFloat := strings.Contains(field.Type, "float")
+	if isFloat {
+		value += 0.5
+	}
+	if min, err := strconv.ParseFloat(field.Min, 64); err == nil && value < min {
+		value = min + float64(variant-1)
+	}
+	if max, err := strconv.ParseFloat(field.Max, 64); err == nil && value > max {
+		value = max - float64(variant-1)
+		if min, err := strconv.ParseFloat(field.Min, 64); err == nil && value < min {
+			value = min
+		}
+	}
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
+
+// testRuleCases returns one value per rule of a field that breaks that rule and no earlier
+// one, in the order the service checks them. Rules that no value can break on its own,
+// such as a minimum length of 1 next to the required check, are left out.
+func testRuleCases(field FieldData) []testRuleCase {
+	if !hasFieldRules(field) {
+		return nil
+	}
+	var cases []testRuleCase
+	switch field.Type {
+	case "string":
+		min, max := testBound(field.Min), testBound(field.Max)
+		if field.Required {
+			cases = append(cases, testRuleCase{
+				Name:    "rejects an empty " + field.Label,
+				Value:   `""`,
+				Message: field.Label + " is required",
+			})
+		}
+		if min > 1 {
+			cases = append(cases, testRuleCase{
+				Name:    fmt.Sprintf("rejects a %s shorter than %d characters", field.Label, min),
+				Value:   strconv.Quote(strings.Repeat("a", min-1)),
+				Message: fmt.Sprintf("%s must be at least %d characters", field.Label, min),
+			})
+		}
+		if max >= 0 {
+			cases = append(cases, testRuleCase{
+				Name:    fmt.Sprintf("rejects a %s longer than %d characters", field.Label, max),
+				Value:   strconv.Quote(strings.Repeat("a", max+1)),
+				Message: fmt.Sprintf("%s must be at most %d characters", field.Label, max),
+			})
+		}
+		if field.Pattern != "" {
+			if value, ok := mismatchPattern(field.Pattern, min, max); ok {
+				cases = append(cases, testRuleCase{
+					Name:    "rejects a " + field.Label + " that does not match the pattern",
+					Value:   strconv.Quote(value),
+					Message: field.Label + " is not in the expected format",
+				})
+			}
+		}
+	case "int", "int64", "uint", "float64":
+		step := 1.0
+		if field.Type == "float64" {
+			step = 0.5
+		}
+		if min, err := strconv.ParseFloat(field.Min, 64); err == nil && !(field.Type == "uint" && min-step < 0) {
+			cases = append(cases, testRuleCase{
+				Name:    fmt.Sprintf("rejects a %s below %s", field.Label, field.Min),
+				Value:   testNumberLiteral(field.Type, min-step),
+				Message: field.Label + " must be at least " + field.Min,
+			})
+		}
+		if max, err := strconv.ParseFloat(field.Max, 64); err == nil {
+			cases = append(cases, testRuleCase{
+				Name:    fmt.Sprintf("rejects a %s above %s", field.Label, field.Max),
+				Value:   testNumberLiteral(field.Type, max+step),
+				Message: field.Label + " must be at most " + field.Max,
+			})
+		}
+	}
+	return cases
+}
+
+// testNumberLiteral returns the Go literal of value as a fieldType, in the style of testValue.
+func testNumberLiteral(fieldType string, value float64) string {
+	text := strconv.FormatFloat(value, 'f', -1, 64)
+	if fieldType == "int" {
+		return text
+	}
+	return fieldType + "(" + text + ")"
+}
+
+// testBound parses a length bound of a string field, -1 when unset.
+func testBound(bound string) int {
+	n, err := strconv.Atoi(bound)
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// samplePattern returns a value that matches pattern as a whole and has a length within
+// min and max (-1 when unset). Variants pick different characters and alternatives where
+// the pattern allows. It reports false when no value is found.
+func samplePattern(pattern string, variant, min, max int) (string, bool) {
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return "", false
+	}
+	parsed, err := syntax.Parse(pattern, syntax.Perl)
+	if err != nil {
+		return "", false
+	}
+	parsed = parsed.Simplify()
+	for _, v := range []int{variant, 1} {
+		// Repeat the repeatable parts more often until the value is long enough
+		for extra := 0; extra <= 64; extra++ {
+			var b strings.Builder
+			writeSample(&b, parsed, v, extra)
+			value := b.String()
+			length := utf8.RuneCountInString(value)
+			if re.MatchString(value) && strings.TrimSpace(value) != "" && (min < 0 || length >= min) && (max < 0 || length <= max) {
+				return value, true
+			}
+			if max >= 0 && length > max {
+				break
+			}
+		}
+	}
+	return "", false
+}
+
+// writeSample writes a string matched by re. Repetitions run extra times beyond their
+// minimum, within their maximum.
+func writeSample(b *strings.Builder, re *syntax.Regexp, variant, extra int) {
+	switch re.Op {
+	case syntax.OpLiteral:
+		for _, r := range re.Rune {
+			b.WriteRune(r)
+		}
+	case syntax.OpCharClass:
+		b.WriteRune(sampleRune(re.Rune, variant))
+	case syntax.OpAnyChar, syntax.OpAnyCharNotNL:
+		b.WriteRune(rune('a' + (variant-1)%26))
+	case syntax.OpCapture:
+		writeSample(b, re.Sub[0], variant, extra)
+	case syntax.OpConcat:
+		for _, sub := range re.Sub {
+			writeSample(b, sub, variant, extra)
+		}
+	case syntax.OpAlternate:
+		writeSample(b, re.Sub[(variant-1)%len(re.Sub)], variant, extra)
+	case syntax.OpStar, syntax.OpPlus, syntax.OpQuest, syntax.OpRepeat:
+		count := 0
+		switch re.Op {
+		case syntax.OpStar:
+			count = extra
+		case syntax.OpPlus:
+			count = 1 + extra
+		case syntax.OpQuest:
+			count = min(1, extra)
+		case syntax.OpRepeat:
+			count = re.Min + extra
+			if re.Max >= 0 && count > re.Max {
+				count = re.Max
+			}
+		}
+		for i := 0; i < count; i++ {
+			writeSample(b, re.Sub[0], variant, extra)
+		}
+	}
+}
+
+// sampleRune picks a rune of a character class, given as ranges of lo, hi pairs. It prefers
+// letters and digits, and the variant-th one of the first range that has them.
+func sampleRune(ranges []rune, variant int) rune {
+	for i := 0; i+1 < len(ranges); i += 2 {
+		lo, hi := ranges[i], ranges[i+1]
+		for r := lo; r <= hi && r-lo < 128; r++ {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) {
+				if pick := r + rune(variant-1); pick <= hi && (unicode.IsLetter(pick) || unicode.IsDigit(pick)) {
+					return pick
+				}
+				return r
+			}
+		}
+	}
+	if len(ranges) > 0 {
+		return ranges[0]
+	}
+	return 'a'
+}
+
+// mismatchPattern returns a non-blank value within min and max (-1 when unset) that does not
+// match pattern as a whole. It reports false when every candidate matches.
+func mismatchPattern(pattern string, min, max int) (string, bool) {
+	re, err := regexp.Compile("^(?:" + pattern + ")$")
+	if err != nil {
+		return "", false
+	}
+	for _, c := range []string{"!", "-", "0", "a", "A", "_", "~"} {
+		length := max
+		if length < 0 || length > 8 {
+			length = 8
+		}
+		if min > length {
+			length = min
+		}
+		if length < 1 {
+			length = 1
+		}
+		value := strings.Repeat(c, length)
+		if !re.MatchString(value) {
+			return value, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,119 @@
+package generator
+
+import (
+	"regexp"
+	"strconv"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestTestText(t *testing.T) {
+	tests := []struct {
+		name  string
+		field FieldData
+	}{
+		{"no rules", FieldData{Label: "Name", Type: "string"}},
+		{"short max", FieldData{Label: "Description", Type: "string", Max: "5"}},
+		{"long min", FieldData{Label: "Bio", Type: "string", Min: "12"}},
+		{"pattern", FieldData{Label: "Sku", Type: "string", Pattern: `[A-Z]{3}-\d+`}},
+		{"pattern with alternatives", FieldData{Label: "Code", Type: "string", Pattern: `(foo|bar)_[a-z]+`}},
+		{"pattern and bounds", FieldData{Label: "Slug", Type: "string", Pattern: `[a-z]+`, Min: "6", Max: "8"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			first, second := testText(tt.field, 1), testText(tt.field, 2)
+			if first == second {
+				t.Errorf("expected the variants to differ, both are %q", first)
+			}
+			for _, value := range []string{first, second} {
+				length := utf8.RuneCountInString(value)
+				if min := testBound(tt.field.Min); min >= 0 && length < min {
+					t.Errorf("%q is shorter than %d characters", value, min)
+				}
+				if max := testBound(tt.field.Max); max >= 0 && length > max {
+					t.Errorf("%q is longer than %d characters", value, max)
+				}
+				if tt.field.Pattern != "" && !regexp.MustCompile("^(?:"+tt.field.Pattern+")$").MatchString(value) {
+					t.Errorf("%q does not match %s", value, tt.field.Pattern)
+				}
+			}
+		})
+	}
+
+	if got := testText(FieldData{Label: "Status", Type: "string", Options: []string{"draft", "published"}}, 2); got != "published" {
+		t.Errorf("expected the second option, got %q", got)
+	}
+}
+
+func TestTestNumber(t *testing.T) {
+	tests := []struct {
+		field   FieldData
+		variant int
+		want    string
+	}{
+		{FieldData{Type: "int"}, 2, "2"},
+		{FieldData{Type: "float64"}, 1, "1.5"},
+		{FieldData{Type: "int", Min: "5"}, 2, "6"},
+		{FieldData{Type: "int", Min: "-3", Max: "0"}, 1, "0"},
+		{FieldData{Type: "float64", Min: "10", Max: "10"}, 2, "10"},
+	}
+	for _, tt := range tests {
+		if got := testNumber(tt.field, tt.variant); got != tt.want {
+			t.Errorf("testNumber(%+v, %d) = %s, want %s", tt.field, tt.variant, got, tt.want)
+		}
+	}
+}
+
+func TestTestRuleCases(t *testing.T) {
+	t.Run("covers each rule of a string", func(t *testing.T) {
+		field := FieldData{Label: "Sku", Type: "string", Required: true, Min: "3", Max: "10", Pattern: `[A-Z]{3}-\d+`}
+		cases := testRuleCases(field)
+		want := []string{
+			"Sku is required",
+			"Sku must be at least 3 characters",
+			"Sku must be at most 10 characters",
+			"Sku is not in the expected format",
+		}
+		if len(cases) != len(want) {
+			t.Fatalf("expected %d cases, got %+v", len(want), cases)
+		}
+		pattern := regexp.MustCompile(`^(?:[A-Z]{3}-\d+)$`)
+		for i, c := range cases {
+			if c.Message != want[i] {
+				t.Errorf("case %d: expected message %q, got %q", i, want[i], c.Message)
+			}
+			value, err := strconv.Unquote(c.Value)
+			if err != nil {
+				t.Fatalf("case %d: expected a string literal, got %s", i, c.Value)
+			}
+			if i == 3 {
+				if length := len(value); length < 3 || length > 10 || pattern.MatchString(value) {
+					t.Errorf("expected a value within the bounds that breaks the pattern, got %q", value)
+				}
+			}
+		}
+	})
+
+	t.Run("covers the bounds of numbers", func(t *testing.T) {
+		cases := testRuleCases(FieldData{Label: "Qty", Type: "int64", Min: "5", Max: "9"})
+		if len(cases) != 2 || cases[0].Value != "int64(4)" || cases[1].Value != "int64(10)" {
+			t.Errorf("expected values below and above the bounds, got %+v", cases)
+		}
+		cases = testRuleCases(FieldData{Label: "Weight", Type: "float64", Min: "2.5"})
+		if len(cases) != 1 || cases[0].Value != "float64(2)" || cases[0].Message != "Weight must be at least 2.5" {
+			t.Errorf("expected a value below the minimum, got %+v", cases)
+		}
+	})
+
+	t.Run("leaves out rules no value can break alone", func(t *testing.T) {
+		if cases := testRuleCases(FieldData{Label: "Name", Type: "string", Required: true, Min: "1"}); len(cases) != 1 {
+			t.Errorf("expected only the required case, got %+v", cases)
+		}
+		if cases := testRuleCases(FieldData{Label: "Stock", Type: "uint", Min: "0"}); len(cases) != 0 {
+			t.Errorf("expected no case below 0 for a uint, got %+v", cases)
+		}
+		if cases := testRuleCases(FieldData{Label: "Name", Type: "string"}); cases != nil {
+			t.Errorf("expected no cases without rules, got %+v", cases)
+		}
+	})
+}
@@ -26,8 +26,28 @@ const (
 // ProjectMetadata contains all scaffold metadata for a project.
 type ProjectMetadata struct {
 	Version string                    `json:"version"`
+	Project *ProjectInfo              `json:"project,omitempty"`
 	Domains map[string]DomainMetadata `json:"domains"`
 	Wizards map[string]WizardMetadata `json:"wizards,omitempty"`
+	// TemplateVersion is the scaffolder version whose template layout the project follows,
+	// recorded by upgrade_project. Until then it is the version the project was scaffolded with.
+	TemplateVersion string `json:"template_version,omitempty"`
+}
+
+// ProjectInfo records how the project itself was scaffolded.
+// Projects scaffolded before this was recorded have no ProjectInfo.
+type ProjectInfo struct {
+	ScaffoldedAt      time.Time                  `json:"scaffolded_at"`
+	ScaffolderVersion string                     `json:"scaffolder_version"`
+	Input             types.ScaffoldProjectInput `json:"input"`
+}
+
+// ProjectType returns the project type, "web" when it was not recorded.
+func (p *ProjectInfo) ProjectType() string {
+	if p == nil || p.Input.ProjectType == "" {
+		return "web"
+	}
+	return p.Input.ProjectType
 }
 
 // DomainMetadata contains metadata for a single scaffolded domain.
@@ -36,6 +56,9 @@ type DomainMetadata struct {
 	UpdatedAt         time.Time                 `json:"updated_at,omitempty"`
 	ScaffolderVersion string                    `json:"scaffolder_version"`
 	Input             types.ScaffoldDomainInput `json:"input"`
+	// Reconstructed indicates the input was rebuilt from the code by reconstruct_metadata,
+	// so the scaffolder version that generated the domain is unknown.
+	Reconstructed     bool                      `json:"reconstructed,omitempty"`
 }
 
 // WizardMetadata contains metadata for a single scaffolded wizard.
@@ -117,8 +140,15 @@ func (s *Store) Save(meta *ProjectMetadata) error {
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 
+	// Write a temporary file and rename it over the metadata, so a failed write keeps the old file
 	path := s.metadataPath()
-	if err := os.WriteFile(path, data, 0644); err != nil {
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0644); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to write metadata: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
 		return fmt.Errorf("failed to write metadata: %w", err)
 	}
 
@@ -152,6 +182,79 @@ func (s *Store) SaveDomain(domainName string, input types.ScaffoldDomainInput, s
 	return s.Save(meta)
 }
 
+// SaveProject records the scaffold_project input of the project.
+func (s *Store) SaveProject(input types.ScaffoldProjectInput, scaffolderVersion string) error {
+	meta, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	// DryRun and InCurrentDir only describe that one call
+	input.DryRun = false
+	input.InCurrentDir = false
+
+	meta.Project = &ProjectInfo{
+		ScaffoldedAt:      time.Now().UTC(),
+		ScaffolderVersion: scaffolderVersion,
+		Input:             input,
+	}
+	return s.Save(meta)
+}
+
+// GetProject retrieves the project metadata. It returns nil when none was recorded.
+func (s *Store) GetProject() (*ProjectInfo, error) {
+	meta, err := s.Load()
+	if err != nil {
+		return nil, err
+	}
+	return meta.Project, nil
+}
+
+// SaveReconstructedDomain saves the input reconstruct_metadata rebuilt for a domain.
+func (s *Store) SaveReconstructedDomain(domainName string, input types.ScaffoldDomainInput) error {
+	meta, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	meta.Domains[domainName] = DomainMetadata{
+		ScaffoldedAt:  time.Now().UTC(),
+		Input:         input,
+		Reconstructed: true,
+	}
+	return s.Save(meta)
+}
+
+// UpdateDomainInput replaces the recorded input of a domain, keeping the scaffolder version
+// that generated it.
+func (s *Store) UpdateDomainInput(domainName string, input types.ScaffoldDomainInput) error {
+	meta, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	domainMeta, exists := meta.Domains[domainName]
+	if !exists {
+		return fmt.Errorf("no metadata for domain '%s'", domainName)
+	}
+	domainMeta.Input = input
+	domainMeta.UpdatedAt = time.Now().UTC()
+
+	meta.Domains[domainName] = domainMeta
+	return s.Save(meta)
+}
+
+// SetTemplateVersion records the scaffolder version whose template layout the project follows.
+func (s *Store) SetTemplateVersion(version string) error {
+	meta, err := s.Load()
+	if err != nil {
+		return err
+	}
+
+	meta.TemplateVersion = version
+	return s.Save(meta)
+}
+
 // SaveWizard saves or updates metadata for a single wizard.
 func (s *Store) SaveWizard(wizardName, domain string, input types.ScaffoldWizardInput, scaffolderVersion string) error {
 	meta, err := s.Load()
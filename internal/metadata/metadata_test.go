@@ -503,3 +503,57 @@ func TestStore_UpdateWizard(t *testing.T) {
 		t.Errorf("len(Input.Steps) = %d, want 2", len(wizard.Input.Steps))
 	}
 }
+
+func TestStore_SaveProject(t *testing.T) {
+	tmpDir, err := os.MkdirTemp("", "metadata-test-*")
+	if err != nil {
+		t.Fatalf("failed to create temp dir: %v", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	store := NewStore(tmpDir)
+
+	// No project recorded yet
+	project, err := store.GetProject()
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if project != nil {
+		t.Errorf("GetProject() = %+v, want nil", project)
+	}
+	if got := project.ProjectType(); got != "web" {
+		t.Errorf("ProjectType() of missing project = %q, want web", got)
+	}
+
+	input := types.ScaffoldProjectInput{
+		ProjectName:  "shop",
+		ModulePath:   "github.com/test/shop",
+		ProjectType:  "api",
+		InCurrentDir: true,
+	}
+	if err := store.SaveProject(input, "0.1.0"); err != nil {
+		t.Fatalf("SaveProject() error = %v", err)
+	}
+
+	// Domains saved later must keep the project
+	if err := store.SaveDomain("order", types.ScaffoldDomainInput{DomainName: "order"}, "0.1.0"); err != nil {
+		t.Fatalf("SaveDomain() error = %v", err)
+	}
+
+	project, err = store.GetProject()
+	if err != nil {
+		t.Fatalf("GetProject() error = %v", err)
+	}
+	if project == nil {
+		t.Fatal("GetProject() = nil, want project")
+	}
+	if got := project.ProjectType(); got != "api" {
+		t.Errorf("ProjectType() = %q, want api", got)
+	}
+	if project.Input.InCurrentDir {
+		t.Error("InCurrentDir should not be recorded")
+	}
+	if project.ScaffoldedAt.IsZero() {
+		t.Error("ScaffoldedAt should be set")
+	}
+}
@@ -35,13 +35,51 @@ const (
 	MarkerRoutesAuthenticatedEnd   = "MCP:ROUTES:AUTHENTICATED:END"
 	MarkerRoutesAdminStart         = "MCP:ROUTES:ADMIN:START"
 	MarkerRoutesAdminEnd           = "MCP:ROUTES:ADMIN:END"
-	// Navigation item markers (in base_layout.templ)
-	MarkerNavItemsStart      = "MCP:NAV_ITEMS:START"
-	MarkerNavItemsEnd        = "MCP:NAV_ITEMS:END"
-	MarkerNavItemsAdminStart = "MCP:NAV_ITEMS_ADMIN:START"
-	MarkerNavItemsAdminEnd   = "MCP:NAV_ITEMS_ADMIN:END"
+	// Reindexer markers (in cmd/reindex/main.go)
+	MarkerReindexStart = "MCP:REINDEX:START"
+	MarkerReindexEnd   = "MCP:REINDEX:END"
+	// Seeder markers (in cmd/seed/seeders/seeders.go All)
+	MarkerSeedersStart = "MCP:SEEDERS:START"
+	MarkerSeedersEnd   = "MCP:SEEDERS:END"
+	// Image column markers (in cmd/reprocess-images/main.go)
+	MarkerImagesStart = "MCP:IMAGES:START"
+	MarkerImagesEnd   = "MCP:IMAGES:END"
+	// Sort column whitelist markers (in domain controllers)
+	MarkerSortColumnsStart = "MCP:SORT_COLUMNS:START"
+	MarkerSortColumnsEnd   = "MCP:SORT_COLUMNS:END"
+	// Migration step markers (in database.go RunMigrations, after AutoMigrate)
+	MarkerMigrationsStart = "MCP:MIGRATIONS:START"
+	MarkerMigrationsEnd   = "MCP:MIGRATIONS:END"
+
+	MarkerSchedulerStart = "MCP:SCHEDULER:START"
+	MarkerSchedulerEnd   = "MCP:SCHEDULER:END"
+	// Request middleware markers (in main.go, before any route is registered)
+	MarkerMiddlewareStart = "MCP:MIDDLEWARE:START"
+	MarkerMiddlewareEnd   = "MCP:MIDDLEWARE:END"
+	// Database plugin markers (in database.go Connect, right after the connection is opened)
+	MarkerDBPluginsStart = "MCP:DB_PLUGINS:START"
+	MarkerDBPluginsEnd   = "MCP:DB_PLUGINS:END"
+
+	MarkerHealthChecksStart = "MCP:HEALTH_CHECKS:START"
+	MarkerHealthChecksEnd   = "MCP:HEALTH_CHECKS:END"
+
+	MarkerProvidersStart = "MCP:PROVIDERS:START"
+	MarkerProvidersEnd   = "MCP:PROVIDERS:END"
+
+	MarkerDomainsStart = "MCP:DOMAINS:START"
+	MarkerDomainsEnd   = "MCP:DOMAINS:END"
+
+	MarkerDomainRoutesStart = "MCP:DOMAIN_ROUTES:START"
+	MarkerDomainRoutesEnd   = "MCP:DOMAIN_ROUTES:END"
+
+	// TypeScript types index markers (in web/src/types/index.ts or index.d.ts)
+	MarkerTypesStart = "MCP:TYPES:START"
+	MarkerTypesEnd   = "MCP:TYPES:END"
 )
 
+// TenantIDField is the model field of tenant-scoped domains, as the model template writes it.
+const TenantIDField = "TenantID  uint           `gorm:\"not null;default:0;index\" json:\"-\"`"
+
 // Injector handles code injection into files using marker comments.
 type Injector struct {
 	filePath string
@@ -196,6 +234,34 @@ func (i *Injector) ReplaceMarkerContent(startMarker, endMarker, code string) err
 	return nil
 }
 
+// MarkerContent returns the code between START and END markers, with the END marker's
+// indentation removed so it can be passed back to ReplaceMarkerContent.
+func (i *Injector) MarkerContent(startMarker, endMarker string) (string, error) {
+	startPattern := regexp.MustCompile(`(?m)^(\s*)//\s*` + regexp.QuoteMeta(startMarker) + `\s*$`)
+	endPattern := regexp.MustCompile(`(?m)^(\s*)//\s*` + regexp.QuoteMeta(endMarker) + `\s*$`)
+
+	startMatch := startPattern.FindStringSubmatchIndex(i.content)
+	endMatch := endPattern.FindStringSubmatchIndex(i.content)
+
+	if startMatch == nil {
+		return "", fmt.Errorf("start marker not found: %s", startMarker)
+	}
+	if endMatch == nil {
+		return "", fmt.Errorf("end marker not found: %s", endMarker)
+	}
+	if startMatch[0] >= endMatch[0] {
+		return "", fmt.Errorf("start marker must come before end marker")
+	}
+
+	// Strip the end marker's indentation from each line
+	indent := i.content[endMatch[2]:endMatch[3]]
+	lines := strings.Split(strings.Trim(i.content[startMatch[1]:endMatch[0]], "\n"), "\n")
+	for n, line := range lines {
+		lines[n] = strings.TrimPrefix(line, indent)
+	}
+	return strings.TrimSpace(strings.Join(lines, "\n")), nil
+}
+
 // HasMarker checks if a marker exists in the content.
 func (i *Injector) HasMarker(marker string) bool {
 	pattern := regexp.MustCompile(`(?m)//\s*` + regexp.QuoteMeta(marker))
@@ -275,11 +341,257 @@ func (i *Injector) InjectModel(modelName string) error {
 	return i.InjectBetweenMarkers(MarkerModelsStart, MarkerModelsEnd, modelCode)
 }
 
+// InjectMigration adds a migration step that runs after AutoMigrate, such as
+// "models.MigrateProductSearch(db)". The step's error aborts the migrations.
+func (i *Injector) InjectMigration(call string) error {
+	// The multi-line step is indented on injection, so check for its first line
+	if strings.Contains(i.content, "if err := "+call+";") {
+		return nil
+	}
+	code := fmt.Sprintf("if err := %s; err != nil {\n\treturn err\n}", call)
+	return i.InjectBetweenMarkers(MarkerMigrationsStart, MarkerMigrationsEnd, code)
+}
+
+// InjectMiddleware adds router.Use(handler) to main.go, before any route is registered.
+// Projects scaffolded without the MCP:MIDDLEWARE markers get them after web.NewRouter.
+func (i *Injector) InjectMiddleware(handler string) error {
+	if !i.HasMarker(MarkerMiddlewareStart) {
+		if err := i.addMarkersNextTo("router := web.NewRouter(", true, MarkerMiddlewareStart, MarkerMiddlewareEnd); err != nil {
+			return err
+		}
+	}
+	return i.InjectBetweenMarkers(MarkerMiddlewareStart, MarkerMiddlewareEnd, "router.Use("+handler+")")
+}
+
+// InjectDBPlugin adds a plugin registration, such as "tenancy.Register(db)", to database.Connect.
+// A registration error stops the program. Projects scaffolded without the MCP:DB_PLUGINS markers
+// get them before the end of Connect.
+func (i *Injector) InjectDBPlugin(call string) error {
+	if strings.Contains(i.content, "if err := "+call+";") {
+		return nil
+	}
+	if !i.HasMarker(MarkerDBPluginsStart) {
+		if err := i.addMarkersNextTo("// Ensure base model is used", false, MarkerDBPluginsStart, MarkerDBPluginsEnd); err != nil {
+			return err
+		}
+	}
+	code := fmt.Sprintf("if err := %s; err != nil {\n\t%s\n}", call, i.exitOnError("Failed to register database plugin"))
+	return i.InjectBetweenMarkers(MarkerDBPluginsStart, MarkerDBPluginsEnd, code)
+}
+
+// HealthHandlerVariable is the variable holding the health handler in main.go.
+const HealthHandlerVariable = "healthHandler"
+
+// InjectHealthHandler creates the health handler among the services in main.go, once the
+// migrations have run, followed by the MCP:HEALTH_CHECKS markers for readiness checks, and
+// registers its routes (/healthz, /readyz, /version) with the general routes.
+func (i *Injector) InjectHealthHandler() error {
+	if strings.Contains(i.content, HealthHandlerVariable+" := ") {
+		return nil
+	}
+	code := HealthHandlerVariable + " := health.NewHandler(db)\n" + HealthHandlerVariable + ".MarkMigrated()"
+	if err := i.InjectBetweenMarkers(MarkerServicesStart, MarkerServicesEnd, code); err != nil {
+		return err
+	}
+	if !i.HasMarker(MarkerHealthChecksStart) {
+		if err := i.addMarkersNextTo("// "+MarkerServicesEnd, true, MarkerHealthChecksStart, MarkerHealthChecksEnd); err != nil {
+			return err
+		}
+	}
+	return i.InjectBetweenMarkers(MarkerRoutesStart, MarkerRoutesEnd, HealthHandlerVariable+".RegisterRoutes(router)")
+}
+
+// InjectHealthCheck adds a readiness check, such as `"cache", cache.Ping`, to the health
+// handler in main.go. Checks are added once per name.
+func (i *Injector) InjectHealthCheck(name, check string) error {
+	if strings.Contains(i.content, fmt.Sprintf(`%s.AddCheck("%s", `, HealthHandlerVariable, name)) {
+		return nil
+	}
+	code := fmt.Sprintf(`%s.AddCheck("%s", %s)`, HealthHandlerVariable, name, check)
+	return i.InjectBetweenMarkers(MarkerHealthChecksStart, MarkerHealthChecksEnd, code)
+}
+
+// addMarkersNextTo inserts an empty start/end marker pair after (or before) the first line
+// containing anchor, indented like that line.
+func (i *Injector) addMarkersNextTo(anchor string, after bool, startMarker, endMarker string) error {
+	pattern := regexp.MustCompile(`(?m)^([ \t]*).*` + regexp.QuoteMeta(anchor) + `.*$`)
+	match := pattern.FindStringSubmatchIndex(i.content)
+	if match == nil {
+		return fmt.Errorf("line not found: %s", anchor)
+	}
+	indent := i.content[match[2]:match[3]]
+	markers := indent + "// " + startMarker + "\n" + indent + "// " + endMarker
+	if after {
+		i.content = i.content[:match[1]] + "\n" + markers + i.content[match[1]:]
+	} else {
+		i.content = i.content[:match[0]] + markers + "\n" + i.content[match[0]:]
+	}
+	return nil
+}
+
+// InjectTenantID adds TenantIDField to a model struct, unless it already has a TenantID.
+// The field goes where the model template puts it, after the timestamps, so a synced model
+// declares it once.
+func (i *Injector) InjectTenantID() error {
+	if regexp.MustCompile(`(?m)^\s*TenantID\s`).MatchString(i.content) {
+		return nil
+	}
+	for _, anchor := range []string{`DeletedAt\s+gorm\.DeletedAt`, `UpdatedAt\s+time\.Time`} {
+		match := regexp.MustCompile(`(?m)^([ \t]*)` + anchor + `.*$`).FindStringSubmatchIndex(i.content)
+		if match == nil {
+			continue
+		}
+		indent := i.content[match[2]:match[3]]
+		i.content = i.content[:match[1]] + "\n" + indent + TenantIDField + i.content[match[1]:]
+		return nil
+	}
+	return fmt.Errorf("no UpdatedAt field to add TenantID after")
+}
+
+// InjectScheduledTask starts a domain service's UpdatePublishStates on the scheduler in main.go.
+func (i *Injector) InjectScheduledTask(domainName string) error {
+	service := utils.ToServiceVariableName(domainName)
+	if i.usesWire() {
+		service = WireAppVariable + "." + utils.ToModelName(domainName) + "Service"
+	}
+	code := fmt.Sprintf(`scheduler.Start("%s publishing", %s.UpdatePublishStates)`, utils.ToTableName(domainName), service)
+	return i.InjectBetweenMarkers(MarkerSchedulerStart, MarkerSchedulerEnd, code)
+}
+
+// WebhookDispatcherVariable is the variable holding the webhook dispatcher in main.go.
+const WebhookDispatcherVariable = "webhookDispatcher"
+
+// InjectWebhookDispatcher creates the webhook dispatcher among the services in main.go and
+// starts its deliveries on the scheduler.
+func (i *Injector) InjectWebhookDispatcher() error {
+	code := WebhookDispatcherVariable + " := webhooks.NewDispatcher(db)"
+	if err := i.InjectBetweenMarkers(MarkerServicesStart, MarkerServicesEnd, code); err != nil {
+		return err
+	}
+	code = fmt.Sprintf(`scheduler.Start("webhook deliveries", %s.Deliver)`, WebhookDispatcherVariable)
+	return i.InjectBetweenMarkers(MarkerSchedulerStart, MarkerSchedulerEnd, code)
+}
+
+// InjectWebhookService wraps a domain service in main.go so that its writes emit webhook events.
+// The service must already be created: the wrapper is added after the existing services.
+func (i *Injector) InjectWebhookService(domainName string) error {
+	varName := utils.ToServiceVariableName(domainName)
+	if !strings.Contains(i.content, varName+" := ") {
+		return fmt.Errorf("service not found: %s", varName)
+	}
+	code := fmt.Sprintf(`%s = %s.NewWebhookService(%s, %s)`, varName, utils.ToServiceImportAlias(domainName), varName, WebhookDispatcherVariable)
+	return i.InjectBetweenMarkers(MarkerServicesStart, MarkerServicesEnd, code)
+}
+
+// RealtimeHubVariable is the variable holding the realtime hub in main.go.
+const RealtimeHubVariable = "realtimeHub"
+
+// InjectRealtimeService wraps a domain service in main.go so that its writes publish realtime
+// events. The service must already be created: the wrapper is added after the existing services.
+func (i *Injector) InjectRealtimeService(domainName string) error {
+	varName := utils.ToServiceVariableName(domainName)
+	if !strings.Contains(i.content, varName+" := ") {
+		return fmt.Errorf("service not found: %s", varName)
+	}
+	code := fmt.Sprintf(`%s = %s.NewRealtimeService(%s, %s)`, varName, utils.ToServiceImportAlias(domainName), varName, RealtimeHubVariable)
+	return i.InjectBetweenMarkers(MarkerServicesStart, MarkerServicesEnd, code)
+}
+
+// RealtimeUserVariable is the function in main.go returning the session user's ID to the
+// realtime event streams of owned domains.
+const RealtimeUserVariable = "realtimeUser"
+
+// RealtimeStreamRoute returns the route of a domain's realtime event stream, without the router
+// variable (e.g., Get("/events/orders", realtimeHub.Stream("order", realtimeUser))).
+func RealtimeStreamRoute(domainName string, owned bool) string {
+	user := "nil"
+	if owned {
+		user = RealtimeUserVariable
+	}
+	return fmt.Sprintf(`Get("%s", %s.Stream("%s", %s))`, utils.ToRealtimeStreamPath(domainName), RealtimeHubVariable, utils.ToSnakeCase(domainName), user)
+}
+
+// InjectRealtimeRoute mounts a domain's realtime event stream at /events/{path} in the domain's
+// route group, so the stream sits behind the middleware of the domain's pages. The streams of
+// owned domains only carry the session user's records. Admin streams go in the authenticated
+// group when main.go has no admin group. In apps with auth, the streams of authenticated and
+// admin domains are never mounted outside a protected group.
+func (i *Injector) InjectRealtimeRoute(domainName, routeGroup string, owned bool) error {
+	if owned && !strings.Contains(i.content, RealtimeUserVariable+" := ") {
+		code := RealtimeUserVariable + ` := func(r *http.Request) uint {
+	if user := middleware.GetUserFromContext(r.Context()); user != nil {
+		return user.ID
+	}
+	return 0
+}`
+		if err := i.InjectBetweenMarkers(MarkerServicesStart, MarkerServicesEnd, code); err != nil {
+			return err
+		}
+	}
+	stream := RealtimeStreamRoute(domainName, owned)
+
+	if routeGroup == "authenticated" || routeGroup == "admin" {
+		startMarker, endMarker := MarkerRoutesAuthenticatedStart, MarkerRoutesAuthenticatedEnd
+		if routeGroup == "admin" && i.HasMarker(MarkerRoutesAdminStart) && i.HasMarker(MarkerRoutesAdminEnd) {
+			startMarker, endMarker = MarkerRoutesAdminStart, MarkerRoutesAdminEnd
+		}
+		if i.HasMarker(startMarker) && i.HasMarker(endMarker) {
+			return i.InjectBetweenMarkers(startMarker, endMarker, "r."+stream)
+		}
+		// Without auth, every route group is public
+		if strings.Contains(i.content, "RequireAuth") {
+			return fmt.Errorf("no %s route group to mount the %s event stream in", routeGroup, domainName)
+		}
+	}
+
+	if i.HasMarker(MarkerRoutesPublicStart) && i.HasMarker(MarkerRoutesPublicEnd) {
+		return i.InjectBetweenMarkers(MarkerRoutesPublicStart, MarkerRoutesPublicEnd, "router."+stream)
+	}
+	return i.InjectBetweenMarkers(MarkerRoutesStart, MarkerRoutesEnd, "router."+stream)
+}
+
+// LoggerVariable is the variable holding the structured logger in main.go.
+const LoggerVariable = "logger"
+
+// hasLogger reports whether main.go creates the structured logger, which is then passed
+// to the domain repositories, services and controllers.
+func (i *Injector) hasLogger() bool {
+	return strings.Contains(i.content, LoggerVariable+" := logging.Setup(")
+}
+
+// loggerArg returns the logger argument appended to domain constructor calls, if any.
+func (i *Injector) loggerArg() string {
+	if i.hasLogger() {
+		return ", " + LoggerVariable
+	}
+	return ""
+}
+
+// exitOnError returns the code that logs err and stops the program. Files logging with slog
+// get slog calls, through main's logger when there is one; older files keep log.Fatalf.
+func (i *Injector) exitOnError(msg string) string {
+	switch {
+	case i.hasLogger():
+		return fmt.Sprintf("%s.Error(%q, \"error\", err)\n\tos.Exit(1)", LoggerVariable, msg)
+	case strings.Contains(i.content, `"log/slog"`):
+		return fmt.Sprintf("slog.Error(%q, \"error\", err)\n\tos.Exit(1)", msg)
+	}
+	return fmt.Sprintf("log.Fatalf(\"%s: %%v\", err)", msg)
+}
+
 // InjectRepo adds a repository instantiation.
 func (i *Injector) InjectRepo(domainName, modulePath string) error {
 	varName := utils.ToRepoVariableName(domainName)
 	pkgAlias := utils.ToRepoImportAlias(domainName)
-	code := fmt.Sprintf(`%s := %s.NewRepository(db)`, varName, pkgAlias)
+	code := fmt.Sprintf(`%s := %s.NewRepository(db%s)`, varName, pkgAlias, i.loggerArg())
+	return i.InjectBetweenMarkers(MarkerReposStart, MarkerReposEnd, code)
+}
+
+// InjectCachedRepo adds a repository instantiation wrapped in the caching decorator.
+func (i *Injector) InjectCachedRepo(domainName string) error {
+	varName := utils.ToRepoVariableName(domainName)
+	pkgAlias := utils.ToRepoImportAlias(domainName)
+	code := fmt.Sprintf(`%s := %s.NewCachedRepository(db, %s.NewRepository(db%s), cache.Default())`, varName, pkgAlias, pkgAlias, i.loggerArg())
 	return i.InjectBetweenMarkers(MarkerReposStart, MarkerReposEnd, code)
 }
 
@@ -288,7 +600,7 @@ func (i *Injector) InjectService(domainName string) error {
 	varName := utils.ToServiceVariableName(domainName)
 	repoVarName := utils.ToRepoVariableName(domainName)
 	pkgAlias := utils.ToServiceImportAlias(domainName)
-	code := fmt.Sprintf(`%s := %s.NewService(%s)`, varName, pkgAlias, repoVarName)
+	code := fmt.Sprintf(`%s := %s.NewService(%s%s)`, varName, pkgAlias, repoVarName, i.loggerArg())
 	return i.InjectBetweenMarkers(MarkerServicesStart, MarkerServicesEnd, code)
 }
 
@@ -311,11 +623,55 @@ func (i *Injector) InjectControllerWithRelations(domainName string, relatedDomai
 		relServiceVarName := utils.ToServiceVariableName(relDomain)
 		args += ", " + relServiceVarName
 	}
+	args += i.loggerArg()
 
 	code := fmt.Sprintf(`%s := %s.NewController(%s)`, varName, pkgAlias, args)
 	return i.InjectBetweenMarkers(MarkerControllersStart, MarkerControllersEnd, code)
 }
 
+// WireAppVariable is the variable holding the domains built by initializeDomains in the
+// main.go of projects with di wire.
+const WireAppVariable = "app"
+
+// usesWire reports whether main.go gets the domains from the wire injector instead of
+// creating their repositories, services and controllers itself.
+func (i *Injector) usesWire() bool {
+	return strings.Contains(i.content, WireAppVariable+", err := initializeDomains(")
+}
+
+// InjectWireProviderSet adds a domain's provider set to the wire injector in cmd/web/wire.go.
+func (i *Injector) InjectWireProviderSet(domainName, modulePath string) error {
+	alias := utils.ToControllerImportAlias(domainName)
+	if err := i.InjectImportWithAlias(modulePath+"/internal/web/"+utils.ToPackageName(domainName), alias); err != nil {
+		return err
+	}
+	return i.InjectBetweenMarkers(MarkerProvidersStart, MarkerProvidersEnd, alias+".ProviderSet,")
+}
+
+// InjectWireDomain adds a domain's controller and service to the domains struct in
+// cmd/web/domains.go, which the wire injector fills in. Format the file to align the fields.
+func (i *Injector) InjectWireDomain(domainName, modulePath string) error {
+	pkgName := utils.ToPackageName(domainName)
+	controllerAlias := utils.ToControllerImportAlias(domainName)
+	serviceAlias := utils.ToServiceImportAlias(domainName)
+	if err := i.InjectImportWithAlias(modulePath+"/internal/services/"+pkgName, serviceAlias); err != nil {
+		return err
+	}
+	if err := i.InjectImportWithAlias(modulePath+"/internal/web/"+pkgName, controllerAlias); err != nil {
+		return err
+	}
+	modelName := utils.ToModelName(domainName)
+	code := fmt.Sprintf("%sController *%s.Controller\n%sService %s.Service", modelName, controllerAlias, modelName, serviceAlias)
+	return i.InjectBetweenMarkers(MarkerDomainsStart, MarkerDomainsEnd, code)
+}
+
+// InjectWireController takes a domain's controller from the domains the wire injector built,
+// so routes are mounted as in projects that create their controllers in main.go.
+func (i *Injector) InjectWireController(domainName string) error {
+	code := fmt.Sprintf(`%s := %s.%sController`, utils.ToControllerVariableName(domainName), WireAppVariable, utils.ToModelName(domainName))
+	return i.InjectBetweenMarkers(MarkerControllersStart, MarkerControllersEnd, code)
+}
+
 // InjectRoute adds a route registration to the default (public) route group.
 // Routes are mounted at the default URL path (e.g., /products for "product" domain).
 // Users can later modify the path in main.go to mount under custom prefixes like /admin/products.
@@ -362,44 +718,227 @@ func (i *Injector) InjectRouteWithGroup(domainName, routeGroup string) error {
 	return i.InjectBetweenMarkers(MarkerRoutesStart, MarkerRoutesEnd, code)
 }
 
+// APIVersionMarkers returns the markers of an API version's sub-router in main.go
+// (MCP:ROUTES:API_V1:START and MCP:ROUTES:API_V1:END for "v1").
+func APIVersionMarkers(version string) (start, end string) {
+	prefix := "MCP:ROUTES:API_" + strings.ToUpper(version)
+	return prefix + ":START", prefix + ":END"
+}
+
+// InjectAPIVersionRoute mounts a domain's routes in the sub-router of an API version
+// (e.g., /api/v1/products for "v1"). The sub-router is created on first use, with the
+// middleware.APIVersion middleware, in the public route group or the general MCP:ROUTES markers.
+// main.go must import chi and the middleware package.
+func (i *Injector) InjectAPIVersionRoute(domainName, version string) error {
+	if err := i.injectAPIVersionRouter(version); err != nil {
+		return err
+	}
+	startMarker, endMarker := APIVersionMarkers(version)
+	code := fmt.Sprintf(`r.Route("%s", %s.RegisterRoutes)`, utils.ToURLPath(domainName), utils.ToControllerVariableName(domainName))
+	return i.InjectBetweenMarkers(startMarker, endMarker, code)
+}
+
+// injectAPIVersionRouter creates the sub-router of an API version unless main.go has it.
+// With the routes registry, the sub-router mounts the controllers of the api_vN route group.
+func (i *Injector) injectAPIVersionRouter(version string) error {
+	startMarker, endMarker := APIVersionMarkers(version)
+	if i.HasMarker(startMarker) {
+		return nil
+	}
+	mount := ""
+	if i.HasMarker(MarkerDomainRoutesStart) {
+		mount = fmt.Sprintf("\n\t%s.Mount(r, routes.Group(%q))", DomainRoutesVariable, "api_"+version)
+	}
+	block := fmt.Sprintf(`router.Route("/api/%s", func(r chi.Router) {
+	r.Use(middleware.APIVersion(%q))%s
+	// %s
+	// %s
+})`, version, version, mount, startMarker, endMarker)
+	groupStart, groupEnd := MarkerRoutesPublicStart, MarkerRoutesPublicEnd
+	if !i.HasMarker(groupStart) || !i.HasMarker(groupEnd) {
+		groupStart, groupEnd = MarkerRoutesStart, MarkerRoutesEnd
+	}
+	return i.InjectBetweenMarkers(groupStart, groupEnd, block)
+}
+
+// DomainRoutesVariable is the routes.Registry in main.go that mounts the domain controllers.
+const DomainRoutesVariable = "domainRoutes"
+
+// InjectDomainRoutes adds a domain controller to the routes registry of main.go, which mounts it
+// in the route group its routes.go names. Versioned API groups get their sub-router first, so
+// main.go must then import chi and the middleware package.
+func (i *Injector) InjectDomainRoutes(domainName, routeGroup string) error {
+	if version := utils.APIVersionFromRouteGroup(routeGroup); version != "" {
+		if err := i.injectAPIVersionRouter(version); err != nil {
+			return err
+		}
+	}
+	code := fmt.Sprintf(`%s.Add(%s)`, DomainRoutesVariable, utils.ToControllerVariableName(domainName))
+	return i.InjectBetweenMarkers(MarkerDomainRoutesStart, MarkerDomainRoutesEnd, code)
+}
+
+// InjectModerationRoute mounts a domain's moderation queue at /admin/{path}/moderation.
+// The queue goes in the admin route group when main.go has one; otherwise it falls back to the
+// general MCP:ROUTES markers and must be protected by hand. Returns whether it was mounted as admin.
+func (i *Injector) InjectModerationRoute(domainName string) (bool, error) {
+	varName := utils.ToControllerVariableName(domainName)
+	urlPath := "/admin" + utils.ToURLPath(domainName) + "/moderation"
+
+	if i.HasMarker(MarkerRoutesAdminStart) && i.HasMarker(MarkerRoutesAdminEnd) {
+		code := fmt.Sprintf(`r.Route("%s", %s.RegisterModerationRoutes)`, urlPath, varName)
+		return true, i.InjectBetweenMarkers(MarkerRoutesAdminStart, MarkerRoutesAdminEnd, code)
+	}
+
+	code := fmt.Sprintf(`router.Route("%s", %s.RegisterModerationRoutes)`, urlPath, varName)
+	return false, i.InjectBetweenMarkers(MarkerRoutesStart, MarkerRoutesEnd, code)
+}
+
+// InjectSearchIndex wires a domain's search indexing in main.go: it creates the shared
+// searchIndexer once, wraps the domain service with WithSearchIndex and adds the search controller.
+func (i *Injector) InjectSearchIndex(domainName string) error {
+	if !strings.Contains(i.content, "search.NewFromEnv()") {
+		code := "searchIndexer, err := search.NewFromEnv()\nif err != nil {\n\t" + i.exitOnError("Failed to configure search") + "\n}"
+		if err := i.InjectBetweenMarkers(MarkerServicesStart, MarkerServicesEnd, code); err != nil {
+			return err
+		}
+	}
+
+	serviceVarName := utils.ToServiceVariableName(domainName)
+	code := fmt.Sprintf(`%s = %s.WithSearchIndex(%s, searchIndexer)`, serviceVarName, utils.ToServiceImportAlias(domainName), serviceVarName)
+	if err := i.InjectBetweenMarkers(MarkerServicesStart, MarkerServicesEnd, code); err != nil {
+		return err
+	}
+
+	code = fmt.Sprintf(`%sSearchController := %s.NewSearchController(searchIndexer)`, utils.ToCamelCase(domainName), utils.ToControllerImportAlias(domainName))
+	return i.InjectBetweenMarkers(MarkerControllersStart, MarkerControllersEnd, code)
+}
+
+// InjectSearchRoute mounts a domain's search endpoint at {path}/search in the given route group,
+// falling back to the general MCP:ROUTES markers like InjectRouteWithGroup.
+func (i *Injector) InjectSearchRoute(domainName, routeGroup string) error {
+	varName := utils.ToCamelCase(domainName) + "SearchController"
+	urlPath := utils.ToURLPath(domainName) + "/search"
+
+	var startMarker, endMarker string
+	switch routeGroup {
+	case "authenticated":
+		startMarker, endMarker = MarkerRoutesAuthenticatedStart, MarkerRoutesAuthenticatedEnd
+	case "admin":
+		startMarker, endMarker = MarkerRoutesAdminStart, MarkerRoutesAdminEnd
+	default:
+		startMarker, endMarker = MarkerRoutesPublicStart, MarkerRoutesPublicEnd
+	}
+
+	if i.HasMarker(startMarker) && i.HasMarker(endMarker) {
+		routerVar := "router"
+		if routeGroup == "authenticated" || routeGroup == "admin" {
+			routerVar = "r"
+		}
+		code := fmt.Sprintf(`%s.Get("%s", %s.Search)`, routerVar, urlPath, varName)
+		return i.InjectBetweenMarkers(startMarker, endMarker, code)
+	}
+
+	code := fmt.Sprintf(`router.Get("%s", %s.Search)`, urlPath, varName)
+	return i.InjectBetweenMarkers(MarkerRoutesStart, MarkerRoutesEnd, code)
+}
+
+// InjectReindexer registers a domain's reindex function in cmd/reindex/main.go.
+func (i *Injector) InjectReindexer(domainName string) error {
+	code := fmt.Sprintf(`"%s": search.Reindex%s,`, domainName, utils.Pluralize(utils.ToModelName(domainName)))
+	return i.InjectBetweenMarkers(MarkerReindexStart, MarkerReindexEnd, code)
+}
+
+// InjectSeeder registers a domain's seeder in the All map of cmd/seed/seeders/seeders.go.
+func (i *Injector) InjectSeeder(domainName string) error {
+	code := fmt.Sprintf(`"%s": New%sSeeder(db),`, domainName, utils.ToModelName(domainName))
+	return i.InjectBetweenMarkers(MarkerSeedersStart, MarkerSeedersEnd, code)
+}
+
+// InjectUploadsRoute mounts storage.Handler at /uploads/ in main.go so files kept in
+// disk storage are served. It is injected once, into the general MCP:ROUTES markers.
+func (i *Injector) InjectUploadsRoute() error {
+	if strings.Contains(i.content, "storage.Handler()") {
+		return nil
+	}
+	return i.InjectBetweenMarkers(MarkerRoutesStart, MarkerRoutesEnd, `router.Handle("/uploads/*", storage.Handler())`)
+}
+
+// InjectImageColumn registers a column holding image keys in cmd/reprocess-images/main.go.
+func (i *Injector) InjectImageColumn(table, column string) error {
+	code := fmt.Sprintf(`{Table: "%s", Column: "%s"},`, table, column)
+	return i.InjectBetweenMarkers(MarkerImagesStart, MarkerImagesEnd, code)
+}
+
+// InjectSortColumn whitelists a ?sort= key in a domain controller's sortColumns map.
+func (i *Injector) InjectSortColumn(key, column string) error {
+	code := fmt.Sprintf(`"%s": "%s",`, key, column)
+	return i.InjectBetweenMarkers(MarkerSortColumnsStart, MarkerSortColumnsEnd, code)
+}
+
 // InjectRelationship adds a relationship field to a model struct.
 // This is used to inject inverse relationships when scaffolding related domains.
 func (i *Injector) InjectRelationship(fieldCode string) error {
 	return i.InjectBetweenMarkers(MarkerRelationshipsStart, MarkerRelationshipsEnd, fieldCode)
 }
 
-// InjectNavItem adds a navigation item to the sidebar in base_layout.templ.
-// routeGroup determines which nav section to add to: "authenticated" or "admin".
-// icon should be a valid icon name (e.g., "folder", "list", "file-text").
-func (i *Injector) InjectNavItem(domainName, routeGroup, icon string) error {
+// InjectMenuItem appends a navigation entry to menu.toml.
+// routeGroup determines the menu section: "admin" or "main" for everything else.
+// icon should be a valid icon name (e.g., "folder", "users", "cog").
+// An entry whose url is already in the menu is skipped.
+func (i *Injector) InjectMenuItem(domainName, routeGroup, icon string) error {
 	urlPath := utils.ToURLPath(domainName)
-	label := utils.ToLabel(domainName)
 	// Pluralize the label for nav items (e.g., "Product" -> "Products")
-	label = utils.Pluralize(label)
+	label := utils.Pluralize(utils.ToLabel(domainName))
 
 	// Default icon if not provided
 	if icon == "" {
 		icon = "folder"
 	}
 
-	// Generate the navItem templ call
-	code := fmt.Sprintf(`@navItem("%s", "%s", "%s", false)`, urlPath, icon, label)
+	section := "main"
+	if routeGroup == "admin" {
+		section = "admin"
+	}
+	return i.InjectMenuLink(section, label, urlPath, icon)
+}
 
-	// Determine which markers to use based on route group
-	var startMarker, endMarker string
-	switch routeGroup {
-	case "admin":
-		startMarker = MarkerNavItemsAdminStart
-		endMarker = MarkerNavItemsAdminEnd
-	default: // "authenticated" or empty
-		startMarker = MarkerNavItemsStart
-		endMarker = MarkerNavItemsEnd
+// InjectMenuLink appends a link to a section of menu.toml ("main", "admin", "account",
+// "site" or "footer"); the header and footer links have no icon. A link whose url is
+// already in that section is skipped, so the same page can be linked from several sections.
+func (i *Injector) InjectMenuLink(section, label, urlPath, icon string) error {
+	if i.hasMenuLink(section, urlPath) {
+		return nil
 	}
 
-	// Check if markers exist
-	if !i.HasMarker(startMarker) || !i.HasMarker(endMarker) {
-		return fmt.Errorf("navigation markers not found: %s, %s", startMarker, endMarker)
+	// Order after the entries already in the section
+	order := (strings.Count(i.content, "[["+section+"]]") + 1) * 10
+
+	entry := fmt.Sprintf("[[%s]]\nlabel = %q\nurl = %q\n", section, label, urlPath)
+	if icon != "" {
+		entry += fmt.Sprintf("icon = %q\n", icon)
+	}
+	entry += fmt.Sprintf("order = %d\n", order)
+	content := strings.TrimRight(i.content, "\n")
+	if content != "" {
+		content += "\n\n"
 	}
+	i.content = content + entry
+	return nil
+}
 
-	return i.InjectBetweenMarkers(startMarker, endMarker, code)
+// hasMenuLink reports whether a section of menu.toml already has a link to urlPath.
+func (i *Injector) hasMenuLink(section, urlPath string) bool {
+	want := fmt.Sprintf("url = %q", urlPath)
+	current := ""
+	for _, line := range strings.Split(i.content, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.HasPrefix(line, "[[") && strings.HasSuffix(line, "]]") {
+			current = strings.TrimSuffix(strings.TrimPrefix(line, "[["), "]]")
+			continue
+		}
+		if current == section && line == want {
+			return true
+		}
+	}
+	return false
 }
@@ -464,6 +464,188 @@ func main() {}
 	}
 }
 
+// TestInjector_InjectMigration tests migration step injection.
+func TestInjector_InjectMigration(t *testing.T) {
+	content := `package database
+
+func RunMigrations(db *gorm.DB) error {
+	// MCP:MIGRATIONS:START
+	// MCP:MIGRATIONS:END
+	return nil
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	for range 2 {
+		if err := injector.InjectMigration("models.MigrateProductSearch(db)"); err != nil {
+			t.Fatalf("InjectMigration() error = %v", err)
+		}
+	}
+
+	result := injector.Content()
+	want := "\tif err := models.MigrateProductSearch(db); err != nil {\n\t\treturn err\n\t}\n"
+	if strings.Count(result, want) != 1 {
+		t.Errorf("Migration step should be injected once, got:\n%s", result)
+	}
+}
+
+func TestInjector_InjectScheduledTask(t *testing.T) {
+	content := `package main
+
+func main() {
+	// MCP:SCHEDULER:START
+	// MCP:SCHEDULER:END
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	for range 2 {
+		if err := injector.InjectScheduledTask("blog_post"); err != nil {
+			t.Fatalf("InjectScheduledTask() error = %v", err)
+		}
+	}
+
+	result := injector.Content()
+	want := `scheduler.Start("blog_posts publishing", blogPostService.UpdatePublishStates)`
+	if strings.Count(result, want) != 1 {
+		t.Errorf("Scheduled task should be injected once, got:\n%s", result)
+	}
+}
+
+func TestInjector_InjectWebhookService(t *testing.T) {
+	content := `package main
+
+func main() {
+	// MCP:SERVICES:START
+	orderService := ordersvc.NewService(orderRepo)
+	// MCP:SERVICES:END
+
+	// MCP:SCHEDULER:START
+	// MCP:SCHEDULER:END
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	for range 2 {
+		if err := injector.InjectWebhookDispatcher(); err != nil {
+			t.Fatalf("InjectWebhookDispatcher() error = %v", err)
+		}
+		if err := injector.InjectWebhookService("order"); err != nil {
+			t.Fatalf("InjectWebhookService() error = %v", err)
+		}
+	}
+
+	result := injector.Content()
+	want := "\torderService := ordersvc.NewService(orderRepo)\n" +
+		"\twebhookDispatcher := webhooks.NewDispatcher(db)\n" +
+		"\torderService = ordersvc.NewWebhookService(orderService, webhookDispatcher)\n"
+	if strings.Count(result, want) != 1 {
+		t.Errorf("Webhook wiring should be injected once, got:\n%s", result)
+	}
+	if strings.Count(result, `scheduler.Start("webhook deliveries", webhookDispatcher.Deliver)`) != 1 {
+		t.Errorf("Delivery task should be injected once, got:\n%s", result)
+	}
+
+	if err := injector.InjectWebhookService("invoice"); err == nil {
+		t.Error("expected an error for a service that does not exist")
+	}
+}
+
+func TestInjector_InjectRealtimeService(t *testing.T) {
+	content := `package main
+
+func main() {
+	// MCP:SERVICES:START
+	orderService := ordersvc.NewService(orderRepo)
+	realtimeHub := realtime.NewHub()
+	// MCP:SERVICES:END
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	for range 2 {
+		if err := injector.InjectRealtimeService("order"); err != nil {
+			t.Fatalf("InjectRealtimeService() error = %v", err)
+		}
+	}
+
+	if strings.Count(injector.Content(), "\torderService = ordersvc.NewRealtimeService(orderService, realtimeHub)\n") != 1 {
+		t.Errorf("Realtime wrapper should be injected once, got:\n%s", injector.Content())
+	}
+
+	if err := injector.InjectRealtimeService("invoice"); err == nil {
+		t.Error("expected an error for a service that does not exist")
+	}
+}
+
+func TestInjector_InjectRealtimeRoute(t *testing.T) {
+	content := `package main
+
+func main() {
+	// MCP:SERVICES:START
+	realtimeHub := realtime.NewHub()
+	// MCP:SERVICES:END
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	router.Group(func(r chi.Router) {
+		// MCP:ROUTES:AUTHENTICATED:START
+		// MCP:ROUTES:AUTHENTICATED:END
+	})
+	// MCP:ROUTES:END
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	for _, tt := range []struct {
+		domain, group string
+		owned         bool
+	}{
+		{"product", "public", false},
+		{"order", "authenticated", true},
+		{"order", "authenticated", true},
+		{"invoice", "admin", true},
+	} {
+		if err := injector.InjectRealtimeRoute(tt.domain, tt.group, tt.owned); err != nil {
+			t.Fatalf("InjectRealtimeRoute(%q) error = %v", tt.domain, err)
+		}
+	}
+
+	result := injector.Content()
+	if count := strings.Count(result, "realtimeUser := func(r *http.Request) uint {"); count != 1 {
+		t.Errorf("expected the session user function to be injected once, got %d", count)
+	}
+	want := "\t// MCP:ROUTES:PUBLIC:START\n" +
+		"\trouter.Get(\"/events/products\", realtimeHub.Stream(\"product\", nil))\n" +
+		"\t// MCP:ROUTES:PUBLIC:END\n"
+	if !strings.Contains(result, want) {
+		t.Errorf("expected the public stream in the public group, got:\n%s", result)
+	}
+	// Without an admin group, admin streams need a login
+	want = "\t\t// MCP:ROUTES:AUTHENTICATED:START\n" +
+		"\t\tr.Get(\"/events/orders\", realtimeHub.Stream(\"order\", realtimeUser))\n" +
+		"\t\tr.Get(\"/events/invoices\", realtimeHub.Stream(\"invoice\", realtimeUser))\n" +
+		"\t\t// MCP:ROUTES:AUTHENTICATED:END\n"
+	if !strings.Contains(result, want) {
+		t.Errorf("expected the owned streams in the authenticated group, got:\n%s", result)
+	}
+
+	// Protected streams are never mounted in the public routes
+	injector = NewInjectorFromContent("package main\n\n// MCP:ROUTES:START\nrouter.Use(authMiddleware.RequireAuth)\n// MCP:ROUTES:END\n")
+	if err := injector.InjectRealtimeRoute("order", "authenticated", false); err == nil {
+		t.Error("expected an error without an authenticated route group")
+	}
+	// Without auth, every route group is public
+	injector = NewInjectorFromContent("package main\n\n// MCP:ROUTES:START\n// MCP:ROUTES:END\n")
+	if err := injector.InjectRealtimeRoute("order", "authenticated", false); err != nil {
+		t.Errorf("InjectRealtimeRoute() error = %v", err)
+	}
+	if !strings.Contains(injector.Content(), `router.Get("/events/orders", realtimeHub.Stream("order", nil))`) {
+		t.Errorf("expected the stream in the general routes, got:\n%s", injector.Content())
+	}
+}
+
 // TestInjector_InjectRepo tests repository injection.
 func TestInjector_InjectRepo(t *testing.T) {
 	content := `package main
@@ -486,6 +668,28 @@ func main() {}
 	}
 }
 
+// TestInjector_InjectCachedRepo tests cached repository injection.
+func TestInjector_InjectCachedRepo(t *testing.T) {
+	content := `package main
+
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+
+func main() {}
+`
+	injector := NewInjectorFromContent(content)
+
+	err := injector.InjectCachedRepo("product")
+	if err != nil {
+		t.Fatalf("InjectCachedRepo() error = %v", err)
+	}
+
+	result := injector.Content()
+	if !strings.Contains(result, "productRepo := productrepo.NewCachedRepository(db, productrepo.NewRepository(db), cache.Default())") {
+		t.Errorf("Cached repo should be injected, got:\n%s", result)
+	}
+}
+
 // TestInjector_InjectService tests service injection.
 func TestInjector_InjectService(t *testing.T) {
 	content := `package main
@@ -553,6 +757,378 @@ func main() {}
 	}
 }
 
+// TestInjector_InjectModerationRoute tests mounting a moderation queue in the admin group.
+func TestInjector_InjectModerationRoute(t *testing.T) {
+	t.Run("admin group", func(t *testing.T) {
+		content := `package main
+
+	// MCP:ROUTES:START
+	router.Group(func(r chi.Router) {
+		// MCP:ROUTES:ADMIN:START
+		// MCP:ROUTES:ADMIN:END
+	})
+	// MCP:ROUTES:END
+`
+		injector := NewInjectorFromContent(content)
+
+		admin, err := injector.InjectModerationRoute("blog_post")
+		if err != nil {
+			t.Fatalf("InjectModerationRoute() error = %v", err)
+		}
+		if !admin {
+			t.Error("expected the queue to be mounted in the admin group")
+		}
+
+		expectedRoute := `r.Route("/admin/blog-posts/moderation", blogPostController.RegisterModerationRoutes)`
+		if !strings.Contains(injector.Content(), expectedRoute) {
+			t.Errorf("Route should be injected.\nExpected to contain: %s\nActual content:\n%s", expectedRoute, injector.Content())
+		}
+	})
+
+	t.Run("falls back to general routes", func(t *testing.T) {
+		content := `package main
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:END
+`
+		injector := NewInjectorFromContent(content)
+
+		admin, err := injector.InjectModerationRoute("product")
+		if err != nil {
+			t.Fatalf("InjectModerationRoute() error = %v", err)
+		}
+		if admin {
+			t.Error("expected the queue to fall back to the general routes")
+		}
+
+		expectedRoute := `router.Route("/admin/products/moderation", productController.RegisterModerationRoutes)`
+		if !strings.Contains(injector.Content(), expectedRoute) {
+			t.Errorf("Route should be injected.\nExpected to contain: %s\nActual content:\n%s", expectedRoute, injector.Content())
+		}
+	})
+}
+
+// TestInjector_InjectAPIVersionRoute tests mounting domains in versioned API sub-routers.
+func TestInjector_InjectAPIVersionRoute(t *testing.T) {
+	content := `package main
+
+func main() {
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	// MCP:ROUTES:END
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	for _, mount := range []struct{ domain, version string }{
+		{"product", "v1"},
+		{"order", "v1"},
+		{"product", "v2"},
+	} {
+		if err := injector.InjectAPIVersionRoute(mount.domain, mount.version); err != nil {
+			t.Fatalf("InjectAPIVersionRoute(%q, %q) error = %v", mount.domain, mount.version, err)
+		}
+	}
+
+	result := injector.Content()
+	if n := strings.Count(result, `router.Route("/api/v1", func(r chi.Router) {`); n != 1 {
+		t.Errorf("expected one v1 sub-router, got %d:\n%s", n, result)
+	}
+	for _, want := range []string{
+		`r.Use(middleware.APIVersion("v1"))`,
+		`r.Use(middleware.APIVersion("v2"))`,
+		"// MCP:ROUTES:API_V1:START\n\t\t" + `r.Route("/products", productController.RegisterRoutes)` +
+			"\n\t\t" + `r.Route("/orders", orderController.RegisterRoutes)` + "\n\t\t// MCP:ROUTES:API_V1:END",
+		"// MCP:ROUTES:API_V2:START\n\t\t" + `r.Route("/products", productController.RegisterRoutes)`,
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected content to contain %q, got:\n%s", want, result)
+		}
+	}
+	if strings.Index(result, "API_V2:END") > strings.Index(result, "MCP:ROUTES:PUBLIC:END") {
+		t.Error("sub-routers should be created in the public route group")
+	}
+}
+
+// TestInjector_InjectDomainRoutes tests adding domain controllers to the routes registry.
+func TestInjector_InjectDomainRoutes(t *testing.T) {
+	content := `package main
+
+func main() {
+	domainRoutes := routes.New()
+	// MCP:DOMAIN_ROUTES:START
+	// MCP:DOMAIN_ROUTES:END
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	domainRoutes.Mount(router, routes.Public)
+	// MCP:ROUTES:END
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	for _, mount := range []struct{ domain, routeGroup string }{
+		{"product", "public"},
+		{"order", "api_v1"},
+		{"order", "api_v1"},
+	} {
+		if err := injector.InjectDomainRoutes(mount.domain, mount.routeGroup); err != nil {
+			t.Fatalf("InjectDomainRoutes(%q, %q) error = %v", mount.domain, mount.routeGroup, err)
+		}
+	}
+
+	result := injector.Content()
+	for _, want := range []string{
+		"// MCP:DOMAIN_ROUTES:START\n\tdomainRoutes.Add(productController)\n\tdomainRoutes.Add(orderController)\n\t// MCP:DOMAIN_ROUTES:END",
+		"r.Use(middleware.APIVersion(\"v1\"))\n\t\tdomainRoutes.Mount(r, routes.Group(\"api_v1\"))\n\t\t// MCP:ROUTES:API_V1:START\n\t\t// MCP:ROUTES:API_V1:END",
+	} {
+		if !strings.Contains(result, want) {
+			t.Errorf("expected content to contain %q, got:\n%s", want, result)
+		}
+	}
+	if strings.Contains(result, "RegisterRoutes") {
+		t.Errorf("expected no routes injected into the route groups, got:\n%s", result)
+	}
+}
+
+// TestInjector_InjectSearchIndex tests wiring search indexing for two domains.
+func TestInjector_InjectSearchIndex(t *testing.T) {
+	content := `package main
+
+func main() {
+	// MCP:SERVICES:START
+	productService := productsvc.NewService(productRepo)
+	orderService := ordersvc.NewService(orderRepo)
+	// MCP:SERVICES:END
+
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+
+	// MCP:ROUTES:START
+	router.Group(func(r chi.Router) {
+		// MCP:ROUTES:AUTHENTICATED:START
+		// MCP:ROUTES:AUTHENTICATED:END
+	})
+	// MCP:ROUTES:END
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	for _, domain := range []string{"product", "order"} {
+		if err := injector.InjectSearchIndex(domain); err != nil {
+			t.Fatalf("InjectSearchIndex(%q) error = %v", domain, err)
+		}
+	}
+	if err := injector.InjectSearchRoute("order", "authenticated"); err != nil {
+		t.Fatalf("InjectSearchRoute() error = %v", err)
+	}
+
+	result := injector.Content()
+	if count := strings.Count(result, "search.NewFromEnv()"); count != 1 {
+		t.Errorf("expected the indexer to be created once, got %d", count)
+	}
+	for _, expected := range []string{
+		`productService = productsvc.WithSearchIndex(productService, searchIndexer)`,
+		`orderService = ordersvc.WithSearchIndex(orderService, searchIndexer)`,
+		`orderSearchController := orderctrl.NewSearchController(searchIndexer)`,
+		`r.Get("/orders/search", orderSearchController.Search)`,
+	} {
+		if !strings.Contains(result, expected) {
+			t.Errorf("Expected to contain: %s\nActual content:\n%s", expected, result)
+		}
+	}
+}
+
+// TestInjector_InjectReindexer tests registering a domain in the reindex command.
+func TestInjector_InjectReindexer(t *testing.T) {
+	content := `package main
+
+var reindexers = map[string]func(ctx context.Context, db *gorm.DB, indexer search.Indexer) (int, error){
+	// MCP:REINDEX:START
+	// MCP:REINDEX:END
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	if err := injector.InjectReindexer("blog_post"); err != nil {
+		t.Fatalf("InjectReindexer() error = %v", err)
+	}
+
+	expected := `"blog_post": search.ReindexBlogPosts,`
+	if !strings.Contains(injector.Content(), expected) {
+		t.Errorf("Expected to contain: %s\nActual content:\n%s", expected, injector.Content())
+	}
+}
+
+// TestInjector_InjectSeeder tests registering a seeder in the seed runner, once.
+func TestInjector_InjectSeeder(t *testing.T) {
+	content := `package seeders
+
+func All(db *gorm.DB) map[string]Seeder {
+	return map[string]Seeder{
+		// MCP:SEEDERS:START
+		// MCP:SEEDERS:END
+	}
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	for i := 0; i < 2; i++ {
+		if err := injector.InjectSeeder("order_line"); err != nil {
+			t.Fatalf("InjectSeeder() error = %v", err)
+		}
+	}
+
+	expected := `"order_line": NewOrderLineSeeder(db),`
+	if count := strings.Count(injector.Content(), expected); count != 1 {
+		t.Errorf("Expected %s once, got %d times\nActual content:\n%s", expected, count, injector.Content())
+	}
+}
+
+// TestInjector_InjectUploadsRoute tests that the uploads route is mounted once.
+func TestInjector_InjectUploadsRoute(t *testing.T) {
+	content := `package main
+
+func main() {
+	// MCP:ROUTES:START
+	// MCP:ROUTES:END
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	for i := 0; i < 2; i++ {
+		if err := injector.InjectUploadsRoute(); err != nil {
+			t.Fatalf("InjectUploadsRoute() error = %v", err)
+		}
+	}
+
+	expected := `router.Handle("/uploads/*", storage.Handler())`
+	if count := strings.Count(injector.Content(), expected); count != 1 {
+		t.Errorf("expected the route once, got %d\nActual content:\n%s", count, injector.Content())
+	}
+}
+
+// TestInjector_InjectHealthHandler tests wiring the health handler and its readiness checks.
+func TestInjector_InjectHealthHandler(t *testing.T) {
+	content := `package main
+
+func main() {
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:END
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	for i := 0; i < 2; i++ {
+		if err := injector.InjectHealthHandler(); err != nil {
+			t.Fatalf("InjectHealthHandler() error = %v", err)
+		}
+		if err := injector.InjectHealthCheck("cache", "cache.Ping"); err != nil {
+			t.Fatalf("InjectHealthCheck() error = %v", err)
+		}
+	}
+
+	expected := `	// MCP:SERVICES:START
+	healthHandler := health.NewHandler(db)
+	healthHandler.MarkMigrated()
+	// MCP:SERVICES:END
+	// MCP:HEALTH_CHECKS:START
+	healthHandler.AddCheck("cache", cache.Ping)
+	// MCP:HEALTH_CHECKS:END
+
+	// MCP:ROUTES:START
+	healthHandler.RegisterRoutes(router)
+	// MCP:ROUTES:END
+`
+	if !strings.Contains(injector.Content(), expected) {
+		t.Errorf("expected the handler, check and routes once\nActual content:\n%s", injector.Content())
+	}
+}
+
+// TestInjector_InjectImageColumn tests registering an image column in the reprocess command.
+func TestInjector_InjectImageColumn(t *testing.T) {
+	content := `package main
+
+var imageColumns = []struct{ Table, Column string }{
+	// MCP:IMAGES:START
+	// MCP:IMAGES:END
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	if err := injector.InjectImageColumn("products", "photo"); err != nil {
+		t.Fatalf("InjectImageColumn() error = %v", err)
+	}
+
+	expected := `{Table: "products", Column: "photo"},`
+	if !strings.Contains(injector.Content(), expected) {
+		t.Errorf("Expected to contain: %s\nActual content:\n%s", expected, injector.Content())
+	}
+}
+
+// TestInjector_InjectSortColumn tests whitelisting a sort column in a controller.
+func TestInjector_InjectSortColumn(t *testing.T) {
+	content := `package products
+
+var sortColumns = map[string]string{
+	// MCP:SORT_COLUMNS:START
+	"id": "id",
+	// MCP:SORT_COLUMNS:END
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	for i := 0; i < 2; i++ {
+		if err := injector.InjectSortColumn("unitPrice", "unit_price"); err != nil {
+			t.Fatalf("InjectSortColumn() error = %v", err)
+		}
+	}
+
+	expected := `"unitPrice": "unit_price",`
+	if got := strings.Count(injector.Content(), expected); got != 1 {
+		t.Errorf("Expected %s exactly once, got %d\nActual content:\n%s", expected, got, injector.Content())
+	}
+}
+
+// TestInjector_MarkerContent tests reading code between markers and writing it back.
+func TestInjector_MarkerContent(t *testing.T) {
+	content := `package product
+
+type Repository interface {
+	// MCP:REPO_INTERFACE:START
+	FindBySKU(ctx context.Context, sku string) (*models.Product, error)
+	// MCP:REPO_INTERFACE:END
+}
+`
+	injector := NewInjectorFromContent(content)
+
+	code, err := injector.MarkerContent("MCP:REPO_INTERFACE:START", "MCP:REPO_INTERFACE:END")
+	if err != nil {
+		t.Fatalf("MarkerContent() error = %v", err)
+	}
+	expected := "FindBySKU(ctx context.Context, sku string) (*models.Product, error)"
+	if code != expected {
+		t.Errorf("MarkerContent() = %q, want %q", code, expected)
+	}
+
+	// Writing the code back leaves the content unchanged
+	if err := injector.ReplaceMarkerContent("MCP:REPO_INTERFACE:START", "MCP:REPO_INTERFACE:END", code); err != nil {
+		t.Fatalf("ReplaceMarkerContent() error = %v", err)
+	}
+	if injector.Content() != content {
+		t.Errorf("Expected round trip to preserve content, got:\n%s", injector.Content())
+	}
+
+	if _, err := injector.MarkerContent("MCP:MISSING:START", "MCP:MISSING:END"); err == nil {
+		t.Error("Expected error for missing markers")
+	}
+}
+
 // TestInjector_Save tests saving to file.
 func TestInjector_Save(t *testing.T) {
 	// Create a temp file
@@ -789,145 +1365,124 @@ func main() {
 	}
 }
 
-// TestInjector_InjectNavItem_Authenticated tests nav item injection for authenticated routes.
-func TestInjector_InjectNavItem_Authenticated(t *testing.T) {
-	content := `package views
-
-templ SidebarNav() {
-	<nav>
-		@navItem("/dashboard", "home", "Dashboard", true)
-		// MCP:NAV_ITEMS:START
-		// MCP:NAV_ITEMS:END
-	</nav>
-}
+const testMenu = `[[main]]
+label = "Dashboard"
+url = "/dashboard"
+icon = "home"
+order = 1
+
+[[account]]
+label = "Settings"
+url = "/settings"
+icon = "cog"
+order = 1
 `
-	injector := NewInjectorFromContent(content)
 
-	err := injector.InjectNavItem("product", "authenticated", "box")
+// TestInjector_InjectMenuItem_Authenticated tests menu entries for authenticated routes.
+func TestInjector_InjectMenuItem_Authenticated(t *testing.T) {
+	injector := NewInjectorFromContent(testMenu)
+
+	err := injector.InjectMenuItem("product", "authenticated", "box")
 	if err != nil {
-		t.Fatalf("InjectNavItem() error = %v", err)
+		t.Fatalf("InjectMenuItem() error = %v", err)
 	}
 
 	result := injector.Content()
-	expectedNavItem := `@navItem("/products", "box", "Products", false)`
-	if !strings.Contains(result, expectedNavItem) {
-		t.Errorf("Nav item should be injected.\nExpected to contain: %s\nActual content:\n%s", expectedNavItem, result)
+	expected := "[[main]]\nlabel = \"Products\"\nurl = \"/products\"\nicon = \"box\"\norder = 20\n"
+	if !strings.HasSuffix(result, "\n\n"+expected) {
+		t.Errorf("Menu entry should be appended.\nExpected to end with: %s\nActual content:\n%s", expected, result)
+	}
+	if !strings.HasPrefix(result, testMenu) {
+		t.Error("Existing entries should be preserved")
 	}
 }
 
-// TestInjector_InjectNavItem_Admin tests nav item injection for admin routes.
-func TestInjector_InjectNavItem_Admin(t *testing.T) {
-	content := `package views
-
-templ SidebarNav() {
-	<nav>
-		// MCP:NAV_ITEMS_ADMIN:START
-		// MCP:NAV_ITEMS_ADMIN:END
-	</nav>
-}
-`
-	injector := NewInjectorFromContent(content)
+// TestInjector_InjectMenuItem_Admin tests menu entries for admin routes.
+func TestInjector_InjectMenuItem_Admin(t *testing.T) {
+	injector := NewInjectorFromContent(testMenu)
 
-	err := injector.InjectNavItem("category", "admin", "folder")
+	err := injector.InjectMenuItem("category", "admin", "folder")
 	if err != nil {
-		t.Fatalf("InjectNavItem() error = %v", err)
+		t.Fatalf("InjectMenuItem() error = %v", err)
 	}
 
 	result := injector.Content()
-	expectedNavItem := `@navItem("/categories", "folder", "Categories", false)`
-	if !strings.Contains(result, expectedNavItem) {
-		t.Errorf("Admin nav item should be injected.\nExpected to contain: %s\nActual content:\n%s", expectedNavItem, result)
+	expected := "[[admin]]\nlabel = \"Categories\"\nurl = \"/categories\"\nicon = \"folder\"\norder = 10\n"
+	if !strings.Contains(result, expected) {
+		t.Errorf("Admin menu entry should be appended.\nExpected to contain: %s\nActual content:\n%s", expected, result)
 	}
 }
 
-// TestInjector_InjectNavItem_DefaultIcon tests that default icon is used when not specified.
-func TestInjector_InjectNavItem_DefaultIcon(t *testing.T) {
-	content := `package views
-
-templ SidebarNav() {
-	<nav>
-		// MCP:NAV_ITEMS:START
-		// MCP:NAV_ITEMS:END
-	</nav>
-}
-`
-	injector := NewInjectorFromContent(content)
+// TestInjector_InjectMenuLink tests menu entries whose label and URL are not derived from a domain.
+func TestInjector_InjectMenuLink(t *testing.T) {
+	injector := NewInjectorFromContent(testMenu)
 
-	err := injector.InjectNavItem("order", "authenticated", "")
-	if err != nil {
-		t.Fatalf("InjectNavItem() error = %v", err)
+	for i := 0; i < 2; i++ {
+		if err := injector.InjectMenuLink("admin", "Settings", "/admin/settings", "cog"); err != nil {
+			t.Fatalf("InjectMenuLink() error = %v", err)
+		}
 	}
 
 	result := injector.Content()
-	// Default icon should be "folder"
-	expectedNavItem := `@navItem("/orders", "folder", "Orders", false)`
-	if !strings.Contains(result, expectedNavItem) {
-		t.Errorf("Nav item with default icon should be injected.\nExpected to contain: %s\nActual content:\n%s", expectedNavItem, result)
+	expected := "[[admin]]\nlabel = \"Settings\"\nurl = \"/admin/settings\"\nicon = \"cog\"\norder = 10\n"
+	if strings.Count(result, expected) != 1 {
+		t.Errorf("Menu link should be appended once.\nExpected to contain: %s\nActual content:\n%s", expected, result)
 	}
 }
 
-// TestInjector_InjectNavItem_MissingMarkers tests error when markers are missing.
-func TestInjector_InjectNavItem_MissingMarkers(t *testing.T) {
-	content := `package views
+// TestInjector_InjectMenuLink_OtherSection tests that a URL already linked from one section
+// can still be added to another.
+func TestInjector_InjectMenuLink_OtherSection(t *testing.T) {
+	injector := NewInjectorFromContent(testMenu)
 
-templ SidebarNav() {
-	<nav>
-		@navItem("/dashboard", "home", "Dashboard", true)
-	</nav>
-}
-`
-	injector := NewInjectorFromContent(content)
+	if err := injector.InjectMenuLink("footer", "Settings", "/settings", "cog"); err != nil {
+		t.Fatalf("InjectMenuLink() error = %v", err)
+	}
+	if err := injector.InjectMenuLink("account", "Settings", "/settings", "cog"); err != nil {
+		t.Fatalf("InjectMenuLink() error = %v", err)
+	}
 
-	err := injector.InjectNavItem("product", "authenticated", "box")
-	if err == nil {
-		t.Error("InjectNavItem() should error when markers are missing")
+	result := injector.Content()
+	if !strings.Contains(result, "[[footer]]\nlabel = \"Settings\"\nurl = \"/settings\"") {
+		t.Errorf("Footer link should be appended.\nActual content:\n%s", result)
 	}
-	if !strings.Contains(err.Error(), "navigation markers not found") {
-		t.Errorf("Error should mention missing markers: %v", err)
+	if count := strings.Count(result, `url = "/settings"`); count != 2 {
+		t.Errorf("Settings should be linked from account and footer once each, found %d links", count)
 	}
 }
 
-// TestInjector_InjectNavItem_Duplicate tests that duplicate nav items are skipped.
-func TestInjector_InjectNavItem_Duplicate(t *testing.T) {
-	content := `package views
+// TestInjector_InjectMenuItem_DefaultIcon tests that default icon is used when not specified.
+func TestInjector_InjectMenuItem_DefaultIcon(t *testing.T) {
+	injector := NewInjectorFromContent("")
 
-templ SidebarNav() {
-	<nav>
-		// MCP:NAV_ITEMS:START
-		@navItem("/products", "box", "Products", false)
-		// MCP:NAV_ITEMS:END
-	</nav>
-}
-`
-	injector := NewInjectorFromContent(content)
-
-	err := injector.InjectNavItem("product", "authenticated", "box")
+	err := injector.InjectMenuItem("order", "authenticated", "")
 	if err != nil {
-		t.Fatalf("InjectNavItem() error = %v", err)
+		t.Fatalf("InjectMenuItem() error = %v", err)
 	}
 
 	result := injector.Content()
-	count := strings.Count(result, `@navItem("/products"`)
-	if count != 1 {
-		t.Errorf("Nav item should appear once, found %d times", count)
+	// Default icon should be "folder"
+	expected := "[[main]]\nlabel = \"Orders\"\nurl = \"/orders\"\nicon = \"folder\"\norder = 10\n"
+	if result != expected {
+		t.Errorf("Menu entry with default icon should be written.\nExpected: %s\nActual content:\n%s", expected, result)
 	}
 }
 
-// TestNavItemMarkerConstants tests that nav item marker constants are properly defined.
-func TestNavItemMarkerConstants(t *testing.T) {
-	markers := []string{
-		MarkerNavItemsStart, MarkerNavItemsEnd,
-		MarkerNavItemsAdminStart, MarkerNavItemsAdminEnd,
-	}
+// TestInjector_InjectMenuItem_Duplicate tests that entries already in the menu are skipped.
+func TestInjector_InjectMenuItem_Duplicate(t *testing.T) {
+	injector := NewInjectorFromContent(testMenu)
 
-	for _, marker := range markers {
-		if !strings.HasPrefix(marker, "MCP:NAV_ITEMS") {
-			t.Errorf("Nav marker %q should start with 'MCP:NAV_ITEMS'", marker)
-		}
-		if !strings.HasSuffix(marker, ":START") && !strings.HasSuffix(marker, ":END") {
-			t.Errorf("Nav marker %q should end with :START or :END", marker)
+	for i := 0; i < 2; i++ {
+		if err := injector.InjectMenuItem("product", "authenticated", "box"); err != nil {
+			t.Fatalf("InjectMenuItem() error = %v", err)
 		}
 	}
+
+	result := injector.Content()
+	count := strings.Count(result, `url = "/products"`)
+	if count != 1 {
+		t.Errorf("Menu entry should appear once, found %d times", count)
+	}
 }
 
 // TestInjector_InjectControllerWithRelations tests controller injection with related services.
@@ -0,0 +1,355 @@
+package modifier
+
+import (
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Marker problem kinds reported by LintMarkers.
+const (
+	// MarkerMalformed is a marker comment the injector does not recognize.
+	MarkerMalformed = "malformed"
+	// MarkerDuplicate is a second marker with the same name; injections only use the first.
+	MarkerDuplicate = "duplicate"
+	// MarkerOrphanStart is a START marker without its END marker.
+	MarkerOrphanStart = "orphan_start"
+	// MarkerOrphanEnd is an END marker without its START marker.
+	MarkerOrphanEnd = "orphan_end"
+	// MarkerOverlapping is a marker section that ends inside another one.
+	MarkerOverlapping = "overlapping"
+	// MarkerInString is a marker inside a string literal, which the injector can mistake for a real one.
+	MarkerInString = "in_string"
+)
+
+// MarkerProblem is a marker comment that breaks or misdirects code injection.
+type MarkerProblem struct {
+	// Line is the 1-based line of the marker.
+	Line int
+	// Marker is the marker as it should read (e.g., "MCP:ROUTES:START"), or as found when unrecognized.
+	Marker string
+	// Kind is one of the Marker* problem kinds.
+	Kind string
+	// Message describes the problem.
+	Message string
+	// Fixable reports whether FixMarkers repairs the problem.
+	Fixable bool
+
+	fix func(edits lineEdits)
+}
+
+// canonicalMarkerRegex matches a marker line the injector recognizes.
+var canonicalMarkerRegex = regexp.MustCompile(`^[ \t]*//\s*MCP:([A-Z0-9_]+(?::[A-Z0-9_]+)*):(START|END)\s*$`)
+
+// markerCommentRegex matches comments meant as markers: the text starts with "MCP:" in any case.
+var markerCommentRegex = regexp.MustCompile(`(?i)^(?://|/\*)\s*mcp\s*:`)
+
+// markerWordRegex reads the marker name and kind after "MCP:".
+var markerWordRegex = regexp.MustCompile(`(?i)^mcp\s*:\s*([a-z0-9_]+(?:\s*:\s*[a-z0-9_]+)*)`)
+
+// stringMarkerRegex finds markers inside string literals.
+var stringMarkerRegex = regexp.MustCompile(`//\s*MCP:[A-Z0-9_:]+:(?:START|END)`)
+
+// markerKinds maps the accepted spellings of a marker kind to START or END.
+var markerKinds = map[string]string{
+	"START": "START",
+	"BEGIN": "START",
+	"END":   "END",
+	"STOP":  "END",
+}
+
+// markerComment is a marker found in the source.
+type markerComment struct {
+	line   int
+	indent string
+	name   string // e.g. "ROUTES:PUBLIC"
+	kind   string // START or END
+}
+
+func (m markerComment) marker() string {
+	return "MCP:" + m.name + ":" + m.kind
+}
+
+// lineEdits collects the changes FixMarkers makes, by 0-based line index.
+type lineEdits map[int]*lineEdit
+
+type lineEdit struct {
+	replace []string // replaces the line when non-nil
+	remove  bool
+	before  []string
+	after   []string
+}
+
+func (e lineEdits) at(i int) *lineEdit {
+	if e[i] == nil {
+		e[i] = &lineEdit{}
+	}
+	return e[i]
+}
+
+// LintMarkers checks the MCP marker comments of Go source: markers the injector cannot see,
+// duplicated, orphaned and overlapping sections, and markers inside string literals.
+// Problems are sorted by line.
+func LintMarkers(src string) []MarkerProblem {
+	lines := strings.Split(src, "\n")
+	var problems []MarkerProblem
+	var markers []markerComment
+
+	fset := token.NewFileSet()
+	file := fset.AddFile("", fset.Base(), len(src))
+	var s scanner.Scanner
+	s.Init(file, []byte(src), func(token.Position, string) {}, scanner.ScanComments)
+	for {
+		pos, tok, lit := s.Scan()
+		if tok == token.EOF {
+			break
+		}
+		position := fset.Position(pos)
+		switch tok {
+		case token.COMMENT:
+			if !markerCommentRegex.MatchString(lit) {
+				continue
+			}
+			marker, problem := checkMarkerComment(lines, position.Line-1, position.Column-1, lit)
+			if problem != nil {
+				problems = append(problems, *problem)
+			}
+			if marker != nil {
+				markers = append(markers, *marker)
+			}
+		case token.STRING:
+			for _, loc := range stringMarkerRegex.FindAllStringIndex(lit, -1) {
+				found := lit[loc[0]:loc[1]]
+				problems = append(problems, MarkerProblem{
+					Line:    position.Line + strings.Count(lit[:loc[0]], "\n"),
+					Marker:  strings.TrimSpace(strings.TrimPrefix(found, "//")),
+					Kind:    MarkerInString,
+					Message: "marker inside a string literal; injections can match it instead of the real marker",
+				})
+			}
+		}
+	}
+
+	problems = append(problems, pairMarkers(lines, markers)...)
+	sort.SliceStable(problems, func(i, j int) bool { return problems[i].Line < problems[j].Line })
+	return problems
+}
+
+// checkMarkerComment checks a comment that starts with "MCP:" on line i (0-based), starting
+// at byte column col. It returns the marker it stands for, or nil when it cannot be recognized,
+// and the problem with it, if any.
+func checkMarkerComment(lines []string, i, col int, comment string) (*markerComment, *MarkerProblem) {
+	line := lines[i]
+	if canonicalMarkerRegex.MatchString(line) {
+		sub := canonicalMarkerRegex.FindStringSubmatch(line)
+		return &markerComment{line: i + 1, indent: leadingSpace(line), name: sub[1], kind: sub[2]}, nil
+	}
+
+	text := strings.TrimPrefix(comment, "//")
+	block := strings.HasPrefix(comment, "/*")
+	if block {
+		text = strings.TrimSuffix(strings.TrimPrefix(comment, "/*"), "*/")
+	}
+	text = strings.TrimSpace(text)
+	problem := &MarkerProblem{Line: i + 1, Marker: text, Kind: MarkerMalformed}
+
+	var name, kind string
+	if sub := markerWordRegex.FindStringSubmatch(text); sub != nil {
+		parts := strings.Split(strings.ToUpper(sub[1]), ":")
+		for j := range parts {
+			parts[j] = strings.TrimSpace(parts[j])
+		}
+		if k, ok := markerKinds[parts[len(parts)-1]]; ok && len(parts) > 1 {
+			name, kind = strings.Join(parts[:len(parts)-1], ":"), k
+		}
+	}
+	if name == "" {
+		problem.Message = "marker without a :START or :END suffix"
+		return nil, problem
+	}
+
+	marker := &markerComment{line: i + 1, indent: leadingSpace(line), name: name, kind: kind}
+	problem.Marker = marker.marker()
+	canonical := marker.indent + "// " + marker.marker()
+	before := strings.TrimSpace(line[:col])
+	after := strings.TrimSpace(line[col+len(comment):])
+
+	switch {
+	case before != "" && !block:
+		problem.Message = "marker shares its line with code; the injector only sees markers on their own line"
+		problem.Fixable = true
+		problem.fix = func(edits lineEdits) {
+			edits.at(i).replace = []string{strings.TrimRight(line[:col], " \t"), canonical}
+		}
+	case before != "" || after != "":
+		problem.Message = "block comment marker shares its line with code"
+		return nil, problem
+	case block:
+		problem.Message = "block comment marker; the injector only sees // comments"
+		problem.Fixable = true
+		problem.fix = func(edits lineEdits) { edits.at(i).replace = []string{canonical} }
+	default:
+		problem.Message = fmt.Sprintf("marker should read %q", marker.marker())
+		problem.Fixable = true
+		problem.fix = func(edits lineEdits) { edits.at(i).replace = []string{canonical} }
+	}
+	return marker, problem
+}
+
+// pairMarkers matches START and END markers and reports the ones that do not pair up.
+func pairMarkers(lines []string, markers []markerComment) []MarkerProblem {
+	var problems []MarkerProblem
+	var open []markerComment
+	paired := map[string]int{} // name -> START line of its first section
+
+	openIndex := func(name string) int {
+		for j := len(open) - 1; j >= 0; j-- {
+			if open[j].name == name {
+				return j
+			}
+		}
+		return -1
+	}
+
+	for _, m := range markers {
+		m := m
+		if m.kind == "START" {
+			if j := openIndex(m.name); j >= 0 {
+				problems = append(problems, removableMarker(m, MarkerDuplicate, fmt.Sprintf("%s is already open at line %d", m.marker(), open[j].line)))
+				continue
+			}
+			open = append(open, m)
+			continue
+		}
+
+		j := openIndex(m.name)
+		if j < 0 {
+			if first, ok := paired[m.name]; ok {
+				problems = append(problems, removableMarker(m, MarkerDuplicate, fmt.Sprintf("extra end marker; the section starting at line %d is already closed", first)))
+				continue
+			}
+			start := m
+			start.kind = "START"
+			problems = append(problems, MarkerProblem{
+				Line:    m.line,
+				Marker:  m.marker(),
+				Kind:    MarkerOrphanEnd,
+				Message: fmt.Sprintf("end marker without %s", start.marker()),
+				Fixable: true,
+				fix: func(edits lineEdits) {
+					e := edits.at(m.line - 1)
+					e.before = append(e.before, start.indent+"// "+start.marker())
+				},
+			})
+			continue
+		}
+
+		start := open[j]
+		if j != len(open)-1 {
+			inner := open[len(open)-1]
+			problems = append(problems, MarkerProblem{
+				Line:    m.line,
+				Marker:  m.marker(),
+				Kind:    MarkerOverlapping,
+				Message: fmt.Sprintf("section opened at line %d ends inside MCP:%s:START (line %d)", start.line, inner.name, inner.line),
+			})
+		}
+		open = append(open[:j], open[j+1:]...)
+
+		if first, ok := paired[m.name]; ok {
+			end := m
+			problem := MarkerProblem{
+				Line:    start.line,
+				Marker:  start.marker(),
+				Kind:    MarkerDuplicate,
+				Message: fmt.Sprintf("second %s section; injections only use the one at line %d", m.name, first),
+				Fixable: true,
+				fix: func(edits lineEdits) {
+					edits.at(start.line - 1).remove = true
+					edits.at(end.line - 1).remove = true
+				},
+			}
+			problems = append(problems, problem)
+			continue
+		}
+		paired[m.name] = start.line
+	}
+
+	for _, m := range open {
+		m := m
+		if first, ok := paired[m.name]; ok {
+			problems = append(problems, removableMarker(m, MarkerDuplicate, fmt.Sprintf("extra start marker; the section at line %d is already closed", first)))
+			continue
+		}
+		end := m
+		end.kind = "END"
+		problems = append(problems, MarkerProblem{
+			Line:    m.line,
+			Marker:  m.marker(),
+			Kind:    MarkerOrphanStart,
+			Message: fmt.Sprintf("start marker without %s", end.marker()),
+			Fixable: true,
+			fix: func(edits lineEdits) {
+				e := edits.at(m.line - 1)
+				e.after = append(e.after, end.indent+"// "+end.marker())
+			},
+		})
+	}
+	return problems
+}
+
+// removableMarker reports a marker that is fixed by deleting its line.
+func removableMarker(m markerComment, kind, message string) MarkerProblem {
+	return MarkerProblem{
+		Line:    m.line,
+		Marker:  m.marker(),
+		Kind:    kind,
+		Message: message,
+		Fixable: true,
+		fix:     func(edits lineEdits) { edits.at(m.line - 1).remove = true },
+	}
+}
+
+// FixMarkers repairs the fixable marker problems of Go source. It returns the new source and
+// the problems it fixed; LintMarkers on the result reports what is left.
+func FixMarkers(src string) (string, []MarkerProblem) {
+	var fixed []MarkerProblem
+	edits := lineEdits{}
+	for _, p := range LintMarkers(src) {
+		if p.Fixable && p.fix != nil {
+			p.fix(edits)
+			fixed = append(fixed, p)
+		}
+	}
+	if len(fixed) == 0 {
+		return src, nil
+	}
+
+	lines := strings.Split(src, "\n")
+	out := make([]string, 0, len(lines))
+	for i, line := range lines {
+		e := edits[i]
+		if e == nil {
+			out = append(out, line)
+			continue
+		}
+		out = append(out, e.before...)
+		switch {
+		case e.remove:
+		case e.replace != nil:
+			out = append(out, e.replace...)
+		default:
+			out = append(out, line)
+		}
+		out = append(out, e.after...)
+	}
+	return strings.Join(out, "\n"), fixed
+}
+
+// leadingSpace returns the indentation of line.
+func leadingSpace(line string) string {
+	return line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+}
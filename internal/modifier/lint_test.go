@@ -0,0 +1,145 @@
+package modifier
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+// TestLintMarkers tests marker problem detection.
+func TestLintMarkers(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    []string // "line kind"
+		fixable bool
+	}{
+		{
+			name: "well formed nested sections",
+			src: `package main
+
+func main() {
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	// MCP:ROUTES:END
+}
+`,
+		},
+		{
+			name:    "wrong case and suffix",
+			src:     "package main\n\n// mcp:routes:begin\n// MCP:ROUTES:END\n",
+			want:    []string{"3 malformed"},
+			fixable: true,
+		},
+		{
+			name:    "code on the marker line",
+			src:     "package main\n\nvar x = 1 // MCP:ROUTES:START\n// MCP:ROUTES:END\n",
+			want:    []string{"3 malformed"},
+			fixable: true,
+		},
+		{
+			name: "missing suffix",
+			src:  "package main\n\n// MCP:ROUTES\n",
+			want: []string{"3 malformed"},
+		},
+		{
+			name:    "orphan start",
+			src:     "package main\n\n// MCP:MODELS:START\n",
+			want:    []string{"3 orphan_start"},
+			fixable: true,
+		},
+		{
+			name:    "orphan end",
+			src:     "package main\n\n// MCP:MODELS:END\n",
+			want:    []string{"3 orphan_end"},
+			fixable: true,
+		},
+		{
+			name:    "duplicate section",
+			src:     "package main\n\n// MCP:MODELS:START\n// MCP:MODELS:END\n// MCP:MODELS:START\n// MCP:MODELS:END\n",
+			want:    []string{"5 duplicate"},
+			fixable: true,
+		},
+		{
+			name: "overlapping sections",
+			src:  "package main\n\n// MCP:A:START\n// MCP:B:START\n// MCP:A:END\n// MCP:B:END\n",
+			want: []string{"5 overlapping"},
+		},
+		{
+			name: "marker in a string",
+			src:  "package main\n\nvar s = `\n// MCP:ROUTES:START\n`\n",
+			want: []string{"4 in_string"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := LintMarkers(tt.src)
+			var got []string
+			for _, p := range problems {
+				got = append(got, fmt.Sprintf("%d %s", p.Line, p.Kind))
+				if p.Fixable != tt.fixable {
+					t.Errorf("line %d %s: Fixable = %v, want %v", p.Line, p.Kind, p.Fixable, tt.fixable)
+				}
+			}
+			if strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("LintMarkers() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// TestFixMarkers tests that fixes leave the source without fixable problems.
+func TestFixMarkers(t *testing.T) {
+	src := `package main
+
+func main() {
+	router := newRouter() // mcp:routes:start
+	// MCP:ROUTES:END
+
+	/* MCP:SERVICES:START */
+	// MCP:SERVICES:STOP
+
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+
+	// MCP:SCHEDULER:START
+}
+`
+	want := `package main
+
+func main() {
+	router := newRouter()
+	// MCP:ROUTES:START
+	// MCP:ROUTES:END
+
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+
+	// MCP:SCHEDULER:START
+	// MCP:SCHEDULER:END
+}
+`
+	got, fixed := FixMarkers(src)
+	if got != want {
+		t.Errorf("FixMarkers() =\n%s\nwant:\n%s", got, want)
+	}
+	if len(fixed) != 5 {
+		t.Errorf("expected 5 fixed problems, got %d: %+v", len(fixed), fixed)
+	}
+	if problems := LintMarkers(got); len(problems) != 0 {
+		t.Errorf("expected no problems after fixing, got %+v", problems)
+	}
+
+	// Fixed markers are found by the injector
+	injector := NewInjectorFromContent(got)
+	if err := injector.InjectBetweenMarkers(MarkerRoutesStart, MarkerRoutesEnd, "router.Get(\"/\", home)"); err != nil {
+		t.Errorf("InjectBetweenMarkers() error = %v", err)
+	}
+}
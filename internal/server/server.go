@@ -61,6 +61,12 @@ Available tools:
 - update_di_wiring: Wire domains into main.go. Run after scaffold_domain.
 - report_bug: Report issues with the scaffolding tools
 
+Available resources:
+- scaffold://routes: All routes registered by scaffolded controllers
+- scaffold://domains: All domains and which layers exist
+- scaffold://domain/{name}: Scaffold metadata for a domain
+- scaffold://domain/{name}/{layer}: Generated source (model, repository, service, dto, controller, list, show, form)
+
 TIP: Use dry_run: true to preview changes before committing. This is safe and encouraged for exploration.`,
 	})
 
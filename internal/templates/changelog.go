@@ -0,0 +1,36 @@
+package templates
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"path"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+// changelogJSON lists the changes to the templates per scaffolder version, oldest first.
+// Add an entry with every template change that shows up in analyze_domain or analyze_project
+// diffs, so the diffs can say why the code changed.
+//
+//go:embed changelog.json
+var changelogJSON []byte
+
+// Changelog returns the template changelog, oldest first.
+func Changelog() ([]types.TemplateChange, error) {
+	var changes []types.TemplateChange
+	if err := json.Unmarshal(changelogJSON, &changes); err != nil {
+		return nil, fmt.Errorf("failed to parse the template changelog: %w", err)
+	}
+	return changes, nil
+}
+
+// ChangeAffects reports whether a changelog entry changed the template at templatePath.
+func ChangeAffects(change types.TemplateChange, templatePath string) bool {
+	for _, pattern := range change.Templates {
+		if matched, _ := path.Match(pattern, templatePath); matched {
+			return true
+		}
+	}
+	return false
+}
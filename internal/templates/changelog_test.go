@@ -0,0 +1,51 @@
+package templates
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+// TestChangelog tests that every changelog entry is complete and names existing templates.
+func TestChangelog(t *testing.T) {
+	changelog, err := Changelog()
+	if err != nil {
+		t.Fatalf("Changelog() error = %v", err)
+	}
+	if len(changelog) == 0 {
+		t.Fatal("Changelog() returned no entries")
+	}
+
+	for i, change := range changelog {
+		if change.Version == "" || change.Summary == "" || len(change.Templates) == 0 {
+			t.Errorf("entry %d is missing its version, summary or templates: %+v", i, change)
+		}
+		for _, pattern := range change.Templates {
+			matches, err := fs.Glob(FS, pattern)
+			if err != nil || len(matches) == 0 {
+				t.Errorf("entry %d (%s): %q matches no template", i, change.Summary, pattern)
+			}
+		}
+	}
+}
+
+// TestChangeAffects tests matching changelog entries against template paths.
+func TestChangeAffects(t *testing.T) {
+	change := types.TemplateChange{Templates: []string{"views/form.templ.tmpl", "api/*"}}
+
+	tests := []struct {
+		template string
+		want     bool
+	}{
+		{"views/form.templ.tmpl", true},
+		{"api/router.go.tmpl", true},
+		{"views/list.templ.tmpl", false},
+		{"", false},
+	}
+	for _, tt := range tests {
+		if got := ChangeAffects(change, tt.template); got != tt.want {
+			t.Errorf("ChangeAffects(%q) = %v, want %v", tt.template, got, tt.want)
+		}
+	}
+}
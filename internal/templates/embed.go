@@ -11,19 +11,38 @@ import (
 // FS is the embedded filesystem containing all template files.
 // Templates use [[ ]] delimiters instead of {{ }} to avoid conflicts with Go templates.
 //
-//go:embed project/*.tmpl domain/*.tmpl views/*.tmpl components/*.tmpl config/*.tmpl seed/*.tmpl auth/*.tmpl usermgmt/*.tmpl usermgmt/views/*.tmpl wizard/*.tmpl
+//go:embed project/*.tmpl domain/*.tmpl views/*.tmpl components/*.tmpl config/*.tmpl seed/*.tmpl auth/*.tmpl usermgmt/*.tmpl usermgmt/views/*.tmpl wizard/*.tmpl mockapi/*.tmpl search/*.tmpl images/*.tmpl scheduler/*.tmpl cache/*.tmpl api/*.tmpl tenancy/*.tmpl webhooks/*.tmpl settings/*.tmpl i18n/*.tmpl deploy/*.tmpl health/*.tmpl tests/*.tmpl events/*.tmpl client/*.tmpl typescript/*.tmpl realtime/*.tmpl pages/*.tmpl mail/*.tmpl blog/*.tmpl
 var FS embed.FS
 
 // Template directories:
 // - project/    : Project scaffolding templates (go.mod, main.go, config, etc.)
 // - domain/     : Domain layer templates (model, repository, service, controller, dto)
 // - views/      : View templates (list, show, form, table, partials)
-// - components/ : Component templates (card, modal, form_field, wizard)
+// - components/ : Component templates (card, modal, form_field, wizard, tabs, accordion, dropdown, toast, badge, tooltip)
 // - config/     : Configuration templates (page.toml)
-// - seed/       : Seeder templates (seeder.go)
+// - seed/       : Seeder templates (seeder.go, the dependency-ordered runner, fixture parsing)
 // - auth/       : Authentication templates (user_model, middleware, service, controller, views)
 // - usermgmt/   : User management templates (service, controller, views)
 // - wizard/     : Wizard templates (controller, views, draft model/repo/service)
+// - mockapi/    : Mock API server templates (tools/mockapi/main.go)
+// - search/     : Search engine indexing templates (indexer clients, service hooks, reindex command)
+// - images/     : Image upload templates (file storage, image processing, reprocess command)
+// - scheduler/  : Background task scheduler templates (periodic tasks such as scheduled publishing)
+// - cache/      : Cache templates (Redis and in-memory caches for repository decorators)
+// - api/        : API-only project templates (JSON router, response helpers, domain controller)
+// - tenancy/    : Multi-tenancy templates (Tenant model, tenant resolution, GORM scoping, tenant seeder)
+// - webhooks/   : Webhook templates (endpoints and deliveries, signed outbound delivery, inbound receivers)
+// - settings/   : Settings templates (setting model, cached typed accessors, admin settings page)
+// - i18n/       : I18n templates (locale middleware, translation helper, language switcher)
+// - deploy/     : Deployment templates (Docker, Kubernetes, systemd, CI and release files)
+// - health/     : Health templates (health, readiness and version endpoints)
+// - tests/      : Test templates (repository, service and controller tests, test data factories)
+// - client/     : API client templates (typed Go client for the JSON API in pkg/client)
+// - typescript/ : TypeScript templates (DTO type definitions or zod schemas in web/src/types)
+// - realtime/   : Realtime templates (server-sent events hub, service decorators publishing changes)
+// - pages/      : Static page templates (about, pricing, contact and terms presets with TOML content)
+// - mail/       : Mail templates (SMTP sender configured by environment variables)
+// - blog/       : Blog templates (published post queries, Markdown rendering, list/post pages, RSS and Atom feeds)
 
 // Categories of templates available.
 var Categories = []string{
@@ -36,6 +55,26 @@ var Categories = []string{
 	"auth",
 	"usermgmt",
 	"wizard",
+	"mockapi",
+	"search",
+	"images",
+	"scheduler",
+	"cache",
+	"api",
+	"tenancy",
+	"webhooks",
+	"settings",
+	"i18n",
+	"deploy",
+	"health",
+	"tests",
+	"events",
+	"client",
+	"typescript",
+	"realtime",
+	"pages",
+	"mail",
+	"blog",
 }
 
 // ReadTemplate reads a template file by path and returns its contents.
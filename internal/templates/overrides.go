@@ -0,0 +1,60 @@
+package templates
+
+import (
+	"io/fs"
+	"os"
+	"sort"
+)
+
+// WithOverrides returns the templates with the files of dir in place of the embedded
+// templates of the same path, e.g. dir/domain/model.go.tmpl replaces domain/model.go.tmpl.
+// Templates dir doesn't have are read from FS.
+func WithOverrides(dir string) fs.FS {
+	return overlayFS{override: os.DirFS(dir), base: FS}
+}
+
+// overlayFS reads files from override first and from base when override lacks them.
+type overlayFS struct {
+	override fs.FS
+	base     fs.FS
+}
+
+func (o overlayFS) Open(name string) (fs.File, error) {
+	if f, err := o.override.Open(name); err == nil {
+		if info, err := f.Stat(); err == nil && !info.IsDir() {
+			return f, nil
+		}
+		f.Close()
+	}
+	return o.base.Open(name)
+}
+
+func (o overlayFS) ReadFile(name string) ([]byte, error) {
+	if content, err := fs.ReadFile(o.override, name); err == nil {
+		return content, nil
+	}
+	return fs.ReadFile(o.base, name)
+}
+
+// ReadDir merges the entries of a directory in override and base.
+func (o overlayFS) ReadDir(name string) ([]fs.DirEntry, error) {
+	baseEntries, baseErr := fs.ReadDir(o.base, name)
+	overrideEntries, overrideErr := fs.ReadDir(o.override, name)
+	if baseErr != nil && overrideErr != nil {
+		return nil, baseErr
+	}
+
+	entries := make(map[string]fs.DirEntry)
+	for _, e := range baseEntries {
+		entries[e.Name()] = e
+	}
+	for _, e := range overrideEntries {
+		entries[e.Name()] = e
+	}
+	merged := make([]fs.DirEntry, 0, len(entries))
+	for _, e := range entries {
+		merged = append(merged, e)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Name() < merged[j].Name() })
+	return merged, nil
+}
@@ -0,0 +1,53 @@
+package templates
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestWithOverrides(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"domain/model.go.tmpl":  "package models\n",
+		"domain/extra.go.tmpl":  "package extra\n",
+		"custom/widget.go.tmpl": "package widget\n",
+	} {
+		path := filepath.Join(dir, name)
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	fsys := WithOverrides(dir)
+
+	if content, err := fs.ReadFile(fsys, "domain/model.go.tmpl"); err != nil || string(content) != "package models\n" {
+		t.Errorf("expected the override of domain/model.go.tmpl, got %q, %v", content, err)
+	}
+	builtin, _ := FS.ReadFile("domain/service.go.tmpl")
+	if content, err := fs.ReadFile(fsys, "domain/service.go.tmpl"); err != nil || string(content) != string(builtin) {
+		t.Errorf("expected the built-in domain/service.go.tmpl, got %v", err)
+	}
+	if _, err := fs.ReadFile(fsys, "domain/missing.go.tmpl"); err == nil {
+		t.Error("expected an error for a template neither has")
+	}
+
+	entries, err := fs.ReadDir(fsys, "domain")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, e := range entries {
+		names = append(names, e.Name())
+	}
+	if !slices.Contains(names, "extra.go.tmpl") || !slices.Contains(names, "service.go.tmpl") || !slices.IsSorted(names) {
+		t.Errorf("expected the merged, sorted entries of domain, got %v", names)
+	}
+	if entries, err := fs.ReadDir(fsys, "custom"); err != nil || len(entries) != 1 {
+		t.Errorf("expected the directory only the overrides have, got %v, %v", entries, err)
+	}
+}
@@ -70,14 +70,38 @@ func TestProjectTemplatesExecute(t *testing.T) {
 		ProjectName        string
 		ModulePath         string
 		DatabaseType       string
+		ProjectType        string
 		WithAuth           bool
 		WithUserManagement bool
+		Navigation         string
+		WithDarkMode       bool
+		CSSPipeline        string
+		JSBundler          string
+		WithNPM            bool
+		WithHTTPCaching    bool
+		WithOtel           bool
+		WithDiagnostics    bool
+		ErrorReporting     string
+		DI                 string
+		IconSet            string
 	}{
 		ProjectName:        "testproject",
 		ModulePath:         "github.com/test/testproject",
 		DatabaseType:       "sqlite",
+		ProjectType:        "web",
 		WithAuth:           true,
 		WithUserManagement: false,
+		Navigation:         "boost",
+		WithDarkMode:       true,
+		CSSPipeline:        "tailwind",
+		JSBundler:          "esbuild",
+		WithNPM:            true,
+		WithHTTPCaching:    true,
+		WithOtel:           true,
+		WithDiagnostics:    true,
+		ErrorReporting:     "sentry",
+		DI:                 "wire",
+		IconSet:            "lucide",
 	}
 
 	templates := []string{
@@ -97,7 +121,30 @@ func TestProjectTemplatesExecute(t *testing.T) {
 		"project/router.go.tmpl",
 		"project/common_components.templ.tmpl",
 		"project/menu.toml.tmpl",
+		"project/package.json.tmpl",
+		"project/postcss.config.mjs.tmpl",
+		"project/js_main.js.tmpl",
+		"project/js_htmx.js.tmpl",
+		"project/esbuild.config.mjs.tmpl",
+		"project/vite.config.mjs.tmpl",
+		"project/assets.go.tmpl",
+		"project/menu.go.tmpl",
 		"project/seed_main.go.tmpl",
+		"project/http_cache.go.tmpl",
+		"project/telemetry.go.tmpl",
+		"project/telemetry_gorm.go.tmpl",
+		"project/logging.go.tmpl",
+		"project/logging_gorm.go.tmpl",
+		"project/diagnostics.go.tmpl",
+		"project/errorreporting.go.tmpl",
+		"project/wire.go.tmpl",
+		"project/wire_gen.go.tmpl",
+		"project/domains.go.tmpl",
+		"project/routes.go.tmpl",
+		"api/main.go.tmpl",
+		"api/router.go.tmpl",
+		"api/response.go.tmpl",
+		"api/middleware.go.tmpl",
 	}
 
 	for _, tmplPath := range templates {
@@ -128,24 +175,43 @@ func TestProjectTemplatesExecute(t *testing.T) {
 // TestDomainTemplatesExecute tests that domain templates execute with valid data.
 func TestDomainTemplatesExecute(t *testing.T) {
 	domainData := struct {
-		ModulePath           string
-		DomainName           string
-		ModelName            string
-		PackageName          string
-		VariableName         string
-		TableName            string
-		URLPath              string
-		URLPathSegment       string
-		Fields               []generator.FieldData
-		Relationships        []generator.RelationshipData
-		HasRelationships     bool
-		PreloadRelationships []generator.RelationshipData
-		WithSoftDelete       bool
-		WithCrudViews        bool
-		WithPagination       bool
-		WithSearch           bool
-		Layout               string
-		RouteGroup           string
+		ModulePath            string
+		DomainName            string
+		ModelName             string
+		PackageName           string
+		VariableName          string
+		TableName             string
+		URLPath               string
+		URLPathSegment        string
+		Fields                []generator.FieldData
+		Relationships         []generator.RelationshipData
+		HasRelationships      bool
+		PreloadRelationships  []generator.RelationshipData
+		WithSoftDelete        bool
+		WithOptimisticLocking bool
+		WithModeration        bool
+		Filters               []generator.FilterData
+		WithBulkActions       bool
+		BulkActions           []generator.BulkActionData
+		FullTextSearch        bool
+		WithScheduling        bool
+		SearchFields          []generator.SearchFieldData
+		WithObservability     bool
+		WithCrudViews         bool
+		WithPagination        bool
+		WithSearch            bool
+		Layout                string
+		RouteGroup            string
+		APIVersion            string
+		APIPrefix             string
+		WithVersionedDTO      bool
+		TenantScoped          bool
+		WithLogging           bool
+		Hooks                 []generator.HookData
+		OwnedByUser           bool
+		WithSEO               bool
+		WithBreadcrumbs       bool
+		WithCache             bool
 	}{
 		ModulePath:     "github.com/test/testproject",
 		DomainName:     "product",
@@ -170,6 +236,7 @@ func TestDomainTemplatesExecute(t *testing.T) {
 		WithSearch:           true,
 		Layout:               "dashboard",
 		RouteGroup:           "public",
+		Hooks:                generator.NewHookDataList("product", []string{"before_create", "after_update"}),
 	}
 
 	templates := []string{
@@ -178,6 +245,11 @@ func TestDomainTemplatesExecute(t *testing.T) {
 		"domain/service.go.tmpl",
 		"domain/controller.go.tmpl",
 		"domain/dto.go.tmpl",
+		"domain/hooks.go.tmpl",
+		"domain/providers.go.tmpl",
+		"domain/routes.go.tmpl",
+		"cache/repository.go.tmpl",
+		"api/controller.go.tmpl",
 	}
 
 	for _, tmplPath := range templates {
@@ -208,30 +280,40 @@ func TestDomainTemplatesExecute(t *testing.T) {
 // TestViewTemplatesExecute tests that view templates execute with valid data.
 func TestViewTemplatesExecute(t *testing.T) {
 	viewData := struct {
-		ModulePath        string
-		DomainName        string
-		ModelName         string
-		PackageName       string
-		VariableName      string
-		URLPath           string
-		URLPathSegment    string
-		ViewType          string
-		ViewName          string
-		Fields            []generator.FieldData
-		Columns           []generator.ColumnData
-		Relationships     []generator.RelationshipData
-		WithPagination    bool
-		WithSearch        bool
-		WithFilters       bool
-		WithSorting       bool
-		WithBulkActions   bool
-		WithSoftDelete    bool
-		RowActions        []generator.RowActionData
-		EmptyStateMessage string
-		SubmitURL         string
-		Method            string
-		SuccessRedirect   string
-		FormStyle         string
+		ModulePath            string
+		DomainName            string
+		ModelName             string
+		PackageName           string
+		VariableName          string
+		URLPath               string
+		URLPathSegment        string
+		ViewType              string
+		ViewName              string
+		Fields                []generator.FieldData
+		Columns               []generator.ColumnData
+		Relationships         []generator.RelationshipData
+		WithPagination        bool
+		WithSearch            bool
+		WithFilters           bool
+		WithSorting           bool
+		WithBulkActions       bool
+		WithRealtime          bool
+		WithSoftDelete        bool
+		WithOptimisticLocking bool
+		WithModeration        bool
+		Filters               []generator.FilterData
+		BulkActions           []generator.BulkActionData
+		FullTextSearch        bool
+		WithScheduling        bool
+		SearchFields          []generator.SearchFieldData
+		RowActions            []generator.RowActionData
+		EmptyStateMessage     string
+		SubmitURL             string
+		Method                string
+		SuccessRedirect       string
+		FormStyle             string
+		Steps                 []generator.FormStepData
+		Icon                  string
 	}{
 		ModulePath:     "github.com/test/testproject",
 		DomainName:     "product",
@@ -305,13 +387,21 @@ func TestViewTemplatesExecute(t *testing.T) {
 // TestAuthTemplatesExecute tests that auth templates execute with valid data.
 func TestAuthTemplatesExecute(t *testing.T) {
 	authData := struct {
-		ModulePath  string
-		ProjectName string
-		SessionType string
+		ModulePath     string
+		ProjectName    string
+		SessionType    string
+		ErrorReporting string
+		WithDarkMode   bool
+		CSSPipeline    string
+		JSBundler      string
 	}{
-		ModulePath:  "github.com/test/testproject",
-		ProjectName: "testproject",
-		SessionType: "cookie",
+		ModulePath:     "github.com/test/testproject",
+		ProjectName:    "testproject",
+		SessionType:    "cookie",
+		ErrorReporting: "sentry",
+		WithDarkMode:   true,
+		CSSPipeline:    "tailwind",
+		JSBundler:      "esbuild",
 	}
 
 	templates := []string{
@@ -388,6 +478,13 @@ func TestComponentTemplatesExecute(t *testing.T) {
 		IsDialog       bool
 		IsSheet        bool
 		IsConfirm      bool
+		ElementID      string
+		Domain         string
+		Action         string
+		IsEdit         bool
+		ModelName      string
+		DomainURL      string
+		ListID         string
 	}{
 		ModulePath:     "github.com/test/testproject",
 		ModalName:      "ConfirmDelete",
@@ -400,6 +497,7 @@ func TestComponentTemplatesExecute(t *testing.T) {
 		IsDialog:       false,
 		IsSheet:        false,
 		IsConfirm:      true,
+		ElementID:      "confirm-delete",
 	}
 
 	t.Run("components/card.templ.tmpl", func(t *testing.T) {
@@ -484,6 +582,10 @@ func TestSeedTemplatesExecute(t *testing.T) {
 		Distributions    []generator.SeedDistributionData
 		HasRelationships bool
 		HasDistributions bool
+		TenantScoped     bool
+		Source           string
+		KeyField         string
+		KeyColumn        string
 	}{
 		ModulePath: "github.com/test/testproject",
 		DomainName: "product",
@@ -641,24 +743,42 @@ func TestConfigTemplatesExecute(t *testing.T) {
 func TestDomainTemplatesWithRelationships(t *testing.T) {
 	fkField := generator.FieldData{Name: "UserID", Type: "uint", JSONName: "user_id"}
 	domainData := struct {
-		ModulePath           string
-		DomainName           string
-		ModelName            string
-		PackageName          string
-		VariableName         string
-		TableName            string
-		URLPath              string
-		URLPathSegment       string
-		Fields               []generator.FieldData
-		Relationships        []generator.RelationshipData
-		HasRelationships     bool
-		PreloadRelationships []generator.RelationshipData
-		WithSoftDelete       bool
-		WithCrudViews        bool
-		WithPagination       bool
-		WithSearch           bool
-		Layout               string
-		RouteGroup           string
+		ModulePath            string
+		DomainName            string
+		ModelName             string
+		PackageName           string
+		VariableName          string
+		TableName             string
+		URLPath               string
+		URLPathSegment        string
+		Fields                []generator.FieldData
+		Relationships         []generator.RelationshipData
+		HasRelationships      bool
+		PreloadRelationships  []generator.RelationshipData
+		WithSoftDelete        bool
+		WithOptimisticLocking bool
+		WithModeration        bool
+		Filters               []generator.FilterData
+		WithBulkActions       bool
+		BulkActions           []generator.BulkActionData
+		FullTextSearch        bool
+		WithScheduling        bool
+		SearchFields          []generator.SearchFieldData
+		WithObservability     bool
+		WithCrudViews         bool
+		WithPagination        bool
+		WithSearch            bool
+		Layout                string
+		RouteGroup            string
+		APIVersion            string
+		APIPrefix             string
+		WithVersionedDTO      bool
+		TenantScoped          bool
+		WithLogging           bool
+		Hooks                 []generator.HookData
+		OwnedByUser           bool
+		WithSEO               bool
+		WithBreadcrumbs       bool
 	}{
 		ModulePath:     "github.com/test/testproject",
 		DomainName:     "order",
@@ -706,12 +826,17 @@ func TestDomainTemplatesWithRelationships(t *testing.T) {
 				Preload:     true,
 			},
 		},
-		WithSoftDelete: true,
-		WithCrudViews:  true,
-		WithPagination: true,
-		WithSearch:     true,
-		Layout:         "dashboard",
-		RouteGroup:     "public",
+		WithSoftDelete:   true,
+		WithCrudViews:    true,
+		WithPagination:   true,
+		WithSearch:       true,
+		Layout:           "dashboard",
+		RouteGroup:       "public",
+		APIVersion:       "v1",
+		APIPrefix:        "/api/v1",
+		WithVersionedDTO: true,
+		TenantScoped:     true,
+		WithLogging:      true,
 	}
 
 	templates := []string{
@@ -720,6 +845,9 @@ func TestDomainTemplatesWithRelationships(t *testing.T) {
 		"domain/service.go.tmpl",
 		"domain/controller.go.tmpl",
 		"domain/dto.go.tmpl",
+		"cache/repository.go.tmpl",
+		"api/controller.go.tmpl",
+		"api/versioned_dto.go.tmpl",
 	}
 
 	for _, tmplPath := range templates {
@@ -868,7 +996,7 @@ func TestListTemplatesInCategory(t *testing.T) {
 		if err != nil {
 			t.Fatalf("ListTemplatesInCategory failed: %v", err)
 		}
-		expectedCount := 5 // model, repository, service, controller, dto
+		expectedCount := 9 // model, repository, service, controller, dto, hooks, providers, routes, page_meta
 		if len(templates) != expectedCount {
 			t.Errorf("domain category should have %d templates, got %d", expectedCount, len(templates))
 		}
@@ -932,6 +1060,26 @@ func TestCategories(t *testing.T) {
 		"auth",
 		"usermgmt",
 		"wizard",
+		"mockapi",
+		"search",
+		"images",
+		"scheduler",
+		"cache",
+		"api",
+		"tenancy",
+		"webhooks",
+		"settings",
+		"i18n",
+		"deploy",
+		"health",
+		"tests",
+		"events",
+		"client",
+		"typescript",
+		"realtime",
+		"pages",
+		"mail",
+		"blog",
 	}
 
 	if len(Categories) != len(expectedCategories) {
@@ -949,30 +1097,39 @@ func TestCategories(t *testing.T) {
 // when the options field is provided on a select form_type field.
 func TestFormSelectOptionsRendering(t *testing.T) {
 	viewData := struct {
-		ModulePath        string
-		DomainName        string
-		ModelName         string
-		PackageName       string
-		VariableName      string
-		URLPath           string
-		URLPathSegment    string
-		ViewType          string
-		ViewName          string
-		Fields            []generator.FieldData
-		Columns           []generator.ColumnData
-		Relationships     []generator.RelationshipData
-		WithPagination    bool
-		WithSearch        bool
-		WithFilters       bool
-		WithSorting       bool
-		WithBulkActions   bool
-		WithSoftDelete    bool
-		RowActions        []generator.RowActionData
-		EmptyStateMessage string
-		SubmitURL         string
-		Method            string
-		SuccessRedirect   string
-		FormStyle         string
+		ModulePath            string
+		DomainName            string
+		ModelName             string
+		PackageName           string
+		VariableName          string
+		URLPath               string
+		URLPathSegment        string
+		ViewType              string
+		ViewName              string
+		Fields                []generator.FieldData
+		Columns               []generator.ColumnData
+		Relationships         []generator.RelationshipData
+		WithPagination        bool
+		WithSearch            bool
+		WithFilters           bool
+		WithSorting           bool
+		WithBulkActions       bool
+		WithSoftDelete        bool
+		WithOptimisticLocking bool
+		WithModeration        bool
+		Filters               []generator.FilterData
+		BulkActions           []generator.BulkActionData
+		FullTextSearch        bool
+		WithScheduling        bool
+		SearchFields          []generator.SearchFieldData
+		RowActions            []generator.RowActionData
+		EmptyStateMessage     string
+		SubmitURL             string
+		Method                string
+		SuccessRedirect       string
+		FormStyle             string
+		Steps                 []generator.FormStepData
+		Icon                  string
 	}{
 		ModulePath:     "github.com/test/testproject",
 		DomainName:     "discount",
@@ -1079,30 +1236,39 @@ func TestFormSelectOptionsRendering(t *testing.T) {
 // is sent when the checkbox is unchecked.
 func TestFormBooleanCheckboxRendering(t *testing.T) {
 	viewData := struct {
-		ModulePath        string
-		DomainName        string
-		ModelName         string
-		PackageName       string
-		VariableName      string
-		URLPath           string
-		URLPathSegment    string
-		ViewType          string
-		ViewName          string
-		Fields            []generator.FieldData
-		Columns           []generator.ColumnData
-		Relationships     []generator.RelationshipData
-		WithPagination    bool
-		WithSearch        bool
-		WithFilters       bool
-		WithSorting       bool
-		WithBulkActions   bool
-		WithSoftDelete    bool
-		RowActions        []generator.RowActionData
-		EmptyStateMessage string
-		SubmitURL         string
-		Method            string
-		SuccessRedirect   string
-		FormStyle         string
+		ModulePath            string
+		DomainName            string
+		ModelName             string
+		PackageName           string
+		VariableName          string
+		URLPath               string
+		URLPathSegment        string
+		ViewType              string
+		ViewName              string
+		Fields                []generator.FieldData
+		Columns               []generator.ColumnData
+		Relationships         []generator.RelationshipData
+		WithPagination        bool
+		WithSearch            bool
+		WithFilters           bool
+		WithSorting           bool
+		WithBulkActions       bool
+		WithSoftDelete        bool
+		WithOptimisticLocking bool
+		WithModeration        bool
+		Filters               []generator.FilterData
+		BulkActions           []generator.BulkActionData
+		FullTextSearch        bool
+		WithScheduling        bool
+		SearchFields          []generator.SearchFieldData
+		RowActions            []generator.RowActionData
+		EmptyStateMessage     string
+		SubmitURL             string
+		Method                string
+		SuccessRedirect       string
+		FormStyle             string
+		Steps                 []generator.FormStepData
+		Icon                  string
 	}{
 		ModulePath:     "github.com/test/testproject",
 		DomainName:     "discount",
@@ -1179,24 +1345,36 @@ func TestFormBooleanCheckboxRendering(t *testing.T) {
 // The controller must check all form values, not just the first one.
 func TestControllerBooleanCheckboxHandling(t *testing.T) {
 	domainData := struct {
-		ModulePath           string
-		DomainName           string
-		ModelName            string
-		PackageName          string
-		VariableName         string
-		TableName            string
-		URLPath              string
-		URLPathSegment       string
-		Fields               []generator.FieldData
-		Relationships        []generator.RelationshipData
-		HasRelationships     bool
-		PreloadRelationships []generator.RelationshipData
-		WithSoftDelete       bool
-		WithCrudViews        bool
-		WithPagination       bool
-		WithSearch           bool
-		Layout               string
-		RouteGroup           string
+		ModulePath            string
+		DomainName            string
+		ModelName             string
+		PackageName           string
+		VariableName          string
+		TableName             string
+		URLPath               string
+		URLPathSegment        string
+		Fields                []generator.FieldData
+		Relationships         []generator.RelationshipData
+		HasRelationships      bool
+		PreloadRelationships  []generator.RelationshipData
+		WithSoftDelete        bool
+		WithOptimisticLocking bool
+		WithModeration        bool
+		Filters               []generator.FilterData
+		WithBulkActions       bool
+		BulkActions           []generator.BulkActionData
+		FullTextSearch        bool
+		WithScheduling        bool
+		SearchFields          []generator.SearchFieldData
+		WithCrudViews         bool
+		WithPagination        bool
+		WithSearch            bool
+		Layout                string
+		RouteGroup            string
+		WithLogging           bool
+		OwnedByUser           bool
+		WithSEO               bool
+		WithBreadcrumbs       bool
 	}{
 		ModulePath:     "github.com/test/testproject",
 		DomainName:     "discount",
@@ -1288,30 +1466,39 @@ func TestControllerBooleanCheckboxHandling(t *testing.T) {
 func TestBelongsToDisplayFieldRendering(t *testing.T) {
 	fkField := generator.FieldData{Name: "CategoryID", Type: "uint", JSONName: "category_id"}
 	viewData := struct {
-		ModulePath        string
-		DomainName        string
-		ModelName         string
-		PackageName       string
-		VariableName      string
-		URLPath           string
-		URLPathSegment    string
-		ViewType          string
-		ViewName          string
-		Fields            []generator.FieldData
-		Columns           []generator.ColumnData
-		Relationships     []generator.RelationshipData
-		WithPagination    bool
-		WithSearch        bool
-		WithFilters       bool
-		WithSorting       bool
-		WithBulkActions   bool
-		WithSoftDelete    bool
-		RowActions        []generator.RowActionData
-		EmptyStateMessage string
-		SubmitURL         string
-		Method            string
-		SuccessRedirect   string
-		FormStyle         string
+		ModulePath            string
+		DomainName            string
+		ModelName             string
+		PackageName           string
+		VariableName          string
+		URLPath               string
+		URLPathSegment        string
+		ViewType              string
+		ViewName              string
+		Fields                []generator.FieldData
+		Columns               []generator.ColumnData
+		Relationships         []generator.RelationshipData
+		WithPagination        bool
+		WithSearch            bool
+		WithFilters           bool
+		WithSorting           bool
+		WithBulkActions       bool
+		WithSoftDelete        bool
+		WithOptimisticLocking bool
+		WithModeration        bool
+		Filters               []generator.FilterData
+		BulkActions           []generator.BulkActionData
+		FullTextSearch        bool
+		WithScheduling        bool
+		SearchFields          []generator.SearchFieldData
+		RowActions            []generator.RowActionData
+		EmptyStateMessage     string
+		SubmitURL             string
+		Method                string
+		SuccessRedirect       string
+		FormStyle             string
+		Steps                 []generator.FormStepData
+		Icon                  string
 	}{
 		ModulePath:     "github.com/test/testproject",
 		DomainName:     "product",
@@ -1426,13 +1613,22 @@ func TestBelongsToDisplayFieldRendering(t *testing.T) {
 
 		// Create DTO-specific data with HasRelationships flag
 		dtoData := struct {
-			ModulePath       string
-			ModelName        string
-			PackageName      string
-			VariableName     string
-			Fields           []generator.FieldData
-			Relationships    []generator.RelationshipData
-			HasRelationships bool
+			ModulePath            string
+			ModelName             string
+			PackageName           string
+			VariableName          string
+			Fields                []generator.FieldData
+			Relationships         []generator.RelationshipData
+			HasRelationships      bool
+			WithOptimisticLocking bool
+			WithModeration        bool
+			Filters               []generator.FilterData
+			WithBulkActions       bool
+			BulkActions           []generator.BulkActionData
+			FullTextSearch        bool
+			WithScheduling        bool
+			SearchFields          []generator.SearchFieldData
+			OwnedByUser           bool
 		}{
 			ModulePath:   "github.com/test/testproject",
 			ModelName:    "Product",
@@ -1544,6 +1740,7 @@ func TestWizardControllerTemplatePatterns(t *testing.T) {
 		FormStyle        string
 		SuccessRedirect  string
 		WithDrafts       bool
+		HasConditions    bool
 	}{
 		ModulePath:       "github.com/test/testproject",
 		WizardName:       "create_order",
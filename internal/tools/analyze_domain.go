@@ -9,6 +9,7 @@ import (
 
 	"github.com/dbb1dev/go-mcp/internal/generator"
 	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/templates"
 	"github.com/dbb1dev/go-mcp/internal/types"
 	"github.com/dbb1dev/go-mcp/internal/utils"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -26,7 +27,7 @@ helping identify opportunities to upgrade code to use new template features.
 
 Requirements:
 - Domain must have been scaffolded with metadata tracking (scaffold_domain saves this automatically)
-- For domains without metadata, use list_domains to see what exists
+- For domains scaffolded before metadata tracking, run reconstruct_metadata first
 
 Use cases:
 - Check if a domain can benefit from new template features (e.g., form_style, belongs_to display)
@@ -35,8 +36,11 @@ Use cases:
 
 Output includes:
 - List of files with differences
-- Unified diff showing what would change
+- Unified diff showing what would change, with numbered hunks ("@@ -12,7 +12,9 @@ hunk 2")
 - Summary of added/removed lines
+- The template changelog entries since the domain was scaffolded that explain each diff (changes)
+
+sync_domain applies the changes, for chosen files or only chosen hunks of a file.
 
 Examples:
 1. Analyze a specific domain:
@@ -45,13 +49,13 @@ Examples:
 2. Analyze all domains:
    analyze_domain: {}
 
-3. Analyze only views:
+3. Analyze only views (layers: model, repository, service, controller, views, types):
    analyze_domain: { domain: "order", layers: ["views"] }
 
 4. Include unchanged files:
    analyze_domain: { domain: "order", show_unchanged: true }`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.AnalyzeDomainInput) (*mcp.CallToolResult, types.AnalyzeDomainResult, error) {
-		result, err := ExecuteAnalyzeDomain(ctx, registry, input)
+		result, err := ExecuteAnalyzeDomain(ctx, registry.For(ctx), input)
 		if err != nil {
 			return nil, types.AnalyzeDomainResult{Success: false, Message: err.Error()}, nil
 		}
@@ -77,7 +81,7 @@ func ExecuteAnalyzeDomain(ctx context.Context, registry *Registry, input types.A
 		if !exists {
 			return types.AnalyzeDomainResult{
 				Success: false,
-				Message: fmt.Sprintf("No metadata found for domain '%s'. Domain may have been scaffolded before metadata tracking was added, or may not exist.", input.Domain),
+				Message: fmt.Sprintf("No metadata found for domain '%s'. Domain may have been scaffolded before metadata tracking was added (run reconstruct_metadata), or may not exist.", input.Domain),
 			}, nil
 		}
 		domainsToAnalyze = []string{input.Domain}
@@ -139,7 +143,8 @@ func ExecuteAnalyzeDomain(ctx context.Context, registry *Registry, input types.A
 	if totalChanges > 0 {
 		result.SuggestedTool = &types.ToolHint{
 			Tool:        "sync_domain",
-			Description: "Apply the detected changes to update scaffolded code",
+			Description: "Apply the detected changes to update scaffolded code, for chosen files or hunks",
+			Example:     `sync_domain: { domain: "order", files: ["internal/web/order/order.go"] }`,
 			Priority:    "optional",
 		}
 	}
@@ -165,10 +170,108 @@ func analyzeSingleDomain(registry *Registry, metaStore *metadata.Store, domainNa
 		HasChanges:        false,
 	}
 
+	gen, err := generateDomainFiles(registry, domainMeta.Input)
+	if err != nil {
+		return types.DomainAnalysis{}, err
+	}
+	changelog, err := templates.Changelog()
+	if err != nil {
+		return types.DomainAnalysis{}, err
+	}
+
+	// Build layer filter
+	layerFilter := make(map[string]bool)
+	if len(layers) > 0 {
+		for _, l := range layers {
+			layerFilter[strings.ToLower(l)] = true
+		}
+	}
+
+	// Get result and compare files
+	result := gen.Result()
+
+	// Combine created and updated files for comparison
+	allFiles := append(result.FilesCreated, result.FilesUpdated...)
+
+	for _, filePath := range allFiles {
+		// Check layer filter
+		if len(layerFilter) > 0 && !matchesLayer(filePath, layerFilter) {
+			continue
+		}
+
+		generatedContent := gen.GetFileContent(filePath)
+		if generatedContent == "" {
+			continue // Skip if we can't get generated content
+		}
+		existingContent, err := os.ReadFile(filepath.Join(registry.WorkingDir, filePath))
+		if err == nil {
+			// Code extend_* tools injected between markers is not drift
+			generatedContent = keepInjectedLines(string(existingContent), generatedContent)
+		}
+		fileAnalysis, ok := compareFile(filePath, existingContent, err, generatedContent, showUnchanged)
+		if !ok {
+			continue // Skip unchanged files
+		}
+		if fileAnalysis.Status == "modified" || fileAnalysis.Status == "missing" {
+			analysis.HasChanges = true
+		}
+		if fileAnalysis.Status == "modified" {
+			fileAnalysis.Changes = templateChangesSince(changelog, domainMeta.ScaffolderVersion, gen.GetFileTemplate(filePath))
+		}
+
+		analysis.Files = append(analysis.Files, fileAnalysis)
+	}
+
+	return analysis, nil
+}
+
+// compareFile compares the existing content of a file, as read with readErr, with the content
+// the templates generate. It reports false for an unchanged file unless showUnchanged is set.
+func compareFile(filePath string, existing []byte, readErr error, generated string, showUnchanged bool) (types.FileAnalysis, bool) {
+	fileAnalysis := types.FileAnalysis{Path: filePath}
+
+	if readErr != nil {
+		if os.IsNotExist(readErr) {
+			fileAnalysis.Status = "missing"
+			fileAnalysis.Diff = "File does not exist (may have been deleted)"
+		} else {
+			fileAnalysis.Status = "error"
+			fileAnalysis.Diff = readErr.Error()
+		}
+		return fileAnalysis, true
+	}
+
+	existingStr := string(existing)
+	if existingStr == generated {
+		fileAnalysis.Status = "unchanged"
+		return fileAnalysis, showUnchanged
+	}
+
+	// Generate unified diff, numbering the hunks sync_domain can apply
+	fileAnalysis.Status = "modified"
+	lines := lineDiff(existingStr, generated)
+	fileAnalysis.Diff = formatHunks(filePath, lines)
+	fileAnalysis.Hunks = countHunks(lines)
+
+	// Count changes
+	for _, l := range lines {
+		switch l.op {
+		case '+':
+			fileAnalysis.LinesAdded++
+		case '-':
+			fileAnalysis.LinesRemoved++
+		}
+	}
+	return fileAnalysis, true
+}
+
+// generateDomainFiles renders the files of a domain from its stored scaffold input, without
+// writing them. The content of each file is kept in the returned generator.
+func generateDomainFiles(registry *Registry, domainInput types.ScaffoldDomainInput) (*generator.Generator, error) {
 	// Get module path
 	modulePath, err := utils.GetModulePath(registry.WorkingDir)
 	if err != nil {
-		return types.DomainAnalysis{}, fmt.Errorf("failed to get module path: %w", err)
+		return nil, fmt.Errorf("failed to get module path: %w", err)
 	}
 
 	// Create generator in dry run mode with content storage
@@ -178,8 +281,10 @@ func analyzeSingleDomain(registry *Registry, metaStore *metadata.Store, domainNa
 	gen.SetForceOverwrite(true) // Allow "overwriting" to capture all files
 
 	// Prepare template data using the stored input
-	domainInput := domainMeta.Input
 	data := generator.NewDomainData(domainInput, modulePath)
+	data.WithLogging = registry.HasStructuredLogging()
+	data.WithSEO = registry.HasSEO()
+	data.WithBreadcrumbs = registry.HasBreadcrumbs()
 
 	// Generate all domain files (same logic as scaffold_domain)
 	pkgName := utils.ToPackageName(domainInput.DomainName)
@@ -187,31 +292,69 @@ func analyzeSingleDomain(registry *Registry, metaStore *metadata.Store, domainNa
 	// Generate model
 	modelPath := filepath.Join("internal", "models", pkgName+".go")
 	if err := gen.GenerateFile("domain/model.go.tmpl", modelPath, data); err != nil {
-		return types.DomainAnalysis{}, fmt.Errorf("failed to generate model: %w", err)
+		return nil, fmt.Errorf("failed to generate model: %w", err)
 	}
 
 	// Generate repository
 	repoPath := filepath.Join("internal", "repository", pkgName, pkgName+".go")
 	if err := gen.GenerateFile("domain/repository.go.tmpl", repoPath, data); err != nil {
-		return types.DomainAnalysis{}, fmt.Errorf("failed to generate repository: %w", err)
+		return nil, fmt.Errorf("failed to generate repository: %w", err)
 	}
 
 	// Generate service
 	servicePath := filepath.Join("internal", "services", pkgName, pkgName+".go")
 	if err := gen.GenerateFile("domain/service.go.tmpl", servicePath, data); err != nil {
-		return types.DomainAnalysis{}, fmt.Errorf("failed to generate service: %w", err)
+		return nil, fmt.Errorf("failed to generate service: %w", err)
 	}
 
 	// Generate DTOs
 	dtoPath := filepath.Join("internal", "services", pkgName, "dto.go")
 	if err := gen.GenerateFile("domain/dto.go.tmpl", dtoPath, data); err != nil {
-		return types.DomainAnalysis{}, fmt.Errorf("failed to generate DTOs: %w", err)
+		return nil, fmt.Errorf("failed to generate DTOs: %w", err)
+	}
+
+	// Generate the realtime service decorator
+	if data.WithRealtime {
+		if err := gen.GenerateFile("realtime/service_events.go.tmpl", realtimeServicePath(domainInput.DomainName), data); err != nil {
+			return nil, fmt.Errorf("failed to generate realtime service: %w", err)
+		}
+	}
+
+	// Generate hook handlers
+	if len(data.Hooks) > 0 {
+		hooksPath := filepath.Join("internal", "services", pkgName, "hooks.go")
+		if err := gen.GenerateFile("domain/hooks.go.tmpl", hooksPath, data); err != nil {
+			return nil, fmt.Errorf("failed to generate hook handlers: %w", err)
+		}
 	}
 
 	// Generate controller
 	controllerPath := filepath.Join("internal", "web", pkgName, pkgName+".go")
 	if err := gen.GenerateFile("domain/controller.go.tmpl", controllerPath, data); err != nil {
-		return types.DomainAnalysis{}, fmt.Errorf("failed to generate controller: %w", err)
+		return nil, fmt.Errorf("failed to generate controller: %w", err)
+	}
+
+	// Generate the provider set of projects with di wire
+	if registry.UsesWire() {
+		providersPath := filepath.Join("internal", "web", pkgName, "providers.go")
+		if err := gen.GenerateFile("domain/providers.go.tmpl", providersPath, data); err != nil {
+			return nil, fmt.Errorf("failed to generate provider set: %w", err)
+		}
+	}
+
+	// Generate the route group and path of projects with the routes registry
+	if registry.UsesRouteRegistry() {
+		routesPath := filepath.Join("internal", "web", pkgName, "routes.go")
+		if err := gen.GenerateFile("domain/routes.go.tmpl", routesPath, data); err != nil {
+			return nil, fmt.Errorf("failed to generate routes: %w", err)
+		}
+	}
+
+	// Generate the TypeScript types of projects with scaffold_ts_types
+	if format := registry.TSTypesFormat(); format != "" {
+		if err := gen.GenerateFile(tsTypesFormats[format].domainTemplate, tsTypesPath(domainInput.DomainName, format), data); err != nil {
+			return nil, fmt.Errorf("failed to generate TypeScript types: %w", err)
+		}
 	}
 
 	// Generate CRUD views if requested
@@ -221,101 +364,22 @@ func analyzeSingleDomain(registry *Registry, metaStore *metadata.Store, domainNa
 		// Generate list view
 		listPath := filepath.Join(viewsDir, "list.templ")
 		if err := gen.GenerateFile("views/list.templ.tmpl", listPath, data); err != nil {
-			return types.DomainAnalysis{}, fmt.Errorf("failed to generate list view: %w", err)
+			return nil, fmt.Errorf("failed to generate list view: %w", err)
 		}
 
 		// Generate show view
 		showPath := filepath.Join(viewsDir, "show.templ")
 		if err := gen.GenerateFile("views/show.templ.tmpl", showPath, data); err != nil {
-			return types.DomainAnalysis{}, fmt.Errorf("failed to generate show view: %w", err)
+			return nil, fmt.Errorf("failed to generate show view: %w", err)
 		}
 
 		// Generate form view
 		formPath := filepath.Join(viewsDir, pkgName+"_form.templ")
 		if err := gen.GenerateFile("views/form.templ.tmpl", formPath, data); err != nil {
-			return types.DomainAnalysis{}, fmt.Errorf("failed to generate form view: %w", err)
+			return nil, fmt.Errorf("failed to generate form view: %w", err)
 		}
 	}
-
-	// Build layer filter
-	layerFilter := make(map[string]bool)
-	if len(layers) > 0 {
-		for _, l := range layers {
-			layerFilter[strings.ToLower(l)] = true
-		}
-	}
-
-	// Get result and compare files
-	result := gen.Result()
-	dmp := diffmatchpatch.New()
-
-	// Combine created and updated files for comparison
-	allFiles := append(result.FilesCreated, result.FilesUpdated...)
-
-	for _, filePath := range allFiles {
-		// Check layer filter
-		if len(layerFilter) > 0 && !matchesLayer(filePath, layerFilter) {
-			continue
-		}
-
-		fullPath := filepath.Join(registry.WorkingDir, filePath)
-		existingContent, err := os.ReadFile(fullPath)
-
-		var fileAnalysis types.FileAnalysis
-		fileAnalysis.Path = filePath
-
-		if err != nil {
-			if os.IsNotExist(err) {
-				fileAnalysis.Status = "missing"
-				fileAnalysis.Diff = "File does not exist (may have been deleted)"
-				analysis.HasChanges = true
-			} else {
-				fileAnalysis.Status = "error"
-				fileAnalysis.Diff = err.Error()
-			}
-		} else {
-			// Get generated content
-			generatedContent := gen.GetFileContent(filePath)
-			if generatedContent == "" {
-				continue // Skip if we can't get generated content
-			}
-
-			existingStr := string(existingContent)
-
-			if existingStr == generatedContent {
-				if showUnchanged {
-					fileAnalysis.Status = "unchanged"
-				} else {
-					continue // Skip unchanged files
-				}
-			} else {
-				fileAnalysis.Status = "modified"
-				analysis.HasChanges = true
-
-				// Generate unified diff
-				diffs := dmp.DiffMain(existingStr, generatedContent, true)
-				fileAnalysis.Diff = generateUnifiedDiff(filePath, diffs)
-
-				// Count changes
-				for _, d := range diffs {
-					lines := strings.Count(d.Text, "\n")
-					if lines == 0 && len(d.Text) > 0 {
-						lines = 1
-					}
-					switch d.Type {
-					case diffmatchpatch.DiffInsert:
-						fileAnalysis.LinesAdded += lines
-					case diffmatchpatch.DiffDelete:
-						fileAnalysis.LinesRemoved += lines
-					}
-				}
-			}
-		}
-
-		analysis.Files = append(analysis.Files, fileAnalysis)
-	}
-
-	return analysis, nil
+	return gen, nil
 }
 
 // matchesLayer checks if a file path matches the layer filter.
@@ -335,6 +399,9 @@ func matchesLayer(filePath string, layerFilter map[string]bool) bool {
 	if strings.Contains(filePath, "/views/") && layerFilter["views"] {
 		return true
 	}
+	if strings.HasPrefix(filepath.ToSlash(filePath), "web/src/types/") && layerFilter["types"] {
+		return true
+	}
 	return false
 }
 
@@ -0,0 +1,204 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/templates"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// projectFilesNotAnalyzed are project files analyze_project does not compare: their content
+// is expected to move away from the template.
+var projectFilesNotAnalyzed = map[string]string{
+	"go.mod":                "dependencies are added by go get and go mod tidy, and pinned by upgrade_stack",
+	"config/en/menu.toml":   "domains and manage_nav edit its links",
+	"assets/css/output.css": "built by the Tailwind CLI",
+}
+
+// RegisterAnalyzeProject registers the analyze_project tool.
+func RegisterAnalyzeProject(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "analyze_project",
+		Description: `Analyze the project-level files to detect differences from the current project templates.
+
+Where analyze_domain covers domains, this tool compares the files scaffold_project generated
+(cmd/web/main.go, the router, middleware, layouts, Taskfile.yml, config, and the auth files of
+projects with auth) against what the current templates would generate for the same options.
+
+Requirements:
+- The project must have been scaffolded with metadata tracking (scaffold_project saves its options
+  in .mcp/scaffold-metadata.json)
+
+Code that tools injected between MCP markers (e.g. // MCP:ROUTES:START ... // MCP:ROUTES:END)
+is kept out of the diff, as are marker sections tools added, so only template drift shows.
+go.mod, config/en/menu.toml and assets/css/output.css are not compared.
+
+Output is the same as analyze_domain: per-file status, a unified diff with numbered hunks and
+the template changelog entries since the project was scaffolded (changes).
+
+Examples:
+1. Analyze all project files:
+   analyze_project: {}
+
+2. Analyze main.go and the router:
+   analyze_project: { files: ["cmd/web/main.go", "internal/web/router.go"] }
+
+3. Include unchanged files:
+   analyze_project: { show_unchanged: true }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.AnalyzeProjectInput) (*mcp.CallToolResult, types.AnalyzeProjectResult, error) {
+		result, err := analyzeProject(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.AnalyzeProjectResult{Success: false, Message: err.Error()}, nil
+		}
+		return nil, result, nil
+	})
+}
+
+func analyzeProject(registry *Registry, input types.AnalyzeProjectInput) (types.AnalyzeProjectResult, error) {
+	project, err := metadata.NewStore(registry.WorkingDir).GetProject()
+	if err != nil {
+		return types.AnalyzeProjectResult{
+			Success: false,
+			Message: fmt.Sprintf("Failed to read project metadata: %v", err),
+		}, nil
+	}
+	if project == nil {
+		return types.AnalyzeProjectResult{
+			Success: false,
+			Message: "No project metadata found. The project may have been scaffolded before metadata tracking was added; analyze_project needs the scaffold_project options in .mcp/scaffold-metadata.json.",
+		}, nil
+	}
+
+	// Render the project with the options it was scaffolded with
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(true)
+	gen.SetStoreContent(true)
+	gen.SetForceOverwrite(true) // Allow "overwriting" to capture all files
+	if err := renderProject(gen, generator.NewProjectData(project.Input)); err != nil {
+		return types.AnalyzeProjectResult{Success: false, Message: err.Error()}, nil
+	}
+	changelog, err := templates.Changelog()
+	if err != nil {
+		return types.AnalyzeProjectResult{Success: false, Message: err.Error()}, nil
+	}
+	var paths []string
+	for _, path := range gen.ListGeneratedFiles() {
+		if _, skip := projectFilesNotAnalyzed[filepath.ToSlash(path)]; !skip {
+			paths = append(paths, path)
+		}
+	}
+
+	selected := map[string]bool{}
+	for _, f := range input.Files {
+		f = filepath.Clean(filepath.FromSlash(f))
+		if !slices.Contains(paths, f) {
+			if reason, ok := projectFilesNotAnalyzed[filepath.ToSlash(f)]; ok {
+				return types.AnalyzeProjectResult{
+					Success: false,
+					Message: fmt.Sprintf("%s is not analyzed: %s", filepath.ToSlash(f), reason),
+				}, nil
+			}
+			return types.AnalyzeProjectResult{
+				Success: false,
+				Message: fmt.Sprintf("'%s' is not a project file, which are: %s", filepath.ToSlash(f), strings.Join(paths, ", ")),
+			}, nil
+		}
+		selected[f] = true
+	}
+
+	result := types.AnalyzeProjectResult{
+		Success:           true,
+		ScaffoldedAt:      project.ScaffoldedAt.Format("2006-01-02 15:04:05"),
+		ScaffolderVersion: project.ScaffolderVersion,
+		CurrentVersion:    ScaffolderVersion,
+	}
+	changed := 0
+	for _, path := range paths {
+		if len(selected) > 0 && !selected[path] {
+			continue
+		}
+		generated := gen.GetFileContent(path)
+		existing, err := os.ReadFile(filepath.Join(registry.WorkingDir, path))
+		if err == nil {
+			generated = keepInjectedLines(string(existing), generated)
+		}
+		fileAnalysis, ok := compareFile(path, existing, err, generated, input.ShowUnchanged)
+		if !ok {
+			continue
+		}
+		if fileAnalysis.Status == "modified" || fileAnalysis.Status == "missing" {
+			changed++
+		}
+		if fileAnalysis.Status == "modified" {
+			fileAnalysis.Changes = templateChangesSince(changelog, project.ScaffolderVersion, gen.GetFileTemplate(path))
+		}
+		result.Files = append(result.Files, fileAnalysis)
+	}
+
+	analyzed := len(paths)
+	if len(selected) > 0 {
+		analyzed = len(selected)
+	}
+	result.HasChanges = changed > 0
+	result.Message = fmt.Sprintf("Analyzed %d project file(s)", analyzed)
+	if changed > 0 {
+		result.Message += fmt.Sprintf(", %d with changes available", changed)
+	} else {
+		result.Message += ", all up to date"
+	}
+	return result, nil
+}
+
+// markerLineRegex matches an MCP marker line, such as "// MCP:ROUTES:START".
+var markerLineRegex = regexp.MustCompile(`^[ \t]*//\s*MCP:([A-Z0-9_]+(?::[A-Z0-9_]+)*):(START|END)\s*$`)
+
+// keepInjectedLines returns the generated content with the lines tools injected into the
+// existing file carried over: the lines of the existing file inside marker sections, and the
+// marker sections tools added, such as MCP:HEALTH_CHECKS, that the template doesn't have.
+func keepInjectedLines(existing, generated string) string {
+	// Mark the lines of the existing file that are markers or inside a marker section
+	var inside []bool
+	var open []string
+	for _, line := range strings.SplitAfter(existing, "\n") {
+		m := markerLineRegex.FindStringSubmatch(strings.TrimSuffix(line, "\n"))
+		switch {
+		case m != nil && m[2] == "START":
+			open = append(open, m[1])
+			inside = append(inside, true)
+		case m != nil:
+			if i := slices.Index(open, m[1]); i >= 0 {
+				open = open[:i]
+			}
+			inside = append(inside, true)
+		default:
+			inside = append(inside, len(open) > 0)
+		}
+	}
+
+	var sb strings.Builder
+	oldLine := 0
+	for _, l := range lineDiff(existing, generated) {
+		switch l.op {
+		case '+':
+			sb.WriteString(l.text)
+		case '-':
+			if inside[oldLine] {
+				sb.WriteString(l.text)
+			}
+			oldLine++
+		default:
+			sb.WriteString(l.text)
+			oldLine++
+		}
+	}
+	return sb.String()
+}
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestAnalyzeProject(t *testing.T) {
+	mainGo := filepath.Join("cmd", "web", "main.go")
+
+	// setup scaffolds a project with a product domain wired into main.go and the router
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		project, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+			ProjectName:  "app",
+			ModulePath:   "github.com/test/app",
+			DatabaseType: "sqlite",
+			WithAuth:     true,
+			InCurrentDir: true,
+		})
+		if err != nil || !project.Success {
+			t.Fatalf("scaffoldProject failed: %v %s", err, project.Message)
+		}
+		domain, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+		})
+		if err != nil || !domain.Success {
+			t.Fatalf("scaffoldDomain failed: %v %s", err, domain.Message)
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("code injected between markers is not drift", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		// Tools also add marker sections of their own
+		injector, err := modifier.NewInjector(filepath.Join(tmpDir, mainGo))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := injector.InjectHealthHandler(); err != nil {
+			t.Fatal(err)
+		}
+		if err := injector.InjectHealthCheck("cache", "cache.Ping"); err != nil {
+			t.Fatal(err)
+		}
+		if err := injector.Save(); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := analyzeProject(registry, types.AnalyzeProjectInput{})
+		if err != nil || !result.Success {
+			t.Fatalf("analyzeProject failed: %v %s", err, result.Message)
+		}
+		if result.HasChanges {
+			for _, f := range result.Files {
+				t.Logf("%s: %s\n%s", f.Path, f.Status, f.Diff)
+			}
+			t.Fatalf("expected a freshly scaffolded project to be up to date, got: %s", result.Message)
+		}
+		if !strings.Contains(result.Message, "all up to date") {
+			t.Errorf("unexpected message: %s", result.Message)
+		}
+	})
+
+	t.Run("reports changes outside the markers", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		path := filepath.Join(tmpDir, mainGo)
+		writeTestFile(t, path, strings.Replace(readFile(t, path), "func main() {", "// custom comment\nfunc main() {", 1))
+
+		result, err := analyzeProject(registry, types.AnalyzeProjectInput{ShowUnchanged: true})
+		if err != nil || !result.Success {
+			t.Fatalf("analyzeProject failed: %v %s", err, result.Message)
+		}
+		var main *types.FileAnalysis
+		for i, f := range result.Files {
+			if f.Path == mainGo {
+				main = &result.Files[i]
+			}
+			if f.Path == "go.mod" {
+				t.Error("go.mod should not be analyzed")
+			}
+		}
+		if main == nil || main.Status != "modified" || main.Hunks != 1 || !strings.Contains(main.Diff, "-// custom comment") {
+			t.Fatalf("expected main.go modified by one hunk, got %+v", main)
+		}
+		if strings.Contains(main.Diff, "productController") {
+			t.Errorf("injected wiring should not be in the diff:\n%s", main.Diff)
+		}
+		if len(result.Files) < 10 {
+			t.Errorf("expected show_unchanged to list every project file, got %d", len(result.Files))
+		}
+	})
+
+	t.Run("filters and validates files", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		if err := os.Remove(filepath.Join(tmpDir, "Taskfile.yml")); err != nil {
+			t.Fatal(err)
+		}
+
+		result, _ := analyzeProject(registry, types.AnalyzeProjectInput{Files: []string{"Taskfile.yml"}})
+		if len(result.Files) != 1 || result.Files[0].Status != "missing" || !result.HasChanges {
+			t.Fatalf("expected Taskfile.yml reported missing, got %+v", result.Files)
+		}
+
+		for file, want := range map[string]string{
+			"go.mod":        "is not analyzed",
+			"internal/x.go": "is not a project file",
+		} {
+			result, _ := analyzeProject(registry, types.AnalyzeProjectInput{Files: []string{file}})
+			if result.Success || !strings.Contains(result.Message, want) {
+				t.Errorf("%s: expected an error containing %q, got: %s", file, want, result.Message)
+			}
+		}
+	})
+
+	t.Run("requires project metadata", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/test/app")
+
+		result, _ := analyzeProject(registry, types.AnalyzeProjectInput{})
+		if result.Success || !strings.Contains(result.Message, "No project metadata found") {
+			t.Errorf("expected a missing metadata error, got: %s", result.Message)
+		}
+	})
+}
@@ -0,0 +1,332 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// RegisterCheckTranslations registers the check_translations tool.
+func RegisterCheckTranslations(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "check_translations",
+		Description: `Compare the translations of a project with i18n (see scaffold_i18n).
+
+Extracts the keys used by the views and Go code, i18n.T(ctx, "key") and
+i18n.Translate(locale, "key") calls with a literal key, and the keys each locale defines in
+config/{locale}/messages/*.toml. Reports per locale:
+- missing: keys used by the code, or defined by the default locale, that the locale lacks.
+  They render in the default locale, or as the key when the default locale lacks them too
+- unused: keys no view or Go file uses. Keys built at runtime (i18n.T(ctx, "status."+s)) and
+  the shared common keys of the domain views are never reported
+
+Options:
+- locales: limit the report to these locales
+- fix: true to stub the missing keys into each locale's messages files: the file that defines
+  the key in the default locale, or {first key segment}.toml. Stubs hold the default locale's
+  text (or a label made from the key) for translators to replace; existing entries are kept
+- dry_run: true to preview the stubs as diffs without writing files
+
+Examples:
+  check_translations: {}
+  check_translations: { locales: ["es"], fix: true, dry_run: true }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.CheckTranslationsInput) (*mcp.CallToolResult, types.CheckTranslationsResult, error) {
+		call := registry.For(ctx)
+		// Fixes rewrite project files, so they hold the project lock like the scaffolding tools
+		if input.Fix && !input.DryRun {
+			unlock, _, _, err := call.lockProject()
+			if err != nil {
+				return nil, types.NewCheckTranslationsError(err.Error()), nil
+			}
+			defer unlock()
+		}
+		result, err := checkTranslations(call, input)
+		if err != nil {
+			return nil, types.NewCheckTranslationsError(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+// translationCallPattern matches the translation calls with a literal key.
+var translationCallPattern = regexp.MustCompile(`i18n\.(?:T|Translate)\(\s*[^,()]+,\s*"((?:[^"\\]|\\.)*)"`)
+
+// tomlStringPattern matches a bare key assigned a basic string in a messages file.
+var tomlStringPattern = regexp.MustCompile(`^\s*([A-Za-z0-9_\-]+)\s*=\s*("(?:[^"\\]|\\.)*")`)
+
+// localeCatalog holds the messages a locale defines.
+type localeCatalog struct {
+	// texts maps each key to its text.
+	texts map[string]string
+	// files maps each key to the messages file defining it (e.g., "product.toml").
+	files map[string]string
+}
+
+func checkTranslations(registry *Registry, input types.CheckTranslationsInput) (types.CheckTranslationsResult, error) {
+	defaultLocale := projectDefaultLocale(registry.WorkingDir)
+	if defaultLocale == "" {
+		return types.NewCheckTranslationsError("internal/i18n/i18n.go not found: run scaffold_i18n first"), nil
+	}
+
+	used, err := usedTranslationKeys(registry.WorkingDir)
+	if err != nil {
+		return types.NewCheckTranslationsError(fmt.Sprintf("failed to scan project: %v", err)), nil
+	}
+
+	catalogs, err := loadLocaleCatalogs(registry.WorkingDir)
+	if err != nil {
+		return types.NewCheckTranslationsError(fmt.Sprintf("failed to read messages: %v", err)), nil
+	}
+	if catalogs[defaultLocale] == nil {
+		catalogs[defaultLocale] = &localeCatalog{texts: map[string]string{}, files: map[string]string{}}
+	}
+
+	locales := []string{defaultLocale}
+	for locale := range catalogs {
+		if locale != defaultLocale {
+			locales = append(locales, locale)
+		}
+	}
+	sort.Strings(locales[1:])
+	if len(input.Locales) > 0 {
+		for _, locale := range input.Locales {
+			if catalogs[locale] == nil {
+				return types.NewCheckTranslationsError(fmt.Sprintf("locale '%s' has no config/%s/messages directory", locale, locale)), nil
+			}
+		}
+		locales = slices.DeleteFunc(locales, func(locale string) bool { return !slices.Contains(input.Locales, locale) })
+	}
+
+	// Every locale needs the keys the code uses and the keys of the default locale
+	defaults := catalogs[defaultLocale]
+	expected := map[string]bool{}
+	for key := range used {
+		expected[key] = true
+	}
+	for key := range defaults.texts {
+		expected[key] = true
+	}
+	common := map[string]bool{}
+	for _, entry := range commonMessageTexts {
+		common[entry.Key] = true
+	}
+
+	result := types.CheckTranslationsResult{Success: true, DryRun: input.DryRun, DefaultLocale: defaultLocale, UsedKeys: len(used)}
+	dmp := diffmatchpatch.New()
+	missingTotal, unusedTotal, stubbed := 0, 0, 0
+
+	for _, locale := range locales {
+		catalog := catalogs[locale]
+		report := types.LocaleTranslations{Locale: locale, Keys: len(catalog.texts)}
+		for key := range expected {
+			if _, ok := catalog.texts[key]; !ok {
+				report.Missing = append(report.Missing, key)
+			}
+		}
+		for key := range catalog.texts {
+			if !used[key] && !common[key] {
+				report.Unused = append(report.Unused, key)
+			}
+		}
+		sort.Strings(report.Missing)
+		sort.Strings(report.Unused)
+		missingTotal += len(report.Missing)
+		unusedTotal += len(report.Unused)
+
+		if input.Fix && len(report.Missing) > 0 {
+			// Group the stubs by the file that defines them in the default locale
+			stubs := map[string][]generator.MessageEntry{}
+			for _, key := range report.Missing {
+				file := defaults.files[key]
+				if file == "" {
+					file = strings.SplitN(key, ".", 2)[0] + ".toml"
+				}
+				text, ok := defaults.texts[key]
+				if !ok {
+					text = utils.ToLabel(key[strings.LastIndex(key, ".")+1:])
+				}
+				stubs[file] = append(stubs[file], generator.MessageEntry{Key: key, Text: text})
+			}
+			files := make([]string, 0, len(stubs))
+			for file := range stubs {
+				files = append(files, file)
+			}
+			sort.Strings(files)
+
+			for _, file := range files {
+				relPath := filepath.Join("config", locale, "messages", file)
+				fullPath := filepath.Join(registry.WorkingDir, relPath)
+				existing := ""
+				if content, err := os.ReadFile(fullPath); err == nil {
+					existing = string(content)
+				}
+				updated := mergeMessages(existing, stubs[file])
+				if updated == existing {
+					continue
+				}
+				report.Stubbed += len(stubs[file])
+
+				status := "modified"
+				if existing == "" {
+					status = "added"
+				}
+				chars1, chars2, lines := dmp.DiffLinesToChars(existing, updated)
+				diffs := dmp.DiffCharsToLines(dmp.DiffMain(chars1, chars2, false), lines)
+				analysis := types.FileAnalysis{
+					Path:   filepath.ToSlash(relPath),
+					Status: status,
+					Diff:   generateUnifiedDiff(filepath.ToSlash(relPath), diffs),
+				}
+				for _, d := range diffs {
+					switch d.Type {
+					case diffmatchpatch.DiffInsert:
+						analysis.LinesAdded += strings.Count(d.Text, "\n")
+					case diffmatchpatch.DiffDelete:
+						analysis.LinesRemoved += strings.Count(d.Text, "\n")
+					}
+				}
+				result.Files = append(result.Files, analysis)
+
+				if !input.DryRun {
+					if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+						return types.NewCheckTranslationsError(fmt.Sprintf("failed to create %s: %v", filepath.Dir(relPath), err)), nil
+					}
+					if err := os.WriteFile(fullPath, []byte(updated), 0644); err != nil {
+						return types.NewCheckTranslationsError(fmt.Sprintf("failed to write %s: %v", relPath, err)), nil
+					}
+				}
+			}
+			stubbed += report.Stubbed
+		}
+		result.Locales = append(result.Locales, report)
+	}
+
+	switch {
+	case missingTotal == 0 && unusedTotal == 0:
+		result.Message = fmt.Sprintf("All %d used keys are translated in %d locale(s)", len(used), len(locales))
+		return result, nil
+	case input.Fix:
+		verb := "Stubbed"
+		if input.DryRun {
+			verb = "Would stub"
+		}
+		result.Message = fmt.Sprintf("%s %d missing key(s) in %d file(s); %d unused key(s)", verb, stubbed, len(result.Files), unusedTotal)
+	default:
+		result.Message = fmt.Sprintf("Found %d missing and %d unused key(s) in %d locale(s)", missingTotal, unusedTotal, len(locales))
+	}
+
+	if missingTotal > 0 && !input.Fix {
+		result.NextSteps = append(result.NextSteps, "Run check_translations with fix: true (and dry_run: true to preview) to stub the missing keys")
+	}
+	if missingTotal > 0 || stubbed > 0 {
+		result.NextSteps = append(result.NextSteps, fmt.Sprintf("Translate the stubbed entries: they hold the %s text until they are replaced", defaultLocale))
+	}
+	if unusedTotal > 0 {
+		result.NextSteps = append(result.NextSteps, "Remove the unused keys from the messages files, unless the code builds them at runtime")
+	}
+	result.NextSteps = append(result.NextSteps, "Restart the application to load the changed messages")
+	return result, nil
+}
+
+// usedTranslationKeys returns the keys of the translation calls in the project's views and Go
+// files (generated *_templ.go files are skipped; their .templ sources are scanned).
+func usedTranslationKeys(dir string) (map[string]bool, error) {
+	keys := map[string]bool{}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && upgradeStackSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := d.Name()
+		if !(strings.HasSuffix(name, ".go") || strings.HasSuffix(name, ".templ")) || strings.HasSuffix(name, "_templ.go") {
+			return nil
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		for _, match := range translationCallPattern.FindAllStringSubmatch(string(content), -1) {
+			if key, err := strconv.Unquote(`"` + match[1] + `"`); err == nil {
+				keys[key] = true
+			}
+		}
+		return nil
+	})
+	return keys, err
+}
+
+// loadLocaleCatalogs reads the messages of every locale, config/{locale}/messages/*.toml.
+func loadLocaleCatalogs(dir string) (map[string]*localeCatalog, error) {
+	dirs, err := filepath.Glob(filepath.Join(dir, "config", "*", "messages"))
+	if err != nil {
+		return nil, err
+	}
+	catalogs := map[string]*localeCatalog{}
+	for _, messagesDir := range dirs {
+		if info, err := os.Stat(messagesDir); err != nil || !info.IsDir() {
+			continue
+		}
+		locale := filepath.Base(filepath.Dir(messagesDir))
+		catalog := &localeCatalog{texts: map[string]string{}, files: map[string]string{}}
+		files, err := filepath.Glob(filepath.Join(messagesDir, "*.toml"))
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			content, err := os.ReadFile(file)
+			if err != nil {
+				return nil, err
+			}
+			for _, entry := range readMessages(string(content)) {
+				catalog.texts[entry.Key] = entry.Text
+				catalog.files[entry.Key] = filepath.Base(file)
+			}
+		}
+		catalogs[locale] = catalog
+	}
+	return catalogs, nil
+}
+
+// readMessages returns the string entries of a messages file under their dotted keys.
+func readMessages(content string) []generator.MessageEntry {
+	var entries []generator.MessageEntry
+	table := ""
+	for _, line := range strings.Split(content, "\n") {
+		if match := tomlTablePattern.FindStringSubmatch(line); match != nil {
+			table = match[1]
+			continue
+		}
+		match := tomlStringPattern.FindStringSubmatch(line)
+		if match == nil {
+			continue
+		}
+		text, err := strconv.Unquote(match[2])
+		if err != nil {
+			continue
+		}
+		key := match[1]
+		if table != "" {
+			key = table + "." + key
+		}
+		entries = append(entries, generator.MessageEntry{Key: key, Text: text})
+	}
+	return entries
+}
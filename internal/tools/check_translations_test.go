@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestCheckTranslations(t *testing.T) {
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		files := map[string]string{
+			filepath.Join("internal", "i18n", "i18n.go"):                          "package i18n\n\nconst DefaultLocale = \"en\"\n",
+			filepath.Join("internal", "web", "product", "views", "list.templ"):    "<h1>{ i18n.T(ctx, \"product.title\") }</h1>\n<p>{ i18n.T(ctx, \"product.total\", props.TotalItems) }</p>\n<p>{ i18n.T(ctx, \"product.new_key\") }</p>\n",
+			filepath.Join("internal", "web", "product", "views", "list_templ.go"): "i18n.T(ctx, \"generated.key\")\n",
+			filepath.Join("internal", "web", "product", "product.go"):             "msg := i18n.Translate(locale, \"product.saved\")\n",
+			filepath.Join("config", "en", "messages", "product.toml"):             "[product]\ntitle = \"Products\"\ntotal = \"%d total\"\nsaved = \"Saved\"\nold = \"Old\"\n",
+			filepath.Join("config", "en", "messages", "common.toml"):              "[common]\nsave = \"Save\"\n",
+			filepath.Join("config", "es", "messages", "product.toml"):             "# Spanish\n[product]\ntitle = \"Productos\"\n",
+		}
+		for path, content := range files {
+			fullPath := filepath.Join(tmpDir, path)
+			if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+				t.Fatalf("failed to create %s dir: %v", path, err)
+			}
+			if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+				t.Fatalf("failed to write %s: %v", path, err)
+			}
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("reports missing and unused keys", func(t *testing.T) {
+		registry, _ := setup(t)
+
+		result, err := checkTranslations(registry, types.CheckTranslationsInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+		if result.UsedKeys != 4 {
+			t.Errorf("expected 4 used keys, got %d", result.UsedKeys)
+		}
+		if len(result.Locales) != 2 || result.Locales[0].Locale != "en" || result.Locales[1].Locale != "es" {
+			t.Fatalf("expected the en and es reports, got %+v", result.Locales)
+		}
+
+		en, es := result.Locales[0], result.Locales[1]
+		if !slices.Equal(en.Missing, []string{"product.new_key"}) {
+			t.Errorf("en missing = %v", en.Missing)
+		}
+		if !slices.Equal(en.Unused, []string{"product.old"}) {
+			t.Errorf("en unused = %v (common keys should not be reported)", en.Unused)
+		}
+		if want := []string{"common.save", "product.new_key", "product.old", "product.saved", "product.total"}; !slices.Equal(es.Missing, want) {
+			t.Errorf("es missing = %v, want %v", es.Missing, want)
+		}
+		if len(result.Files) != 0 {
+			t.Error("expected no files changed without fix")
+		}
+	})
+
+	t.Run("fix stubs the missing keys", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		preview, err := checkTranslations(registry, types.CheckTranslationsInput{Locales: []string{"es"}, Fix: true, DryRun: true})
+		if err != nil || !preview.Success {
+			t.Fatalf("unexpected failure: %v %s", err, preview.Message)
+		}
+		if len(preview.Files) != 2 || preview.Locales[0].Stubbed != 5 {
+			t.Fatalf("expected 5 stubs in 2 files, got %d in %+v", preview.Locales[0].Stubbed, preview.Files)
+		}
+		if fileExists(filepath.Join(tmpDir, "config", "es", "messages", "common.toml")) {
+			t.Fatal("expected the dry run to write nothing")
+		}
+
+		result, err := checkTranslations(registry, types.CheckTranslationsInput{Locales: []string{"es"}, Fix: true})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		product := readFile(t, filepath.Join(tmpDir, "config", "es", "messages", "product.toml"))
+		want := "# Spanish\n[product]\ntitle = \"Productos\"\nnew_key = \"New Key\"\nold = \"Old\"\nsaved = \"Saved\"\ntotal = \"%d total\"\n"
+		if product != want {
+			t.Errorf("es/product.toml =\n%s\nwant\n%s", product, want)
+		}
+		if common := readFile(t, filepath.Join(tmpDir, "config", "es", "messages", "common.toml")); common != "[common]\nsave = \"Save\"\n" {
+			t.Errorf("es/common.toml =\n%s", common)
+		}
+
+		again, err := checkTranslations(registry, types.CheckTranslationsInput{Locales: []string{"es"}})
+		if err != nil || !again.Success {
+			t.Fatalf("unexpected failure: %v %s", err, again.Message)
+		}
+		if len(again.Locales[0].Missing) != 0 {
+			t.Errorf("expected nothing missing after the fix, got %v", again.Locales[0].Missing)
+		}
+	})
+
+	t.Run("requires i18n", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		if err := os.Remove(filepath.Join(tmpDir, "internal", "i18n", "i18n.go")); err != nil {
+			t.Fatal(err)
+		}
+		result, err := checkTranslations(registry, types.CheckTranslationsInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || !strings.Contains(result.Message, "scaffold_i18n") {
+			t.Errorf("expected a failure pointing to scaffold_i18n, got %q", result.Message)
+		}
+	})
+
+	t.Run("rejects unknown locales", func(t *testing.T) {
+		registry, _ := setup(t)
+		result, err := checkTranslations(registry, types.CheckTranslationsInput{Locales: []string{"fr"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure for a locale without messages")
+		}
+	})
+}
@@ -0,0 +1,48 @@
+package tools
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+// CommandTools returns the tools the gomcp command line can run, sorted. They are the
+// tools of scaffold jobs: the tools that write files and return a ScaffoldResult.
+func CommandTools() []string {
+	return jobToolNames()
+}
+
+// ToolInputType returns the input struct type of a command line tool.
+func ToolInputType(tool string) (reflect.Type, bool) {
+	t, ok := jobTools[tool]
+	if !ok {
+		return nil, false
+	}
+	return t.input, true
+}
+
+// Run runs a tool outside an MCP session with its input as JSON, calling the same
+// implementation as the MCP tool. Unknown input fields are rejected.
+func (r *Registry) Run(tool string, input []byte) (types.ScaffoldResult, error) {
+	t, ok := jobTools[tool]
+	if !ok {
+		return types.ScaffoldResult{}, fmt.Errorf("unknown tool '%s'", tool)
+	}
+	call, err := t.decode(input)
+	if err != nil {
+		return types.ScaffoldResult{}, fmt.Errorf("invalid input for %s: %w", tool, err)
+	}
+	return call(r)
+}
+
+// ListDomains lists the domains scaffolded in the project, like the list_domains tool.
+func (r *Registry) ListDomains() (types.ListDomainsResult, error) {
+	return listDomains(r)
+}
+
+// RenderPreview renders templates with an input without writing files, like the
+// render_preview tool.
+func (r *Registry) RenderPreview(input types.RenderPreviewInput) (types.RenderPreviewResult, error) {
+	return renderPreview(r, input)
+}
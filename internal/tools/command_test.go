@@ -0,0 +1,65 @@
+package tools
+
+import (
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+)
+
+func TestCommandTools(t *testing.T) {
+	tools := CommandTools()
+	if !slices.IsSorted(tools) {
+		t.Errorf("expected sorted tools, got %v", tools)
+	}
+	if !slices.Contains(tools, "scaffold_domain") || slices.Contains(tools, "list_domains") {
+		t.Errorf("expected the write tools only, got %v", tools)
+	}
+
+	input, ok := ToolInputType("scaffold_domain")
+	if !ok || input != reflect.TypeFor[types.ScaffoldDomainInput]() {
+		t.Errorf("expected ScaffoldDomainInput, got %v", input)
+	}
+	if _, ok := ToolInputType("list_domains"); ok {
+		t.Error("expected no input type for a read-only tool")
+	}
+}
+
+func TestRegistryRun(t *testing.T) {
+	registry, tmpDir := testRegistry(t)
+	setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+	result, err := registry.Run("scaffold_domain", []byte(`{"domain_name": "product", "fields": [{"name": "Name", "type": "string"}]}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !result.Success {
+		t.Fatalf("expected success, got: %s", result.Message)
+	}
+	if !utils.FileExists(filepath.Join(tmpDir, "internal", "models", "product.go")) {
+		t.Error("expected the model to be generated")
+	}
+
+	tests := []struct {
+		name    string
+		tool    string
+		input   string
+		wantErr string
+	}{
+		{"unknown tool", "list_domains", `{}`, "unknown tool 'list_domains'"},
+		{"unknown field", "scaffold_domain", `{"domain": "product"}`, `invalid input for scaffold_domain: json: unknown field "domain"`},
+		{"wrong type", "scaffold_domain", `{"domain_name": 42}`, "invalid input for scaffold_domain"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := registry.Run(tt.tool, []byte(tt.input))
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("expected error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
@@ -7,6 +7,7 @@ import (
 	"testing"
 
 	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
 )
 
 // Edge case tests verify behavior with unusual inputs and boundary conditions.
@@ -502,6 +503,50 @@ func TestEdgeCases_ConcurrentScaffolding(t *testing.T) {
 			}
 		}
 	})
+
+	t.Run("concurrent domain scaffolding keeps shared files intact", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		project, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+			ProjectName:  "app",
+			ModulePath:   "github.com/test/app",
+			DatabaseType: "sqlite",
+			InCurrentDir: true,
+		})
+		if err != nil || !project.Success {
+			t.Fatalf("scaffoldProject failed: %v %s", err, project.Message)
+		}
+
+		domains := []string{"product", "order", "category", "tag", "invoice"}
+		results := make(chan types.ScaffoldResult, len(domains))
+		for _, domain := range domains {
+			go func(d string) {
+				result, err := locked(scaffoldDomain)(registry, types.ScaffoldDomainInput{
+					DomainName: d,
+					Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+				})
+				if err != nil {
+					result = types.NewErrorResult(err.Error())
+				}
+				results <- result
+			}(domain)
+		}
+		for range domains {
+			if result := <-results; !result.Success {
+				t.Errorf("concurrent scaffolding failed: %s", result.Message)
+			}
+		}
+
+		mainGo := readFile(t, filepath.Join(tmpDir, "cmd/web/main.go"))
+		database := readFile(t, filepath.Join(tmpDir, "internal/database/database.go"))
+		for _, domain := range domains {
+			if !strings.Contains(mainGo, domain+"Controller") {
+				t.Errorf("main.go lost the wiring of %s", domain)
+			}
+			if model := "models." + utils.ToModelName(domain) + "{}"; !strings.Contains(database, model) {
+				t.Errorf("database.go lost the migration of %s", model)
+			}
+		}
+	})
 }
 
 func TestWizardEdgeCases(t *testing.T) {
@@ -0,0 +1,197 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// DomainExportVersion is the current export_domain file format version.
+const DomainExportVersion = "1"
+
+// extensionSections are the marker sections that hold code added after scaffold_domain.
+var extensionSections = []struct {
+	layer   string
+	dir     string
+	section string
+}{
+	{"repository", "repository", "REPO_INTERFACE"},
+	{"repository", "repository", "REPO_METHODS"},
+	{"service", "services", "SERVICE_INTERFACE"},
+	{"service", "services", "SERVICE_METHODS"},
+	{"controller", "web", "SORT_COLUMNS"},
+	{"controller", "web", "ROUTES"},
+	{"controller", "web", "HANDLERS"},
+}
+
+// extensionFilePath returns the domain file that holds the given layer's extensions.
+func extensionFilePath(layer, pkgName string) (string, error) {
+	for _, s := range extensionSections {
+		if s.layer == layer {
+			return filepath.Join("internal", s.dir, pkgName, pkgName+".go"), nil
+		}
+	}
+	return "", fmt.Errorf("unknown extension layer '%s'", layer)
+}
+
+// RegisterExportDomain registers the export_domain tool.
+func RegisterExportDomain(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "export_domain",
+		Description: `Export a scaffolded domain so it can be re-created in another project with import_domain.
+
+The export contains the scaffold_domain input recorded in .mcp/scaffold-metadata.json and, optionally:
+- with_extensions: custom code between the domain's injection markers (methods added with
+  extend_repository, extend_service and extend_controller, and scaffold_table sort columns)
+- with_wizards: the scaffold_wizard inputs of the domain's wizards
+
+The export is returned in the result. Set output_path to also write it to a JSON file, e.g. to keep
+a library of reusable domains next to your projects. project_path and output_path are relative to
+the server's working directory; project_path defaults to the working directory itself.
+
+Example:
+  export_domain: { domain: "product", project_path: "shop", output_path: "domain-library/product.json", with_extensions: true }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ExportDomainInput) (*mcp.CallToolResult, types.ExportDomainResult, error) {
+		result, err := exportDomain(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewExportDomainError(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func exportDomain(registry *Registry, input types.ExportDomainInput) (types.ExportDomainResult, error) {
+	if err := utils.ValidateDomainName(input.Domain); err != nil {
+		return types.NewExportDomainError(err.Error()), nil
+	}
+
+	projectDir, err := resolveWorkingPath(registry.WorkingDir, input.ProjectPath)
+	if err != nil {
+		return types.NewExportDomainError(fmt.Sprintf("invalid project_path: %v", err)), nil
+	}
+
+	meta, err := metadata.NewStore(projectDir).Load()
+	if err != nil {
+		return types.NewExportDomainError(fmt.Sprintf("failed to read scaffold metadata: %v", err)), nil
+	}
+	domain, ok := meta.Domains[input.Domain]
+	if !ok {
+		return types.NewExportDomainError(fmt.Sprintf("no scaffold metadata for domain '%s' in %s", input.Domain, projectDir)), nil
+	}
+
+	export := types.DomainExport{
+		Version:           DomainExportVersion,
+		ScaffolderVersion: domain.ScaffolderVersion,
+		ExportedAt:        time.Now().UTC(),
+		Domain:            domain.Input,
+	}
+	// Dry runs are never recorded, but keep the export replayable regardless
+	export.Domain.DryRun = false
+
+	if input.WithWizards {
+		keys := make([]string, 0, len(meta.Wizards))
+		for key, wizard := range meta.Wizards {
+			if wizard.Domain == input.Domain {
+				keys = append(keys, key)
+			}
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			wizard := meta.Wizards[key].Input
+			wizard.DryRun = false
+			export.Wizards = append(export.Wizards, wizard)
+		}
+	}
+
+	if input.WithExtensions {
+		extensions, err := readDomainExtensions(projectDir, utils.ToPackageName(input.Domain))
+		if err != nil {
+			return types.NewExportDomainError(fmt.Sprintf("failed to read extensions: %v", err)), nil
+		}
+		export.Extensions = extensions
+	}
+
+	result := types.ExportDomainResult{
+		Success: true,
+		Message: fmt.Sprintf("Exported domain '%s' (%d wizard(s), %d extension section(s))", input.Domain, len(export.Wizards), len(export.Extensions)),
+		Export:  &export,
+	}
+
+	if input.OutputPath != "" {
+		outputPath, err := resolveWorkingPath(registry.WorkingDir, input.OutputPath)
+		if err != nil {
+			return types.NewExportDomainError(fmt.Sprintf("invalid output_path: %v", err)), nil
+		}
+		data, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			return types.NewExportDomainError(fmt.Sprintf("failed to marshal export: %v", err)), nil
+		}
+		if err := utils.EnsureDir(filepath.Dir(outputPath)); err != nil {
+			return types.NewExportDomainError(fmt.Sprintf("failed to create directory: %v", err)), nil
+		}
+		if err := utils.WriteFileString(outputPath, string(data)+"\n", true); err != nil {
+			return types.NewExportDomainError(fmt.Sprintf("failed to write export: %v", err)), nil
+		}
+		result.FilesCreated = []string{input.OutputPath}
+		result.Message += " to " + input.OutputPath
+	}
+
+	return result, nil
+}
+
+// readDomainExtensions collects the non-empty extension sections of a domain's files.
+// Files and markers that don't exist (e.g., a domain without a controller) are skipped.
+func readDomainExtensions(projectDir, pkgName string) ([]types.DomainExtension, error) {
+	var extensions []types.DomainExtension
+	for _, s := range extensionSections {
+		path := filepath.Join(projectDir, "internal", s.dir, pkgName, pkgName+".go")
+		if !utils.FileExists(path) {
+			continue
+		}
+		injector, err := modifier.NewInjector(path)
+		if err != nil {
+			return nil, err
+		}
+		startMarker, endMarker := "MCP:"+s.section+":START", "MCP:"+s.section+":END"
+		if !injector.HasMarker(startMarker) {
+			continue
+		}
+		code, err := injector.MarkerContent(startMarker, endMarker)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		if code == "" {
+			continue
+		}
+		extensions = append(extensions, types.DomainExtension{
+			Layer:   s.layer,
+			Section: s.section,
+			Code:    code,
+		})
+	}
+	return extensions, nil
+}
+
+// resolveWorkingPath resolves a path relative to the working directory. Empty paths
+// resolve to the working directory; paths outside it are rejected.
+func resolveWorkingPath(workingDir, path string) (string, error) {
+	if filepath.IsAbs(path) {
+		return "", fmt.Errorf("%s must be relative to the working directory", path)
+	}
+	full := filepath.Join(workingDir, path)
+	rel, err := filepath.Rel(workingDir, full)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%s is outside the working directory", path)
+	}
+	return full, nil
+}
@@ -0,0 +1,177 @@
+package tools
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+// setupExportProject scaffolds an extended product domain with a wizard in dir.
+func setupExportProject(t *testing.T, dir string) {
+	t.Helper()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	setupGoMod(t, dir, "github.com/example/shop")
+	registry := NewRegistry(dir)
+
+	result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+		DomainName: "product",
+		Fields: []types.FieldDef{
+			{Name: "Name", Type: "string"},
+			{Name: "SKU", Type: "string"},
+		},
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("scaffold_domain failed: %v %s", err, result.Message)
+	}
+
+	// Custom repository method, as added by extend_repository
+	injector, err := modifier.NewInjector(filepath.Join(dir, "internal", "repository", "product", "product.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := injector.InjectBetweenMarkers("MCP:REPO_INTERFACE:START", "MCP:REPO_INTERFACE:END", "FindBySKU(ctx context.Context, sku string) (*models.Product, error)"); err != nil {
+		t.Fatal(err)
+	}
+	method := "func (r *repository) FindBySKU(ctx context.Context, sku string) (*models.Product, error) {\n\tvar product models.Product\n\treturn &product, r.db.WithContext(ctx).Where(\"sku = ?\", sku).First(&product).Error\n}"
+	if err := injector.InjectBetweenMarkers("MCP:REPO_METHODS:START", "MCP:REPO_METHODS:END", method); err != nil {
+		t.Fatal(err)
+	}
+	if err := injector.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err = scaffoldWizard(registry, types.ScaffoldWizardInput{
+		WizardName: "create",
+		Domain:     "product",
+		Steps: []types.WizardStepDef{
+			{Name: "Details", Type: "form", Fields: []string{"name", "sku"}},
+			{Name: "Review", Type: "summary"},
+		},
+	})
+	if err != nil || !result.Success {
+		t.Fatalf("scaffold_wizard failed: %v %s", err, result.Message)
+	}
+}
+
+func TestExportDomain(t *testing.T) {
+	t.Run("validates domain name", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+
+		result, err := exportDomain(registry, types.ExportDomainInput{Domain: ""})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure for empty domain")
+		}
+	})
+
+	t.Run("requires scaffold metadata", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/shop")
+
+		result, err := exportDomain(registry, types.ExportDomainInput{Domain: "product"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure for a domain without metadata")
+		}
+		if !strings.Contains(result.Message, "no scaffold metadata") {
+			t.Errorf("expected error about metadata, got %q", result.Message)
+		}
+	})
+
+	t.Run("rejects paths outside the working directory", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupExportProject(t, filepath.Join(tmpDir, "shop"))
+
+		tests := []types.ExportDomainInput{
+			{Domain: "product", ProjectPath: "../shop"},
+			{Domain: "product", ProjectPath: "/tmp"},
+			{Domain: "product", ProjectPath: "shop", OutputPath: "../product.json"},
+		}
+		for _, input := range tests {
+			result, err := exportDomain(registry, input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success {
+				t.Errorf("expected failure for %+v", input)
+			}
+		}
+	})
+
+	t.Run("exports metadata only by default", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupExportProject(t, filepath.Join(tmpDir, "shop"))
+
+		result, err := exportDomain(registry, types.ExportDomainInput{Domain: "product", ProjectPath: "shop"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+		if result.Export.Domain.DomainName != "product" || len(result.Export.Domain.Fields) != 2 {
+			t.Errorf("expected the scaffold_domain input, got %+v", result.Export.Domain)
+		}
+		if len(result.Export.Wizards) != 0 || len(result.Export.Extensions) != 0 {
+			t.Error("expected no wizards or extensions without with_wizards and with_extensions")
+		}
+		if len(result.FilesCreated) != 0 {
+			t.Error("expected no file without output_path")
+		}
+	})
+
+	t.Run("exports wizards and extensions to a file", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupExportProject(t, filepath.Join(tmpDir, "shop"))
+
+		result, err := exportDomain(registry, types.ExportDomainInput{
+			Domain:         "product",
+			ProjectPath:    "shop",
+			OutputPath:     filepath.Join("library", "product.json"),
+			WithExtensions: true,
+			WithWizards:    true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		var export types.DomainExport
+		if err := json.Unmarshal([]byte(readFile(t, filepath.Join(tmpDir, "library", "product.json"))), &export); err != nil {
+			t.Fatalf("failed to parse export file: %v", err)
+		}
+		if export.Version != DomainExportVersion {
+			t.Errorf("expected version %s, got %s", DomainExportVersion, export.Version)
+		}
+		if len(export.Wizards) != 1 || export.Wizards[0].WizardName != "create" {
+			t.Errorf("expected the create wizard, got %+v", export.Wizards)
+		}
+
+		sections := make(map[string]string)
+		for _, ext := range export.Extensions {
+			sections[ext.Section] = ext.Code
+		}
+		if !strings.HasPrefix(sections["REPO_INTERFACE"], "FindBySKU(ctx context.Context, sku string)") {
+			t.Errorf("expected unindented interface method, got %q", sections["REPO_INTERFACE"])
+		}
+		if !strings.Contains(sections["REPO_METHODS"], "func (r *repository) FindBySKU(") {
+			t.Errorf("expected method implementation, got %q", sections["REPO_METHODS"])
+		}
+		if _, ok := sections["SERVICE_METHODS"]; ok {
+			t.Error("expected empty sections to be skipped")
+		}
+	})
+}
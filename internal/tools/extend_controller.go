@@ -73,7 +73,7 @@ Examples:
      ]
    }`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ExtendControllerInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := extendController(registry, input)
+		result, err := locked(extendController)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -0,0 +1,274 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go/token"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// modelHookEvents describes when each GORM hook runs.
+var modelHookEvents = map[string]string{
+	"BeforeSave":   "before %s is saved",
+	"BeforeCreate": "before %s is created",
+	"AfterCreate":  "after %s is created",
+	"BeforeUpdate": "before %s is updated",
+	"AfterUpdate":  "after %s is updated",
+	"AfterSave":    "after %s is saved",
+	"BeforeDelete": "before %s is deleted",
+	"AfterDelete":  "after %s is deleted",
+	"AfterFind":    "after %s is loaded",
+}
+
+// RegisterExtendModel registers the extend_model tool.
+func RegisterExtendModel(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "extend_model",
+		Description: `Add GORM hooks, methods or a table name override to an existing model.
+
+Hooks and methods are added between the MCP:MODEL_HOOKS and MCP:MODEL_METHODS markers of the
+model, so analyze_domain and sync_domain keep them when the domain is regenerated. Models
+scaffolded before the markers existed get them appended.
+
+Hooks: BeforeSave, BeforeCreate, AfterCreate, BeforeUpdate, AfterUpdate, AfterSave,
+BeforeDelete, AfterDelete, AfterFind. A hook receives the model as m and the transaction as tx,
+and returns an error to abort the operation. Leave the body empty for a TODO placeholder.
+
+Methods get the model as m. Unlike extend_service, no context parameter is added.
+
+table_name rewrites the TableName method and records the override in the domain metadata, so
+regenerated files use it. Migrating the existing table is up to you.
+
+Template variables available in body:
+- [[.ModelName]]: The model name in PascalCase (e.g., "Order")
+- [[.VariableName]]: The variable name in camelCase (e.g., "order")
+- [[.PackageName]]: The package name (e.g., "order")
+
+Examples:
+
+1. Normalize an email before saving:
+   extend_model: {
+     domain: "customer",
+     hooks: [
+       {name: "BeforeSave", body: "\tm.Email = strings.ToLower(m.Email)\n\treturn nil"}
+     ]
+   }
+
+2. Add a computed method:
+   extend_model: {
+     domain: "customer",
+     methods: [
+       {name: "DisplayName", returns: "string", body: "\treturn m.FirstName + \" \" + m.LastName"}
+     ]
+   }
+
+3. Use a legacy table:
+   extend_model: {domain: "customer", table_name: "tbl_customers"}`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ExtendModelInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(extendModel)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+// extendModel adds hooks and methods to an existing model and overrides its table name.
+func extendModel(registry *Registry, input types.ExtendModelInput) (types.ScaffoldResult, error) {
+	if input.Domain == "" {
+		return types.NewErrorResult("domain is required"), nil
+	}
+	if len(input.Hooks) == 0 && len(input.Methods) == 0 && input.TableName == "" {
+		return types.NewErrorResult("at least one hook, method or table_name is required"), nil
+	}
+	if input.TableName != "" {
+		if err := utils.ValidateTableName(input.TableName); err != nil {
+			return types.NewErrorResult(err.Error()), nil
+		}
+	}
+
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+	gen.SetForceOverwrite(true)
+
+	// Derive names from domain
+	packageName := utils.ToPackageName(input.Domain)
+	modelName := utils.ToModelName(input.Domain)
+	variableName := utils.ToVariableName(input.Domain)
+
+	modelPath := filepath.Join("internal", "models", packageName+".go")
+	if !gen.FileExists(modelPath) {
+		return types.NewErrorResult(fmt.Sprintf("model file not found: %s. Use scaffold_domain first.", modelPath)), nil
+	}
+	content, err := gen.ReadFile(modelPath)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to read model file: %v", err)), nil
+	}
+
+	// Every hook and method needs a name the model doesn't have yet
+	var names []string
+	for _, hook := range input.Hooks {
+		if _, ok := modelHookEvents[hook.Name]; !ok {
+			return types.NewErrorResult(fmt.Sprintf("unknown hook '%s': use one of BeforeSave, BeforeCreate, AfterCreate, BeforeUpdate, AfterUpdate, AfterSave, BeforeDelete, AfterDelete, AfterFind", hook.Name)), nil
+		}
+		names = append(names, hook.Name)
+	}
+	for _, method := range input.Methods {
+		if !token.IsIdentifier(method.Name) || !token.IsExported(method.Name) {
+			return types.NewErrorResult(fmt.Sprintf("method name '%s' must be an exported Go identifier", method.Name)), nil
+		}
+		names = append(names, method.Name)
+	}
+	for i, name := range names {
+		if slices.Contains(names[:i], name) {
+			return types.NewErrorResult(fmt.Sprintf("%s is given more than once", name)), nil
+		}
+		if modelMethodRegex(modelName, name).MatchString(content) {
+			return types.NewErrorResult(fmt.Sprintf("%s already has a %s method", modelName, name)), nil
+		}
+	}
+
+	replaceVars := func(body string) string {
+		body = strings.ReplaceAll(body, "[[.ModelName]]", modelName)
+		body = strings.ReplaceAll(body, "[[.VariableName]]", variableName)
+		return strings.ReplaceAll(body, "[[.PackageName]]", packageName)
+	}
+
+	var hookImpls []string
+	for _, hook := range input.Hooks {
+		body := hook.Body
+		if body == "" {
+			body = "\t// TODO: Implement this hook\n\treturn nil"
+		}
+		hookImpls = append(hookImpls, fmt.Sprintf(`
+// %s runs %s.
+func (m *%s) %s(tx *gorm.DB) error {
+%s
+}`, hook.Name, fmt.Sprintf(modelHookEvents[hook.Name], "the "+modelName), modelName, hook.Name, replaceVars(body)))
+	}
+
+	var methodImpls []string
+	for _, method := range input.Methods {
+		var params []string
+		for _, p := range method.Params {
+			params = append(params, fmt.Sprintf("%s %s", p.Name, p.Type))
+		}
+
+		// Build return type
+		returnType := method.Returns
+		if returnType == "" {
+			returnType = "error"
+		}
+		// Wrap multiple returns in parentheses if not already
+		if strings.Contains(returnType, ",") && !strings.HasPrefix(returnType, "(") {
+			returnType = "(" + returnType + ")"
+		}
+
+		description := method.Description
+		if description == "" {
+			description = fmt.Sprintf("%s is a custom model method.", method.Name)
+		}
+		body := method.Body
+		if body == "" {
+			body = "\t// TODO: Implement this method\n\treturn nil"
+		}
+		methodImpls = append(methodImpls, fmt.Sprintf(`
+// %s %s
+func (m *%s) %s(%s) %s {
+%s
+}`, method.Name, description, modelName, method.Name, strings.Join(params, ", "), returnType, replaceVars(body)))
+	}
+
+	injector := modifier.NewInjectorFromContent(content)
+
+	// Models scaffolded before the markers existed get them at the end
+	if !injector.HasMarker("MCP:MODEL_HOOKS:START") && !injector.HasMarker("MCP:MODEL_METHODS:START") {
+		content = strings.TrimRight(content, "\n") + "\n\n// MCP:MODEL_HOOKS:START\n// MCP:MODEL_HOOKS:END\n\n// MCP:MODEL_METHODS:START\n// MCP:MODEL_METHODS:END\n"
+		injector = modifier.NewInjectorFromContent(content)
+	}
+
+	if len(hookImpls) > 0 {
+		if err := injector.InjectBetweenMarkers("MCP:MODEL_HOOKS:START", "MCP:MODEL_HOOKS:END", strings.Join(hookImpls, "\n")); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to inject hooks: %v", err)), nil
+		}
+		if err := injector.InjectImport("gorm.io/gorm"); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to import gorm: %v", err)), nil
+		}
+	}
+	if len(methodImpls) > 0 {
+		if err := injector.InjectBetweenMarkers("MCP:MODEL_METHODS:START", "MCP:MODEL_METHODS:END", strings.Join(methodImpls, "\n")); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to inject methods: %v", err)), nil
+		}
+	}
+
+	var nextSteps []string
+	var suggestedTools []types.ToolHint
+	updated := injector.Content()
+	if input.TableName != "" {
+		tableNameRegex := regexp.MustCompile(`(func \(\w*\s*\*?` + modelName + `\) TableName\(\) string \{\s*return )"[^"]*"`)
+		if !tableNameRegex.MatchString(updated) {
+			return types.NewErrorResult(fmt.Sprintf("%s has no TableName method returning a string literal", modelName)), nil
+		}
+		updated = tableNameRegex.ReplaceAllString(updated, `${1}"`+input.TableName+`"`)
+		nextSteps = append(nextSteps, fmt.Sprintf("Rename or migrate the existing table to %s", input.TableName))
+		suggestedTools = append(suggestedTools, types.ToolHint{
+			Tool:        "analyze_domain",
+			Description: fmt.Sprintf("Find the other %s files, such as full-text search, that name the table", input.Domain),
+			Priority:    "recommended",
+		})
+	}
+
+	if err := gen.WriteFile(modelPath, updated); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to write model file: %v", err)), nil
+	}
+
+	// Record the table so regenerated files use it
+	if input.TableName != "" && !input.DryRun {
+		store := metadata.NewStore(registry.WorkingDir)
+		domainMeta, exists, err := store.GetDomain(input.Domain)
+		if err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to read metadata: %v", err)), nil
+		}
+		if exists {
+			domainMeta.Input.TableName = input.TableName
+			if err := store.UpdateDomainInput(input.Domain, domainMeta.Input); err != nil {
+				return types.NewErrorResult(fmt.Sprintf("failed to save metadata: %v", err)), nil
+			}
+		}
+	}
+
+	nextSteps = append(nextSteps, "go build ./...")
+
+	var added []string
+	if len(input.Hooks) > 0 {
+		added = append(added, fmt.Sprintf("%d hook(s)", len(input.Hooks)))
+	}
+	if len(input.Methods) > 0 {
+		added = append(added, fmt.Sprintf("%d method(s)", len(input.Methods)))
+	}
+	if input.TableName != "" {
+		added = append(added, fmt.Sprintf("table name %s", input.TableName))
+	}
+
+	return types.ScaffoldResult{
+		Success:        true,
+		Message:        fmt.Sprintf("Added %s to %s model", strings.Join(added, ", "), modelName),
+		FilesUpdated:   []string{modelPath},
+		NextSteps:      nextSteps,
+		SuggestedTools: suggestedTools,
+	}, nil
+}
+
+// modelMethodRegex matches the declaration of the named method on the model.
+func modelMethodRegex(modelName, name string) *regexp.Regexp {
+	return regexp.MustCompile(`(?m)^func \(\w*\s*\*?` + regexp.QuoteMeta(modelName) + `\)\s+` + regexp.QuoteMeta(name) + `\(`)
+}
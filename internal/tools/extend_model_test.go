@@ -0,0 +1,119 @@
+package tools
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestExtendModel(t *testing.T) {
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		project, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+			ProjectName:  "app",
+			ModulePath:   "github.com/test/app",
+			DatabaseType: "sqlite",
+			InCurrentDir: true,
+		})
+		if err != nil || !project.Success {
+			t.Fatalf("scaffoldProject failed: %v %s", err, project.Message)
+		}
+		domain, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "customer",
+			Fields: []types.FieldDef{
+				{Name: "FirstName", Type: "string"},
+				{Name: "LastName", Type: "string"},
+				{Name: "Email", Type: "string"},
+			},
+		})
+		if err != nil || !domain.Success {
+			t.Fatalf("scaffoldDomain failed: %v %s", err, domain.Message)
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("adds hooks and methods that regeneration keeps", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := extendModel(registry, types.ExtendModelInput{
+			Domain: "customer",
+			Hooks: []types.ModelHookDef{
+				{Name: "BeforeSave", Body: "\tm.Email = strings.ToLower(m.Email)\n\treturn nil"},
+				{Name: "AfterFind"},
+			},
+			Methods: []types.ExtendMethodDef{
+				{Name: "DisplayName", Description: "joins the first and last name.", Returns: "string", Body: "\treturn m.FirstName + \" \" + m.LastName"},
+			},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("extendModel failed: %v %s", err, result.Message)
+		}
+
+		modelPath := filepath.Join(tmpDir, "internal", "models", "customer.go")
+		content := readFile(t, modelPath)
+		for _, want := range []string{
+			"// BeforeSave runs before the Customer is saved.\nfunc (m *Customer) BeforeSave(tx *gorm.DB) error {\n\tm.Email = strings.ToLower(m.Email)",
+			"func (m *Customer) AfterFind(tx *gorm.DB) error {\n\t// TODO: Implement this hook\n\treturn nil",
+			"// DisplayName joins the first and last name.\nfunc (m *Customer) DisplayName() string {",
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected model to contain %q, got:\n%s", want, content)
+			}
+		}
+		hooks := strings.Index(content, "// MCP:MODEL_HOOKS:START")
+		if hook := strings.Index(content, "BeforeSave(tx"); hooks < 0 || hook < hooks || hook > strings.Index(content, "// MCP:MODEL_HOOKS:END") {
+			t.Error("expected the hooks between the MCP:MODEL_HOOKS markers")
+		}
+
+		analysis, err := ExecuteAnalyzeDomain(context.Background(), registry, types.AnalyzeDomainInput{Domain: "customer"})
+		if err != nil || !analysis.Success || analysis.Domains[0].HasChanges {
+			t.Errorf("expected the injected code not to count as drift, got %+v (%v)", analysis, err)
+		}
+		if sync, err := syncDomain(registry, types.SyncDomainInput{Domain: "customer"}); err != nil || !sync.Success {
+			t.Fatalf("syncDomain failed: %v %s", err, sync.Message)
+		}
+		if readFile(t, modelPath) != content {
+			t.Error("expected sync_domain to keep the injected code")
+		}
+
+		result, _ = extendModel(registry, types.ExtendModelInput{Domain: "customer", Methods: []types.ExtendMethodDef{{Name: "DisplayName"}}})
+		if result.Success || !strings.Contains(result.Message, "already has a DisplayName method") {
+			t.Errorf("expected a duplicate method error, got: %s", result.Message)
+		}
+		result, _ = extendModel(registry, types.ExtendModelInput{Domain: "customer", Hooks: []types.ModelHookDef{{Name: "BeforeValidate"}}})
+		if result.Success || !strings.Contains(result.Message, "unknown hook 'BeforeValidate'") {
+			t.Errorf("expected an unknown hook error, got: %s", result.Message)
+		}
+	})
+
+	t.Run("overrides the table name", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := extendModel(registry, types.ExtendModelInput{Domain: "customer", TableName: "tbl_customers"})
+		if err != nil || !result.Success {
+			t.Fatalf("extendModel failed: %v %s", err, result.Message)
+		}
+		if content := readFile(t, filepath.Join(tmpDir, "internal", "models", "customer.go")); !strings.Contains(content, "TableName() string {\n\treturn \"tbl_customers\"") {
+			t.Errorf("expected TableName to return tbl_customers, got:\n%s", content)
+		}
+
+		domainMeta, _, err := metadata.NewStore(tmpDir).GetDomain("customer")
+		if err != nil || domainMeta.Input.TableName != "tbl_customers" || domainMeta.ScaffolderVersion != ScaffolderVersion {
+			t.Errorf("expected the override recorded with the scaffolder version kept, got %+v (%v)", domainMeta, err)
+		}
+		analysis, err := ExecuteAnalyzeDomain(context.Background(), registry, types.AnalyzeDomainInput{Domain: "customer"})
+		if err != nil || !analysis.Success || analysis.Domains[0].HasChanges {
+			t.Errorf("expected the regenerated model to use the override, got %+v (%v)", analysis, err)
+		}
+
+		result, _ = extendModel(registry, types.ExtendModelInput{Domain: "customer", TableName: "tbl-customers"})
+		if result.Success || !strings.Contains(result.Message, "invalid table name") {
+			t.Errorf("expected an invalid table name error, got: %s", result.Message)
+		}
+	})
+}
@@ -69,7 +69,7 @@ Examples:
      ]
    }`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ExtendRepositoryInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := extendRepository(registry, input)
+		result, err := locked(extendRepository)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
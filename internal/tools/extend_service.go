@@ -67,7 +67,7 @@ Examples:
      ]
    }`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ExtendServiceInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := extendService(registry, input)
+		result, err := locked(extendService)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -0,0 +1,124 @@
+package tools
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// hunkContext is the number of unchanged lines shown around each hunk.
+const hunkContext = 3
+
+// diffLine is one line of a line diff: ' ' unchanged, '-' removed or '+' added. Text keeps
+// its newline, so joining lines gives back the file.
+type diffLine struct {
+	op   byte
+	text string
+}
+
+// lineDiff diffs two versions of a file line by line.
+func lineDiff(existing, generated string) []diffLine {
+	dmp := diffmatchpatch.New()
+	a, b, lines := dmp.DiffLinesToChars(existing, generated)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(a, b, false), lines)
+
+	var result []diffLine
+	for _, d := range diffs {
+		op := byte(' ')
+		switch d.Type {
+		case diffmatchpatch.DiffDelete:
+			op = '-'
+		case diffmatchpatch.DiffInsert:
+			op = '+'
+		}
+		for _, line := range strings.SplitAfter(d.Text, "\n") {
+			if line != "" {
+				result = append(result, diffLine{op: op, text: line})
+			}
+		}
+	}
+	return result
+}
+
+// countHunks returns the number of hunks of a line diff: the runs of changed lines.
+func countHunks(lines []diffLine) int {
+	n := 0
+	for i, l := range lines {
+		if l.op != ' ' && (i == 0 || lines[i-1].op == ' ') {
+			n++
+		}
+	}
+	return n
+}
+
+// applyHunks returns the existing file with the selected hunks of the new version applied.
+// Hunks are numbered from 1, as in formatHunks.
+func applyHunks(lines []diffLine, selected map[int]bool) string {
+	var sb strings.Builder
+	hunk := 0
+	for i, l := range lines {
+		if l.op != ' ' && (i == 0 || lines[i-1].op == ' ') {
+			hunk++
+		}
+		switch {
+		case l.op == ' ',
+			l.op == '-' && !selected[hunk],
+			l.op == '+' && selected[hunk]:
+			sb.WriteString(l.text)
+		}
+	}
+	return sb.String()
+}
+
+// formatHunks writes a line diff as a unified diff whose hunk headers carry the hunk
+// numbers sync_domain selects hunks by.
+func formatHunks(filePath string, lines []diffLine) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("--- a/%s (existing)\n", filePath))
+	sb.WriteString(fmt.Sprintf("+++ b/%s (new template)\n", filePath))
+
+	// Line numbers of each diff line in the existing and the new file
+	oldLine, newLine := make([]int, len(lines)), make([]int, len(lines))
+	o, n := 1, 1
+	for i, l := range lines {
+		oldLine[i], newLine[i] = o, n
+		if l.op != '+' {
+			o++
+		}
+		if l.op != '-' {
+			n++
+		}
+	}
+
+	hunk := 0
+	for start := 0; start < len(lines); start++ {
+		if lines[start].op == ' ' {
+			continue
+		}
+		end := start
+		for end < len(lines) && lines[end].op != ' ' {
+			end++
+		}
+		hunk++
+		from, to := max(start-hunkContext, 0), min(end+hunkContext, len(lines))
+
+		oldCount, newCount := 0, 0
+		for _, l := range lines[from:to] {
+			if l.op != '+' {
+				oldCount++
+			}
+			if l.op != '-' {
+				newCount++
+			}
+		}
+		sb.WriteString(fmt.Sprintf("@@ -%d,%d +%d,%d @@ hunk %d\n", oldLine[from], oldCount, newLine[from], newCount, hunk))
+		for _, l := range lines[from:to] {
+			sb.WriteByte(l.op)
+			sb.WriteString(strings.TrimSuffix(l.text, "\n"))
+			sb.WriteByte('\n')
+		}
+		start = end - 1
+	}
+	return sb.String()
+}
@@ -0,0 +1,179 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterImportDomain registers the import_domain tool.
+func RegisterImportDomain(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "import_domain",
+		Description: `Re-create a domain exported with export_domain in another project.
+
+Replays the export in the target project:
+1. scaffold_domain with the exported input (files, wiring and metadata, as if scaffolded there)
+2. scaffold_wizard for each exported wizard
+3. Exported extensions are written back between the same injection markers
+
+Pass either path (an export file) or export (the export object returned by export_domain).
+path and project_path are relative to the server's working directory; project_path defaults to
+the working directory itself and must contain a go.mod.
+
+Related models (belongs_to, has_one, has_many, many_to_many) must already exist in the target
+project: import or scaffold them first. Existing files are reported as conflicts, like scaffold_domain.
+
+Example:
+  import_domain: { path: "domain-library/product.json", project_path: "admin-portal" }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ImportDomainInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(importDomain)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func importDomain(registry *Registry, input types.ImportDomainInput) (types.ScaffoldResult, error) {
+	export := input.Export
+	if input.Path != "" {
+		path, err := resolveWorkingPath(registry.WorkingDir, input.Path)
+		if err != nil {
+			return types.NewErrorResult(fmt.Sprintf("invalid path: %v", err)), nil
+		}
+		content, err := utils.ReadFileString(path)
+		if err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to read export: %v", err)), nil
+		}
+		export = &types.DomainExport{}
+		if err := json.Unmarshal([]byte(content), export); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to parse export %s: %v", input.Path, err)), nil
+		}
+	}
+	if export == nil {
+		return types.NewErrorResult("either path or export is required"), nil
+	}
+	if export.Version != DomainExportVersion {
+		return types.NewErrorResult(fmt.Sprintf("unsupported export version '%s': expected %s", export.Version, DomainExportVersion)), nil
+	}
+
+	projectDir, err := resolveWorkingPath(registry.WorkingDir, input.ProjectPath)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("invalid project_path: %v", err)), nil
+	}
+	if !utils.FileExists(filepath.Join(projectDir, "go.mod")) {
+		return types.NewErrorResult(fmt.Sprintf("no go.mod in %s: project_path must point to a project", projectDir)), nil
+	}
+
+	// The import writes to the target project, so it holds that project's lock too
+	target := NewRegistry(projectDir)
+	if projectLockPath(projectDir) != projectLockPath(registry.WorkingDir) {
+		unlock, _, _, err := target.lockProject()
+		if err != nil {
+			return types.NewErrorResult(err.Error()), nil
+		}
+		defer unlock()
+	}
+
+	// Related models must exist, or the imported model would not compile
+	var missing []string
+	for _, rel := range export.Domain.Relationships {
+		if rel.Type == "polymorphic" || isSelfRelationship(rel) {
+			continue
+		}
+		modelPath := filepath.Join(projectDir, "internal", "models", utils.ToPackageName(rel.Model)+".go")
+		if !utils.FileExists(modelPath) {
+			missing = append(missing, rel.Model)
+		}
+	}
+	if len(missing) > 0 {
+		return types.NewErrorResult(fmt.Sprintf("related model(s) %s not found in the target project: import or scaffold them first", strings.Join(missing, ", "))), nil
+	}
+
+	domainInput := export.Domain
+	domainInput.DryRun = input.DryRun
+
+	result, err := scaffoldDomain(target, domainInput)
+	if err != nil || !result.Success {
+		return result, err
+	}
+
+	for _, wizard := range export.Wizards {
+		wizard.DryRun = input.DryRun
+		wizardResult, err := scaffoldWizard(target, wizard)
+		if err != nil {
+			return wizardResult, err
+		}
+		if !wizardResult.Success {
+			wizardResult.Message = fmt.Sprintf("domain '%s' was imported, but wizard '%s' failed: %s", domainInput.DomainName, wizard.WizardName, wizardResult.Message)
+			return wizardResult, nil
+		}
+		result.FilesCreated = append(result.FilesCreated, wizardResult.FilesCreated...)
+		result.FilesUpdated = append(result.FilesUpdated, wizardResult.FilesUpdated...)
+		result.NextSteps = appendUnique(result.NextSteps, wizardResult.NextSteps...)
+	}
+
+	if !input.DryRun {
+		updated, err := writeDomainExtensions(projectDir, utils.ToPackageName(domainInput.DomainName), export.Extensions)
+		if err != nil {
+			return types.NewErrorResult(fmt.Sprintf("domain '%s' was imported, but restoring extensions failed: %v", domainInput.DomainName, err)), nil
+		}
+		result.FilesUpdated = appendUnique(result.FilesUpdated, updated...)
+	}
+
+	verb := "Imported"
+	if input.DryRun {
+		verb = "Dry run: Would import"
+	}
+	result.Message = fmt.Sprintf("%s domain '%s' into %s (%d wizard(s), %d extension section(s))", verb, domainInput.DomainName, projectDir, len(export.Wizards), len(export.Extensions))
+	return result, nil
+}
+
+// writeDomainExtensions replaces the code between each extension's markers and returns the updated files.
+func writeDomainExtensions(projectDir, pkgName string, extensions []types.DomainExtension) ([]string, error) {
+	injectors := make(map[string]*modifier.Injector)
+	var updated []string
+	for _, ext := range extensions {
+		relPath, err := extensionFilePath(ext.Layer, pkgName)
+		if err != nil {
+			return nil, err
+		}
+		injector, ok := injectors[relPath]
+		if !ok {
+			injector, err = modifier.NewInjector(filepath.Join(projectDir, relPath))
+			if err != nil {
+				return nil, err
+			}
+			injectors[relPath] = injector
+			updated = append(updated, relPath)
+		}
+		if err := injector.ReplaceMarkerContent("MCP:"+ext.Section+":START", "MCP:"+ext.Section+":END", ext.Code); err != nil {
+			return nil, fmt.Errorf("%s: %w", relPath, err)
+		}
+	}
+	for _, relPath := range updated {
+		if err := injectors[relPath].Save(); err != nil {
+			return nil, err
+		}
+	}
+	return updated, nil
+}
+
+// appendUnique appends the values that are not already in the slice.
+func appendUnique(slice []string, values ...string) []string {
+	for _, v := range values {
+		if !slices.Contains(slice, v) {
+			slice = append(slice, v)
+		}
+	}
+	return slice
+}
@@ -0,0 +1,190 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestImportDomain(t *testing.T) {
+	t.Run("requires an export", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/portal")
+
+		result, err := importDomain(registry, types.ImportDomainInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure without path or export")
+		}
+	})
+
+	t.Run("rejects unknown export versions", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/portal")
+
+		result, err := importDomain(registry, types.ImportDomainInput{
+			Export: &types.DomainExport{Version: "99", Domain: types.ScaffoldDomainInput{DomainName: "product"}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure for an unknown version")
+		}
+	})
+
+	t.Run("requires related models in the target project", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/portal")
+
+		result, err := importDomain(registry, types.ImportDomainInput{
+			Export: &types.DomainExport{
+				Version: DomainExportVersion,
+				Domain: types.ScaffoldDomainInput{
+					DomainName:    "order",
+					Fields:        []types.FieldDef{{Name: "Total", Type: "float64"}},
+					Relationships: []types.RelationshipDef{{Type: "belongs_to", Model: "Customer"}},
+				},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Fatal("expected failure for a missing related model")
+		}
+		if !strings.Contains(result.Message, "Customer") {
+			t.Errorf("expected error naming the missing model, got %q", result.Message)
+		}
+		if fileExists(filepath.Join(tmpDir, "internal", "models", "order.go")) {
+			t.Error("expected nothing to be generated")
+		}
+	})
+
+	t.Run("re-creates an exported domain in another project", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupExportProject(t, filepath.Join(tmpDir, "shop"))
+		portalDir := filepath.Join(tmpDir, "portal")
+		if err := os.MkdirAll(portalDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		setupGoMod(t, portalDir, "github.com/example/portal")
+
+		exported, err := exportDomain(registry, types.ExportDomainInput{
+			Domain:         "product",
+			ProjectPath:    "shop",
+			OutputPath:     "product.json",
+			WithExtensions: true,
+			WithWizards:    true,
+		})
+		if err != nil || !exported.Success {
+			t.Fatalf("export failed: %v %s", err, exported.Message)
+		}
+
+		result, err := importDomain(registry, types.ImportDomainInput{Path: "product.json", ProjectPath: "portal"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		model := readFile(t, filepath.Join(portalDir, "internal", "models", "product.go"))
+		if !strings.Contains(model, "SKU") {
+			t.Error("expected the model to have the exported fields")
+		}
+		if !fileExists(filepath.Join(portalDir, "internal", "web", "product", "wizard_create.go")) {
+			t.Error("expected the wizard to be imported")
+		}
+
+		shopRepo := readFile(t, filepath.Join(tmpDir, "shop", "internal", "repository", "product", "product.go"))
+		portalRepo := readFile(t, filepath.Join(portalDir, "internal", "repository", "product", "product.go"))
+		if !strings.Contains(portalRepo, "func (r *repository) FindBySKU(") {
+			t.Error("expected the repository extension to be restored")
+		}
+		if strings.ReplaceAll(shopRepo, "example/shop", "example/portal") != portalRepo {
+			t.Error("expected the imported repository to match the exported one")
+		}
+
+		meta, err := metadata.NewStore(portalDir).Load()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if _, ok := meta.Domains["product"]; !ok {
+			t.Error("expected the domain to be recorded in the target's metadata")
+		}
+		if _, ok := meta.Wizards["product:create"]; !ok {
+			t.Error("expected the wizard to be recorded in the target's metadata")
+		}
+	})
+
+	t.Run("locks the target project", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		portalDir := filepath.Join(tmpDir, "portal")
+		if err := os.MkdirAll(portalDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		setupGoMod(t, portalDir, "github.com/example/portal")
+
+		// Another call is writing to the portal project
+		unlock, _, _, err := NewRegistry(portalDir).lockProject()
+		if err != nil {
+			t.Fatal(err)
+		}
+		done := make(chan types.ScaffoldResult)
+		go func() {
+			result, _ := importDomain(registry, types.ImportDomainInput{
+				ProjectPath: "portal",
+				Export: &types.DomainExport{
+					Version: DomainExportVersion,
+					Domain:  types.ScaffoldDomainInput{DomainName: "product", Fields: []types.FieldDef{{Name: "Name", Type: "string"}}},
+				},
+			})
+			done <- result
+		}()
+		time.Sleep(50 * time.Millisecond)
+		if fileExists(filepath.Join(portalDir, "internal", "models", "product.go")) {
+			t.Error("expected the import to wait for the portal project's lock")
+		}
+		unlock()
+
+		if result := <-done; !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+		if !fileExists(filepath.Join(portalDir, "internal", "models", "product.go")) {
+			t.Error("expected the domain to be imported once the lock was released")
+		}
+	})
+
+	t.Run("dry run does not create files", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/portal")
+
+		result, err := importDomain(registry, types.ImportDomainInput{
+			Export: &types.DomainExport{
+				Version: DomainExportVersion,
+				Domain:  types.ScaffoldDomainInput{DomainName: "product", Fields: []types.FieldDef{{Name: "Name", Type: "string"}}},
+			},
+			DryRun: true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+		if fileExists(filepath.Join(tmpDir, "internal", "models", "product.go")) {
+			t.Error("expected file NOT to be created in dry run mode")
+		}
+		if len(result.FilesCreated) == 0 {
+			t.Error("expected FilesCreated to report the files that would be created")
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// RegisterLintMarkers registers the lint_markers tool.
+func RegisterLintMarkers(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "lint_markers",
+		Description: `Check the MCP marker comments of a generated project.
+
+Scaffolding tools inject code between marker comments such as '// MCP:ROUTES:START' and
+'// MCP:ROUTES:END'. A broken marker makes later injections fail or land in the wrong place,
+so lint_markers scans every Go file (except generated *_templ.go files) and reports:
+- malformed: markers the injector cannot see (wrong case, BEGIN/STOP, trailing text,
+  /* */ comments, code on the same line, missing :START or :END)
+- duplicate: a marker or section repeated; injections only use the first one
+- orphan_start / orphan_end: a START without its END, or an END without its START
+- overlapping: a section that ends inside another one
+- in_string: a marker inside a string literal
+
+Options:
+- fix: true to repair the fixable problems: malformed markers are rewritten, code is moved
+  off marker lines, orphans get their missing half next to them and duplicates are removed.
+  Overlapping sections, markers in strings and unrecognizable markers are left for you.
+- dry_run: true to preview the fixes as diffs without writing files
+
+Examples:
+  lint_markers: {}
+  lint_markers: { fix: true, dry_run: true }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.LintMarkersInput) (*mcp.CallToolResult, types.LintMarkersResult, error) {
+		call := registry.For(ctx)
+		// Fixes rewrite project files, so they hold the project lock like the scaffolding tools
+		if input.Fix && !input.DryRun {
+			unlock, _, _, err := call.lockProject()
+			if err != nil {
+				return nil, types.NewLintMarkersError(err.Error()), nil
+			}
+			defer unlock()
+		}
+		result, err := lintMarkers(call, input)
+		if err != nil {
+			return nil, types.NewLintMarkersError(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func lintMarkers(registry *Registry, input types.LintMarkersInput) (types.LintMarkersResult, error) {
+	if _, err := os.Stat(filepath.Join(registry.WorkingDir, "go.mod")); err != nil {
+		return types.NewLintMarkersError("go.mod not found: run lint_markers from the root of a generated project"), nil
+	}
+
+	paths, err := lintMarkersFiles(registry.WorkingDir)
+	if err != nil {
+		return types.NewLintMarkersError(fmt.Sprintf("failed to scan project: %v", err)), nil
+	}
+
+	result := types.LintMarkersResult{Success: true, DryRun: input.DryRun}
+	dmp := diffmatchpatch.New()
+	fixed, remaining := 0, 0
+
+	for _, relPath := range paths {
+		fullPath := filepath.Join(registry.WorkingDir, relPath)
+		content, err := os.ReadFile(fullPath)
+		if err != nil {
+			return types.NewLintMarkersError(fmt.Sprintf("failed to read %s: %v", relPath, err)), nil
+		}
+
+		problems := modifier.LintMarkers(string(content))
+		if len(problems) == 0 {
+			continue
+		}
+		if !input.Fix {
+			for _, p := range problems {
+				result.Issues = append(result.Issues, markerIssue(relPath, p, false))
+				remaining++
+			}
+			continue
+		}
+
+		updated, fixes := modifier.FixMarkers(string(content))
+		for _, p := range fixes {
+			result.Issues = append(result.Issues, markerIssue(relPath, p, true))
+			fixed++
+		}
+		// Problems left after fixing are reported with their lines in the fixed file
+		for _, p := range modifier.LintMarkers(updated) {
+			result.Issues = append(result.Issues, markerIssue(relPath, p, false))
+			remaining++
+		}
+		if len(fixes) == 0 {
+			continue
+		}
+
+		existing, generated, lines := dmp.DiffLinesToChars(string(content), updated)
+		diffs := dmp.DiffCharsToLines(dmp.DiffMain(existing, generated, false), lines)
+		file := types.FileAnalysis{
+			Path:   relPath,
+			Status: "modified",
+			Diff:   generateUnifiedDiff(relPath, diffs),
+		}
+		for _, d := range diffs {
+			switch d.Type {
+			case diffmatchpatch.DiffInsert:
+				file.LinesAdded += strings.Count(d.Text, "\n")
+			case diffmatchpatch.DiffDelete:
+				file.LinesRemoved += strings.Count(d.Text, "\n")
+			}
+		}
+		result.Files = append(result.Files, file)
+
+		if !input.DryRun {
+			if err := os.WriteFile(fullPath, []byte(updated), 0644); err != nil {
+				return types.NewLintMarkersError(fmt.Sprintf("failed to write %s: %v", relPath, err)), nil
+			}
+		}
+	}
+
+	switch {
+	case fixed == 0 && remaining == 0:
+		result.Message = fmt.Sprintf("No marker problems found in %d Go file(s)", len(paths))
+		return result, nil
+	case fixed == 0:
+		result.Message = fmt.Sprintf("Found %d marker problem(s)", remaining)
+	default:
+		verb := "Fixed"
+		if input.DryRun {
+			verb = "Would fix"
+		}
+		result.Message = fmt.Sprintf("%s %d marker problem(s) in %d file(s); %d left", verb, fixed, len(result.Files), remaining)
+	}
+
+	fixable := 0
+	for _, issue := range result.Issues {
+		if issue.Fixable && !issue.Fixed {
+			fixable++
+		}
+	}
+	if fixable > 0 {
+		result.NextSteps = append(result.NextSteps, "Run lint_markers with fix: true (and dry_run: true to preview) to repair the fixable problems")
+	}
+	if remaining > fixable {
+		result.NextSteps = append(result.NextSteps, "Edit the problems that are not fixable by hand: every section needs exactly one '// MCP:NAME:START' and one '// MCP:NAME:END' line, nested inside at most one enclosing section")
+	}
+	return result, nil
+}
+
+// lintMarkersFiles returns the Go files lint_markers checks, relative to dir.
+func lintMarkersFiles(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && upgradeStackSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		name := d.Name()
+		if !strings.HasSuffix(name, ".go") || strings.HasSuffix(name, "_templ.go") {
+			return nil
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		paths = append(paths, rel)
+		return nil
+	})
+	return paths, err
+}
+
+// markerIssue converts a marker problem found in path.
+func markerIssue(path string, p modifier.MarkerProblem, fixed bool) types.MarkerIssue {
+	return types.MarkerIssue{
+		File:    path,
+		Line:    p.Line,
+		Marker:  p.Marker,
+		Kind:    p.Kind,
+		Message: p.Message,
+		Fixable: p.Fixable,
+		Fixed:   fixed,
+	}
+}
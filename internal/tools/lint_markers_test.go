@@ -0,0 +1,131 @@
+package tools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestLintMarkers(t *testing.T) {
+	const broken = `package main
+
+func main() {
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+	// mcp:controllers:begin
+	// MCP:CONTROLLERS:END
+
+	router := web.NewRouter(cfg)
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:END
+	// MCP:ROUTES:PUBLIC:END
+	// MCP:SCHEDULER:START
+}
+`
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, broken)
+		return registry, tmpDir
+	}
+
+	t.Run("reports problems without changing files", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := lintMarkers(registry, types.LintMarkersInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		kinds := map[string]int{}
+		for _, issue := range result.Issues {
+			kinds[issue.Kind]++
+			if issue.File != filepath.Join("cmd", "web", "main.go") {
+				t.Errorf("unexpected file %s", issue.File)
+			}
+		}
+		want := map[string]int{"malformed": 1, "overlapping": 1, "orphan_start": 1}
+		for kind, n := range want {
+			if kinds[kind] != n {
+				t.Errorf("expected %d %s issue(s), got %d: %+v", n, kind, kinds[kind], result.Issues)
+			}
+		}
+		if readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go")) != broken {
+			t.Error("expected main.go to be unchanged")
+		}
+		if len(result.NextSteps) == 0 {
+			t.Error("expected next steps")
+		}
+	})
+
+	t.Run("dry run previews fixes", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := lintMarkers(registry, types.LintMarkersInput{Fix: true, DryRun: true})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		if len(result.Files) != 1 || !strings.Contains(result.Files[0].Diff, "+\t// MCP:CONTROLLERS:START") {
+			t.Errorf("expected a diff for main.go, got %+v", result.Files)
+		}
+		if readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go")) != broken {
+			t.Error("expected dry run to leave main.go unchanged")
+		}
+	})
+
+	t.Run("fixes the fixable problems", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := lintMarkers(registry, types.LintMarkersInput{Fix: true})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go"))
+		for _, want := range []string{"\t// MCP:CONTROLLERS:START\n", "\t// MCP:SCHEDULER:START\n\t// MCP:SCHEDULER:END\n"} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected main.go to contain %q", want)
+			}
+		}
+
+		var left []string
+		for _, issue := range result.Issues {
+			if !issue.Fixed {
+				left = append(left, issue.Kind)
+			}
+		}
+		if strings.Join(left, ",") != "overlapping" {
+			t.Errorf("expected only the overlapping sections to be left, got %v", left)
+		}
+	})
+
+	t.Run("clean project", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, "package main\n\nfunc main() {\n\t// MCP:ROUTES:START\n\t// MCP:ROUTES:END\n}\n")
+
+		result, err := lintMarkers(registry, types.LintMarkersInput{Fix: true})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		if len(result.Issues) != 0 || len(result.Files) != 0 {
+			t.Errorf("expected no issues, got %+v", result.Issues)
+		}
+	})
+
+	t.Run("requires go.mod", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+		result, _ := lintMarkers(registry, types.LintMarkersInput{})
+		if result.Success {
+			t.Error("expected failure without go.mod")
+		}
+	})
+}
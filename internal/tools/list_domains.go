@@ -2,8 +2,12 @@ package tools
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
+	"slices"
+	"strings"
 
+	"github.com/dbb1dev/go-mcp/internal/metadata"
 	"github.com/dbb1dev/go-mcp/internal/types"
 	"github.com/dbb1dev/go-mcp/internal/utils"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -20,10 +24,13 @@ Scans internal/models, internal/repository, internal/services, and internal/web
 Returns for each domain:
 - Which layers exist (model, repository, service, controller)
 - List of views in internal/web/{domain}/views
+- Whether it has scaffold metadata. Domains with a model and a controller but no metadata
+  (scaffolded before metadata tracking) are flagged unmanaged: run reconstruct_metadata so
+  analyze_domain and sync_domain can update them
 
 Use this to understand project structure before adding new domains.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ListDomainsInput) (*mcp.CallToolResult, types.ListDomainsResult, error) {
-		result, err := listDomains(registry)
+		result, err := listDomains(registry.For(ctx))
 		if err != nil {
 			return nil, types.NewListDomainsError(err.Error()), nil
 		}
@@ -78,7 +85,7 @@ func listDomains(registry *Registry) (types.ListDomainsResult, error) {
 	webDirs, _ := utils.ListDirs(webDir)
 	for _, dir := range webDirs {
 		// Skip common directories
-		if dir == "middleware" || dir == "layouts" || dir == "components" {
+		if dir == "middleware" || dir == "layouts" || dir == "components" || dir == "menu" || dir == "routes" || dir == "seo" || dir == "breadcrumbs" {
 			continue
 		}
 
@@ -97,11 +104,39 @@ func listDomains(registry *Registry) (types.ListDomainsResult, error) {
 		}
 	}
 
+	// Flag the domains without metadata: analyze_domain and sync_domain can't see them
+	metaDomains, err := metadata.NewStore(registry.WorkingDir).ListDomains()
+	if err != nil {
+		return types.NewListDomainsError(fmt.Sprintf("failed to read project metadata: %v", err)), nil
+	}
+	for _, name := range metaDomains {
+		if info, ok := domains[utils.ToPackageName(name)]; ok {
+			info.HasMetadata = true
+		}
+	}
+
 	// Convert map to slice
 	result := make([]types.DomainInfo, 0, len(domains))
+	var unmanaged []string
 	for _, info := range domains {
+		if !info.HasMetadata && info.HasModel && info.HasController {
+			info.Unmanaged = true
+			unmanaged = append(unmanaged, info.Name)
+		}
 		result = append(result, *info)
 	}
-
-	return types.NewListDomainsResult(result), nil
+	slices.SortFunc(result, func(a, b types.DomainInfo) int { return strings.Compare(a.Name, b.Name) })
+
+	listResult := types.NewListDomainsResult(result)
+	if len(unmanaged) > 0 {
+		slices.Sort(unmanaged)
+		listResult.Message = fmt.Sprintf("%d domain(s) have no scaffold metadata: %s", len(unmanaged), strings.Join(unmanaged, ", "))
+		listResult.SuggestedTool = &types.ToolHint{
+			Tool:        "reconstruct_metadata",
+			Description: "Rebuild the metadata of the unmanaged domains from their code, so analyze_domain and sync_domain can update them",
+			Example:     fmt.Sprintf(`reconstruct_metadata: { domains: ["%s"] }`, unmanaged[0]),
+			Priority:    "recommended",
+		}
+	}
+	return listResult, nil
 }
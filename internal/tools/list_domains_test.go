@@ -3,7 +3,11 @@ package tools
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
 )
 
 func TestListDomains(t *testing.T) {
@@ -321,4 +325,42 @@ func TestListDomains(t *testing.T) {
 			}
 		}
 	})
+	t.Run("flags domains without metadata as unmanaged", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+
+		// order_item has metadata, invoice doesn't, and user has no controller
+		for _, path := range []string{
+			"internal/models/orderitem.go",
+			"internal/web/orderitem/orderitem.go",
+			"internal/models/invoice.go",
+			"internal/web/invoice/invoice.go",
+			"internal/models/user.go",
+		} {
+			writeTestFile(t, filepath.Join(tmpDir, path), "package x\n")
+		}
+		if err := metadata.NewStore(tmpDir).SaveDomain("order_item", types.ScaffoldDomainInput{DomainName: "order_item"}, ScaffolderVersion); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := listDomains(registry)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Domains) != 3 || result.Domains[0].Name != "invoice" {
+			t.Fatalf("expected 3 domains sorted by name, got %+v", result.Domains)
+		}
+		invoice, orderItem, user := result.Domains[0], result.Domains[1], result.Domains[2]
+		if !invoice.Unmanaged || invoice.HasMetadata {
+			t.Errorf("expected invoice unmanaged, got %+v", invoice)
+		}
+		if orderItem.Unmanaged || !orderItem.HasMetadata {
+			t.Errorf("expected orderitem managed, got %+v", orderItem)
+		}
+		if user.Unmanaged {
+			t.Errorf("a model without a controller is not a domain, got %+v", user)
+		}
+		if result.SuggestedTool == nil || result.SuggestedTool.Tool != "reconstruct_metadata" || !strings.Contains(result.Message, "invoice") {
+			t.Errorf("expected a reconstruct_metadata suggestion, got %q %+v", result.Message, result.SuggestedTool)
+		}
+	})
 }
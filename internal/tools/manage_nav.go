@@ -0,0 +1,467 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// RegisterManageNav registers the manage_nav tool.
+func RegisterManageNav(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "manage_nav",
+		Description: `List, add, remove and reorder the navigation links of config/en/menu.toml.
+
+scaffold_domain only links authenticated and admin domains; use this tool for every other
+link. The layouts render menu.toml, so no templ file changes: restart the app to load it.
+
+Sections:
+- main: the sidebar of every signed-in user (default)
+- admin: the sidebar of admins
+- account: the bottom of the sidebar
+- site, footer: the header and footer of the public pages (no icon, badge or group)
+
+Actions:
+- list (default): the links of a section, or of every section, sorted by order
+- add: add a link, or update the link of the section with that url. Requires url, and label
+  for new links. Optional icon (a name of the project's heroicons or lucide set in
+  internal/web/components/icons.go, e.g. home, folder, chart-bar, shopping-cart), badge (a
+  short text next to the label, e.g. "New"), group (a heading the links sharing it are listed
+  under) and position (1 for the top). Fields left empty keep their value
+- remove: remove the link of the section with that url
+- reorder: urls lists the section's links in their new order; the others follow
+
+Orders are renumbered 10, 20, 30... when a link moves. dry_run previews the menu.toml diff.
+The previous menu.toml is kept in .mcp/backups/<timestamp>/ (backup_dir).
+
+Examples:
+  manage_nav: {}
+  manage_nav: { action: "add", url: "/reports", label: "Reports", icon: "folder", group: "Insights", badge: "New" }
+  manage_nav: { action: "add", section: "admin", url: "/admin/audit", label: "Audit log", position: 1 }
+  manage_nav: { action: "reorder", urls: ["/reports", "/dashboard"] }
+  manage_nav: { action: "remove", section: "footer", url: "/terms" }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ManageNavInput) (*mcp.CallToolResult, types.ManageNavResult, error) {
+		call := registry.For(ctx)
+		// add, remove and reorder rewrite menu.toml, which scaffold_domain also edits
+		if input.Action != "" && input.Action != "list" && !input.DryRun {
+			unlock, _, _, err := call.lockProject()
+			if err != nil {
+				return nil, types.NewManageNavError(err.Error()), nil
+			}
+			defer unlock()
+		}
+		result, err := manageNav(call, input)
+		if err != nil {
+			return nil, types.NewManageNavError(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+// menuSections are the sections of menu.toml, in the order list reports them.
+var menuSections = []string{"main", "admin", "account", "site", "footer"}
+
+// menuTableRegex matches the header of a menu.toml link, e.g. [[main]].
+var menuTableRegex = regexp.MustCompile(`^\s*\[\[\s*([A-Za-z0-9_-]+)\s*\]\]\s*$`)
+
+// menuKeyRegex matches a key of a menu.toml link with its value.
+var menuKeyRegex = regexp.MustCompile(`^\s*([A-Za-z0-9_-]+)\s*=\s*(.*?)\s*$`)
+
+// projectIconsPath is the file drawing the icons of the project's icon set.
+var projectIconsPath = filepath.Join("internal", "web", "components", "icons.go")
+
+// projectIconRegex matches the icon names of projectIconsPath.
+var projectIconRegex = regexp.MustCompile(`(?m)^\s*case "([a-z0-9-]+)":`)
+
+// validateProjectIcon checks that the project's icon set draws an icon. The names are read from
+// internal/web/components/icons.go, so icons added there are accepted; projects without the
+// file are checked against the names both icon sets draw.
+func validateProjectIcon(workingDir, icon string) error {
+	if err := utils.ValidateMenuIcon(icon); err != nil {
+		return err
+	}
+	names := generator.IconNames()
+	if content, err := os.ReadFile(filepath.Join(workingDir, projectIconsPath)); err == nil {
+		names = nil
+		for _, match := range projectIconRegex.FindAllStringSubmatch(string(content), -1) {
+			names = append(names, match[1])
+		}
+		sort.Strings(names)
+	}
+	if !slices.Contains(names, icon) {
+		return fmt.Errorf("unknown icon '%s': the project's icon set draws %s", icon, strings.Join(names, ", "))
+	}
+	return nil
+}
+
+// menuKeys is the order of the keys of a link written by manage_nav.
+var menuKeys = []string{"label", "url", "icon", "badge", "group", "order"}
+
+// menuFile is config/en/menu.toml split into its links. Comments and the keys the tool does
+// not know are kept as they are.
+type menuFile struct {
+	// header is the text before the first link.
+	header string
+	links  []*menuLink
+}
+
+// menuLink is a [[section]] table of menu.toml.
+type menuLink struct {
+	section string
+	// comments are the comment lines right above the table header.
+	comments []string
+	// lines are the lines of the table after its header, without trailing blank lines.
+	lines []string
+}
+
+// parseMenuFile splits the content of menu.toml into its links.
+func parseMenuFile(content string) *menuFile {
+	file := &menuFile{}
+	var header []string
+	var current *menuLink
+	for _, line := range strings.Split(strings.TrimRight(content, "\n"), "\n") {
+		m := menuTableRegex.FindStringSubmatch(line)
+		if m == nil {
+			if current == nil {
+				header = append(header, line)
+			} else {
+				current.lines = append(current.lines, line)
+			}
+			continue
+		}
+
+		// Comments right above the header belong to the new link
+		previous := &header
+		if current != nil {
+			previous = &current.lines
+		}
+		start := len(*previous)
+		for start > 0 && strings.HasPrefix(strings.TrimSpace((*previous)[start-1]), "#") {
+			start--
+		}
+		link := &menuLink{section: m[1], comments: slices.Clone((*previous)[start:])}
+		*previous = (*previous)[:start]
+		if current != nil {
+			current.lines = trimBlankLines(current.lines)
+		}
+		file.links = append(file.links, link)
+		current = link
+	}
+	if current != nil {
+		current.lines = trimBlankLines(current.lines)
+	}
+	file.header = strings.Join(trimBlankLines(header), "\n")
+	return file
+}
+
+// trimBlankLines drops the blank lines at the end of lines.
+func trimBlankLines(lines []string) []string {
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// String renders the menu file, the links separated by a blank line.
+func (f *menuFile) String() string {
+	var blocks []string
+	if f.header != "" {
+		blocks = append(blocks, f.header)
+	}
+	for _, link := range f.links {
+		lines := append(slices.Clone(link.comments), "[["+link.section+"]]")
+		blocks = append(blocks, strings.Join(append(lines, link.lines...), "\n"))
+	}
+	return strings.Join(blocks, "\n\n") + "\n"
+}
+
+// section returns the links of a section sorted by order, like the menu package does.
+func (f *menuFile) section(name string) []*menuLink {
+	var links []*menuLink
+	for _, link := range f.links {
+		if link.section == name {
+			links = append(links, link)
+		}
+	}
+	sort.SliceStable(links, func(i, j int) bool { return links[i].order() < links[j].order() })
+	return links
+}
+
+// find returns the link of a section with the given url, or nil.
+func (f *menuFile) find(section, url string) *menuLink {
+	for _, link := range f.links {
+		if link.section == section && link.get("url") == url {
+			return link
+		}
+	}
+	return nil
+}
+
+// get returns the value of a key of the link: the text of a string, the digits of a number.
+func (l *menuLink) get(key string) string {
+	for _, line := range l.lines {
+		m := menuKeyRegex.FindStringSubmatch(line)
+		if m == nil || m[1] != key {
+			continue
+		}
+		if s := tomlStringPattern.FindStringSubmatch(line); s != nil {
+			if value, err := strconv.Unquote(s[2]); err == nil {
+				return value
+			}
+		}
+		return strings.TrimSpace(strings.SplitN(m[2], "#", 2)[0])
+	}
+	return ""
+}
+
+// order returns the order of the link, 0 when unset.
+func (l *menuLink) order() int {
+	order, _ := strconv.Atoi(l.get("order"))
+	return order
+}
+
+// set writes a key of the link; an empty value removes it. New keys go in menuKeys order.
+func (l *menuLink) set(key, value string) {
+	line := fmt.Sprintf("%s = %q", key, value)
+	if key == "order" {
+		line = key + " = " + value
+	}
+	for i, existing := range l.lines {
+		if m := menuKeyRegex.FindStringSubmatch(existing); m != nil && m[1] == key {
+			if value == "" {
+				l.lines = slices.Delete(l.lines, i, i+1)
+			} else {
+				l.lines[i] = line
+			}
+			return
+		}
+	}
+	if value == "" {
+		return
+	}
+	// Before the first key that follows it
+	rank := slices.Index(menuKeys, key)
+	for i, existing := range l.lines {
+		if m := menuKeyRegex.FindStringSubmatch(existing); m != nil && slices.Index(menuKeys, m[1]) > rank {
+			l.lines = slices.Insert(l.lines, i, line)
+			return
+		}
+	}
+	l.lines = append(l.lines, line)
+}
+
+// navItem returns the link as reported by the tool.
+func (l *menuLink) navItem() types.NavItem {
+	return types.NavItem{
+		Label: l.get("label"),
+		URL:   l.get("url"),
+		Icon:  l.get("icon"),
+		Badge: l.get("badge"),
+		Group: l.get("group"),
+		Order: l.order(),
+	}
+}
+
+// renumber orders links 10, 20, 30...
+func renumber(links []*menuLink) {
+	for i, link := range links {
+		link.set("order", strconv.Itoa((i+1)*10))
+	}
+}
+
+func manageNav(registry *Registry, input types.ManageNavInput) (types.ManageNavResult, error) {
+	action := input.Action
+	if action == "" {
+		action = "list"
+	}
+	section := input.Section
+	if section == "" && action != "list" {
+		section = "main"
+	}
+	if section != "" {
+		if err := utils.ValidateMenuSection(section); err != nil {
+			return types.NewManageNavError(err.Error()), nil
+		}
+	}
+
+	relPath := filepath.Join("config", "en", "menu.toml")
+	menuPath := filepath.Join(registry.WorkingDir, relPath)
+	content, err := os.ReadFile(menuPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return types.NewManageNavError("config/en/menu.toml not found: run scaffold_project first"), nil
+		}
+		return types.NewManageNavError(fmt.Sprintf("failed to read %s: %v", relPath, err)), nil
+	}
+	file := parseMenuFile(string(content))
+
+	var message string
+	switch action {
+	case "list":
+		count := 0
+		for _, link := range file.links {
+			if section == "" || link.section == section {
+				count++
+			}
+		}
+		message = fmt.Sprintf("%d link(s) in config/en/menu.toml", count)
+
+	case "add":
+		if err := utils.ValidateMenuURL(input.URL); err != nil {
+			return types.NewManageNavError(err.Error()), nil
+		}
+		if input.Icon != "" {
+			if err := validateProjectIcon(registry.WorkingDir, input.Icon); err != nil {
+				return types.NewManageNavError(err.Error()), nil
+			}
+		}
+		if (section == "site" || section == "footer") && (input.Icon != "" || input.Badge != "" || input.Group != "") {
+			return types.NewManageNavError(fmt.Sprintf("%s links have no icon, badge or group: only the sidebar sections (main, admin, account) show them", section)), nil
+		}
+		if len(input.Badge) > 20 {
+			return types.NewManageNavError(fmt.Sprintf("badge '%s' is too long: keep it to 20 characters", input.Badge)), nil
+		}
+		links := file.section(section)
+		if input.Position < 0 || input.Position > len(links)+1 {
+			return types.NewManageNavError(fmt.Sprintf("invalid position %d: the %s section has %d link(s)", input.Position, section, len(links))), nil
+		}
+
+		link := file.find(section, input.URL)
+		if link == nil {
+			if input.Label == "" {
+				return types.NewManageNavError("label is required to add a link"), nil
+			}
+			order := 10
+			if len(links) > 0 {
+				order = links[len(links)-1].order() + 10
+			}
+			link = &menuLink{section: section}
+			link.set("url", input.URL)
+			link.set("order", strconv.Itoa(order))
+			file.links = append(file.links, link)
+			links = append(links, link)
+			message = fmt.Sprintf("Added %s to the %s section", input.URL, section)
+		} else {
+			message = fmt.Sprintf("Updated %s in the %s section", input.URL, section)
+		}
+		for key, value := range map[string]string{"label": input.Label, "icon": input.Icon, "badge": input.Badge, "group": input.Group} {
+			if value != "" {
+				link.set(key, value)
+			}
+		}
+		if input.Position > 0 {
+			links = slices.DeleteFunc(links, func(l *menuLink) bool { return l == link })
+			links = slices.Insert(links, min(input.Position-1, len(links)), link)
+			renumber(links)
+		}
+
+	case "remove":
+		if input.URL == "" {
+			return types.NewManageNavError("url is required to remove a link"), nil
+		}
+		link := file.find(section, input.URL)
+		if link == nil {
+			return types.NewManageNavError(fmt.Sprintf("the %s section has no link to %s", section, input.URL)), nil
+		}
+		file.links = slices.DeleteFunc(file.links, func(l *menuLink) bool { return l == link })
+		message = fmt.Sprintf("Removed %s from the %s section", input.URL, section)
+
+	case "reorder":
+		if len(input.URLs) == 0 {
+			return types.NewManageNavError("urls is required to reorder a section"), nil
+		}
+		var moved []*menuLink
+		for _, url := range input.URLs {
+			link := file.find(section, url)
+			if link == nil {
+				return types.NewManageNavError(fmt.Sprintf("the %s section has no link to %s", section, url)), nil
+			}
+			if slices.Contains(moved, link) {
+				return types.NewManageNavError(fmt.Sprintf("%s is listed twice in urls", url)), nil
+			}
+			moved = append(moved, link)
+		}
+		for _, link := range file.section(section) {
+			if !slices.Contains(moved, link) {
+				moved = append(moved, link)
+			}
+		}
+		renumber(moved)
+		message = fmt.Sprintf("Reordered the %s section", section)
+
+	default:
+		return types.NewManageNavError(fmt.Sprintf("invalid action '%s': must be one of list, add, remove, reorder", action)), nil
+	}
+
+	result := types.ManageNavResult{Success: true, Message: message, DryRun: input.DryRun}
+	for _, name := range menuSections {
+		if section != "" && name != section {
+			continue
+		}
+		items := []types.NavItem{}
+		for _, link := range file.section(name) {
+			items = append(items, link.navItem())
+		}
+		result.Sections = append(result.Sections, types.NavSection{Section: name, Items: items})
+	}
+	if action == "list" {
+		return result, nil
+	}
+
+	updated := file.String()
+	if updated == string(content) {
+		result.Message += " (no change)"
+		return result, nil
+	}
+	dmp := diffmatchpatch.New()
+	chars1, chars2, lines := dmp.DiffLinesToChars(string(content), updated)
+	diffs := dmp.DiffCharsToLines(dmp.DiffMain(chars1, chars2, false), lines)
+	analysis := types.FileAnalysis{
+		Path:   filepath.ToSlash(relPath),
+		Status: "modified",
+		Diff:   generateUnifiedDiff(filepath.ToSlash(relPath), diffs),
+	}
+	for _, d := range diffs {
+		switch d.Type {
+		case diffmatchpatch.DiffInsert:
+			analysis.LinesAdded += strings.Count(d.Text, "\n")
+		case diffmatchpatch.DiffDelete:
+			analysis.LinesRemoved += strings.Count(d.Text, "\n")
+		}
+	}
+	result.Files = append(result.Files, analysis)
+	if input.DryRun {
+		return result, nil
+	}
+
+	backupDir := newBackupDir()
+	if err := utils.CopyFile(menuPath, filepath.Join(registry.WorkingDir, backupDir, relPath)); err != nil {
+		return types.NewManageNavError(fmt.Sprintf("failed to back up %s: %v", relPath, err)), nil
+	}
+	result.BackupDir = backupDir
+	if err := os.WriteFile(menuPath, []byte(updated), 0644); err != nil {
+		return types.NewManageNavError(fmt.Sprintf("failed to write %s: %v", relPath, err)), nil
+	}
+	result.Message += fmt.Sprintf("; the previous version is in %s", backupDir)
+	result.NextSteps = append(result.NextSteps, "Restart the app to load the menu")
+	if input.Badge != "" || input.Group != "" {
+		// Projects scaffolded before badges and groups ignore them
+		menuGo, _ := os.ReadFile(filepath.Join(registry.WorkingDir, "internal", "web", "menu", "menu.go"))
+		if !strings.Contains(string(menuGo), "Badge") {
+			result.NextSteps = append(result.NextSteps, "internal/web/menu/menu.go predates badges and groups: run analyze_project to update it and the sidebar of internal/web/layouts/base.templ")
+		}
+	}
+	return result, nil
+}
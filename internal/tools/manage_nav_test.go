@@ -0,0 +1,185 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestManageNav(t *testing.T) {
+	const menu = `# Sidebar navigation
+
+[[main]]
+label = "Dashboard"
+url = "/dashboard"
+icon = "home"
+order = 1
+
+# Scaffolded domain
+[[main]]
+label = "Products"
+url = "/products"
+icon = "folder"
+order = 10
+
+[[account]]
+label = "Profile"
+url = "/profile"
+icon = "user"
+order = 1
+`
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		menuPath := filepath.Join(tmpDir, "config", "en", "menu.toml")
+		if err := os.MkdirAll(filepath.Dir(menuPath), 0755); err != nil {
+			t.Fatalf("failed to create config dir: %v", err)
+		}
+		if err := os.WriteFile(menuPath, []byte(menu), 0644); err != nil {
+			t.Fatalf("failed to write menu.toml: %v", err)
+		}
+		return registry, menuPath
+	}
+	urls := func(section types.NavSection) string {
+		var urls []string
+		for _, item := range section.Items {
+			urls = append(urls, item.URL)
+		}
+		return strings.Join(urls, " ")
+	}
+
+	t.Run("lists every section", func(t *testing.T) {
+		registry, _ := setup(t)
+		result, _ := manageNav(registry, types.ManageNavInput{})
+		if !result.Success || len(result.Sections) != 5 {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+		if got := urls(result.Sections[0]); got != "/dashboard /products" {
+			t.Errorf("main = %q", got)
+		}
+		if result.Sections[2].Items[0].Icon != "user" {
+			t.Errorf("account = %+v", result.Sections[2].Items)
+		}
+	})
+
+	t.Run("adds a link with a badge and group at a position", func(t *testing.T) {
+		registry, menuPath := setup(t)
+		result, _ := manageNav(registry, types.ManageNavInput{Action: "add", URL: "/reports", Label: "Reports", Icon: "chart-bar", Badge: "New", Group: "Insights", Position: 2})
+		if !result.Success {
+			t.Fatalf("manageNav failed: %s", result.Message)
+		}
+		if got := urls(result.Sections[0]); got != "/dashboard /reports /products" {
+			t.Errorf("main = %q", got)
+		}
+
+		content := readFile(t, menuPath)
+		for _, want := range []string{
+			"[[main]]\nlabel = \"Reports\"\nurl = \"/reports\"\nicon = \"chart-bar\"\nbadge = \"New\"\ngroup = \"Insights\"\norder = 20\n",
+			"url = \"/dashboard\"\nicon = \"home\"\norder = 10\n",
+			"# Scaffolded domain\n[[main]]\nlabel = \"Products\"\nurl = \"/products\"\nicon = \"folder\"\norder = 30\n",
+			"url = \"/profile\"\nicon = \"user\"\norder = 1\n",
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("menu.toml should contain %q, got:\n%s", want, content)
+			}
+		}
+	})
+
+	t.Run("updates an existing link", func(t *testing.T) {
+		registry, menuPath := setup(t)
+		result, _ := manageNav(registry, types.ManageNavInput{Action: "add", URL: "/products", Badge: "3"})
+		if !result.Success || !strings.HasPrefix(result.Message, "Updated") {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+		if content := readFile(t, menuPath); !strings.Contains(content, "label = \"Products\"\nurl = \"/products\"\nicon = \"folder\"\nbadge = \"3\"\norder = 10\n") {
+			t.Errorf("unexpected menu.toml:\n%s", content)
+		}
+	})
+
+	t.Run("removes a link with its comments", func(t *testing.T) {
+		registry, menuPath := setup(t)
+		result, _ := manageNav(registry, types.ManageNavInput{Action: "remove", URL: "/products"})
+		if !result.Success {
+			t.Fatalf("manageNav failed: %s", result.Message)
+		}
+		content := readFile(t, menuPath)
+		if strings.Contains(content, "Products") || strings.Contains(content, "Scaffolded domain") {
+			t.Errorf("expected the link and its comment removed, got:\n%s", content)
+		}
+		if !strings.HasPrefix(content, "# Sidebar navigation\n\n[[main]]\nlabel = \"Dashboard\"") {
+			t.Errorf("expected the header kept, got:\n%s", content)
+		}
+		if result.BackupDir == "" || readFile(t, filepath.Join(registry.WorkingDir, result.BackupDir, "config", "en", "menu.toml")) != menu {
+			t.Errorf("expected the previous menu.toml in the backup dir, got %q", result.BackupDir)
+		}
+	})
+
+	t.Run("reorders a section", func(t *testing.T) {
+		registry, _ := setup(t)
+		result, _ := manageNav(registry, types.ManageNavInput{Action: "reorder", URLs: []string{"/products"}})
+		if !result.Success {
+			t.Fatalf("manageNav failed: %s", result.Message)
+		}
+		if got := urls(result.Sections[0]); got != "/products /dashboard" {
+			t.Errorf("main = %q", got)
+		}
+	})
+
+	t.Run("dry run leaves menu.toml alone", func(t *testing.T) {
+		registry, menuPath := setup(t)
+		result, _ := manageNav(registry, types.ManageNavInput{Action: "add", Section: "footer", URL: "/privacy", Label: "Privacy", DryRun: true})
+		if !result.Success || len(result.Files) != 1 || !strings.Contains(result.Files[0].Diff, "+[[footer]]") {
+			t.Fatalf("unexpected result: %+v", result)
+		}
+		if readFile(t, menuPath) != menu {
+			t.Error("dry run should not write menu.toml")
+		}
+		if result.BackupDir != "" {
+			t.Errorf("expected no backup on a dry run, got %s", result.BackupDir)
+		}
+	})
+
+	t.Run("checks icons against the project's icon set", func(t *testing.T) {
+		registry, _ := setup(t)
+		iconsPath := filepath.Join(registry.WorkingDir, "internal", "web", "components", "icons.go")
+		if err := os.MkdirAll(filepath.Dir(iconsPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		icons := "package components\n\nfunc iconSVG(name string) string {\n\tswitch name {\n\tcase \"home\":\n\t\treturn ``\n\tcase \"rocket\":\n\t\treturn ``\n\t}\n\treturn ``\n}\n"
+		if err := os.WriteFile(iconsPath, []byte(icons), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if result, _ := manageNav(registry, types.ManageNavInput{Action: "add", URL: "/launches", Label: "Launches", Icon: "rocket"}); !result.Success {
+			t.Errorf("expected an icon added to icons.go to be accepted, got: %s", result.Message)
+		}
+		result, _ := manageNav(registry, types.ManageNavInput{Action: "add", URL: "/reports", Label: "Reports", Icon: "chart-bar"})
+		if result.Success || !strings.Contains(result.Message, "draws home, rocket") {
+			t.Errorf("expected an icon icons.go does not draw to be refused, got: %s", result.Message)
+		}
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		registry, _ := setup(t)
+		for name, input := range map[string]types.ManageNavInput{
+			"action":         {Action: "rename"},
+			"section":        {Action: "add", Section: "sidebar", URL: "/x", Label: "X"},
+			"url":            {Action: "add", URL: "reports", Label: "Reports"},
+			"label":          {Action: "add", URL: "/reports"},
+			"icon":           {Action: "add", URL: "/reports", Label: "Reports", Icon: "Chart Bar"},
+			"unknown icon":   {Action: "add", URL: "/reports", Label: "Reports", Icon: "rocket"},
+			"footer badge":   {Action: "add", Section: "footer", URL: "/terms", Label: "Terms", Badge: "New"},
+			"position":       {Action: "add", URL: "/reports", Label: "Reports", Position: 5},
+			"missing link":   {Action: "remove", URL: "/missing"},
+			"reorder twice":  {Action: "reorder", URLs: []string{"/products", "/products"}},
+			"reorder absent": {Action: "reorder", URLs: []string{"/missing"}},
+		} {
+			if result, _ := manageNav(registry, input); result.Success {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+}
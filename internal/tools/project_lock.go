@@ -0,0 +1,120 @@
+package tools
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+// Tools that write to a project hold its lock for the whole call, so concurrent calls,
+// jobs and command line runs can't interleave their edits of the files they share,
+// such as main.go, database.go and .mcp/scaffold-metadata.json.
+const (
+	// lockTimeout is how long a call waits for the lock before giving up.
+	lockTimeout = 30 * time.Second
+	// lockStale is the age after which a lock file is taken to be left behind by a process that died.
+	lockStale = 2 * time.Minute
+	// lockRefresh is how often a held lock file is touched, so a call that runs longer than
+	// lockStale, such as scaffold_project running go mod tidy, doesn't lose its lock.
+	lockRefresh = lockStale / 4
+	// lockMinBackoff and lockMaxBackoff bound the wait between attempts to take the lock.
+	lockMinBackoff = 10 * time.Millisecond
+	lockMaxBackoff = 500 * time.Millisecond
+)
+
+// projectLockPath returns the lock file of the project in dir. It lives in the temporary
+// directory rather than in the project, which scaffold_project may not have created yet.
+func projectLockPath(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		dir = abs
+	}
+	sum := sha256.Sum256([]byte(resolveSymlinks(dir)))
+	return filepath.Join(os.TempDir(), "gomcp-"+hex.EncodeToString(sum[:8])+".lock")
+}
+
+// lockProject takes the lock of the registry's project, retrying with backoff while another
+// call holds it. The lock is a file created exclusively, so it serializes calls across
+// processes as well. It returns the function that releases the lock, and how long the call
+// waited and how many times it retried when the lock was busy.
+func (r *Registry) lockProject() (unlock func(), waited time.Duration, retries int, err error) {
+	path := projectLockPath(r.WorkingDir)
+	started := time.Now()
+	backoff := lockMinBackoff
+	for {
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0o644)
+		if err == nil {
+			fmt.Fprintf(f, "%d\n", os.Getpid())
+			f.Close()
+			if retries > 0 {
+				waited = time.Since(started)
+			}
+			stop := keepLockFresh(path, lockRefresh)
+			return func() {
+				stop()
+				os.Remove(path)
+			}, waited, retries, nil
+		}
+		if !os.IsExist(err) {
+			return nil, 0, retries, fmt.Errorf("locking the project: %w", err)
+		}
+		if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > lockStale {
+			os.Remove(path)
+			continue
+		}
+		if time.Since(started) >= lockTimeout {
+			return nil, time.Since(started), retries, fmt.Errorf(
+				"another call has been writing to %s for over %s (%d retries): retry later, or remove %s if no gomcp process is running",
+				r.WorkingDir, lockTimeout, retries, path)
+		}
+		time.Sleep(backoff)
+		backoff = min(backoff*2, lockMaxBackoff)
+		retries++
+	}
+}
+
+// keepLockFresh touches the lock file every interval until stop is called, so other calls
+// don't take it over as stale while the call that holds it is still running.
+func keepLockFresh(path string, interval time.Duration) (stop func()) {
+	done := make(chan struct{})
+	stopped := make(chan struct{})
+	go func() {
+		defer close(stopped)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				return
+			case now := <-ticker.C:
+				os.Chtimes(path, now, now)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-stopped
+	}
+}
+
+// locked wraps a tool that writes to the project so that its calls hold the project lock.
+// A call that had to wait for another one says so in its message.
+func locked[T any](fn func(*Registry, T) (types.ScaffoldResult, error)) func(*Registry, T) (types.ScaffoldResult, error) {
+	return func(registry *Registry, input T) (types.ScaffoldResult, error) {
+		unlock, waited, retries, err := registry.lockProject()
+		if err != nil {
+			return types.NewErrorResult(err.Error()), nil
+		}
+		defer unlock()
+
+		result, err := fn(registry, input)
+		if err == nil && retries > 0 {
+			result.Message += fmt.Sprintf(" (waited %s, %d retries, for another call writing to this project)", waited.Round(time.Millisecond), retries)
+		}
+		return result, err
+	}
+}
@@ -0,0 +1,94 @@
+package tools
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestLockProject(t *testing.T) {
+	registry, _ := testRegistry(t)
+
+	unlock, _, retries, err := registry.lockProject()
+	if err != nil {
+		t.Fatalf("lockProject() error = %v", err)
+	}
+	if retries != 0 {
+		t.Errorf("retries = %d on a free lock, want 0", retries)
+	}
+	if _, err := os.Stat(projectLockPath(registry.WorkingDir)); err != nil {
+		t.Errorf("lock file missing while held: %v", err)
+	}
+
+	// A second call waits for the first and reports it.
+	done := make(chan types.ScaffoldResult)
+	go func() {
+		result, _ := locked(func(*Registry, struct{}) (types.ScaffoldResult, error) {
+			return types.ScaffoldResult{Success: true, Message: "done"}, nil
+		})(registry, struct{}{})
+		done <- result
+	}()
+	time.Sleep(50 * time.Millisecond)
+	unlock()
+
+	result := <-done
+	if !result.Success || !strings.Contains(result.Message, "for another call writing to this project") {
+		t.Errorf("Message = %q, want a note about waiting for the lock", result.Message)
+	}
+	if _, err := os.Stat(projectLockPath(registry.WorkingDir)); !os.IsNotExist(err) {
+		t.Errorf("lock file left behind after unlock: %v", err)
+	}
+}
+
+func TestLockProject_Stale(t *testing.T) {
+	registry, _ := testRegistry(t)
+
+	path := projectLockPath(registry.WorkingDir)
+	if err := os.WriteFile(path, []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * lockStale)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, _, retries, err := registry.lockProject()
+	if err != nil {
+		t.Fatalf("lockProject() error = %v", err)
+	}
+	defer unlock()
+	if retries != 0 {
+		t.Errorf("retries = %d, want a stale lock taken over at once", retries)
+	}
+}
+
+func TestKeepLockFresh(t *testing.T) {
+	registry, _ := testRegistry(t)
+
+	unlock, _, _, err := registry.lockProject()
+	if err != nil {
+		t.Fatalf("lockProject() error = %v", err)
+	}
+	defer unlock()
+
+	// A call that has held the lock for longer than lockStale keeps it while it runs.
+	path := projectLockPath(registry.WorkingDir)
+	old := time.Now().Add(-2 * lockStale)
+	if err := os.Chtimes(path, old, old); err != nil {
+		t.Fatal(err)
+	}
+	stop := keepLockFresh(path, 10*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+	stop()
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if age := time.Since(info.ModTime()); age > lockStale {
+		t.Errorf("lock file is %s old while held, want it refreshed", age.Round(time.Second))
+	}
+}
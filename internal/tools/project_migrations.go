@@ -0,0 +1,168 @@
+package tools
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// projectMigration moves a generated project to the template layout of a scaffolder version.
+type projectMigration struct {
+	// version is the ScaffolderVersion that introduced the layout.
+	version string
+	// description says what changed in the layout.
+	description string
+	steps       []migrationStep
+}
+
+// migrationStep is one scripted change of a migration. It returns what it changed in the
+// project, nothing when the project doesn't have what the step rewrites.
+type migrationStep func(f *migrationFiles) ([]string, error)
+
+// projectMigrations are the migrations upgrade_project applies, ordered by version.
+// Add one whenever ScaffolderVersion bumps with a change to the layout of generated projects,
+// such as a renamed marker, a moved file or a moved package:
+//
+//	{
+//		version:     "0.2.0",
+//		description: "Routes moved to internal/web/routes",
+//		steps: []migrationStep{
+//			moveFile("internal/web/router.go", "internal/web/routes/router.go"),
+//			rewriteImport("internal/web/router", "internal/web/routes"),
+//			renameMarker("ROUTES:PUBLIC", "ROUTES:GUEST"),
+//		},
+//	},
+var projectMigrations = []projectMigration{}
+
+// migrationFiles holds the files of a project while migrations run, so nothing is written
+// unless every step succeeds.
+type migrationFiles struct {
+	dir        string
+	modulePath string
+	// paths are the project files steps rewrite, relative to dir.
+	paths   []string
+	content map[string]string
+	changed map[string]bool
+	// moved maps the new path of each moved file to its original path.
+	moved map[string]string
+}
+
+// newMigrationFiles reads the files of the project in dir that migrations rewrite.
+func newMigrationFiles(dir, modulePath string) (*migrationFiles, error) {
+	paths, err := upgradeStackFiles(dir)
+	if err != nil {
+		return nil, err
+	}
+	f := &migrationFiles{
+		dir:        dir,
+		modulePath: modulePath,
+		paths:      paths,
+		content:    map[string]string{},
+		changed:    map[string]bool{},
+		moved:      map[string]string{},
+	}
+	for _, path := range paths {
+		data, err := os.ReadFile(filepath.Join(dir, path))
+		if err != nil {
+			return nil, err
+		}
+		f.content[path] = string(data)
+	}
+	return f, nil
+}
+
+// exists reports whether a file is in the project, after the moves so far.
+func (f *migrationFiles) exists(path string) bool {
+	if _, ok := f.content[path]; ok {
+		return true
+	}
+	if slices.Contains(slices.Collect(maps.Values(f.moved)), path) {
+		return false
+	}
+	_, err := os.Stat(filepath.Join(f.dir, path))
+	return err == nil
+}
+
+// replaceAll applies a regular expression replacement to the Go and templ files.
+func (f *migrationFiles) replaceAll(pattern *regexp.Regexp, replacement string) []string {
+	var changes []string
+	for _, path := range f.paths {
+		if !strings.HasSuffix(path, ".go") && !strings.HasSuffix(path, ".templ") {
+			continue
+		}
+		content := f.content[path]
+		if updated := pattern.ReplaceAllString(content, replacement); updated != content {
+			f.content[path] = updated
+			f.changed[path] = true
+			changes = append(changes, filepath.ToSlash(path))
+		}
+	}
+	return changes
+}
+
+// renameMarker renames a pair of MCP markers, e.g. "ROUTES:PUBLIC" to "ROUTES:GUEST".
+func renameMarker(from, to string) migrationStep {
+	pattern := regexp.MustCompile(`MCP:` + regexp.QuoteMeta(from) + `:(START|END)\b`)
+	return func(f *migrationFiles) ([]string, error) {
+		var changes []string
+		for _, path := range f.replaceAll(pattern, "MCP:"+to+":$1") {
+			changes = append(changes, fmt.Sprintf("%s: renamed marker MCP:%s to MCP:%s", path, from, to))
+		}
+		return changes, nil
+	}
+}
+
+// rewriteImport rewrites the imports of a package of the project, and of the packages under
+// it, to a new path. Both paths are relative to the module, e.g. "internal/web/router".
+func rewriteImport(from, to string) migrationStep {
+	return func(f *migrationFiles) ([]string, error) {
+		pattern := regexp.MustCompile(`"` + regexp.QuoteMeta(f.modulePath+"/"+from) + `((?:/[^"]*)?)"`)
+		var changes []string
+		for _, path := range f.replaceAll(pattern, `"`+f.modulePath+"/"+to+`$1"`) {
+			changes = append(changes, fmt.Sprintf("%s: import %s is now %s", path, from, to))
+		}
+		return changes, nil
+	}
+}
+
+// moveFile moves a file of the project. A project without the file is left alone, since it
+// was moved or removed by hand; a project that has both files is an error.
+func moveFile(from, to string) migrationStep {
+	from, to = filepath.FromSlash(from), filepath.FromSlash(to)
+	return func(f *migrationFiles) ([]string, error) {
+		if !f.exists(from) {
+			return nil, nil
+		}
+		if f.exists(to) {
+			return nil, fmt.Errorf("cannot move %s: %s already exists", filepath.ToSlash(from), filepath.ToSlash(to))
+		}
+
+		content, ok := f.content[from]
+		if !ok {
+			data, err := os.ReadFile(filepath.Join(f.dir, from))
+			if err != nil {
+				return nil, err
+			}
+			content = string(data)
+		}
+
+		// Keep track of the original of a file moved twice
+		original := from
+		if earlier, ok := f.moved[from]; ok {
+			original = earlier
+			delete(f.moved, from)
+		}
+		f.moved[to] = original
+
+		delete(f.content, from)
+		delete(f.changed, from)
+		f.paths = slices.DeleteFunc(f.paths, func(p string) bool { return p == from })
+		f.content[to] = content
+		f.paths = append(f.paths, to)
+		return []string{fmt.Sprintf("moved %s to %s", filepath.ToSlash(from), filepath.ToSlash(to))}, nil
+	}
+}
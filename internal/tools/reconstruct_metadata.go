@@ -0,0 +1,857 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterReconstructMetadata registers the reconstruct_metadata tool.
+func RegisterReconstructMetadata(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "reconstruct_metadata",
+		Description: `Rebuild the scaffold metadata of domains scaffolded before metadata tracking existed.
+
+analyze_domain and sync_domain compare a domain with what its scaffold_domain input generates,
+so they only work for domains recorded in .mcp/scaffold-metadata.json. reconstruct_metadata
+reads the code of a domain and rebuilds that input:
+- internal/models/{domain}.go: fields, JSON names, GORM tags, required fields, relationships,
+  soft delete, tenant scoping, optimistic locking, moderation, scheduling and full-text search
+- internal/web/{domain}/{domain}.go: layout, bulk actions and inline editing
+- internal/web/{domain}/views/{domain}_form.templ: form types, labels, select options and form style
+- cmd/web/main.go: the route group the controller is mounted in
+
+The input is checked by regenerating the domain: files_matching are regenerated exactly,
+files_differing were edited by hand or predate template changes (see analyze_domain).
+notes lists what the code shows but the input doesn't capture, such as list filters.
+
+Domains that already have metadata are skipped unless overwrite is set. Reconstructed
+domains are marked "reconstructed" in the metadata, with no scaffolder version.
+
+Examples:
+  reconstruct_metadata: { dry_run: true }
+  reconstruct_metadata: { domains: ["order_item"] }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ReconstructMetadataInput) (*mcp.CallToolResult, types.ReconstructMetadataResult, error) {
+		call := registry.For(ctx)
+		unlock, _, _, err := call.lockProject()
+		if err != nil {
+			return nil, types.ReconstructMetadataResult{Success: false, Message: err.Error()}, nil
+		}
+		defer unlock()
+		result, err := reconstructMetadata(call, input)
+		if err != nil {
+			return nil, types.ReconstructMetadataResult{Success: false, Message: err.Error()}, nil
+		}
+		return nil, result, nil
+	})
+}
+
+// legacyDomain is a domain found in the code: a model with a controller.
+type legacyDomain struct {
+	name      string
+	pkg       string
+	modelName string
+	table     string
+}
+
+func reconstructMetadata(registry *Registry, input types.ReconstructMetadataInput) (types.ReconstructMetadataResult, error) {
+	if _, err := utils.GetModulePath(registry.WorkingDir); err != nil {
+		return types.ReconstructMetadataResult{Success: false, Message: "go.mod not found: run reconstruct_metadata from the root of a generated project"}, nil
+	}
+
+	store := metadata.NewStore(registry.WorkingDir)
+	meta, err := store.Load()
+	if err != nil {
+		return types.ReconstructMetadataResult{Success: false, Message: fmt.Sprintf("failed to read project metadata: %v", err)}, nil
+	}
+
+	found, err := findLegacyDomains(registry.WorkingDir)
+	if err != nil {
+		return types.ReconstructMetadataResult{Success: false, Message: fmt.Sprintf("failed to scan models: %v", err)}, nil
+	}
+	domains := found
+	if len(input.Domains) > 0 {
+		domains = nil
+		for _, name := range input.Domains {
+			i := slices.IndexFunc(found, func(d legacyDomain) bool {
+				return d.name == name || d.pkg == utils.ToPackageName(name)
+			})
+			if i < 0 {
+				pkg := utils.ToPackageName(name)
+				return types.ReconstructMetadataResult{Success: false, Message: fmt.Sprintf("no domain '%s' found: expected a model in internal/models/%s.go and a controller in internal/web/%s/%s.go", name, pkg, pkg, pkg)}, nil
+			}
+			domains = append(domains, found[i])
+		}
+	}
+
+	mainContent, _ := utils.ReadFileString(filepath.Join(registry.WorkingDir, "cmd", "web", "main.go"))
+
+	result := types.ReconstructMetadataResult{Success: true, DryRun: input.DryRun}
+	for _, d := range domains {
+		if _, exists := meta.Domains[d.name]; exists && !input.Overwrite {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: already has metadata (pass overwrite to replace it)", d.name))
+			continue
+		}
+		domain, err := reconstructDomain(registry, d, mainContent)
+		if err != nil {
+			result.Skipped = append(result.Skipped, fmt.Sprintf("%s: %v", d.name, err))
+			continue
+		}
+		if !input.DryRun {
+			if err := store.SaveReconstructedDomain(d.name, domain.Input); err != nil {
+				return types.ReconstructMetadataResult{Success: false, Message: fmt.Sprintf("failed to save metadata for '%s': %v", d.name, err)}, nil
+			}
+			domain.Saved = true
+		}
+		result.Domains = append(result.Domains, domain)
+	}
+
+	switch {
+	case len(result.Domains) == 0 && len(result.Skipped) == 0:
+		result.Message = "No domains found: reconstruct_metadata looks for models in internal/models with a controller in internal/web"
+	case input.DryRun:
+		result.Message = fmt.Sprintf("Dry run: Would reconstruct the metadata of %d domain(s)", len(result.Domains))
+	default:
+		result.Message = fmt.Sprintf("Reconstructed the metadata of %d domain(s); run analyze_domain to review the differences with the templates", len(result.Domains))
+	}
+	if len(result.Skipped) > 0 {
+		result.Message += fmt.Sprintf(", %d skipped", len(result.Skipped))
+	}
+	return result, nil
+}
+
+// findLegacyDomains lists the models in internal/models that have a controller in
+// internal/web/{package}, sorted by domain name.
+func findLegacyDomains(dir string) ([]legacyDomain, error) {
+	files, err := utils.ListFiles(filepath.Join(dir, "internal", "models"), "*.go")
+	if err != nil {
+		return nil, err
+	}
+
+	var domains []legacyDomain
+	for _, path := range files {
+		pkg := strings.TrimSuffix(filepath.Base(path), ".go")
+		if strings.HasSuffix(pkg, "_test") || !utils.FileExists(filepath.Join(dir, "internal", "web", pkg, pkg+".go")) {
+			continue
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+		}
+		for modelName, table := range tableNames(file) {
+			// The domain model is the one named after the file; join models have their own table
+			name := utils.Singularize(table)
+			if utils.ToPackageName(name) != pkg {
+				name = utils.ToSnakeCase(modelName)
+			}
+			if utils.ToPackageName(name) == pkg {
+				domains = append(domains, legacyDomain{name: name, pkg: pkg, modelName: modelName, table: table})
+			}
+		}
+	}
+	slices.SortFunc(domains, func(a, b legacyDomain) int { return strings.Compare(a.name, b.name) })
+	return domains, nil
+}
+
+// tableNames maps each model of a file to the table its TableName method returns.
+func tableNames(file *ast.File) map[string]string {
+	tables := map[string]string{}
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok || fn.Name.Name != "TableName" || fn.Recv == nil || len(fn.Recv.List) != 1 || fn.Body == nil || len(fn.Body.List) != 1 {
+			continue
+		}
+		recv, ok := fn.Recv.List[0].Type.(*ast.Ident)
+		if !ok {
+			continue
+		}
+		ret, ok := fn.Body.List[0].(*ast.ReturnStmt)
+		if !ok || len(ret.Results) != 1 {
+			continue
+		}
+		if table, ok := stringLiteral(ret.Results[0]); ok {
+			tables[recv.Name] = table
+		}
+	}
+	return tables
+}
+
+// stringLiteral returns the value of a string literal expression.
+func stringLiteral(expr ast.Expr) (string, bool) {
+	lit, ok := expr.(*ast.BasicLit)
+	if !ok || lit.Kind != token.STRING {
+		return "", false
+	}
+	value, err := strconv.Unquote(lit.Value)
+	return value, err == nil
+}
+
+// reconstructDomain rebuilds the scaffold input of a domain from its code and checks it by
+// regenerating the domain's files.
+func reconstructDomain(registry *Registry, d legacyDomain, mainContent string) (types.ReconstructedDomain, error) {
+	domain := types.ReconstructedDomain{Domain: d.name, Input: types.ScaffoldDomainInput{DomainName: d.name}}
+	in := &domain.Input
+	if d.table != utils.ToTableName(d.name) {
+		in.TableName = d.table
+	}
+
+	if err := readModel(filepath.Join(registry.WorkingDir, "internal", "models", d.pkg+".go"), d.modelName, in); err != nil {
+		return domain, err
+	}
+	if len(in.Fields) == 0 {
+		return domain, fmt.Errorf("model %s has no fields of its own", d.modelName)
+	}
+
+	webDir := filepath.Join(registry.WorkingDir, "internal", "web", d.pkg)
+	formPath := filepath.Join(webDir, "views", d.pkg+"_form.templ")
+	withViews := utils.FileExists(formPath)
+	if !withViews {
+		in.WithCrudViews = &withViews
+	} else {
+		form, err := utils.ReadFileString(formPath)
+		if err != nil {
+			return domain, err
+		}
+		readFormView(form, in)
+	}
+
+	notes, err := readController(filepath.Join(webDir, d.pkg+".go"), withViews, in)
+	if err != nil {
+		return domain, err
+	}
+	domain.Notes = notes
+
+	if _, group, ok := registeredRoute(registry.WorkingDir, mainContent, d.pkg); ok && group != "public" {
+		in.RouteGroup = group
+	}
+	if m := routeMountRegex.FindAllStringSubmatchIndex(mainContent, -1); m != nil {
+		for _, mount := range m {
+			if mainContent[mount[4]:mount[5]] == utils.ToControllerVariableName(d.name) {
+				if group := routeGroupAt(mainContent, mount[0]); group != "public" {
+					in.RouteGroup = group
+				}
+			}
+		}
+	}
+
+	// Authenticated domains are owned by default, so only record the exception
+	if in.RouteGroup == "authenticated" {
+		if in.OwnedByUser != nil {
+			in.OwnedByUser = nil
+		} else {
+			notOwned := false
+			in.OwnedByUser = &notOwned
+		}
+	}
+
+	readDomainFiles(registry.WorkingDir, d.pkg, in)
+	domain.Notes = append(domain.Notes, readBulkActions(registry.WorkingDir, d.pkg, in)...)
+
+	// Check the input by regenerating the domain
+	gen, err := generateDomainFiles(registry, *in)
+	if err != nil {
+		return domain, err
+	}
+	result := gen.Result()
+	for _, path := range append(result.FilesCreated, result.FilesUpdated...) {
+		existing, err := os.ReadFile(filepath.Join(registry.WorkingDir, path))
+		if generated := gen.GetFileContent(path); err == nil && string(existing) == keepInjectedLines(string(existing), generated) {
+			domain.FilesMatching = append(domain.FilesMatching, path)
+		} else {
+			domain.FilesDiffering = append(domain.FilesDiffering, path)
+		}
+	}
+	return domain, nil
+}
+
+// readModel reads the fields, relationships and model options of a domain from its model file.
+func readModel(path, modelName string, in *types.ScaffoldDomainInput) error {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.ParseComments)
+	if err != nil {
+		return fmt.Errorf("failed to parse %s: %w", filepath.Base(path), err)
+	}
+
+	structs := map[string]*ast.StructType{}
+	var consts []*ast.ValueSpec
+	for _, decl := range file.Decls {
+		gen, ok := decl.(*ast.GenDecl)
+		if !ok {
+			continue
+		}
+		for _, spec := range gen.Specs {
+			switch s := spec.(type) {
+			case *ast.TypeSpec:
+				if st, ok := s.Type.(*ast.StructType); ok {
+					structs[s.Name.Name] = st
+				}
+			case *ast.ValueSpec:
+				if gen.Tok == token.CONST {
+					consts = append(consts, s)
+				}
+			}
+		}
+	}
+	model, ok := structs[modelName]
+	if !ok {
+		return fmt.Errorf("struct %s not found in %s", modelName, filepath.Base(path))
+	}
+
+	// The "// Relationships" comment separates the fields from the relationship fields;
+	// fields between the relationship markers were injected by other domains
+	relStart, injectedStart := model.End(), model.End()
+	for _, group := range file.Comments {
+		if group.Pos() < model.Pos() || group.End() > model.End() {
+			continue
+		}
+		switch text := group.Text(); {
+		case strings.TrimSpace(text) == "Relationships":
+			relStart = group.Pos()
+		case strings.Contains(text, "MCP:RELATIONSHIPS:START"):
+			injectedStart = group.Pos()
+		}
+	}
+
+	softDelete := false
+	var relFields []*ast.Field
+	for _, field := range model.Fields.List {
+		if len(field.Names) != 1 {
+			continue
+		}
+		// Earlier versions of scaffold_tenancy put TenantID with the injected fields
+		if field.Pos() > injectedStart && isTenantIDField(field) {
+			in.TenantScoped = true
+		}
+		if field.Pos() > injectedStart {
+			continue
+		}
+		if field.Pos() > relStart {
+			relFields = append(relFields, field)
+			continue
+		}
+		name, typ := field.Names[0].Name, exprToString(field.Type)
+		switch {
+		case name == "ID" || name == "CreatedAt" || name == "UpdatedAt":
+		case name == "DeletedAt" && typ == "gorm.DeletedAt":
+			softDelete = true
+		case isTenantIDField(field):
+			in.TenantScoped = true
+		case name == "UserID" && structTag(field).Get("gorm") == "not null;index" && structTag(field).Get("json") == "user_id":
+			owned := true
+			in.OwnedByUser = &owned
+		case name == "Version" && structTag(field).Get("gorm") == "not null;default:1":
+			in.WithOptimisticLocking = true
+		case name == "ModerationStatus" && strings.Contains(structTag(field).Get("gorm"), "default:pending"):
+			in.WithModeration = true
+		case (name == "PublishAt" || name == "UnpublishAt") && typ == "*time.Time":
+		case name == "PublishState" && strings.Contains(structTag(field).Get("gorm"), "default:draft"):
+			in.WithScheduling = true
+		case name == "SearchSnippet" && structTag(field).Get("json") == "-":
+			in.Search = "fulltext"
+		default:
+			in.Fields = append(in.Fields, modelField(name, typ, structTag(field), structs))
+		}
+	}
+	if !softDelete {
+		in.WithSoftDelete = &softDelete
+	}
+
+	in.Relationships = modelRelationships(in.DomainName, modelName, relFields, structs, consts)
+
+	if in.Search == "fulltext" {
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		in.SearchFields = fullTextSearchFields(string(content), in.Fields)
+	}
+	return nil
+}
+
+// isTenantIDField reports whether a model field is the TenantID of a tenant-scoped domain.
+func isTenantIDField(field *ast.Field) bool {
+	return field.Names[0].Name == "TenantID" && structTag(field).Get("json") == "-"
+}
+
+// structTag returns the tag of a struct field.
+func structTag(field *ast.Field) reflect.StructTag {
+	if field.Tag == nil {
+		return ""
+	}
+	tag, _ := strconv.Unquote(field.Tag.Value)
+	return reflect.StructTag(tag)
+}
+
+// jsonName returns the name of a json tag and whether it has omitempty.
+func jsonName(tag reflect.StructTag) (string, bool) {
+	parts := strings.Split(tag.Get("json"), ",")
+	return parts[0], slices.Contains(parts[1:], "omitempty")
+}
+
+// gormSettings splits a gorm tag into its settings, e.g. "foreignKey:UserID;references:ID".
+func gormSettings(tag reflect.StructTag) map[string]string {
+	settings := map[string]string{}
+	for _, part := range strings.Split(tag.Get("gorm"), ";") {
+		key, value, _ := strings.Cut(part, ":")
+		if key != "" {
+			settings[key] = value
+		}
+	}
+	return settings
+}
+
+// modelField returns the field definition of a model field. Fields without omitempty are required.
+func modelField(name, typ string, tag reflect.StructTag, structs map[string]*ast.StructType) types.FieldDef {
+	json, omitempty := jsonName(tag)
+	field := types.FieldDef{Name: name, Type: typ, GORMTags: tag.Get("gorm"), Required: !omitempty}
+	if json != utils.ToJSONTag(name) {
+		field.JSONTag = json
+	}
+
+	// Embedded value types are stored as prefixed columns
+	prefix := "embedded;embeddedPrefix:" + utils.ToSnakeCase(name) + "_"
+	if st, ok := structs[typ]; ok && strings.HasPrefix(field.GORMTags, prefix) {
+		field.GORMTags = strings.TrimPrefix(strings.TrimPrefix(field.GORMTags, prefix), ";")
+		field.Required = false
+		for _, sub := range st.Fields.List {
+			if len(sub.Names) != 1 {
+				continue
+			}
+			subJSON, subOmitempty := jsonName(structTag(sub))
+			embedded := types.EmbeddedFieldDef{Name: sub.Names[0].Name, Type: exprToString(sub.Type), Required: !subOmitempty}
+			if subJSON != utils.ToJSONTag(embedded.Name) {
+				embedded.JSONTag = subJSON
+			}
+			field.Embedded = append(field.Embedded, embedded)
+		}
+	}
+	return field
+}
+
+// modelRelationships rebuilds the relationships of a model from the fields after its
+// "// Relationships" comment.
+func modelRelationships(domainName, modelName string, fields []*ast.Field, structs map[string]*ast.StructType, consts []*ast.ValueSpec) []types.RelationshipDef {
+	names := map[string]bool{}
+	for _, field := range fields {
+		names[field.Names[0].Name] = true
+	}
+
+	var rels []types.RelationshipDef
+	for i := 0; i < len(fields); i++ {
+		name, typ := fields[i].Names[0].Name, exprToString(fields[i].Type)
+		tag := structTag(fields[i])
+		gorm := gormSettings(tag)
+		onDelete := strings.TrimPrefix(gorm["constraint"], "OnDelete:")
+
+		switch {
+		case typ == "*uint":
+			// belongs_to self: ParentID, Parent and Children
+			rel := types.RelationshipDef{Type: "belongs_to", Model: "self"}
+			if name != "ParentID" {
+				rel.ForeignKey = name
+			}
+			for i+1 < len(fields) && (exprToString(fields[i+1].Type) == "*"+modelName || exprToString(fields[i+1].Type) == "[]"+modelName) {
+				i++
+			}
+			rels = append(rels, rel)
+
+		case typ == "uint" && strings.HasPrefix(gorm["index"], "idx_"):
+			// polymorphic: {Name}ID and {Name}Type, with a constant per owner type
+			poly := strings.TrimSuffix(name, "ID")
+			rel := types.RelationshipDef{Type: "polymorphic", Model: poly}
+			for _, spec := range consts {
+				for _, ident := range spec.Names {
+					if owner, ok := strings.CutPrefix(ident.Name, modelName+poly); ok && owner != "" {
+						rel.Models = append(rel.Models, owner)
+					}
+				}
+			}
+			if i+1 < len(fields) && fields[i+1].Names[0].Name == poly+"Type" {
+				i++
+			}
+			rels = append(rels, rel)
+
+		case typ == "uint":
+			// The foreign key of a belongs_to, read with its model
+
+		case strings.HasPrefix(typ, "*"):
+			related := strings.TrimPrefix(typ, "*")
+			foreignKey := gorm["foreignKey"]
+			rel := types.RelationshipDef{Type: "has_one", Model: related}
+			if names[foreignKey] {
+				rel.Type = "belongs_to"
+				if foreignKey != related+"ID" {
+					rel.ForeignKey = foreignKey
+				}
+			} else {
+				if foreignKey != modelName+"ID" {
+					rel.ForeignKey = foreignKey
+				}
+				if onDelete != "" && onDelete != "CASCADE" {
+					rel.OnDelete = onDelete
+				}
+			}
+			if references := gorm["references"]; references != "" && references != "ID" {
+				rel.References = references
+			}
+			rels = append(rels, rel)
+
+		case strings.HasPrefix(typ, "[]"):
+			related := strings.TrimPrefix(typ, "[]")
+			if foreignKey, ok := gorm["foreignKey"]; ok {
+				rel := types.RelationshipDef{Type: "has_many", Model: related}
+				if foreignKey != modelName+"ID" {
+					rel.ForeignKey = foreignKey
+				}
+				if references := gorm["references"]; references != "" && references != "ID" {
+					rel.References = references
+				}
+				if onDelete != "" && onDelete != "CASCADE" {
+					rel.OnDelete = onDelete
+				}
+				rels = append(rels, rel)
+				continue
+			}
+			rel := types.RelationshipDef{Type: "many_to_many", Model: related, JoinTable: gorm["many2many"]}
+			if join, ok := structs[modelName+related]; ok {
+				for _, field := range join.Fields.List {
+					if len(field.Names) != 1 {
+						continue
+					}
+					switch field.Names[0].Name {
+					case modelName + "ID", related + "ID", "CreatedAt", related:
+						continue
+					}
+					rel.JoinFields = append(rel.JoinFields, modelField(field.Names[0].Name, exprToString(field.Type), structTag(field), structs))
+				}
+				if rel.JoinTable == generator.DefaultJoinTable(domainName, related) {
+					rel.JoinTable = ""
+				}
+			}
+			rels = append(rels, rel)
+		}
+	}
+	return rels
+}
+
+// ftsColumnsRegex matches the columns of the SQLite full-text table of a model.
+var ftsColumnsRegex = regexp.MustCompile(`USING fts5\(([^)]*), content=`)
+
+// fullTextSearchFields returns the search fields of a model with full-text search, nil when
+// they are the default: every string field.
+func fullTextSearchFields(content string, fields []types.FieldDef) []string {
+	m := ftsColumnsRegex.FindStringSubmatch(content)
+	if m == nil {
+		return nil
+	}
+	var searchFields, defaults []string
+	for _, column := range strings.Split(m[1], ",") {
+		for _, field := range fields {
+			if utils.ToSnakeCase(field.Name) == strings.TrimSpace(column) {
+				searchFields = append(searchFields, field.Name)
+			}
+		}
+	}
+	for _, field := range fields {
+		if field.Type == "string" && len(field.Embedded) == 0 && field.FormType != "image" {
+			defaults = append(defaults, field.Name)
+		}
+	}
+	if slices.Equal(searchFields, defaults) {
+		return nil
+	}
+	return searchFields
+}
+
+var (
+	// formFieldRegex matches the block of a field in a form view, from its comment to its error.
+	formFieldRegex = regexp.MustCompile(`(?s)<!-- ([^\n]+?) Field -->\s*<div class="space-y-2"(?: \{ components\.FieldValidation\(props\.Errors\["\w+"\]\)\.\.\. \})?>\s*@components\.Label\("(\w+)", \w+\) \{(.*?)@components\.(?:FieldError|FormError)\(props\.Errors\["(\w+)"\]\)`)
+	// formRuleRegex matches a validation attribute of an input component.
+	formRuleRegex = regexp.MustCompile(`"(minlength|maxlength|min|max|pattern)": ("(?:[^"\\]|\\.)*")`)
+	// formInputTypeRegex matches the type of an input component.
+	formInputTypeRegex = regexp.MustCompile(`Type:\s+"([\w-]+)"`)
+	// formOptionRegex matches an option of a select field.
+	formOptionRegex = regexp.MustCompile(`value="([^"]+)"`)
+	// formDisplayFieldRegex matches the field shown in a relationship select.
+	formDisplayFieldRegex = regexp.MustCompile(`(?s)range props\.(\w+)Options \{.*?\{ opt\.(\w+) \}`)
+	// formNestedRegex matches the rows of a has_many relationship edited in the form.
+	formNestedRegex = regexp.MustCompile(`<!-- (\w+) Rows -->`)
+)
+
+// formInputTypes maps the input types of form views to their form_type.
+var formInputTypes = map[string]string{
+	"file":           "image",
+	"number":         "number",
+	"email":          "email",
+	"password":       "password",
+	"date":           "date",
+	"datetime-local": "datetime",
+	"text":           "input",
+}
+
+// readFormView reads the form style, and the form type, label and options of each field,
+// from a domain's form view.
+func readFormView(form string, in *types.ScaffoldDomainInput) {
+	if strings.Contains(form, "// Page-style form layout") {
+		in.FormStyle = "page"
+	}
+
+	for _, m := range formFieldRegex.FindAllStringSubmatch(form, -1) {
+		label, json, block := m[1], m[2], m[3]
+		i := slices.IndexFunc(in.Fields, func(f types.FieldDef) bool {
+			return f.JSONTag == json || (f.JSONTag == "" && utils.ToJSONTag(f.Name) == json)
+		})
+		if i < 0 {
+			continue
+		}
+		field := &in.Fields[i]
+
+		formType := ""
+		switch {
+		case strings.Contains(block, "@components.Textarea("):
+			formType = "textarea"
+		case strings.Contains(block, "@components.Checkbox("):
+			formType = "checkbox"
+		case strings.Contains(block, "@components.Select("):
+			formType = "select"
+			for _, option := range formOptionRegex.FindAllStringSubmatch(block, -1) {
+				field.Options = append(field.Options, option[1])
+			}
+		default:
+			if t := formInputTypeRegex.FindStringSubmatch(block); t != nil {
+				formType = formInputTypes[t[1]]
+			}
+		}
+
+		// Keep only what differs from the defaults
+		defaults := generator.NewFieldData(types.FieldDef{Name: field.Name, Type: field.Type})
+		if formType != "" && formType != defaults.FormType {
+			field.FormType = formType
+		}
+		if label != defaults.Label {
+			field.Label = label
+		}
+
+		for _, rule := range formRuleRegex.FindAllStringSubmatch(block, -1) {
+			value, err := strconv.Unquote(rule[2])
+			if err != nil {
+				continue
+			}
+			switch rule[1] {
+			case "pattern":
+				field.Pattern = value
+			case "min", "minlength":
+				field.Min = parseBound(value)
+			case "max", "maxlength":
+				field.Max = parseBound(value)
+			}
+		}
+	}
+
+	for _, m := range formDisplayFieldRegex.FindAllStringSubmatch(form, -1) {
+		for i, rel := range in.Relationships {
+			if m[2] != "Name" && ((rel.Type == "belongs_to" && rel.Model == m[1]) || (rel.Model == "self" && m[1] == "Parent")) {
+				in.Relationships[i].DisplayField = m[2]
+			}
+		}
+	}
+	for _, m := range formNestedRegex.FindAllStringSubmatch(form, -1) {
+		for i, rel := range in.Relationships {
+			if rel.Type == "has_many" && utils.Pluralize(rel.Model) == m[1] {
+				in.Relationships[i].NestedForm = true
+			}
+		}
+	}
+}
+
+// parseBound parses the value of a min or max attribute, or returns nil when it is not a number.
+func parseBound(value string) *float64 {
+	bound, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return nil
+	}
+	return &bound
+}
+
+// readController reads the layout, bulk actions and inline editable fields of a domain from
+// its controller. It returns notes on what the input can't capture.
+func readController(path string, withViews bool, in *types.ScaffoldDomainInput) ([]string, error) {
+	file, err := parser.ParseFile(token.NewFileSet(), path, nil, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse controller: %w", err)
+	}
+
+	var notes, filters []string
+	layouts := map[string]bool{}
+	ast.Inspect(file, func(n ast.Node) bool {
+		switch node := n.(type) {
+		case *ast.SelectorExpr:
+			if x, ok := node.X.(*ast.Ident); ok && x.Name == "layouts" {
+				layouts[node.Sel.Name] = true
+			}
+		case *ast.FuncDecl:
+			if node.Recv == nil || node.Name.Name != "Bulk" {
+				return true
+			}
+			in.WithBulkActions = true
+			ast.Inspect(node.Body, func(n ast.Node) bool {
+				clause, ok := n.(*ast.CaseClause)
+				if !ok {
+					return true
+				}
+				for _, expr := range clause.List {
+					if action, ok := stringLiteral(expr); ok && action != "delete" && action != "export" {
+						in.BulkActions = append(in.BulkActions, types.BulkActionDef{Name: action})
+					}
+				}
+				return true
+			})
+		case *ast.AssignStmt:
+			for _, lhs := range node.Lhs {
+				if sel, ok := lhs.(*ast.SelectorExpr); ok {
+					if x, ok := sel.X.(*ast.Ident); ok && x.Name == "filter" && !slices.Contains(filters, sel.Sel.Name) {
+						filters = append(filters, sel.Sel.Name)
+					}
+				}
+			}
+		case *ast.ValueSpec:
+			if len(node.Names) != 1 || node.Names[0].Name != "inlineEditableFields" || len(node.Values) != 1 {
+				return true
+			}
+			lit, ok := node.Values[0].(*ast.CompositeLit)
+			if !ok {
+				return true
+			}
+			for _, elt := range lit.Elts {
+				kv, ok := elt.(*ast.KeyValueExpr)
+				if !ok {
+					continue
+				}
+				json, _ := stringLiteral(kv.Key)
+				for _, field := range in.Fields {
+					if field.JSONTag == json || (field.JSONTag == "" && utils.ToJSONTag(field.Name) == json) {
+						in.InlineEdit = append(in.InlineEdit, field.Name)
+					}
+				}
+			}
+		}
+		return true
+	})
+
+	if withViews {
+		switch {
+		case layouts["DashboardPage"]:
+		case layouts["BasePage"]:
+			in.Layout = "base"
+		default:
+			in.Layout = "none"
+		}
+	}
+	if len(filters) > 0 {
+		notes = append(notes, fmt.Sprintf("the list filters on %s: add them to filters with their widgets", strings.Join(filters, ", ")))
+	}
+	return notes, nil
+}
+
+// readDomainFiles reads the options that add files or imports to a domain: repository tests,
+// the repository cache, realtime events, service observability and default preloads.
+func readDomainFiles(dir, pkg string, in *types.ScaffoldDomainInput) {
+	repoDir := filepath.Join(dir, "internal", "repository", pkg)
+	in.WithTests = utils.FileExists(filepath.Join(repoDir, pkg+"_test.go"))
+	in.WithCache = utils.FileExists(filepath.Join(repoDir, "cache.go"))
+	in.WithRealtime = utils.FileExists(filepath.Join(dir, "internal", "services", pkg, "realtime.go"))
+
+	if service, err := utils.ReadFileString(filepath.Join(dir, "internal", "services", pkg, pkg+".go")); err == nil {
+		in.WithObservability = strings.Contains(service, `"go.opentelemetry.io/otel"`)
+	}
+
+	if model, err := utils.ReadFileString(filepath.Join(dir, "internal", "models", pkg+".go")); err == nil {
+		for _, m := range hookDispatchRegex.FindAllStringSubmatch(model, -1) {
+			in.WithHooks = append(in.WithHooks, m[1])
+		}
+	}
+
+	repo, err := utils.ReadFileString(filepath.Join(repoDir, pkg+".go"))
+	if err != nil {
+		return
+	}
+	for i, rel := range in.Relationships {
+		fieldName := rel.Model
+		if rel.Type == "has_many" || rel.Type == "many_to_many" {
+			fieldName = utils.Pluralize(rel.Model)
+		}
+		if rel.Type != "belongs_to" && rel.Type != "polymorphic" && strings.Contains(repo, `db = db.Preload("`+fieldName+`")`) {
+			in.Relationships[i].Preload = true
+		}
+	}
+}
+
+// hookDispatchRegex matches a model hook generated by with_hooks, capturing the hook name.
+var hookDispatchRegex = regexp.MustCompile(`return events\.Dispatch\(tx, "\w+\.(\w+)", m\)`)
+
+var (
+	// bulkMethodRegex matches the doc comment of a custom bulk action method of a service.
+	bulkMethodRegex = regexp.MustCompile(`// (Bulk\w+) sets (\w+) to (.+) on the \w+ with the given IDs`)
+	// bulkButtonRegex matches the toolbar button of a custom bulk action in a list view.
+	bulkButtonRegex = regexp.MustCompile(`(?s)"hx-vals":\s+` + "`" + `\{"action": "(\w+)"\}` + "`" + `,\s*(?:"hx-confirm":\s+("(?:[^"\\]|\\.)*"),\s*)?"hx-swap"[^\n]*\n\s*\},\s*\}\) \{\s*([^\n]+?)\s*\n`)
+)
+
+// readBulkActions completes the custom bulk actions found in the controller with the field and
+// value their service method sets, and the label and confirmation of their toolbar button.
+// Actions it can't complete are dropped with a note.
+func readBulkActions(dir, pkg string, in *types.ScaffoldDomainInput) []string {
+	if len(in.BulkActions) == 0 {
+		return nil
+	}
+	service, _ := utils.ReadFileString(filepath.Join(dir, "internal", "services", pkg, pkg+".go"))
+	list, _ := utils.ReadFileString(filepath.Join(dir, "internal", "web", pkg, "views", "list.templ"))
+
+	methods := map[string][]string{}
+	for _, m := range bulkMethodRegex.FindAllStringSubmatch(service, -1) {
+		methods[m[1]] = m[2:]
+	}
+	buttons := map[string][]string{}
+	for _, m := range bulkButtonRegex.FindAllStringSubmatch(list, -1) {
+		buttons[m[1]] = m[2:]
+	}
+
+	var notes []string
+	var actions []types.BulkActionDef
+	for _, action := range in.BulkActions {
+		method, ok := methods["Bulk"+utils.ToPascalCase(action.Name)]
+		if !ok {
+			notes = append(notes, fmt.Sprintf("bulk action %q: add it to bulk_actions with the field and value it sets", action.Name))
+			continue
+		}
+		action.Field, action.Value = method[0], method[1]
+		if value, err := strconv.Unquote(action.Value); err == nil {
+			action.Value = value
+		}
+		if button, ok := buttons[action.Name]; ok {
+			if confirm, err := strconv.Unquote(button[0]); err == nil {
+				action.Confirm = confirm
+			}
+			if label := button[1]; !strings.HasPrefix(label, "{") && label != utils.ToLabel(action.Name) {
+				action.Label = label
+			}
+		}
+		actions = append(actions, action)
+	}
+	in.BulkActions = actions
+	return notes
+}
@@ -0,0 +1,175 @@
+package tools
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestReconstructMetadata(t *testing.T) {
+	// setup scaffolds a project with two domains, then forgets their metadata
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		project, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+			ProjectName:  "app",
+			ModulePath:   "github.com/test/app",
+			DatabaseType: "sqlite",
+			WithAuth:     true,
+			InCurrentDir: true,
+		})
+		if err != nil || !project.Success {
+			t.Fatalf("scaffoldProject failed: %v %s", err, project.Message)
+		}
+		noSoftDelete := false
+		bound := func(v float64) *float64 { return &v }
+		for _, input := range []types.ScaffoldDomainInput{
+			{DomainName: "customer", Fields: []types.FieldDef{{Name: "Name", Type: "string", Required: true}}},
+			{
+				DomainName: "order_item",
+				Fields: []types.FieldDef{
+					{Name: "Name", Type: "string", Required: true, Max: bound(100), Pattern: `[A-Z]\w*`},
+					{Name: "Price", Type: "float64", GORMTags: "not null", Min: bound(0)},
+					{Name: "Status", Type: "string", FormType: "select", Options: []string{"new", "done"}},
+					{Name: "Active", Type: "bool"},
+					{Name: "Notes", Type: "string", FormType: "textarea", Label: "Extra notes"},
+				},
+				Relationships:         []types.RelationshipDef{{Type: "belongs_to", Model: "Customer"}},
+				WithSoftDelete:        &noSoftDelete,
+				RouteGroup:            "authenticated",
+				FormStyle:             "page",
+				WithOptimisticLocking: true,
+				InlineEdit:            []string{"Status"},
+			},
+		} {
+			domain, err := scaffoldDomain(registry, input)
+			if err != nil || !domain.Success {
+				t.Fatalf("scaffoldDomain %s failed: %v %s", input.DomainName, err, domain.Message)
+			}
+		}
+		if err := os.Remove(filepath.Join(tmpDir, metadata.MetadataDir, metadata.MetadataFile)); err != nil {
+			t.Fatal(err)
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("rebuilds the input of legacy domains", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := reconstructMetadata(registry, types.ReconstructMetadataInput{})
+		if err != nil || !result.Success {
+			t.Fatalf("reconstructMetadata failed: %v %s", err, result.Message)
+		}
+		if len(result.Domains) != 2 || result.Domains[0].Domain != "customer" || result.Domains[1].Domain != "order_item" {
+			t.Fatalf("expected customer and order_item, got %+v (skipped: %v)", result.Domains, result.Skipped)
+		}
+		for _, d := range result.Domains {
+			if len(d.FilesDiffering) > 0 || !d.Saved {
+				t.Errorf("%s: expected every file to regenerate exactly and the input saved, differing: %v", d.Domain, d.FilesDiffering)
+			}
+		}
+
+		in := result.Domains[1].Input
+		if in.RouteGroup != "authenticated" || in.FormStyle != "page" || !in.WithOptimisticLocking || in.GetWithSoftDelete() {
+			t.Errorf("unexpected options: %+v", in)
+		}
+		if len(in.Fields) != 5 || !in.Fields[0].Required || in.Fields[1].GORMTags != "not null" || in.Fields[4].Label != "Extra notes" {
+			t.Errorf("unexpected fields: %+v", in.Fields)
+		}
+		if name, price := in.Fields[0], in.Fields[1]; name.Max == nil || *name.Max != 100 || name.Pattern != `[A-Z]\w*` || price.Min == nil || *price.Min != 0 {
+			t.Errorf("expected the validation rules back, got %+v and %+v", name, price)
+		}
+		if status := in.Fields[2]; status.FormType != "select" || strings.Join(status.Options, ",") != "new,done" {
+			t.Errorf("unexpected status field: %+v", status)
+		}
+		if len(in.Relationships) != 1 || in.Relationships[0].Type != "belongs_to" || in.Relationships[0].Model != "Customer" {
+			t.Errorf("unexpected relationships: %+v", in.Relationships)
+		}
+		if strings.Join(in.InlineEdit, ",") != "Status" {
+			t.Errorf("expected Status inline editable, got %v", in.InlineEdit)
+		}
+
+		domainMeta, exists, err := metadata.NewStore(tmpDir).GetDomain("order_item")
+		if err != nil || !exists || !domainMeta.Reconstructed {
+			t.Fatalf("expected reconstructed metadata for order_item, got %+v (%v)", domainMeta, err)
+		}
+		analysis, err := ExecuteAnalyzeDomain(context.Background(), registry, types.AnalyzeDomainInput{Domain: "order_item"})
+		if err != nil || !analysis.Success || analysis.Domains[0].HasChanges {
+			t.Errorf("expected analyze_domain to find no changes, got %+v (%v)", analysis, err)
+		}
+	})
+
+	t.Run("detects tenant-scoped domains", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		if result, err := scaffoldTenancy(registry, types.ScaffoldTenancyInput{}); err != nil || !result.Success {
+			t.Fatalf("scaffoldTenancy failed: %v %s", err, result.Message)
+		}
+
+		// customer has TenantID where the template puts it, order_item where earlier versions injected it
+		customerPath := filepath.Join(tmpDir, "internal", "models", "customer.go")
+		injector, err := modifier.NewInjector(customerPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := injector.InjectTenantID(); err != nil {
+			t.Fatal(err)
+		}
+		if err := injector.Save(); err != nil {
+			t.Fatal(err)
+		}
+		itemPath := filepath.Join(tmpDir, "internal", "models", "orderitem.go")
+		item := strings.Replace(readFile(t, itemPath), "\t// MCP:RELATIONSHIPS:END", "\tTenantID uint `gorm:\"not null;default:0;index\" json:\"-\"`\n\t// MCP:RELATIONSHIPS:END", 1)
+		writeTestFile(t, itemPath, item)
+
+		result, err := reconstructMetadata(registry, types.ReconstructMetadataInput{})
+		if err != nil || !result.Success {
+			t.Fatalf("reconstructMetadata failed: %v %s", err, result.Message)
+		}
+		for _, d := range result.Domains {
+			if !d.Input.TenantScoped {
+				t.Errorf("expected %s to be tenant_scoped", d.Domain)
+			}
+		}
+		if differing := result.Domains[0].FilesDiffering; len(differing) > 0 {
+			t.Errorf("expected the tenant-scoped customer to regenerate exactly, differing: %v", differing)
+		}
+	})
+
+	t.Run("skips domains with metadata unless overwriting", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		store := metadata.NewStore(tmpDir)
+		if err := store.SaveDomain("customer", types.ScaffoldDomainInput{DomainName: "customer"}, ScaffolderVersion); err != nil {
+			t.Fatal(err)
+		}
+
+		result, _ := reconstructMetadata(registry, types.ReconstructMetadataInput{DryRun: true})
+		if len(result.Domains) != 1 || len(result.Skipped) != 1 || !strings.HasPrefix(result.Skipped[0], "customer:") {
+			t.Fatalf("expected customer skipped, got %+v", result)
+		}
+		if result.Domains[0].Saved {
+			t.Error("a dry run should not save")
+		}
+		if exists, _ := store.Exists("order_item"); exists {
+			t.Error("a dry run should not write metadata")
+		}
+
+		result, _ = reconstructMetadata(registry, types.ReconstructMetadataInput{Domains: []string{"customer"}, Overwrite: true})
+		if len(result.Domains) != 1 || !result.Domains[0].Saved {
+			t.Fatalf("expected customer overwritten, got %+v", result)
+		}
+		if domainMeta, _, _ := store.GetDomain("customer"); len(domainMeta.Input.Fields) != 1 {
+			t.Errorf("expected the reconstructed input saved, got %+v", domainMeta.Input)
+		}
+
+		result, _ = reconstructMetadata(registry, types.ReconstructMetadataInput{Domains: []string{"invoice"}})
+		if result.Success || !strings.Contains(result.Message, "no domain 'invoice' found") {
+			t.Errorf("expected an unknown domain error, got: %s", result.Message)
+		}
+	})
+}
@@ -0,0 +1,953 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterRefactorExtractService registers the refactor_extract_service tool.
+func RegisterRefactorExtractService(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "refactor_extract_service",
+		Description: `Split a fat service by moving methods into a new service package.
+
+Use this when a scaffolded service has grown too many responsibilities. The selected methods
+are moved out of internal/services/{domain}/ into internal/services/{new_service}/, which
+shares the domain's repository.
+
+Steps performed:
+1. Parses the domain service and moves the methods (interface entries and implementations)
+2. Qualifies references to the domain service's DTOs and errors (e.g., CreateOrderInput -> ordersvc.CreateOrderInput)
+3. Removes imports the domain service no longer uses
+4. Rewrites controller calls (c.service.Method -> c.{newService}Service.Method) and adds the
+   new service to the controller struct and NewController
+5. Passes the new service to NewController in the controller tests: their stub service when it
+   has every moved method, nil otherwise (next_steps lists those tests)
+6. Wires the new service in cmd/web/main.go and passes it to the controller
+
+Service tests of the moved methods stay in the domain package; the result names them so they
+can be moved to the new one.
+
+Moved methods may only use s.repo, other moved methods and unexported helper methods. Helpers
+move along with the methods that use them, or are copied when methods that stay behind use them
+too; they may also be listed in methods. If a method calls a Service method that stays behind,
+include it in methods as well.
+
+Use dry_run: true to preview the files that would change.
+
+Example:
+  refactor_extract_service: {
+    domain: "order",
+    new_service: "order_pricing",
+    methods: ["CalculateTotal", "ApplyDiscount"]
+  }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.RefactorExtractServiceInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(refactorExtractService)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+// textEdit replaces src[start:end] with text.
+type textEdit struct {
+	start int
+	end   int
+	text  string
+}
+
+// applyEdits applies non-overlapping edits to src.
+func applyEdits(src string, edits []textEdit) string {
+	sort.Slice(edits, func(i, j int) bool { return edits[i].start > edits[j].start })
+	for _, e := range edits {
+		src = src[:e.start] + e.text + src[e.end:]
+	}
+	return src
+}
+
+func refactorExtractService(registry *Registry, input types.RefactorExtractServiceInput) (types.ScaffoldResult, error) {
+	if err := utils.ValidateDomainName(input.Domain); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("domain: %v", err)), nil
+	}
+	if err := utils.ValidateDomainName(input.NewService); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("new_service: %v", err)), nil
+	}
+	if len(input.Methods) == 0 {
+		return types.NewErrorResult("at least one method is required"), nil
+	}
+
+	pkgName := utils.ToPackageName(input.Domain)
+	newPkgName := utils.ToPackageName(input.NewService)
+	if pkgName == newPkgName {
+		return types.NewErrorResult("new_service must differ from domain"), nil
+	}
+	if registry.UsesWire() {
+		return types.NewErrorResult("refactor_extract_service wires the new service in cmd/web/main.go, which projects with di wire don't support yet"), nil
+	}
+
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+	gen.SetForceOverwrite(true)
+
+	servicePath := filepath.Join("internal", "services", pkgName, pkgName+".go")
+	if !gen.FileExists(servicePath) {
+		return types.NewErrorResult(fmt.Sprintf("service file not found: %s. Use scaffold_domain first.", servicePath)), nil
+	}
+	newServicePath := filepath.Join("internal", "services", newPkgName, newPkgName+".go")
+	if gen.FileExists(newServicePath) {
+		return types.NewErrorResult(fmt.Sprintf("service already exists: %s", newServicePath)), nil
+	}
+
+	src, err := gen.ReadFile(servicePath)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to read service file: %v", err)), nil
+	}
+
+	svcAlias := utils.ToServiceImportAlias(input.Domain)
+	extracted, err := extractServiceMethods(src, input.Methods, registry.WorkingDir, servicePath, svcAlias)
+	if err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
+	// Build the new service package
+	svcImport := fmt.Sprintf("%s/internal/services/%s", modulePath, pkgName)
+	newService, err := buildExtractedService(newPkgName, utils.ToModelName(input.NewService), input.Domain, svcAlias, svcImport, extracted)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", newServicePath, err)), nil
+	}
+	if err := gen.EnsureDir(filepath.Join("internal", "services", newPkgName)); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to create directory: %v", err)), nil
+	}
+	if err := gen.WriteFile(newServicePath, newService); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to write %s: %v", newServicePath, err)), nil
+	}
+
+	// Write the slimmed-down domain service
+	if err := gen.WriteFile(servicePath, extracted.remaining); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to write %s: %v", servicePath, err)), nil
+	}
+
+	// Point controller calls at the new service
+	newAlias := utils.ToServiceImportAlias(input.NewService)
+	newField := utils.ToServiceVariableName(input.NewService)
+	newImport := fmt.Sprintf("%s/internal/services/%s", modulePath, newPkgName)
+	controllerUpdated := false
+	controllerPath := filepath.Join("internal", "web", pkgName, pkgName+".go")
+	if gen.FileExists(controllerPath) {
+		controllerSrc, err := gen.ReadFile(controllerPath)
+		if err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to read controller: %v", err)), nil
+		}
+		updated, changed, err := rewriteControllerServiceCalls(controllerSrc, extracted.moved, newField, newAlias, newImport)
+		if err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to update controller: %v", err)), nil
+		}
+		if changed {
+			if err := gen.WriteFile(controllerPath, updated); err != nil {
+				return types.NewErrorResult(fmt.Sprintf("failed to write %s: %v", controllerPath, err)), nil
+			}
+			controllerUpdated = true
+		}
+	}
+
+	// Pass the new service to the controller tests, and point out service tests of moved methods
+	nextSteps := []string{"go build ./..."}
+	if controllerUpdated {
+		stubbed, err := updateControllerTests(gen, filepath.Dir(controllerPath), extracted.moved)
+		if err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to update controller tests: %v", err)), nil
+		}
+		for _, path := range stubbed {
+			nextSteps = append(nextSteps, fmt.Sprintf("%s passes nil for the %s service: give it a stub if it tests %s", filepath.ToSlash(path), newPkgName, movedList(extracted.moved)))
+		}
+	}
+	if len(extracted.copied) > 0 {
+		nextSteps = append(nextSteps, fmt.Sprintf("%s stayed in the %s service, which still uses it, and was copied to the %s service", strings.Join(extracted.copied, ", "), pkgName, newPkgName))
+	}
+	serviceTests, err := movedServiceTests(gen, filepath.Dir(servicePath), extracted.moved)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to read service tests: %v", err)), nil
+	}
+	for _, path := range serviceTests {
+		nextSteps = append([]string{fmt.Sprintf("Move the tests of %s in %s to internal/services/%s: the %s service no longer has them", movedList(extracted.moved), filepath.ToSlash(path), newPkgName, pkgName)}, nextSteps...)
+	}
+
+	// Wire the new service in main.go
+	mainGoPath := filepath.Join("cmd", "web", "main.go")
+	if gen.FileExists(mainGoPath) {
+		mainSrc, err := gen.ReadFile(mainGoPath)
+		if err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to read main.go: %v", err)), nil
+		}
+		wired, err := wireExtractedService(mainSrc, input.Domain, input.NewService, newImport, controllerUpdated)
+		if err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to update main.go: %v", err)), nil
+		}
+		if err := gen.WriteFile(mainGoPath, wired); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to write main.go: %v", err)), nil
+		}
+	} else {
+		nextSteps = append([]string{
+			fmt.Sprintf("Wire %s := %s.NewService(%s) where the %s service is created", newField, newAlias, utils.ToRepoVariableName(input.Domain), input.Domain),
+		}, nextSteps...)
+	}
+
+	genResult := gen.Result()
+	message := fmt.Sprintf("Moved %d method(s) from %s service to %s service", len(extracted.moved), pkgName, newPkgName)
+	if len(serviceTests) > 0 {
+		message += fmt.Sprintf("; %s still test(s) the moved methods and won't compile until they move", filepath.ToSlash(strings.Join(serviceTests, ", ")))
+	}
+	if input.DryRun {
+		message = "Dry run: " + message
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      message,
+		FilesCreated: genResult.FilesCreated,
+		FilesUpdated: genResult.FilesUpdated,
+		Backups:      genResult.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// extractedMethods holds the result of moving methods out of a service file.
+type extractedMethods struct {
+	// moved is the set of moved method names.
+	moved map[string]bool
+	// imports are the import specs of the original service file.
+	imports []string
+	// interfaceMethods are the rewritten Service interface entries.
+	interfaceMethods []string
+	// implementations are the rewritten method declarations, helpers included.
+	implementations []string
+	// copied are the helpers both services use, so the original keeps its copy.
+	copied []string
+	// remaining is the original service file without the moved methods.
+	remaining string
+}
+
+// extractServiceMethods removes methods from a service file and returns them rewritten for a new package.
+// References to package-level declarations of the original service package are qualified with svcAlias.
+func extractServiceMethods(src string, methods []string, workingDir, servicePath, svcAlias string) (*extractedMethods, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, servicePath, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse service file: %v", err)
+	}
+	offset := func(p token.Pos) int { return fset.Position(p).Offset }
+
+	// Locate the Service interface and the *service methods
+	var iface *ast.InterfaceType
+	impls := make(map[string]*ast.FuncDecl)
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				if ts, ok := spec.(*ast.TypeSpec); ok && ts.Name.Name == "Service" {
+					if it, ok := ts.Type.(*ast.InterfaceType); ok {
+						iface = it
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if receiverTypeName(d) == "service" {
+				impls[d.Name.Name] = d
+			}
+		}
+	}
+	if iface == nil {
+		return nil, fmt.Errorf("service interface not found in %s", servicePath)
+	}
+	ifaceFields := make(map[string]*ast.Field)
+	for _, field := range iface.Methods.List {
+		if len(field.Names) == 1 {
+			ifaceFields[field.Names[0].Name] = field
+		}
+	}
+
+	// Service methods move to the new interface; unexported helpers move along with them
+	moved := make(map[string]bool)
+	helpers := make(map[string]bool)
+	for _, name := range methods {
+		if _, ok := impls[name]; !ok {
+			return nil, fmt.Errorf("method '%s' not found in %s", name, servicePath)
+		}
+		if _, ok := ifaceFields[name]; ok {
+			moved[name] = true
+		} else if token.IsExported(name) {
+			return nil, fmt.Errorf("method '%s' is not part of the Service interface", name)
+		} else {
+			helpers[name] = true
+		}
+	}
+	if len(moved) == 0 {
+		return nil, fmt.Errorf("methods must include at least one method of the Service interface")
+	}
+
+	// Moved methods may only depend on s.repo, each other and unexported helpers, which they take along
+	queue := slices.Clone(methods)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		fn := impls[name]
+		uses, recvName := receiverSelectors(fn)
+		for _, used := range uses {
+			_, isMethod := impls[used]
+			_, inInterface := ifaceFields[used]
+			switch {
+			case used == "repo" || moved[used] || helpers[used]:
+			case isMethod && !inInterface:
+				helpers[used] = true
+				queue = append(queue, used)
+			case isMethod:
+				return nil, fmt.Errorf("method '%s' uses %s.%s, which stays in the original service; include it in methods or refactor it first", name, recvName, used)
+			default:
+				return nil, fmt.Errorf("method '%s' uses %s.%s, which the new service doesn't have: it only gets the repository, so refactor it first", name, recvName, used)
+			}
+		}
+	}
+
+	// Helpers the remaining methods still use are copied rather than moved
+	shared := make(map[string]bool)
+	for name, fn := range impls {
+		if moved[name] || helpers[name] {
+			continue
+		}
+		uses, _ := receiverSelectors(fn)
+		for _, used := range uses {
+			if helpers[used] {
+				shared[used] = true
+			}
+		}
+	}
+	helperNames := make([]string, 0, len(helpers))
+	for name := range helpers {
+		helperNames = append(helperNames, name)
+	}
+	sort.Slice(helperNames, func(i, j int) bool { return impls[helperNames[i]].Pos() < impls[helperNames[j]].Pos() })
+
+	pkgNames, err := packageLevelNames(filepath.Join(workingDir, filepath.Dir(servicePath)))
+	if err != nil {
+		return nil, err
+	}
+	topLevel := topLevelDecls(file)
+
+	result := &extractedMethods{moved: moved}
+	for _, spec := range file.Imports {
+		result.imports = append(result.imports, src[offset(spec.Pos()):offset(spec.End())])
+	}
+
+	var edits []textEdit
+	for _, name := range methods {
+		if !moved[name] {
+			continue
+		}
+		field := ifaceFields[name]
+		start := field.Pos()
+		if field.Doc != nil {
+			start = field.Doc.Pos()
+		}
+		result.interfaceMethods = append(result.interfaceMethods,
+			qualifyIdents(src, fset, start, field.End(), field, pkgNames, topLevel, svcAlias))
+		edits = append(edits, lineEdit(src, offset(start), offset(field.End())))
+
+		fn := impls[name]
+		start = fn.Pos()
+		if fn.Doc != nil {
+			start = fn.Doc.Pos()
+		}
+		result.implementations = append(result.implementations,
+			qualifyIdents(src, fset, start, fn.End(), fn, pkgNames, topLevel, svcAlias))
+		edits = append(edits, lineEdit(src, offset(start), offset(fn.End())))
+	}
+	for _, name := range helperNames {
+		fn := impls[name]
+		start := fn.Pos()
+		if fn.Doc != nil {
+			start = fn.Doc.Pos()
+		}
+		result.implementations = append(result.implementations,
+			qualifyIdents(src, fset, start, fn.End(), fn, pkgNames, topLevel, svcAlias))
+		if shared[name] {
+			result.copied = append(result.copied, name)
+		} else {
+			edits = append(edits, lineEdit(src, offset(start), offset(fn.End())))
+		}
+	}
+
+	remaining, err := pruneImports(applyEdits(src, edits))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format %s: %v", servicePath, err)
+	}
+	result.remaining = remaining
+
+	return result, nil
+}
+
+// buildExtractedService generates the source of a new service package holding the extracted methods.
+func buildExtractedService(pkgName, modelName, domainName, svcAlias, svcImport string, extracted *extractedMethods) (string, error) {
+	var b strings.Builder
+	// Keep standard library imports in their own group
+	var stdImports, otherImports []string
+	for _, imp := range extracted.imports {
+		path := imp[strings.Index(imp, `"`)+1:]
+		if first, _, _ := strings.Cut(path, "/"); strings.Contains(first, ".") {
+			otherImports = append(otherImports, imp)
+		} else {
+			stdImports = append(stdImports, imp)
+		}
+	}
+	otherImports = append(otherImports, fmt.Sprintf("%s %q", svcAlias, svcImport))
+
+	fmt.Fprintf(&b, "package %s\n\nimport (\n", pkgName)
+	for _, imp := range stdImports {
+		fmt.Fprintf(&b, "\t%s\n", imp)
+	}
+	if len(stdImports) > 0 {
+		b.WriteString("\n")
+	}
+	for _, imp := range otherImports {
+		fmt.Fprintf(&b, "\t%s\n", imp)
+	}
+	b.WriteString(")\n\n")
+
+	fmt.Fprintf(&b, "// Service defines the interface for %s business operations.\n", modelName)
+	b.WriteString("type Service interface {\n")
+	for _, m := range extracted.interfaceMethods {
+		fmt.Fprintf(&b, "\t%s\n", m)
+	}
+	b.WriteString("\t// MCP:SERVICE_INTERFACE:START\n\t// MCP:SERVICE_INTERFACE:END\n}\n\n")
+
+	repoAlias := utils.ToRepoImportAlias(domainName)
+	b.WriteString("// service implements Service.\n")
+	fmt.Fprintf(&b, "type service struct {\n\trepo %s.Repository\n}\n\n", repoAlias)
+	fmt.Fprintf(&b, "// NewService creates a new %s service.\n", modelName)
+	fmt.Fprintf(&b, "// It shares the %s repository with the %s service it was extracted from.\n", utils.ToModelName(domainName), utils.ToPackageName(domainName))
+	fmt.Fprintf(&b, "func NewService(repo %s.Repository) Service {\n\treturn &service{repo: repo}\n}\n", repoAlias)
+
+	for _, impl := range extracted.implementations {
+		fmt.Fprintf(&b, "\n%s\n", impl)
+	}
+	b.WriteString("\n// MCP:SERVICE_METHODS:START\n// MCP:SERVICE_METHODS:END\n")
+
+	return pruneImports(b.String())
+}
+
+// rewriteControllerServiceCalls points calls to moved service methods at a new controller field
+// and adds that field to the Controller struct and NewController.
+// It reports whether the controller referenced any of the moved methods.
+func rewriteControllerServiceCalls(src string, moved map[string]bool, field, alias, importPath string) (string, bool, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return "", false, err
+	}
+	offset := func(p token.Pos) int { return fset.Position(p).Offset }
+
+	var edits []textEdit
+	ast.Inspect(file, func(n ast.Node) bool {
+		sel, ok := n.(*ast.SelectorExpr)
+		if !ok || !moved[sel.Sel.Name] {
+			return true
+		}
+		if inner, ok := sel.X.(*ast.SelectorExpr); ok && inner.Sel.Name == "service" {
+			edits = append(edits, textEdit{offset(inner.Sel.Pos()), offset(inner.Sel.End()), field})
+		}
+		return true
+	})
+	if len(edits) == 0 {
+		return src, false, nil
+	}
+
+	fieldType := alias + ".Service"
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				ts, ok := spec.(*ast.TypeSpec)
+				if !ok || ts.Name.Name != "Controller" {
+					continue
+				}
+				st, ok := ts.Type.(*ast.StructType)
+				if !ok {
+					continue
+				}
+				for _, f := range st.Fields.List {
+					if len(f.Names) == 1 && f.Names[0].Name == "service" {
+						edits = append(edits, textEdit{offset(f.End()), offset(f.End()), fmt.Sprintf("\n\t%s %s", field, fieldType)})
+					}
+				}
+			}
+		case *ast.FuncDecl:
+			if d.Recv != nil || d.Name.Name != "NewController" {
+				continue
+			}
+			for _, p := range d.Type.Params.List {
+				if len(p.Names) == 1 && p.Names[0].Name == "service" {
+					edits = append(edits, textEdit{offset(p.End()), offset(p.End()), fmt.Sprintf(", %s %s", field, fieldType)})
+				}
+			}
+			ast.Inspect(d.Body, func(n ast.Node) bool {
+				kv, ok := n.(*ast.KeyValueExpr)
+				if ok {
+					if key, ok := kv.Key.(*ast.Ident); ok && key.Name == "service" {
+						edits = append(edits, textEdit{offset(kv.End()), offset(kv.End()), fmt.Sprintf(", %s: %s", field, field)})
+					}
+				}
+				return true
+			})
+		}
+	}
+
+	if len(file.Imports) > 0 {
+		last := file.Imports[len(file.Imports)-1]
+		edits = append(edits, textEdit{offset(last.End()), offset(last.End()), fmt.Sprintf("\n\t%s %q", alias, importPath)})
+	}
+
+	formatted, err := format.Source([]byte(applyEdits(src, edits)))
+	if err != nil {
+		return "", false, err
+	}
+	return string(formatted), true, nil
+}
+
+// updateControllerTests passes the new service to the NewController calls of the controller tests
+// in dir. A call gets the stub service it already passes when that stub has every moved method,
+// and nil otherwise; it returns the tests that got nil.
+func updateControllerTests(gen *generator.Generator, dir string, moved map[string]bool) ([]string, error) {
+	var withNil []string
+	for _, path := range goTestFiles(gen, dir) {
+		src, err := gen.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		updated, nilService, err := rewriteControllerTestCalls(src, moved)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		if updated == src {
+			continue
+		}
+		if err := gen.WriteFile(path, updated); err != nil {
+			return nil, err
+		}
+		if nilService {
+			withNil = append(withNil, path)
+		}
+	}
+	return withNil, nil
+}
+
+// rewriteControllerTestCalls adds the new service argument after the first argument of every
+// NewController call in a test file. It reports whether any call got nil instead of a stub.
+func rewriteControllerTestCalls(src string, moved map[string]bool) (string, bool, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return "", false, err
+	}
+	offset := func(p token.Pos) int { return fset.Position(p).Offset }
+
+	methods := make(map[string]map[string]bool)
+	for _, decl := range file.Decls {
+		if fn, ok := decl.(*ast.FuncDecl); ok && fn.Recv != nil {
+			recv := receiverTypeName(fn)
+			if methods[recv] == nil {
+				methods[recv] = make(map[string]bool)
+			}
+			methods[recv][fn.Name.Name] = true
+		}
+	}
+
+	var edits []textEdit
+	nilService := false
+	ast.Inspect(file, func(n ast.Node) bool {
+		call, ok := n.(*ast.CallExpr)
+		if !ok || len(call.Args) == 0 {
+			return true
+		}
+		var name string
+		switch fn := call.Fun.(type) {
+		case *ast.Ident:
+			name = fn.Name
+		case *ast.SelectorExpr:
+			name = fn.Sel.Name
+		}
+		if name != "NewController" {
+			return true
+		}
+		arg := "nil"
+		if id, ok := call.Args[0].(*ast.Ident); ok && hasMethods(methods[identTypeName(id)], moved) {
+			arg = id.Name
+		} else {
+			nilService = true
+		}
+		end := offset(call.Args[0].End())
+		edits = append(edits, textEdit{end, end, ", " + arg})
+		return true
+	})
+	if len(edits) == 0 {
+		return src, false, nil
+	}
+	return applyEdits(src, edits), nilService, nil
+}
+
+// movedServiceTests returns the test files in dir that call moved methods on the service.
+func movedServiceTests(gen *generator.Generator, dir string, moved map[string]bool) ([]string, error) {
+	var paths []string
+	for _, path := range goTestFiles(gen, dir) {
+		src, err := gen.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		file, err := parser.ParseFile(token.NewFileSet(), "", src, 0)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", path, err)
+		}
+		found := false
+		ast.Inspect(file, func(n ast.Node) bool {
+			sel, ok := n.(*ast.SelectorExpr)
+			if !ok || found || !moved[sel.Sel.Name] {
+				return !found
+			}
+			// The service under test comes from NewService or a helper like newTestService
+			if id, ok := sel.X.(*ast.Ident); ok && id.Obj != nil {
+				if assign, ok := id.Obj.Decl.(*ast.AssignStmt); ok && len(assign.Rhs) == 1 {
+					if call, ok := assign.Rhs[0].(*ast.CallExpr); ok {
+						if fn, ok := call.Fun.(*ast.Ident); ok && strings.HasSuffix(fn.Name, "Service") {
+							found = true
+						}
+					}
+				}
+			}
+			return true
+		})
+		if found {
+			paths = append(paths, path)
+		}
+	}
+	return paths, nil
+}
+
+// goTestFiles returns the _test.go files in a project directory.
+func goTestFiles(gen *generator.Generator, dir string) []string {
+	entries, err := os.ReadDir(gen.FullPath(dir))
+	if err != nil {
+		return nil
+	}
+	var paths []string
+	for _, entry := range entries {
+		if !entry.IsDir() && strings.HasSuffix(entry.Name(), "_test.go") {
+			paths = append(paths, filepath.Join(dir, entry.Name()))
+		}
+	}
+	return paths
+}
+
+// identTypeName returns the type name of a parameter or variable like `service *stubService`
+// or `service := &stubService{}`, without the pointer.
+func identTypeName(id *ast.Ident) string {
+	if id.Obj == nil {
+		return ""
+	}
+	var expr ast.Expr
+	switch decl := id.Obj.Decl.(type) {
+	case *ast.Field:
+		expr = decl.Type
+	case *ast.AssignStmt:
+		if len(decl.Rhs) == 1 {
+			expr = decl.Rhs[0]
+		}
+	}
+	if unary, ok := expr.(*ast.UnaryExpr); ok && unary.Op == token.AND {
+		expr = unary.X
+	}
+	if lit, ok := expr.(*ast.CompositeLit); ok {
+		expr = lit.Type
+	}
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// hasMethods reports whether declared holds every method in want.
+func hasMethods(declared, want map[string]bool) bool {
+	for name := range want {
+		if !declared[name] {
+			return false
+		}
+	}
+	return len(declared) > 0
+}
+
+// movedList returns the moved method names, sorted and comma separated.
+func movedList(moved map[string]bool) string {
+	names := make([]string, 0, len(moved))
+	for name := range moved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return strings.Join(names, ", ")
+}
+
+// wireExtractedService instantiates the new service in main.go and, if needed, passes it to the domain controller.
+func wireExtractedService(src, domainName, newServiceName, importPath string, withController bool) (string, error) {
+	injector := modifier.NewInjectorFromContent(src)
+	newAlias := utils.ToServiceImportAlias(newServiceName)
+	if err := injector.InjectImportWithAlias(importPath, newAlias); err != nil {
+		return "", err
+	}
+
+	newVar := utils.ToServiceVariableName(newServiceName)
+	code := fmt.Sprintf(`%s := %s.NewService(%s)`, newVar, newAlias, utils.ToRepoVariableName(domainName))
+	if !withController {
+		// Keep main.go building until something uses the new service
+		code += fmt.Sprintf("\n_ = %s", newVar)
+	}
+	if err := injector.InjectBetweenMarkers(modifier.MarkerServicesStart, modifier.MarkerServicesEnd, code); err != nil {
+		return "", err
+	}
+
+	content := injector.Content()
+	if withController {
+		ctrlCall := regexp.MustCompile(fmt.Sprintf(`(%s := %s\.NewController\(%s)`,
+			regexp.QuoteMeta(utils.ToControllerVariableName(domainName)),
+			regexp.QuoteMeta(utils.ToControllerImportAlias(domainName)),
+			regexp.QuoteMeta(utils.ToServiceVariableName(domainName))))
+		if !ctrlCall.MatchString(content) {
+			return "", fmt.Errorf("%s controller instantiation not found", domainName)
+		}
+		content = ctrlCall.ReplaceAllString(content, "${1}, "+newVar)
+	}
+	return content, nil
+}
+
+// receiverSelectors returns the fields and methods a method body selects on its receiver,
+// such as repo for s.repo, along with the receiver name.
+func receiverSelectors(fn *ast.FuncDecl) ([]string, string) {
+	recv := fn.Recv.List[0].Names
+	if len(recv) == 0 || fn.Body == nil {
+		return nil, ""
+	}
+	recvName := recv[0].Name
+	var used []string
+	ast.Inspect(fn.Body, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if x, ok := sel.X.(*ast.Ident); ok && x.Name == recvName && !slices.Contains(used, sel.Sel.Name) {
+				used = append(used, sel.Sel.Name)
+			}
+		}
+		return true
+	})
+	return used, recvName
+}
+
+// receiverTypeName returns the receiver type name of a method, without the pointer.
+func receiverTypeName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return ""
+	}
+	expr := fn.Recv.List[0].Type
+	if star, ok := expr.(*ast.StarExpr); ok {
+		expr = star.X
+	}
+	if ident, ok := expr.(*ast.Ident); ok {
+		return ident.Name
+	}
+	return ""
+}
+
+// packageLevelNames returns the names declared at package level in a directory's non-test Go files,
+// excluding the Service/service/NewService names every service package declares.
+func packageLevelNames(dir string) (map[string]bool, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	names := make(map[string]bool)
+	fset := token.NewFileSet()
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".go") || strings.HasSuffix(entry.Name(), "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, filepath.Join(dir, entry.Name()), nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %v", entry.Name(), err)
+		}
+		for _, decl := range file.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					switch s := spec.(type) {
+					case *ast.TypeSpec:
+						names[s.Name.Name] = true
+					case *ast.ValueSpec:
+						for _, n := range s.Names {
+							names[n.Name] = true
+						}
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv == nil {
+					names[d.Name.Name] = true
+				}
+			}
+		}
+	}
+	delete(names, "Service")
+	delete(names, "service")
+	delete(names, "NewService")
+	return names, nil
+}
+
+// topLevelDecls returns the nodes that declare package-level objects in a file.
+func topLevelDecls(file *ast.File) map[any]bool {
+	decls := make(map[any]bool)
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				decls[spec] = true
+			}
+		case *ast.FuncDecl:
+			decls[d] = true
+		}
+	}
+	return decls
+}
+
+// qualifyIdents returns src[start:end] with references to package-level names prefixed by alias.
+// Selector fields, composite literal keys, method names, and locally declared identifiers are left alone.
+func qualifyIdents(src string, fset *token.FileSet, start, end token.Pos, node ast.Node, pkgNames map[string]bool, topLevel map[any]bool, alias string) string {
+	base := fset.Position(start).Offset
+	skip := make(map[*ast.Ident]bool)
+	var edits []textEdit
+
+	ast.Inspect(node, func(n ast.Node) bool {
+		switch x := n.(type) {
+		case *ast.FuncDecl:
+			skip[x.Name] = true
+			if x.Recv != nil {
+				for _, f := range x.Recv.List {
+					if star, ok := f.Type.(*ast.StarExpr); ok {
+						if id, ok := star.X.(*ast.Ident); ok {
+							skip[id] = true
+						}
+					}
+				}
+			}
+		case *ast.Field:
+			for _, id := range x.Names {
+				skip[id] = true
+			}
+		case *ast.SelectorExpr:
+			skip[x.Sel] = true
+		case *ast.KeyValueExpr:
+			if id, ok := x.Key.(*ast.Ident); ok {
+				skip[id] = true
+			}
+		case *ast.Ident:
+			if skip[x] || !pkgNames[x.Name] {
+				return true
+			}
+			if x.Obj != nil && !topLevel[x.Obj.Decl] {
+				return true
+			}
+			off := fset.Position(x.Pos()).Offset - base
+			edits = append(edits, textEdit{off, off, alias + "."})
+		}
+		return true
+	})
+
+	return applyEdits(src[base:fset.Position(end).Offset], edits)
+}
+
+// lineEdit returns an edit removing src[start:end] along with the rest of its last line
+// and the indentation before it.
+func lineEdit(src string, start, end int) textEdit {
+	for start > 0 && (src[start-1] == '\t' || src[start-1] == ' ') {
+		start--
+	}
+	if nl := strings.IndexByte(src[end:], '\n'); nl >= 0 {
+		end += nl + 1
+	}
+	return textEdit{start, end, ""}
+}
+
+// pruneImports removes unused imports from Go source and formats it.
+func pruneImports(src string) (string, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, "", src, parser.ParseComments)
+	if err != nil {
+		return "", err
+	}
+
+	used := make(map[string]bool)
+	ast.Inspect(file, func(n ast.Node) bool {
+		if sel, ok := n.(*ast.SelectorExpr); ok {
+			if id, ok := sel.X.(*ast.Ident); ok && id.Obj == nil {
+				used[id.Name] = true
+			}
+		}
+		return true
+	})
+
+	var edits []textEdit
+	for _, decl := range file.Decls {
+		d, ok := decl.(*ast.GenDecl)
+		if !ok || d.Tok != token.IMPORT {
+			continue
+		}
+		for _, spec := range d.Specs {
+			imp := spec.(*ast.ImportSpec)
+			path, _ := strconv.Unquote(imp.Path.Value)
+			name := path[strings.LastIndex(path, "/")+1:]
+			if imp.Name != nil {
+				name = imp.Name.Name
+			}
+			if name == "_" || name == "." || used[name] {
+				continue
+			}
+			// Single-line imports are removed with their import keyword
+			node := ast.Node(imp)
+			if !d.Lparen.IsValid() {
+				node = d
+			}
+			edits = append(edits, lineEdit(src, fset.Position(node.Pos()).Offset, fset.Position(node.End()).Offset))
+		}
+	}
+
+	formatted, err := format.Source([]byte(applyEdits(src, edits)))
+	if err != nil {
+		return "", err
+	}
+	return string(formatted), nil
+}
@@ -0,0 +1,285 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestRefactorExtractService(t *testing.T) {
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, mainGoWithMarkers)
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "order",
+			Fields: []types.FieldDef{
+				{Name: "Total", Type: "float64"},
+			},
+		}
+		if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("validates input", func(t *testing.T) {
+		registry, _ := setup(t)
+
+		tests := []struct {
+			name  string
+			input types.RefactorExtractServiceInput
+		}{
+			{"no methods", types.RefactorExtractServiceInput{Domain: "order", NewService: "order_admin"}},
+			{"same package", types.RefactorExtractServiceInput{Domain: "order", NewService: "order", Methods: []string{"Delete"}}},
+			{"unknown method", types.RefactorExtractServiceInput{Domain: "order", NewService: "order_admin", Methods: []string{"Archive"}}},
+			{"unknown domain", types.RefactorExtractServiceInput{Domain: "invoice", NewService: "invoice_admin", Methods: []string{"Delete"}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := refactorExtractService(registry, tt.input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success {
+					t.Error("expected failure")
+				}
+			})
+		}
+	})
+
+	t.Run("moves methods and rewires callers", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := refactorExtractService(registry, types.RefactorExtractServiceInput{
+			Domain:     "order",
+			NewService: "order_admin",
+			Methods:    []string{"Update", "Delete"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		newService := readFile(t, filepath.Join(tmpDir, "internal", "services", "orderadmin", "orderadmin.go"))
+		for _, want := range []string{
+			"package orderadmin",
+			`ordersvc "github.com/example/testapp/internal/services/order"`,
+			"Update(ctx context.Context, id uint, input ordersvc.UpdateOrderInput) (*models.Order, error)",
+			"return ordersvc.ErrOrderNotFound",
+			"func NewService(repo orderrepo.Repository) Service",
+		} {
+			if !strings.Contains(newService, want) {
+				t.Errorf("expected new service to contain %q", want)
+			}
+		}
+
+		oldService := readFile(t, filepath.Join(tmpDir, "internal", "services", "order", "order.go"))
+		if strings.Contains(oldService, "func (s *service) Delete") {
+			t.Error("expected Delete to be removed from the order service")
+		}
+		if !strings.Contains(oldService, "func (s *service) Create") {
+			t.Error("expected Create to stay in the order service")
+		}
+
+		controller := readFile(t, filepath.Join(tmpDir, "internal", "web", "order", "order.go"))
+		for _, want := range []string{
+			"orderAdminService orderadminsvc.Service",
+			"c.orderAdminService.Delete(",
+			"c.service.Create(",
+		} {
+			if !strings.Contains(controller, want) {
+				t.Errorf("expected controller to contain %q", want)
+			}
+		}
+
+		mainGo := readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go"))
+		for _, want := range []string{
+			"orderAdminService := orderadminsvc.NewService(orderRepo)",
+			"orderctrl.NewController(orderService, orderAdminService)",
+		} {
+			if !strings.Contains(mainGo, want) {
+				t.Errorf("expected main.go to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("passes the new service to the controller tests", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		if result, err := scaffoldTests(registry, types.ScaffoldTestsInput{Domain: "order"}); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold tests: %v %s", err, result.Message)
+		}
+
+		result, err := refactorExtractService(registry, types.RefactorExtractServiceInput{
+			Domain:     "order",
+			NewService: "order_admin",
+			Methods:    []string{"Update", "Delete"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		controllerTest := readFile(t, filepath.Join(tmpDir, "internal", "web", "order", "order_test.go"))
+		if !strings.Contains(controllerTest, "NewController(service, service)") {
+			t.Error("expected the controller test to pass its stub as the new service")
+		}
+
+		// The order service tests still call Update and Delete
+		servicePath := filepath.ToSlash(filepath.Join("internal", "services", "order", "order_test.go"))
+		if !strings.Contains(result.Message, servicePath) {
+			t.Errorf("expected message to name %s, got %s", servicePath, result.Message)
+		}
+		if len(result.NextSteps) == 0 || !strings.Contains(result.NextSteps[0], "Move the tests of Delete, Update in "+servicePath) {
+			t.Errorf("expected a next step to move the service tests, got %v", result.NextSteps)
+		}
+	})
+
+	t.Run("rejects methods that depend on remaining methods", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		servicePath := filepath.Join(tmpDir, "internal", "services", "order", "order.go")
+		content := readFile(t, servicePath)
+		content = strings.Replace(content, "	// MCP:SERVICE_INTERFACE:START", "	Archive(ctx context.Context, id uint) error\n	// MCP:SERVICE_INTERFACE:START", 1)
+		content += "\n// Archive archives an Order.\nfunc (s *service) Archive(ctx context.Context, id uint) error {\n\treturn s.Delete(ctx, id)\n}\n"
+		if err := os.WriteFile(servicePath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := refactorExtractService(registry, types.RefactorExtractServiceInput{
+			Domain:     "order",
+			NewService: "order_archive",
+			Methods:    []string{"Archive"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Fatal("expected failure")
+		}
+		if !strings.Contains(result.Message, "s.Delete") {
+			t.Errorf("expected message to name the dependency, got %s", result.Message)
+		}
+	})
+
+	t.Run("takes unexported helpers along", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		servicePath := filepath.Join(tmpDir, "internal", "services", "order", "order.go")
+		content := readFile(t, servicePath)
+		content = strings.Replace(content, "	// MCP:SERVICE_INTERFACE:START", "	Approve(ctx context.Context, id uint) error\n	Reject(ctx context.Context, id uint) error\n	// MCP:SERVICE_INTERFACE:START", 1)
+		content += `
+func (s *service) Approve(ctx context.Context, id uint) error {
+	return s.moderate(ctx, id, "approved")
+}
+
+func (s *service) Reject(ctx context.Context, id uint) error {
+	return s.moderate(ctx, id, "rejected")
+}
+
+// moderate is only used by Approve and Reject.
+func (s *service) moderate(ctx context.Context, id uint, status string) error {
+	return s.find(ctx, id)
+}
+
+// find is shared with exists, which stays.
+func (s *service) find(ctx context.Context, id uint) error {
+	_, err := s.repo.FindByID(ctx, id)
+	return err
+}
+
+func (s *service) exists(ctx context.Context, id uint) bool {
+	return s.find(ctx, id) == nil
+}
+`
+		writeTestFile(t, servicePath, content)
+
+		result, err := refactorExtractService(registry, types.RefactorExtractServiceInput{
+			Domain:     "order",
+			NewService: "order_moderation",
+			Methods:    []string{"Approve", "Reject"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		newService := readFile(t, filepath.Join(tmpDir, "internal", "services", "ordermoderation", "ordermoderation.go"))
+		for _, want := range []string{"func (s *service) moderate(", "func (s *service) find("} {
+			if !strings.Contains(newService, want) {
+				t.Errorf("expected new service to contain %q", want)
+			}
+		}
+		iface, _, _ := strings.Cut(newService[strings.Index(newService, "type Service interface"):], "}")
+		if strings.Contains(iface, "moderate") || strings.Contains(iface, "find") {
+			t.Errorf("expected helpers to stay out of the Service interface, got %s", iface)
+		}
+
+		oldService := readFile(t, servicePath)
+		if strings.Contains(oldService, "func (s *service) moderate(") {
+			t.Error("expected moderate to move out of the order service")
+		}
+		if !strings.Contains(oldService, "func (s *service) find(") {
+			t.Error("expected find to stay in the order service, which still uses it")
+		}
+		if !strings.Contains(strings.Join(result.NextSteps, "\n"), "find stayed in the order service") {
+			t.Errorf("expected a next step about the copied helper, got %v", result.NextSteps)
+		}
+	})
+
+	t.Run("names fields the new service doesn't have", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		servicePath := filepath.Join(tmpDir, "internal", "services", "order", "order.go")
+		content := readFile(t, servicePath)
+		content = strings.Replace(content, "	// MCP:SERVICE_INTERFACE:START", "	Archive(ctx context.Context, id uint) error\n	// MCP:SERVICE_INTERFACE:START", 1)
+		content += "\nfunc (s *service) Archive(ctx context.Context, id uint) error {\n\ts.logger.Info(\"archived\")\n\treturn nil\n}\n"
+		writeTestFile(t, servicePath, content)
+
+		result, err := refactorExtractService(registry, types.RefactorExtractServiceInput{
+			Domain:     "order",
+			NewService: "order_archive",
+			Methods:    []string{"Archive"},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || !strings.Contains(result.Message, "s.logger, which the new service doesn't have") {
+			t.Errorf("expected failure naming s.logger, got %s", result.Message)
+		}
+	})
+
+	t.Run("dry run does not write files", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := refactorExtractService(registry, types.RefactorExtractServiceInput{
+			Domain:     "order",
+			NewService: "order_admin",
+			Methods:    []string{"Delete"},
+			DryRun:     true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+		if len(result.FilesCreated) != 1 {
+			t.Errorf("expected 1 file to be created, got %v", result.FilesCreated)
+		}
+		if _, err := os.Stat(filepath.Join(tmpDir, "internal", "services", "orderadmin")); !os.IsNotExist(err) {
+			t.Error("expected new service directory not to be created in dry run")
+		}
+	})
+}
@@ -2,11 +2,18 @@
 package tools
 
 import (
+	"fmt"
+	"io/fs"
 	"os"
+	"path/filepath"
+	"time"
 
+	"github.com/dbb1dev/go-mcp/internal/config"
 	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
 	"github.com/dbb1dev/go-mcp/internal/templates"
 	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -14,25 +21,126 @@ import (
 type Registry struct {
 	// WorkingDir is the base directory for scaffolding operations.
 	WorkingDir string
+	// Strict makes scaffold_domain and scaffold_wizard fail on warnings and roll back, as if every
+	// call passed strict: true. It is set from MCP_SCAFFOLD_STRICT.
+	Strict bool
+	// Roots are the directories the workdir argument of a tool call may select, or any of
+	// their subdirectories. Without roots, calls may only select WorkingDir and its
+	// subdirectories. It is set from MCP_SCAFFOLD_ROOTS.
+	Roots []string
+	// jobs runs the jobs of submit_scaffold_job.
+	jobs *jobQueue
 }
 
-// NewRegistry creates a new tool registry.
+// NewRegistry creates a new tool registry. When workingDir holds a .gomcp.toml that sets
+// project.root, such as the root of a monorepo, the registry works in that project.
 func NewRegistry(workingDir string) *Registry {
 	if workingDir == "" {
 		workingDir, _ = os.Getwd()
 	}
 	return &Registry{
-		WorkingDir: workingDir,
+		WorkingDir: config.ProjectDir(workingDir),
+		jobs:       newJobQueue(),
 	}
 }
 
-// NewGenerator creates a generator for the given project path.
+// NewGenerator creates a generator for the given project path. Files it overwrites are
+// backed up to a directory of this call under .mcp/backups.
 func (r *Registry) NewGenerator(projectPath string) *generator.Generator {
 	basePath := projectPath
 	if projectPath == "" {
 		basePath = r.WorkingDir
 	}
-	return generator.NewGenerator(templates.FS, basePath)
+	gen := generator.NewGenerator(r.TemplateFS(), basePath)
+	gen.SetBackupDir(newBackupDir())
+	return gen
+}
+
+// newBackupDir returns the directory, relative to the project, that keeps the previous
+// version of the files a tool call overwrites: .mcp/backups/<timestamp>.
+func newBackupDir() string {
+	return filepath.Join(metadata.MetadataDir, "backups", time.Now().Format("20060102-150405.000"))
+}
+
+// Defaults returns the tool input defaults of the project's .gomcp.toml.
+func (r *Registry) Defaults() (config.Defaults, error) {
+	cfg, err := config.Load(r.WorkingDir)
+	return cfg.Defaults, err
+}
+
+// TemplateFS returns the templates to generate files from: the built-in templates, with the
+// overrides of the project's templates directory when .gomcp.toml sets one.
+func (r *Registry) TemplateFS() fs.FS {
+	cfg, err := config.Load(r.WorkingDir)
+	if err != nil || cfg.TemplatesDir == "" {
+		return templates.FS
+	}
+	return templates.WithOverrides(filepath.Join(cfg.Dir, cfg.TemplatesDir))
+}
+
+// IsAPIProject reports whether the working directory was scaffolded with project_type api.
+// Projects without recorded project metadata are treated as web projects.
+func (r *Registry) IsAPIProject() bool {
+	project, err := metadata.NewStore(r.WorkingDir).GetProject()
+	return err == nil && project.ProjectType() == "api"
+}
+
+// HasAuth reports whether the project was scaffolded with auth, whose middleware puts the
+// session user in the request context.
+func (r *Registry) HasAuth() bool {
+	return utils.FileExists(filepath.Join(r.WorkingDir, "internal", "web", "middleware", "auth.go"))
+}
+
+// HasSEO reports whether the project has the internal/web/seo package, whose Defaults
+// middleware applies the [meta] of config/en/pages to the page <head>.
+func (r *Registry) HasSEO() bool {
+	return utils.FileExists(filepath.Join(r.WorkingDir, "internal", "web", "seo", "seo.go"))
+}
+
+// HasBreadcrumbs reports whether the project has the internal/web/breadcrumbs package, whose
+// Section middleware starts the breadcrumb trail the dashboard layout renders.
+func (r *Registry) HasBreadcrumbs() bool {
+	return utils.FileExists(filepath.Join(r.WorkingDir, "internal", "web", "breadcrumbs", "breadcrumbs.go"))
+}
+
+// HasStructuredLogging reports whether the project has the internal/logging package, whose
+// *slog.Logger main.go passes to the domain repositories, services and controllers.
+func (r *Registry) HasStructuredLogging() bool {
+	return utils.FileExists(filepath.Join(r.WorkingDir, "internal", "logging", "logging.go"))
+}
+
+// HasTestContainers reports whether the project has the internal/testutil package, whose DB
+// opens the database of repository tests on SQLite or a Postgres or MySQL container.
+func (r *Registry) HasTestContainers() bool {
+	return utils.FileExists(filepath.Join(r.WorkingDir, "internal", "testutil", "db.go"))
+}
+
+// UsesWire reports whether the project was scaffolded with di wire, whose domains are built
+// by the google/wire injector in cmd/web/wire.go instead of constructor calls in main.go.
+func (r *Registry) UsesWire() bool {
+	return utils.FileExists(filepath.Join(r.WorkingDir, "cmd", "web", "wire.go"))
+}
+
+// UsesRouteRegistry reports whether domains name their route group in their own routes.go,
+// for the routes.Registry in main.go, instead of having their routes injected into main.go.
+func (r *Registry) UsesRouteRegistry() bool {
+	return utils.FileExists(filepath.Join(r.WorkingDir, "internal", "web", "routes", "routes.go"))
+}
+
+// TSTypesFormat returns the format of the TypeScript types scaffold_ts_types generated in
+// web/src/types, "zod" or "dts", or "" when the project has none.
+func (r *Registry) TSTypesFormat() string {
+	for _, format := range []string{"zod", "dts"} {
+		if utils.FileExists(filepath.Join(r.WorkingDir, tsTypesDir, tsTypesFormats[format].index)) {
+			return format
+		}
+	}
+	return ""
+}
+
+// noViewsInAPIProject is the error result of view tools in API-only projects.
+func noViewsInAPIProject(tool string) types.ScaffoldResult {
+	return types.NewErrorResult(fmt.Sprintf("%s generates templ views, but this is an API-only project (project_type: api) without views", tool))
 }
 
 // CheckForConflicts checks if the generator has conflicts and returns a conflict result if so.
@@ -57,6 +165,9 @@ func CheckForConflicts(result generator.GeneratorResult) *types.ScaffoldResult {
 
 // RegisterAll registers all scaffolding tools with the server.
 func (r *Registry) RegisterAll(server *mcp.Server) {
+	// Every tool takes an optional workdir argument
+	server.AddReceivingMiddleware(r.workdirMiddleware)
+
 	// Phase 2: Project scaffolding
 	RegisterScaffoldProject(server, r)
 
@@ -76,11 +187,39 @@ func (r *Registry) RegisterAll(server *mcp.Server) {
 
 	// Phase 5: Configuration and utility tools
 	RegisterScaffoldPage(server, r)
+	RegisterScaffoldBlog(server, r)
 	RegisterScaffoldConfig(server, r)
 	RegisterScaffoldSeed(server, r)
+	RegisterScaffoldAPIMock(server, r)
+	RegisterScaffoldClient(server, r)
+	RegisterScaffoldTSTypes(server, r)
+	RegisterScaffoldRealtime(server, r)
+	RegisterScaffoldSearchIndex(server, r)
+	RegisterScaffoldTenancy(server, r)
+	RegisterScaffoldWebhooks(server, r)
+	RegisterScaffoldSettings(server, r)
+	RegisterScaffoldI18n(server, r)
+	RegisterScaffoldDeploy(server, r)
+	RegisterScaffoldCI(server, r)
+	RegisterScaffoldHealth(server, r)
+	RegisterScaffoldTests(server, r)
+	RegisterScaffoldFactory(server, r)
 	RegisterListDomains(server, r)
 	RegisterAnalyzeDomain(server, r)
+	RegisterSyncDomain(server, r)
+	RegisterAnalyzeProject(server, r)
+	RegisterReconstructMetadata(server, r)
+	RegisterTemplateChanges(server, r)
 	RegisterUpdateDIWiring(server, r)
+	RegisterExportDomain(server, r)
+	RegisterImportDomain(server, r)
+	RegisterUpgradeStack(server, r)
+	RegisterUpgradeProject(server, r)
+	RegisterLintMarkers(server, r)
+	RegisterCheckTranslations(server, r)
+	RegisterManageNav(server, r)
+	RegisterSubmitScaffoldJob(server, r)
+	RegisterGetJobStatus(server, r)
 
 	// Wizard tools
 	RegisterScaffoldWizard(server, r)
@@ -88,8 +227,14 @@ func (r *Registry) RegisterAll(server *mcp.Server) {
 	// Phase 6: Extend tools for custom logic
 	RegisterExtendRepository(server, r)
 	RegisterExtendService(server, r)
+	RegisterExtendModel(server, r)
 	RegisterExtendController(server, r)
+	RegisterRefactorExtractService(server, r)
 
 	// Utility tools
+	RegisterRenderPreview(server, r)
 	RegisterReportBug(server, r)
+
+	// Resources for referencing generated artifacts by URI
+	RegisterResources(server, r)
 }
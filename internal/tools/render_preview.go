@@ -0,0 +1,229 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// previewModulePath is used when the working directory has no go.mod.
+const previewModulePath = "example.com/app"
+
+// RegisterRenderPreview registers the render_preview tool.
+func RegisterRenderPreview(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "render_preview",
+		Description: `Render scaffolding templates with the given input and return the output without writing any files.
+
+Use this to inspect exactly what a template produces before running scaffold_domain or scaffold_wizard.
+Unlike dry_run, which lists the files that would change, this returns the rendered content per template.
+
+template can be:
+- A single template: "domain/model.go.tmpl", "views/form.templ.tmpl", "wizard/step_select.templ.tmpl",
+  or any other template scaffold_domain renders for the input, such as "api/controller.go.tmpl"
+- A directory: "domain" renders every file scaffold_domain would generate but the views, "views"
+  the CRUD views and "wizard" every template scaffold_wizard would use
+
+Template data:
+- domain/ and views/ templates use the domain input (same shape as scaffold_domain), with the
+  project's .gomcp.toml defaults and features applied as scaffold_domain applies them; each
+  render names the file it would be written to
+- wizard/ templates use the wizard input (same shape as scaffold_wizard)
+- wizard/step_* templates render the step given by step (1-based, defaults to 1)
+
+Example:
+  render_preview: {
+    template: "domain/model.go.tmpl",
+    domain: {domain_name: "product", fields: [{name: "Name", type: "string"}]}
+  }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.RenderPreviewInput) (*mcp.CallToolResult, types.RenderPreviewResult, error) {
+		result, err := renderPreview(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewRenderPreviewError(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func renderPreview(registry *Registry, input types.RenderPreviewInput) (types.RenderPreviewResult, error) {
+	name := strings.Trim(input.Template, "/")
+	if name == "" {
+		return types.NewRenderPreviewError("template is required"), nil
+	}
+
+	category, _, _ := strings.Cut(name, "/")
+	isDir := name == category
+	if isDir && category != "domain" && category != "views" && category != "wizard" {
+		return types.NewRenderPreviewError(fmt.Sprintf("unsupported directory '%s': must be domain, views or wizard", input.Template)), nil
+	}
+	if !isDir && !generator.TemplateExists(registry.TemplateFS(), name) {
+		return types.NewRenderPreviewError(fmt.Sprintf("template not found: %s", name)), nil
+	}
+
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		modulePath = previewModulePath
+	}
+
+	var renders []types.TemplateRender
+	if category == "wizard" {
+		renders, err = renderWizardPreview(registry, input, name, isDir, modulePath)
+	} else {
+		renders, err = renderDomainPreview(registry, input, name, modulePath)
+	}
+	if err != nil {
+		return types.NewRenderPreviewError(err.Error()), nil
+	}
+
+	return types.NewRenderPreviewResult(fmt.Sprintf("Rendered %d template(s) with module path %s", len(renders), modulePath), renders), nil
+}
+
+// renderDomainPreview renders the templates of the files scaffold_domain generates, with the
+// same input defaults and data.
+func renderDomainPreview(registry *Registry, input types.RenderPreviewInput, name, modulePath string) ([]types.TemplateRender, error) {
+	if input.Domain == nil {
+		return nil, fmt.Errorf("domain input is required for %s templates", name)
+	}
+	if err := utils.ValidateDomainName(input.Domain.DomainName); err != nil {
+		return nil, fmt.Errorf("invalid domain: %v", err)
+	}
+	if len(input.Domain.Fields) == 0 {
+		return nil, fmt.Errorf("at least one field is required")
+	}
+
+	domainInput := *input.Domain
+	if err := applyProjectDefaults(registry, &domainInput); err != nil {
+		return nil, err
+	}
+	data, err := newDomainData(registry, domainInput, modulePath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Shared files the project already has are not generated again
+	files := slices.DeleteFunc(domainFiles(registry, domainInput, data), func(f domainFile) bool {
+		return f.shared && utils.FileExists(filepath.Join(registry.WorkingDir, f.output))
+	})
+	isView := func(f domainFile) bool { return strings.HasPrefix(f.template, "views/") }
+
+	var selected []domainFile
+	switch {
+	case name == "domain":
+		selected = slices.DeleteFunc(slices.Clone(files), isView)
+	case name == "views":
+		selected = slices.DeleteFunc(slices.Clone(files), func(f domainFile) bool { return !isView(f) })
+		if len(selected) == 0 {
+			return nil, fmt.Errorf("domain '%s' has no views: with_crud_views is off", domainInput.DomainName)
+		}
+	default:
+		if i := slices.IndexFunc(files, func(f domainFile) bool { return f.template == name }); i >= 0 {
+			selected = files[i : i+1]
+		} else if strings.HasPrefix(name, "domain/") {
+			selected = []domainFile{{template: name}}
+		} else {
+			var templates []string
+			for _, f := range files {
+				templates = append(templates, f.template)
+			}
+			return nil, fmt.Errorf("scaffold_domain doesn't render %s for this input: use one of %s", name, strings.Join(templates, ", "))
+		}
+	}
+
+	renders := make([]types.TemplateRender, 0, len(selected))
+	for _, f := range selected {
+		output, err := generator.ExecuteTemplate(registry.TemplateFS(), f.template, data)
+		if err != nil {
+			return nil, err
+		}
+		renders = append(renders, types.TemplateRender{Template: f.template, Path: filepath.ToSlash(f.output), Output: output})
+	}
+	return renders, nil
+}
+
+// renderWizardPreview renders wizard/ templates with the same data scaffold_wizard uses.
+func renderWizardPreview(registry *Registry, input types.RenderPreviewInput, name string, isDir bool, modulePath string) ([]types.TemplateRender, error) {
+	if input.Wizard == nil {
+		return nil, fmt.Errorf("wizard input is required for %s templates", name)
+	}
+	if input.Wizard.WizardName == "" {
+		return nil, fmt.Errorf("wizard_name is required")
+	}
+	if err := utils.ValidateDomainName(input.Wizard.Domain); err != nil {
+		return nil, fmt.Errorf("invalid domain: %v", err)
+	}
+	if len(input.Wizard.Steps) == 0 {
+		return nil, fmt.Errorf("at least one step is required")
+	}
+
+	data := generator.NewWizardData(*input.Wizard, modulePath)
+	draftData := generator.WizardDraftData{ModulePath: modulePath}
+
+	var renders []types.TemplateRender
+	render := func(n string, step int, tmplData any) error {
+		output, err := generator.ExecuteTemplate(registry.TemplateFS(), n, tmplData)
+		if err != nil {
+			return err
+		}
+		renders = append(renders, types.TemplateRender{Template: n, Step: step, Output: output})
+		return nil
+	}
+
+	if isDir {
+		if err := render("wizard/controller.go.tmpl", 0, data); err != nil {
+			return nil, err
+		}
+		if err := render("wizard/wizard_view.templ.tmpl", 0, data); err != nil {
+			return nil, err
+		}
+		for i, step := range data.Steps {
+			if err := render(wizardStepTemplate(step.Type), i+1, wizardStepData{WizardData: data, Step: step}); err != nil {
+				return nil, err
+			}
+		}
+		if data.WithDrafts {
+			for _, n := range []string{"wizard/draft_model.go.tmpl", "wizard/draft_repository.go.tmpl", "wizard/draft_service.go.tmpl"} {
+				if err := render(n, 0, draftData); err != nil {
+					return nil, err
+				}
+			}
+		}
+		if data.WithTests {
+			if err := render("wizard/controller_test.go.tmpl", 0, data); err != nil {
+				return nil, err
+			}
+		}
+		return renders, nil
+	}
+
+	base := path.Base(name)
+	switch {
+	case strings.HasPrefix(base, "step_"):
+		step := input.Step
+		if step == 0 {
+			step = 1
+		}
+		if step < 1 || step > len(data.Steps) {
+			return nil, fmt.Errorf("step %d out of range: wizard has %d step(s)", step, len(data.Steps))
+		}
+		if err := render(name, step, wizardStepData{WizardData: data, Step: data.Steps[step-1]}); err != nil {
+			return nil, err
+		}
+	case strings.HasPrefix(base, "draft_"):
+		if err := render(name, 0, draftData); err != nil {
+			return nil, err
+		}
+	default:
+		if err := render(name, 0, data); err != nil {
+			return nil, err
+		}
+	}
+	return renders, nil
+}
@@ -0,0 +1,194 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestRenderPreview(t *testing.T) {
+	domain := &types.ScaffoldDomainInput{
+		DomainName: "product",
+		Fields: []types.FieldDef{
+			{Name: "Name", Type: "string"},
+			{Name: "Price", Type: "float64"},
+		},
+	}
+
+	t.Run("renders a single domain template", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		result, err := renderPreview(registry, types.RenderPreviewInput{
+			Template: "domain/model.go.tmpl",
+			Domain:   domain,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+		if len(result.Renders) != 1 {
+			t.Fatalf("expected 1 render, got %d", len(result.Renders))
+		}
+		if !strings.Contains(result.Renders[0].Output, "type Product struct") {
+			t.Errorf("expected model output, got:\n%s", result.Renders[0].Output)
+		}
+
+		entries, err := os.ReadDir(tmpDir)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(entries) != 1 {
+			t.Errorf("expected only go.mod in the working directory, got %d entries", len(entries))
+		}
+	})
+
+	t.Run("renders every template in a directory", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+
+		result, err := renderPreview(registry, types.RenderPreviewInput{
+			Template: "views",
+			Domain:   domain,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+		if len(result.Renders) != 4 {
+			t.Fatalf("expected the list, show, form and partials views, got %d renders", len(result.Renders))
+		}
+		if result.Renders[2].Path != "internal/web/product/views/product_form.templ" {
+			t.Errorf("expected the form view's path, got %q", result.Renders[2].Path)
+		}
+		if !strings.Contains(result.Renders[0].Output, `"example.com/app/internal/models"`) {
+			t.Error("expected the default module path without go.mod")
+		}
+	})
+
+	t.Run("renders the files scaffold_domain would generate", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		writeTestFile(t, filepath.Join(tmpDir, ".gomcp.toml"), "[defaults]\nform_style = 'page'\n")
+		writeTestFile(t, filepath.Join(tmpDir, "internal", "testutil", "db.go"), "package testutil\n")
+
+		withTests := *domain
+		withTests.WithTests = true
+		withTests.WithHooks = []string{"after_create"}
+		withTests.WithCache = true
+
+		result, err := renderPreview(registry, types.RenderPreviewInput{Template: "domain", Domain: &withTests})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		outputs := map[string]string{}
+		for _, render := range result.Renders {
+			outputs[render.Template] = render.Output
+		}
+		for _, want := range []string{"tests/repository_test.go.tmpl", "domain/hooks.go.tmpl", "events/events.go.tmpl", "cache/repository.go.tmpl", "domain/controller.go.tmpl"} {
+			if _, ok := outputs[want]; !ok {
+				t.Errorf("expected %s to be rendered", want)
+			}
+		}
+		if _, ok := outputs["views/list.templ.tmpl"]; ok {
+			t.Error("expected the views to be left to the views directory")
+		}
+		if !strings.Contains(outputs["tests/repository_test.go.tmpl"], `"github.com/example/testapp/internal/testutil"`) {
+			t.Error("expected the repository tests to use the project's test containers")
+		}
+
+		form, err := renderPreview(registry, types.RenderPreviewInput{Template: "views/form.templ.tmpl", Domain: domain})
+		if err != nil || !form.Success {
+			t.Fatalf("unexpected failure: %v %s", err, form.Message)
+		}
+		if !strings.Contains(form.Renders[0].Output, "Page-style form layout") {
+			t.Error("expected the form_style default of .gomcp.toml")
+		}
+	})
+
+	t.Run("renders the JSON controller of API projects", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		if err := metadata.NewStore(tmpDir).SaveProject(types.ScaffoldProjectInput{ProjectName: "testapp", ProjectType: "api"}, ScaffolderVersion); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := renderPreview(registry, types.RenderPreviewInput{Template: "domain", Domain: domain})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		var templates []string
+		for _, render := range result.Renders {
+			templates = append(templates, render.Template)
+		}
+		if !slices.Contains(templates, "api/controller.go.tmpl") || slices.Contains(templates, "domain/controller.go.tmpl") {
+			t.Errorf("expected the API controller, got %v", templates)
+		}
+
+		if views, _ := renderPreview(registry, types.RenderPreviewInput{Template: "views", Domain: domain}); views.Success {
+			t.Error("expected API projects to have no views to render")
+		}
+	})
+
+	t.Run("renders a wizard step", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+
+		result, err := renderPreview(registry, types.RenderPreviewInput{
+			Template: "wizard/step_select.templ.tmpl",
+			Wizard: &types.ScaffoldWizardInput{
+				WizardName: "create_order",
+				Domain:     "order",
+				Steps: []types.WizardStepDef{
+					{Name: "Details", Type: "form", Fields: []string{"Total"}},
+					{Name: "Customer", Type: "select", ChildDomain: "customer"},
+				},
+			},
+			Step: 2,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+		if len(result.Renders) != 1 || result.Renders[0].Step != 2 {
+			t.Fatalf("expected a single render for step 2, got %+v", result.Renders)
+		}
+	})
+
+	t.Run("validates input", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+
+		tests := []struct {
+			name  string
+			input types.RenderPreviewInput
+		}{
+			{"missing template", types.RenderPreviewInput{Domain: domain}},
+			{"unsupported category", types.RenderPreviewInput{Template: "project/main.go.tmpl", Domain: domain}},
+			{"unsupported directory", types.RenderPreviewInput{Template: "project", Domain: domain}},
+			{"unknown template", types.RenderPreviewInput{Template: "domain/missing.go.tmpl", Domain: domain}},
+			{"view without domain data", types.RenderPreviewInput{Template: "views/table.templ.tmpl", Domain: domain}},
+			{"missing domain input", types.RenderPreviewInput{Template: "domain/model.go.tmpl"}},
+			{"missing wizard input", types.RenderPreviewInput{Template: "wizard", Domain: domain}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := renderPreview(registry, tt.input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success {
+					t.Error("expected failure")
+				}
+			})
+		}
+	})
+}
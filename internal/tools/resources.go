@@ -0,0 +1,331 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/templates"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	// ResourceScheme is the URI scheme for scaffold resources.
+	ResourceScheme = "scaffold://"
+	// RoutesResourceURI lists every route registered by scaffolded controllers.
+	RoutesResourceURI = "scaffold://routes"
+	// DomainsResourceURI lists every scaffolded domain.
+	DomainsResourceURI = "scaffold://domains"
+	// TemplateChangesResourceURI is the template changelog.
+	TemplateChangesResourceURI = "scaffold://template-changes"
+	// DomainResourceTemplate exposes the scaffold metadata for a domain.
+	DomainResourceTemplate = "scaffold://domain/{name}"
+	// DomainLayerResourceTemplate exposes a generated file for a domain.
+	DomainLayerResourceTemplate = "scaffold://domain/{name}/{layer}"
+)
+
+// domainLayerPaths maps resource layer names to generated file paths.
+// %s is replaced with the domain package name.
+var domainLayerPaths = map[string]string{
+	"model":      "internal/models/%s.go",
+	"repository": "internal/repository/%s/%s.go",
+	"service":    "internal/services/%s/%s.go",
+	"dto":        "internal/services/%s/dto.go",
+	"controller": "internal/web/%s/%s.go",
+	"list":       "internal/web/%s/views/list.templ",
+	"show":       "internal/web/%s/views/show.templ",
+	"form":       "internal/web/%s/views/%s_form.templ",
+}
+
+// routeMountRegex matches controller mounts in main.go, e.g. router.Route("/products", productController.RegisterRoutes).
+var routeMountRegex = regexp.MustCompile(`\.Route\("([^"]*)",\s*(\w+)\.RegisterRoutes\)`)
+
+// routeGroupMethodRegex and routePathMethodRegex match the route group and path a domain's
+// routes.go returns, e.g. return routes.Authenticated and return "/products". registryAddRegex
+// matches the controllers main.go adds to the routes registry.
+var (
+	routeGroupMethodRegex = regexp.MustCompile(`RouteGroup\(\) routes\.Group \{\s*return routes\.(?:Group\("(\w+)"\)|(\w+))`)
+	routePathMethodRegex  = regexp.MustCompile(`RoutePath\(\) string \{\s*return "([^"]*)"`)
+	registryAddRegex      = regexp.MustCompile(modifier.DomainRoutesVariable + `\.Add\((\w+)\)`)
+)
+
+// apiVersionMarkerRegex matches the start marker of an API version sub-router in main.go.
+var apiVersionMarkerRegex = regexp.MustCompile(`MCP:ROUTES:API_(V[0-9]+):START`)
+
+// controllerRouteRegex matches route registrations in a controller's RegisterRoutes, e.g. r.Get("/{id}", c.Show).
+var controllerRouteRegex = regexp.MustCompile(`r\.(Get|Post|Put|Patch|Delete)\("([^"]*)",\s*c\.(\w+)\)`)
+
+// RegisterResources registers the scaffold resources and resource templates.
+// Resources let other MCP servers and clients in the same session reference
+// generated artifacts by URI (e.g., scaffold://domain/product/model).
+func RegisterResources(server *mcp.Server, registry *Registry) {
+	server.AddResource(&mcp.Resource{
+		URI:         RoutesResourceURI,
+		Name:        "routes",
+		Title:       "Scaffolded routes",
+		Description: "All HTTP routes registered by scaffolded controllers, with their mount path and route group.",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		routes, err := collectRoutes(registry)
+		if err != nil {
+			return nil, err
+		}
+		return jsonResource(req.Params.URI, routes)
+	})
+
+	server.AddResource(&mcp.Resource{
+		URI:         DomainsResourceURI,
+		Name:        "domains",
+		Title:       "Scaffolded domains",
+		Description: "All domains found in the project and which layers exist for each.",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		result, err := listDomains(registry)
+		if err != nil {
+			return nil, err
+		}
+		return jsonResource(req.Params.URI, result.Domains)
+	})
+
+	server.AddResource(&mcp.Resource{
+		URI:         TemplateChangesResourceURI,
+		Name:        "template-changes",
+		Title:       "Template changelog",
+		Description: "The changes to the scaffolding templates per scaffolder version, with the templates each change touched.",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		changelog, err := templates.Changelog()
+		if err != nil {
+			return nil, err
+		}
+		return jsonResource(req.Params.URI, changelog)
+	})
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: DomainResourceTemplate,
+		Name:        "domain",
+		Title:       "Domain scaffold metadata",
+		Description: "The scaffold_domain input and scaffolder version recorded for a domain.",
+		MIMEType:    "application/json",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return readDomainResource(registry, req.Params.URI)
+	})
+
+	server.AddResourceTemplate(&mcp.ResourceTemplate{
+		URITemplate: DomainLayerResourceTemplate,
+		Name:        "domain-layer",
+		Title:       "Generated domain file",
+		Description: "The current source of a generated domain file. Layers: model, repository, service, dto, controller, list, show, form.",
+	}, func(ctx context.Context, req *mcp.ReadResourceRequest) (*mcp.ReadResourceResult, error) {
+		return readDomainResource(registry, req.Params.URI)
+	})
+}
+
+// readDomainResource resolves scaffold://domain/{name} and scaffold://domain/{name}/{layer} URIs.
+func readDomainResource(registry *Registry, uri string) (*mcp.ReadResourceResult, error) {
+	rest, ok := strings.CutPrefix(uri, ResourceScheme+"domain/")
+	if !ok || rest == "" {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	name, layer, hasLayer := strings.Cut(rest, "/")
+	if err := utils.ValidateDomainName(name); err != nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	if !hasLayer {
+		domain, exists, err := metadata.NewStore(registry.WorkingDir).GetDomain(name)
+		if err != nil {
+			return nil, err
+		}
+		if !exists {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		return jsonResource(uri, domain)
+	}
+
+	path, err := domainLayerPath(name, layer)
+	if err != nil {
+		return nil, mcp.ResourceNotFoundError(uri)
+	}
+
+	content, err := os.ReadFile(filepath.Join(registry.WorkingDir, path))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, mcp.ResourceNotFoundError(uri)
+		}
+		return nil, err
+	}
+
+	mimeType := "text/x-go"
+	if strings.HasSuffix(path, ".templ") {
+		mimeType = "text/x-templ"
+	}
+
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: uri, MIMEType: mimeType, Text: string(content)},
+		},
+	}, nil
+}
+
+// domainLayerPath returns the project-relative path of a domain's generated file for a layer.
+func domainLayerPath(domainName, layer string) (string, error) {
+	pattern, ok := domainLayerPaths[layer]
+	if !ok {
+		return "", fmt.Errorf("unknown layer '%s'", layer)
+	}
+	pkgName := utils.ToPackageName(domainName)
+	args := make([]any, strings.Count(pattern, "%s"))
+	for i := range args {
+		args[i] = pkgName
+	}
+	return fmt.Sprintf(pattern, args...), nil
+}
+
+// collectRoutes builds the route table from cmd/web/main.go mounts and each controller's RegisterRoutes.
+func collectRoutes(registry *Registry) ([]types.RouteInfo, error) {
+	routes := []types.RouteInfo{}
+
+	mainGoPath := filepath.Join(registry.WorkingDir, "cmd", "web", "main.go")
+	if !utils.FileExists(mainGoPath) {
+		return routes, nil
+	}
+	mainContent, err := utils.ReadFileString(mainGoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Map controller variables to their mount path and route group
+	type mount struct {
+		path       string
+		routeGroup string
+	}
+	mounts := make(map[string]mount)
+	for _, m := range routeMountRegex.FindAllStringSubmatchIndex(mainContent, -1) {
+		path := mainContent[m[2]:m[3]]
+		varName := mainContent[m[4]:m[5]]
+		routeGroup := routeGroupAt(mainContent, m[0])
+		if version := utils.APIVersionFromRouteGroup(routeGroup); version != "" {
+			path = "/api/" + version + path
+		}
+		mounts[varName] = mount{path: path, routeGroup: routeGroup}
+	}
+
+	webDir := filepath.Join(registry.WorkingDir, "internal", "web")
+	dirs, _ := utils.ListDirs(webDir)
+	for _, dir := range dirs {
+		controllerPath := filepath.Join(webDir, dir, dir+".go")
+		content, err := utils.ReadFileString(controllerPath)
+		if err != nil {
+			continue
+		}
+
+		m, mounted := mounts[utils.ToControllerVariableName(dir)]
+		if !mounted {
+			if path, routeGroup, ok := registeredRoute(registry.WorkingDir, mainContent, dir); ok {
+				if version := utils.APIVersionFromRouteGroup(routeGroup); version != "" {
+					path = "/api/" + version + path
+				}
+				m, mounted = mount{path: path, routeGroup: routeGroup}, true
+			}
+		}
+		for _, match := range controllerRouteRegex.FindAllStringSubmatch(content, -1) {
+			route := types.RouteInfo{
+				Domain:  dir,
+				Method:  strings.ToUpper(match[1]),
+				Path:    match[2],
+				Handler: match[3],
+			}
+			if mounted {
+				route.Path = strings.TrimSuffix(m.path+match[2], "/")
+				if route.Path == "" {
+					route.Path = "/"
+				}
+				route.RouteGroup = m.routeGroup
+			}
+			routes = append(routes, route)
+		}
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].Domain != routes[j].Domain {
+			return routes[i].Domain < routes[j].Domain
+		}
+		return routes[i].Path < routes[j].Path
+	})
+
+	return routes, nil
+}
+
+// registeredRoute returns the path and route group of a domain controller that main.go adds to
+// the routes registry, as its routes.go names them. The package name loses the underscores of
+// the domain name, so the controller variable is matched case-insensitively.
+func registeredRoute(workingDir, mainContent, pkg string) (path, routeGroup string, ok bool) {
+	added := false
+	for _, m := range registryAddRegex.FindAllStringSubmatch(mainContent, -1) {
+		added = added || strings.EqualFold(m[1], utils.ToControllerVariableName(pkg))
+	}
+	if !added {
+		return "", "", false
+	}
+	content, err := utils.ReadFileString(filepath.Join(workingDir, "internal", "web", pkg, "routes.go"))
+	if err != nil {
+		return "", "", false
+	}
+	pathMatch := routePathMethodRegex.FindStringSubmatch(content)
+	groupMatch := routeGroupMethodRegex.FindStringSubmatch(content)
+	if pathMatch == nil || groupMatch == nil {
+		return "", "", false
+	}
+	routeGroup = groupMatch[1]
+	if routeGroup == "" {
+		routeGroup = strings.ToLower(groupMatch[2])
+	}
+	return pathMatch[1], routeGroup, true
+}
+
+// routeGroupAt returns the route group whose marker section contains the given offset in main.go.
+func routeGroupAt(content string, offset int) string {
+	before := content[:offset]
+	group := "public"
+	last := strings.LastIndex(before, modifier.MarkerRoutesPublicStart)
+	if idx := strings.LastIndex(before, modifier.MarkerRoutesAuthenticatedStart); idx > last {
+		group, last = "authenticated", idx
+	}
+	if idx := strings.LastIndex(before, modifier.MarkerRoutesAdminStart); idx > last {
+		group, last = "admin", idx
+	}
+	// Versioned API sub-routers sit inside a group, so only count one that is still open
+	if m := apiVersionMarkerRegex.FindAllStringSubmatchIndex(before, -1); len(m) > 0 {
+		open := m[len(m)-1]
+		version := strings.ToLower(before[open[2]:open[3]])
+		_, endMarker := modifier.APIVersionMarkers(version)
+		if open[0] > last && !strings.Contains(before[open[1]:], endMarker) {
+			group = "api_" + version
+		}
+	}
+	return group
+}
+
+// jsonResource returns a resource result with the value encoded as indented JSON.
+func jsonResource(uri string, v any) (*mcp.ReadResourceResult, error) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return &mcp.ReadResourceResult{
+		Contents: []*mcp.ResourceContents{
+			{URI: uri, MIMEType: "application/json", Text: string(data)},
+		},
+	}, nil
+}
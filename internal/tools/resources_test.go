@@ -0,0 +1,163 @@
+package tools
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestReadDomainResource(t *testing.T) {
+	registry, tmpDir := testRegistry(t)
+	setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+	input := types.ScaffoldDomainInput{
+		DomainName: "product",
+		Fields: []types.FieldDef{
+			{Name: "Name", Type: "string"},
+		},
+	}
+	if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+		t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+	}
+
+	t.Run("returns domain metadata", func(t *testing.T) {
+		res, err := readDomainResource(registry, "scaffold://domain/product")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(res.Contents) != 1 || res.Contents[0].MIMEType != "application/json" {
+			t.Fatalf("expected one JSON content, got %+v", res.Contents)
+		}
+		if !strings.Contains(res.Contents[0].Text, `"domain_name": "product"`) {
+			t.Errorf("expected metadata to include the scaffold input, got %s", res.Contents[0].Text)
+		}
+	})
+
+	t.Run("returns generated layer source", func(t *testing.T) {
+		res, err := readDomainResource(registry, "scaffold://domain/product/model")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !strings.Contains(res.Contents[0].Text, "type Product struct") {
+			t.Errorf("expected model source, got %s", res.Contents[0].Text)
+		}
+		if res.Contents[0].URI != "scaffold://domain/product/model" {
+			t.Errorf("expected content URI to match request, got %s", res.Contents[0].URI)
+		}
+	})
+
+	t.Run("returns not found for unknown resources", func(t *testing.T) {
+		uris := []string{
+			"scaffold://domain/order",
+			"scaffold://domain/product/unknown",
+			"scaffold://domain/order/model",
+			"scaffold://domain/",
+		}
+		for _, uri := range uris {
+			if _, err := readDomainResource(registry, uri); err == nil {
+				t.Errorf("expected error for %s", uri)
+			}
+		}
+	})
+}
+
+func TestCollectRoutes(t *testing.T) {
+	t.Run("returns empty list without main.go", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+
+		routes, err := collectRoutes(registry)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(routes) != 0 {
+			t.Errorf("expected no routes, got %d", len(routes))
+		}
+	})
+
+	t.Run("includes mount path and route group", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, `package main
+
+func main() {
+	// MCP:IMPORTS:START
+	// MCP:IMPORTS:END
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	router.Group(func(r chi.Router) {
+		// MCP:ROUTES:ADMIN:START
+		// MCP:ROUTES:ADMIN:END
+	})
+	// MCP:ROUTES:END
+}
+`)
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "category",
+			RouteGroup: "admin",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string"},
+			},
+		}
+		if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+
+		routes, err := collectRoutes(registry)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		found := false
+		for _, route := range routes {
+			if route.Method == "GET" && route.Path == "/categories/{id}" && route.Handler == "Show" {
+				found = true
+				if route.RouteGroup != "admin" {
+					t.Errorf("expected route group admin, got %q", route.RouteGroup)
+				}
+			}
+		}
+		if !found {
+			data, _ := json.Marshal(routes)
+			t.Errorf("expected GET /categories/{id} route, got %s", data)
+		}
+	})
+}
+
+func TestDomainLayerPath(t *testing.T) {
+	tests := []struct {
+		domain string
+		layer  string
+		want   string
+	}{
+		{"product", "model", "internal/models/product.go"},
+		{"order_item", "repository", "internal/repository/orderitem/orderitem.go"},
+		{"product", "dto", "internal/services/product/dto.go"},
+		{"product", "form", "internal/web/product/views/product_form.templ"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain+"/"+tt.layer, func(t *testing.T) {
+			got, err := domainLayerPath(tt.domain, tt.layer)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("domainLayerPath(%q, %q) = %q, want %q", tt.domain, tt.layer, got, tt.want)
+			}
+		})
+	}
+
+	if _, err := domainLayerPath("product", "unknown"); err == nil {
+		t.Error("expected error for unknown layer")
+	}
+}
@@ -0,0 +1,144 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterScaffoldAPIMock registers the scaffold_api_mock tool.
+func RegisterScaffoldAPIMock(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_api_mock",
+		Description: `Generate a standalone mock API server that serves fake data for the project's domain endpoints.
+
+Frontend teams can develop against realistic endpoints before the backend is finished.
+The server is generated at tools/mockapi/main.go and run with: go run ./tools/mockapi
+
+Features:
+- One in-memory collection per domain at the same path as the real API (e.g., /products)
+- Fake records use the same faker mappings as scaffold_seed (with_faker) and the model's JSON field names
+- List, show, create, update and delete, with page, page_size, search, sort and dir (asc|desc)
+- Same list envelope as the real API: items, total, page, page_size, total_pages, total_items
+- CORS enabled for any origin; optional artificial latency
+- count, port and latency_ms set the defaults; -count, -port and -latency flags override them at run time
+
+Domains are read from scaffold metadata (.mcp/scaffold-metadata.json), so they must have been
+created with scaffold_domain. domains limits the mock to a subset; by default all are included.
+
+Re-run the tool after scaffolding new domains; it regenerates (overwrites) tools/mockapi/main.go.`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldAPIMockInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldAPIMock)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldAPIMock(registry *Registry, input types.ScaffoldAPIMockInput) (types.ScaffoldResult, error) {
+	if input.Count < 0 || input.Port < 0 || input.Port > 65535 || input.LatencyMS < 0 {
+		return types.NewErrorResult("count, port and latency_ms must be positive (port at most 65535)"), nil
+	}
+
+	// Get module path from go.mod
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	metaStore := metadata.NewStore(registry.WorkingDir)
+	meta, err := metaStore.Load()
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to read scaffold metadata: %v", err)), nil
+	}
+
+	names := input.Domains
+	if len(names) == 0 {
+		for name := range meta.Domains {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	if len(names) == 0 {
+		return types.NewErrorResult("no domains with metadata found: scaffold a domain first, or check .mcp/scaffold-metadata.json exists"), nil
+	}
+
+	domains := make([]generator.DomainData, 0, len(names))
+	var missing []string
+	for _, name := range names {
+		domain, ok := meta.Domains[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		domains = append(domains, generator.NewDomainData(domain.Input, modulePath))
+	}
+	if len(missing) > 0 {
+		return types.NewErrorResult(fmt.Sprintf("no scaffold metadata for domain(s): %s", strings.Join(missing, ", "))), nil
+	}
+
+	data := generator.MockAPIData{
+		ModulePath: modulePath,
+		Port:       input.Port,
+		Count:      input.Count,
+		LatencyMS:  input.LatencyMS,
+		Domains:    domains,
+	}
+	if data.Port == 0 {
+		data.Port = 8081
+	}
+	if data.Count == 0 {
+		data.Count = 20
+	}
+
+	// Create generator
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+	gen.SetForceOverwrite(true)
+
+	mockDir := filepath.Join("tools", "mockapi")
+	if err := gen.EnsureDir(mockDir); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to create directory: %v", err)), nil
+	}
+
+	if err := gen.GenerateFile("mockapi/main.go.tmpl", filepath.Join(mockDir, "main.go"), data); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to generate mock API server: %v", err)), nil
+	}
+
+	result := gen.Result()
+
+	nextSteps := []string{
+		"go get github.com/brianvoe/gofakeit/v6",
+		"go run ./tools/mockapi",
+		fmt.Sprintf("Point the frontend at http://localhost:%d", data.Port),
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would create mock API server for %d domain(s)", len(domains)),
+			FilesCreated: result.FilesCreated,
+			FilesUpdated: result.FilesUpdated,
+			Backups:      result.Backups,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully created mock API server for %d domain(s)", len(domains)),
+		FilesCreated: result.FilesCreated,
+		FilesUpdated: result.FilesUpdated,
+		Backups:      result.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
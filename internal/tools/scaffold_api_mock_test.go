@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldAPIMock(t *testing.T) {
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		for _, input := range []types.ScaffoldDomainInput{
+			{DomainName: "customer", Fields: []types.FieldDef{{Name: "Email", Type: "string"}}},
+			{
+				DomainName:    "order",
+				Fields:        []types.FieldDef{{Name: "Total", Type: "float64"}, {Name: "Name", Type: "string"}},
+				Relationships: []types.RelationshipDef{{Type: "belongs_to", Model: "Customer"}},
+			},
+		} {
+			if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+				t.Fatalf("failed to scaffold domain %s: %v %s", input.DomainName, err, result.Message)
+			}
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("generates a mock server for every domain", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := scaffoldAPIMock(registry, types.ScaffoldAPIMockInput{Port: 9090})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "tools", "mockapi", "main.go"))
+		for _, want := range []string{
+			"package main",
+			`flag.Int("port", 9090,`,
+			`flag.Int("count", 20,`,
+			`.register(mux, "/customers")`,
+			`.register(mux, "/orders")`,
+			`newCollection(*count, "name",`,
+			`"total": gofakeit.Float64Range(0, 1000),`,
+			`"customer_id": uint(gofakeit.Number(1, count)),`,
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected mock server to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("limits the mock to the given domains", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := scaffoldAPIMock(registry, types.ScaffoldAPIMockInput{Domains: []string{"customer"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "tools", "mockapi", "main.go"))
+		if strings.Contains(content, "/orders") {
+			t.Error("expected orders to be left out of the mock")
+		}
+	})
+
+	t.Run("validates input", func(t *testing.T) {
+		registry, _ := setup(t)
+
+		tests := []struct {
+			name  string
+			input types.ScaffoldAPIMockInput
+		}{
+			{"unknown domain", types.ScaffoldAPIMockInput{Domains: []string{"invoice"}}},
+			{"negative count", types.ScaffoldAPIMockInput{Count: -1}},
+			{"port out of range", types.ScaffoldAPIMockInput{Port: 70000}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := scaffoldAPIMock(registry, tt.input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success {
+					t.Error("expected failure")
+				}
+			})
+		}
+	})
+
+	t.Run("requires scaffolded domains", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		result, err := scaffoldAPIMock(registry, types.ScaffoldAPIMockInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure without domain metadata")
+		}
+	})
+}
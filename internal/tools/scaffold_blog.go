@@ -0,0 +1,287 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// blogJoinTable is the many_to_many join table of posts and tags.
+const blogJoinTable = "posts_tags"
+
+// RegisterScaffoldBlog registers the scaffold_blog tool.
+func RegisterScaffoldBlog(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_blog",
+		Description: `Add a blog: posts written in Markdown by signed-in users, public list and post pages
+and RSS and Atom feeds.
+
+Composes two domains with scaffold_domain, so posts and tags get the usual model, repository,
+service, controller, CRUD views, sidebar links and metadata:
+- tag: Name and Slug, at /tags
+- post: Title, Slug, Summary, Body (Markdown) and TagList, at /posts with full page forms,
+  scheduled publishing (PublishAt/UnpublishAt, kept current by the scheduler) and a
+  many_to_many Tags relationship (join table posts_tags)
+
+Adds blog-specific code:
+- internal/services/post/blog.go: hooks that derive the slug from the title and create and
+  link the comma-separated tags of TagList; internal/services/tag/blog.go derives tag slugs
+- internal/slug/slug.go: slug.Make
+- internal/markdown/markdown.go: Markdown (GitHub flavored) to HTML with goldmark, code blocks
+  highlighted by Chroma; raw HTML in posts is dropped
+- internal/services/blog/blog.go: published posts, newest first, by page and by tag
+- internal/web/blog: {path} (list), {path}/tags/{slug}, {path}/{slug} (post),
+  {path}/feed.xml (RSS 2.0) and {path}/atom.xml (Atom), mounted with the public routes,
+  with site and footer menu links. Feeds link to BASE_URL when it is set
+
+Requires a web project with auth (scaffold_project with_auth); the post and tag domains must
+not exist yet.
+
+Example:
+  scaffold_blog: { title: "Engineering blog", path: "/blog", posts_per_page: 10 }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldBlogInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldBlog)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldBlog(registry *Registry, input types.ScaffoldBlogInput) (types.ScaffoldResult, error) {
+	if registry.IsAPIProject() {
+		return noViewsInAPIProject("scaffold_blog"), nil
+	}
+	if !registry.HasAuth() {
+		return types.NewErrorResult("scaffold_blog needs auth for its authoring pages: scaffold the project with with_auth"), nil
+	}
+
+	title := input.Title
+	if title == "" {
+		title = "Blog"
+	}
+	blogPath := input.Path
+	if blogPath == "" {
+		blogPath = "/blog"
+	}
+	if err := utils.ValidateURLPath(blogPath); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	if blogPath == "/" || blogPath == "/posts" || blogPath == "/tags" {
+		return types.NewErrorResult(fmt.Sprintf("path must not be %s: the blog needs a path of its own, apart from the /posts and /tags authoring pages (e.g., /blog)", blogPath)), nil
+	}
+	perPage := input.PostsPerPage
+	if perPage == 0 {
+		perPage = 10
+	}
+	if perPage < 1 || perPage > 100 {
+		return types.NewErrorResult("posts_per_page must be between 1 and 100"), nil
+	}
+
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	store := metadata.NewStore(registry.WorkingDir)
+	for _, domain := range []string{"post", "tag"} {
+		exists, err := store.Exists(domain)
+		if err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to read metadata: %v", err)), nil
+		}
+		if exists || utils.FileExists(filepath.Join(registry.WorkingDir, "internal", "models", domain+".go")) {
+			return types.NewErrorResult(fmt.Sprintf("the %s domain already exists: scaffold_blog creates the post and tag domains", domain)), nil
+		}
+	}
+
+	// The blog files are checked before the domains are scaffolded, so a conflict leaves the project unchanged
+	data := generator.BlogData{
+		ModulePath:   modulePath,
+		Title:        title,
+		Path:         blogPath,
+		PostsPerPage: perPage,
+		JoinTable:    blogJoinTable,
+		WithSEO:      registry.HasSEO(),
+	}
+	files := []struct{ tmpl, out string }{
+		{"blog/slug.go.tmpl", filepath.Join("internal", "slug", "slug.go")},
+		{"blog/markdown.go.tmpl", filepath.Join("internal", "markdown", "markdown.go")},
+		{"blog/post_hooks.go.tmpl", filepath.Join("internal", "services", "post", "blog.go")},
+		{"blog/tag_hooks.go.tmpl", filepath.Join("internal", "services", "tag", "blog.go")},
+		{"blog/service.go.tmpl", filepath.Join("internal", "services", "blog", "blog.go")},
+		{"blog/controller.go.tmpl", filepath.Join("internal", "web", "blog", "blog.go")},
+		{"blog/feed.go.tmpl", filepath.Join("internal", "web", "blog", "feed.go")},
+		{"blog/views.templ.tmpl", filepath.Join("internal", "web", "blog", "views", "blog.templ")},
+	}
+	check := registry.NewGenerator("")
+	check.SetDryRun(true)
+	for _, f := range files {
+		if err := check.GenerateFile(f.tmpl, f.out, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.out, err)), nil
+		}
+	}
+	if conflictResult := CheckForConflicts(check.Result()); conflictResult != nil {
+		return *conflictResult, nil
+	}
+
+	var filesCreated, filesUpdated, nextSteps []string
+	for _, domainInput := range blogDomains(input.DryRun) {
+		result, err := scaffoldDomain(registry, domainInput)
+		if err != nil {
+			return result, err
+		}
+		if !result.Success {
+			result.Message = fmt.Sprintf("scaffold_blog could not create the %s domain: %s", domainInput.DomainName, result.Message)
+			return result, nil
+		}
+		filesCreated = append(filesCreated, result.FilesCreated...)
+		filesUpdated = appendUnique(filesUpdated, result.FilesUpdated...)
+		nextSteps = appendUnique(nextSteps, result.NextSteps...)
+	}
+
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+	for _, f := range files {
+		if err := gen.GenerateFile(f.tmpl, f.out, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.out, err)), nil
+		}
+	}
+	result := gen.Result()
+	filesCreated = append(filesCreated, result.FilesCreated...)
+
+	nextSteps = appendUnique(nextSteps, "go get github.com/yuin/goldmark github.com/yuin/goldmark-highlighting/v2")
+	if !input.DryRun {
+		mainGoPath := filepath.Join(registry.WorkingDir, "cmd", "web", "main.go")
+		if err := injectBlogWiring(mainGoPath, modulePath, blogPath); err != nil {
+			nextSteps = append(nextSteps, fmt.Sprintf("Wire the blog in cmd/web/main.go: blogService := blogsvc.NewService(db), blogController := blogweb.NewController(blogService) and router.Route(%q, blogController.RegisterRoutes) with the public routes", blogPath))
+		} else {
+			filesUpdated = appendUnique(filesUpdated, "cmd/web/main.go")
+		}
+
+		menuGoPath := filepath.Join("internal", "web", "menu", "menu.go")
+		if added, err := addSiteMenuSections(filepath.Join(registry.WorkingDir, menuGoPath)); err != nil {
+			nextSteps = append(nextSteps, "Add the site and footer sections to internal/web/menu/menu.go: Site and Footer fields (toml:\"site\", toml:\"footer\") and their Site() and Footer() accessors")
+		} else if added {
+			filesUpdated = appendUnique(filesUpdated, menuGoPath)
+		}
+
+		menuPath := filepath.Join("config", "en", "menu.toml")
+		if err := injectBlogMenuLinks(filepath.Join(registry.WorkingDir, menuPath), title, blogPath); err != nil {
+			nextSteps = append(nextSteps, fmt.Sprintf("Link %s from the site section of config/en/menu.toml", blogPath))
+		} else {
+			filesUpdated = appendUnique(filesUpdated, menuPath)
+		}
+	}
+	nextSteps = append(nextSteps,
+		"Write posts at /posts: set a publish date to publish them, tags are comma-separated",
+		"Set BASE_URL (e.g., https://example.com) in production so the feeds link to the public host",
+	)
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would create the blog at '%s' with the post and tag domains", blogPath),
+			FilesCreated: filesCreated,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully created the blog at '%s' with the post and tag domains", blogPath),
+		FilesCreated: filesCreated,
+		FilesUpdated: filesUpdated,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// blogDomains returns the scaffold_domain inputs of the tag and post domains, in that order
+// so the post's relationship finds the Tag model. Both are written by any signed-in user.
+func blogDomains(dryRun bool) []types.ScaffoldDomainInput {
+	shared := false
+	return []types.ScaffoldDomainInput{
+		{
+			DomainName: "tag",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string", GORMTags: "size:100;not null", Required: true},
+				{Name: "Slug", Type: "string", GORMTags: "size:100;uniqueIndex", Label: "Slug (derived from the name when empty)"},
+			},
+			RouteGroup:  "authenticated",
+			OwnedByUser: &shared,
+			WithHooks:   []string{"before_save"},
+			DryRun:      dryRun,
+		},
+		{
+			DomainName: "post",
+			Fields: []types.FieldDef{
+				{Name: "Title", Type: "string", GORMTags: "size:200;not null", Required: true},
+				{Name: "Slug", Type: "string", GORMTags: "size:200;uniqueIndex", Label: "Slug (derived from the title when empty)"},
+				{Name: "Summary", Type: "string", FormType: "textarea"},
+				{Name: "Body", Type: "string", GORMTags: "type:text", FormType: "textarea", Required: true, Label: "Body (Markdown)"},
+				{Name: "TagList", Type: "string", Label: "Tags (comma-separated)"},
+			},
+			Relationships: []types.RelationshipDef{
+				{Type: "many_to_many", Model: "Tag", JoinTable: blogJoinTable, DisplayField: "Name"},
+			},
+			RouteGroup:     "authenticated",
+			OwnedByUser:    &shared,
+			FormStyle:      "page",
+			WithScheduling: true,
+			WithHooks:      []string{"before_save", "after_save"},
+			DryRun:         dryRun,
+		},
+	}
+}
+
+// injectBlogWiring creates the blog service and controller in main.go and mounts the blog
+// with the public routes.
+func injectBlogWiring(mainGoPath, modulePath, blogPath string) error {
+	injector, err := modifier.NewInjector(mainGoPath)
+	if err != nil {
+		return err
+	}
+	if !injector.HasMarker(modifier.MarkerServicesStart) || !injector.HasMarker(modifier.MarkerControllersStart) {
+		return fmt.Errorf("main.go has no services or controllers markers")
+	}
+	if err := injector.InjectImportWithAlias(modulePath+"/internal/services/blog", "blogsvc"); err != nil {
+		return err
+	}
+	if err := injector.InjectImportWithAlias(modulePath+"/internal/web/blog", "blogweb"); err != nil {
+		return err
+	}
+	if err := injector.InjectBetweenMarkers(modifier.MarkerServicesStart, modifier.MarkerServicesEnd, "blogService := blogsvc.NewService(db)"); err != nil {
+		return err
+	}
+	if err := injector.InjectBetweenMarkers(modifier.MarkerControllersStart, modifier.MarkerControllersEnd, "blogController := blogweb.NewController(blogService)"); err != nil {
+		return err
+	}
+	start, end := modifier.MarkerRoutesPublicStart, modifier.MarkerRoutesPublicEnd
+	if !injector.HasMarker(start) {
+		start, end = modifier.MarkerRoutesStart, modifier.MarkerRoutesEnd
+	}
+	if err := injector.InjectBetweenMarkers(start, end, fmt.Sprintf("router.Route(%q, blogController.RegisterRoutes)", blogPath)); err != nil {
+		return err
+	}
+	return injector.Save()
+}
+
+// injectBlogMenuLinks links the blog from the site header and footer.
+func injectBlogMenuLinks(menuPath, title, blogPath string) error {
+	injector, err := modifier.NewInjector(menuPath)
+	if err != nil {
+		return err
+	}
+	for _, section := range []string{"site", "footer"} {
+		if err := injector.InjectMenuLink(section, title, blogPath, ""); err != nil {
+			return err
+		}
+	}
+	return injector.Save()
+}
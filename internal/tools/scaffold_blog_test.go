@@ -0,0 +1,138 @@
+package tools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldBlog(t *testing.T) {
+	setup := func(t *testing.T, withAuth bool) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		project, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+			ProjectName:  "app",
+			ModulePath:   "github.com/test/app",
+			DatabaseType: "sqlite",
+			WithAuth:     withAuth,
+			InCurrentDir: true,
+		})
+		if err != nil || !project.Success {
+			t.Fatalf("scaffoldProject failed: %v %s", err, project.Message)
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("composes the post and tag domains with the public blog", func(t *testing.T) {
+		registry, tmpDir := setup(t, true)
+
+		result, err := scaffoldBlog(registry, types.ScaffoldBlogInput{Title: "Engineering", Path: "/news", PostsPerPage: 5})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			"internal/models/post.go": {
+				"TagList string",
+				`gorm:"many2many:posts_tags"`,
+				"PublishState string",
+				"func (m *Post) BeforeSave(tx *gorm.DB) error",
+			},
+			"internal/models/tag.go":             {"Slug string", "func (m *Tag) BeforeSave(tx *gorm.DB) error"},
+			"internal/services/post/blog.go":     {`events.On("post.after_save", syncPostTags)`, "DELETE FROM posts_tags WHERE post_id = ?", "slug.Make(post.Title)"},
+			"internal/services/tag/blog.go":      {`events.On("tag.before_save", setTagSlug)`},
+			"internal/slug/slug.go":              {"func Make(text string) string"},
+			"internal/markdown/markdown.go":      {"extension.GFM", `highlighting.WithStyle("github")`, "func Render(source string) templ.Component"},
+			"internal/services/blog/blog.go":     {"models.PostStatePublished", `Table("posts_tags")`, "func (s *Service) FindBySlug("},
+			"internal/web/blog/blog.go":          {`blogPath = "/news"`, `blogTitle = "Engineering"`, "postsPerPage = 5", `r.Get("/feed.xml", c.RSS)`, `r.Get("/{slug}", c.Show)`},
+			"internal/web/blog/feed.go":          {`xml:"http://www.w3.org/2005/Atom feed"`, "BASE_URL"},
+			"internal/web/blog/views/blog.templ": {"@components.Pagination(", "@markdown.Render(props.Post.Body)"},
+			"cmd/web/main.go": {
+				`blogsvc "github.com/test/app/internal/services/blog"`,
+				"blogService := blogsvc.NewService(db)",
+				"blogController := blogweb.NewController(blogService)",
+				`router.Route("/news", blogController.RegisterRoutes)`,
+			},
+			"config/en/menu.toml": {"[[site]]", "[[footer]]", `url = "/news"`},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("%s should contain %q", path, want)
+				}
+			}
+		}
+
+		store := metadata.NewStore(tmpDir)
+		for _, domain := range []string{"post", "tag"} {
+			if exists, _ := store.Exists(domain); !exists {
+				t.Errorf("expected metadata for the %s domain", domain)
+			}
+		}
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		registry, tmpDir := setup(t, true)
+
+		result, err := scaffoldBlog(registry, types.ScaffoldBlogInput{DryRun: true})
+		if err != nil || !result.Success {
+			t.Fatalf("expected success: %v %s", err, result.Message)
+		}
+		if !strings.Contains(strings.Join(result.FilesCreated, " "), "internal/web/blog/feed.go") {
+			t.Errorf("expected feed.go in the files to create, got %v", result.FilesCreated)
+		}
+		for _, path := range []string{"internal/models/post.go", "internal/web/blog/blog.go"} {
+			if fileExists(filepath.Join(tmpDir, path)) {
+				t.Errorf("dry run should not create %s", path)
+			}
+		}
+	})
+
+	t.Run("rejects existing post domain", func(t *testing.T) {
+		registry, _ := setup(t, true)
+
+		domain, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "post",
+			Fields:     []types.FieldDef{{Name: "Title", Type: "string"}},
+		})
+		if err != nil || !domain.Success {
+			t.Fatalf("scaffoldDomain failed: %v %s", err, domain.Message)
+		}
+
+		result, _ := scaffoldBlog(registry, types.ScaffoldBlogInput{})
+		if result.Success || !strings.Contains(result.Message, "post domain already exists") {
+			t.Errorf("expected the existing post domain to be rejected, got: %s", result.Message)
+		}
+	})
+
+	t.Run("requires auth", func(t *testing.T) {
+		registry, _ := setup(t, false)
+
+		result, _ := scaffoldBlog(registry, types.ScaffoldBlogInput{})
+		if result.Success || !strings.Contains(result.Message, "with_auth") {
+			t.Errorf("expected an error asking for auth, got: %s", result.Message)
+		}
+	})
+
+	t.Run("rejects invalid paths and page sizes", func(t *testing.T) {
+		registry, _ := setup(t, true)
+
+		for _, input := range []types.ScaffoldBlogInput{
+			{Path: "/"},
+			{Path: "/posts"},
+			{Path: "blog"},
+			{PostsPerPage: 500},
+		} {
+			if result, _ := scaffoldBlog(registry, input); result.Success {
+				t.Errorf("expected %+v to be rejected", input)
+			}
+		}
+	})
+}
@@ -0,0 +1,195 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterScaffoldCI registers the scaffold_ci tool.
+func RegisterScaffoldCI(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_ci",
+		Description: `Generate a continuous integration workflow for the project.
+
+provider "github" (the default) generates .github/workflows/ci.yml, run on pushes to main and on
+pull requests, with the jobs:
+- test: templ generate (templ installed at the go.mod version), go vet and go test. Postgres
+  and MySQL projects get a database service container with DB_DRIVER and DB_DSN pointing at
+  it; SQLite tests build with the sqlite_fts5 tag. Redis gets a service container and
+  REDIS_URL when the project has the Redis cache or with_redis. Projects with the testcontainers
+  harness (internal/testutil) also run the repository tests on a database container
+- css: builds Tailwind CSS with the pinned CLI version (web projects)
+- image: builds the container image from the Dockerfile (see scaffold_deploy) without pushing
+  it, with the GitHub Actions build cache
+
+with_release: true adds the release tooling:
+- .goreleaser.yaml: builds the server and seed binaries after templ generate and the Tailwind
+  build, for Linux, macOS and Windows (SQLite's cgo limits them to linux/amd64), and archives
+  them with config/ and assets/. The version, commit and build date are set with -ldflags in
+  the config package
+- internal/config/version.go: the Version, Commit and BuildDate variables ("dev" locally), kept
+  when the project already has it (error_reporting, scaffold_health)
+- .github/workflows/release.yml: runs GoReleaser when a v* tag is pushed
+- Taskfile.yml: release and release:snapshot tasks
+The release tooling can be added to a project that already has ci.yml.
+
+Existing files are reported as conflicts instead of being overwritten.
+
+Examples:
+  scaffold_ci: {}
+  scaffold_ci: { database_type: "postgres", with_redis: true }
+  scaffold_ci: { with_release: true }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldCIInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldCI)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldCI(registry *Registry, input types.ScaffoldCIInput) (types.ScaffoldResult, error) {
+	provider := input.Provider
+	if provider == "" {
+		provider = "github"
+	}
+	if provider != "github" {
+		return types.NewErrorResult(fmt.Sprintf("invalid provider '%s': must be github", provider)), nil
+	}
+
+	data, err := deployData(registry.WorkingDir, input.DatabaseType, input.WithRedis)
+	if err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
+	// Create generator
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+
+	workflowPath := filepath.Join(".github", "workflows", "ci.yml")
+	if input.WithRelease {
+		// Release tooling can be added to a project that already has its CI workflow
+		if err := gen.GenerateFileIfNotExists("deploy/github_ci.yml.tmpl", workflowPath, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", workflowPath, err)), nil
+		}
+		files := []struct {
+			template string
+			output   string
+		}{
+			{"deploy/goreleaser.yaml.tmpl", ".goreleaser.yaml"},
+			{"deploy/github_release.yml.tmpl", filepath.Join(".github", "workflows", "release.yml")},
+		}
+		for _, f := range files {
+			if err := gen.GenerateFile(f.template, f.output, data); err != nil {
+				return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.output, err)), nil
+			}
+		}
+		// Projects with error reporting or scaffold_health already have the version variables
+		versionPath := filepath.Join("internal", "config", "version.go")
+		if err := gen.GenerateFileIfNotExists("deploy/version.go.tmpl", versionPath, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", versionPath, err)), nil
+		}
+	} else if err := gen.GenerateFile("deploy/github_ci.yml.tmpl", workflowPath, data); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", workflowPath, err)), nil
+	}
+
+	result := gen.Result()
+
+	// Check for conflicts - if any files would be overwritten, return conflict result
+	if conflictResult := CheckForConflicts(result); conflictResult != nil {
+		return *conflictResult, nil
+	}
+
+	if input.WithRelease {
+		updated, err := addReleaseTasks(registry.WorkingDir, input.DryRun)
+		if err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to update Taskfile.yml: %v", err)), nil
+		}
+		if updated {
+			result.FilesUpdated = append(result.FilesUpdated, "Taskfile.yml")
+		}
+	}
+
+	var nextSteps []string
+	if !utils.FileExists(filepath.Join(registry.WorkingDir, "Dockerfile")) {
+		nextSteps = append(nextSteps, "Generate the Dockerfile the image job builds: scaffold_deploy")
+	}
+	nextSteps = append(nextSteps,
+		"Commit .github/workflows/ci.yml and push to GitHub to run the workflow",
+		"Tests can read DB_DRIVER and DB_DSN to run against the service database",
+	)
+	if input.WithRelease {
+		nextSteps = append(nextSteps,
+			"Check the release build locally: task release:snapshot (needs goreleaser), then look in dist/",
+			"Publish a release by pushing a tag: git tag v0.1.0 && git push origin v0.1.0",
+			"Show the build with config.Version, config.Commit and config.BuildDate (e.g., in logs or a footer)",
+		)
+	}
+
+	what := "workflow"
+	if input.WithRelease {
+		what = "workflows and GoReleaser release tooling"
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would add the GitHub Actions %s (%s)", what, data.DatabaseType),
+			FilesCreated: result.FilesCreated,
+			FilesUpdated: result.FilesUpdated,
+			Backups:      result.Backups,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully added the GitHub Actions %s (%s)", what, data.DatabaseType),
+		FilesCreated: result.FilesCreated,
+		FilesUpdated: result.FilesUpdated,
+		Backups:      result.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// releaseTasks are the Taskfile.yml tasks added with the release tooling.
+const releaseTasks = `
+  release:
+    desc: Publish a release of the current tag with GoReleaser
+    cmds:
+      - goreleaser release --clean
+
+  release:snapshot:
+    desc: Build the release archives in dist/ without publishing them
+    cmds:
+      - goreleaser release --snapshot --clean
+`
+
+// addReleaseTasks appends the release tasks to Taskfile.yml, whose tasks map is the last
+// section of the generated file. It reports whether the file changed.
+func addReleaseTasks(workingDir string, dryRun bool) (bool, error) {
+	path := filepath.Join(workingDir, "Taskfile.yml")
+	content, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	taskfile := string(content)
+	if strings.Contains(taskfile, "\n  release:\n") {
+		return false, nil
+	}
+	if dryRun {
+		return true, nil
+	}
+	taskfile = strings.TrimRight(taskfile, "\n") + "\n" + releaseTasks
+	return true, os.WriteFile(path, []byte(taskfile), 0644)
+}
@@ -0,0 +1,233 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldCI(t *testing.T) {
+	workflowPath := filepath.Join(".github", "workflows", "ci.yml")
+
+	t.Run("sqlite web project", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		result, err := scaffoldCI(registry, types.ScaffoldCIInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		workflow := readFile(t, filepath.Join(tmpDir, workflowPath))
+		for _, want := range []string{
+			"GOFLAGS: -tags=sqlite_fts5",
+			"templ generate",
+			"run: go vet ./...",
+			"run: go test ./...",
+			"npx @tailwindcss/cli@" + generator.StackVersion("tailwind") + " ",
+			"needs: [test, css]",
+			"uses: docker/build-push-action@v6",
+		} {
+			if !strings.Contains(workflow, want) {
+				t.Errorf("expected ci.yml to contain %q", want)
+			}
+		}
+		if strings.Contains(workflow, "services:") {
+			t.Error("expected no service containers for SQLite")
+		}
+	})
+
+	t.Run("postgres with redis", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		result, err := scaffoldCI(registry, types.ScaffoldCIInput{DatabaseType: "postgres", WithRedis: true})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		workflow := readFile(t, filepath.Join(tmpDir, workflowPath))
+		for _, want := range []string{
+			"      postgres:\n        image: postgres:16-alpine",
+			"POSTGRES_DB: testapp_test",
+			"DB_DRIVER: postgres",
+			"DB_DSN: host=localhost user=postgres password=postgres dbname=testapp_test",
+			"      redis:\n        image: redis:7-alpine",
+			"REDIS_URL: redis://localhost:6379/0",
+		} {
+			if !strings.Contains(workflow, want) {
+				t.Errorf("expected ci.yml to contain %q", want)
+			}
+		}
+		if strings.Contains(workflow, "sqlite_fts5") {
+			t.Error("expected no SQLite build tag for Postgres")
+		}
+	})
+
+	t.Run("mysql api project", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		if err := metadata.NewStore(tmpDir).SaveProject(types.ScaffoldProjectInput{ProjectName: "Shop", ProjectType: "api", DatabaseType: "mysql"}, ScaffolderVersion); err != nil {
+			t.Fatalf("failed to save project metadata: %v", err)
+		}
+
+		result, err := scaffoldCI(registry, types.ScaffoldCIInput{})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		workflow := readFile(t, filepath.Join(tmpDir, workflowPath))
+		for _, want := range []string{"image: mysql:8.4", "DB_DSN: root:mysql@tcp(127.0.0.1:3306)/Shop_test", "needs: [test]", "tags: shop:${{ github.sha }}"} {
+			if !strings.Contains(workflow, want) {
+				t.Errorf("expected ci.yml to contain %q", want)
+			}
+		}
+		for _, unwanted := range []string{"templ", "tailwindcss", "css:"} {
+			if strings.Contains(workflow, unwanted) {
+				t.Errorf("expected ci.yml of an API project not to contain %q", unwanted)
+			}
+		}
+	})
+
+	t.Run("runs repository tests on a container with the testcontainers harness", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		result, err := scaffoldCI(registry, types.ScaffoldCIInput{DatabaseType: "postgres"})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, workflowPath)), "Integration tests") {
+			t.Error("expected no integration tests without internal/testutil")
+		}
+
+		registry, tmpDir = testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupTestUtil(t, tmpDir)
+
+		result, err = scaffoldCI(registry, types.ScaffoldCIInput{DatabaseType: "postgres"})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		want := "      - name: Integration tests\n" +
+			"        # Runs the repository tests on a database container started by testcontainers\n" +
+			"        env:\n" +
+			"          TEST_DATABASE: container\n" +
+			"        run: go test ./internal/repository/..."
+		if !strings.Contains(readFile(t, filepath.Join(tmpDir, workflowPath)), want) {
+			t.Errorf("expected ci.yml to contain %q", want)
+		}
+	})
+
+	t.Run("release tooling", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		ciPath := filepath.Join(tmpDir, workflowPath)
+		if err := os.MkdirAll(filepath.Dir(ciPath), 0755); err != nil {
+			t.Fatalf("failed to create workflows dir: %v", err)
+		}
+		if err := os.WriteFile(ciPath, []byte("name: Mine\n"), 0644); err != nil {
+			t.Fatalf("failed to write ci.yml: %v", err)
+		}
+		taskfile := "version: \"3\"\n\ntasks:\n  test:\n    cmds:\n      - go test ./...\n"
+		if err := os.WriteFile(filepath.Join(tmpDir, "Taskfile.yml"), []byte(taskfile), 0644); err != nil {
+			t.Fatalf("failed to write Taskfile.yml: %v", err)
+		}
+
+		result, err := scaffoldCI(registry, types.ScaffoldCIInput{WithRelease: true})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		checks := map[string][]string{
+			".goreleaser.yaml": {
+				"    - templ generate",
+				"    main: ./cmd/web\n    binary: server",
+				"    main: ./cmd/seed\n    binary: seed",
+				"CGO_ENABLED=1",
+				"-X github.com/example/testapp/internal/config.Version={{.Version}}",
+				"      - assets/**/*",
+			},
+			filepath.Join(".github", "workflows", "release.yml"): {"- \"v*\"", "uses: goreleaser/goreleaser-action@v6", "GITHUB_TOKEN: ${{ secrets.GITHUB_TOKEN }}"},
+			filepath.Join("internal", "config", "version.go"):    {"package config", `Version = "dev"`},
+			"Taskfile.yml": {"      - go test ./...\n\n  release:\n", "  release:snapshot:\n"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+		if content := readFile(t, ciPath); content != "name: Mine\n" {
+			t.Error("expected the existing ci.yml to be kept")
+		}
+
+		if updated, err := addReleaseTasks(tmpDir, false); err != nil || updated {
+			t.Errorf("expected the release tasks to be added once, got %v %v", updated, err)
+		}
+	})
+
+	t.Run("release tooling keeps an existing version.go", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		versionPath := filepath.Join(tmpDir, "internal", "config", "version.go")
+		if err := os.MkdirAll(filepath.Dir(versionPath), 0755); err != nil {
+			t.Fatalf("failed to create config dir: %v", err)
+		}
+		if err := os.WriteFile(versionPath, []byte("package config\n\nvar Version, Commit, BuildDate = \"1\", \"2\", \"3\"\n"), 0644); err != nil {
+			t.Fatalf("failed to write version.go: %v", err)
+		}
+
+		result, err := scaffoldCI(registry, types.ScaffoldCIInput{WithRelease: true})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		if content := readFile(t, versionPath); !strings.Contains(content, `"1", "2", "3"`) {
+			t.Error("expected the existing version.go to be kept")
+		}
+	})
+
+	t.Run("reports an existing workflow as a conflict", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		fullPath := filepath.Join(tmpDir, workflowPath)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatalf("failed to create workflows dir: %v", err)
+		}
+		if err := os.WriteFile(fullPath, []byte("name: Mine\n"), 0644); err != nil {
+			t.Fatalf("failed to write ci.yml: %v", err)
+		}
+
+		result, err := scaffoldCI(registry, types.ScaffoldCIInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || len(result.Conflicts) != 1 {
+			t.Errorf("expected a conflict for ci.yml, got %+v", result)
+		}
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		for _, input := range []types.ScaffoldCIInput{{Provider: "gitlab"}, {DatabaseType: "mongodb"}} {
+			result, err := scaffoldCI(registry, input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success {
+				t.Errorf("expected failure for %+v", input)
+			}
+		}
+	})
+}
@@ -0,0 +1,160 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// clientReservedNames are the declarations of pkg/client/client.go that a domain's model
+// name would collide with.
+var clientReservedNames = map[string]bool{
+	"Client":      true,
+	"Option":      true,
+	"Error":       true,
+	"ListOptions": true,
+	"Page":        true,
+	"Iterator":    true,
+}
+
+// RegisterScaffoldClient registers the scaffold_client tool.
+func RegisterScaffoldClient(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_client",
+		Description: `Generate a typed Go client for the project's JSON API in pkg/client.
+
+Other Go services, CLIs and integration tests can call the API without hand-written HTTP code.
+Requires an API-only project (project_type: api).
+
+Generated files:
+- pkg/client/client.go: client.New(baseURL, opts...), the request helper, error type and pagination
+- pkg/client/{domain}.go: one per domain, with its types and endpoints
+
+Features:
+- A resource client per domain on the Client (e.g., c.Products) with List, Get, Create, Update and Delete
+- Response, create and update types mirroring the domain DTOs and JSON field names; update fields are pointers
+- ListOptions for page, page_size, search, sort and dir, returning a Page with the list envelope
+- Iter walks every item of a list, fetching the pages as it goes
+- client.WithToken sends a bearer token, client.WithHTTPClient sets timeouts or transports
+- Error responses are returned as *client.Error with the status code; client.IsNotFound checks for 404
+- Versioned domains (api_vN route groups) are called at their /api/vN path
+
+Domains are read from scaffold metadata (.mcp/scaffold-metadata.json), so they must have been
+created with scaffold_domain. domains limits the client to a subset; by default all are included.
+
+Re-run the tool after scaffolding or changing domains; it regenerates (overwrites) pkg/client.`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldClientInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldClient)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldClient(registry *Registry, input types.ScaffoldClientInput) (types.ScaffoldResult, error) {
+	// Web controllers take form posts and render HTML, so only API projects have a client
+	if !registry.IsAPIProject() {
+		return types.NewErrorResult("scaffold_client generates a client for JSON endpoints, which only API-only projects (project_type: api) have"), nil
+	}
+
+	// Get module path from go.mod
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	metaStore := metadata.NewStore(registry.WorkingDir)
+	meta, err := metaStore.Load()
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to read scaffold metadata: %v", err)), nil
+	}
+
+	names := input.Domains
+	if len(names) == 0 {
+		for name := range meta.Domains {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	if len(names) == 0 {
+		return types.NewErrorResult("no domains with metadata found: scaffold a domain first, or check .mcp/scaffold-metadata.json exists"), nil
+	}
+
+	domains := make([]generator.DomainData, 0, len(names))
+	var missing []string
+	for _, name := range names {
+		domain, ok := meta.Domains[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		data := generator.NewDomainData(domain.Input, modulePath)
+		if clientReservedNames[data.ModelName] {
+			return types.NewErrorResult(fmt.Sprintf("domain '%s' can't be in the client: its model %s collides with client.%s", name, data.ModelName, data.ModelName)), nil
+		}
+		domains = append(domains, data)
+	}
+	if len(missing) > 0 {
+		return types.NewErrorResult(fmt.Sprintf("no scaffold metadata for domain(s): %s", strings.Join(missing, ", "))), nil
+	}
+
+	// Create generator
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+	gen.SetForceOverwrite(true)
+
+	clientDir := filepath.Join("pkg", "client")
+	if err := gen.EnsureDir(clientDir); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to create directory: %v", err)), nil
+	}
+
+	data := generator.ClientData{
+		ModulePath: modulePath,
+		Domains:    domains,
+	}
+	if err := gen.GenerateFile("client/client.go.tmpl", filepath.Join(clientDir, "client.go"), data); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to generate client: %v", err)), nil
+	}
+	for _, domain := range domains {
+		resourcePath := filepath.Join(clientDir, utils.ToSnakeCase(domain.DomainName)+".go")
+		if err := gen.GenerateFile("client/resource.go.tmpl", resourcePath, domain); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s client: %v", domain.DomainName, err)), nil
+		}
+	}
+
+	result := gen.Result()
+
+	nextSteps := []string{
+		"go build ./pkg/client",
+		fmt.Sprintf("c := client.New(\"http://localhost:8080\"); c.%s.List(ctx, client.ListOptions{})", utils.Pluralize(domains[0].ModelName)),
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would create API client for %d domain(s)", len(domains)),
+			FilesCreated: result.FilesCreated,
+			FilesUpdated: result.FilesUpdated,
+			Backups:      result.Backups,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully created API client for %d domain(s)", len(domains)),
+		FilesCreated: result.FilesCreated,
+		FilesUpdated: result.FilesUpdated,
+		Backups:      result.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
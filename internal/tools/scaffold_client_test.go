@@ -0,0 +1,125 @@
+package tools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldClient(t *testing.T) {
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		if err := metadata.NewStore(tmpDir).SaveProject(types.ScaffoldProjectInput{ProjectName: "testapp", ProjectType: "api"}, ScaffolderVersion); err != nil {
+			t.Fatalf("failed to save project metadata: %v", err)
+		}
+
+		for _, input := range []types.ScaffoldDomainInput{
+			{DomainName: "customer", Fields: []types.FieldDef{{Name: "Email", Type: "string"}}},
+			{
+				DomainName:    "order_item",
+				Fields:        []types.FieldDef{{Name: "Quantity", Type: "int"}, {Name: "Options", Type: "datatypes.JSON"}},
+				Relationships: []types.RelationshipDef{{Type: "belongs_to", Model: "Customer"}},
+			},
+		} {
+			if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+				t.Fatalf("failed to scaffold domain %s: %v %s", input.DomainName, err, result.Message)
+			}
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("generates a resource client for every domain", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := scaffoldClient(registry, types.ScaffoldClientInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		client := readFile(t, filepath.Join(tmpDir, "pkg", "client", "client.go"))
+		for _, want := range []string{
+			"package client",
+			"func New(baseURL string, opts ...Option) *Client {",
+			"func WithToken(token string) Option {",
+			`req.Header.Set("Authorization", "Bearer "+c.token)`,
+			"Customers *CustomerClient",
+			"OrderItems *OrderItemClient",
+			"c.OrderItems = &OrderItemClient{client: c}",
+			"func (it *Iterator[T]) Next(ctx context.Context) bool {",
+		} {
+			if !strings.Contains(client, want) {
+				t.Errorf("expected client.go to contain %q", want)
+			}
+		}
+
+		resource := readFile(t, filepath.Join(tmpDir, "pkg", "client", "order_item.go"))
+		for _, want := range []string{
+			`const orderItemPath = "/order-items"`,
+			"Quantity int `json:\"quantity\"`",
+			"Options json.RawMessage `json:\"options\"`",
+			"Customer *OrderItemCustomerSummary `json:\"customer,omitempty\"`",
+			"Quantity *int `json:\"quantity,omitempty\"`",
+			"type OrderItemPage = Page[OrderItem]",
+			"func (c *OrderItemClient) List(ctx context.Context, opts ListOptions) (*OrderItemPage, error) {",
+			"func (c *OrderItemClient) Iter(opts ListOptions) *OrderItemIterator {",
+			"func (c *OrderItemClient) Update(ctx context.Context, id uint, input UpdateOrderItemInput) (*OrderItem, error) {",
+			"func (c *OrderItemClient) Delete(ctx context.Context, id uint) error {",
+		} {
+			if !strings.Contains(resource, want) {
+				t.Errorf("expected order_item.go to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("limits the client to the given domains", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := scaffoldClient(registry, types.ScaffoldClientInput{Domains: []string{"customer"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, "pkg", "client", "client.go")), "OrderItems") {
+			t.Error("expected order items to be left out of the client")
+		}
+		if fileExists(filepath.Join(tmpDir, "pkg", "client", "order_item.go")) {
+			t.Error("expected no order_item.go client")
+		}
+	})
+
+	t.Run("validates input", func(t *testing.T) {
+		registry, _ := setup(t)
+
+		result, err := scaffoldClient(registry, types.ScaffoldClientInput{Domains: []string{"invoice"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure for a domain without metadata")
+		}
+	})
+
+	t.Run("requires an API project", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		result, err := scaffoldClient(registry, types.ScaffoldClientInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure in a web project")
+		}
+	})
+}
@@ -17,13 +17,28 @@ func RegisterScaffoldComponent(server *mcp.Server, registry *Registry) {
 		Name: "scaffold_component",
 		Description: `Create reusable templ components with Tailwind CSS styling.
 
-Component types: card, modal, dropdown, form_field, table, wizard, custom
+Component types: card, modal, form_field, wizard, tabs, accordion, dropdown, toast, badge, tooltip, custom
 
 Features:
 - Props with types and defaults
 - Optional HTMX attributes (with_htmx: true)
 - Alpine.js state integration (alpine_state)
 
+Interactive components (Alpine.js, keyboard accessible):
+- tabs: {Name}(props) with Tabs []{Name}Tab{ID, Label, Content}; arrow keys, Home and End move
+  between tabs. with_htmx adds Tab.URL, loaded the first time the tab is shown
+- accordion: {Name}(props) with Items []{Name}Item{ID, Title, Content, Open}; Multiple keeps
+  several sections open
+- dropdown: {Name}(props){ children } menu with {Name}Item(href, attrs), {Name}Label and
+  {Name}Separator; closes on Escape, click outside and item choice
+- toast: {Name}(props) with Variant, Title, Description, Duration and an optional action link
+- badge: {Name}(props){ text } with Variant, a status Dot and Removable (with_htmx: RemoveURL)
+- tooltip: {Name}(props){ trigger } with Text and Side, shown on hover and focus
+
+Toasts from handlers use the toast stack of the base layout: web.Response.Success, ErrorToast
+and Toast(variant, message) show them with HTMX responses, and Redirect carries them to the
+next page as a flash message.
+
 The "wizard" type generates a complete set of wizard components including:
 - WizardSteps: Horizontal/vertical clickable step indicators
 - WizardNav: Previous/Next/Submit navigation buttons
@@ -35,7 +50,7 @@ Use scaffold_modal for full modal dialogs, scaffold_form for forms.
 Use scaffold_wizard for complete multi-step wizard flows.
 Run 'templ generate' after creating components.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldComponentInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldComponent(registry, input)
+		result, err := locked(scaffoldComponent)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -44,6 +59,11 @@ Run 'templ generate' after creating components.`,
 }
 
 func scaffoldComponent(registry *Registry, input types.ScaffoldComponentInput) (types.ScaffoldResult, error) {
+	// API-only projects have no views
+	if registry.IsAPIProject() {
+		return noViewsInAPIProject("scaffold_component"), nil
+	}
+
 	// Validate input
 	if err := utils.ValidateComponentName(input.ComponentName); err != nil {
 		return types.NewErrorResult(err.Error()), nil
@@ -121,6 +141,7 @@ func scaffoldComponent(registry *Registry, input types.ScaffoldComponentInput) (
 		Message:        fmt.Sprintf("Successfully created %s component '%s'", input.ComponentType, input.ComponentName),
 		FilesCreated:   result.FilesCreated,
 		FilesUpdated:   result.FilesUpdated,
+		Backups:        result.Backups,
 		NextSteps:      nextSteps,
 		SuggestedTools: suggestedTools,
 	}, nil
@@ -135,6 +156,8 @@ func getComponentTemplatePath(componentType string) string {
 		return "components/form_field.templ.tmpl"
 	case "wizard":
 		return "components/wizard.templ.tmpl"
+	case "tabs", "accordion", "dropdown", "toast", "badge", "tooltip":
+		return "components/" + componentType + ".templ.tmpl"
 	default:
 		// Default to card for modal, custom, and unknown types
 		// Note: For full modal support, use scaffold_modal tool
@@ -145,6 +145,41 @@ func TestScaffoldComponent(t *testing.T) {
 		}
 	})
 
+	t.Run("generates interactive components", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/test/myapp")
+
+		tests := []struct {
+			name          string
+			componentType string
+			file          string
+			want          []string
+		}{
+			{"SettingsTabs", "tabs", "settings_tabs.templ", []string{"type SettingsTabsTab struct", `role="tablist"`, "func settingsTabsState(props SettingsTabsProps) string"}},
+			{"FaqAccordion", "accordion", "faq_accordion.templ", []string{"type FaqAccordionItem struct", "x-bind:aria-expanded", "multiple: ` + multiple + `"}},
+			{"RowMenu", "dropdown", "row_menu.templ", []string{"templ RowMenuItem(href string, attrs templ.Attributes)", `x-on:click.outside="open = false"`, "func rowMenuMenuClasses(align string) string"}},
+			{"UndoToast", "toast", "undo_toast.templ", []string{"ActionLabel string", "func undoToastInit(duration int) string"}},
+			{"StatusBadge", "badge", "status_badge.templ", []string{"Dot bool", `x-on:click="visible = false"`}},
+			{"HelpTip", "tooltip", "help_tip.templ", []string{`role="tooltip"`, `x-on:focusin="show = true"`}},
+		}
+		for _, tt := range tests {
+			result, err := scaffoldComponent(registry, types.ScaffoldComponentInput{ComponentName: tt.name, ComponentType: tt.componentType})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("%s: expected success, got failure: %s", tt.componentType, result.Message)
+			}
+
+			content := readFile(t, filepath.Join(tmpDir, "internal", "web", "components", tt.file))
+			for _, want := range tt.want {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s component to contain %q", tt.componentType, want)
+				}
+			}
+		}
+	})
+
 	t.Run("generates custom component defaults to card", func(t *testing.T) {
 		registry, tmpDir := testRegistry(t)
 		setupGoMod(t, tmpDir, "github.com/test/myapp")
@@ -255,6 +290,12 @@ func TestGetComponentTemplatePath(t *testing.T) {
 		{"modal", "components/card.templ.tmpl"}, // modal falls back to card; use scaffold_modal for full modal support
 		{"form_field", "components/form_field.templ.tmpl"},
 		{"wizard", "components/wizard.templ.tmpl"},
+		{"tabs", "components/tabs.templ.tmpl"},
+		{"accordion", "components/accordion.templ.tmpl"},
+		{"dropdown", "components/dropdown.templ.tmpl"},
+		{"toast", "components/toast.templ.tmpl"},
+		{"badge", "components/badge.templ.tmpl"},
+		{"tooltip", "components/tooltip.templ.tmpl"},
 		{"custom", "components/card.templ.tmpl"},
 		{"unknown", "components/card.templ.tmpl"},
 		{"", "components/card.templ.tmpl"},
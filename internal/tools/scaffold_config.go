@@ -18,13 +18,13 @@ func RegisterScaffoldConfig(server *mcp.Server, registry *Registry) {
 
 Config types:
 - page: Page-specific content (title, description, headings)
-- menu: Navigation menu structure
+- menu: Sidebar navigation (main, admin and account sections), read by the menu package at startup
 - app: Application settings (server, database)
 - messages: Localized message strings
 
 Supports multiple locales (default: en). Files go to config/{locale}/.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldConfigInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldConfig(registry, input)
+		result, err := locked(scaffoldConfig)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -124,11 +124,11 @@ url = "/dashboard"
 icon = "home"
 order = 1
 
-[[main]]
+[[account]]
 label = "Settings"
 url = "/settings"
-icon = "settings"
-order = 100
+icon = "cog"
+order = 1
 `
 
 	case "app":
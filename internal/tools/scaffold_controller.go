@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/dbb1dev/go-mcp/internal/config"
 	"github.com/dbb1dev/go-mcp/internal/generator"
 	"github.com/dbb1dev/go-mcp/internal/types"
 	"github.com/dbb1dev/go-mcp/internal/utils"
@@ -58,7 +59,7 @@ Examples:
 
 Prefer scaffold_domain for new features - it generates all layers consistently.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldControllerInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldController(registry, input)
+		result, err := locked(scaffoldController)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -67,6 +68,14 @@ Prefer scaffold_domain for new features - it generates all layers consistently.`
 }
 
 func scaffoldController(registry *Registry, input types.ScaffoldControllerInput) (types.ScaffoldResult, error) {
+	// Fill in the project defaults of .gomcp.toml
+	defaults, err := registry.Defaults()
+	if err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	input.Layout = config.Or(input.Layout, defaults.Layout)
+	input.RouteGroup = config.Or(input.RouteGroup, defaults.RouteGroup)
+
 	// Validate input - support nested paths like "admin/users"
 	if err := utils.ValidateDomainPath(input.DomainName); err != nil {
 		return types.NewErrorResult(err.Error()), nil
@@ -134,6 +143,7 @@ func scaffoldController(registry *Registry, input types.ScaffoldControllerInput)
 		URLPath:      urlPath,
 		Layout:       layout,
 		RouteGroup:   routeGroup,
+		WithLogging:  registry.HasStructuredLogging(),
 	}
 
 	// Create directory - use full path for nested domains
@@ -0,0 +1,305 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterScaffoldDeploy registers the scaffold_deploy tool.
+func RegisterScaffoldDeploy(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_deploy",
+		Description: `Generate the files to build and run the project in containers.
+
+target "docker" (the default) generates:
+- Dockerfile: a multi-stage build. Tailwind CSS is compiled in a Node stage, templ generate and
+  go build run in a Go stage (with cgo and FTS5 for SQLite) and the server and seed binaries
+  are copied into a small Alpine image that runs as a non-root user, with a /health check
+- docker-compose.yml: the app with the project's database (Postgres or MySQL with a health
+  check, or SQLite on a volume) and Redis when the project has the Redis cache or with_redis
+- .dockerignore: keeps generated files, databases, uploads and secrets out of the build
+
+target "kubernetes" generates a kustomize base in deploy/kubernetes/base, for the image built
+from the Dockerfile:
+- deployment.yaml: the app with a liveness probe on /health and a readiness probe on /readyz
+  (see scaffold_health, else /health), running as the image's non-root user. SQLite and uploads get a volume (pvc.yaml) and a single replica
+- service.yaml and ingress.yaml: the app behind an Ingress for host, with TLS
+- configmap.yaml: the settings (PORT, DB_DRIVER, ...); secret.yaml: placeholder DB_DSN,
+  SESSION_SECRET and REDIS_URL values to replace. Postgres, MySQL and Redis are not deployed
+and an overlay per environment in deploy/kubernetes/overlays/{environment} that sets the
+namespace ({project}-{environment}), the image and the host ({environment}.{host}, or host for
+production). Production runs two replicas when the app keeps no data on disk.
+
+target "systemd" generates deploy/systemd, to run the project on a server without containers:
+- {project}.service: runs the server as a system user from /opt/{project}/current, with the
+  environment file, restarts on failure, file system hardening and logs appended to
+  /var/log/{project}/{project}.log
+- {project}.env.example: the environment file (DB_DSN, SESSION_SECRET, ...), with SQLite and
+  uploads in /var/lib/{project}
+- logrotate: daily rotation of the logs, kept for two weeks
+- install.sh: installs or updates the app from a release archive or a build: creates the
+  user and directories, installs a new release next to the previous ones (for a rollback),
+  the environment file (first install only), the unit and the logrotate config, and restarts
+  the service
+
+The container is configured with environment variables (PORT, DB_DRIVER, DB_DSN,
+SESSION_SECRET, REDIS_URL, ...); config/en/app.toml holds the development settings and is not
+used, but a config file can be mounted at /etc/{project}/app.toml. Existing files are reported
+as conflicts instead of being overwritten.
+
+Examples:
+  scaffold_deploy: {}
+  scaffold_deploy: { database_type: "postgres", with_redis: true }
+  scaffold_deploy: { target: "kubernetes", host: "shop.example.com", environments: ["staging", "production"] }
+  scaffold_deploy: { target: "systemd" }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldDeployInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldDeploy)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldDeploy(registry *Registry, input types.ScaffoldDeployInput) (types.ScaffoldResult, error) {
+	target := input.Target
+	if target == "" {
+		target = "docker"
+	}
+	if target != "docker" && target != "kubernetes" && target != "systemd" {
+		return types.NewErrorResult(fmt.Sprintf("invalid target '%s': must be one of docker, kubernetes, systemd", target)), nil
+	}
+
+	data, err := deployData(registry.WorkingDir, input.DatabaseType, input.WithRedis)
+	if err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
+	// Create generator
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+
+	type deployFile struct {
+		template string
+		output   string
+		data     generator.DeployData
+	}
+	var files []deployFile
+	var nextSteps []string
+	label := "Docker"
+
+	switch target {
+	case "kubernetes":
+		label = "Kubernetes"
+		data.Host = input.Host
+		if data.Host == "" {
+			data.Host = data.AppName + ".example.com"
+		}
+		if err := utils.ValidateHostName(data.Host); err != nil {
+			return types.NewErrorResult(err.Error()), nil
+		}
+		environments := input.Environments
+		if len(environments) == 0 {
+			environments = []string{"staging", "production"}
+		}
+		for _, env := range environments {
+			if err := utils.ValidateDeployEnvironment(env); err != nil {
+				return types.NewErrorResult(err.Error()), nil
+			}
+		}
+
+		base := filepath.Join("deploy", "kubernetes", "base")
+		for _, name := range []string{"kustomization", "configmap", "secret", "deployment", "service", "ingress"} {
+			files = append(files, deployFile{"deploy/k8s_" + name + ".yaml.tmpl", filepath.Join(base, name+".yaml"), data})
+		}
+		if data.WithVolume {
+			files = append(files, deployFile{"deploy/k8s_pvc.yaml.tmpl", filepath.Join(base, "pvc.yaml"), data})
+		}
+		for _, env := range environments {
+			overlay := data
+			overlay.Environment = env
+			overlay.EnvironmentHost = env + "." + data.Host
+			if env == "production" {
+				overlay.EnvironmentHost = data.Host
+			}
+			files = append(files, deployFile{"deploy/k8s_overlay.yaml.tmpl", filepath.Join("deploy", "kubernetes", "overlays", env, "kustomization.yaml"), overlay})
+		}
+
+		if !utils.FileExists(filepath.Join(registry.WorkingDir, "Dockerfile")) {
+			nextSteps = append(nextSteps, "Generate the Dockerfile the manifests deploy: scaffold_deploy with target docker")
+		}
+		nextSteps = append(nextSteps,
+			fmt.Sprintf("Build and push the image, then set its name and tag in deploy/kubernetes/overlays/*/kustomization.yaml: docker build -t registry.example.com/%s:latest .", data.AppName),
+			"Replace the placeholder values in deploy/kubernetes/base/secret.yaml, or create the secret outside the repository",
+		)
+		if data.DatabaseType != "sqlite" {
+			nextSteps = append(nextSteps, fmt.Sprintf("Point DB_DSN at a %s database the cluster can reach; the manifests do not deploy one", data.DatabaseType))
+		}
+		if data.WithRedis {
+			nextSteps = append(nextSteps, "Point REDIS_URL at a Redis server the cluster can reach")
+		}
+		env := environments[0]
+		nextSteps = append(nextSteps,
+			fmt.Sprintf("Preview and apply an environment: kubectl kustomize deploy/kubernetes/overlays/%s, then kubectl create namespace %s-%s && kubectl apply -k deploy/kubernetes/overlays/%s", env, data.AppName, env, env),
+			fmt.Sprintf("Seed the database: kubectl -n %s-%s exec deploy/%s -- ./seed", data.AppName, env, data.AppName),
+		)
+	case "systemd":
+		label = "systemd"
+		dir := filepath.Join("deploy", "systemd")
+		files = []deployFile{
+			{"deploy/systemd_service.tmpl", filepath.Join(dir, data.AppName+".service"), data},
+			{"deploy/systemd_env.tmpl", filepath.Join(dir, data.AppName+".env.example"), data},
+			{"deploy/systemd_logrotate.tmpl", filepath.Join(dir, "logrotate"), data},
+			{"deploy/systemd_install.sh.tmpl", filepath.Join(dir, "install.sh"), data},
+		}
+
+		build := "go build -o bin/server ./cmd/web"
+		if data.DatabaseType == "sqlite" {
+			build = "go build -tags sqlite_fts5 -o bin/server ./cmd/web"
+		}
+		if data.ProjectType != "api" {
+			build = "templ generate && task tailwind:build && " + build
+		}
+		nextSteps = append(nextSteps,
+			fmt.Sprintf("Build the release for the server: a release archive (scaffold_ci with_release), or %s", build),
+			"Copy the build and deploy/systemd to the server and install or update: sudo deploy/systemd/install.sh <build directory>",
+			fmt.Sprintf("Set the secrets in /etc/%s/%s.env, then: sudo systemctl restart %s", data.AppName, data.AppName, data.AppName),
+		)
+		if data.DatabaseType != "sqlite" {
+			nextSteps = append(nextSteps, fmt.Sprintf("Create the %s database and user that DB_DSN names", data.DatabaseType))
+		}
+		nextSteps = append(nextSteps,
+			fmt.Sprintf("Seed the database: sudo systemd-run --wait --pipe -p User=%s -p EnvironmentFile=/etc/%s/%s.env -p WorkingDirectory=/opt/%s/current /opt/%s/current/seed", data.AppName, data.AppName, data.AppName, data.AppName, data.AppName),
+			"Put a reverse proxy with HTTPS (e.g., Caddy or nginx) in front of port 8080",
+			fmt.Sprintf("Follow the logs: tail -f /var/log/%s/%s.log", data.AppName, data.AppName),
+		)
+	default:
+		files = []deployFile{
+			{"deploy/dockerfile.tmpl", "Dockerfile", data},
+			{"deploy/compose.yml.tmpl", "docker-compose.yml", data},
+			{"deploy/dockerignore.tmpl", ".dockerignore", data},
+		}
+
+		nextSteps = []string{
+			"Start the app and its services: docker compose up --build",
+			"Seed the database: docker compose run --rm app ./seed",
+		}
+		if data.ProjectType != "api" {
+			nextSteps = append(nextSteps, "Set SESSION_SECRET (e.g., in .env next to docker-compose.yml) before exposing the app")
+		}
+		if utils.FileExists(filepath.Join(registry.WorkingDir, "docker-compose.search.yml")) {
+			nextSteps = append(nextSteps, "Add the search engine: docker compose -f docker-compose.yml -f docker-compose.search.yml up --build, with SEARCH_URL set for the app")
+		}
+	}
+
+	for _, f := range files {
+		if err := gen.GenerateFile(f.template, f.output, f.data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.output, err)), nil
+		}
+	}
+
+	result := gen.Result()
+
+	// Check for conflicts - if any files would be overwritten, return conflict result
+	if conflictResult := CheckForConflicts(result); conflictResult != nil {
+		return *conflictResult, nil
+	}
+
+	if target == "systemd" && !input.DryRun {
+		if err := os.Chmod(filepath.Join(registry.WorkingDir, "deploy", "systemd", "install.sh"), 0755); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to make install.sh executable: %v", err)), nil
+		}
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would add %s deployment files (%s)", label, data.DatabaseType),
+			FilesCreated: result.FilesCreated,
+			FilesUpdated: result.FilesUpdated,
+			Backups:      result.Backups,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully added %s deployment files (%s)", label, data.DatabaseType),
+		FilesCreated: result.FilesCreated,
+		FilesUpdated: result.FilesUpdated,
+		Backups:      result.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// deployData describes the project for the deployment templates: its name, type and database
+// (from the project metadata, else go.mod) and the services it uses.
+func deployData(workingDir, databaseType string, withRedis bool) (generator.DeployData, error) {
+	// Get module path from go.mod
+	modulePath, err := utils.GetModulePath(workingDir)
+	if err != nil {
+		return generator.DeployData{}, fmt.Errorf("failed to get module path: %v", err)
+	}
+
+	data := generator.DeployData{
+		ModulePath:         modulePath,
+		ProjectName:        filepath.Base(modulePath),
+		ProjectType:        "web",
+		CSSPipeline:        "tailwind",
+		JSBundler:          "none",
+		DatabaseType:       databaseType,
+		WithRedis:          withRedis || utils.FileExists(filepath.Join(workingDir, "internal", "cache", "redis.go")),
+		WithUploads:        utils.FileExists(filepath.Join(workingDir, "internal", "storage", "storage.go")),
+		WithHealth:         utils.FileExists(filepath.Join(workingDir, "internal", "health", "health.go")),
+		WithTestContainers: utils.FileExists(filepath.Join(workingDir, "internal", "testutil", "db.go")),
+	}
+	if project, err := metadata.NewStore(workingDir).GetProject(); err == nil && project != nil {
+		if project.Input.ProjectName != "" {
+			data.ProjectName = project.Input.ProjectName
+		}
+		data.ProjectType = project.ProjectType()
+		if project.Input.CSSPipeline != "" {
+			data.CSSPipeline = project.Input.CSSPipeline
+		}
+		if project.Input.JSBundler != "" {
+			data.JSBundler = project.Input.JSBundler
+		}
+		if data.DatabaseType == "" {
+			data.DatabaseType = project.Input.DatabaseType
+		}
+	}
+	if data.DatabaseType == "" {
+		data.DatabaseType = goModDatabaseType(workingDir)
+	}
+	if err := utils.ValidateDatabaseType(data.DatabaseType); err != nil {
+		return generator.DeployData{}, err
+	}
+	data.WithVolume = data.DatabaseType == "sqlite" || data.WithUploads
+	data.WithNPM = generator.UsesNPM(data.CSSPipeline, data.JSBundler)
+	data.AppName = utils.ToKebabCase(data.ProjectName)
+	return data, nil
+}
+
+// goModDatabaseType returns the database of a project from its GORM driver requirement,
+// or "sqlite" when go.mod has none.
+func goModDatabaseType(workingDir string) string {
+	content, err := os.ReadFile(filepath.Join(workingDir, "go.mod"))
+	if err != nil {
+		return "sqlite"
+	}
+	for _, dbType := range []string{"postgres", "mysql", "sqlite"} {
+		if strings.Contains(string(content), "gorm.io/driver/"+dbType+" ") {
+			return dbType
+		}
+	}
+	return "sqlite"
+}
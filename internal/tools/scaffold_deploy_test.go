@@ -0,0 +1,273 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldDeploy(t *testing.T) {
+	checkFiles := func(t *testing.T, tmpDir string, checks map[string][]string, absent map[string][]string) {
+		t.Helper()
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+		for path, unwanted := range absent {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, s := range unwanted {
+				if strings.Contains(content, s) {
+					t.Errorf("expected %s not to contain %q", path, s)
+				}
+			}
+		}
+	}
+
+	t.Run("sqlite web project", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		result, err := scaffoldDeploy(registry, types.ScaffoldDeployInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checkFiles(t, tmpDir, map[string][]string{
+			"Dockerfile": {
+				"FROM node:22-alpine AS css",
+				"npx @tailwindcss/cli@",
+				"ENV CGO_ENABLED=1 GOFLAGS=-tags=sqlite_fts5",
+				"templ generate",
+				"CONFIG_PATH=/etc/testapp/app.toml",
+				"DB_DSN=/data/data.db",
+				"USER app",
+			},
+			"docker-compose.yml": {"DB_DRIVER: sqlite", "app-data:/data", "SESSION_SECRET:"},
+			".dockerignore":      {"*_templ.go", "*.db"},
+		}, map[string][]string{
+			"docker-compose.yml": {"db:", "redis"},
+		})
+	})
+
+	t.Run("postgres from go.mod with redis", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		goMod := "module github.com/example/testapp\n\ngo 1.22\n\nrequire (\n\tgorm.io/driver/postgres v1.5.9\n\tgorm.io/gorm v1.25.12\n)\n"
+		if err := os.WriteFile(filepath.Join(tmpDir, "go.mod"), []byte(goMod), 0644); err != nil {
+			t.Fatalf("failed to write go.mod: %v", err)
+		}
+
+		result, err := scaffoldDeploy(registry, types.ScaffoldDeployInput{WithRedis: true})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		checkFiles(t, tmpDir, map[string][]string{
+			"Dockerfile": {"ENV CGO_ENABLED=0"},
+			"docker-compose.yml": {
+				"DB_DSN: host=db user=postgres",
+				"image: postgres:16-alpine",
+				"POSTGRES_DB: testapp",
+				"REDIS_URL: redis://redis:6379/0",
+				"image: redis:7-alpine",
+				"      db:\n        condition: service_healthy",
+			},
+		}, map[string][]string{
+			"Dockerfile":         {"build-base", "/data"},
+			"docker-compose.yml": {"app-data"},
+		})
+	})
+
+	t.Run("api project", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		if err := metadata.NewStore(tmpDir).SaveProject(types.ScaffoldProjectInput{ProjectName: "shop", ProjectType: "api", DatabaseType: "mysql"}, ScaffolderVersion); err != nil {
+			t.Fatalf("failed to save project metadata: %v", err)
+		}
+
+		result, err := scaffoldDeploy(registry, types.ScaffoldDeployInput{})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		checkFiles(t, tmpDir, map[string][]string{
+			"Dockerfile":         {"CONFIG_PATH=/etc/shop/app.toml"},
+			"docker-compose.yml": {"image: mysql:8.4", "MYSQL_DATABASE: shop"},
+		}, map[string][]string{
+			"Dockerfile":         {"AS css", "templ", "COPY assets"},
+			"docker-compose.yml": {"SESSION_SECRET"},
+		})
+	})
+
+	t.Run("npm project builds the CSS and JS bundle", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		if err := metadata.NewStore(tmpDir).SaveProject(types.ScaffoldProjectInput{ProjectName: "shop", CSSPipeline: "postcss", JSBundler: "vite"}, ScaffolderVersion); err != nil {
+			t.Fatalf("failed to save project metadata: %v", err)
+		}
+
+		result, err := scaffoldDeploy(registry, types.ScaffoldDeployInput{})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		checkFiles(t, tmpDir, map[string][]string{
+			"Dockerfile":    {"RUN npm install\nRUN npm run build:css\nRUN npm run build:js\n", "COPY --from=css /src/assets/dist ./assets/dist"},
+			".dockerignore": {"assets/dist/"},
+		}, map[string][]string{
+			"Dockerfile": {"npx @tailwindcss/cli@"},
+		})
+	})
+
+	t.Run("kubernetes sqlite web project", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/test_app")
+
+		result, err := scaffoldDeploy(registry, types.ScaffoldDeployInput{Target: "kubernetes"})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		base := filepath.Join("deploy", "kubernetes", "base")
+		checkFiles(t, tmpDir, map[string][]string{
+			filepath.Join(base, "kustomization.yaml"): {"  - pvc.yaml\n", "  - ingress.yaml\n"},
+			filepath.Join(base, "deployment.yaml"): {
+				"name: test-app\n",
+				"readinessProbe:\n            httpGet:\n              path: /health",
+				"type: Recreate",
+				"path: /health",
+				"readinessProbe:",
+				"claimName: test-app-data",
+			},
+			filepath.Join(base, "configmap.yaml"):                                                 {"CONFIG_PATH: /etc/test_app/app.toml", "DB_DSN: /data/data.db", `SESSION_SECURE: "true"`},
+			filepath.Join(base, "secret.yaml"):                                                    {"SESSION_SECRET:"},
+			filepath.Join(base, "ingress.yaml"):                                                   {"host: test-app.example.com"},
+			filepath.Join("deploy", "kubernetes", "overlays", "staging", "kustomization.yaml"):    {"namespace: test-app-staging", "value: staging.test-app.example.com"},
+			filepath.Join("deploy", "kubernetes", "overlays", "production", "kustomization.yaml"): {"namespace: test-app-production", "value: test-app.example.com"},
+		}, map[string][]string{
+			filepath.Join(base, "secret.yaml"): {"DB_DSN", "REDIS_URL"},
+			// The SQLite volume keeps production on one replica
+			filepath.Join("deploy", "kubernetes", "overlays", "production", "kustomization.yaml"): {"/spec/replicas"},
+		})
+		if fileExists(filepath.Join(tmpDir, "Dockerfile")) {
+			t.Error("expected no Docker files for the kubernetes target")
+		}
+	})
+
+	t.Run("kubernetes postgres with custom environments", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		healthPath := filepath.Join(tmpDir, "internal", "health", "health.go")
+		if err := os.MkdirAll(filepath.Dir(healthPath), 0755); err != nil {
+			t.Fatalf("failed to create health dir: %v", err)
+		}
+		if err := os.WriteFile(healthPath, []byte("package health\n"), 0644); err != nil {
+			t.Fatalf("failed to write health.go: %v", err)
+		}
+
+		result, err := scaffoldDeploy(registry, types.ScaffoldDeployInput{
+			Target:       "kubernetes",
+			DatabaseType: "postgres",
+			WithRedis:    true,
+			Host:         "shop.example.com",
+			Environments: []string{"qa", "production"},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		base := filepath.Join("deploy", "kubernetes", "base")
+		checkFiles(t, tmpDir, map[string][]string{
+			filepath.Join(base, "secret.yaml"):                                                    {"DB_DSN: host=postgres", "REDIS_URL: redis://"},
+			filepath.Join(base, "configmap.yaml"):                                                 {"DB_DRIVER: postgres"},
+			filepath.Join(base, "deployment.yaml"):                                                {"readinessProbe:\n            httpGet:\n              path: /readyz"},
+			filepath.Join("deploy", "kubernetes", "overlays", "qa", "kustomization.yaml"):         {"value: qa.shop.example.com"},
+			filepath.Join("deploy", "kubernetes", "overlays", "production", "kustomization.yaml"): {"value: shop.example.com", "path: /spec/replicas\n        value: 2"},
+		}, map[string][]string{
+			filepath.Join(base, "kustomization.yaml"): {"pvc.yaml"},
+			filepath.Join(base, "deployment.yaml"):    {"Recreate", "volumes:"},
+			filepath.Join(base, "configmap.yaml"):     {"DB_DSN"},
+		})
+		if fileExists(filepath.Join(tmpDir, base, "pvc.yaml")) || fileExists(filepath.Join(tmpDir, "deploy", "kubernetes", "overlays", "staging")) {
+			t.Error("expected no volume claim and only the requested overlays")
+		}
+	})
+
+	t.Run("systemd", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/test_app")
+
+		result, err := scaffoldDeploy(registry, types.ScaffoldDeployInput{Target: "systemd", DatabaseType: "postgres"})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		dir := filepath.Join("deploy", "systemd")
+		checkFiles(t, tmpDir, map[string][]string{
+			filepath.Join(dir, "test-app.service"): {
+				"After=network-online.target postgresql.service",
+				"User=test-app",
+				"EnvironmentFile=/etc/test-app/test-app.env",
+				"ExecStart=/opt/test-app/current/server",
+				"StandardOutput=append:/var/log/test-app/test-app.log",
+			},
+			filepath.Join(dir, "test-app.env.example"): {"CONFIG_PATH=/etc/test-app/app.toml", "DB_DRIVER=postgres", "DB_DSN=host=localhost", "SESSION_SECRET="},
+			filepath.Join(dir, "logrotate"):            {"/var/log/test-app/*.log {", "copytruncate"},
+			filepath.Join(dir, "install.sh"):           {"APP=test-app", `cp -R "$SRC/assets" "$RELEASE/assets"`, `systemctl restart "$APP"`},
+		}, nil)
+
+		info, err := os.Stat(filepath.Join(tmpDir, dir, "install.sh"))
+		if err != nil {
+			t.Fatalf("failed to stat install.sh: %v", err)
+		}
+		if info.Mode().Perm()&0100 == 0 {
+			t.Errorf("expected install.sh to be executable, got %v", info.Mode())
+		}
+	})
+
+	t.Run("reports existing files as conflicts", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		if err := os.WriteFile(filepath.Join(tmpDir, "Dockerfile"), []byte("FROM scratch\n"), 0644); err != nil {
+			t.Fatalf("failed to write Dockerfile: %v", err)
+		}
+
+		result, err := scaffoldDeploy(registry, types.ScaffoldDeployInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || len(result.Conflicts) != 1 {
+			t.Errorf("expected a conflict for the Dockerfile, got %+v", result)
+		}
+		if content := readFile(t, filepath.Join(tmpDir, "Dockerfile")); content != "FROM scratch\n" {
+			t.Error("expected the existing Dockerfile to be kept")
+		}
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		for name, input := range map[string]types.ScaffoldDeployInput{
+			"target":        {Target: "heroku"},
+			"database type": {DatabaseType: "mongodb"},
+		} {
+			result, err := scaffoldDeploy(registry, input)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			if result.Success {
+				t.Errorf("%s: expected failure", name)
+			}
+		}
+	})
+}
@@ -3,8 +3,14 @@ package tools
 import (
 	"context"
 	"fmt"
+	"go/format"
+	"os"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
 
+	"github.com/dbb1dev/go-mcp/internal/config"
 	"github.com/dbb1dev/go-mcp/internal/generator"
 	"github.com/dbb1dev/go-mcp/internal/metadata"
 	"github.com/dbb1dev/go-mcp/internal/modifier"
@@ -30,17 +36,59 @@ Generates ALL layers at once following clean architecture:
 - Controller with HTTP handlers (internal/web/{domain}/)
 - Optional CRUD views (with_crud_views: true, default)
 
-Supports relationships: belongs_to, has_one, has_many, many_to_many
+Empty layout, route_group and form_style take the project defaults of .gomcp.toml ([defaults] table), and a
+[templates] dir there overrides built-in templates, so omit them unless a domain differs from the project.
+
+API-only projects (scaffold_project with project_type: "api") are detected from .mcp/scaffold-metadata.json:
+- The controller is a JSON API (GET/POST /, GET/PUT/PATCH/DELETE /{id}) decoding the Create/Update DTOs
+- No views are generated; with_crud_views, with_moderation, image fields and authenticated/admin route groups are refused
+
+Supports relationships: belongs_to, has_one, has_many, many_to_many, polymorphic
 
 Relationship options:
 - display_field: Field to show in dropdowns/views (defaults to "Name"). Use when the related model doesn't have a "Name" field. Examples: "Title", "Email", "OrderNumber"
 
+Polymorphic relationships (one child attached to several parent types, e.g. comments on posts and videos):
+- model is the polymorphic name and models lists the owner models:
+  {type: "polymorphic", model: "Commentable", models: ["Post", "Video"]}
+- Adds CommentableID/CommentableType columns and a FindByCommentable repository method
+- Injects Comments []Comment (gorm polymorphic tag) into each existing owner model
+
+Many-to-many with extra columns on the join table (project members with a role):
+- {type: "many_to_many", model: "User", join_fields: [{name: "Role", type: "string"}]}
+- Generates a ProjectUser join model (table projects_users unless join_table is set), migrated with the domain
+- Repository/service helpers to list, attach (or update attributes) and detach linked records
+- With CRUD views: a section on the show view to manage the links
+- join_fields support string, int, int64, uint, float64, bool
+
+Nested has_many forms (order lines edited on the order form):
+- {type: "has_many", model: "OrderItem", nested_form: true}
+- Scaffold the child domain first, with a belongs_to back to this domain; its scalar fields become row inputs
+- The create/edit form gets an "Add" button for new rows and a remove button per row (Alpine)
+- Create saves the parent and rows in one transaction; update replaces the rows (missing rows are deleted)
+
+Self-referential trees (categories, org charts, threaded comments):
+- {type: "belongs_to", model: "self"}
+- Adds ParentID/Parent/Children to the model and FindRoots/FindChildren to the repository
+- Service rejects parents that would create a cycle
+- With CRUD views: parent select in the form and an expandable tree view at {path}/tree
+
 Supported field types:
 - Scalars: string, int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, float32, float64, bool
 - Time: time.Time, *time.Time (nullable)
 - Pointers (nullable): *string, *int, *int64, *uint, *float64, *bool
 - Slices: []byte, []string, []int, []uint
 - Custom types: any valid Go identifier (e.g., Status, models.Role)
+- Embedded value types: type is the struct name and embedded lists its fields
+  (string, int, int64, uint, float64, bool):
+  {name: "ShippingAddress", type: "Address", embedded: [{name: "Street", type: "string"}, {name: "City", type: "string"}]}
+  Stored as prefixed columns (shipping_address_street); an Address already declared in models is reused
+
+Field validation (checked by the service and, with CRUD views, by the browser as fields are edited):
+- required: strings must not be blank
+- min/max: bounds of number fields, lengths of string fields: {name: "Sku", type: "string", min: 3, max: 20}
+- pattern: regular expression a string must match as a whole: {pattern: "[A-Z]{3}-[0-9]+"}
+- Invalid records are rejected with a ValidationError whose messages the form shows under each field
 
 Layout options (layout parameter):
 - "dashboard" (default): Views wrapped in DashboardPage layout with sidebar
@@ -51,6 +99,24 @@ Route group options (route_group parameter):
 - "public" (default): No authentication required
 - "authenticated": Requires user login (RequireAuth middleware)
 - "admin": Requires admin role (RequireAuth + RequireAdmin middleware)
+Authenticated and admin domains get a sidebar link appended to config/en/menu.toml.
+icon names the icon of that link and of the list's empty state (e.g., "shopping-cart"): one of the
+names of the project's icon set. Defaults to folder for the link and inbox for the empty state.
+
+Owned records (owned_by_user, default true for route_group "authenticated" in projects with auth):
+- Adds a UserID column; Create stamps it with the session user
+- The list, tree and bulk actions only see the session user's records; show, edit, update,
+  delete and the other /{id} routes answer 404 for records of other users
+- The repository gets FindOwnedByID and WithOwner, the service GetOwnedByID
+- Set owned_by_user: false for records every signed-in user shares
+
+Versioned API route groups (API-only projects, route_group: "api_v1", "api_v2", ...):
+- Routes are mounted in a /api/vN sub-router of main.go, created on first use
+- The sub-router uses middleware.APIVersion (internal/web/middleware/versioning.go), which sets
+  an API-Version header and, for versions listed in DeprecatedVersions, the Deprecation,
+  Sunset and Link rel="successor-version" headers
+- with_versioned_dto: true generates internal/web/{domain}/dto_vN.go, a response DTO frozen for
+  that version (V1ProductResponse) that the controller returns instead of the service response
 
 Form style options (form_style parameter):
 - "modal" (default): Forms displayed in popup modal overlays
@@ -66,6 +132,116 @@ Form type options (form_type parameter on fields):
 - "password": Password input (masked)
 - "date": Date picker
 - "datetime": Date and time picker
+- "image": Image upload on a string field, which stores the image key (see below)
+
+Image uploads (form_type: "image"):
+- Uploads are re-encoded without EXIF metadata (after applying the EXIF orientation)
+- Resized variants (320, 640, 1280px wide) are stored next to the original
+- Views render responsive <img srcset> tags; the form posts multipart/form-data
+- Generates internal/storage (disk or S3, chosen with STORAGE_DRIVER), internal/images
+  and cmd/reprocess-images, which regenerates variants of every image column
+
+Inline editing (inline_edit parameter):
+- List of field names editable in place from the list view (e.g., ["Status", "Name"]),
+  or inline_edit: true on the field itself
+- Clicking a value (or Enter on it) loads an edit fragment (GET), saving sends a PATCH and
+  swaps the value back; Escape cancels
+- Invalid values and failed saves keep the editor open with the submitted value and an error,
+  so nothing typed is lost
+- scaffold_table columns with format "inline" render the same editable cells
+- Supported field types: string, int, int64, uint, float64, bool
+
+Optimistic locking (with_optimistic_locking: true):
+- Adds a Version column that Update checks and increments
+- Stale edits return 409 Conflict and the edit form shows a "changed by someone else" banner
+
+Observability (with_observability: true):
+- Wraps service methods in OpenTelemetry spans tagged with the domain and record ID
+- Records records.created/updated/deleted counters and a records.list.duration histogram
+- Uses the global tracer and meter providers, so register yours with otel.SetTracerProvider/otel.SetMeterProvider
+- On by default in projects scaffolded with with_otel, whose GORM query spans nest under the service spans
+
+Moderation (with_moderation: true):
+- Adds a ModerationStatus column (pending/approved/rejected); new records start as pending
+- Public list and show views only return approved records
+- Adds a moderation queue at /admin/{domain}/moderation with approve/reject endpoints,
+  mounted in the admin route group when the project has one
+- The service exposes Approve, Reject and OnModerated hooks for notifications
+
+List filters (filters parameter):
+- {field: "Status", widget: "select"}, {field: "Price", operator: "gte"}, {field: "Price", operator: "lte"}
+- Operators: eq, contains (strings), gt, gte, lt, lte (numbers and times; times only take ranges)
+- Widgets: text, select (uses the field's options), number, date, checkbox (bool)
+- Defaults: strings use a "contains" text box, fields with options and bools a select,
+  numbers an "eq" number input and times a "gte" date picker
+- Adds a filter bar above the list; filters are query parameters (status, price_gte) kept across pagination
+
+Bulk actions (with_bulk_actions: true, bulk_actions parameter):
+- Adds row checkboxes and a toolbar with Delete and Export CSV to the list view
+- POST {path}/bulk takes ids and an action; the repository deletes/updates in one statement
+- bulk_actions adds buttons that set a field to a fixed value on the selected records:
+  {name: "publish", field: "Status", value: "published", confirm: "Publish the selected products?"}
+- Actions set string, int, int64, uint, float64 or bool fields; values must match the field's options
+
+Full-text search (search: "fulltext", search_fields parameter):
+- Replaces the LIKE search with an SQLite FTS5 table kept in sync by triggers,
+  or a Postgres tsvector column with a GIN index (other databases keep LIKE on the search fields)
+- search_fields lists the string fields to match, most important first (default: all string fields)
+- Results are ordered by relevance unless a sort is requested; the list view shows highlighted snippets
+- Generates models.Migrate{Model}Search, called from RunMigrations;
+  SQLite projects must build with -tags sqlite_fts5
+
+Scheduled publishing (with_scheduling: true):
+- Adds PublishAt, UnpublishAt and a PublishState column (draft/scheduled/published/expired)
+- Forms get date-time pickers with "Publish now" and "Clear" shortcuts; lists and show pages a state badge
+- A background task (internal/scheduler, every SCHEDULER_INTERVAL, default 1m) publishes and expires
+  records as their dates pass; it is started from cmd/web/main.go
+- Public route groups only list and show published records; authenticated and admin groups see every state
+
+Caching (with_cache: true):
+- Generates internal/repository/{domain}/cache.go, a cache-aside decorator around the repository
+  that caches FindByID and FindAll (keyed by the SQL of the query options)
+- Writes invalidate the record and every cached list of the domain
+- Uses Redis when REDIS_URL is set, an in-memory cache otherwise (internal/cache); CACHE_TTL sets the TTL (default 5m)
+- main.go wires NewCachedRepository(db, NewRepository(db), cache.Default())
+
+Multi-tenancy (tenant_scoped: true, after scaffold_tenancy):
+- Adds a TenantID column; the tenancy callbacks limit every query to the request's tenant
+  and stamp new records with it
+- Queries without a tenant fail with tenancy.ErrNoTenant; the scheduler runs across all tenants
+- Cannot be combined with with_cache
+
+Live updates (with_realtime: true, after scaffold_realtime):
+- Generates internal/services/{domain}/realtime.go, a Service decorator publishing an event to
+  the realtime hub after each create, update and delete
+- The list view reloads when the domain's records change in another session
+- main.go wraps the service with NewRealtimeService(service, realtimeHub) and mounts its event
+  stream at /events/{path} in the domain's route group
+
+Repository tests (with_tests: true):
+- Generates internal/repository/{domain}/{domain}_test.go, which migrates the model into in-memory
+  SQLite and tests Create/FindByID, Update, Delete (soft delete keeps the row), FindAll pagination
+  and ordering, and preloading each relationship
+- Optimistic locking adds a stale version test and bulk actions a DeleteByIDs test
+- Postgres and MySQL projects get gorm.io/driver/sqlite as a test dependency with go mod tidy
+
+Lifecycle hooks (with_hooks: ["before_create", "after_update"]):
+- Hooks: before_save, before_create, after_create, before_update, after_update, after_save,
+  before_delete, after_delete, after_find
+- The model gets the GORM hook methods, which dispatch {domain}.{hook} events (e.g. product.before_create)
+  through internal/events
+- internal/services/{domain}/hooks.go registers a handler stub for each event; business logic that must
+  run on every write goes there, and an error rolls back the write
+
+Writing:
+- The generated files are written in one pass after rendering, or none of them
+- Each injection into existing files applies in full or restores them; outside strict mode
+  a failed injection is reported as a warning and the scaffold continues
+
+Strict mode (strict: true, or MCP_SCAFFOLD_STRICT=true for every call):
+- DI wiring, menu, moderation, search, scheduler, image and inverse relationship
+  injections that fail, and metadata that cannot be saved, fail the scaffold
+- The generated files are removed and the changed files restored
 
 Examples:
 
@@ -130,7 +306,7 @@ Automatically wires into main.go DI container. Run 'go mod tidy' and 'templ gene
 
 Use dry_run: true to preview all generated files first.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldDomainInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldDomain(registry, input)
+		result, err := locked(scaffoldDomain)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -139,6 +315,11 @@ Use dry_run: true to preview all generated files first.`,
 }
 
 func scaffoldDomain(registry *Registry, input types.ScaffoldDomainInput) (types.ScaffoldResult, error) {
+	// Fill in the project defaults of .gomcp.toml and what the project was scaffolded with
+	if err := applyProjectDefaults(registry, &input); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
 	// Validate input
 	if err := utils.ValidateDomainName(input.DomainName); err != nil {
 		return types.NewErrorResult(err.Error()), nil
@@ -156,11 +337,67 @@ func scaffoldDomain(registry *Registry, input types.ScaffoldDomainInput) (types.
 			), nil
 		}
 	}
+	if utils.ToPackageName(input.DomainName) == "routes" && registry.UsesRouteRegistry() {
+		return types.NewErrorResult("Cannot scaffold 'routes' domain: internal/web/routes holds the routes registry that mounts the domains"), nil
+	}
 
 	if len(input.Fields) == 0 {
 		return types.NewErrorResult("at least one field is required"), nil
 	}
 
+	if err := utils.ValidateRouteGroup(input.RouteGroup); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	if input.Icon != "" {
+		if err := validateProjectIcon(registry.WorkingDir, input.Icon); err != nil {
+			return types.NewErrorResult(err.Error()), nil
+		}
+	}
+	apiVersion := utils.APIVersionFromRouteGroup(input.RouteGroup)
+	if input.WithVersionedDTO && apiVersion == "" {
+		return types.NewErrorResult("with_versioned_dto requires an api_vN route_group (e.g., api_v1)"), nil
+	}
+
+	if input.TenantScoped {
+		if input.WithCache {
+			return types.NewErrorResult("tenant_scoped cannot be combined with with_cache: cache keys are shared by all tenants"), nil
+		}
+		if !utils.FileExists(filepath.Join(registry.WorkingDir, "internal", "tenancy", "tenancy.go")) {
+			return types.NewErrorResult("tenant_scoped requires the tenancy package: run scaffold_tenancy first"), nil
+		}
+	}
+
+	if input.WithRealtime && !utils.FileExists(filepath.Join(registry.WorkingDir, realtimeHubPath)) {
+		return types.NewErrorResult("with_realtime requires the realtime hub: run scaffold_realtime first"), nil
+	}
+	if input.WithRealtime && realtimeHubOutdated(registry.WorkingDir) {
+		return types.NewErrorResult(outdatedRealtimeHubMessage), nil
+	}
+
+	tracedProject := isTracedProject(registry.WorkingDir)
+
+	// API-only projects get a JSON controller and no views
+	apiProject := registry.IsAPIProject()
+	if apiVersion != "" && !apiProject {
+		return types.NewErrorResult(fmt.Sprintf("route_group %s requires an API-only project (project_type: api): versioned sub-routers mount JSON controllers", input.RouteGroup)), nil
+	}
+	if apiProject {
+		if input.WithCrudViews != nil && *input.WithCrudViews {
+			return types.NewErrorResult("with_crud_views is not available in an API-only project (project_type: api)"), nil
+		}
+		if input.WithModeration {
+			return types.NewErrorResult("with_moderation is not available in an API-only project (project_type: api): the moderation queue is a view"), nil
+		}
+		if rg := input.RouteGroup; rg == "authenticated" || rg == "admin" {
+			return types.NewErrorResult(fmt.Sprintf("route_group %s is not available in an API-only project (project_type: api): it has no auth", rg)), nil
+		}
+		for _, field := range input.Fields {
+			if field.FormType == "image" {
+				return types.NewErrorResult(fmt.Sprintf("field '%s': image uploads are not available in an API-only project (project_type: api)", field.Name)), nil
+			}
+		}
+	}
+
 	// Validate each field
 	for _, field := range input.Fields {
 		if err := utils.ValidateFieldName(field.Name); err != nil {
@@ -169,11 +406,179 @@ func scaffoldDomain(registry *Registry, input types.ScaffoldDomainInput) (types.
 		if err := utils.ValidateFieldType(field.Type); err != nil {
 			return types.NewErrorResult(fmt.Sprintf("field '%s': %v", field.Name, err)), nil
 		}
+		if len(field.Embedded) > 0 {
+			if err := utils.ValidateEmbeddedType(field.Type); err != nil {
+				return types.NewErrorResult(fmt.Sprintf("field '%s': %v", field.Name, err)), nil
+			}
+			for _, sub := range field.Embedded {
+				if err := utils.ValidateFieldName(sub.Name); err != nil {
+					return types.NewErrorResult(fmt.Sprintf("field '%s.%s': %v", field.Name, sub.Name, err)), nil
+				}
+				if err := utils.ValidateEmbeddedFieldType(sub.Type); err != nil {
+					return types.NewErrorResult(fmt.Sprintf("field '%s.%s': %v", field.Name, sub.Name, err)), nil
+				}
+			}
+		}
 		if field.FormType != "" {
 			if err := utils.ValidateFormType(field.FormType); err != nil {
 				return types.NewErrorResult(fmt.Sprintf("field '%s': %v", field.Name, err)), nil
 			}
 		}
+		if field.FormType == "image" && field.Type != "string" {
+			return types.NewErrorResult(fmt.Sprintf("field '%s': image fields must be of type string", field.Name)), nil
+		}
+		if err := utils.ValidateFieldRules(field.Type, field.FormType, field.Min, field.Max, field.Pattern); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("field '%s': %v", field.Name, err)), nil
+		}
+	}
+
+	// Validate inline editable fields
+	inlineEdit := input.InlineEditFields()
+	if len(inlineEdit) > 0 && !input.GetWithCrudViews() {
+		return types.NewErrorResult("inline_edit requires with_crud_views"), nil
+	}
+	for _, name := range inlineEdit {
+		var fieldType, formType string
+		for _, field := range input.Fields {
+			if field.Name == name {
+				fieldType, formType = field.Type, field.FormType
+				break
+			}
+		}
+		if fieldType == "" {
+			return types.NewErrorResult(fmt.Sprintf("inline_edit: unknown field '%s'", name)), nil
+		}
+		if formType == "image" {
+			return types.NewErrorResult(fmt.Sprintf("inline_edit field '%s': image fields cannot be edited inline", name)), nil
+		}
+		if err := utils.ValidateInlineEditType(fieldType); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("inline_edit field '%s': %v", name, err)), nil
+		}
+	}
+
+	// Validate list filters
+	if len(input.Filters) > 0 && !input.GetWithCrudViews() {
+		return types.NewErrorResult("filters require with_crud_views"), nil
+	}
+	filterParams := make(map[string]bool)
+	for _, filter := range input.Filters {
+		var field *types.FieldDef
+		for i := range input.Fields {
+			if input.Fields[i].Name == filter.Field {
+				field = &input.Fields[i]
+				break
+			}
+		}
+		if field == nil || len(field.Embedded) > 0 {
+			return types.NewErrorResult(fmt.Sprintf("filters: unknown field '%s'", filter.Field)), nil
+		}
+		data := generator.NewFilterData(filter, generator.NewFieldData(*field))
+		if err := utils.ValidateFilter(field.Type, data.Operator, data.Widget); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("filter on '%s': %v", filter.Field, err)), nil
+		}
+		if data.Widget == "select" && field.Type != "bool" && len(data.Options) == 0 {
+			return types.NewErrorResult(fmt.Sprintf("filter on '%s': a select widget needs options", filter.Field)), nil
+		}
+		if filterParams[data.Param] {
+			return types.NewErrorResult(fmt.Sprintf("filter on '%s': duplicate %s filter", filter.Field, data.Operator)), nil
+		}
+		filterParams[data.Param] = true
+	}
+
+	// Validate custom bulk actions
+	bulkActionNames := make(map[string]bool)
+	for _, action := range input.BulkActions {
+		if err := utils.ValidateBulkActionName(action.Name); err != nil {
+			return types.NewErrorResult(err.Error()), nil
+		}
+		if bulkActionNames[action.Name] {
+			return types.NewErrorResult(fmt.Sprintf("duplicate bulk action '%s'", action.Name)), nil
+		}
+		bulkActionNames[action.Name] = true
+
+		var field *types.FieldDef
+		for i := range input.Fields {
+			if input.Fields[i].Name == action.Field {
+				field = &input.Fields[i]
+				break
+			}
+		}
+		if field == nil || len(field.Embedded) > 0 {
+			return types.NewErrorResult(fmt.Sprintf("bulk action '%s': unknown field '%s'", action.Name, action.Field)), nil
+		}
+		if err := utils.ValidateBulkActionValue(field.Type, action.Value); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("bulk action '%s': %v", action.Name, err)), nil
+		}
+		if len(field.Options) > 0 && !slices.Contains(field.Options, action.Value) {
+			return types.NewErrorResult(fmt.Sprintf("bulk action '%s': value '%s' is not one of the options of '%s'", action.Name, action.Value, action.Field)), nil
+		}
+	}
+
+	// Validate search
+	if err := utils.ValidateSearchMode(input.Search); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	if len(input.SearchFields) > 0 && input.GetSearch() != "fulltext" {
+		return types.NewErrorResult("search_fields require search: \"fulltext\""), nil
+	}
+	for _, name := range input.SearchFields {
+		var field *types.FieldDef
+		for i := range input.Fields {
+			if input.Fields[i].Name == name {
+				field = &input.Fields[i]
+				break
+			}
+		}
+		if field == nil || len(field.Embedded) > 0 {
+			return types.NewErrorResult(fmt.Sprintf("search_fields: unknown field '%s'", name)), nil
+		}
+		if field.Type != "string" {
+			return types.NewErrorResult(fmt.Sprintf("search_fields: field '%s' is %s, only string fields can be searched", name, field.Type)), nil
+		}
+	}
+	if input.GetSearch() == "fulltext" && len(generator.NewSearchFieldDataList(input.SearchFields, generator.NewFieldDataList(input.Fields))) == 0 {
+		return types.NewErrorResult("full-text search needs at least one string field to search"), nil
+	}
+
+	// Validate scheduling
+	if input.WithScheduling {
+		for _, field := range input.Fields {
+			if field.Name == "PublishAt" || field.Name == "UnpublishAt" || field.Name == "PublishState" {
+				return types.NewErrorResult(fmt.Sprintf("field '%s' is added by with_scheduling: remove it from fields", field.Name)), nil
+			}
+		}
+	}
+
+	// Validate hooks
+	for i, hook := range input.WithHooks {
+		if err := utils.ValidateModelHook(hook); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("with_hooks: %v", err)), nil
+		}
+		if slices.Contains(input.WithHooks[:i], hook) {
+			return types.NewErrorResult(fmt.Sprintf("with_hooks: '%s' is given more than once", hook)), nil
+		}
+	}
+
+	// Validate ownership: records belong to the session user, which only auth projects have
+	if input.OwnedByUser != nil && *input.OwnedByUser {
+		if input.RouteGroup != "authenticated" {
+			return types.NewErrorResult(fmt.Sprintf("owned_by_user requires route_group authenticated, got %s", input.RouteGroup)), nil
+		}
+		if !registry.HasAuth() {
+			return types.NewErrorResult("owned_by_user requires a project scaffolded with auth (with_auth: true)"), nil
+		}
+	}
+	if input.GetOwnedByUser() {
+		for _, field := range input.Fields {
+			if field.Name == "UserID" {
+				return types.NewErrorResult("field 'UserID' is added by owned_by_user: remove it from fields"), nil
+			}
+		}
+		for _, rel := range input.Relationships {
+			if rel.Type == "belongs_to" && utils.ToModelName(rel.Model) == "User" {
+				return types.NewErrorResult("a belongs_to User relationship conflicts with the UserID owned_by_user adds: remove it, or set owned_by_user: false"), nil
+			}
+		}
 	}
 
 	// Validate relationships
@@ -181,14 +586,45 @@ func scaffoldDomain(registry *Registry, input types.ScaffoldDomainInput) (types.
 		if err := utils.ValidateRelationshipType(rel.Type); err != nil {
 			return types.NewErrorResult(fmt.Sprintf("relationship to '%s': %v", rel.Model, err)), nil
 		}
-		if err := utils.ValidateRelationshipModel(rel.Model); err != nil {
-			return types.NewErrorResult(fmt.Sprintf("relationship: %v", err)), nil
+		if !isSelfRelationship(rel) {
+			if err := utils.ValidateRelationshipModel(rel.Model); err != nil {
+				return types.NewErrorResult(fmt.Sprintf("relationship: %v", err)), nil
+			}
 		}
 		if rel.OnDelete != "" {
 			if err := utils.ValidateOnDelete(rel.OnDelete); err != nil {
 				return types.NewErrorResult(fmt.Sprintf("relationship to '%s': %v", rel.Model, err)), nil
 			}
 		}
+		if len(rel.JoinFields) > 0 {
+			if rel.Type != "many_to_many" {
+				return types.NewErrorResult(fmt.Sprintf("relationship to '%s': join_fields are only supported on many_to_many relationships", rel.Model)), nil
+			}
+			if utils.ToModelName(rel.Model) == utils.ToModelName(input.DomainName) {
+				return types.NewErrorResult(fmt.Sprintf("relationship to '%s': join_fields are not supported on self-referencing many_to_many relationships", rel.Model)), nil
+			}
+			for _, field := range rel.JoinFields {
+				if err := utils.ValidateFieldName(field.Name); err != nil {
+					return types.NewErrorResult(fmt.Sprintf("join field '%s': %v", field.Name, err)), nil
+				}
+				if err := utils.ValidateJoinFieldType(field.Type); err != nil {
+					return types.NewErrorResult(fmt.Sprintf("join field '%s': %v", field.Name, err)), nil
+				}
+			}
+		}
+		if rel.NestedForm && rel.Type != "has_many" {
+			return types.NewErrorResult(fmt.Sprintf("relationship to '%s': nested_form is only supported on has_many relationships", rel.Model)), nil
+		}
+		if rel.Type == "polymorphic" {
+			if len(rel.Models) == 0 {
+				return types.NewErrorResult(fmt.Sprintf("polymorphic relationship '%s': at least one owner model is required in models", rel.Model)), nil
+			}
+			for _, owner := range rel.Models {
+				if err := utils.ValidateRelationshipModel(owner); err != nil {
+					return types.NewErrorResult(fmt.Sprintf("polymorphic relationship '%s': %v", rel.Model, err)), nil
+				}
+			}
+		}
 	}
 
 	// Get module path from go.mod
@@ -200,9 +636,15 @@ func scaffoldDomain(registry *Registry, input types.ScaffoldDomainInput) (types.
 	// Create generator
 	gen := registry.NewGenerator("")
 	gen.SetDryRun(input.DryRun)
+	gen.SetBuffered(true)
 
 	// Prepare template data
-	data := generator.NewDomainData(input, modulePath)
+	data, err := newDomainData(registry, input, modulePath)
+	if err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	defaultLocale := projectDefaultLocale(registry.WorkingDir)
+	tsFormat := registry.TSTypesFormat()
 
 	// Create directories
 	pkgName := utils.ToPackageName(input.DomainName)
@@ -222,62 +664,21 @@ func scaffoldDomain(registry *Registry, input types.ScaffoldDomainInput) (types.
 		}
 	}
 
-	// Generate model
-	modelPath := filepath.Join("internal", "models", pkgName+".go")
-	if err := gen.GenerateFile("domain/model.go.tmpl", modelPath, data); err != nil {
-		return types.NewErrorResult(fmt.Sprintf("failed to generate model: %v", err)), nil
-	}
-
-	// Generate repository
-	repoPath := filepath.Join("internal", "repository", pkgName, pkgName+".go")
-	if err := gen.GenerateFile("domain/repository.go.tmpl", repoPath, data); err != nil {
-		return types.NewErrorResult(fmt.Sprintf("failed to generate repository: %v", err)), nil
-	}
-
-	// Generate service
-	servicePath := filepath.Join("internal", "services", pkgName, pkgName+".go")
-	if err := gen.GenerateFile("domain/service.go.tmpl", servicePath, data); err != nil {
-		return types.NewErrorResult(fmt.Sprintf("failed to generate service: %v", err)), nil
-	}
-
-	// Generate DTOs
-	dtoPath := filepath.Join("internal", "services", pkgName, "dto.go")
-	if err := gen.GenerateFile("domain/dto.go.tmpl", dtoPath, data); err != nil {
-		return types.NewErrorResult(fmt.Sprintf("failed to generate DTOs: %v", err)), nil
-	}
-
-	// Generate controller
-	controllerPath := filepath.Join("internal", "web", pkgName, pkgName+".go")
-	if err := gen.GenerateFile("domain/controller.go.tmpl", controllerPath, data); err != nil {
-		return types.NewErrorResult(fmt.Sprintf("failed to generate controller: %v", err)), nil
-	}
-
-	// Generate CRUD views if requested
-	if input.GetWithCrudViews() {
-		viewsDir := filepath.Join("internal", "web", pkgName, "views")
-
-		// Generate list view
-		listPath := filepath.Join(viewsDir, "list.templ")
-		if err := gen.GenerateFile("views/list.templ.tmpl", listPath, data); err != nil {
-			return types.NewErrorResult(fmt.Sprintf("failed to generate list view: %v", err)), nil
+	// Generate the domain's files
+	for _, f := range domainFiles(registry, input, data) {
+		generate := gen.GenerateFile
+		if f.shared {
+			generate = gen.GenerateFileIfNotExists
 		}
-
-		// Generate show view
-		showPath := filepath.Join(viewsDir, "show.templ")
-		if err := gen.GenerateFile("views/show.templ.tmpl", showPath, data); err != nil {
-			return types.NewErrorResult(fmt.Sprintf("failed to generate show view: %v", err)), nil
-		}
-
-		// Generate form view
-		formPath := filepath.Join(viewsDir, pkgName+"_form.templ")
-		if err := gen.GenerateFile("views/form.templ.tmpl", formPath, data); err != nil {
-			return types.NewErrorResult(fmt.Sprintf("failed to generate form view: %v", err)), nil
+		if err := generate(f.template, f.output, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.what, err)), nil
 		}
+	}
 
-		// Generate partials (card, empty state, etc.)
-		partialsPath := filepath.Join(viewsDir, "partials.templ")
-		if err := gen.GenerateFile("views/partials.templ.tmpl", partialsPath, data); err != nil {
-			return types.NewErrorResult(fmt.Sprintf("failed to generate partials: %v", err)), nil
+	var imageColumns []string
+	for _, field := range data.Fields {
+		if field.FormType == "image" {
+			imageColumns = append(imageColumns, utils.ToSnakeCase(field.Name))
 		}
 	}
 
@@ -289,36 +690,222 @@ func scaffoldDomain(registry *Registry, input types.ScaffoldDomainInput) (types.
 		return *conflictResult, nil
 	}
 
-	// Inject into main.go, database.go, and base_layout.templ if not dry run
+	// Write the generated files in one pass, or none of them
+	if err := gen.Flush(); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to write files: %v", err)), nil
+	}
+
+	// Inject into main.go, database.go, and menu.toml if not dry run
+	moderationUnprotected := false
+	searchMigrationMissing := false
+	schedulerMissing := false
+	realtimeUnwired := false
+	menuMissing := false
+	warnings := &scaffoldWarnings{strict: input.Strict || registry.Strict}
+	rollback := newFileRollback(registry.WorkingDir)
 	if !input.DryRun {
 		mainGoPath := filepath.Join(registry.WorkingDir, "cmd", "web", "main.go")
 		databaseGoPath := filepath.Join(registry.WorkingDir, "internal", "database", "database.go")
-		layoutPath := filepath.Join(registry.WorkingDir, "internal", "web", "layouts", "base_layout.templ")
+		menuPath := filepath.Join(registry.WorkingDir, "config", "en", "menu.toml")
+		if warnings.strict {
+			rollback.track(
+				filepath.Join("cmd", "web", "main.go"),
+				filepath.Join("cmd", "web", "wire.go"),
+				filepath.Join("cmd", "web", "domains.go"),
+				filepath.Join("internal", "database", "database.go"),
+				filepath.Join("config", "en", "menu.toml"),
+				filepath.Join("cmd", "reprocess-images", "main.go"),
+				filepath.Join(".mcp", "scaffold-metadata.json"),
+			)
+			if tsFormat != "" {
+				rollback.track(filepath.Join(tsTypesDir, tsTypesFormats[tsFormat].index))
+			}
+			if data.Messages != nil {
+				rollback.track(filepath.Join("config", defaultLocale, "messages", "common.toml"))
+			}
+			for _, path := range relatedModelPaths(input.Relationships) {
+				rollback.track(path)
+			}
+		}
+
+		// Each injection either applies in full or leaves its files as they were
+		wd := registry.WorkingDir
+		mainGoFile := filepath.Join("cmd", "web", "main.go")
+		databaseGoFile := filepath.Join("internal", "database", "database.go")
 		if utils.FileExists(mainGoPath) {
-			if err := injectDomainWiring(mainGoPath, databaseGoPath, modulePath, pkgName, input.DomainName, data.RouteGroup, input.Relationships, data.WithCrudViews); err != nil {
-				// Log warning but don't fail
-				fmt.Printf("Warning: could not inject DI wiring: %v\n", err)
+			wiringFiles := []string{mainGoFile, filepath.Join("cmd", "web", "wire.go"), filepath.Join("cmd", "web", "domains.go"), databaseGoFile}
+			if err := injectStep(wd, wiringFiles, func() error {
+				return injectDomainWiring(mainGoPath, databaseGoPath, modulePath, pkgName, input.DomainName, data.RouteGroup, input.Relationships, data.WithCrudViews, input.WithCache)
+			}); err != nil {
+				warnings.add("could not inject DI wiring: %v", err)
 			} else {
 				result.FilesUpdated = append(result.FilesUpdated, "cmd/web/main.go")
+				if registry.UsesWire() {
+					result.FilesUpdated = append(result.FilesUpdated, "cmd/web/wire.go", "cmd/web/domains.go")
+				}
 				if utils.FileExists(databaseGoPath) {
 					result.FilesUpdated = append(result.FilesUpdated, "internal/database/database.go")
 				}
-				// Track layout update for authenticated/admin routes
-				if (data.RouteGroup == "authenticated" || data.RouteGroup == "admin") && utils.FileExists(layoutPath) {
-					result.FilesUpdated = append(result.FilesUpdated, "internal/web/layouts/base_layout.templ")
-				}
+			}
+		}
+
+		// Publish the domain's changes to the realtime hub
+		if data.WithRealtime {
+			if registry.UsesWire() || !utils.FileExists(mainGoPath) {
+				realtimeUnwired = true
+			} else if err := injectStep(wd, []string{mainGoFile}, func() error { return injectRealtimeService(mainGoPath, input) }); err != nil {
+				realtimeUnwired = true
+			}
+		}
+
+		// Export the domain's TypeScript types from the types index
+		if tsFormat != "" {
+			if err := injectStep(wd, []string{filepath.Join(tsTypesDir, tsTypesFormats[tsFormat].index)}, func() error {
+				return injectTSTypesExport(wd, input.DomainName, tsFormat)
+			}); err != nil {
+				warnings.add("could not export the TypeScript types: %v", err)
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, filepath.ToSlash(filepath.Join(tsTypesDir, tsTypesFormats[tsFormat].index)))
+			}
+		}
+
+		// Add a sidebar link for authenticated/admin routes
+		if data.RouteGroup == "authenticated" || data.RouteGroup == "admin" {
+			if !utils.FileExists(menuPath) {
+				menuMissing = true
+			} else if err := injectStep(wd, []string{filepath.Join("config", "en", "menu.toml")}, func() error {
+				return injectMenuItem(menuPath, input.DomainName, data.RouteGroup, input.Icon)
+			}); err != nil {
+				warnings.add("could not add menu entry: %v", err)
+				menuMissing = true
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, "config/en/menu.toml")
+			}
+		}
+
+		// Mount the moderation queue next to the domain routes
+		if input.WithModeration && utils.FileExists(mainGoPath) {
+			var admin bool
+			if err := injectStep(wd, []string{mainGoFile}, func() (err error) {
+				admin, err = injectModerationRoute(mainGoPath, input.DomainName)
+				return err
+			}); err != nil {
+				warnings.add("could not inject moderation routes: %v", err)
+			} else if !admin {
+				moderationUnprotected = true
+			}
+		}
+
+		// Create the full-text index after AutoMigrate
+		if data.FullTextSearch {
+			var injected bool
+			if err := injectStep(wd, []string{databaseGoFile}, func() (err error) {
+				injected, err = injectSearchMigration(databaseGoPath, data.ModelName)
+				return err
+			}); err != nil {
+				warnings.add("could not inject search migration: %v", err)
+				searchMigrationMissing = true
+			} else if injected {
+				result.FilesUpdated = appendUnique(result.FilesUpdated, "internal/database/database.go")
+			} else {
+				searchMigrationMissing = true
+			}
+		}
+
+		// Start the publish state task with the other background tasks
+		if input.WithScheduling {
+			var injected bool
+			if err := injectStep(wd, []string{mainGoFile}, func() (err error) {
+				injected, err = injectScheduledTask(mainGoPath, modulePath, input.DomainName)
+				return err
+			}); err != nil {
+				warnings.add("could not inject scheduled task: %v", err)
+				schedulerMissing = true
+			} else if !injected {
+				schedulerMissing = true
+			}
+		}
+
+		// Serve uploads and register the image columns for reprocessing
+		if len(imageColumns) > 0 {
+			if err := injectStep(wd, []string{mainGoFile, filepath.Join("cmd", "reprocess-images", "main.go")}, func() error {
+				return injectImageWiring(wd, modulePath, data.TableName, imageColumns)
+			}); err != nil {
+				warnings.add("could not inject image wiring: %v", err)
 			}
 		}
 
 		// Inject inverse relationships into related models
 		if len(input.Relationships) > 0 {
-			injectInverseRelationships(registry.WorkingDir, input.DomainName, input.Relationships, &result.FilesUpdated)
+			for _, err := range injectInverseRelationships(registry.WorkingDir, input.DomainName, input.Relationships, &result.FilesUpdated) {
+				warnings.add("could not inject inverse relationship: %v", err)
+			}
+		}
+
+		// Add the translation keys of the views to the default locale
+		if data.Messages != nil {
+			messagesName := utils.ToSnakeCase(utils.ParseDomainPath(input.DomainName))
+			messagesDir := filepath.Join("config", defaultLocale, "messages")
+			var created, updated []string
+			if err := injectStep(wd, []string{filepath.Join(messagesDir, messagesName+".toml"), filepath.Join(messagesDir, "common.toml")}, func() (err error) {
+				created, updated, err = writeDomainMessages(wd, defaultLocale, messagesName, data.Messages, false)
+				return err
+			}); err != nil {
+				warnings.add("could not write translation keys: %v", err)
+			} else {
+				result.FilesCreated = append(result.FilesCreated, created...)
+				result.FilesUpdated = append(result.FilesUpdated, updated...)
+			}
+		}
+
+		if warnings.failed() {
+			return types.NewErrorResult(strictFailure(rollback, result.FilesCreated, warnings)), nil
+		}
+	}
+	nextSteps := []string{"go mod tidy"}
+	if !apiProject {
+		nextSteps = append(nextSteps, "templ generate")
+	}
+	if registry.UsesWire() {
+		nextSteps = append(nextSteps, "task wire  # Regenerate cmd/web/wire_gen.go with the new provider set")
+	}
+	nextSteps = append(nextSteps, fmt.Sprintf("Add business logic to internal/services/%s/%s.go", pkgName, pkgName))
+	if menuMissing {
+		nextSteps = append(nextSteps, fmt.Sprintf("Add a sidebar link to %s", utils.ToURLPath(input.DomainName)))
+	}
+	if moderationUnprotected {
+		nextSteps = append(nextSteps, fmt.Sprintf("Protect /admin%s/moderation in cmd/web/main.go: the project has no admin route group", utils.ToURLPath(input.DomainName)))
+	}
+	if input.WithObservability && !tracedProject {
+		nextSteps = append(nextSteps, "Register your tracer and meter providers with otel.SetTracerProvider and otel.SetMeterProvider at startup")
+	}
+	if data.FullTextSearch {
+		if searchMigrationMissing {
+			nextSteps = append(nextSteps, fmt.Sprintf("Call models.Migrate%sSearch(db) after AutoMigrate in internal/database/database.go", data.ModelName))
 		}
+		nextSteps = append(nextSteps, "SQLite: build and run with -tags sqlite_fts5 (go-sqlite3 leaves FTS5 out by default); new projects set it in Taskfile.yml and .air.toml")
+	}
+	if schedulerMissing {
+		nextSteps = append(nextSteps, fmt.Sprintf("Start the publishing task in cmd/web/main.go: scheduler.Start(\"%s publishing\", %s.UpdatePublishStates)", utils.ToTableName(input.DomainName), utils.ToServiceVariableName(input.DomainName)))
+	}
+	if realtimeUnwired {
+		nextSteps = append(nextSteps, realtimeWiringStep(input))
+	}
+	if input.WithScheduling {
+		nextSteps = append(nextSteps, "Publish states are refreshed every minute; set SCHEDULER_INTERVAL (e.g. 30s) to change it")
 	}
-	nextSteps := []string{
-		"go mod tidy",
-		"templ generate",
-		fmt.Sprintf("Add business logic to internal/services/%s/%s.go", pkgName, pkgName),
+	if input.WithTests {
+		nextSteps = append(nextSteps, fmt.Sprintf("go test ./internal/repository/%s/", pkgName))
+	}
+	if len(data.Hooks) > 0 {
+		nextSteps = append(nextSteps, fmt.Sprintf("Implement the %s hook handlers in internal/services/%s/hooks.go", data.ModelName, pkgName))
+	}
+	if input.WithCache {
+		nextSteps = append(nextSteps, "Results are cached in memory for 5 minutes; set REDIS_URL (e.g. redis://localhost:6379/0) to share the cache between instances and CACHE_TTL to change the TTL")
+	}
+	if len(imageColumns) > 0 {
+		nextSteps = append(nextSteps, "Images are stored in ./uploads; set STORAGE_DRIVER=s3 with S3_BUCKET, S3_REGION and AWS credentials to store them in S3")
+		nextSteps = append(nextSteps, "After changing images.Widths, regenerate the variants with: go run ./cmd/reprocess-images")
 	}
 
 	// Suggest tools for extending the domain
@@ -357,8 +944,11 @@ func scaffoldDomain(registry *Registry, input types.ScaffoldDomainInput) (types.
 	// Save scaffold metadata for future sync/upgrade capabilities
 	metaStore := metadata.NewStore(registry.WorkingDir)
 	if err := metaStore.SaveDomain(input.DomainName, input, ScaffolderVersion); err != nil {
-		// Log warning but don't fail - metadata is optional
-		fmt.Printf("Warning: could not save scaffold metadata: %v\n", err)
+		// Metadata is optional unless strict
+		warnings.add("could not save scaffold metadata: %v", err)
+		if warnings.failed() {
+			return types.NewErrorResult(strictFailure(rollback, result.FilesCreated, warnings)), nil
+		}
 	} else {
 		result.FilesUpdated = append(result.FilesUpdated, ".mcp/scaffold-metadata.json")
 	}
@@ -368,15 +958,212 @@ func scaffoldDomain(registry *Registry, input types.ScaffoldDomainInput) (types.
 		Message:        fmt.Sprintf("Successfully created domain '%s'", input.DomainName),
 		FilesCreated:   result.FilesCreated,
 		FilesUpdated:   result.FilesUpdated,
+		Backups:        result.Backups,
 		NextSteps:      nextSteps,
 		SuggestedTools: suggestedTools,
 	}, nil
 }
 
+// applyProjectDefaults fills in what a domain input leaves to the project: the defaults of
+// .gomcp.toml, tracing in projects scaffolded with with_otel, no views in API-only projects
+// and no ownership in projects without auth.
+func applyProjectDefaults(registry *Registry, input *types.ScaffoldDomainInput) error {
+	defaults, err := registry.Defaults()
+	if err != nil {
+		return err
+	}
+	input.Layout = config.Or(input.Layout, defaults.Layout)
+	input.RouteGroup = config.Or(input.RouteGroup, defaults.RouteGroup)
+	input.FormStyle = config.Or(input.FormStyle, defaults.FormStyle)
+
+	// Projects scaffolded with with_otel trace every service, so a request's trace runs
+	// from the HTTP span through the service span to the GORM query spans
+	if isTracedProject(registry.WorkingDir) {
+		input.WithObservability = true
+	}
+	if registry.IsAPIProject() && input.WithCrudViews == nil {
+		withoutViews := false
+		input.WithCrudViews = &withoutViews
+	}
+	if input.OwnedByUser == nil && input.RouteGroup == "authenticated" && !registry.HasAuth() {
+		notOwned := false
+		input.OwnedByUser = &notOwned
+	}
+	return nil
+}
+
+// isTracedProject reports whether the project was scaffolded with with_otel.
+func isTracedProject(workingDir string) bool {
+	return utils.FileExists(filepath.Join(workingDir, "internal", "telemetry", "telemetry.go"))
+}
+
+// newDomainData returns the template data of a domain: its input and the features of the
+// project it is scaffolded into.
+func newDomainData(registry *Registry, input types.ScaffoldDomainInput, modulePath string) (generator.DomainData, error) {
+	data := generator.NewDomainData(input, modulePath)
+	data.WithLogging = registry.HasStructuredLogging()
+	data.WithSEO = registry.HasSEO()
+	data.WithBreadcrumbs = registry.HasBreadcrumbs()
+	data.WithTestContainers = registry.HasTestContainers()
+
+	// Views of projects with i18n render translation keys
+	if projectDefaultLocale(registry.WorkingDir) != "" && input.GetWithCrudViews() {
+		data.Messages = generator.NewMessages(input.DomainName)
+	}
+
+	markExistingEmbeddedTypes(registry.WorkingDir, utils.ToPackageName(input.DomainName)+".go", data.Fields)
+	if err := loadNestedFormFields(registry.WorkingDir, data.Relationships); err != nil {
+		return data, err
+	}
+	return data, nil
+}
+
+// domainFile is a file scaffold_domain generates from a template.
+type domainFile struct {
+	template string
+	output   string
+	// what names the file in errors, e.g. "model"
+	what string
+	// shared files, like the cache package, are generated once for every domain that needs them
+	shared bool
+}
+
+// domainFiles returns the files scaffold_domain generates for a domain, in generation order.
+func domainFiles(registry *Registry, input types.ScaffoldDomainInput, data generator.DomainData) []domainFile {
+	pkgName := utils.ToPackageName(input.DomainName)
+	repoDir := filepath.Join("internal", "repository", pkgName)
+	serviceDir := filepath.Join("internal", "services", pkgName)
+	webDir := filepath.Join("internal", "web", pkgName)
+
+	files := []domainFile{
+		{template: "domain/model.go.tmpl", output: filepath.Join("internal", "models", pkgName+".go"), what: "model"},
+		{template: "domain/repository.go.tmpl", output: filepath.Join(repoDir, pkgName+".go"), what: "repository"},
+	}
+
+	// Repository tests if requested
+	if data.WithTests {
+		files = append(files, domainFile{template: "tests/repository_test.go.tmpl", output: filepath.Join(repoDir, pkgName+"_test.go"), what: "repository tests"})
+	}
+
+	files = append(files,
+		domainFile{template: "domain/service.go.tmpl", output: filepath.Join(serviceDir, pkgName+".go"), what: "service"},
+		domainFile{template: "domain/dto.go.tmpl", output: filepath.Join(serviceDir, "dto.go"), what: "DTOs"},
+	)
+
+	// The service decorator publishing the domain's changes to the realtime hub
+	if data.WithRealtime {
+		files = append(files, domainFile{template: "realtime/service_events.go.tmpl", output: realtimeServicePath(input.DomainName), what: "realtime service"})
+	}
+
+	// The hook handlers and the events dispatcher shared by every domain with hooks
+	if len(data.Hooks) > 0 {
+		eventsPath := filepath.Join("internal", "events", "events.go")
+		files = append(files,
+			domainFile{template: "events/events.go.tmpl", output: eventsPath, what: eventsPath, shared: true},
+			domainFile{template: "domain/hooks.go.tmpl", output: filepath.Join(serviceDir, "hooks.go"), what: "hook handlers"},
+		)
+	}
+
+	controllerTemplate := "domain/controller.go.tmpl"
+	if registry.IsAPIProject() {
+		controllerTemplate = "api/controller.go.tmpl"
+	}
+	files = append(files, domainFile{template: controllerTemplate, output: filepath.Join(webDir, pkgName+".go"), what: "controller"})
+
+	// Projects with di wire build the domain from its provider set
+	if registry.UsesWire() {
+		files = append(files, domainFile{template: "domain/providers.go.tmpl", output: filepath.Join(webDir, "providers.go"), what: "provider set"})
+	}
+
+	// Projects with the routes registry mount the controller in the group its routes.go names
+	if registry.UsesRouteRegistry() {
+		files = append(files, domainFile{template: "domain/routes.go.tmpl", output: filepath.Join(webDir, "routes.go"), what: "routes"})
+	}
+
+	// Projects with scaffold_ts_types get the TypeScript types of the domain's DTOs
+	if format := registry.TSTypesFormat(); format != "" {
+		files = append(files, domainFile{template: tsTypesFormats[format].domainTemplate, output: tsTypesPath(input.DomainName, format), what: "TypeScript types"})
+	}
+
+	// Versioned API route groups share the API-Version/deprecation middleware
+	if data.APIVersion != "" {
+		versioningPath := filepath.Join("internal", "web", "middleware", "versioning.go")
+		files = append(files, domainFile{template: "api/versioning.go.tmpl", output: versioningPath, what: versioningPath, shared: true})
+	}
+
+	// The response DTO frozen for the API version
+	if data.WithVersionedDTO {
+		files = append(files, domainFile{template: "api/versioned_dto.go.tmpl", output: filepath.Join(webDir, "dto_"+data.APIVersion+".go"), what: "versioned DTO"})
+	}
+
+	// CRUD views if requested
+	if input.GetWithCrudViews() {
+		viewsDir := filepath.Join(webDir, "views")
+		files = append(files,
+			domainFile{template: "views/list.templ.tmpl", output: filepath.Join(viewsDir, "list.templ"), what: "list view"},
+			domainFile{template: "views/show.templ.tmpl", output: filepath.Join(viewsDir, "show.templ"), what: "show view"},
+			domainFile{template: "views/form.templ.tmpl", output: filepath.Join(viewsDir, pkgName+"_form.templ"), what: "form view"},
+			domainFile{template: "views/partials.templ.tmpl", output: filepath.Join(viewsDir, "partials.templ"), what: "partials"},
+		)
+
+		// The [meta] defaults the controller's seo.Defaults applies and the [breadcrumbs]
+		// labels of breadcrumbs.Section; an existing page file, e.g. from scaffold_config, is kept
+		if data.WithSEO || data.WithBreadcrumbs {
+			metaPath := filepath.Join("config", "en", "pages", data.SEOPage+".toml")
+			files = append(files, domainFile{template: "domain/page_meta.toml.tmpl", output: metaPath, what: metaPath, shared: true})
+		}
+
+		if input.WithModeration {
+			files = append(files, domainFile{template: "views/moderation.templ.tmpl", output: filepath.Join(viewsDir, "moderation.templ"), what: "moderation queue"})
+		}
+	}
+
+	// The image pipeline shared by every domain with image fields
+	if slices.ContainsFunc(data.Fields, func(field generator.FieldData) bool { return field.FormType == "image" }) {
+		for _, f := range []struct{ template, output string }{
+			{"images/storage.go.tmpl", filepath.Join("internal", "storage", "storage.go")},
+			{"images/s3.go.tmpl", filepath.Join("internal", "storage", "s3.go")},
+			{"images/images.go.tmpl", filepath.Join("internal", "images", "images.go")},
+			{"images/reprocess_main.go.tmpl", filepath.Join("cmd", "reprocess-images", "main.go")},
+		} {
+			files = append(files, domainFile{template: f.template, output: f.output, what: f.output, shared: true})
+		}
+	}
+
+	// The cache shared by every cached domain and the repository decorator
+	if input.WithCache {
+		for _, f := range []struct{ template, output string }{
+			{"cache/cache.go.tmpl", filepath.Join("internal", "cache", "cache.go")},
+			{"cache/redis.go.tmpl", filepath.Join("internal", "cache", "redis.go")},
+		} {
+			files = append(files, domainFile{template: f.template, output: f.output, what: f.output, shared: true})
+		}
+		files = append(files, domainFile{template: "cache/repository.go.tmpl", output: filepath.Join(repoDir, "cache.go"), what: "repository cache"})
+	}
+
+	// The scheduler shared by every domain with scheduled publishing
+	if input.WithScheduling {
+		schedulerPath := filepath.Join("internal", "scheduler", "scheduler.go")
+		files = append(files, domainFile{template: "scheduler/scheduler.go.tmpl", output: schedulerPath, what: schedulerPath, shared: true})
+	}
+	return files
+}
+
 // injectInverseRelationships injects inverse relationship fields into related models.
 // For example, if Order has belongs_to: User, this will add Orders []Order to User model.
-func injectInverseRelationships(workingDir string, domainName string, relationships []types.RelationshipDef, filesUpdated *[]string) {
+// Related models that are not scaffolded yet are skipped; it returns the injections that failed.
+func injectInverseRelationships(workingDir string, domainName string, relationships []types.RelationshipDef, filesUpdated *[]string) []error {
+	var errs []error
 	for _, rel := range relationships {
+		if rel.Type == "polymorphic" {
+			errs = append(errs, injectPolymorphicOwners(workingDir, domainName, rel, filesUpdated)...)
+			continue
+		}
+		if isSelfRelationship(rel) {
+			// Children is already part of the generated model.
+			continue
+		}
+
 		var inverseFieldCode string
 		var inverseModelPath string
 
@@ -389,12 +1176,15 @@ func injectInverseRelationships(workingDir string, domainName string, relationsh
 		}
 
 		// Check if the model has the relationship markers
+		relPath := filepath.Join("internal", "models", relatedPkgName+".go")
 		injector, err := modifier.NewInjector(inverseModelPath)
 		if err != nil {
+			errs = append(errs, err)
 			continue
 		}
 
 		if !injector.HasMarker(modifier.MarkerRelationshipsStart) {
+			errs = append(errs, fmt.Errorf("%s has no %s marker", relPath, modifier.MarkerRelationshipsStart))
 			continue
 		}
 
@@ -431,12 +1221,7 @@ func injectInverseRelationships(workingDir string, domainName string, relationsh
 			fieldName := utils.Pluralize(modelName)
 			joinTable := rel.JoinTable
 			if joinTable == "" {
-				// Default join table name (alphabetical order)
-				names := []string{utils.ToSnakeCase(utils.Pluralize(domainName)), utils.ToSnakeCase(utils.Pluralize(rel.Model))}
-				if names[0] > names[1] {
-					names[0], names[1] = names[1], names[0]
-				}
-				joinTable = names[0] + "_" + names[1]
+				joinTable = generator.DefaultJoinTable(domainName, rel.Model)
 			}
 			inverseFieldCode = fmt.Sprintf(`%s []%s `+"`"+`gorm:"many2many:%s" json:"%s,omitempty"`+"`",
 				fieldName, modelName, joinTable, utils.ToSnakeCase(fieldName))
@@ -445,27 +1230,353 @@ func injectInverseRelationships(workingDir string, domainName string, relationsh
 			continue
 		}
 
+		// Skip models that already declare the inverse field, e.g. a child scaffolded with its belongs_to first
+		inverseField, _, _ := strings.Cut(inverseFieldCode, " ")
+		if declaresField(injector.Content(), inverseField) {
+			continue
+		}
+
 		// Inject the inverse relationship
 		if err := injector.InjectRelationship(inverseFieldCode); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", relPath, err))
+			continue
+		}
+
+		if err := injector.Save(); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		*filesUpdated = append(*filesUpdated, relPath)
+	}
+	return errs
+}
+
+// relatedModelPaths returns the model files injectInverseRelationships may change.
+func relatedModelPaths(relationships []types.RelationshipDef) []string {
+	var paths []string
+	for _, rel := range relationships {
+		models := []string{rel.Model}
+		if rel.Type == "polymorphic" {
+			models = rel.Models
+		}
+		for _, model := range models {
+			paths = append(paths, filepath.Join("internal", "models", utils.ToPackageName(model)+".go"))
+		}
+	}
+	return paths
+}
+
+// declaresField reports whether Go source declares a struct field with the given name.
+func declaresField(content, name string) bool {
+	return regexp.MustCompile(`(?m)^\s+` + regexp.QuoteMeta(name) + `\s`).MatchString(content)
+}
+
+// modelStructRegex matches struct declarations in the models package.
+var modelStructRegex = regexp.MustCompile(`(?m)^type (\w+) struct`)
+
+// markExistingEmbeddedTypes flags embedded value types that another model file already declares,
+// so the generated model reuses them instead of declaring them again.
+func markExistingEmbeddedTypes(workingDir, modelFile string, fields []generator.FieldData) {
+	modelsDir := filepath.Join(workingDir, "internal", "models")
+	entries, err := os.ReadDir(modelsDir)
+	if err != nil {
+		return
+	}
+
+	declared := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.IsDir() || entry.Name() == modelFile || !strings.HasSuffix(entry.Name(), ".go") {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(modelsDir, entry.Name()))
+		if err != nil {
+			continue
+		}
+		for _, match := range modelStructRegex.FindAllStringSubmatch(string(content), -1) {
+			declared[match[1]] = true
+		}
+	}
+
+	for i := range fields {
+		if fields[i].IsEmbedded && declared[fields[i].Type] {
+			fields[i].EmbeddedTypeExists = true
+		}
+	}
+}
+
+// loadNestedFormFields fills in the row fields of nested_form relationships from the scaffold
+// metadata of the child domains. Only scalar child fields are edited in the rows.
+func loadNestedFormFields(workingDir string, relationships []generator.RelationshipData) error {
+	var meta *metadata.ProjectMetadata
+	for i, rel := range relationships {
+		if !rel.NestedForm {
+			continue
+		}
+		if meta == nil {
+			var err error
+			if meta, err = metadata.NewStore(workingDir).Load(); err != nil {
+				return fmt.Errorf("failed to read scaffold metadata: %v", err)
+			}
+		}
+
+		var child *types.ScaffoldDomainInput
+		for _, domain := range meta.Domains {
+			if utils.ToModelName(domain.Input.DomainName) == rel.Model {
+				child = &domain.Input
+				break
+			}
+		}
+		if child == nil {
+			return fmt.Errorf("nested_form on '%s': scaffold the %s domain first so its fields are known", rel.Model, utils.ToPackageName(rel.Model))
+		}
+
+		hasParentKey := false
+		for _, childRel := range child.Relationships {
+			if childData := generator.NewRelationshipData(childRel, child.DomainName); childData.IsBelongsTo && childData.ForeignKey == rel.ForeignKey {
+				hasParentKey = true
+			}
+		}
+		if !hasParentKey {
+			return fmt.Errorf("nested_form on '%s': the %s domain needs a belongs_to relationship with foreign key %s", rel.Model, child.DomainName, rel.ForeignKey)
+		}
+
+		for _, field := range generator.NewFieldDataList(child.Fields) {
+			if !field.IsEmbedded && utils.ValidateNestedFieldType(field.Type) == nil {
+				relationships[i].NestedFields = append(relationships[i].NestedFields, field)
+			}
+		}
+		if len(relationships[i].NestedFields) == 0 {
+			return fmt.Errorf("nested_form on '%s': the %s domain has no fields that can be edited in a row", rel.Model, child.DomainName)
+		}
+	}
+	return nil
+}
+
+// isSelfRelationship reports whether rel is a belongs_to pointing at the domain itself.
+func isSelfRelationship(rel types.RelationshipDef) bool {
+	return rel.Type == "belongs_to" && strings.EqualFold(rel.Model, "self")
+}
+
+// injectPolymorphicOwners injects the has_many side of a polymorphic relationship into each owner model.
+// For example, if Comment is polymorphic Commentable over Post and Video, this adds
+// Comments []Comment to both the Post and Video models.
+func injectPolymorphicOwners(workingDir string, domainName string, rel types.RelationshipDef, filesUpdated *[]string) []error {
+	var errs []error
+	modelName := utils.ToModelName(domainName)
+	fieldName := utils.Pluralize(modelName)
+	polymorphicName := utils.ToModelName(rel.Model)
+
+	for _, owner := range rel.Models {
+		ownerPkgName := utils.ToPackageName(owner)
+		relPath := filepath.Join("internal", "models", ownerPkgName+".go")
+		ownerModelPath := filepath.Join(workingDir, relPath)
+		if !utils.FileExists(ownerModelPath) {
+			continue
+		}
+
+		injector, err := modifier.NewInjector(ownerModelPath)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		if !injector.HasMarker(modifier.MarkerRelationshipsStart) {
+			errs = append(errs, fmt.Errorf("%s has no %s marker", relPath, modifier.MarkerRelationshipsStart))
 			continue
 		}
 
+		fieldCode := fmt.Sprintf(`%s []%s `+"`"+`gorm:"polymorphic:%s;polymorphicValue:%s" json:"%s,omitempty"`+"`",
+			fieldName, modelName, polymorphicName, utils.ToTableName(owner), utils.ToSnakeCase(fieldName))
+
+		if err := injector.InjectRelationship(fieldCode); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", relPath, err))
+			continue
+		}
 		if err := injector.Save(); err != nil {
+			errs = append(errs, err)
 			continue
 		}
 
-		*filesUpdated = append(*filesUpdated, filepath.Join("internal", "models", relatedPkgName+".go"))
+		*filesUpdated = append(*filesUpdated, relPath)
+	}
+	return errs
+}
+
+// injectModerationRoute mounts the domain's moderation queue in main.go.
+// Returns whether the queue was mounted behind the admin middleware.
+func injectModerationRoute(mainGoPath, domainName string) (bool, error) {
+	injector, err := modifier.NewInjector(mainGoPath)
+	if err != nil {
+		return false, err
+	}
+	admin, err := injector.InjectModerationRoute(domainName)
+	if err != nil {
+		return false, err
+	}
+	return admin, injector.Save()
+}
+
+// injectSearchMigration adds the model's full-text search migration to RunMigrations.
+// It reports false when database.go predates the MCP:MIGRATIONS markers.
+func injectSearchMigration(databaseGoPath, modelName string) (bool, error) {
+	if !utils.FileExists(databaseGoPath) {
+		return false, nil
+	}
+	injector, err := modifier.NewInjector(databaseGoPath)
+	if err != nil {
+		return false, err
+	}
+	if !injector.HasMarker(modifier.MarkerMigrationsStart) {
+		return false, nil
+	}
+	if err := injector.InjectMigration("models.Migrate" + modelName + "Search(db)"); err != nil {
+		return false, err
+	}
+	return true, injector.Save()
+}
+
+// injectScheduledTask starts the domain's publish state task in main.go.
+// It reports false when main.go predates the MCP:SCHEDULER markers.
+func injectScheduledTask(mainGoPath, modulePath, domainName string) (bool, error) {
+	if !utils.FileExists(mainGoPath) {
+		return false, nil
+	}
+	injector, err := modifier.NewInjector(mainGoPath)
+	if err != nil {
+		return false, err
+	}
+	if !injector.HasMarker(modifier.MarkerSchedulerStart) {
+		return false, nil
+	}
+	if err := injector.InjectImport(modulePath + "/internal/scheduler"); err != nil {
+		return false, err
+	}
+	if err := injector.InjectScheduledTask(domainName); err != nil {
+		return false, err
+	}
+	return true, injector.Save()
+}
+
+// injectImageWiring mounts the uploads route in main.go and registers the domain's image
+// columns in cmd/reprocess-images/main.go.
+func injectImageWiring(workingDir, modulePath, tableName string, columns []string) error {
+	mainGoPath := filepath.Join(workingDir, "cmd", "web", "main.go")
+	if utils.FileExists(mainGoPath) {
+		injector, err := modifier.NewInjector(mainGoPath)
+		if err != nil {
+			return err
+		}
+		if err := injector.InjectImport(modulePath + "/internal/storage"); err != nil {
+			return err
+		}
+		if err := injector.InjectUploadsRoute(); err != nil {
+			return err
+		}
+		if err := injector.Save(); err != nil {
+			return err
+		}
+	}
+
+	injector, err := modifier.NewInjector(filepath.Join(workingDir, "cmd", "reprocess-images", "main.go"))
+	if err != nil {
+		return err
+	}
+	for _, column := range columns {
+		if err := injector.InjectImageColumn(tableName, column); err != nil {
+			return err
+		}
 	}
+	return injector.Save()
 }
 
-// injectDomainWiring injects the domain wiring into main.go, database.go, and base_layout.templ.
-func injectDomainWiring(mainGoPath, databaseGoPath, modulePath, pkgName, domainName, routeGroup string, relationships []types.RelationshipDef, withCrudViews bool) error {
+// injectDomainWiring injects the domain wiring into main.go and database.go.
+func injectDomainWiring(mainGoPath, databaseGoPath, modulePath, pkgName, domainName, routeGroup string, relationships []types.RelationshipDef, withCrudViews, withCache bool) error {
 	// Inject into main.go
 	mainInjector, err := modifier.NewInjector(mainGoPath)
 	if err != nil {
 		return err
 	}
 
+	// Projects with di wire build the domain from its provider set; main.go only takes the controller
+	if webDir := filepath.Dir(mainGoPath); utils.FileExists(filepath.Join(webDir, "wire.go")) {
+		if err := injectWireDomain(webDir, modulePath, domainName); err != nil {
+			return err
+		}
+		if err := mainInjector.InjectWireController(domainName); err != nil {
+			return err
+		}
+	} else if err := injectDomainConstructors(mainInjector, modulePath, pkgName, domainName, relationships, withCrudViews, withCache); err != nil {
+		return err
+	}
+
+	// Projects with the health endpoints check the cache before taking traffic
+	if withCache && mainInjector.HasMarker(modifier.MarkerHealthChecksStart) && cacheHasPing(filepath.Join(filepath.Dir(mainGoPath), "..", "..")) {
+		if err := mainInjector.InjectImport(modulePath + "/internal/cache"); err != nil {
+			return err
+		}
+		if err := mainInjector.InjectHealthCheck("cache", "cache.Ping"); err != nil {
+			return err
+		}
+	}
+
+	// Inject route with route group, in the version's sub-router for api_vN groups
+	version := utils.APIVersionFromRouteGroup(routeGroup)
+	if version != "" {
+		if err := mainInjector.InjectImport("github.com/go-chi/chi/v5"); err != nil {
+			return err
+		}
+		if err := mainInjector.InjectImport(modulePath + "/internal/web/middleware"); err != nil {
+			return err
+		}
+	}
+	if mainInjector.HasMarker(modifier.MarkerDomainRoutesStart) {
+		// The routes registry mounts the controller in the group its routes.go names
+		if err := mainInjector.InjectDomainRoutes(domainName, routeGroup); err != nil {
+			return err
+		}
+	} else if version != "" {
+		if err := mainInjector.InjectAPIVersionRoute(domainName, version); err != nil {
+			return err
+		}
+	} else if err := mainInjector.InjectRouteWithGroup(domainName, routeGroup); err != nil {
+		return err
+	}
+
+	if err := mainInjector.Save(); err != nil {
+		return err
+	}
+
+	// Inject model into database.go AutoMigrate
+	if databaseGoPath != "" && utils.FileExists(databaseGoPath) {
+		dbInjector, err := modifier.NewInjector(databaseGoPath)
+		if err != nil {
+			return err
+		}
+
+		modelName := utils.ToModelName(domainName)
+		if err := dbInjector.InjectModel(modelName); err != nil {
+			return err
+		}
+
+		// Join models carry the extra columns of many_to_many join tables
+		for _, rel := range relationships {
+			if joinModel := generator.NewRelationshipData(rel, domainName).JoinModel; joinModel != "" {
+				if err := dbInjector.InjectModel(joinModel); err != nil {
+					return err
+				}
+			}
+		}
+
+		if err := dbInjector.Save(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// injectDomainConstructors creates the domain's repository, service and controller in main.go.
+func injectDomainConstructors(mainInjector *modifier.Injector, modulePath, pkgName, domainName string, relationships []types.RelationshipDef, withCrudViews, withCache bool) error {
 	// Inject imports with aliases to avoid naming conflicts
 	repoImport := fmt.Sprintf("%s/internal/repository/%s", modulePath, pkgName)
 	repoAlias := utils.ToRepoImportAlias(domainName)
@@ -485,8 +1596,15 @@ func injectDomainWiring(mainGoPath, databaseGoPath, modulePath, pkgName, domainN
 		return err
 	}
 
-	// Inject repository
-	if err := mainInjector.InjectRepo(domainName, modulePath); err != nil {
+	// Inject repository, wrapped in the cache when enabled
+	if withCache {
+		if err := mainInjector.InjectImport(modulePath + "/internal/cache"); err != nil {
+			return err
+		}
+		if err := mainInjector.InjectCachedRepo(domainName); err != nil {
+			return err
+		}
+	} else if err := mainInjector.InjectRepo(domainName, modulePath); err != nil {
 		return err
 	}
 
@@ -499,59 +1617,59 @@ func injectDomainWiring(mainGoPath, databaseGoPath, modulePath, pkgName, domainN
 	var relatedDomains []string
 	if withCrudViews {
 		for _, rel := range relationships {
-			if rel.Type == "belongs_to" {
+			if rel.Type == "belongs_to" && !isSelfRelationship(rel) {
 				relatedDomains = append(relatedDomains, rel.Model)
 			}
 		}
 	}
 
 	// Inject controller with related services if needed
-	if err := mainInjector.InjectControllerWithRelations(domainName, relatedDomains); err != nil {
+	return mainInjector.InjectControllerWithRelations(domainName, relatedDomains)
+}
+
+// injectWireDomain adds the domain's provider set to the wire injector in webDir/wire.go and its
+// controller and service to the domains struct in webDir/domains.go.
+func injectWireDomain(webDir, modulePath, domainName string) error {
+	wireInjector, err := modifier.NewInjector(filepath.Join(webDir, "wire.go"))
+	if err != nil {
 		return err
 	}
-
-	// Inject route with route group
-	if err := mainInjector.InjectRouteWithGroup(domainName, routeGroup); err != nil {
+	if err := wireInjector.InjectWireProviderSet(domainName, modulePath); err != nil {
 		return err
 	}
 
-	if err := mainInjector.Save(); err != nil {
+	domainsPath := filepath.Join(webDir, "domains.go")
+	domainsInjector, err := modifier.NewInjector(domainsPath)
+	if err != nil {
 		return err
 	}
-
-	// Inject model into database.go AutoMigrate
-	if databaseGoPath != "" && utils.FileExists(databaseGoPath) {
-		dbInjector, err := modifier.NewInjector(databaseGoPath)
-		if err != nil {
-			return err
-		}
-
-		modelName := utils.ToModelName(domainName)
-		if err := dbInjector.InjectModel(modelName); err != nil {
-			return err
-		}
-
-		if err := dbInjector.Save(); err != nil {
-			return err
-		}
+	if err := domainsInjector.InjectWireDomain(domainName, modulePath); err != nil {
+		return err
+	}
+	// Align the new fields with the others
+	formatted, err := format.Source([]byte(domainsInjector.Content()))
+	if err != nil {
+		return fmt.Errorf("failed to format %s: %w", domainsPath, err)
 	}
 
-	// Inject navigation item into base_layout.templ for authenticated/admin routes
-	if routeGroup == "authenticated" || routeGroup == "admin" {
-		// Get directory of main.go to find base_layout.templ
-		baseDir := filepath.Dir(filepath.Dir(mainGoPath)) // cmd/web -> cmd -> project root
-		layoutPath := filepath.Join(baseDir, "internal", "web", "layouts", "base_layout.templ")
-
-		if utils.FileExists(layoutPath) {
-			navInjector, err := modifier.NewInjector(layoutPath)
-			if err == nil {
-				// Use default "folder" icon - domains can customize later
-				if err := navInjector.InjectNavItem(domainName, routeGroup, "folder"); err == nil {
-					_ = navInjector.Save()
-				}
-			}
-		}
+	if err := wireInjector.Save(); err != nil {
+		return err
 	}
+	return utils.WriteFileString(domainsPath, string(formatted), true)
+}
 
-	return nil
+// injectMenuItem appends the domain's sidebar link to menu.toml, with the "folder" icon
+// unless one is given.
+func injectMenuItem(menuPath, domainName, routeGroup, icon string) error {
+	injector, err := modifier.NewInjector(menuPath)
+	if err != nil {
+		return err
+	}
+	if icon == "" {
+		icon = "folder"
+	}
+	if err := injector.InjectMenuItem(domainName, routeGroup, icon); err != nil {
+		return err
+	}
+	return injector.Save()
 }
@@ -1,10 +1,13 @@
 package tools
 
 import (
+	"context"
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
 
+	"github.com/dbb1dev/go-mcp/internal/metadata"
 	"github.com/dbb1dev/go-mcp/internal/types"
 )
 
@@ -113,6 +116,1468 @@ func TestScaffoldDomain(t *testing.T) {
 		}
 	})
 
+	t.Run("validates inline edit fields", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		tests := []struct {
+			name       string
+			fields     []types.FieldDef
+			inlineEdit []string
+			wantErr    string
+		}{
+			{"unknown field", []types.FieldDef{{Name: "Name", Type: "string"}}, []string{"Status"}, "unknown field"},
+			{"unsupported type", []types.FieldDef{{Name: "DueAt", Type: "time.Time"}}, []string{"DueAt"}, "does not support inline editing"},
+			{"unsupported type of a field flag", []types.FieldDef{{Name: "DueAt", Type: "time.Time", InlineEdit: true}}, nil, "does not support inline editing"},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				input := types.ScaffoldDomainInput{
+					DomainName: "task",
+					Fields:     tt.fields,
+					InlineEdit: tt.inlineEdit,
+				}
+				result, err := scaffoldDomain(registry, input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success {
+					t.Fatal("expected failure for invalid inline_edit")
+				}
+				if !strings.Contains(result.Message, tt.wantErr) {
+					t.Errorf("expected error containing %q, got %q", tt.wantErr, result.Message)
+				}
+			})
+		}
+	})
+
+	t.Run("generates inline edit handlers and cells", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "task",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string", InlineEdit: true},
+				{Name: "Status", Type: "string", FormType: "select", Options: []string{"open", "done"}},
+				{Name: "Notes", Type: "string"},
+			},
+			InlineEdit: []string{"Status"},
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		controller := readFile(t, filepath.Join(tmpDir, "internal", "web", "task", "task.go"))
+		for _, want := range []string{
+			`r.Get("/{id}/fields/{field}/edit", c.InlineEdit)`,
+			`r.Patch("/{id}/fields/{field}", c.InlineUpdate)`,
+			`"status": true,`,
+			`case "name":`,
+			// Failed saves re-render the editor with the submitted value
+			"task.Name = v",
+		} {
+			if !strings.Contains(controller, want) {
+				t.Errorf("expected controller to contain %q", want)
+			}
+		}
+		if strings.Contains(controller, `"notes": true,`) {
+			t.Error("expected notes not to be inline editable")
+		}
+
+		partials := readFile(t, filepath.Join(tmpDir, "internal", "web", "task", "views", "partials.templ"))
+		if !strings.Contains(partials, "templ TaskInlineEditor(") {
+			t.Error("expected partials to contain TaskInlineEditor component")
+		}
+		if !strings.Contains(partials, `selected?={ item.Status == "open" }`) {
+			t.Error("expected inline editor to render select options for Status")
+		}
+		if !strings.Contains(partials, `hx-trigger="click, keyup[key=='Enter']"`) {
+			t.Error("expected inline cells to open the editor with Enter")
+		}
+
+		list := readFile(t, filepath.Join(tmpDir, "internal", "web", "task", "views", "list.templ"))
+		if !strings.Contains(list, `@TaskInlineCell(item, "status", basePath)`) {
+			t.Error("expected list view to render Status as an inline cell")
+		}
+	})
+
+	t.Run("generates optimistic locking", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "document",
+			Fields: []types.FieldDef{
+				{Name: "Title", Type: "string"},
+			},
+			WithOptimisticLocking: true,
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "models", "document.go"):                           {"Version   uint"},
+			filepath.Join("internal", "repository", "document", "document.go"):           {"ErrVersionConflict", `Where("version = ?", expected)`},
+			filepath.Join("internal", "services", "document", "document.go"):             {"ErrDocumentConflict", "errors.Is(err, documentrepo.ErrVersionConflict)"},
+			filepath.Join("internal", "services", "document", "dto.go"):                  {"Version *uint"},
+			filepath.Join("internal", "web", "document", "document.go"):                  {"http.StatusConflict", "Conflict:  true"},
+			filepath.Join("internal", "web", "document", "views", "document_form.templ"): {`name="version"`, "if props.Conflict {"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("generates observability instrumentation", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "document",
+			Fields: []types.FieldDef{
+				{Name: "Title", Type: "string"},
+			},
+			WithObservability: true,
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "internal", "services", "document", "document.go"))
+		for _, want := range []string{
+			`otel.Tracer(instrumentationName)`,
+			`attribute.String("domain", "document")`,
+			`startSpan(ctx, "Create")`,
+			`startSpan(ctx, "Delete", recordIDAttribute(id))`,
+			`"records.created"`,
+			`"records.list.duration"`,
+			"s.metrics.deleted.Add(ctx, 1, metric.WithAttributes(domainAttribute))",
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected service to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("generates moderation queue", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, `package main
+
+func main() {
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	router.Group(func(r chi.Router) {
+		// MCP:ROUTES:ADMIN:START
+		// MCP:ROUTES:ADMIN:END
+	})
+	// MCP:ROUTES:END
+}
+`)
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "review",
+			Fields: []types.FieldDef{
+				{Name: "Body", Type: "string", FormType: "textarea"},
+			},
+			WithModeration: true,
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "models", "review.go"):                        {"ModerationStatus string", `ReviewModerationPending  = "pending"`},
+			filepath.Join("internal", "repository", "review", "review.go"):          {"func WithModerationStatus(status string) QueryOption", "UpdateModerationStatus("},
+			filepath.Join("internal", "services", "review", "review.go"):            {"ModerationStatus: models.ReviewModerationPending", "func (s *service) Approve(", "func (s *service) OnModerated(hook ModerationHook)"},
+			filepath.Join("internal", "web", "review", "review.go"):                 {"ModerationStatus: models.ReviewModerationApproved", "func (c *Controller) RegisterModerationRoutes(r chi.Router)", `r.Post("/{id}/approve", c.Approve)`},
+			filepath.Join("internal", "web", "review", "views", "moderation.templ"): {"templ ReviewModerationQueue(", `fmt.Sprintf("%s/%d/reject", basePath, item.ID)`},
+			filepath.Join("cmd", "web", "main.go"):                                  {`r.Route("/admin/reviews/moderation", reviewController.RegisterModerationRoutes)`},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("validates filters", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			filter types.FilterDef
+		}{
+			{"unknown field", types.FilterDef{Field: "Missing"}},
+			{"contains on number", types.FilterDef{Field: "Price", Operator: "contains"}},
+			{"date widget on string", types.FilterDef{Field: "Name", Widget: "date"}},
+			{"select without options", types.FilterDef{Field: "Name", Widget: "select"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				registry, tmpDir := testRegistry(t)
+				setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+				input := types.ScaffoldDomainInput{
+					DomainName: "product",
+					Fields: []types.FieldDef{
+						{Name: "Name", Type: "string"},
+						{Name: "Price", Type: "float64"},
+					},
+					Filters: []types.FilterDef{tt.filter},
+				}
+				result, err := scaffoldDomain(registry, input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success {
+					t.Errorf("expected failure for %s", tt.name)
+				}
+			})
+		}
+	})
+
+	t.Run("generates list filters", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string"},
+				{Name: "Status", Type: "string", Options: []string{"draft", "active"}},
+				{Name: "Price", Type: "float64"},
+			},
+			Filters: []types.FilterDef{
+				{Field: "Name"},
+				{Field: "Status"},
+				{Field: "Price", Operator: "gte"},
+			},
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "services", "product", "dto.go"):         {"PriceGte *float64 `json:\"price_gte,omitempty\"`", "Status *string `json:\"status,omitempty\"`"},
+			filepath.Join("internal", "repository", "product", "product.go"):   {"func WithFilter(column, operator string, value interface{}) QueryOption"},
+			filepath.Join("internal", "services", "product", "product.go"):     {`repo.WithFilter("price", "gte", *filter.PriceGte)`, `repo.WithFilter("name", "contains", *filter.Name)`},
+			filepath.Join("internal", "web", "product", "product.go"):          {`r.URL.Query().Get("price_gte")`, `"status": r.URL.Query().Get("status"),`},
+			filepath.Join("internal", "web", "product", "views", "list.templ"): {`id="product-filters"`, `<option value="active" selected?={ props.Filters["status"] == "active" }>active</option>`, "BaseURL:     props.paginationURL(),"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("validates bulk actions", func(t *testing.T) {
+		tests := []struct {
+			name   string
+			action types.BulkActionDef
+		}{
+			{"reserved name", types.BulkActionDef{Name: "delete", Field: "Status", Value: "draft"}},
+			{"invalid name", types.BulkActionDef{Name: "Mark Shipped", Field: "Status", Value: "draft"}},
+			{"unknown field", types.BulkActionDef{Name: "publish", Field: "Missing", Value: "x"}},
+			{"value not an option", types.BulkActionDef{Name: "publish", Field: "Status", Value: "live"}},
+			{"invalid number", types.BulkActionDef{Name: "reprice", Field: "Price", Value: "cheap"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				registry, tmpDir := testRegistry(t)
+				setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+				input := types.ScaffoldDomainInput{
+					DomainName: "product",
+					Fields: []types.FieldDef{
+						{Name: "Status", Type: "string", Options: []string{"draft", "published"}},
+						{Name: "Price", Type: "float64"},
+					},
+					BulkActions: []types.BulkActionDef{tt.action},
+				}
+				result, err := scaffoldDomain(registry, input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success {
+					t.Errorf("expected failure for %s", tt.name)
+				}
+			})
+		}
+	})
+
+	t.Run("generates bulk actions", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string"},
+				{Name: "Status", Type: "string", Options: []string{"draft", "published"}},
+			},
+			BulkActions: []types.BulkActionDef{
+				{Name: "publish", Field: "Status", Value: "published", Confirm: "Publish the selected products?"},
+			},
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "repository", "product", "product.go"):   {"DeleteByIDs(ctx context.Context, ids []uint) (int64, error)", `Where("id IN ?", ids).Updates(values)`},
+			filepath.Join("internal", "services", "product", "product.go"):     {"BulkDelete(ctx context.Context, ids []uint) (int64, error)", `s.repo.UpdateByIDs(ctx, ids, map[string]interface{}{"status": "published"})`},
+			filepath.Join("internal", "web", "product", "product.go"):          {`r.Post("/bulk", c.Bulk)`, `case "publish":`, "n, err = c.service.BulkPublish(r.Context(), ids)", "csv.NewWriter(w)"},
+			filepath.Join("internal", "web", "product", "views", "list.templ"): {`x-data="{ selected: [] }"`, `x-model="selected"`, "`{\"action\": \"publish\"}`", `"hx-confirm": "Publish the selected products?"`, `"value": "export"`},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("validates search", func(t *testing.T) {
+		tests := []struct {
+			name         string
+			search       string
+			searchFields []string
+		}{
+			{"unknown mode", "regex", nil},
+			{"search fields without fulltext", "", []string{"Title"}},
+			{"unknown search field", "fulltext", []string{"Missing"}},
+			{"non-string search field", "fulltext", []string{"Views"}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				registry, tmpDir := testRegistry(t)
+				setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+				input := types.ScaffoldDomainInput{
+					DomainName: "article",
+					Fields: []types.FieldDef{
+						{Name: "Title", Type: "string"},
+						{Name: "Views", Type: "int"},
+					},
+					Search:       tt.search,
+					SearchFields: tt.searchFields,
+				}
+				result, err := scaffoldDomain(registry, input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success {
+					t.Errorf("expected failure for %s", tt.name)
+				}
+			})
+		}
+	})
+
+	t.Run("generates full-text search", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupWizardDatabaseGo(t, tmpDir, `package database
+
+func RunMigrations(db *gorm.DB) error {
+	if err := db.AutoMigrate(
+		// MCP:MODELS:START
+		// MCP:MODELS:END
+	); err != nil {
+		return err
+	}
+
+	// MCP:MIGRATIONS:START
+	// MCP:MIGRATIONS:END
+	return nil
+}
+`)
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "article",
+			Fields: []types.FieldDef{
+				{Name: "Title", Type: "string"},
+				{Name: "Body", Type: "string", FormType: "textarea"},
+				{Name: "Views", Type: "int"},
+			},
+			Search: "fulltext",
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "models", "article.go"):                  {"SearchSnippet string `gorm:\"->;-:migration\" json:\"-\"`", "func MigrateArticleSearch(db *gorm.DB) error", "USING fts5(title, body, content='articles', content_rowid='id')", "VALUES ('delete', old.id, old.title, old.body)", "setweight(to_tsvector('english', coalesce(body, '')), 'B')", "USING GIN (search_vector)"},
+			filepath.Join("internal", "repository", "article", "article.go"):   {"func WithFullTextSearch(search string) QueryOption", "WHERE articles_fts MATCH ?", "articles.search_vector @@ websearch_to_tsquery('english', ?)", "(articles.title LIKE @search OR articles.body LIKE @search)", "func WithRelevanceOrder(search string) QueryOption"},
+			filepath.Join("internal", "services", "article", "article.go"):     {"articlerepo.WithFullTextSearch(filter.Search)", "articlerepo.WithRelevanceOrder(filter.Search)"},
+			filepath.Join("internal", "web", "article", "views", "list.templ"): {`name="search"`, "@templ.Raw(highlightSnippet(item.SearchSnippet))"},
+			filepath.Join("internal", "database", "database.go"):               {"if err := models.MigrateArticleSearch(db); err != nil {"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+		for _, step := range result.NextSteps {
+			if strings.Contains(step, "MigrateArticleSearch") {
+				t.Errorf("expected no manual migration step once injected, got %q", step)
+			}
+		}
+	})
+
+	t.Run("appends sidebar link to menu.toml", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		menuPath := filepath.Join(tmpDir, "config", "en", "menu.toml")
+		if err := os.MkdirAll(filepath.Dir(menuPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(menuPath, []byte("[[main]]\nlabel = \"Dashboard\"\nurl = \"/dashboard\"\nicon = \"home\"\norder = 1\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, domain := range []struct{ name, routeGroup string }{
+			{"invoice", "authenticated"},
+			{"tag", "admin"},
+			{"page", "public"},
+		} {
+			result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+				DomainName: domain.name,
+				Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+				RouteGroup: domain.routeGroup,
+			})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("expected success, got failure: %s", result.Message)
+			}
+		}
+
+		content := readFile(t, menuPath)
+		for _, want := range []string{
+			"[[main]]\nlabel = \"Invoices\"\nurl = \"/invoices\"\nicon = \"folder\"\norder = 20\n",
+			"[[admin]]\nlabel = \"Tags\"\nurl = \"/tags\"\nicon = \"folder\"\norder = 10\n",
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("menu.toml should contain %q, got:\n%s", want, content)
+			}
+		}
+		if strings.Contains(content, "/pages") {
+			t.Error("public domains should not be added to the sidebar")
+		}
+	})
+
+	t.Run("generates scheduled publishing", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupWizardMainGo(t, tmpDir, `package main
+
+import (
+	// MCP:IMPORTS:START
+	// MCP:IMPORTS:END
+)
+
+func main() {
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+
+	// MCP:SCHEDULER:START
+	// MCP:SCHEDULER:END
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:END
+}
+`)
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "article",
+			Fields: []types.FieldDef{
+				{Name: "Title", Type: "string"},
+			},
+			WithScheduling: true,
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "models", "article.go"):                          {"PublishAt    *time.Time `gorm:\"index\" json:\"publish_at,omitempty\"`", "ArticleStateScheduled = \"scheduled\"", "func (m *Article) PublishStateAt(t time.Time) string"},
+			filepath.Join("internal", "repository", "article", "article.go"):           {"func WithPublishState(state string) QueryOption", "func (r *repository) UpdatePublishStates(ctx context.Context, now time.Time) (int64, error)"},
+			filepath.Join("internal", "services", "article", "article.go"):             {"ErrArticleInvalidSchedule", "func (s *service) UpdatePublishStates(ctx context.Context) error", "article.PublishState = article.PublishStateAt(time.Now())"},
+			filepath.Join("internal", "web", "article", "article.go"):                  {"PublishState: models.ArticleStatePublished", `parseScheduleTime(r.FormValue("publish_at"))`},
+			filepath.Join("internal", "web", "article", "views", "article_form.templ"): {`"x-ref": "publishAt"`, "Publish now"},
+			filepath.Join("internal", "web", "article", "views", "partials.templ"):     {"templ ArticlePublishStateBadge(state string)"},
+			filepath.Join("internal", "scheduler", "scheduler.go"):                     {"func Start(name string, task Task)"},
+			filepath.Join("cmd", "web", "main.go"):                                     {`"github.com/example/testapp/internal/scheduler"`, `scheduler.Start("articles publishing", articleService.UpdatePublishStates)`},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+		for _, step := range result.NextSteps {
+			if strings.Contains(step, "scheduler.Start") {
+				t.Errorf("expected no manual scheduler step once injected, got %q", step)
+			}
+		}
+	})
+
+	t.Run("generates repository cache", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupWizardMainGo(t, tmpDir, `package main
+
+import (
+	// MCP:IMPORTS:START
+	// MCP:IMPORTS:END
+)
+
+func main() {
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:END
+}
+`)
+
+		input := types.ScaffoldDomainInput{
+			DomainName:      "article",
+			Fields:          []types.FieldDef{{Name: "Title", Type: "string"}},
+			WithCache:       true,
+			WithBulkActions: true,
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "repository", "article", "cache.go"): {"func NewCachedRepository(db *gorm.DB, repo Repository, c cache.Cache) Repository", `const cacheKeyPrefix = "articles:"`, "func (r *cachedRepository) DeleteByIDs("},
+			filepath.Join("internal", "cache", "cache.go"):                 {"func Default() Cache", "func NewMemory() *Memory", "CACHE_TTL"},
+			filepath.Join("internal", "cache", "redis.go"):                 {"func NewRedis(url string) (*Redis, error)"},
+			filepath.Join("cmd", "web", "main.go"):                         {`"github.com/example/testapp/internal/cache"`, "articleRepo := articlerepo.NewCachedRepository(db, articlerepo.NewRepository(db), cache.Default())"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go")), "articleRepo := articlerepo.NewRepository(db)") {
+			t.Error("expected the uncached repository not to be wired")
+		}
+	})
+
+	t.Run("rejects scheduling field names", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "article",
+			Fields: []types.FieldDef{
+				{Name: "Title", Type: "string"},
+				{Name: "PublishAt", Type: "time.Time"},
+			},
+			WithScheduling: true,
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure for a PublishAt field with with_scheduling")
+		}
+	})
+
+	t.Run("generates sort whitelist", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string"},
+				{Name: "UnitPrice", Type: "float64"},
+				{Name: "Photo", Type: "string", FormType: "image"},
+			},
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		controller := readFile(t, filepath.Join(tmpDir, "internal", "web", "product", "product.go"))
+		for _, want := range []string{`"unit_price": "unit_price",`, `"created_at": "created_at",`, "sortKey, sortDir := parseSort(r)", "SortBy:   sortColumns[sortKey],"} {
+			if !strings.Contains(controller, want) {
+				t.Errorf("expected controller to contain %q", want)
+			}
+		}
+		if strings.Contains(controller, `"photo": "photo",`) {
+			t.Error("expected image field not to be sortable")
+		}
+
+		repo := readFile(t, filepath.Join(tmpDir, "internal", "repository", "product", "product.go"))
+		if !strings.Contains(repo, "clause.OrderByColumn{Column: clause.Column{Table: clause.CurrentTable, Name: column}, Desc: desc}") {
+			t.Error("expected WithOrder to quote the sort column")
+		}
+	})
+
+	t.Run("generates image uploads", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, `package main
+
+import (
+	"log"
+)
+
+func main() {
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+	// MCP:ROUTES:START
+	// MCP:ROUTES:END
+}
+`)
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string"},
+				{Name: "Photo", Type: "string", FormType: "image", Required: true},
+			},
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "storage", "storage.go"):                         {"type Storage interface", `getEnv("STORAGE_DRIVER", "disk")`},
+			filepath.Join("internal", "storage", "s3.go"):                              {"func NewS3("},
+			filepath.Join("internal", "images", "images.go"):                           {"func Save(", "func Srcset("},
+			filepath.Join("cmd", "reprocess-images", "main.go"):                        {`{Table: "products", Column: "photo"},`},
+			filepath.Join("cmd", "web", "main.go"):                                     {`router.Handle("/uploads/*", storage.Handler())`, `"github.com/example/testapp/internal/storage"`},
+			filepath.Join("internal", "web", "product", "product.go"):                  {"r.ParseMultipartForm(images.MaxUploadSize)", `photoImage, err := images.FromRequest(r, "photo")`, "Photo: photoImage,"},
+			filepath.Join("internal", "web", "product", "views", "product_form.templ"): {`hx-encoding="multipart/form-data"`, `Type:       "file",`},
+			filepath.Join("internal", "web", "product", "views", "show.templ"):         {"srcset={ images.Srcset(props.Item.Photo) }"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("validates image fields", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		tests := []struct {
+			name  string
+			input types.ScaffoldDomainInput
+		}{
+			{"non-string image", types.ScaffoldDomainInput{
+				DomainName: "product",
+				Fields:     []types.FieldDef{{Name: "Photo", Type: "int", FormType: "image"}},
+			}},
+			{"inline edited image", types.ScaffoldDomainInput{
+				DomainName: "product",
+				Fields:     []types.FieldDef{{Name: "Photo", Type: "string", FormType: "image"}},
+				InlineEdit: []string{"Photo"},
+			}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := scaffoldDomain(registry, tt.input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success {
+					t.Errorf("expected failure for %s", tt.name)
+				}
+			})
+		}
+	})
+
+	t.Run("validates polymorphic owner models", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "comment",
+			Fields: []types.FieldDef{
+				{Name: "Body", Type: "string"},
+			},
+			Relationships: []types.RelationshipDef{
+				{Type: "polymorphic", Model: "Commentable"},
+			},
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure without owner models")
+		}
+	})
+
+	t.Run("generates polymorphic relationship", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		for _, owner := range []string{"post", "video"} {
+			ownerInput := types.ScaffoldDomainInput{
+				DomainName: owner,
+				Fields: []types.FieldDef{
+					{Name: "Title", Type: "string"},
+				},
+			}
+			if result, err := scaffoldDomain(registry, ownerInput); err != nil || !result.Success {
+				t.Fatalf("failed to scaffold %s: %v %s", owner, err, result.Message)
+			}
+		}
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "comment",
+			Fields: []types.FieldDef{
+				{Name: "Body", Type: "string"},
+			},
+			Relationships: []types.RelationshipDef{
+				{Type: "polymorphic", Model: "Commentable", Models: []string{"Post", "Video"}},
+			},
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "models", "comment.go"):                {"CommentableID uint", "CommentableType string", `CommentCommentablePost = "posts"`},
+			filepath.Join("internal", "repository", "comment", "comment.go"): {"FindByCommentable(ctx context.Context, ownerType string, ownerID uint"},
+			filepath.Join("internal", "services", "comment", "dto.go"):       {`CommentableType string ` + "`" + `json:"commentable_type"` + "`"},
+			filepath.Join("internal", "models", "post.go"):                   {`Comments []Comment ` + "`" + `gorm:"polymorphic:Commentable;polymorphicValue:posts"`},
+			filepath.Join("internal", "models", "video.go"):                  {`Comments []Comment ` + "`" + `gorm:"polymorphic:Commentable;polymorphicValue:videos"`},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("generates embedded struct fields", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		address := []types.EmbeddedFieldDef{
+			{Name: "Street", Type: "string"},
+			{Name: "City", Type: "string"},
+			{Name: "Zip", Type: "string"},
+		}
+		input := types.ScaffoldDomainInput{
+			DomainName: "customer",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string"},
+				{Name: "ShippingAddress", Type: "Address", Embedded: address},
+				{Name: "BillingAddress", Type: "Address", Embedded: address},
+			},
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		model := readFile(t, filepath.Join(tmpDir, "internal", "models", "customer.go"))
+		for _, want := range []string{
+			`ShippingAddress Address ` + "`" + `gorm:"embedded;embeddedPrefix:shipping_address_"`,
+			"type Address struct",
+		} {
+			if !strings.Contains(model, want) {
+				t.Errorf("expected model to contain %q", want)
+			}
+		}
+		if strings.Count(model, "type Address struct") != 1 {
+			t.Error("expected Address to be declared once")
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "services", "customer", "dto.go"):                  {"ShippingAddress *models.Address"},
+			filepath.Join("internal", "web", "customer", "customer.go"):                  {`r.FormValue("shipping_address_street")`},
+			filepath.Join("internal", "web", "customer", "views", "customer_form.templ"): {"<fieldset", `Name:        "billing_address_city"`},
+			filepath.Join("internal", "web", "customer", "views", "list.templ"):          {"item.ShippingAddress.Zip"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+
+		// A second domain reuses the existing Address type
+		input = types.ScaffoldDomainInput{
+			DomainName: "warehouse",
+			Fields: []types.FieldDef{
+				{Name: "Location", Type: "Address", Embedded: address},
+			},
+		}
+		if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold warehouse: %v %s", err, result.Message)
+		}
+		warehouse := readFile(t, filepath.Join(tmpDir, "internal", "models", "warehouse.go"))
+		if strings.Contains(warehouse, "type Address struct") {
+			t.Error("expected warehouse to reuse the existing Address type")
+		}
+	})
+
+	t.Run("validates embedded fields", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "customer",
+			Fields: []types.FieldDef{
+				{Name: "Address", Type: "Address", Embedded: []types.EmbeddedFieldDef{
+					{Name: "MovedIn", Type: "time.Time"},
+				}},
+			},
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure for unsupported embedded field type")
+		}
+	})
+
+	t.Run("generates self-referential tree", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, mainGoWithMarkers)
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "category",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string"},
+			},
+			Relationships: []types.RelationshipDef{
+				{Type: "belongs_to", Model: "self"},
+			},
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "models", "category.go"):                      {"ParentID *uint", "Children []Category"},
+			filepath.Join("internal", "repository", "category", "category.go"):      {"FindRoots(ctx context.Context", "FindChildren(ctx context.Context, parentID uint"},
+			filepath.Join("internal", "services", "category", "category.go"):        {"ErrCategoryInvalidParent", "func (s *service) validateParent("},
+			filepath.Join("internal", "web", "category", "category.go"):             {`r.Get("/tree", c.Tree)`, `r.Get("/{id}/children", c.Children)`},
+			filepath.Join("internal", "web", "category", "views", "partials.templ"): {"templ CategoryTreeNodes("},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+
+		mainGo := readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go"))
+		if strings.Contains(mainGo, "selfService") {
+			t.Error("expected self relationship not to be wired as a related service")
+		}
+	})
+
+	t.Run("generates repository tests", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, mainGoWithMarkers)
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "category",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string", GORMTags: "uniqueIndex"},
+				{Name: "PublishedAt", Type: "*time.Time"},
+			},
+			Relationships: []types.RelationshipDef{
+				{Type: "belongs_to", Model: "self"},
+			},
+			WithOptimisticLocking: true,
+			WithTests:             true,
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		testPath := filepath.Join("internal", "repository", "category", "category_test.go")
+		content := readFile(t, filepath.Join(tmpDir, testPath))
+		for _, want := range []string{
+			`sqlite.Open(":memory:")`,
+			"db.AutoMigrate(\n\t\t&models.Category{},",
+			"func TestRepositoryCreateAndFindByID(t *testing.T)",
+			"!got.PublishedAt.Equal(*want.PublishedAt)",
+			"!errors.Is(err, ErrVersionConflict)",
+			"if !deleted.DeletedAt.Valid",
+			`Name: fmt.Sprintf("Name %d", i),`,
+			`{name: "returns a partial last page", opts: []QueryOption{WithOrder("id", false), WithPagination(3, 10)}, wantCount: 5, wantFirst: 21},`,
+			`preload: "Parent",`,
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected %s to contain %q", testPath, want)
+			}
+		}
+
+		nextSteps := strings.Join(result.NextSteps, "\n")
+		if !strings.Contains(nextSteps, "go test ./internal/repository/category/") {
+			t.Errorf("expected a next step to run the tests, got: %v", result.NextSteps)
+		}
+	})
+
+	t.Run("repository tests use the testcontainers harness of the project", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, mainGoWithMarkers)
+		setupTestUtil(t, tmpDir)
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "category",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+			WithTests:  true,
+		}
+		if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "internal", "repository", "category", "category_test.go"))
+		for _, want := range []string{`"github.com/example/testapp/internal/testutil"`, "db := testutil.DB(t,\n\t\t&models.Category{},"} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected repository tests to contain %q", want)
+			}
+		}
+		for _, unwanted := range []string{"gorm.io/driver/sqlite", "db.AutoMigrate("} {
+			if strings.Contains(content, unwanted) {
+				t.Errorf("expected repository tests not to contain %q", unwanted)
+			}
+		}
+	})
+
+	t.Run("generates no repository tests by default", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, mainGoWithMarkers)
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "category",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+		}
+		if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+		if fileExists(filepath.Join(tmpDir, "internal", "repository", "category", "category_test.go")) {
+			t.Error("expected no repository tests without with_tests")
+		}
+	})
+
+	t.Run("generates lifecycle hooks that dispatch domain events", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, mainGoWithMarkers)
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "order_item",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+			WithHooks:  []string{"before_create", "after_update"},
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+
+		model := readFile(t, filepath.Join(tmpDir, "internal", "models", "orderitem.go"))
+		for _, want := range []string{
+			"\"github.com/example/testapp/internal/events\"\n\t\"gorm.io/gorm\"",
+			"// BeforeCreate dispatches order_item.before_create before the OrderItem is created.\nfunc (m *OrderItem) BeforeCreate(tx *gorm.DB) error {\n\treturn events.Dispatch(tx, \"order_item.before_create\", m)",
+			"func (m *OrderItem) AfterUpdate(tx *gorm.DB) error {",
+		} {
+			if !strings.Contains(model, want) {
+				t.Errorf("expected the model to contain %q, got:\n%s", want, model)
+			}
+		}
+		hooks := readFile(t, filepath.Join(tmpDir, "internal", "services", "orderitem", "hooks.go"))
+		for _, want := range []string{
+			"\tevents.On(\"order_item.before_create\", onBeforeCreate)\n\tevents.On(\"order_item.after_update\", onAfterUpdate)\n}",
+			"func onAfterUpdate(tx *gorm.DB, orderItem *models.OrderItem) error {",
+		} {
+			if !strings.Contains(hooks, want) {
+				t.Errorf("expected hooks.go to contain %q, got:\n%s", want, hooks)
+			}
+		}
+		if !fileExists(filepath.Join(tmpDir, "internal", "events", "events.go")) {
+			t.Error("expected the events dispatcher to be generated")
+		}
+
+		result, _ = scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "invoice",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+			WithHooks:  []string{"before_validate"},
+		})
+		if result.Success || !strings.Contains(result.Message, "with_hooks: invalid hook 'before_validate'") {
+			t.Errorf("expected an invalid hook error, got: %s", result.Message)
+		}
+	})
+
+	t.Run("scopes authenticated domains to the session user", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, mainGoWithMarkers)
+
+		// Without auth there is no session user, so nothing is owned
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "note",
+			Fields:     []types.FieldDef{{Name: "Title", Type: "string"}},
+			RouteGroup: "authenticated",
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+		if model := readFile(t, filepath.Join(tmpDir, "internal", "models", "note.go")); strings.Contains(model, "UserID") {
+			t.Errorf("expected no owner without auth, got:\n%s", model)
+		}
+
+		authPath := filepath.Join(tmpDir, "internal", "web", "middleware", "auth.go")
+		if err := os.MkdirAll(filepath.Dir(authPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(authPath, []byte("package middleware\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err = scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName:      "task",
+			Fields:          []types.FieldDef{{Name: "Title", Type: "string"}},
+			RouteGroup:      "authenticated",
+			WithBulkActions: true,
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+		files := map[string][]string{
+			filepath.Join("internal", "models", "task.go"): {
+				"UserID    uint           `gorm:\"not null;index\" json:\"user_id\"`",
+			},
+			filepath.Join("internal", "repository", "task", "task.go"): {
+				"FindOwnedByID(ctx context.Context, id, userID uint) (*models.Task, error)",
+				"Where(\"user_id = ?\", userID)",
+			},
+			filepath.Join("internal", "web", "task", "task.go"): {
+				"owned := r.With(c.requireOwner)",
+				"owned.Get(\"/{id}\", c.Show)",
+				"owned.Delete(\"/{id}\", c.Delete)",
+				"UserID:   currentUserID(r),",
+				"c.service.OwnedIDs(r.Context(), ids, currentUserID(r))",
+				"func (c *Controller) requireOwner(next http.Handler) http.Handler {",
+			},
+		}
+		for path, wants := range files {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+
+		result, _ = scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName:    "comment",
+			Fields:        []types.FieldDef{{Name: "Body", Type: "string"}},
+			RouteGroup:    "authenticated",
+			Relationships: []types.RelationshipDef{{Type: "belongs_to", Model: "User"}},
+		})
+		if result.Success || !strings.Contains(result.Message, "set owned_by_user: false") {
+			t.Errorf("expected a belongs_to User conflict, got: %s", result.Message)
+		}
+		owned := true
+		result, _ = scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName:  "page",
+			Fields:      []types.FieldDef{{Name: "Title", Type: "string"}},
+			OwnedByUser: &owned,
+		})
+		if result.Success || !strings.Contains(result.Message, "owned_by_user requires route_group authenticated") {
+			t.Errorf("expected a route group error, got: %s", result.Message)
+		}
+	})
+
+	t.Run("adds the controller to the routes registry", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		project, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+			ProjectName:  "app",
+			ModulePath:   "github.com/test/app",
+			DatabaseType: "sqlite",
+			InCurrentDir: true,
+		})
+		if err != nil || !project.Success {
+			t.Fatalf("scaffoldProject failed: %v %s", err, project.Message)
+		}
+
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "order_item",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+			RouteGroup: "admin",
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+		routesGo := readFile(t, filepath.Join(tmpDir, "internal", "web", "orderitem", "routes.go"))
+		for _, want := range []string{"return routes.Admin", `return "/order-items"`} {
+			if !strings.Contains(routesGo, want) {
+				t.Errorf("expected routes.go to contain %q, got:\n%s", want, routesGo)
+			}
+		}
+		main := readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go"))
+		if !strings.Contains(main, "domainRoutes.Add(orderItemController)\n\t// MCP:DOMAIN_ROUTES:END") {
+			t.Errorf("expected the controller added to the routes registry, got:\n%s", main)
+		}
+		if strings.Contains(main, "orderItemController.RegisterRoutes") {
+			t.Errorf("expected no route injected into main.go, got:\n%s", main)
+		}
+
+		routes, err := collectRoutes(registry)
+		if err != nil || len(routes) == 0 || !strings.HasPrefix(routes[0].Path, "/order-items") || routes[0].RouteGroup != "admin" {
+			t.Errorf("expected the routes mounted at /order-items in the admin group, got %+v (%v)", routes, err)
+		}
+		analysis, err := ExecuteAnalyzeDomain(context.Background(), registry, types.AnalyzeDomainInput{Domain: "order_item"})
+		if err != nil || !analysis.Success || analysis.Domains[0].HasChanges {
+			t.Errorf("expected analyze_domain to find no changes, got %+v (%v)", analysis, err)
+		}
+	})
+
+	t.Run("adds provider sets in projects with di wire", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		project, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+			ProjectName:  "app",
+			ModulePath:   "github.com/test/app",
+			DatabaseType: "sqlite",
+			DI:           "wire",
+			InCurrentDir: true,
+		})
+		if err != nil || !project.Success {
+			t.Fatalf("scaffoldProject failed: %v %s", err, project.Message)
+		}
+
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+		files := map[string][]string{
+			filepath.Join("internal", "web", "product", "providers.go"): {
+				"var ProviderSet = wire.NewSet(productrepo.NewRepository, productsvc.NewService, NewController)",
+			},
+			filepath.Join("cmd", "web", "wire.go"): {
+				`productctrl "github.com/test/app/internal/web/product"`,
+				"\t\tproductctrl.ProviderSet,\n\t\t// MCP:PROVIDERS:END",
+			},
+			filepath.Join("cmd", "web", "domains.go"): {
+				"\tProductController *productctrl.Controller\n\tProductService    productsvc.Service\n",
+			},
+			filepath.Join("cmd", "web", "main.go"): {
+				"productController := app.ProductController",
+			},
+		}
+		for path, wants := range files {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q, got:\n%s", path, want, content)
+				}
+			}
+		}
+		if main := readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go")); strings.Contains(main, "productsvc.NewService") {
+			t.Errorf("expected no constructor calls in main.go, got:\n%s", main)
+		}
+
+		wiring, _ := updateDIWiring(registry, types.UpdateDIWiringInput{Domains: []string{"product"}})
+		if wiring.Success || !strings.Contains(wiring.Message, "run 'task wire' instead") {
+			t.Errorf("expected update_di_wiring to refuse wire projects, got: %s", wiring.Message)
+		}
+	})
+
+	t.Run("generates many_to_many join model", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, mainGoWithMarkers)
+		setupWizardDatabaseGo(t, tmpDir, wizardDatabaseGoWithMarkers)
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "project",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string"},
+			},
+			Relationships: []types.RelationshipDef{
+				{Type: "many_to_many", Model: "Team", JoinFields: []types.FieldDef{
+					{Name: "Role", Type: "string"},
+					{Name: "Position", Type: "int"},
+				}},
+			},
+		}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "models", "project.go"):                  {`Teams []Team `, `gorm:"many2many:projects_teams"`, "type ProjectTeam struct", "TeamID uint `gorm:\"primaryKey\"", "Position int", `return "projects_teams"`},
+			filepath.Join("internal", "repository", "project", "project.go"):   {"FindProjectTeams(ctx context.Context, projectID uint)", "clause.OnConflict{UpdateAll: true}", "DetachTeam(ctx context.Context, projectID, teamID uint)"},
+			filepath.Join("internal", "services", "project", "project.go"):     {"AttachTeam(ctx context.Context, id uint, input AttachTeamInput) (*models.ProjectTeam, error)", "ErrProjectInvalidTeam"},
+			filepath.Join("internal", "services", "project", "dto.go"):         {"type AttachTeamInput struct"},
+			filepath.Join("internal", "web", "project", "project.go"):          {`r.Post("/{id}/teams", c.AttachTeam)`, `r.Delete("/{id}/teams/{teamID}", c.DetachTeam)`},
+			filepath.Join("internal", "web", "project", "views", "show.templ"): {"templ ProjectTeamsSection(", "@ProjectTeamsSection(props.Item.ID, props.Teams, props.TeamOptions, props.getBasePath())"},
+			filepath.Join("internal", "database", "database.go"):               {"&models.ProjectTeam{},"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("validates join fields", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		tests := []struct {
+			name string
+			rel  types.RelationshipDef
+		}{
+			{"not many_to_many", types.RelationshipDef{Type: "has_many", Model: "Team", JoinFields: []types.FieldDef{{Name: "Role", Type: "string"}}}},
+			{"unsupported type", types.RelationshipDef{Type: "many_to_many", Model: "Team", JoinFields: []types.FieldDef{{Name: "Since", Type: "time.Time"}}}},
+			{"self reference", types.RelationshipDef{Type: "many_to_many", Model: "Project", JoinFields: []types.FieldDef{{Name: "Role", Type: "string"}}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+					DomainName:    "project",
+					Fields:        []types.FieldDef{{Name: "Name", Type: "string"}},
+					Relationships: []types.RelationshipDef{tt.rel},
+				})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success {
+					t.Error("expected failure")
+				}
+			})
+		}
+	})
+
+	t.Run("generates nested has_many form rows", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, mainGoWithMarkers)
+		setupWizardDatabaseGo(t, tmpDir, wizardDatabaseGoWithMarkers)
+
+		child := types.ScaffoldDomainInput{
+			DomainName: "order_item",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string"},
+				{Name: "Quantity", Type: "int"},
+				{Name: "ShippedAt", Type: "*time.Time"},
+			},
+			Relationships: []types.RelationshipDef{{Type: "belongs_to", Model: "Order"}},
+		}
+		if result, err := scaffoldDomain(registry, child); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold child domain: %v %s", err, result.Message)
+		}
+
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName:    "order",
+			Fields:        []types.FieldDef{{Name: "Name", Type: "string"}},
+			Relationships: []types.RelationshipDef{{Type: "has_many", Model: "OrderItem", NestedForm: true}},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "repository", "order", "order.go"):           {"UpdateWithNested(ctx context.Context, order *models.Order) error", "func (r *repository) replaceOrderItems(", `Select("name", "quantity")`},
+			filepath.Join("internal", "services", "order", "order.go"):             {"s.repo.UpdateWithNested(ctx, order)", "func toOrderItem(item OrderItemInput) models.OrderItem"},
+			filepath.Join("internal", "services", "order", "dto.go"):               {"type OrderItemInput struct", "OrderItems []OrderItemInput `json:\"orderitems,omitempty\"`", "OrderItems *[]OrderItemInput"},
+			filepath.Join("internal", "web", "order", "order.go"):                  {"func parseOrderItems(r *http.Request) []ordersvc.OrderItemInput", `c.service.GetByIDWithRelations(r.Context(), uint(id), "OrderItems")`},
+			filepath.Join("internal", "web", "order", "views", "order_form.templ"): {"templ OrderOrderItemRow(row string, item *models.OrderItem)", `@OrderOrderItemRow("__row__", nil)`, `<input type="hidden" name="orderitems" value=""/>`},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+
+		// Time fields are not edited in rows
+		if dto := readFile(t, filepath.Join(tmpDir, "internal", "services", "order", "dto.go")); strings.Contains(dto, "ShippedAt") {
+			t.Error("expected ShippedAt to be left out of the nested rows")
+		}
+		// The child already declares its belongs_to, so it is not injected again
+		if model := readFile(t, filepath.Join(tmpDir, "internal", "models", "orderitem.go")); strings.Count(model, "OrderID uint") != 1 {
+			t.Errorf("expected a single OrderID field on OrderItem, got:\n%s", model)
+		}
+	})
+
+	t.Run("validates nested forms", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		if result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "note",
+			Fields:     []types.FieldDef{{Name: "Body", Type: "string"}},
+		}); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold note domain: %v %s", err, result.Message)
+		}
+
+		tests := []struct {
+			name string
+			rel  types.RelationshipDef
+		}{
+			{"not has_many", types.RelationshipDef{Type: "has_one", Model: "Note", NestedForm: true}},
+			{"child not scaffolded", types.RelationshipDef{Type: "has_many", Model: "LineItem", NestedForm: true}},
+			{"child without belongs_to", types.RelationshipDef{Type: "has_many", Model: "Note", NestedForm: true}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+					DomainName:    "order",
+					Fields:        []types.FieldDef{{Name: "Name", Type: "string"}},
+					Relationships: []types.RelationshipDef{tt.rel},
+				})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success {
+					t.Error("expected failure")
+				}
+			})
+		}
+	})
+
 	t.Run("requires go.mod", func(t *testing.T) {
 		registry, _ := testRegistry(t)
 
@@ -306,3 +1771,675 @@ func TestScaffoldDomain(t *testing.T) {
 		}
 	})
 }
+
+func TestScaffoldDomainAPIVersioning(t *testing.T) {
+	const apiMainGo = `package main
+
+import (
+	"log"
+	// MCP:IMPORTS:START
+	// MCP:IMPORTS:END
+)
+
+func main() {
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	// MCP:ROUTES:END
+	log.Println("ready")
+}
+`
+	setupAPIProject := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, apiMainGo)
+		if err := metadata.NewStore(tmpDir).SaveProject(types.ScaffoldProjectInput{ProjectName: "testapp", ProjectType: "api"}, ScaffolderVersion); err != nil {
+			t.Fatalf("failed to save project metadata: %v", err)
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("validates route groups", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		tests := []struct {
+			name  string
+			input types.ScaffoldDomainInput
+		}{
+			{"unknown route group", types.ScaffoldDomainInput{RouteGroup: "api-v1"}},
+			{"versioned group in a web project", types.ScaffoldDomainInput{RouteGroup: "api_v1"}},
+			{"versioned DTO without versioned group", types.ScaffoldDomainInput{WithVersionedDTO: true}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				tt.input.DomainName = "product"
+				tt.input.Fields = []types.FieldDef{{Name: "Name", Type: "string"}}
+				result, err := scaffoldDomain(registry, tt.input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success {
+					t.Error("expected failure")
+				}
+			})
+		}
+	})
+
+	t.Run("mounts domains in versioned sub-routers", func(t *testing.T) {
+		registry, tmpDir := setupAPIProject(t)
+
+		for _, input := range []types.ScaffoldDomainInput{
+			{DomainName: "product", RouteGroup: "api_v1", WithVersionedDTO: true, Fields: []types.FieldDef{{Name: "Name", Type: "string"}, {Name: "ReleasedAt", Type: "time.Time"}}},
+			{DomainName: "order", RouteGroup: "api_v1", Fields: []types.FieldDef{{Name: "Total", Type: "float64"}}},
+			{DomainName: "invoice", RouteGroup: "api_v2", Fields: []types.FieldDef{{Name: "Number", Type: "string"}}},
+		} {
+			result, err := scaffoldDomain(registry, input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !result.Success {
+				t.Fatalf("expected success for %s, got failure: %s", input.DomainName, result.Message)
+			}
+		}
+
+		mainGo := readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go"))
+		for _, want := range []string{
+			`"github.com/go-chi/chi/v5"`,
+			`"github.com/example/testapp/internal/web/middleware"`,
+			`router.Route("/api/v1", func(r chi.Router) {`,
+			`r.Use(middleware.APIVersion("v1"))`,
+			`r.Route("/orders", orderController.RegisterRoutes)`,
+			`router.Route("/api/v2", func(r chi.Router) {`,
+			`r.Route("/invoices", invoiceController.RegisterRoutes)`,
+		} {
+			if !strings.Contains(mainGo, want) {
+				t.Errorf("expected main.go to contain %q, got:\n%s", want, mainGo)
+			}
+		}
+
+		versioning := readFile(t, filepath.Join(tmpDir, "internal", "web", "middleware", "versioning.go"))
+		for _, want := range []string{"var DeprecatedVersions = map[string]Deprecation{}", "func APIVersion(version string) func(http.Handler) http.Handler", `rel="successor-version"`} {
+			if !strings.Contains(versioning, want) {
+				t.Errorf("expected versioning.go to contain %q", want)
+			}
+		}
+
+		dto := readFile(t, filepath.Join(tmpDir, "internal", "web", "product", "dto_v1.go"))
+		for _, want := range []string{"type V1ProductResponse struct {", "func toV1ProductResponse(product *models.Product) *V1ProductResponse {", `"time"`} {
+			if !strings.Contains(dto, want) {
+				t.Errorf("expected dto_v1.go to contain %q", want)
+			}
+		}
+		controller := readFile(t, filepath.Join(tmpDir, "internal", "web", "product", "product.go"))
+		for _, want := range []string{"Items      []*V1ProductResponse", "toV1ProductResponse(product)", `"/api/v1/products/"`} {
+			if !strings.Contains(controller, want) {
+				t.Errorf("expected controller to contain %q", want)
+			}
+		}
+		if fileExists(filepath.Join(tmpDir, "internal", "web", "order", "dto_v1.go")) {
+			t.Error("expected no versioned DTO without with_versioned_dto")
+		}
+
+		routes, err := collectRoutes(registry)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		found := false
+		for _, route := range routes {
+			if route.Domain == "invoice" && route.Path == "/api/v2/invoices/{id}" && route.Method == "GET" {
+				found = true
+				if route.RouteGroup != "api_v2" {
+					t.Errorf("expected route group api_v2, got %q", route.RouteGroup)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("expected GET /api/v2/invoices/{id} in the route table, got %+v", routes)
+		}
+	})
+}
+
+func TestScaffoldDomainStrict(t *testing.T) {
+	// main.go without the MCP markers, so the DI wiring cannot be injected
+	const mainGo = "package main\n\nfunc main() {\n\trouter := web.NewRouter(cfg)\n\t_ = router\n}\n"
+	input := types.ScaffoldDomainInput{
+		DomainName: "product",
+		Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+	}
+
+	t.Run("warnings do not fail by default", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, mainGo)
+
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+		if !fileExists(filepath.Join(tmpDir, "internal", "models", "product.go")) {
+			t.Error("expected the model to be generated")
+		}
+	})
+
+	t.Run("a failed injection leaves no partial edit", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, mainGoWithMarkers)
+		// database.go without the models markers fails the wiring after main.go is saved
+		const databaseGo = "package database\n"
+		databasePath := filepath.Join(tmpDir, "internal", "database", "database.go")
+		if err := os.MkdirAll(filepath.Dir(databasePath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(databasePath, []byte(databaseGo), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+		if readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go")) != mainGoWithMarkers {
+			t.Error("expected main.go to be restored after the wiring failed")
+		}
+		for _, path := range result.FilesUpdated {
+			if path == "cmd/web/main.go" {
+				t.Error("expected main.go not to be reported as updated")
+			}
+		}
+	})
+
+	t.Run("strict input fails and rolls back", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, mainGo)
+
+		strict := input
+		strict.Strict = true
+		result, err := scaffoldDomain(registry, strict)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Fatal("expected strict mode to fail")
+		}
+		if !strings.Contains(result.Message, "could not inject DI wiring") || !strings.Contains(result.Message, "rolled back") {
+			t.Errorf("unexpected message: %s", result.Message)
+		}
+
+		for _, path := range []string{
+			filepath.Join("internal", "models", "product.go"),
+			filepath.Join("internal", "repository", "product"),
+			filepath.Join("internal", "web", "product"),
+			filepath.Join(".mcp", "scaffold-metadata.json"),
+		} {
+			if fileExists(filepath.Join(tmpDir, path)) {
+				t.Errorf("expected %s to be rolled back", path)
+			}
+		}
+		if readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go")) != mainGo {
+			t.Error("expected main.go to be restored")
+		}
+	})
+
+	t.Run("server policy", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		registry.Strict = true
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, mainGo)
+
+		if result, _ := scaffoldDomain(registry, input); result.Success {
+			t.Error("expected registry.Strict to fail the scaffold")
+		}
+	})
+
+	t.Run("inverse relationship without markers", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		userGo := "package models\n\ntype User struct {\n\tName string\n}\n"
+		modelsDir := filepath.Join(tmpDir, "internal", "models")
+		if err := os.MkdirAll(modelsDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(modelsDir, "user.go"), []byte(userGo), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		order := types.ScaffoldDomainInput{
+			DomainName:    "order",
+			Fields:        []types.FieldDef{{Name: "Total", Type: "float64"}},
+			Relationships: []types.RelationshipDef{{Type: "belongs_to", Model: "User"}},
+			Strict:        true,
+		}
+		result, err := scaffoldDomain(registry, order)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || !strings.Contains(result.Message, "has no MCP:RELATIONSHIPS:START marker") {
+			t.Errorf("expected the missing relationship markers to fail, got: %s", result.Message)
+		}
+		if fileExists(filepath.Join(modelsDir, "order.go")) {
+			t.Error("expected order.go to be rolled back")
+		}
+		if readFile(t, filepath.Join(modelsDir, "user.go")) != userGo {
+			t.Error("expected user.go to be unchanged")
+		}
+	})
+}
+
+func TestScaffoldDomainAtomic(t *testing.T) {
+	registry, tmpDir := testRegistry(t)
+	setupGoMod(t, tmpDir, "github.com/example/testapp")
+	// An existing controller conflicts with the one the domain generates
+	controllerPath := filepath.Join(tmpDir, "internal", "web", "product", "product.go")
+	writeTestFile(t, controllerPath, "package product\n")
+
+	result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+		DomainName: "product",
+		Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if result.Success || len(result.Conflicts) != 1 {
+		t.Fatalf("expected a conflict, got: %+v", result)
+	}
+	for _, path := range []string{
+		filepath.Join("internal", "models", "product.go"),
+		filepath.Join("internal", "repository", "product", "product.go"),
+		filepath.Join("internal", "services", "product", "product.go"),
+	} {
+		if fileExists(filepath.Join(tmpDir, path)) {
+			t.Errorf("a conflicting scaffold should write nothing, found %s", path)
+		}
+	}
+	if content := readFile(t, controllerPath); content != "package product\n" {
+		t.Errorf("the conflicting file changed: %q", content)
+	}
+}
+
+func TestScaffoldDomainProjectConfig(t *testing.T) {
+	input := types.ScaffoldDomainInput{
+		DomainName: "product",
+		Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+	}
+	const configFile = "[defaults]\nroute_group = \"admin\" # every domain is admin only\nform_style = 'page'\n\n[templates]\ndir = \".gomcp/templates\"\n"
+
+	t.Run("defaults and template overrides", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		writeTestFile(t, filepath.Join(tmpDir, ".gomcp.toml"), configFile)
+		writeTestFile(t, filepath.Join(tmpDir, ".gomcp", "templates", "domain", "model.go.tmpl"), "package models\n\n// [[.ModelName]] is customized.\ntype [[.ModelName]] struct{}\n")
+
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		meta, _, err := metadata.NewStore(tmpDir).GetDomain("product")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if meta.Input.RouteGroup != "admin" || meta.Input.FormStyle != "page" || meta.Input.Layout != "" {
+			t.Errorf("expected the defaults of .gomcp.toml, got route_group %q, form_style %q, layout %q", meta.Input.RouteGroup, meta.Input.FormStyle, meta.Input.Layout)
+		}
+		if model := readFile(t, filepath.Join(tmpDir, "internal", "models", "product.go")); !strings.Contains(model, "// Product is customized.") {
+			t.Errorf("expected the overridden model template, got:\n%s", model)
+		}
+		if repo := readFile(t, filepath.Join(tmpDir, "internal", "repository", "product", "product.go")); !strings.Contains(repo, "package product") {
+			t.Errorf("expected the built-in repository template, got:\n%s", repo)
+		}
+	})
+
+	t.Run("input overrides the defaults", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		writeTestFile(t, filepath.Join(tmpDir, ".gomcp.toml"), configFile)
+
+		public := input
+		public.RouteGroup = "public"
+		if result, _ := scaffoldDomain(registry, public); !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+		meta, _, _ := metadata.NewStore(tmpDir).GetDomain("product")
+		if meta.Input.RouteGroup != "public" {
+			t.Errorf("expected route_group public, got %q", meta.Input.RouteGroup)
+		}
+	})
+
+	t.Run("invalid config", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		writeTestFile(t, filepath.Join(tmpDir, ".gomcp.toml"), "[defaults]\nlayout = \"sidebar\"\n")
+
+		result, _ := scaffoldDomain(registry, input)
+		if result.Success || !strings.Contains(result.Message, ".gomcp.toml: defaults.layout: invalid layout 'sidebar'") {
+			t.Errorf("expected the config error, got: %+v", result)
+		}
+	})
+}
+
+func TestScaffoldDomainSEO(t *testing.T) {
+	registry, tmpDir := testRegistry(t)
+	project, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+		ProjectName:  "app",
+		ModulePath:   "github.com/test/app",
+		DatabaseType: "sqlite",
+		WithAuth:     true,
+		InCurrentDir: true,
+	})
+	if err != nil || !project.Success {
+		t.Fatalf("scaffoldProject failed: %v %s", err, project.Message)
+	}
+
+	for path, wants := range map[string][]string{
+		"internal/web/seo/seo.go":              {"func Defaults(page string) func(http.Handler) http.Handler", "func Props(ctx context.Context, title string) components.MetaProps"},
+		"config/en/seo.toml":                   {`site_name = "app"`},
+		"internal/web/components/common.templ": {"templ Meta(props MetaProps)", `<meta property="og:image" content={ props.Image }/>`},
+		"internal/web/layouts/base.templ":      {"@components.Meta(seo.Props(ctx, title))"},
+		"internal/web/auth/views/layout.templ": {"@components.Meta(seo.Props(ctx, title))"},
+		"internal/web/router.go":               {"r.Use(seo.Middleware)"},
+		"cmd/web/main.go":                      {"seo.Init()"},
+	} {
+		content := readFile(t, filepath.Join(tmpDir, path))
+		for _, want := range wants {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s should contain %q", path, want)
+			}
+		}
+	}
+
+	t.Run("domains apply the page defaults and title the show page", func(t *testing.T) {
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string"},
+				{Name: "Description", Type: "string", FormType: "textarea"},
+			},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("scaffoldDomain failed: %v %s", err, result.Message)
+		}
+
+		controller := readFile(t, filepath.Join(tmpDir, "internal", "web", "product", "product.go"))
+		for _, want := range []string{`r.Use(seo.Defaults("products"))`, "Title: product.Name,", "Description: product.Description,"} {
+			if !strings.Contains(controller, want) {
+				t.Errorf("controller should contain %q", want)
+			}
+		}
+		pageFile := readFile(t, filepath.Join(tmpDir, "config", "en", "pages", "products.toml"))
+		if !strings.Contains(pageFile, `title = "Products"`) || strings.Contains(pageFile, "noindex") {
+			t.Errorf("unexpected page file:\n%s", pageFile)
+		}
+	})
+
+	t.Run("signed-in domains stay out of search results", func(t *testing.T) {
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "note",
+			RouteGroup: "authenticated",
+			Fields:     []types.FieldDef{{Name: "Body", Type: "string"}},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("scaffoldDomain failed: %v %s", err, result.Message)
+		}
+
+		if !strings.Contains(readFile(t, filepath.Join(tmpDir, "config", "en", "pages", "notes.toml")), "noindex = true") {
+			t.Error("expected noindex in the page file of an authenticated domain")
+		}
+		controller := readFile(t, filepath.Join(tmpDir, "internal", "web", "note", "note.go"))
+		if !strings.Contains(controller, `Title: "Note #" + strconv.FormatUint(uint64(note.ID), 10),`) {
+			t.Errorf("expected the ID title of a model without a title field, got:\n%s", controller)
+		}
+	})
+
+	t.Run("page presets and settings", func(t *testing.T) {
+		if result, _ := scaffoldPage(registry, types.ScaffoldPageInput{Preset: "about"}); !result.Success {
+			t.Fatalf("scaffoldPage failed: %s", result.Message)
+		}
+		if result, _ := scaffoldSettings(registry, types.ScaffoldSettingsInput{}); !result.Success {
+			t.Fatalf("scaffoldSettings failed: %s", result.Message)
+		}
+
+		mainGo := readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go"))
+		for _, want := range []string{`router.With(seo.Defaults("about")).Get("/about", pages.About)`, "seo.SiteName = settings.SiteName"} {
+			if !strings.Contains(mainGo, want) {
+				t.Errorf("main.go should contain %q", want)
+			}
+		}
+	})
+}
+
+func TestScaffoldDomainValidation(t *testing.T) {
+	registry, tmpDir := testRegistry(t)
+	project, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+		ProjectName:  "app",
+		ModulePath:   "github.com/test/app",
+		DatabaseType: "sqlite",
+		InCurrentDir: true,
+	})
+	if err != nil || !project.Success {
+		t.Fatalf("scaffoldProject failed: %v %s", err, project.Message)
+	}
+	bound := func(v float64) *float64 { return &v }
+
+	t.Run("checks the rules in the service and the browser", func(t *testing.T) {
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "ticket",
+			Fields: []types.FieldDef{
+				{Name: "Code", Type: "string", Required: true, Min: bound(3), Max: bound(12), Pattern: "[A-Z]+-[0-9]+"},
+				{Name: "Priority", Type: "int", Min: bound(1), Max: bound(5)},
+			},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("scaffoldDomain failed: %v %s", err, result.Message)
+		}
+
+		for path, wants := range map[string][]string{
+			"internal/services/ticket/ticket.go": {
+				"type ValidationError map[string]string",
+				`var codePattern = regexp.MustCompile("^(?:[A-Z]+-[0-9]+)$")`,
+				`invalid.checkText("code", "Code", ticket.Code, true, 3, 12, codePattern)`,
+				"if ticket.Priority > 5 {",
+				"if err := validateTicket(ticket); err != nil {",
+			},
+			"internal/web/ticket/ticket.go": {"func formErrors(err error) map[string]string", "Errors:    formErrors(err),"},
+			"internal/web/ticket/views/ticket_form.templ": {
+				`{ components.FieldValidation(props.Errors["code"])... }`,
+				`Attributes:  templ.Attributes{"minlength": "3", "maxlength": "12", "pattern": "[A-Z]+-[0-9]+"},`,
+				`Attributes:  templ.Attributes{"min": "1", "max": "5"},`,
+				`@components.FieldError(props.Errors["priority"])`,
+			},
+			"internal/web/components/common.templ": {"func FieldValidation(err string) templ.Attributes", "templ FieldError(message string)"},
+		} {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("%s should contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("domains without rules keep the form error", func(t *testing.T) {
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "note",
+			Fields:     []types.FieldDef{{Name: "Body", Type: "string"}},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("scaffoldDomain failed: %v %s", err, result.Message)
+		}
+		if service := readFile(t, filepath.Join(tmpDir, "internal", "services", "note", "note.go")); strings.Contains(service, "ValidationError") {
+			t.Error("expected no ValidationError in a service without rules")
+		}
+	})
+
+	t.Run("rejects invalid rules", func(t *testing.T) {
+		for _, field := range []types.FieldDef{
+			{Name: "Code", Type: "string", Pattern: "[A-Z"},
+			{Name: "Code", Type: "string", Min: bound(1.5)},
+			{Name: "Count", Type: "int", Pattern: "[0-9]+"},
+			{Name: "Count", Type: "int", Min: bound(5), Max: bound(1)},
+			{Name: "Due", Type: "time.Time", Min: bound(1)},
+		} {
+			result, _ := scaffoldDomain(registry, types.ScaffoldDomainInput{DomainName: "invalid", Fields: []types.FieldDef{field}, DryRun: true})
+			if result.Success {
+				t.Errorf("expected %+v to be rejected", field)
+			}
+		}
+	})
+}
+
+func TestScaffoldDomainBreadcrumbs(t *testing.T) {
+	registry, tmpDir := testRegistry(t)
+	project, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+		ProjectName:  "app",
+		ModulePath:   "github.com/test/app",
+		DatabaseType: "sqlite",
+		WithAuth:     true,
+		InCurrentDir: true,
+	})
+	if err != nil || !project.Success {
+		t.Fatalf("scaffoldProject failed: %v %s", err, project.Message)
+	}
+
+	for path, wants := range map[string][]string{
+		"internal/web/breadcrumbs/breadcrumbs.go": {"func Section(page, fallback string) func(http.Handler) http.Handler", "func Trail(ctx context.Context) []components.BreadcrumbItem"},
+		"internal/web/layouts/base.templ":         {"@trail()", "breadcrumbs.Trail(ctx)"},
+		"internal/web/router.go":                  {"r.Use(breadcrumbs.Middleware)"},
+	} {
+		content := readFile(t, filepath.Join(tmpDir, path))
+		for _, want := range wants {
+			if !strings.Contains(content, want) {
+				t.Errorf("%s should contain %q", path, want)
+			}
+		}
+	}
+
+	t.Run("domains start a section and label the record", func(t *testing.T) {
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("scaffoldDomain failed: %v %s", err, result.Message)
+		}
+
+		controller := readFile(t, filepath.Join(tmpDir, "internal", "web", "product", "product.go"))
+		if !strings.Contains(controller, `r.Use(breadcrumbs.Section("products", "Products"))`) {
+			t.Error("controller should start the breadcrumbs section")
+		}
+		// Show and Edit
+		if got := strings.Count(controller, "breadcrumbs.Set(r, product.Name)"); got != 2 {
+			t.Errorf("expected the record label in Show and Edit, got %d", got)
+		}
+		pageFile := readFile(t, filepath.Join(tmpDir, "config", "en", "pages", "products.toml"))
+		for _, want := range []string{"[meta]", "[breadcrumbs]", `edit = "Edit"`} {
+			if !strings.Contains(pageFile, want) {
+				t.Errorf("page file should contain %q, got:\n%s", want, pageFile)
+			}
+		}
+	})
+
+	t.Run("records without a title field keep their ID", func(t *testing.T) {
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "reading",
+			Fields:     []types.FieldDef{{Name: "Value", Type: "float64"}},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("scaffoldDomain failed: %v %s", err, result.Message)
+		}
+
+		controller := readFile(t, filepath.Join(tmpDir, "internal", "web", "reading", "reading.go"))
+		if !strings.Contains(controller, "breadcrumbs.Section") || strings.Contains(controller, "breadcrumbs.Set") {
+			t.Errorf("expected a section without record labels, got:\n%s", controller)
+		}
+	})
+}
+
+func TestScaffoldDomainIcon(t *testing.T) {
+	registry, tmpDir := testRegistry(t)
+	project, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+		ProjectName:  "app",
+		ModulePath:   "github.com/test/app",
+		DatabaseType: "sqlite",
+		WithAuth:     true,
+		InCurrentDir: true,
+	})
+	if err != nil || !project.Success {
+		t.Fatalf("scaffoldProject failed: %v %s", err, project.Message)
+	}
+
+	t.Run("icon names the menu link and the empty state", func(t *testing.T) {
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "order",
+			Fields:     []types.FieldDef{{Name: "Reference", Type: "string"}},
+			RouteGroup: "authenticated",
+			Icon:       "shopping-cart",
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("scaffoldDomain failed: %v %s", err, result.Message)
+		}
+
+		menu := readFile(t, filepath.Join(tmpDir, "config", "en", "menu.toml"))
+		if !strings.Contains(menu, "url = \"/orders\"\nicon = \"shopping-cart\"") {
+			t.Errorf("menu.toml should link the domain with its icon, got:\n%s", menu)
+		}
+		list := readFile(t, filepath.Join(tmpDir, "internal", "web", "order", "views", "list.templ"))
+		if !strings.Contains(list, `@components.Icon("shopping-cart", "h-12 w-12")`) {
+			t.Error("the empty state should use the domain icon")
+		}
+	})
+
+	t.Run("defaults to folder and inbox", func(t *testing.T) {
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "note",
+			Fields:     []types.FieldDef{{Name: "Body", Type: "string"}},
+			RouteGroup: "authenticated",
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("scaffoldDomain failed: %v %s", err, result.Message)
+		}
+
+		menu := readFile(t, filepath.Join(tmpDir, "config", "en", "menu.toml"))
+		if !strings.Contains(menu, "url = \"/notes\"\nicon = \"folder\"") {
+			t.Errorf("menu.toml should link the domain with the folder icon, got:\n%s", menu)
+		}
+		list := readFile(t, filepath.Join(tmpDir, "internal", "web", "note", "views", "list.templ"))
+		if !strings.Contains(list, `@components.Icon("inbox", "h-12 w-12")`) {
+			t.Error("the empty state should default to the inbox icon")
+		}
+	})
+
+	t.Run("rejects icons the sets do not draw", func(t *testing.T) {
+		for _, icon := range []string{"Shopping Cart", "shopping-bag"} {
+			result, _ := scaffoldDomain(registry, types.ScaffoldDomainInput{
+				DomainName: "invoice",
+				Fields:     []types.FieldDef{{Name: "Number", Type: "string"}},
+				Icon:       icon,
+			})
+			if result.Success {
+				t.Errorf("%q: expected an error", icon)
+			}
+		}
+	})
+}
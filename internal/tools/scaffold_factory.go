@@ -0,0 +1,174 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterScaffoldFactory registers the scaffold_factory tool.
+func RegisterScaffoldFactory(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_factory",
+		Description: `Generate a test data factory for a domain, shared by tests and seeders.
+
+Generates internal/testutil/factories/{domain}.go with a builder-style factory:
+- New{Model}(db) starts a factory; With{Field}(value) sets a field and With(func(*models.{Model}))
+  changes anything else. Fields left alone get gofakeit values picked by type and name (emails,
+  names, URLs, addresses...), select fields one of their options and unique fields a sequence number
+- Build() returns an unsaved record; Create(ctx) saves it; CreateMany(ctx, n) saves n records with
+  their own fake values; MustCreate(t) fails the test instead of returning an error
+- belongs_to parents: With{Parent}(parent) uses an existing record; otherwise Create creates one
+  with the parent's factory. Factories of parent domains are generated too when missing. Parents
+  without scaffold metadata (e.g., User of the auth system) must be set with With{Parent}
+
+internal/testutil/factories/factories.go holds what the factories share and is generated once.
+Seeders can use the factories too: factories.NewProduct(db).CreateMany(ctx, 50).
+
+The domain must have been created with scaffold_domain (it is read from .mcp/scaffold-metadata.json).
+An existing factory of the domain is reported as a conflict instead of being overwritten.
+
+Example:
+  scaffold_factory: { domain: "order_line" }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldFactoryInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldFactory)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldFactory(registry *Registry, input types.ScaffoldFactoryInput) (types.ScaffoldResult, error) {
+	if err := utils.ValidateDomainName(input.Domain); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
+	// Get module path from go.mod
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	meta, err := metadata.NewStore(registry.WorkingDir).Load()
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to read scaffold metadata: %v", err)), nil
+	}
+	domain, ok := meta.Domains[input.Domain]
+	if !ok {
+		return types.NewErrorResult(fmt.Sprintf("no scaffold metadata for domain '%s': scaffold it with scaffold_domain first", input.Domain)), nil
+	}
+
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+
+	factoriesDir := filepath.Join("internal", "testutil", "factories")
+	if err := gen.EnsureDir(factoriesDir); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to create directory: %v", err)), nil
+	}
+	if err := gen.GenerateFileIfNotExists("tests/factories.go.tmpl", filepath.Join(factoriesDir, "factories.go"), nil); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to generate factories.go: %v", err)), nil
+	}
+
+	data := factoryData(meta, domain.Input, modulePath)
+	outputPath := filepath.Join(factoriesDir, utils.ToSnakeCase(input.Domain)+".go")
+	if err := gen.GenerateFile("tests/factory.go.tmpl", outputPath, data); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", outputPath, err)), nil
+	}
+
+	// Create uses the factories of the parents, so generate the missing ones up the belongs_to chain
+	seen := map[string]bool{input.Domain: true}
+	queue := parentDomains(meta, domain.Input)
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		if seen[parent.DomainName] {
+			continue
+		}
+		seen[parent.DomainName] = true
+
+		parentData := factoryData(meta, parent, modulePath)
+		parentPath := filepath.Join(factoriesDir, utils.ToSnakeCase(parent.DomainName)+".go")
+		if err := gen.GenerateFileIfNotExists("tests/factory.go.tmpl", parentPath, parentData); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", parentPath, err)), nil
+		}
+		queue = append(queue, parentDomains(meta, parent)...)
+	}
+
+	result := gen.Result()
+
+	// Factories the team has extended are never overwritten
+	if conflictResult := CheckForConflicts(result); conflictResult != nil {
+		return *conflictResult, nil
+	}
+
+	nextSteps := []string{
+		"go get github.com/brianvoe/gofakeit/v6",
+		fmt.Sprintf("Create test data with factories.New%s(db).MustCreate(t) in tests, or CreateMany(ctx, n) in seeders", data.ModelName),
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would create a factory for '%s'", input.Domain),
+			FilesCreated: result.FilesCreated,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully created a factory for '%s'", input.Domain),
+		FilesCreated: result.FilesCreated,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// factoryData builds the factory template data of a domain. A belongs_to parent has a factory
+// when the parent domain has scaffold metadata.
+func factoryData(meta *metadata.ProjectMetadata, input types.ScaffoldDomainInput, modulePath string) generator.FactoryData {
+	data := generator.FactoryData{DomainData: generator.NewDomainData(input, modulePath)}
+	for _, rel := range data.Relationships {
+		if !rel.IsBelongsTo || rel.IsSelfReferential {
+			continue
+		}
+		_, hasFactory := findDomainByModel(meta, rel.Model)
+		data.Parents = append(data.Parents, generator.FactoryParentData{
+			FieldName:  rel.FieldName,
+			ForeignKey: rel.ForeignKey,
+			Model:      rel.Model,
+			HasFactory: hasFactory,
+		})
+	}
+	return data
+}
+
+// parentDomains returns the belongs_to parents of a domain that have scaffold metadata.
+func parentDomains(meta *metadata.ProjectMetadata, input types.ScaffoldDomainInput) []types.ScaffoldDomainInput {
+	var parents []types.ScaffoldDomainInput
+	for _, rel := range input.Relationships {
+		if rel.Type != "belongs_to" {
+			continue
+		}
+		if parent, ok := findDomainByModel(meta, rel.Model); ok && parent.DomainName != input.DomainName {
+			parents = append(parents, parent)
+		}
+	}
+	return parents
+}
+
+// findDomainByModel returns the scaffold input of the domain whose model is model (e.g., "OrderLine").
+func findDomainByModel(meta *metadata.ProjectMetadata, model string) (types.ScaffoldDomainInput, bool) {
+	for _, domain := range meta.Domains {
+		if utils.ToModelName(domain.Input.DomainName) == utils.ToModelName(model) {
+			return domain.Input, true
+		}
+	}
+	return types.ScaffoldDomainInput{}, false
+}
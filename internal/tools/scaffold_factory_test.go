@@ -0,0 +1,162 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldFactory(t *testing.T) {
+	factoriesDir := filepath.Join("internal", "testutil", "factories")
+	setup := func(t *testing.T, inputs ...types.ScaffoldDomainInput) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		for _, input := range inputs {
+			if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+				t.Fatalf("failed to scaffold domain %s: %v %s", input.DomainName, err, result.Message)
+			}
+		}
+		return registry, tmpDir
+	}
+	category := types.ScaffoldDomainInput{
+		DomainName:    "category",
+		Fields:        []types.FieldDef{{Name: "Name", Type: "string", GORMTags: "uniqueIndex"}},
+		Relationships: []types.RelationshipDef{{Type: "belongs_to", Model: "self"}},
+	}
+	product := types.ScaffoldDomainInput{
+		DomainName: "product",
+		Fields: []types.FieldDef{
+			{Name: "Title", Type: "string"},
+			{Name: "Status", Type: "string", FormType: "select", Options: []string{"draft", "live"}},
+			{Name: "ReleasedAt", Type: "*time.Time"},
+		},
+		Relationships: []types.RelationshipDef{{Type: "belongs_to", Model: "Category"}},
+	}
+	orderLine := types.ScaffoldDomainInput{
+		DomainName: "order_line",
+		Fields:     []types.FieldDef{{Name: "Quantity", Type: "int"}},
+		Relationships: []types.RelationshipDef{
+			{Type: "belongs_to", Model: "Product"},
+			{Type: "belongs_to", Model: "User"},
+		},
+	}
+
+	t.Run("generates a builder with fake values", func(t *testing.T) {
+		registry, tmpDir := setup(t, category, product)
+
+		result, err := scaffoldFactory(registry, types.ScaffoldFactoryInput{Domain: "product"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			"factories.go": {
+				"package factories",
+				"type TB interface {",
+				"func unique(value string) string {",
+			},
+			"product.go": {
+				`"time"`,
+				"func NewProduct(db *gorm.DB) *ProductFactory {",
+				"func (f *ProductFactory) WithTitle(value string) *ProductFactory {",
+				"func (f *ProductFactory) WithReleasedAt(value *time.Time) *ProductFactory {",
+				"func (f *ProductFactory) WithCategory(category *models.Category) *ProductFactory {",
+				"Title: gofakeit.Sentence(3),",
+				`Status: gofakeit.RandomString([]string{"draft", "live"}),`,
+				"ReleasedAt: ptr(gofakeit.Date()),",
+				"if m.CategoryID == 0 {\n\t\tcategory, err := NewCategory(f.db).Create(ctx)",
+				"func (f *ProductFactory) CreateMany(ctx context.Context, n int) ([]*models.Product, error) {",
+				"func (f *ProductFactory) MustCreate(t TB) *models.Product {",
+			},
+			// The parent's factory is generated for Create
+			"category.go": {
+				"Name: unique(gofakeit.Name()),",
+				"func (f *CategoryFactory) WithParent(parent *models.Category) *CategoryFactory {",
+				"m.ParentID = &parent.ID",
+			},
+		}
+		for file, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, factoriesDir, file))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", file, want)
+				}
+			}
+		}
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, factoriesDir, "category.go")), "NewCategory(f.db).Create(ctx)") {
+			t.Error("expected a self-referential parent not to be created")
+		}
+	})
+
+	t.Run("generates the factories up the belongs_to chain", func(t *testing.T) {
+		registry, tmpDir := setup(t, category, product, orderLine)
+
+		result, err := scaffoldFactory(registry, types.ScaffoldFactoryInput{Domain: "order_line"})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		for _, file := range []string{"factories.go", "order_line.go", "product.go", "category.go"} {
+			if !fileExists(filepath.Join(tmpDir, factoriesDir, file)) {
+				t.Errorf("expected %s to be generated", file)
+			}
+		}
+
+		// User has no scaffold metadata, so there is no factory to create one with
+		content := readFile(t, filepath.Join(tmpDir, factoriesDir, "order_line.go"))
+		for _, want := range []string{
+			"NewProduct(f.db).Create(ctx)",
+			`return nil, fmt.Errorf("order line needs a user: set it with WithUser")`,
+			"func (f *OrderLineFactory) WithUser(user *models.User) *OrderLineFactory {",
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected order_line.go to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("keeps existing parent factories and reports its own as a conflict", func(t *testing.T) {
+		registry, tmpDir := setup(t, category, product)
+		categoryPath := filepath.Join(tmpDir, factoriesDir, "category.go")
+		if err := os.MkdirAll(filepath.Dir(categoryPath), 0755); err != nil {
+			t.Fatalf("failed to create factories dir: %v", err)
+		}
+		if err := os.WriteFile(categoryPath, []byte("package factories\n// customized\n"), 0644); err != nil {
+			t.Fatalf("failed to write category.go: %v", err)
+		}
+
+		result, err := scaffoldFactory(registry, types.ScaffoldFactoryInput{Domain: "product"})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		if !strings.Contains(readFile(t, categoryPath), "// customized") {
+			t.Error("expected the existing category factory to be kept")
+		}
+
+		result, err = scaffoldFactory(registry, types.ScaffoldFactoryInput{Domain: "product"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected the existing product factory to be reported as a conflict")
+		}
+	})
+
+	t.Run("requires scaffold metadata", func(t *testing.T) {
+		registry, _ := setup(t)
+
+		result, err := scaffoldFactory(registry, types.ScaffoldFactoryInput{Domain: "product"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || !strings.Contains(result.Message, "no scaffold metadata for domain 'product'") {
+			t.Errorf("expected a missing metadata error, got: %s", result.Message)
+		}
+	})
+}
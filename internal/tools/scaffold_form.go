@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"path/filepath"
+	"strings"
 
 	"github.com/dbb1dev/go-mcp/internal/generator"
 	"github.com/dbb1dev/go-mcp/internal/types"
@@ -29,9 +30,15 @@ Do NOT scaffold separate forms for create and edit actions.
 Specify action: 'create' or 'edit' to set the default form behavior.
 Set submit_endpoint for the HTMX post URL.
 
+Set steps to split a long form into sections, e.g.
+steps: [{title: "Account", fields: ["Email", "Password"]}, {title: "Profile", fields: ["Name", "Bio"]}].
+Alpine shows one step at a time with Back/Next buttons; Next checks the step's fields with the
+browser's validation (required, type, ...) and the last step submits the whole form at once.
+Every field goes on exactly one step. For flows saved step by step as drafts, use scaffold_wizard.
+
 Run 'templ generate' after creating forms.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldFormInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldForm(registry, input)
+		result, err := locked(scaffoldForm)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -40,6 +47,11 @@ Run 'templ generate' after creating forms.`,
 }
 
 func scaffoldForm(registry *Registry, input types.ScaffoldFormInput) (types.ScaffoldResult, error) {
+	// API-only projects have no views
+	if registry.IsAPIProject() {
+		return noViewsInAPIProject("scaffold_form"), nil
+	}
+
 	// Validate input
 	if input.FormName == "" {
 		return types.NewErrorResult("form name is required"), nil
@@ -68,6 +80,13 @@ func scaffoldForm(registry *Registry, input types.ScaffoldFormInput) (types.Scaf
 				return types.NewErrorResult(fmt.Sprintf("field '%s': %v", field.Name, err)), nil
 			}
 		}
+		if err := utils.ValidateFieldRules(field.Type, field.FormType, field.Min, field.Max, field.Pattern); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("field '%s': %v", field.Name, err)), nil
+		}
+	}
+
+	if err := validateFormSteps(input.Steps, input.Fields); err != nil {
+		return types.NewErrorResult(err.Error()), nil
 	}
 
 	// Get module path from go.mod
@@ -129,10 +148,15 @@ func scaffoldForm(registry *Registry, input types.ScaffoldFormInput) (types.Scaf
 		},
 	}
 
+	kind := input.Action
+	if len(input.Steps) > 0 {
+		kind = fmt.Sprintf("%d-step %s", len(input.Steps), input.Action)
+	}
+
 	if input.DryRun {
 		return types.ScaffoldResult{
 			Success:        true,
-			Message:        fmt.Sprintf("Dry run: Would create %s form '%s' for domain '%s'", input.Action, input.FormName, input.Domain),
+			Message:        fmt.Sprintf("Dry run: Would create %s form '%s' for domain '%s'", kind, input.FormName, input.Domain),
 			FilesCreated:   result.FilesCreated,
 			NextSteps:      nextSteps,
 			SuggestedTools: suggestedTools,
@@ -141,10 +165,62 @@ func scaffoldForm(registry *Registry, input types.ScaffoldFormInput) (types.Scaf
 
 	return types.ScaffoldResult{
 		Success:        true,
-		Message:        fmt.Sprintf("Successfully created %s form '%s' for domain '%s'", input.Action, input.FormName, input.Domain),
+		Message:        fmt.Sprintf("Successfully created %s form '%s' for domain '%s'", kind, input.FormName, input.Domain),
 		FilesCreated:   result.FilesCreated,
 		FilesUpdated:   result.FilesUpdated,
+		Backups:        result.Backups,
 		NextSteps:      nextSteps,
 		SuggestedTools: suggestedTools,
 	}, nil
 }
+
+// maxFormSteps bounds the steps of a multi-step form; longer flows are better served by
+// scaffold_wizard, which saves each step.
+const maxFormSteps = 8
+
+// validateFormSteps checks that the steps of a multi-step form put every field on exactly one step.
+func validateFormSteps(steps []types.FormStepDef, fields []types.FieldDef) error {
+	if len(steps) == 0 {
+		return nil
+	}
+	if len(steps) == 1 {
+		return fmt.Errorf("steps needs at least two steps: leave it out for a single form")
+	}
+	if len(steps) > maxFormSteps {
+		return fmt.Errorf("steps supports at most %d steps: use scaffold_wizard for longer flows", maxFormSteps)
+	}
+
+	known := make(map[string]bool, len(fields))
+	for _, field := range fields {
+		known[field.Name] = true
+	}
+	placed := make(map[string]string, len(fields))
+	for i, step := range steps {
+		if strings.TrimSpace(step.Title) == "" {
+			return fmt.Errorf("step %d: title is required", i+1)
+		}
+		for _, text := range []string{step.Title, step.Description} {
+			if strings.ContainsAny(text, "{}<>\"\\") {
+				return fmt.Errorf("step '%s': title and description must not contain braces, angle brackets, quotes or backslashes", step.Title)
+			}
+		}
+		if len(step.Fields) == 0 {
+			return fmt.Errorf("step '%s': at least one field is required", step.Title)
+		}
+		for _, name := range step.Fields {
+			if !known[name] {
+				return fmt.Errorf("step '%s': unknown field '%s'", step.Title, name)
+			}
+			if other, ok := placed[name]; ok {
+				return fmt.Errorf("field '%s' is on both step '%s' and step '%s'", name, other, step.Title)
+			}
+			placed[name] = step.Title
+		}
+	}
+	for _, field := range fields {
+		if _, ok := placed[field.Name]; !ok {
+			return fmt.Errorf("field '%s' is not on any step", field.Name)
+		}
+	}
+	return nil
+}
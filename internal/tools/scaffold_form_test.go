@@ -251,6 +251,71 @@ func TestScaffoldForm(t *testing.T) {
 		}
 	})
 
+	t.Run("generates multi-step form", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		input := types.ScaffoldFormInput{
+			FormName: "product_wizard",
+			Domain:   "product",
+			Action:   "create",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string", Required: true},
+				{Name: "Price", Type: "float64"},
+				{Name: "Active", Type: "bool"},
+			},
+			Steps: []types.FormStepDef{
+				{Title: "Details", Fields: []string{"Name", "Active"}},
+				{Title: "Pricing", Description: "What it costs", Fields: []string{"Price"}},
+			},
+		}
+		result, err := scaffoldForm(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "internal", "web", "product", "views", "product_wizard.templ"))
+		for _, want := range []string{`data-step="0"`, `data-step="1"`, "last: 1", "Pricing", "What it costs", "next()"} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected multi-step form to contain %q", want)
+			}
+		}
+		if strings.Index(content, `"active"`) > strings.Index(content, `"price"`) {
+			t.Error("expected fields to be ordered by step")
+		}
+	})
+
+	t.Run("rejects invalid steps", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		fields := []types.FieldDef{{Name: "Name", Type: "string"}, {Name: "Price", Type: "float64"}}
+		for name, steps := range map[string][]types.FormStepDef{
+			"single step":   {{Title: "All", Fields: []string{"Name", "Price"}}},
+			"unknown field": {{Title: "One", Fields: []string{"Name"}}, {Title: "Two", Fields: []string{"Price", "Stock"}}},
+			"field twice":   {{Title: "One", Fields: []string{"Name", "Price"}}, {Title: "Two", Fields: []string{"Price"}}},
+			"missing field": {{Title: "One", Fields: []string{"Name"}}, {Title: "Two", Fields: nil}},
+			"missing title": {{Fields: []string{"Name"}}, {Title: "Two", Fields: []string{"Price"}}},
+		} {
+			result, err := scaffoldForm(registry, types.ScaffoldFormInput{
+				FormName: "product_wizard",
+				Domain:   "product",
+				Action:   "create",
+				Fields:   fields,
+				Steps:    steps,
+			})
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			if result.Success {
+				t.Errorf("%s: expected steps to be rejected", name)
+			}
+		}
+	})
+
 	t.Run("dry run does not create files", func(t *testing.T) {
 		registry, tmpDir := testRegistry(t)
 		setupGoMod(t, tmpDir, "github.com/example/testapp")
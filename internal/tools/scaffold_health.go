@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterScaffoldHealth registers the scaffold_health tool.
+func RegisterScaffoldHealth(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_health",
+		Description: `Add health, readiness and version endpoints to the project.
+
+Generates internal/health/health.go with:
+- GET /healthz: pings the database and checks that the migrations were applied
+- GET /readyz: pings the database and runs the readiness checks registered with
+  healthHandler.AddCheck(name, check), e.g. the cache of cached domains (cache.Ping)
+- GET /version: the version, commit, build date and Go version of the binary
+Checks time out after 2 seconds and the reports are JSON, with 503 when a check fails.
+
+Also generates internal/config/version.go (the Version, Commit and BuildDate variables that
+release builds set with -ldflags, see scaffold_ci with_release) when missing, and wires
+main.go: the handler is created after the migrations, its routes are registered, and the
+MCP:HEALTH_CHECKS markers hold the readiness checks, so later scaffolds add their own checks
+without touching the rest. The existing /health route stays as a plain liveness check.
+
+Example:
+  scaffold_health: {}`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldHealthInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldHealth)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldHealth(registry *Registry, input types.ScaffoldHealthInput) (types.ScaffoldResult, error) {
+	// Get module path from go.mod
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	healthPath := filepath.Join("internal", "health", "health.go")
+	if utils.FileExists(filepath.Join(registry.WorkingDir, healthPath)) {
+		return types.NewErrorResult("internal/health/health.go already exists"), nil
+	}
+
+	data := generator.HealthData{ModulePath: modulePath}
+
+	// Create generator
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+
+	if err := gen.GenerateFile("health/health.go.tmpl", healthPath, data); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", healthPath, err)), nil
+	}
+	versionPath := filepath.Join("internal", "config", "version.go")
+	if err := gen.GenerateFileIfNotExists("deploy/version.go.tmpl", versionPath, data); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", versionPath, err)), nil
+	}
+
+	result := gen.Result()
+
+	withCache := cacheHasPing(registry.WorkingDir)
+	if !input.DryRun {
+		mainGoPath := filepath.Join(registry.WorkingDir, "cmd", "web", "main.go")
+		if utils.FileExists(mainGoPath) {
+			if err := injectHealthWiring(mainGoPath, modulePath, withCache); err != nil {
+				fmt.Printf("Warning: could not wire the health endpoints into main.go: %v\n", err)
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, "cmd/web/main.go")
+			}
+		}
+	}
+
+	nextSteps := []string{
+		"Check the endpoints: curl localhost:8080/healthz, /readyz and /version",
+		"Register readiness checks of other dependencies between the MCP:HEALTH_CHECKS markers in main.go: healthHandler.AddCheck(\"search\", func(ctx context.Context) error { ... })",
+		"Point probes at them: liveness at /health, readiness at /readyz (scaffold_deploy target kubernetes does)",
+	}
+	if utils.FileExists(filepath.Join(registry.WorkingDir, "internal", "cache", "cache.go")) && !withCache {
+		nextSteps = append(nextSteps, "Add a readiness check of the cache: its package predates cache.Ping")
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      "Dry run: Would add the health, readiness and version endpoints",
+			FilesCreated: result.FilesCreated,
+			FilesUpdated: result.FilesUpdated,
+			Backups:      result.Backups,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      "Successfully added the health, readiness and version endpoints",
+		FilesCreated: result.FilesCreated,
+		FilesUpdated: result.FilesUpdated,
+		Backups:      result.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// injectHealthWiring creates the health handler in main.go, registers its routes and, with
+// withCache, the readiness check of the cache.
+func injectHealthWiring(mainGoPath, modulePath string, withCache bool) error {
+	injector, err := modifier.NewInjector(mainGoPath)
+	if err != nil {
+		return err
+	}
+	if err := injector.InjectImport(modulePath + "/internal/health"); err != nil {
+		return err
+	}
+	if err := injector.InjectHealthHandler(); err != nil {
+		return err
+	}
+	if withCache {
+		if err := injector.InjectImport(modulePath + "/internal/cache"); err != nil {
+			return err
+		}
+		if err := injector.InjectHealthCheck("cache", "cache.Ping"); err != nil {
+			return err
+		}
+	}
+	return injector.Save()
+}
+
+// cacheHasPing reports whether the project's cache package has Ping, which packages generated
+// before the health endpoints lack.
+func cacheHasPing(workingDir string) bool {
+	content, err := os.ReadFile(filepath.Join(workingDir, "internal", "cache", "cache.go"))
+	return err == nil && strings.Contains(string(content), "func Ping(ctx context.Context) error")
+}
@@ -0,0 +1,133 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldHealth(t *testing.T) {
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, `package main
+
+import (
+	"log"
+)
+
+func main() {
+	db := database.Connect(cfg)
+	if err := database.RunMigrations(db); err != nil {
+		log.Fatalf("Failed to migrate database: %v", err)
+	}
+
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+
+	router := web.NewRouter(cfg)
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:END
+}
+`)
+		return registry, tmpDir
+	}
+
+	t.Run("generates the endpoints and wires them", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := scaffoldHealth(registry, types.ScaffoldHealthInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "health", "health.go"): {
+				`"github.com/example/testapp/internal/config"`,
+				`r.Get("/healthz", h.Healthz)`,
+				`r.Get("/readyz", h.Readyz)`,
+				`r.Get("/version", h.Version)`,
+			},
+			filepath.Join("internal", "config", "version.go"): {`Version = "dev"`},
+			filepath.Join("cmd", "web", "main.go"): {
+				`"github.com/example/testapp/internal/health"`,
+				"healthHandler := health.NewHandler(db)\n\thealthHandler.MarkMigrated()\n\t// MCP:SERVICES:END",
+				"// MCP:HEALTH_CHECKS:START\n\t// MCP:HEALTH_CHECKS:END",
+				"healthHandler.RegisterRoutes(router)",
+			},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+
+		again, err := scaffoldHealth(registry, types.ScaffoldHealthInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if again.Success {
+			t.Error("expected failure when the health package exists")
+		}
+	})
+
+	t.Run("keeps an existing version.go", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		versionPath := filepath.Join(tmpDir, "internal", "config", "version.go")
+		if err := os.MkdirAll(filepath.Dir(versionPath), 0755); err != nil {
+			t.Fatalf("failed to create config dir: %v", err)
+		}
+		if err := os.WriteFile(versionPath, []byte("package config\n\nvar Version, Commit, BuildDate = \"1\", \"2\", \"3\"\n"), 0644); err != nil {
+			t.Fatalf("failed to write version.go: %v", err)
+		}
+
+		result, err := scaffoldHealth(registry, types.ScaffoldHealthInput{})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		if content := readFile(t, versionPath); !strings.Contains(content, `"1", "2", "3"`) {
+			t.Error("expected the existing version.go to be kept")
+		}
+	})
+
+	t.Run("checks the cache of cached domains", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		if result, err := scaffoldHealth(registry, types.ScaffoldHealthInput{}); err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+			WithCache:  true,
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		main := readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go"))
+		if !strings.Contains(main, "// MCP:HEALTH_CHECKS:START\n\thealthHandler.AddCheck(\"cache\", cache.Ping)\n") {
+			t.Errorf("expected the cache readiness check, got:\n%s", main)
+		}
+		if cache := readFile(t, filepath.Join(tmpDir, "internal", "cache", "cache.go")); !strings.Contains(cache, "func Ping(ctx context.Context) error") {
+			t.Error("expected cache.Ping")
+		}
+	})
+}
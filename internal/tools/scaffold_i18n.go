@@ -0,0 +1,470 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterScaffoldI18n registers the scaffold_i18n tool.
+func RegisterScaffoldI18n(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_i18n",
+		Description: `Translate the application: per-request locale detection, a translation helper for
+views and a language switcher.
+
+Generates:
+- internal/i18n/i18n.go: i18n.Middleware picks the locale of each request from the ?lang= query
+  parameter (remembered in a cookie), the cookie, then Accept-Language. i18n.T(ctx, "key")
+  translates a key into it, falling back to the default locale and then to the key. Messages are
+  loaded at startup from config/{locale}/messages/*.toml, where [product] title = "Products" is
+  the key "product.title"
+- internal/i18n/switcher.templ: i18n.LanguageSwitcher, links to the current page in each locale
+- config/{locale}/messages/common.toml: the shared view strings (Edit, Delete, Cancel, ...),
+  translated for en, es, fr, de and pt; other locales start in English to be translated
+
+main.go loads the messages and installs the middleware, the layouts set <html lang> from the
+request locale and the sidebar gets the language switcher.
+
+Domains scaffolded afterwards render their view strings with i18n.T, and their keys are written
+to config/{default locale}/messages/{domain}.toml. Re-scaffold existing domains to translate
+their views. Add a locale later by re-running the tool with it.
+
+Example:
+  scaffold_i18n: { locales: ["en", "es", "pt-BR"] }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldI18nInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldI18n)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldI18n(registry *Registry, input types.ScaffoldI18nInput) (types.ScaffoldResult, error) {
+	if registry.IsAPIProject() {
+		return types.NewErrorResult("scaffold_i18n translates templ views; API projects have none"), nil
+	}
+
+	// Get module path from go.mod
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	defaultLocale := input.DefaultLocale
+	if defaultLocale == "" {
+		defaultLocale = "en"
+	}
+	if existing := projectDefaultLocale(registry.WorkingDir); existing != "" {
+		if input.DefaultLocale != "" && input.DefaultLocale != existing {
+			return types.NewErrorResult(fmt.Sprintf("the default locale is already '%s' (internal/i18n/i18n.go)", existing)), nil
+		}
+		defaultLocale = existing
+	}
+	locales := []string{defaultLocale}
+	for _, locale := range input.Locales {
+		if !slices.Contains(locales, locale) {
+			locales = append(locales, locale)
+		}
+	}
+	for _, locale := range locales {
+		if err := utils.ValidateLocale(locale); err != nil {
+			return types.NewErrorResult(err.Error()), nil
+		}
+	}
+
+	data := generator.I18nData{ModulePath: modulePath, DefaultLocale: defaultLocale}
+
+	// Create generator; the i18n package is kept when the tool is re-run to add locales
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+
+	files := []struct {
+		template string
+		output   string
+	}{
+		{"i18n/i18n.go.tmpl", filepath.Join("internal", "i18n", "i18n.go")},
+		{"i18n/switcher.templ.tmpl", filepath.Join("internal", "i18n", "switcher.templ")},
+	}
+	for _, f := range files {
+		if err := gen.GenerateFileIfNotExists(f.template, f.output, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.output, err)), nil
+		}
+	}
+
+	result := gen.Result()
+
+	for _, locale := range locales {
+		path, created, err := writeMessages(registry.WorkingDir, locale, "common", commonMessages(locale), input.DryRun)
+		if err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to write %s: %v", path, err)), nil
+		}
+		if created {
+			result.FilesCreated = append(result.FilesCreated, path)
+		} else if path != "" {
+			result.FilesUpdated = append(result.FilesUpdated, path)
+		}
+	}
+
+	if !input.DryRun {
+		mainGoPath := filepath.Join(registry.WorkingDir, "cmd", "web", "main.go")
+		if utils.FileExists(mainGoPath) {
+			if err := injectI18nWiring(mainGoPath, modulePath); err != nil {
+				fmt.Printf("Warning: could not wire i18n into main.go: %v\n", err)
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, "cmd/web/main.go")
+			}
+		}
+
+		layouts := []string{
+			filepath.Join("internal", "web", "layouts", "base.templ"),
+			filepath.Join("internal", "web", "auth", "views", "layout.templ"),
+		}
+		for _, layout := range layouts {
+			layoutPath := filepath.Join(registry.WorkingDir, layout)
+			if !utils.FileExists(layoutPath) {
+				continue
+			}
+			updated, err := useI18nInLayout(layoutPath, modulePath)
+			if err != nil {
+				fmt.Printf("Warning: could not translate %s: %v\n", layout, err)
+			} else if updated {
+				result.FilesUpdated = append(result.FilesUpdated, filepath.ToSlash(layout))
+			}
+		}
+	}
+
+	nextSteps := []string{
+		"Run templ generate to compile the language switcher",
+		"Re-scaffold existing domains to render their views with i18n.T; new domains are translated automatically",
+		fmt.Sprintf("Translate the domain messages: copy config/%s/messages/{domain}.toml into the other locales", defaultLocale),
+		`Translate your own views with { i18n.T(ctx, "key") } and add the keys to config/{locale}/messages/*.toml`,
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would add i18n (%s)", strings.Join(locales, ", ")),
+			FilesCreated: result.FilesCreated,
+			FilesUpdated: result.FilesUpdated,
+			Backups:      result.Backups,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully added i18n (%s)", strings.Join(locales, ", ")),
+		FilesCreated: result.FilesCreated,
+		FilesUpdated: result.FilesUpdated,
+		Backups:      result.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// defaultLocalePattern matches the default locale constant of the generated i18n package.
+var defaultLocalePattern = regexp.MustCompile(`const DefaultLocale = "([^"]+)"`)
+
+// projectDefaultLocale returns the default locale of a project with i18n, or "" without it.
+func projectDefaultLocale(workingDir string) string {
+	content, err := os.ReadFile(filepath.Join(workingDir, "internal", "i18n", "i18n.go"))
+	if err != nil {
+		return ""
+	}
+	if match := defaultLocalePattern.FindSubmatch(content); match != nil {
+		return string(match[1])
+	}
+	return ""
+}
+
+// commonMessageTexts are the English texts of the common keys used by the domain views.
+var commonMessageTexts = []generator.MessageEntry{
+	{Key: "common.all", Text: "All"},
+	{Key: "common.cancel", Text: "Cancel"},
+	{Key: "common.clear", Text: "Clear"},
+	{Key: "common.click_to_edit", Text: "Click to edit"},
+	{Key: "common.created", Text: "Created"},
+	{Key: "common.delete", Text: "Delete"},
+	{Key: "common.deleted", Text: "Deleted"},
+	{Key: "common.edit", Text: "Edit"},
+	{Key: "common.export_csv", Text: "Export CSV"},
+	{Key: "common.item_deleted", Text: "Item deleted"},
+	{Key: "common.loading", Text: "Loading..."},
+	{Key: "common.no", Text: "No"},
+	{Key: "common.no_image", Text: "No image"},
+	{Key: "common.not_set", Text: "Not set"},
+	{Key: "common.replace_image", Text: "Choose a file to replace the current image."},
+	{Key: "common.save", Text: "Save"},
+	{Key: "common.save_changes", Text: "Save Changes"},
+	{Key: "common.save_failed", Text: "Could not save. Try again."},
+	{Key: "common.select_all", Text: "Select all"},
+	{Key: "common.selected", Text: "selected"},
+	{Key: "common.updated", Text: "Last Updated"},
+	{Key: "common.view", Text: "View"},
+	{Key: "common.view_details", Text: "View Details"},
+	{Key: "common.yes", Text: "Yes"},
+}
+
+// commonTranslations translates the common keys into the languages the tool knows.
+var commonTranslations = map[string]map[string]string{
+	"es": {
+		"common.all": "Todos", "common.cancel": "Cancelar", "common.clear": "Limpiar",
+		"common.click_to_edit": "Haz clic para editar", "common.created": "Creado", "common.delete": "Eliminar",
+		"common.deleted": "Eliminado", "common.edit": "Editar", "common.export_csv": "Exportar CSV",
+		"common.item_deleted": "Elemento eliminado", "common.loading": "Cargando...", "common.no": "No",
+		"common.no_image": "Sin imagen", "common.not_set": "Sin definir",
+		"common.replace_image": "Elige un archivo para reemplazar la imagen actual.", "common.save": "Guardar",
+		"common.save_changes": "Guardar cambios", "common.save_failed": "No se pudo guardar. Inténtalo de nuevo.",
+		"common.select_all": "Seleccionar todo", "common.selected": "seleccionados", "common.updated": "Última actualización",
+		"common.view": "Ver", "common.view_details": "Ver detalles", "common.yes": "Sí",
+	},
+	"fr": {
+		"common.all": "Tous", "common.cancel": "Annuler", "common.clear": "Effacer",
+		"common.click_to_edit": "Cliquez pour modifier", "common.created": "Créé", "common.delete": "Supprimer",
+		"common.deleted": "Supprimé", "common.edit": "Modifier", "common.export_csv": "Exporter en CSV",
+		"common.item_deleted": "Élément supprimé", "common.loading": "Chargement...", "common.no": "Non",
+		"common.no_image": "Aucune image", "common.not_set": "Non défini",
+		"common.replace_image": "Choisissez un fichier pour remplacer l'image actuelle.", "common.save": "Enregistrer",
+		"common.save_changes": "Enregistrer les modifications", "common.save_failed": "Impossible d'enregistrer. Réessayez.",
+		"common.select_all": "Tout sélectionner", "common.selected": "sélectionnés", "common.updated": "Dernière mise à jour",
+		"common.view": "Voir", "common.view_details": "Voir les détails", "common.yes": "Oui",
+	},
+	"de": {
+		"common.all": "Alle", "common.cancel": "Abbrechen", "common.clear": "Zurücksetzen",
+		"common.click_to_edit": "Zum Bearbeiten klicken", "common.created": "Erstellt", "common.delete": "Löschen",
+		"common.deleted": "Gelöscht", "common.edit": "Bearbeiten", "common.export_csv": "Als CSV exportieren",
+		"common.item_deleted": "Eintrag gelöscht", "common.loading": "Wird geladen...", "common.no": "Nein",
+		"common.no_image": "Kein Bild", "common.not_set": "Nicht festgelegt",
+		"common.replace_image": "Wählen Sie eine Datei, um das aktuelle Bild zu ersetzen.", "common.save": "Speichern",
+		"common.save_changes": "Änderungen speichern", "common.save_failed": "Speichern fehlgeschlagen. Bitte erneut versuchen.",
+		"common.select_all": "Alle auswählen", "common.selected": "ausgewählt", "common.updated": "Zuletzt aktualisiert",
+		"common.view": "Ansehen", "common.view_details": "Details ansehen", "common.yes": "Ja",
+	},
+	"pt": {
+		"common.all": "Todos", "common.cancel": "Cancelar", "common.clear": "Limpar",
+		"common.click_to_edit": "Clique para editar", "common.created": "Criado", "common.delete": "Excluir",
+		"common.deleted": "Excluído", "common.edit": "Editar", "common.export_csv": "Exportar CSV",
+		"common.item_deleted": "Item excluído", "common.loading": "Carregando...", "common.no": "Não",
+		"common.no_image": "Sem imagem", "common.not_set": "Não definido",
+		"common.replace_image": "Escolha um arquivo para substituir a imagem atual.", "common.save": "Salvar",
+		"common.save_changes": "Salvar alterações", "common.save_failed": "Não foi possível salvar. Tente novamente.",
+		"common.select_all": "Selecionar tudo", "common.selected": "selecionados", "common.updated": "Última atualização",
+		"common.view": "Ver", "common.view_details": "Ver detalhes", "common.yes": "Sim",
+	},
+}
+
+// commonMessages returns the common keys translated into locale, in English when the tool
+// has no translation for its language.
+func commonMessages(locale string) []generator.MessageEntry {
+	translations := commonTranslations[strings.SplitN(locale, "-", 2)[0]]
+	entries := make([]generator.MessageEntry, len(commonMessageTexts))
+	for i, entry := range commonMessageTexts {
+		if text, ok := translations[entry.Key]; ok {
+			entry.Text = text
+		}
+		entries[i] = entry
+	}
+	return entries
+}
+
+// writeMessages writes entries to config/{locale}/messages/{name}.toml, adding the keys
+// missing from an existing file and leaving the others as they are (they may be translated).
+// It returns the file path relative to the working directory, or "" when nothing changed,
+// and whether the file was created.
+func writeMessages(workingDir, locale, name string, entries []generator.MessageEntry, dryRun bool) (string, bool, error) {
+	path := filepath.Join("config", locale, "messages", name+".toml")
+	fullPath := filepath.Join(workingDir, path)
+
+	existing := ""
+	if content, err := os.ReadFile(fullPath); err == nil {
+		existing = string(content)
+	}
+	updated := mergeMessages(existing, entries)
+	if updated == existing {
+		return "", false, nil
+	}
+	if !dryRun {
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			return path, false, err
+		}
+		if err := os.WriteFile(fullPath, []byte(updated), 0644); err != nil {
+			return path, false, err
+		}
+	}
+	return filepath.ToSlash(path), existing == "", nil
+}
+
+// tomlTablePattern and tomlKeyPattern match the table headers and bare keys of a messages file.
+var (
+	tomlTablePattern = regexp.MustCompile(`^\s*\[([A-Za-z0-9_.\-]+)\]\s*(#.*)?$`)
+	tomlKeyPattern   = regexp.MustCompile(`^\s*([A-Za-z0-9_\-]+)\s*=`)
+)
+
+// sidebarNavPattern matches the sidebar navigation of the layouts.
+var sidebarNavPattern = regexp.MustCompile(`(?m)^(\s*)@SidebarNav\(\)\n`)
+
+// mergeMessages adds the entries missing from a TOML messages file. A key is written into its
+// table ("product.fields.name" is name in [product.fields]): at the end of the table when the
+// file declares it, since TOML forbids declaring a table twice, or in a new table otherwise.
+func mergeMessages(content string, entries []generator.MessageEntry) string {
+	lines := strings.Split(strings.TrimRight(content, "\n"), "\n")
+	if content == "" {
+		lines = nil
+	}
+
+	// Index the keys and the last line of each table
+	keys := map[string]bool{}
+	tableEnd := map[string]int{}
+	table := ""
+	for i, line := range lines {
+		if match := tomlTablePattern.FindStringSubmatch(line); match != nil {
+			table = match[1]
+			tableEnd[table] = i
+			continue
+		}
+		if match := tomlKeyPattern.FindStringSubmatch(line); match != nil {
+			keys[table+"."+match[1]] = true
+			tableEnd[table] = i
+		}
+	}
+
+	// Group the missing keys by table, in the order the entries use them
+	var tables []string
+	missing := map[string][]string{}
+	for _, entry := range entries {
+		if keys[entry.Key] {
+			continue
+		}
+		i := strings.LastIndex(entry.Key, ".")
+		if i < 0 {
+			continue
+		}
+		table, key := entry.Key[:i], entry.Key[i+1:]
+		if _, ok := missing[table]; !ok {
+			tables = append(tables, table)
+		}
+		missing[table] = append(missing[table], fmt.Sprintf("%s = %q", key, entry.Text))
+		keys[entry.Key] = true
+	}
+	if len(tables) == 0 {
+		return content
+	}
+
+	// Insert into the declared tables from the bottom up so earlier line numbers stay valid
+	var declared []string
+	for _, table := range tables {
+		if _, ok := tableEnd[table]; ok {
+			declared = append(declared, table)
+		}
+	}
+	sort.Slice(declared, func(i, j int) bool { return tableEnd[declared[i]] > tableEnd[declared[j]] })
+	for _, table := range declared {
+		end := tableEnd[table] + 1
+		lines = append(lines[:end], append(append([]string{}, missing[table]...), lines[end:]...)...)
+	}
+
+	for _, table := range tables {
+		if _, ok := tableEnd[table]; ok {
+			continue
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, "["+table+"]")
+		lines = append(lines, missing[table]...)
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// writeDomainMessages writes the keys recorded while rendering a domain's views to the
+// default locale: the domain's keys to {domain}.toml and the common keys to common.toml.
+// It returns the files it created and updated.
+func writeDomainMessages(workingDir, locale, name string, messages *generator.Messages, dryRun bool) (created, updated []string, err error) {
+	var domain, common []generator.MessageEntry
+	for _, entry := range messages.Entries() {
+		if strings.HasPrefix(entry.Key, "common.") {
+			common = append(common, entry)
+		} else {
+			domain = append(domain, entry)
+		}
+	}
+	for _, file := range []struct {
+		name    string
+		entries []generator.MessageEntry
+	}{{name, domain}, {"common", common}} {
+		if len(file.entries) == 0 {
+			continue
+		}
+		path, isNew, err := writeMessages(workingDir, locale, file.name, file.entries, dryRun)
+		if err != nil {
+			return created, updated, fmt.Errorf("%s: %w", path, err)
+		}
+		if isNew {
+			created = append(created, path)
+		} else if path != "" {
+			updated = append(updated, path)
+		}
+	}
+	return created, updated, nil
+}
+
+// injectI18nWiring loads the messages in main.go and installs the locale middleware.
+func injectI18nWiring(mainGoPath, modulePath string) error {
+	injector, err := modifier.NewInjector(mainGoPath)
+	if err != nil {
+		return err
+	}
+	if err := injector.InjectImport(modulePath + "/internal/i18n"); err != nil {
+		return err
+	}
+	if err := injector.InjectBetweenMarkers(modifier.MarkerServicesStart, modifier.MarkerServicesEnd, "i18n.Init()"); err != nil {
+		return err
+	}
+	if err := injector.InjectMiddleware("i18n.Middleware"); err != nil {
+		return err
+	}
+	return injector.Save()
+}
+
+// useI18nInLayout sets <html lang> from the request locale and adds the language switcher
+// under the sidebar navigation. It reports whether the layout changed.
+func useI18nInLayout(layoutPath, modulePath string) (bool, error) {
+	content, err := os.ReadFile(layoutPath)
+	if err != nil {
+		return false, err
+	}
+	updated := strings.ReplaceAll(string(content), `<html lang="en"`, `<html lang={ i18n.Locale(ctx) }`)
+	if !strings.Contains(updated, "@i18n.LanguageSwitcher()") {
+		updated = sidebarNavPattern.ReplaceAllString(updated, "$0$1@i18n.LanguageSwitcher()\n")
+	}
+	if updated == string(content) {
+		return false, nil
+	}
+
+	importPath := fmt.Sprintf("%q", modulePath+"/internal/i18n")
+	if !strings.Contains(updated, importPath) {
+		switch {
+		case strings.Contains(updated, "import \"context\"\n"):
+			updated = strings.Replace(updated, "import \"context\"\n", "import \"context\"\nimport "+importPath+"\n", 1)
+		case strings.Contains(updated, "import (\n"):
+			start := strings.Index(updated, "import (\n")
+			end := start + strings.Index(updated[start:], "\n)\n")
+			updated = updated[:end] + "\n\n\t" + importPath + updated[end:]
+		default:
+			return false, fmt.Errorf("import block not found")
+		}
+	}
+	return true, os.WriteFile(layoutPath, []byte(updated), 0644)
+}
@@ -0,0 +1,195 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldI18n(t *testing.T) {
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, `package main
+
+import (
+	"log"
+)
+
+func main() {
+	db := database.Connect(cfg)
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+
+	router := web.NewRouter(cfg)
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:END
+}
+`)
+		layoutPath := filepath.Join(tmpDir, "internal", "web", "layouts", "base.templ")
+		if err := os.MkdirAll(filepath.Dir(layoutPath), 0755); err != nil {
+			t.Fatalf("failed to create layouts dir: %v", err)
+		}
+		layout := "package layouts\n\nimport \"context\"\n\ntempl Base(title string) {\n\t<html lang=\"en\" class=\"h-full\">\n\t\t<aside>\n\t\t\t@SidebarNav()\n\t\t</aside>\n\t</html>\n}\n"
+		if err := os.WriteFile(layoutPath, []byte(layout), 0644); err != nil {
+			t.Fatalf("failed to write base.templ: %v", err)
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("generates i18n and wires it", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := scaffoldI18n(registry, types.ScaffoldI18nInput{Locales: []string{"es", "pt-BR"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "i18n", "i18n.go"):                {`const DefaultLocale = "en"`, "func Middleware(next http.Handler) http.Handler"},
+			filepath.Join("internal", "i18n", "switcher.templ"):         {"templ LanguageSwitcher()"},
+			filepath.Join("config", "en", "messages", "common.toml"):    {"[common]\nall = \"All\"\n", `save_changes = "Save Changes"`},
+			filepath.Join("config", "es", "messages", "common.toml"):    {`save_changes = "Guardar cambios"`},
+			filepath.Join("config", "pt-BR", "messages", "common.toml"): {`save_changes = "Salvar alterações"`},
+			filepath.Join("cmd", "web", "main.go"):                      {`"github.com/example/testapp/internal/i18n"`, "i18n.Init()", "router.Use(i18n.Middleware)"},
+			filepath.Join("internal", "web", "layouts", "base.templ"): {
+				"import \"context\"\nimport \"github.com/example/testapp/internal/i18n\"\n",
+				`<html lang={ i18n.Locale(ctx) } class="h-full">`,
+				"\t\t\t@SidebarNav()\n\t\t\t@i18n.LanguageSwitcher()\n",
+			},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("re-running adds locales", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		for _, input := range []types.ScaffoldI18nInput{{Locales: []string{"es"}}, {Locales: []string{"fr"}}} {
+			if result, err := scaffoldI18n(registry, input); err != nil || !result.Success {
+				t.Fatalf("unexpected failure: %v %s", err, result.Message)
+			}
+		}
+		if !fileExists(filepath.Join(tmpDir, "config", "fr", "messages", "common.toml")) {
+			t.Error("expected the fr messages")
+		}
+		counts := map[string]string{
+			filepath.Join("cmd", "web", "main.go"):                    "i18n.Middleware",
+			filepath.Join("internal", "web", "layouts", "base.templ"): "@i18n.LanguageSwitcher()",
+			filepath.Join("config", "en", "messages", "common.toml"):  "[common]",
+		}
+		for path, want := range counts {
+			if n := strings.Count(readFile(t, filepath.Join(tmpDir, path)), want); n != 1 {
+				t.Errorf("expected %s to contain %q once, got %d", path, want, n)
+			}
+		}
+
+		result, err := scaffoldI18n(registry, types.ScaffoldI18nInput{DefaultLocale: "es"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure when changing the default locale")
+		}
+	})
+
+	t.Run("domain views render translation keys", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		if result, err := scaffoldI18n(registry, types.ScaffoldI18nInput{}); err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}, {Name: "Active", Type: "bool"}},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		list := readFile(t, filepath.Join(tmpDir, "internal", "web", "product", "views", "list.templ"))
+		for _, want := range []string{`"github.com/example/testapp/internal/i18n"`, `{ i18n.T(ctx, "product.title") }`, `{ i18n.T(ctx, "product.total", props.TotalItems) }`} {
+			if !strings.Contains(list, want) {
+				t.Errorf("expected list.templ to contain %q", want)
+			}
+		}
+		form := readFile(t, filepath.Join(tmpDir, "internal", "web", "product", "views", "product_form.templ"))
+		if !strings.Contains(form, `{ i18n.T(ctx, "common.save_changes") }`) {
+			t.Error("expected the form to translate the common keys")
+		}
+
+		messages := readFile(t, filepath.Join(tmpDir, "config", "en", "messages", "product.toml"))
+		for _, want := range []string{"[product]\ntitle = \"Products\"\n", "[product.fields]\n", `name = "Name"`} {
+			if !strings.Contains(messages, want) {
+				t.Errorf("expected product.toml to contain %q, got:\n%s", want, messages)
+			}
+		}
+	})
+
+	t.Run("domain views without i18n are plain text", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		list := readFile(t, filepath.Join(tmpDir, "internal", "web", "product", "views", "list.templ"))
+		if strings.Contains(list, "i18n") {
+			t.Error("expected no i18n in the views of a project without it")
+		}
+		if fileExists(filepath.Join(tmpDir, "config", "en", "messages", "product.toml")) {
+			t.Error("expected no messages without i18n")
+		}
+	})
+
+	t.Run("rejects invalid locales", func(t *testing.T) {
+		registry, _ := setup(t)
+		for _, input := range []types.ScaffoldI18nInput{{Locales: []string{"spanish"}}, {DefaultLocale: "EN"}} {
+			result, err := scaffoldI18n(registry, input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success {
+				t.Errorf("expected failure for %+v", input)
+			}
+		}
+	})
+}
+
+func TestMergeMessages(t *testing.T) {
+	existing := "# Product messages\n[product]\ntitle = \"Produits\"\n\n[product.fields]\nname = \"Nom\"\n"
+	entries := []generator.MessageEntry{
+		{Key: "product.title", Text: "Products"},
+		{Key: "product.add", Text: "Add Product"},
+		{Key: "product.fields.name", Text: "Name"},
+		{Key: "product.fields.price", Text: "Price"},
+		{Key: "product.placeholders.name", Text: "Enter name"},
+	}
+
+	got := mergeMessages(existing, entries)
+	want := "# Product messages\n[product]\ntitle = \"Produits\"\nadd = \"Add Product\"\n\n[product.fields]\nname = \"Nom\"\nprice = \"Price\"\n\n[product.placeholders]\nname = \"Enter name\"\n"
+	if got != want {
+		t.Errorf("mergeMessages() =\n%s\nwant\n%s", got, want)
+	}
+	if again := mergeMessages(got, entries); again != got {
+		t.Errorf("expected merging again to change nothing, got:\n%s", again)
+	}
+}
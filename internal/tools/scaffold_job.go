@@ -0,0 +1,439 @@
+package tools
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Job and step statuses reported by get_job_status.
+const (
+	jobQueued    = "queued"
+	jobRunning   = "running"
+	jobSucceeded = "succeeded"
+	jobFailed    = "failed"
+	stepPending  = "pending"
+	stepSkipped  = "skipped"
+)
+
+// maxQueuedJobs bounds the jobs waiting to run; submit_scaffold_job refuses more.
+const maxQueuedJobs = 16
+
+// maxFinishedJobs is how many finished jobs get_job_status remembers.
+const maxFinishedJobs = 64
+
+// jobStepFunc decodes the JSON input of a scaffold job step and returns the call that runs it.
+type jobStepFunc func(input []byte) (jobCall, error)
+
+// jobCall runs a decoded scaffold job step.
+type jobCall func(registry *Registry) (types.ScaffoldResult, error)
+
+// jobTool is a tool a scaffold job step can run.
+type jobTool struct {
+	decode jobStepFunc
+	// input is the type of the tool's input, which the command line builds its flags from.
+	input reflect.Type
+}
+
+// jobStep adapts a tool implementation to a jobTool. Unknown input fields are
+// rejected so that typos fail at submit time instead of being silently ignored.
+// Every job tool writes to the project, so its calls hold the project lock.
+func jobStep[T any](fn func(*Registry, T) (types.ScaffoldResult, error)) jobTool {
+	fn = locked(fn)
+	decode := func(input []byte) (jobCall, error) {
+		var in T
+		dec := json.NewDecoder(bytes.NewReader(input))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&in); err != nil {
+			return nil, err
+		}
+		return func(registry *Registry) (types.ScaffoldResult, error) {
+			return fn(registry, in)
+		}, nil
+	}
+	return jobTool{decode: decode, input: reflect.TypeFor[T]()}
+}
+
+// jobTools are the tools a scaffold job step can run.
+var jobTools = map[string]jobTool{
+	"scaffold_project":          jobStep(scaffoldProject),
+	"scaffold_domain":           jobStep(scaffoldDomain),
+	"scaffold_repository":       jobStep(scaffoldRepository),
+	"scaffold_service":          jobStep(scaffoldService),
+	"scaffold_service_for_repo": jobStep(scaffoldServiceForRepo),
+	"scaffold_controller":       jobStep(scaffoldController),
+	"scaffold_view":             jobStep(scaffoldView),
+	"scaffold_component":        jobStep(scaffoldComponent),
+	"scaffold_form":             jobStep(scaffoldForm),
+	"scaffold_table":            jobStep(scaffoldTable),
+	"scaffold_modal":            jobStep(scaffoldModal),
+	"scaffold_page":             jobStep(scaffoldPage),
+	"scaffold_blog":             jobStep(scaffoldBlog),
+	"scaffold_config":           jobStep(scaffoldConfig),
+	"scaffold_deploy":           jobStep(scaffoldDeploy),
+	"scaffold_ci":               jobStep(scaffoldCI),
+	"scaffold_health":           jobStep(scaffoldHealth),
+	"scaffold_tests":            jobStep(scaffoldTests),
+	"scaffold_factory":          jobStep(scaffoldFactory),
+	"scaffold_seed":             jobStep(scaffoldSeed),
+	"scaffold_api_mock":         jobStep(scaffoldAPIMock),
+	"scaffold_client":           jobStep(scaffoldClient),
+	"scaffold_ts_types":         jobStep(scaffoldTSTypes),
+	"scaffold_realtime":         jobStep(scaffoldRealtime),
+	"scaffold_search_index":     jobStep(scaffoldSearchIndex),
+	"scaffold_i18n":             jobStep(scaffoldI18n),
+	"scaffold_settings":         jobStep(scaffoldSettings),
+	"scaffold_tenancy":          jobStep(scaffoldTenancy),
+	"scaffold_webhooks":         jobStep(scaffoldWebhooks),
+	"scaffold_wizard":           jobStep(scaffoldWizard),
+	"import_domain":             jobStep(importDomain),
+	"update_di_wiring":          jobStep(updateDIWiring),
+	"extend_repository":         jobStep(extendRepository),
+	"extend_service":            jobStep(extendService),
+	"extend_controller":         jobStep(extendController),
+	"extend_model":              jobStep(extendModel),
+	"refactor_extract_service":  jobStep(refactorExtractService),
+	"sync_domain":               jobStep(syncDomain),
+}
+
+// jobToolNames returns the tools a job step can run, sorted.
+func jobToolNames() []string {
+	names := make([]string, 0, len(jobTools))
+	for name := range jobTools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// scaffoldJob is a submitted list of tool calls and their progress.
+type scaffoldJob struct {
+	id              string
+	status          string
+	continueOnError bool
+	calls           []jobCall
+	steps           []types.JobStepResult
+	nextSteps       [][]string
+	// registry is the registry of the submitting call, whose working directory the steps write to.
+	registry *Registry
+}
+
+// jobQueue runs scaffold jobs one at a time, in submission order, on a background worker.
+// Steps write to the working directory, so jobs never run concurrently with each other.
+type jobQueue struct {
+	mu       sync.Mutex
+	jobs     map[string]*scaffoldJob
+	finished []string
+	nextID   int
+	pending  chan *scaffoldJob
+	start    sync.Once
+}
+
+// newJobQueue creates an empty job queue. Its worker starts with the first job.
+func newJobQueue() *jobQueue {
+	return &jobQueue{
+		jobs:    make(map[string]*scaffoldJob),
+		pending: make(chan *scaffoldJob, maxQueuedJobs),
+	}
+}
+
+// submit queues a job and returns its ID.
+func (q *jobQueue) submit(registry *Registry, job *scaffoldJob) (string, error) {
+	job.registry = registry
+	q.start.Do(func() { go q.work() })
+
+	q.mu.Lock()
+	q.nextID++
+	job.id = fmt.Sprintf("job-%d", q.nextID)
+	job.status = jobQueued
+	q.jobs[job.id] = job
+	q.mu.Unlock()
+
+	select {
+	case q.pending <- job:
+		return job.id, nil
+	default:
+		q.mu.Lock()
+		delete(q.jobs, job.id)
+		q.mu.Unlock()
+		return "", fmt.Errorf("the job queue is full (%d jobs waiting): poll get_job_status and submit again later", maxQueuedJobs)
+	}
+}
+
+// work runs queued jobs until the server exits.
+func (q *jobQueue) work() {
+	for job := range q.pending {
+		q.run(job.registry, job)
+	}
+}
+
+// run executes the steps of a job, recording each result as it finishes.
+func (q *jobQueue) run(registry *Registry, job *scaffoldJob) {
+	q.setJobStatus(job, jobRunning)
+
+	failed := false
+	for i, call := range job.calls {
+		tool := job.steps[i].Tool
+		if failed && !job.continueOnError {
+			q.updateStep(job, i, types.JobStepResult{Tool: tool, Status: stepSkipped, Message: "skipped after a failed step"})
+			continue
+		}
+		q.updateStep(job, i, types.JobStepResult{Tool: tool, Status: jobRunning})
+
+		started := time.Now()
+		result, err := runJobStep(registry, tool, call)
+		step := types.JobStepResult{
+			Tool:         tool,
+			Status:       jobSucceeded,
+			Message:      result.Message,
+			FilesCreated: result.FilesCreated,
+			FilesUpdated: result.FilesUpdated,
+			Backups:      result.Backups,
+			Conflicts:    result.Conflicts,
+			DurationMS:   time.Since(started).Milliseconds(),
+		}
+		if err != nil {
+			step.Status, step.Message = jobFailed, err.Error()
+		} else if !result.Success {
+			step.Status = jobFailed
+		}
+		if step.Status == jobFailed {
+			failed = true
+		}
+		q.mu.Lock()
+		job.steps[i] = step
+		job.nextSteps[i] = result.NextSteps
+		q.mu.Unlock()
+	}
+
+	if failed {
+		q.setJobStatus(job, jobFailed)
+	} else {
+		q.setJobStatus(job, jobSucceeded)
+	}
+	q.forgetOldJobs(job.id)
+}
+
+// runJobStep runs one step, turning a panic in a tool into a failed step
+// so that it does not take the server down with the worker.
+func runJobStep(registry *Registry, tool string, call jobCall) (result types.ScaffoldResult, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%s panicked: %v", tool, r)
+		}
+	}()
+	return call(registry)
+}
+
+func (q *jobQueue) setJobStatus(job *scaffoldJob, status string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job.status = status
+}
+
+func (q *jobQueue) updateStep(job *scaffoldJob, i int, step types.JobStepResult) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job.steps[i] = step
+}
+
+// forgetOldJobs drops the oldest finished jobs beyond maxFinishedJobs.
+func (q *jobQueue) forgetOldJobs(id string) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	q.finished = append(q.finished, id)
+	for len(q.finished) > maxFinishedJobs {
+		delete(q.jobs, q.finished[0])
+		q.finished = q.finished[1:]
+	}
+}
+
+// status returns a snapshot of a job, with its report once it has finished.
+func (q *jobQueue) status(id string) (types.ScaffoldJobResult, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.jobs[id]
+	if !ok {
+		return types.ScaffoldJobResult{}, false
+	}
+
+	result := types.ScaffoldJobResult{
+		Success: true,
+		JobID:   job.id,
+		Status:  job.status,
+		Total:   len(job.steps),
+		Steps:   slices.Clone(job.steps),
+	}
+	for _, step := range job.steps {
+		if step.Status == jobSucceeded || step.Status == jobFailed || step.Status == stepSkipped {
+			result.Completed++
+		}
+	}
+
+	switch job.status {
+	case jobQueued:
+		result.Message = fmt.Sprintf("Job %s is waiting for earlier jobs to finish", job.id)
+	case jobRunning:
+		result.Message = fmt.Sprintf("Job %s is running: %d of %d steps done", job.id, result.Completed, result.Total)
+	default:
+		result.Report = newJobReport(job.steps, job.nextSteps)
+		result.Message = fmt.Sprintf("Job %s %s: %d succeeded, %d failed, %d skipped",
+			job.id, job.status, result.Report.Succeeded, result.Report.Failed, result.Report.Skipped)
+	}
+	return result, true
+}
+
+// newJobReport aggregates the results of a finished job's steps.
+func newJobReport(steps []types.JobStepResult, nextSteps [][]string) *types.JobReport {
+	report := &types.JobReport{}
+	for i, step := range steps {
+		switch step.Status {
+		case jobSucceeded:
+			report.Succeeded++
+		case jobFailed:
+			report.Failed++
+		case stepSkipped:
+			report.Skipped++
+		}
+		for _, f := range step.FilesCreated {
+			report.FilesCreated = appendUnique(report.FilesCreated, f)
+		}
+		for _, f := range step.FilesUpdated {
+			report.FilesUpdated = appendUnique(report.FilesUpdated, f)
+		}
+		for _, cmd := range nextSteps[i] {
+			report.NextSteps = appendUnique(report.NextSteps, cmd)
+		}
+	}
+	return report
+}
+
+// RegisterSubmitScaffoldJob registers the submit_scaffold_job tool.
+func RegisterSubmitScaffoldJob(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "submit_scaffold_job",
+		Description: `Run a long list of scaffolding tool calls in the background and return a job ID immediately.
+
+Use this for large plans (a project and dozens of domains, wizards and views) that would take
+longer than the client's tool call timeout. Poll get_job_status with the job ID for progress,
+per-step results and, once the job has finished, the final report.
+
+Each step names a tool and its input, exactly as the tool would be called directly:
+  steps: [{tool: "scaffold_domain", input: {domain_name: "product", fields: [...]}}]
+
+Supported tools: ` + strings.Join(jobToolNames(), ", ") + `
+
+Behavior:
+- Every step is validated before the job is queued: unknown tools and input fields are rejected
+- Steps run in order; jobs run one at a time in submission order
+- A failed step (including conflicts) skips the remaining steps unless continue_on_error is true
+- Steps write files exactly like direct tool calls; avoid running other scaffolding tools while a job runs
+- scaffold_project should use in_current_dir: true so later steps run inside the new project
+- Jobs live in server memory: they are lost when the server restarts
+
+Example:
+  submit_scaffold_job: {
+    steps: [
+      {tool: "scaffold_domain", input: {domain_name: "category", fields: [{name: "Name", type: "string"}]}},
+      {tool: "scaffold_domain", input: {domain_name: "product", fields: [{name: "Name", type: "string"}],
+        relationships: [{type: "belongs_to", model: "Category"}]}}
+    ]
+  }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.SubmitScaffoldJobInput) (*mcp.CallToolResult, types.ScaffoldJobResult, error) {
+		result, err := submitScaffoldJob(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewScaffoldJobError(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+// RegisterGetJobStatus registers the get_job_status tool.
+func RegisterGetJobStatus(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "get_job_status",
+		Description: `Get the progress of a job submitted with submit_scaffold_job.
+
+Returns the job status (queued, running, succeeded, failed), the number of finished steps,
+and each step's status (pending, running, succeeded, failed, skipped) with its message,
+files and conflicts. Once the job has finished, the report aggregates the step counts
+and every file created or updated.
+
+Example:
+  get_job_status: { job_id: "job-1" }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.GetJobStatusInput) (*mcp.CallToolResult, types.ScaffoldJobResult, error) {
+		result, err := getJobStatus(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewScaffoldJobError(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func submitScaffoldJob(registry *Registry, input types.SubmitScaffoldJobInput) (types.ScaffoldJobResult, error) {
+	if len(input.Steps) == 0 {
+		return types.NewScaffoldJobError("at least one step is required"), nil
+	}
+
+	job := &scaffoldJob{
+		continueOnError: input.ContinueOnError,
+		calls:           make([]jobCall, len(input.Steps)),
+		steps:           make([]types.JobStepResult, len(input.Steps)),
+		nextSteps:       make([][]string, len(input.Steps)),
+	}
+	for i, step := range input.Steps {
+		tool, ok := jobTools[step.Tool]
+		if !ok {
+			return types.NewScaffoldJobError(fmt.Sprintf("step %d: unsupported tool '%s': must be one of %s", i+1, step.Tool, strings.Join(jobToolNames(), ", "))), nil
+		}
+		if step.Input == nil {
+			step.Input = map[string]any{}
+		}
+		data, err := json.Marshal(step.Input)
+		if err != nil {
+			return types.NewScaffoldJobError(fmt.Sprintf("step %d: invalid input: %v", i+1, err)), nil
+		}
+		// Decode every step up front, so bad inputs are reported before anything is written
+		call, err := tool.decode(data)
+		if err != nil {
+			return types.NewScaffoldJobError(fmt.Sprintf("step %d (%s): invalid input: %v", i+1, step.Tool, err)), nil
+		}
+		job.calls[i] = call
+		job.steps[i] = types.JobStepResult{Tool: step.Tool, Status: stepPending}
+	}
+
+	id, err := registry.jobs.submit(registry, job)
+	if err != nil {
+		return types.NewScaffoldJobError(err.Error()), nil
+	}
+
+	return types.ScaffoldJobResult{
+		Success: true,
+		Message: fmt.Sprintf("Job %s queued with %d steps: poll get_job_status for progress", id, len(job.steps)),
+		JobID:   id,
+		Status:  jobQueued,
+		Total:   len(job.steps),
+	}, nil
+}
+
+func getJobStatus(registry *Registry, input types.GetJobStatusInput) (types.ScaffoldJobResult, error) {
+	if input.JobID == "" {
+		return types.NewScaffoldJobError("job_id is required"), nil
+	}
+	result, ok := registry.jobs.status(input.JobID)
+	if !ok {
+		return types.NewScaffoldJobError(fmt.Sprintf("job '%s' not found: it never existed, or finished long enough ago to be forgotten", input.JobID)), nil
+	}
+	return result, nil
+}
@@ -0,0 +1,199 @@
+package tools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+// waitForJob polls get_job_status until the job has finished.
+func waitForJob(t *testing.T, registry *Registry, jobID string) types.ScaffoldJobResult {
+	t.Helper()
+	deadline := time.Now().Add(30 * time.Second)
+	for time.Now().Before(deadline) {
+		result, err := getJobStatus(registry, types.GetJobStatusInput{JobID: jobID})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected job status, got failure: %s", result.Message)
+		}
+		if result.Status == jobSucceeded || result.Status == jobFailed {
+			return result
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("job %s did not finish", jobID)
+	return types.ScaffoldJobResult{}
+}
+
+func domainStep(name string, fields ...map[string]any) types.JobStepDef {
+	input := map[string]any{"domain_name": name}
+	if len(fields) > 0 {
+		input["fields"] = fields
+	}
+	return types.JobStepDef{Tool: "scaffold_domain", Input: input}
+}
+
+func TestScaffoldJob(t *testing.T) {
+	nameField := map[string]any{"name": "Name", "type": "string"}
+
+	t.Run("validates steps before queueing", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+
+		tests := []struct {
+			name  string
+			steps []types.JobStepDef
+		}{
+			{"no steps", nil},
+			{"unknown tool", []types.JobStepDef{{Tool: "rm_rf"}}},
+			{"read-only tool", []types.JobStepDef{{Tool: "list_domains"}}},
+			{"unknown field", []types.JobStepDef{{Tool: "scaffold_domain", Input: map[string]any{"domain": "product"}}}},
+			{"wrong type", []types.JobStepDef{{Tool: "scaffold_domain", Input: map[string]any{"domain_name": 42}}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := submitScaffoldJob(registry, types.SubmitScaffoldJobInput{Steps: tt.steps})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success || result.JobID != "" {
+					t.Errorf("expected the job to be refused, got %+v", result)
+				}
+			})
+		}
+	})
+
+	t.Run("runs steps in order and reports the result", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		submitted, err := submitScaffoldJob(registry, types.SubmitScaffoldJobInput{Steps: []types.JobStepDef{
+			domainStep("category", nameField),
+			{Tool: "scaffold_domain", Input: map[string]any{
+				"domain_name":   "product",
+				"fields":        []any{nameField},
+				"relationships": []any{map[string]any{"type": "belongs_to", "model": "Category"}},
+			}},
+		}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !submitted.Success || submitted.JobID == "" || submitted.Status != jobQueued || submitted.Total != 2 {
+			t.Fatalf("expected a queued job, got %+v", submitted)
+		}
+
+		result := waitForJob(t, registry, submitted.JobID)
+		if result.Status != jobSucceeded {
+			t.Fatalf("expected the job to succeed, got %+v", result)
+		}
+		if result.Completed != 2 || result.Total != 2 {
+			t.Errorf("expected 2 of 2 steps done, got %d of %d", result.Completed, result.Total)
+		}
+		for _, step := range result.Steps {
+			if step.Status != jobSucceeded || len(step.FilesCreated) == 0 {
+				t.Errorf("expected the step to succeed with files, got %+v", step)
+			}
+		}
+		if result.Report == nil || result.Report.Succeeded != 2 {
+			t.Fatalf("expected a report of 2 succeeded steps, got %+v", result.Report)
+		}
+		created := strings.Join(result.Report.FilesCreated, "\n")
+		for _, want := range []string{filepath.Join("internal", "models", "category.go"), filepath.Join("internal", "models", "product.go")} {
+			if !strings.Contains(created, want) {
+				t.Errorf("expected the report to list %s, got:\n%s", want, created)
+			}
+		}
+		if !fileExists(filepath.Join(tmpDir, "internal", "models", "product.go")) {
+			t.Error("expected the product model to be written")
+		}
+	})
+
+	t.Run("runs every tool that extends a domain", func(t *testing.T) {
+		names := strings.Join(jobToolNames(), " ")
+		for _, tool := range []string{"extend_repository", "extend_service", "extend_controller", "extend_model"} {
+			if !strings.Contains(names, tool) {
+				t.Errorf("expected jobs to run %s, got %s", tool, names)
+			}
+		}
+
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		submitted, _ := submitScaffoldJob(registry, types.SubmitScaffoldJobInput{Steps: []types.JobStepDef{
+			domainStep("product", nameField),
+			{Tool: "extend_model", Input: map[string]any{
+				"domain":  "product",
+				"methods": []any{map[string]any{"name": "Label", "returns": "string", "body": "\treturn m.Name"}},
+			}},
+		}})
+		result := waitForJob(t, registry, submitted.JobID)
+		if result.Status != jobSucceeded {
+			t.Fatalf("expected the job to succeed, got %+v", result.Steps)
+		}
+		if model := readFile(t, filepath.Join(tmpDir, "internal", "models", "product.go")); !strings.Contains(model, "func (m *Product) Label() string") {
+			t.Errorf("expected the extend_model step to add the method, got:\n%s", model)
+		}
+	})
+
+	t.Run("skips the remaining steps after a failure", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		submitted, _ := submitScaffoldJob(registry, types.SubmitScaffoldJobInput{Steps: []types.JobStepDef{
+			domainStep("category"), // no fields
+			domainStep("product", nameField),
+		}})
+		result := waitForJob(t, registry, submitted.JobID)
+
+		if result.Status != jobFailed {
+			t.Errorf("expected the job to fail, got %s", result.Status)
+		}
+		if result.Steps[0].Status != jobFailed || !strings.Contains(result.Steps[0].Message, "at least one field") {
+			t.Errorf("expected the first step to fail, got %+v", result.Steps[0])
+		}
+		if result.Steps[1].Status != stepSkipped {
+			t.Errorf("expected the second step to be skipped, got %+v", result.Steps[1])
+		}
+		if result.Report.Failed != 1 || result.Report.Skipped != 1 || result.Completed != 2 {
+			t.Errorf("unexpected report: %+v", result.Report)
+		}
+		if fileExists(filepath.Join(tmpDir, "internal", "models", "product.go")) {
+			t.Error("expected the skipped step not to run")
+		}
+	})
+
+	t.Run("continues after a failure with continue_on_error", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		submitted, _ := submitScaffoldJob(registry, types.SubmitScaffoldJobInput{
+			Steps:           []types.JobStepDef{domainStep("category"), domainStep("product", nameField)},
+			ContinueOnError: true,
+		})
+		result := waitForJob(t, registry, submitted.JobID)
+
+		if result.Status != jobFailed || result.Steps[1].Status != jobSucceeded {
+			t.Errorf("expected the second step to run, got %+v", result.Steps)
+		}
+		if !fileExists(filepath.Join(tmpDir, "internal", "models", "product.go")) {
+			t.Error("expected the product model to be written")
+		}
+	})
+
+	t.Run("rejects unknown job IDs", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+
+		for _, id := range []string{"", "job-999"} {
+			result, err := getJobStatus(registry, types.GetJobStatusInput{JobID: id})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success {
+				t.Errorf("expected failure for job ID %q", id)
+			}
+		}
+	})
+}
@@ -6,6 +6,7 @@ import (
 	"path/filepath"
 
 	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
 	"github.com/dbb1dev/go-mcp/internal/types"
 	"github.com/dbb1dev/go-mcp/internal/utils"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -27,9 +28,19 @@ Features:
 - Customizable trigger button (text, variant)
 - Content types: form, info, confirm
 
+Domain forms (domain and action parameters, dialog and sheet modals):
+- The trigger button loads the domain's create form (action "create", the default) or the edit
+  form of props.ID (action "edit") into the modal over HTMX
+- The form posts from the modal: validation errors are re-rendered in it
+- Once saved, the modal closes, the toast is shown and the domain's list on the page reloads
+  (web.Response.Redirect answers forms sent with the HX-Modal header with the modalSaved event)
+
+Example:
+  scaffold_modal: { modal_name: "ProductCreate", modal_type: "dialog", domain: "product" }
+
 Run 'templ generate' after creating modals.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldModalInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldModal(registry, input)
+		result, err := locked(scaffoldModal)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -38,6 +49,11 @@ Run 'templ generate' after creating modals.`,
 }
 
 func scaffoldModal(registry *Registry, input types.ScaffoldModalInput) (types.ScaffoldResult, error) {
+	// API-only projects have no views
+	if registry.IsAPIProject() {
+		return noViewsInAPIProject("scaffold_modal"), nil
+	}
+
 	// Validate input
 	if input.ModalName == "" {
 		return types.NewErrorResult("modal name is required"), nil
@@ -46,6 +62,15 @@ func scaffoldModal(registry *Registry, input types.ScaffoldModalInput) (types.Sc
 	if err := utils.ValidateModalType(input.ModalType); err != nil {
 		return types.NewErrorResult(err.Error()), nil
 	}
+	if err := utils.ValidateModalAction(input.Action); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	if input.Action != "" && input.Domain == "" {
+		return types.NewErrorResult("action requires domain: it picks the domain form the modal shows"), nil
+	}
+	if input.Domain != "" && input.ModalType == "confirm" {
+		return types.NewErrorResult("modal_type confirm cannot show a domain form: use dialog or sheet"), nil
+	}
 
 	// Get module path from go.mod
 	modulePath, err := utils.GetModulePath(registry.WorkingDir)
@@ -59,6 +84,32 @@ func scaffoldModal(registry *Registry, input types.ScaffoldModalInput) (types.Sc
 
 	// Prepare template data
 	data := generator.NewModalData(modulePath, input)
+	if input.Domain != "" {
+		if err := utils.ValidateDomainName(input.Domain); err != nil {
+			return types.NewErrorResult(err.Error()), nil
+		}
+		meta, err := metadata.NewStore(registry.WorkingDir).Load()
+		if err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to read scaffold metadata: %v", err)), nil
+		}
+		domain, ok := meta.Domains[input.Domain]
+		if !ok {
+			return types.NewErrorResult(fmt.Sprintf("no scaffold metadata for domain '%s': scaffold it with scaffold_domain first", input.Domain)), nil
+		}
+		if !domain.Input.GetWithCrudViews() {
+			return types.NewErrorResult(fmt.Sprintf("domain '%s' has no CRUD views: the modal loads its form", input.Domain)), nil
+		}
+		domainData := generator.NewDomainData(domain.Input, modulePath)
+		data.Domain = input.Domain
+		data.Action = input.Action
+		if data.Action == "" {
+			data.Action = "create"
+		}
+		data.IsEdit = data.Action == "edit"
+		data.ModelName = domainData.ModelName
+		data.DomainURL = domainData.URLPath
+		data.ListID = domainData.VariableName + "-list"
+	}
 
 	// Determine output path - modals go to shared components directory
 	outputDir := filepath.Join("internal", "web", "components")
@@ -90,6 +141,16 @@ func scaffoldModal(registry *Registry, input types.ScaffoldModalInput) (types.Sc
 	if data.HTMXURL != "" {
 		nextSteps = append(nextSteps, fmt.Sprintf("Add handler for HTMX endpoint: %s", data.HTMXURL))
 	}
+	if data.Domain != "" {
+		props := ""
+		if data.IsEdit {
+			props = "ID: item.ID"
+		}
+		nextSteps = append(nextSteps,
+			fmt.Sprintf("Render @components.%s(components.%sProps{%s}) on a page, e.g. the %s list view", input.ModalName, input.ModalName, props, data.ModelName),
+			"Projects scaffolded before the modalSaved event redirect to the list once the form is saved: run analyze_project for internal/web/response.go to close the modal in place",
+		)
+	}
 
 	suggestedTools := []types.ToolHint{
 		types.HintScaffoldForm,
@@ -115,6 +176,7 @@ func scaffoldModal(registry *Registry, input types.ScaffoldModalInput) (types.Sc
 		Message:        fmt.Sprintf("Successfully created %s modal '%s'", input.ModalType, input.ModalName),
 		FilesCreated:   result.FilesCreated,
 		FilesUpdated:   result.FilesUpdated,
+		Backups:        result.Backups,
 		NextSteps:      nextSteps,
 		SuggestedTools: suggestedTools,
 	}, nil
@@ -239,3 +239,94 @@ func TestScaffoldModal(t *testing.T) {
 		}
 	})
 }
+
+func TestScaffoldModalDomain(t *testing.T) {
+	registry, tmpDir := testRegistry(t)
+	project, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+		ProjectName:  "app",
+		ModulePath:   "github.com/test/app",
+		DatabaseType: "sqlite",
+		WithAuth:     true,
+		InCurrentDir: true,
+	})
+	if err != nil || !project.Success {
+		t.Fatalf("scaffoldProject failed: %v %s", err, project.Message)
+	}
+	domain, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+		DomainName: "product",
+		Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+		RouteGroup: "authenticated",
+	})
+	if err != nil || !domain.Success {
+		t.Fatalf("scaffoldDomain failed: %v %s", err, domain.Message)
+	}
+
+	response := readFile(t, filepath.Join(tmpDir, "internal", "web", "response.go"))
+	if !strings.Contains(response, `res.r.Header.Get("HX-Modal") != ""`) || !strings.Contains(response, `"modalSaved"`) {
+		t.Error("Redirect should close the modals of domain forms with the modalSaved event")
+	}
+
+	t.Run("create form in a dialog", func(t *testing.T) {
+		result, err := scaffoldModal(registry, types.ScaffoldModalInput{
+			ModalName: "ProductCreate",
+			ModalType: "dialog",
+			Domain:    "product",
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("scaffoldModal failed: %v %s", err, result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "internal", "web", "components", "ProductCreate.templ"))
+		for _, want := range []string{
+			`return basePath + "/new"`,
+			`"hx-select":  "form",`,
+			`hx-headers={ ` + "`" + `{"HX-Modal": "product-create"}` + "`" + ` }`,
+			`x-on:modal-saved.camel="open = false; document.getElementById('product-list')`,
+			`form.setAttribute('hx-target', '#product-create-body')`,
+			`return "Add Product"`,
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("modal should contain %q", want)
+			}
+		}
+		// Domain modals do not share the standalone helpers, so several fit in the package
+		if strings.Contains(content, "templ ModalLoading()") {
+			t.Error("domain modals should not declare ModalLoading")
+		}
+	})
+
+	t.Run("edit form in a sheet", func(t *testing.T) {
+		result, err := scaffoldModal(registry, types.ScaffoldModalInput{
+			ModalName: "ProductEdit",
+			ModalType: "sheet",
+			Domain:    "product",
+			Action:    "edit",
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("scaffoldModal failed: %v %s", err, result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "internal", "web", "components", "ProductEdit.templ"))
+		for _, want := range []string{`import "fmt"`, "ID          uint", `return fmt.Sprintf("%s/%d/edit", basePath, p.ID)`, "animate-slide-in-right"} {
+			if !strings.Contains(content, want) {
+				t.Errorf("modal should contain %q", want)
+			}
+		}
+		if !strings.Contains(strings.Join(result.NextSteps, "\n"), "components.ProductEditProps{ID: item.ID}") {
+			t.Errorf("next steps should show how to render the modal, got %v", result.NextSteps)
+		}
+	})
+
+	t.Run("rejects invalid domain options", func(t *testing.T) {
+		for name, input := range map[string]types.ScaffoldModalInput{
+			"unknown domain":       {ModalName: "OrderCreate", ModalType: "dialog", Domain: "order"},
+			"confirm":              {ModalName: "ProductConfirm", ModalType: "confirm", Domain: "product"},
+			"action":               {ModalName: "ProductDelete", ModalType: "dialog", Domain: "product", Action: "delete"},
+			"action and no domain": {ModalName: "ProductNew", ModalType: "dialog", Action: "create"},
+		} {
+			if result, _ := scaffoldModal(registry, input); result.Success {
+				t.Errorf("%s: expected an error", name)
+			}
+		}
+	})
+}
@@ -3,10 +3,15 @@ package tools
 import (
 	"context"
 	"fmt"
+	"os"
+	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
 	"github.com/dbb1dev/go-mcp/internal/types"
 	"github.com/dbb1dev/go-mcp/internal/utils"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -24,9 +29,22 @@ Set create_toml_config: true to generate a TOML file for i18n content.
 
 Sections allow composing pages from: hero, content, table, cards, form.
 
+Presets generate a complete static page in internal/web/pages instead:
+  - about: hero, story sections and values
+  - pricing: plan cards with features and calls to action, and an FAQ
+  - contact: contact details and a mail form (required fields, honeypot, CSRF token);
+    messages go to the recipient in the page file through internal/mail, an SMTP sender
+    configured by SMTP_HOST, SMTP_PORT, SMTP_USERNAME, SMTP_PASSWORD and MAIL_FROM
+  - terms: numbered terms of service sections
+page_name defaults to the preset and route to /{page-name}. The text lives in
+config/en/pages/{page}.toml and is read on every request. Pages render in the base layout
+between a site header and footer whose links come from the site and footer sections of
+menu.toml; each preset adds itself there (terms to the footer only) and its routes to the
+public routes of main.go.
+
 Run 'templ generate' after creating pages.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldPageInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldPage(registry, input)
+		result, err := locked(scaffoldPage)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -35,6 +53,18 @@ Run 'templ generate' after creating pages.`,
 }
 
 func scaffoldPage(registry *Registry, input types.ScaffoldPageInput) (types.ScaffoldResult, error) {
+	// API-only projects have no views
+	if registry.IsAPIProject() {
+		return noViewsInAPIProject("scaffold_page"), nil
+	}
+
+	if err := utils.ValidatePagePreset(input.Preset); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	if input.Preset != "" {
+		return scaffoldPagePreset(registry, input)
+	}
+
 	// Validate input
 	if input.PageName == "" {
 		return types.NewErrorResult("page name is required"), nil
@@ -137,6 +167,7 @@ func scaffoldPage(registry *Registry, input types.ScaffoldPageInput) (types.Scaf
 		Message:        fmt.Sprintf("Successfully created page '%s' at route '%s'", input.PageName, input.Route),
 		FilesCreated:   result.FilesCreated,
 		FilesUpdated:   result.FilesUpdated,
+		Backups:        result.Backups,
 		NextSteps:      nextSteps,
 		SuggestedTools: suggestedTools,
 	}, nil
@@ -212,3 +243,238 @@ func buildPageConfigData(input types.ScaffoldPageInput) generator.ConfigData {
 		Layout:      layout,
 	}
 }
+
+// pagePresetTitles are the page titles of the presets when page_name is not set.
+var pagePresetTitles = map[string]string{
+	"about":   "About us",
+	"pricing": "Pricing",
+	"contact": "Contact us",
+	"terms":   "Terms of Service",
+}
+
+// pagesDir is the package of the static pages.
+var pagesDir = filepath.Join("internal", "web", "pages")
+
+// scaffoldPagePreset generates a static page from a preset and links it from main.go and
+// menu.toml.
+func scaffoldPagePreset(registry *Registry, input types.ScaffoldPageInput) (types.ScaffoldResult, error) {
+	pageName := input.PageName
+	title := utils.ToLabel(pageName)
+	if pageName == "" {
+		pageName = input.Preset
+		title = pagePresetTitles[input.Preset]
+	}
+	route := input.Route
+	if route == "" {
+		route = "/" + utils.ToKebabCase(pageName)
+	}
+	if err := utils.ValidateURLPath(route); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	projectName := path.Base(modulePath)
+	if project, err := metadata.NewStore(registry.WorkingDir).GetProject(); err == nil && project != nil && project.Input.ProjectName != "" {
+		projectName = project.Input.ProjectName
+	}
+	data := generator.StaticPageData{
+		ModulePath:   modulePath,
+		ProjectName:  projectName,
+		Preset:       input.Preset,
+		PageName:     utils.ToSnakeCase(pageName),
+		FuncName:     utils.ToPascalCase(pageName),
+		VariableName: utils.ToVariableName(pageName),
+		Route:        route,
+		Title:        title,
+		Date:         time.Now().Format("January 2, 2006"),
+		WithI18n:     utils.FileExists(filepath.Join(registry.WorkingDir, "internal", "i18n", "i18n.go")),
+		WithSEO:      registry.HasSEO(),
+	}
+
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+
+	// The shared helpers and the site header and footer are kept, so every preset can be added
+	shared := []struct{ tmpl, out string }{
+		{"pages/pages.go.tmpl", filepath.Join(pagesDir, "pages.go")},
+		{"pages/site.templ.tmpl", filepath.Join(pagesDir, "site.templ")},
+	}
+	if input.Preset == "contact" {
+		shared = append(shared, struct{ tmpl, out string }{"mail/mail.go.tmpl", filepath.Join("internal", "mail", "mail.go")})
+	}
+	for _, f := range shared {
+		if err := gen.GenerateFileIfNotExists(f.tmpl, f.out, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.out, err)), nil
+		}
+	}
+
+	files := []struct{ tmpl, out string }{
+		{"pages/" + input.Preset + ".go.tmpl", filepath.Join(pagesDir, data.PageName+".go")},
+		{"pages/" + input.Preset + ".templ.tmpl", filepath.Join(pagesDir, data.PageName+".templ")},
+		{"pages/" + input.Preset + ".toml.tmpl", filepath.Join("config", "en", "pages", data.PageName+".toml")},
+	}
+	for _, f := range files {
+		if err := gen.GenerateFile(f.tmpl, f.out, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.out, err)), nil
+		}
+	}
+
+	result := gen.Result()
+	if conflictResult := CheckForConflicts(result); conflictResult != nil {
+		return *conflictResult, nil
+	}
+
+	nextSteps := []string{"templ generate"}
+	if !input.DryRun {
+		mainGoPath := filepath.Join(registry.WorkingDir, "cmd", "web", "main.go")
+		if err := injectPageRoutes(mainGoPath, modulePath, data); err != nil {
+			nextSteps = append(nextSteps, fmt.Sprintf("Register the page in cmd/web/main.go: %s", strings.Join(pageRoutes(data), "; ")))
+		} else {
+			result.FilesUpdated = append(result.FilesUpdated, "cmd/web/main.go")
+		}
+
+		menuGoPath := filepath.Join("internal", "web", "menu", "menu.go")
+		if added, err := addSiteMenuSections(filepath.Join(registry.WorkingDir, menuGoPath)); err != nil {
+			nextSteps = append(nextSteps, "Add the site and footer sections to internal/web/menu/menu.go: Site and Footer fields (toml:\"site\", toml:\"footer\") and their Site() and Footer() accessors")
+		} else if added {
+			result.FilesUpdated = append(result.FilesUpdated, menuGoPath)
+		}
+
+		menuPath := filepath.Join("config", "en", "menu.toml")
+		if err := injectPageMenuLinks(filepath.Join(registry.WorkingDir, menuPath), data); err != nil {
+			nextSteps = append(nextSteps, fmt.Sprintf("Link %s from the footer section of config/en/menu.toml", route))
+		} else {
+			result.FilesUpdated = append(result.FilesUpdated, menuPath)
+		}
+	}
+	nextSteps = append(nextSteps, fmt.Sprintf("Edit config/en/pages/%s.toml to change the text of the page", data.PageName))
+	if input.Preset == "contact" {
+		nextSteps = append(nextSteps, "Set [form] recipient in the page file and SMTP_HOST, SMTP_USERNAME, SMTP_PASSWORD and MAIL_FROM in the environment; without SMTP_HOST messages are logged")
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would create the %s page at route '%s'", input.Preset, route),
+			FilesCreated: result.FilesCreated,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully created the %s page at route '%s'", input.Preset, route),
+		FilesCreated: result.FilesCreated,
+		FilesUpdated: result.FilesUpdated,
+		Backups:      result.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// pageRoutes returns the routes of a static page, as registered in main.go.
+func pageRoutes(data generator.StaticPageData) []string {
+	router := "router"
+	if data.WithSEO {
+		// The <head> gets the title and description of the page file's [meta]
+		router = fmt.Sprintf("router.With(seo.Defaults(%q))", data.PageName)
+	}
+	routes := []string{fmt.Sprintf("%s.Get(%q, pages.%s)", router, data.Route, data.FuncName)}
+	if data.Preset == "contact" {
+		routes = append(routes, fmt.Sprintf("%s.Post(%q, pages.Submit%s(mail.NewFromEnv()))", router, data.Route, data.FuncName))
+	}
+	return routes
+}
+
+// injectPageRoutes registers the routes of a static page with the public routes of main.go.
+func injectPageRoutes(mainGoPath, modulePath string, data generator.StaticPageData) error {
+	injector, err := modifier.NewInjector(mainGoPath)
+	if err != nil {
+		return err
+	}
+	if err := injector.InjectImport(modulePath + "/internal/web/pages"); err != nil {
+		return err
+	}
+	if data.Preset == "contact" {
+		if err := injector.InjectImport(modulePath + "/internal/mail"); err != nil {
+			return err
+		}
+	}
+	if data.WithSEO {
+		if err := injector.InjectImport(modulePath + "/internal/web/seo"); err != nil {
+			return err
+		}
+	}
+	start, end := modifier.MarkerRoutesPublicStart, modifier.MarkerRoutesPublicEnd
+	if !injector.HasMarker(start) {
+		start, end = modifier.MarkerRoutesStart, modifier.MarkerRoutesEnd
+	}
+	for _, route := range pageRoutes(data) {
+		if err := injector.InjectBetweenMarkers(start, end, route); err != nil {
+			return err
+		}
+	}
+	return injector.Save()
+}
+
+// injectPageMenuLinks links a static page from the site header and footer. The terms are
+// only linked from the footer.
+func injectPageMenuLinks(menuPath string, data generator.StaticPageData) error {
+	injector, err := modifier.NewInjector(menuPath)
+	if err != nil {
+		return err
+	}
+	if data.Preset != "terms" {
+		if err := injector.InjectMenuLink("site", data.Title, data.Route, ""); err != nil {
+			return err
+		}
+	}
+	if err := injector.InjectMenuLink("footer", data.Title, data.Route, ""); err != nil {
+		return err
+	}
+	return injector.Save()
+}
+
+// addSiteMenuSections adds the site and footer sections to the menu package of projects
+// scaffolded before it had them. It reports whether menu.go was changed.
+func addSiteMenuSections(menuGoPath string) (bool, error) {
+	content, err := os.ReadFile(menuGoPath)
+	if err != nil {
+		return false, err
+	}
+	source := string(content)
+	if strings.Contains(source, "func Footer()") {
+		return false, nil
+	}
+
+	fields := "\tAccount []Item `toml:\"account\"`\n"
+	sorted := "[][]Item{m.Main, m.Admin, m.Account}"
+	if !strings.Contains(source, fields) || !strings.Contains(source, sorted) {
+		return false, fmt.Errorf("menu sections not found")
+	}
+	source = strings.Replace(source, fields, fields+
+		"\t// Site links are shown in the header of the public pages.\n"+
+		"\tSite []Item `toml:\"site\"`\n"+
+		"\t// Footer links are shown in the footer of the public pages.\n"+
+		"\tFooter []Item `toml:\"footer\"`\n", 1)
+	source = strings.Replace(source, sorted, "[][]Item{m.Main, m.Admin, m.Account, m.Site, m.Footer}", 1)
+	source += `
+// Site returns the header links of the public pages.
+func Site() []Item {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current.Site
+}
+
+// Footer returns the footer links of the public pages.
+func Footer() []Item {
+	mu.RLock()
+	defer mu.RUnlock()
+	return current.Footer
+}
+`
+	return true, os.WriteFile(menuGoPath, []byte(source), 0644)
+}
@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -230,4 +231,173 @@ func TestScaffoldPage(t *testing.T) {
 			t.Error("expected NextSteps to be populated")
 		}
 	})
+	t.Run("validates preset", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+
+		result, err := scaffoldPage(registry, types.ScaffoldPageInput{Preset: "faq"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure for an unknown preset")
+		}
+		if !strings.Contains(result.Message, "preset") {
+			t.Errorf("expected error about the preset, got %q", result.Message)
+		}
+	})
+
+	presetSetup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, `package main
+
+import (
+	"net/http"
+)
+
+func main() {
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	// MCP:ROUTES:END
+	http.ListenAndServe(":8089", router)
+}
+`)
+		menuDir := filepath.Join(tmpDir, "config", "en")
+		if err := os.MkdirAll(menuDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(menuDir, "menu.toml"), []byte("[[main]]\nlabel = \"Dashboard\"\nurl = \"/dashboard\"\nicon = \"home\"\norder = 1\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("preset generates a static page", func(t *testing.T) {
+		registry, tmpDir := presetSetup(t)
+
+		result, err := scaffoldPage(registry, types.ScaffoldPageInput{Preset: "about"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			"internal/web/pages/pages.go":    {"package pages", "func loadContent(", "func render(", "layouts.BasePage(title, site(content))"},
+			"internal/web/pages/site.templ":  {"menu.Site()", "menu.Footer()"},
+			"internal/web/pages/about.go":    {"type aboutContent struct", "func About(w http.ResponseWriter, r *http.Request)", `loadContent(r.Context(), "about", &content)`},
+			"internal/web/pages/about.templ": {"templ aboutPage(content aboutContent)", "paragraphs(section.Body)"},
+			"config/en/pages/about.toml":     {"[meta]", `title = "About us"`, "[[sections]]", "[[values]]"},
+			"cmd/web/main.go":                {`"github.com/example/testapp/internal/web/pages"`, `router.Get("/about", pages.About)`},
+			"config/en/menu.toml":            {"[[site]]\nlabel = \"About us\"\nurl = \"/about\"\norder = 10", "[[footer]]\nlabel = \"About us\"\nurl = \"/about\"\norder = 10"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("%s should contain %q", path, want)
+				}
+			}
+		}
+		if content := readFile(t, filepath.Join(tmpDir, "cmd/web/main.go")); strings.Index(content, "pages.About") > strings.Index(content, "MCP:ROUTES:PUBLIC:END") {
+			t.Error("the page should be registered with the public routes")
+		}
+	})
+
+	t.Run("contact preset mails the form", func(t *testing.T) {
+		registry, tmpDir := presetSetup(t)
+
+		result, err := scaffoldPage(registry, types.ScaffoldPageInput{Preset: "contact"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			"internal/mail/mail.go":            {"package mail", "type Sender interface", "func NewFromEnv() Sender", `os.Getenv("SMTP_HOST")`},
+			"internal/web/pages/contact.go":    {"func Contact(", "func SubmitContact(sender mail.Sender) http.HandlerFunc", `r.PostFormValue("website")`, "netmail.ParseAddress(f.Email)"},
+			"internal/web/pages/contact.templ": {`hx-post="/contact"`, `name="csrf_token"`, `name="website"`},
+			"config/en/pages/contact.toml":     {"[form]", `recipient = "hello@example.com"`},
+			"cmd/web/main.go":                  {`"github.com/example/testapp/internal/mail"`, `router.Post("/contact", pages.SubmitContact(mail.NewFromEnv()))`},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("%s should contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("terms preset is only linked from the footer", func(t *testing.T) {
+		registry, tmpDir := presetSetup(t)
+
+		result, err := scaffoldPage(registry, types.ScaffoldPageInput{Preset: "terms", PageName: "legal"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		if !fileExists(filepath.Join(tmpDir, "internal", "web", "pages", "legal.templ")) {
+			t.Error("page_name should name the page files")
+		}
+		if content := readFile(t, filepath.Join(tmpDir, "cmd/web/main.go")); !strings.Contains(content, `router.Get("/legal", pages.Legal)`) {
+			t.Error("route should default to the page name")
+		}
+		menu := readFile(t, filepath.Join(tmpDir, "config/en/menu.toml"))
+		if strings.Contains(menu, "[[site]]") || !strings.Contains(menu, "[[footer]]\nlabel = \"Legal\"\nurl = \"/legal\"") {
+			t.Errorf("terms should only be linked from the footer, got:\n%s", menu)
+		}
+	})
+
+	t.Run("preset adds the site and footer sections to an older menu package", func(t *testing.T) {
+		registry, tmpDir := presetSetup(t)
+		menuGo := "package menu\n\ntype Menu struct {\n\tMain []Item `toml:\"main\"`\n\tAccount []Item `toml:\"account\"`\n}\n\nfunc Load() {\n\t_ = [][]Item{m.Main, m.Admin, m.Account}\n}\n"
+		menuDir := filepath.Join(tmpDir, "internal", "web", "menu")
+		if err := os.MkdirAll(menuDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(menuDir, "menu.go"), []byte(menuGo), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		for _, preset := range []string{"pricing", "about"} {
+			if result, err := scaffoldPage(registry, types.ScaffoldPageInput{Preset: preset}); err != nil || !result.Success {
+				t.Fatalf("scaffoldPage() = %v, %v", result.Message, err)
+			}
+		}
+
+		content := readFile(t, filepath.Join(menuDir, "menu.go"))
+		for _, want := range []string{"Site []Item `toml:\"site\"`", "Footer []Item `toml:\"footer\"`", "m.Site, m.Footer}", "func Site() []Item", "func Footer() []Item"} {
+			if strings.Count(content, want) != 1 {
+				t.Errorf("menu.go should contain %q once", want)
+			}
+		}
+	})
+
+	t.Run("preset dry run does not touch main.go", func(t *testing.T) {
+		registry, tmpDir := presetSetup(t)
+
+		result, err := scaffoldPage(registry, types.ScaffoldPageInput{Preset: "pricing", DryRun: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success || len(result.FilesCreated) == 0 {
+			t.Fatalf("expected a successful dry run reporting files, got %+v", result)
+		}
+		if fileExists(filepath.Join(tmpDir, "internal", "web", "pages", "pricing.go")) {
+			t.Error("dry run should not create files")
+		}
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, "cmd/web/main.go")), "pages.Pricing") {
+			t.Error("dry run should not change main.go")
+		}
+	})
 }
@@ -5,7 +5,9 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/dbb1dev/go-mcp/internal/config"
 	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
 	"github.com/dbb1dev/go-mcp/internal/types"
 	"github.com/dbb1dev/go-mcp/internal/utils"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -22,6 +24,15 @@ Generates a complete, production-ready project with:
 - templ + HTMX for interactive UIs with Tailwind CSS styling
 - Reusable UI components (buttons, cards, forms, tables, modals)
 - GORM database setup (sqlite, postgres, or mysql)
+- Seed command (go run ./cmd/seed) running the seeders registered in cmd/seed/seeders, each
+  after the seeders it depends on
+- Postgres and MySQL projects get a testcontainers harness (internal/testutil): repository tests
+  of domains scaffolded with with_tests open their database with testutil.DB, in-memory SQLite by
+  default or a database on a shared Postgres/MySQL container with TEST_DATABASE=container
+  (task test:integration and task test:repositories, which need Docker)
+- Structured logging with log/slog (internal/logging), configured by [log] in config/en/app.toml:
+  JSON or text output, request IDs (X-Request-ID) on every request log line, panics and 5xx
+  errors logged with their stack, and a logger passed to domain repositories, services and controllers
 - Taskfile for development commands
 - Hot reload with Air
 
@@ -29,11 +40,78 @@ Directory behavior:
 - Auto-detects if current directory name matches project_name and scaffolds in place
 - Otherwise creates a new subdirectory with the project name
 - Use in_current_dir: true to force scaffolding in current directory regardless of name
+- In a monorepo whose .gomcp.toml sets [project] root = "apps/web", scaffolds in apps/web, where
+  later tools also look for go.mod, main.go and .mcp/ (or pass workdir: "apps/web" per call)
 
 Options:
 - in_current_dir: true to force scaffold in current directory
+- project_type: web (default) or api
+  - web: templ + HTMX views, Tailwind CSS and a layout shell
+  - api: lean JSON API without templ, Tailwind, layouts or HTMX; domains get JSON controllers and no views
+    (with_auth and navigation are not available)
 - with_auth: true to include full authentication system (login, register, sessions, middleware)
 - with_user_management: true to include admin user management (requires with_auth)
+- navigation: soft page navigation so the app feels like an SPA without a JS framework
+  - none (default): regular full page loads
+  - boost: htmx hx-boost swaps only #main-content, keeps the layout shell, shows a progress bar and pushes history
+  - unpoly: Unpoly follows links and submits forms into the [up-main] content area
+  Add hx-boost="false" to any link or form that must do a full page load (e.g. file downloads)
+- css_pipeline: how assets/css/output.css is built (task tailwind, task tailwind:build)
+  - tailwind (default): npx @tailwindcss/cli
+  - standalone: the Tailwind standalone CLI, downloaded to bin/tailwindcss; no Node.js needed
+  - daisyui: Tailwind with the daisyUI plugin from package.json; buttons, cards, badges, form
+    fields and the sidebar menu use daisyUI classes and the theme switcher picks daisyUI themes
+    (with_dark_mode is not available: list "dark" in the [theme] palettes instead)
+  - postcss: PostCSS with @tailwindcss/postcss from package.json and postcss.config.mjs
+  Not available with project_type api
+- js_bundler: bundle htmx, Alpine and assets/js instead of loading htmx and Alpine from CDN script tags
+  - none (default): CDN script tags
+  - esbuild: esbuild.config.mjs; vite: vite.config.mjs (build only, the Go server serves the pages)
+  - Builds assets/js/main.js into assets/dist with a hashed file name and manifest.json; the layouts
+    load it with assets.Path("assets/js/main.js") and the router caches assets/dist for good
+  - task js watches and task js:build bundles; task dev and task build run them
+  Not available with project_type api
+- icon_set: the SVG icons of components.Icon (internal/web/components/icons.go), used by the
+  sidebar links, buttons and empty states
+  - heroicons (default): Heroicons outline; lucide: Lucide
+  - Both draw the same names (home, folder, chart-bar, shopping-cart, ...), so menu.toml and the
+    views work with either
+  Not available with project_type api
+- with_dark_mode: true to let users pick a light, dark or system color scheme
+  - A toggle in the sidebar keeps the choice in a color_scheme cookie; the default is
+    color_scheme under [theme] in config/en/app.toml
+  - The layouts, auth pages included, set the .dark class on <html> before the first paint,
+    following the OS with system
+  - Tailwind's dark: variant and the dark theme tokens follow that class instead of the OS setting
+- with_http_caching: true to add conditional GET support
+  - ETag middleware hashes 200 GET responses (show/list pages, fragments) and answers If-None-Match with 304 Not Modified
+  - Cache-Control per route group (public, authenticated, admin) from [http_cache] in config/en/app.toml
+- with_otel: true to add OpenTelemetry tracing (internal/telemetry)
+  - OTLP/HTTP trace exporter set up in main.go, configured by [telemetry] in config/en/app.toml
+  - HTTP middleware starting a span per request, named after the chi route (e.g., "GET /products/{id}")
+  - GORM plugin recording a span per query under the request's span
+  - Domains scaffolded afterwards get service spans (with_observability), so traces run from
+    the HTTP request through the service to each query
+  - The server shuts down gracefully on SIGINT/SIGTERM to flush buffered spans
+- error_reporting: report panics and server errors to an error tracker
+  - none (default): errors are only logged
+  - sentry: Sentry client (internal/errorreporting) configured by [error_reporting] in
+    config/en/app.toml and SENTRY_DSN; nothing is sent without a DSN
+  - Middleware reporting panics with the request, request ID and signed-in user, before
+    logging.Recoverer answers 500; 5xx responses written with Response.Error are reported too
+  - Events are tagged with the release: the version set with -ldflags (internal/config/version.go,
+    see scaffold_ci with_release) or the VCS revision of development builds
+- with_diagnostics: true to add runtime diagnostics for admins (requires with_auth)
+  - net/http/pprof profiles under /admin/debug/pprof/ and expvar variables (memory stats,
+    goroutines, uptime, database connection pool) under /admin/debug/vars
+  - Mounted on the admin route group, so only signed-in admins can reach them
+  - task profile captures a profile from a running instance and opens it in the pprof web UI
+- di: how cmd/web/main.go gets the domain repositories, services and controllers
+  - imperative (default): scaffold_domain injects the constructor calls into main.go
+  - wire: google/wire builds them. scaffold_domain writes a provider set per domain
+    (internal/web/{domain}/providers.go) and adds it to the injector in cmd/web/wire.go, and
+    main.go only gets the domain's controller from initializeDomains. Run 'task wire' after
+    scaffolding a domain to regenerate cmd/web/wire_gen.go; 'task build' runs it first
 - dry_run: true to preview files without writing
 
 Examples:
@@ -64,7 +142,7 @@ Examples:
 
 After running: Execute 'go mod tidy' then 'task dev' to start.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldProjectInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldProject(registry, input)
+		result, err := locked(scaffoldProject)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -73,6 +151,13 @@ After running: Execute 'go mod tidy' then 'task dev' to start.`,
 }
 
 func scaffoldProject(registry *Registry, input types.ScaffoldProjectInput) (types.ScaffoldResult, error) {
+	// Fill in the defaults of a .gomcp.toml written before the project
+	defaults, err := registry.Defaults()
+	if err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	input.DatabaseType = config.Or(input.DatabaseType, defaults.DatabaseType)
+
 	// Validate input
 	if err := utils.ValidateProjectName(input.ProjectName); err != nil {
 		return types.NewErrorResult(err.Error()), nil
@@ -83,22 +168,72 @@ func scaffoldProject(registry *Registry, input types.ScaffoldProjectInput) (type
 	if err := utils.ValidateDatabaseType(input.DatabaseType); err != nil {
 		return types.NewErrorResult(err.Error()), nil
 	}
+	if err := utils.ValidateNavigation(input.Navigation); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	if err := utils.ValidateProjectType(input.ProjectType); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	if err := utils.ValidateErrorReporting(input.ErrorReporting); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	if err := utils.ValidateDI(input.DI); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	if err := utils.ValidateCSSPipeline(input.CSSPipeline); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	if err := utils.ValidateJSBundler(input.JSBundler); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	if err := utils.ValidateIconSet(input.IconSet); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
+	// API projects have no views, sessions or layout shell
+	isAPI := input.ProjectType == "api"
+	if isAPI && input.WithAuth {
+		return types.NewErrorResult("with_auth is not supported with project_type api: the auth system uses sessions and templ views"), nil
+	}
+	if isAPI && input.Navigation != "" && input.Navigation != "none" {
+		return types.NewErrorResult("navigation is not supported with project_type api: API projects have no views"), nil
+	}
+	if isAPI && input.WithDarkMode {
+		return types.NewErrorResult("with_dark_mode is not supported with project_type api: API projects have no views"), nil
+	}
+	if isAPI && input.CSSPipeline != "" && input.CSSPipeline != "tailwind" {
+		return types.NewErrorResult("css_pipeline is not supported with project_type api: API projects have no CSS"), nil
+	}
+	if isAPI && input.JSBundler != "" && input.JSBundler != "none" {
+		return types.NewErrorResult("js_bundler is not supported with project_type api: API projects have no views"), nil
+	}
+	if isAPI && input.IconSet != "" {
+		return types.NewErrorResult("icon_set is not supported with project_type api: API projects have no views"), nil
+	}
+	// daisyUI themes switch between light and dark themselves
+	if input.WithDarkMode && input.CSSPipeline == "daisyui" {
+		return types.NewErrorResult("with_dark_mode is not supported with css_pipeline daisyui: daisyUI has its own dark theme; list \"dark\" in the [theme] palettes of config/en/app.toml instead"), nil
+	}
 
 	// Validate that with_user_management requires with_auth
 	if input.WithUserManagement && !input.WithAuth {
 		return types.NewErrorResult("with_user_management requires with_auth to be enabled"), nil
 	}
+	// The diagnostics routes are only reachable by admins
+	if input.WithDiagnostics && !input.WithAuth {
+		return types.NewErrorResult("with_diagnostics requires with_auth to be enabled: the pprof and expvar routes are mounted on the admin route group"), nil
+	}
 
 	// Set defaults
-	dbType := input.DatabaseType
-	if dbType == "" {
-		dbType = "sqlite"
-	}
+	data := generator.NewProjectData(input)
+	dbType := data.DatabaseType
+	errorReporting := data.ErrorReporting
 
 	// Auto-detect if we should scaffold in current directory:
-	// If the current directory name matches the project name, use current dir
+	// If the current directory name matches the project name, use current dir.
+	// The project.root of a monorepo's .gomcp.toml is the project directory itself.
 	currentDirName := filepath.Base(registry.WorkingDir)
-	useCurrentDir := input.InCurrentDir || currentDirName == input.ProjectName
+	useCurrentDir := input.InCurrentDir || currentDirName == input.ProjectName || isConfiguredProjectRoot(registry.WorkingDir)
 
 	// Create project path
 	var projectPath string
@@ -115,37 +250,168 @@ func scaffoldProject(registry *Registry, input types.ScaffoldProjectInput) (type
 	// Create generator
 	gen := registry.NewGenerator(projectPath)
 	gen.SetDryRun(input.DryRun)
+	gen.SetBuffered(true)
+
+	if err := renderProject(gen, data); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
+	// Prepare result
+	result := gen.Result()
+
+	// Check for conflicts
+	if conflictResult := CheckForConflicts(result); conflictResult != nil {
+		return *conflictResult, nil
+	}
+
+	// Write the generated files in one pass, or none of them
+	if err := gen.Flush(); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to write files: %v", err)), nil
+	}
+
+	// Add MCP marker instructions to CLAUDE.md and AGENTS.md
+	if !input.DryRun {
+		if err := addMCPMarkerInstructions(projectPath); err != nil {
+			// Non-fatal error - just log and continue
+			// The project is still functional without these instructions
+		}
+	}
+
+	var nextSteps []string
+	if isAPI {
+		nextSteps = []string{
+			"go mod tidy",
+			"task run  # Start the API server",
+		}
+		if !useCurrentDir {
+			nextSteps = append([]string{fmt.Sprintf("cd %s", input.ProjectName)}, nextSteps...)
+		}
+	} else if useCurrentDir {
+		nextSteps = []string{
+			"go mod tidy",
+			"templ generate",
+			"task dev  # Start development server",
+			"# If templ version mismatch: go get -u github.com/a-h/templ",
+		}
+	} else {
+		nextSteps = []string{
+			fmt.Sprintf("cd %s", input.ProjectName),
+			"go mod tidy",
+			"templ generate",
+			"task dev  # Start development server",
+			"# If templ version mismatch: go get -u github.com/a-h/templ",
+		}
+	}
+
+	if input.WithOtel {
+		nextSteps = append(nextSteps, "Run 'task tracing' to start Jaeger, set enabled = true under [telemetry] in config/en/app.toml, then open http://localhost:16686")
+	}
+	if errorReporting == "sentry" {
+		nextSteps = append(nextSteps, "Set SENTRY_DSN to your Sentry project's DSN to start reporting errors")
+	}
+	if dbType != "sqlite" {
+		nextSteps = append(nextSteps, "Run 'task test:integration' to run the tests with repositories on a database container (requires Docker)")
+	}
+	if input.WithDiagnostics {
+		nextSteps = append(nextSteps, fmt.Sprintf("Sign in as an admin, copy the %s-session cookie, then run 'task profile SESSION=<cookie>' to capture a CPU profile", input.ProjectName))
+	}
+
+	if data.DI == "wire" {
+		nextSteps = append(nextSteps, "Run 'task wire' after each scaffold_domain to regenerate cmd/web/wire_gen.go")
+	}
 
-	// Prepare template data
-	data := generator.ProjectData{
-		ProjectName:        input.ProjectName,
-		ModulePath:         input.ModulePath,
-		DatabaseType:       dbType,
-		WithAuth:           input.WithAuth,
-		WithUserManagement: input.WithUserManagement,
+	// A project in a subdirectory of a Go workspace, such as apps/web of a monorepo, joins it
+	if workspace := findGoWork(filepath.Dir(projectPath)); workspace != "" {
+		if rel, err := filepath.Rel(workspace, projectPath); err == nil {
+			nextSteps = append(nextSteps, fmt.Sprintf("Add the module to the workspace in %s: go work use ./%s", workspace, filepath.ToSlash(rel)))
+		}
 	}
 
+	// Suggest next tools to call
+	suggestedTools := []types.ToolHint{
+		types.HintScaffoldDomain,
+	}
+	if !isAPI {
+		suggestedTools = append(suggestedTools, types.HintScaffoldPage)
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:        true,
+			Message:        fmt.Sprintf("Dry run: Would create project '%s' with %d files", input.ProjectName, len(result.FilesCreated)),
+			FilesCreated:   result.FilesCreated,
+			NextSteps:      nextSteps,
+			SuggestedTools: suggestedTools,
+		}, nil
+	}
+
+	// Record the project type so later tools know whether the project has views
+	if err := metadata.NewStore(projectPath).SaveProject(input, ScaffolderVersion); err != nil {
+		// Log warning but don't fail - metadata is optional
+		fmt.Printf("Warning: could not save scaffold metadata: %v\n", err)
+	} else {
+		result.FilesCreated = append(result.FilesCreated, ".mcp/scaffold-metadata.json")
+	}
+
+	return types.ScaffoldResult{
+		Success:        true,
+		Message:        fmt.Sprintf("Successfully created project '%s'", input.ProjectName),
+		FilesCreated:   result.FilesCreated,
+		FilesUpdated:   result.FilesUpdated,
+		Backups:        result.Backups,
+		NextSteps:      nextSteps,
+		SuggestedTools: suggestedTools,
+	}, nil
+}
+
+// renderProject generates the directories and files of a project into gen.
+func renderProject(gen *generator.Generator, data generator.ProjectData) error {
+	// API projects have no views, sessions or layout shell
+	isAPI := data.ProjectType == "api"
+
 	// Create directory structure
 	directories := []string{
 		"cmd/web",
 		"cmd/seed",
+		"cmd/seed/seeders",
 		"internal/config",
 		"internal/database",
+		"internal/logging",
 		"internal/models",
 		"internal/repository",
 		"internal/services",
 		"internal/web/middleware",
-		"internal/web/layouts",
-		"internal/web/components",
-		"config/en/pages",
-		"assets/css",
-		"assets/js",
-		"components",
-		"utils",
+		"internal/web/routes",
+	}
+	if isAPI {
+		directories = append(directories, "config/en")
+	} else {
+		directories = append(directories,
+			"internal/web/layouts",
+			"internal/web/components",
+			"internal/web/menu",
+			"internal/web/seo",
+			"internal/web/breadcrumbs",
+			"config/en/pages",
+			"assets/css",
+			"assets/js",
+			"components",
+			"utils",
+		)
+	}
+
+	if data.WithDiagnostics {
+		directories = append(directories, "internal/web/diagnostics")
+	}
+	if data.DatabaseType != "sqlite" {
+		directories = append(directories, "internal/testutil")
+	}
+	if data.ErrorReporting == "sentry" {
+		directories = append(directories, "internal/errorreporting")
 	}
 
 	// Add auth directories if WithAuth is enabled
-	if input.WithAuth {
+	if data.WithAuth {
 		directories = append(directories,
 			"internal/repository/user",
 			"internal/services/auth",
@@ -158,7 +424,7 @@ func scaffoldProject(registry *Registry, input types.ScaffoldProjectInput) (type
 		)
 
 		// Add user management directories if enabled
-		if input.WithUserManagement {
+		if data.WithUserManagement {
 			directories = append(directories,
 				"internal/services/user",
 				"internal/web/users",
@@ -169,45 +435,148 @@ func scaffoldProject(registry *Registry, input types.ScaffoldProjectInput) (type
 
 	for _, dir := range directories {
 		if err := gen.EnsureDir(dir); err != nil {
-			return types.NewErrorResult(fmt.Sprintf("failed to create directory %s: %v", dir, err)), nil
+			return fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
 	}
 
 	// Generate files
-	files := []struct {
+	type projectFile struct {
 		template string
 		output   string
-	}{
+	}
+	files := []projectFile{
 		{"project/go.mod.tmpl", "go.mod"},
-		{"project/main.go.tmpl", "cmd/web/main.go"},
 		{"project/seed_main.go.tmpl", "cmd/seed/main.go"},
+		{"seed/seeders.go.tmpl", "cmd/seed/seeders/seeders.go"},
 		{"project/config.go.tmpl", "internal/config/config.go"},
 		{"project/database.go.tmpl", "internal/database/database.go"},
+		{"project/logging.go.tmpl", "internal/logging/logging.go"},
+		{"project/logging_gorm.go.tmpl", "internal/logging/gorm.go"},
 		{"project/base_model.go.tmpl", "internal/models/base.go"},
-		{"project/router.go.tmpl", "internal/web/router.go"},
-		{"project/middleware.go.tmpl", "internal/web/middleware/middleware.go"},
-		{"project/response.go.tmpl", "internal/web/response.go"},
-		{"project/base_layout.templ.tmpl", "internal/web/layouts/base.templ"},
-		{"project/common_components.templ.tmpl", "internal/web/components/common.templ"},
+		{"project/routes.go.tmpl", "internal/web/routes/routes.go"},
 		{"project/taskfile.yml.tmpl", "Taskfile.yml"},
 		{"project/air.toml.tmpl", ".air.toml"},
-		{"project/tailwind_input.css.tmpl", "assets/css/input.css"},
-		{"project/tailwind_output.css.tmpl", "assets/css/output.css"},
-		{"project/tailwind.config.js.tmpl", "tailwind.config.js"},
 		{"project/app.toml.tmpl", "config/en/app.toml"},
-		{"project/menu.toml.tmpl", "config/en/menu.toml"},
 		{"project/gitignore.tmpl", ".gitignore"},
 	}
+	if isAPI {
+		// JSON API: no templ, Tailwind, layouts, menu or HTMX
+		files = append(files, []projectFile{
+			{"api/main.go.tmpl", "cmd/web/main.go"},
+			{"api/router.go.tmpl", "internal/web/router.go"},
+			{"api/middleware.go.tmpl", "internal/web/middleware/middleware.go"},
+			{"api/response.go.tmpl", "internal/web/response.go"},
+		}...)
+	} else {
+		files = append(files, []projectFile{
+			{"project/main.go.tmpl", "cmd/web/main.go"},
+			{"project/router.go.tmpl", "internal/web/router.go"},
+			{"project/middleware.go.tmpl", "internal/web/middleware/middleware.go"},
+			{"project/response.go.tmpl", "internal/web/response.go"},
+			{"project/menu.go.tmpl", "internal/web/menu/menu.go"},
+			{"project/base_layout.templ.tmpl", "internal/web/layouts/base.templ"},
+			{"project/common_components.templ.tmpl", "internal/web/components/common.templ"},
+			{"project/icons.go.tmpl", "internal/web/components/icons.go"},
+			{"project/tailwind_input.css.tmpl", "assets/css/input.css"},
+			{"project/tailwind_output.css.tmpl", "assets/css/output.css"},
+			{"project/tailwind.config.js.tmpl", "tailwind.config.js"},
+			{"project/menu.toml.tmpl", "config/en/menu.toml"},
+			{"project/seo.go.tmpl", "internal/web/seo/seo.go"},
+			{"project/seo.toml.tmpl", "config/en/seo.toml"},
+			{"project/breadcrumbs.go.tmpl", "internal/web/breadcrumbs/breadcrumbs.go"},
+		}...)
+		// daisyUI, PostCSS and the JS bundlers are installed from npm
+		if data.WithNPM {
+			files = append(files, projectFile{"project/package.json.tmpl", "package.json"})
+		}
+		if data.CSSPipeline == "postcss" {
+			files = append(files, projectFile{"project/postcss.config.mjs.tmpl", "postcss.config.mjs"})
+		}
+		if data.JSBundler != "none" {
+			files = append(files, []projectFile{
+				{"project/js_main.js.tmpl", "assets/js/main.js"},
+				{"project/js_htmx.js.tmpl", "assets/js/htmx.js"},
+				{"project/assets.go.tmpl", "internal/web/assets/assets.go"},
+				{"project/" + data.JSBundler + ".config.mjs.tmpl", data.JSBundler + ".config.mjs"},
+			}...)
+		}
+	}
 
 	for _, f := range files {
 		if err := gen.GenerateFile(f.template, f.output, data); err != nil {
-			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.output, err)), nil
+			return fmt.Errorf("failed to generate %s: %w", f.output, err)
+		}
+	}
+
+	// Generate HTTP caching middleware if WithHTTPCaching is enabled
+	if data.WithHTTPCaching {
+		if err := gen.GenerateFile("project/http_cache.go.tmpl", "internal/web/middleware/http_cache.go", data); err != nil {
+			return fmt.Errorf("failed to generate internal/web/middleware/http_cache.go: %w", err)
+		}
+	}
+
+	// Generate the tracing setup if WithOtel is enabled
+	if data.WithOtel {
+		telemetryFiles := []projectFile{
+			{"project/telemetry.go.tmpl", "internal/telemetry/telemetry.go"},
+			{"project/telemetry_gorm.go.tmpl", "internal/telemetry/gorm.go"},
+		}
+		for _, f := range telemetryFiles {
+			if err := gen.GenerateFile(f.template, f.output, data); err != nil {
+				return fmt.Errorf("failed to generate %s: %w", f.output, err)
+			}
+		}
+	}
+
+	// Generate the Sentry client and middleware, tagged with the release from internal/config/version.go
+	if data.ErrorReporting == "sentry" {
+		sentryFiles := []projectFile{
+			{"project/errorreporting.go.tmpl", "internal/errorreporting/errorreporting.go"},
+			{"deploy/version.go.tmpl", "internal/config/version.go"},
+		}
+		for _, f := range sentryFiles {
+			if err := gen.GenerateFile(f.template, f.output, data); err != nil {
+				return fmt.Errorf("failed to generate %s: %w", f.output, err)
+			}
+		}
+	}
+
+	// Generate the pprof and expvar routes if WithDiagnostics is enabled
+	if data.WithDiagnostics {
+		if err := gen.GenerateFile("project/diagnostics.go.tmpl", "internal/web/diagnostics/diagnostics.go", data); err != nil {
+			return fmt.Errorf("failed to generate internal/web/diagnostics/diagnostics.go: %w", err)
+		}
+	}
+
+	// Generate the wire injector: domains append their provider sets to wire.go, and the
+	// generated wire_gen.go starts out with no domains
+	if data.DI == "wire" {
+		wireFiles := []projectFile{
+			{"project/wire.go.tmpl", "cmd/web/wire.go"},
+			{"project/wire_gen.go.tmpl", "cmd/web/wire_gen.go"},
+			{"project/domains.go.tmpl", "cmd/web/domains.go"},
+		}
+		for _, f := range wireFiles {
+			if err := gen.GenerateFile(f.template, f.output, data); err != nil {
+				return fmt.Errorf("failed to generate %s: %w", f.output, err)
+			}
+		}
+	}
+
+	// Generate the testcontainers harness for repository tests against Postgres or MySQL
+	if data.DatabaseType != "sqlite" {
+		if err := gen.GenerateFile("project/testutil_db.go.tmpl", "internal/testutil/db.go", data); err != nil {
+			return fmt.Errorf("failed to generate internal/testutil/db.go: %w", err)
 		}
 	}
 
 	// Generate auth files if WithAuth is enabled
-	if input.WithAuth {
-		authData := generator.NewAuthData(input.ModulePath, input.ProjectName)
+	if data.WithAuth {
+		authData := generator.NewAuthData(data.ModulePath, data.ProjectName)
+		authData.ErrorReporting = data.ErrorReporting
+		authData.WithDarkMode = data.WithDarkMode
+		authData.CSSPipeline = data.CSSPipeline
+		authData.JSBundler = data.JSBundler
 		authFiles := []struct {
 			template string
 			output   string
@@ -237,12 +606,12 @@ func scaffoldProject(registry *Registry, input types.ScaffoldProjectInput) (type
 
 		for _, f := range authFiles {
 			if err := gen.GenerateFile(f.template, f.output, authData); err != nil {
-				return types.NewErrorResult(fmt.Sprintf("failed to generate auth file %s: %v", f.output, err)), nil
+				return fmt.Errorf("failed to generate auth file %s: %w", f.output, err)
 			}
 		}
 
 		// Generate user management files if enabled
-		if input.WithUserManagement {
+		if data.WithUserManagement {
 			userMgmtFiles := []struct {
 				template string
 				output   string
@@ -260,70 +629,41 @@ func scaffoldProject(registry *Registry, input types.ScaffoldProjectInput) (type
 
 			for _, f := range userMgmtFiles {
 				if err := gen.GenerateFile(f.template, f.output, authData); err != nil {
-					return types.NewErrorResult(fmt.Sprintf("failed to generate user management file %s: %v", f.output, err)), nil
+					return fmt.Errorf("failed to generate user management file %s: %w", f.output, err)
 				}
 			}
 		}
 	}
+	return nil
+}
 
-	// Add MCP marker instructions to CLAUDE.md and AGENTS.md
-	if !input.DryRun {
-		if err := addMCPMarkerInstructions(projectPath); err != nil {
-			// Non-fatal error - just log and continue
-			// The project is still functional without these instructions
-		}
+// isConfiguredProjectRoot reports whether dir is the project.root of a .gomcp.toml above it.
+func isConfiguredProjectRoot(dir string) bool {
+	cfg, err := config.Load(dir)
+	if err != nil || cfg.ProjectRoot == "" {
+		return false
 	}
+	abs, err := filepath.Abs(dir)
+	return err == nil && abs == filepath.Join(cfg.Dir, cfg.ProjectRoot)
+}
 
-	// Prepare result
-	result := gen.Result()
-
-	// Check for conflicts
-	if conflictResult := CheckForConflicts(result); conflictResult != nil {
-		return *conflictResult, nil
+// findGoWork returns the directory of the go.work of dir or its parents, up to the root of
+// the git repository, or "" when there is none.
+func findGoWork(dir string) string {
+	dir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
 	}
-
-	var nextSteps []string
-	if useCurrentDir {
-		nextSteps = []string{
-			"go mod tidy",
-			"templ generate",
-			"task dev  # Start development server",
-			"# If templ version mismatch: go get -u github.com/a-h/templ",
+	for {
+		if utils.FileExists(filepath.Join(dir, "go.work")) {
+			return dir
 		}
-	} else {
-		nextSteps = []string{
-			fmt.Sprintf("cd %s", input.ProjectName),
-			"go mod tidy",
-			"templ generate",
-			"task dev  # Start development server",
-			"# If templ version mismatch: go get -u github.com/a-h/templ",
+		parent := filepath.Dir(dir)
+		if utils.DirExists(filepath.Join(dir, ".git")) || parent == dir {
+			return ""
 		}
+		dir = parent
 	}
-
-	// Suggest next tools to call
-	suggestedTools := []types.ToolHint{
-		types.HintScaffoldDomain,
-		types.HintScaffoldPage,
-	}
-
-	if input.DryRun {
-		return types.ScaffoldResult{
-			Success:        true,
-			Message:        fmt.Sprintf("Dry run: Would create project '%s' with %d files", input.ProjectName, len(result.FilesCreated)),
-			FilesCreated:   result.FilesCreated,
-			NextSteps:      nextSteps,
-			SuggestedTools: suggestedTools,
-		}, nil
-	}
-
-	return types.ScaffoldResult{
-		Success:        true,
-		Message:        fmt.Sprintf("Successfully created project '%s'", input.ProjectName),
-		FilesCreated:   result.FilesCreated,
-		FilesUpdated:   result.FilesUpdated,
-		NextSteps:      nextSteps,
-		SuggestedTools: suggestedTools,
-	}, nil
 }
 
 // mcpMarkerInstructions contains the warning about MCP marker comments.
@@ -2,8 +2,11 @@ package tools
 
 import (
 	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/dbb1dev/go-mcp/internal/generator"
 	"github.com/dbb1dev/go-mcp/internal/types"
 )
 
@@ -404,11 +407,11 @@ func TestScaffoldProject(t *testing.T) {
 			}
 		}
 
-		// Should have base files (19) + auth files (14) = 33 files
+		// Should have base files (28) + auth files (14) = 42 files
 		// Auth files: role_model, user_model, user_repository, auth_service, session,
 		// auth_middleware, auth_controller, auth_layout, login, register,
 		// dashboard_controller, dashboard, profile_controller, profile
-		expectedFileCount := 33
+		expectedFileCount := 42
 		if len(result.FilesCreated) != expectedFileCount {
 			t.Errorf("expected %d files with auth, got %d", expectedFileCount, len(result.FilesCreated))
 		}
@@ -458,13 +461,487 @@ func TestScaffoldProject(t *testing.T) {
 			t.Fatalf("expected success, got: %s", result.Message)
 		}
 
-		// Should have 19 files based on the template list (including tailwind.config.js and output.css)
-		expectedFileCount := 19
+		// Should have 28 files based on the template list (including tailwind.config.js, output.css, the icons and the seo and breadcrumbs packages)
+		expectedFileCount := 28
 		if len(result.FilesCreated) != expectedFileCount {
 			t.Errorf("expected %d files, got %d: %v", expectedFileCount, len(result.FilesCreated), result.FilesCreated)
 		}
 	})
 
+	t.Run("navigation option wires soft navigation", func(t *testing.T) {
+		tests := []struct {
+			navigation string
+			want       []string
+			notWant    []string
+		}{
+			{navigation: "", want: []string{`<div id="app" class="min-h-full">`}, notWant: []string{`class="min-h-full" hx-boost="true"`, "unpoly"}},
+			{navigation: "boost", want: []string{`class="min-h-full" hx-boost="true"`, "nav-progress", `data-shell="dashboard"`, "htmx:beforeSwap"}, notWant: []string{"unpoly"}},
+			{navigation: "unpoly", want: []string{"unpoly.min.js", "up-main", "up:fragment:inserted", `name="csrf-token"`}, notWant: []string{`class="min-h-full" hx-boost="true"`}},
+		}
+
+		for _, tt := range tests {
+			t.Run("navigation "+tt.navigation, func(t *testing.T) {
+				registry, tmpDir := testRegistry(t)
+				input := types.ScaffoldProjectInput{
+					ProjectName: "navapp",
+					ModulePath:  "github.com/test/navapp",
+					Navigation:  tt.navigation,
+				}
+
+				result, err := scaffoldProject(registry, input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !result.Success {
+					t.Fatalf("expected success, got: %s", result.Message)
+				}
+
+				content := readFile(t, tmpDir+"/navapp/internal/web/layouts/base.templ")
+				for _, want := range tt.want {
+					if !containsString(content, want) {
+						t.Errorf("base.templ should contain %q", want)
+					}
+				}
+				for _, notWant := range tt.notWant {
+					if containsString(content, notWant) {
+						t.Errorf("base.templ should not contain %q", notWant)
+					}
+				}
+			})
+		}
+
+		registry, _ := testRegistry(t)
+		result, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+			ProjectName: "navapp",
+			ModulePath:  "github.com/test/navapp",
+			Navigation:  "turbo",
+			DryRun:      true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure for invalid navigation")
+		}
+	})
+
+	t.Run("with_http_caching generates ETag and Cache-Control middleware", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName:        "cacheapp",
+			ModulePath:         "github.com/test/cacheapp",
+			WithAuth:           true,
+			WithUserManagement: true,
+			WithHTTPCaching:    true,
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got: %s", result.Message)
+		}
+
+		middleware := readFile(t, tmpDir+"/cacheapp/internal/web/middleware/http_cache.go")
+		for _, want := range []string{"func ETag(", "If-None-Match", "http.StatusNotModified", "func CacheControl("} {
+			if !containsString(middleware, want) {
+				t.Errorf("http_cache.go should contain %q", want)
+			}
+		}
+
+		router := readFile(t, tmpDir+"/cacheapp/internal/web/router.go")
+		if !containsString(router, "r.Use(middleware.ETag)") {
+			t.Error("router.go should apply the ETag middleware")
+		}
+
+		mainGo := readFile(t, tmpDir+"/cacheapp/cmd/web/main.go")
+		for _, want := range []string{"cfg.HTTPCache.Authenticated", "cfg.HTTPCache.Admin"} {
+			if !containsString(mainGo, want) {
+				t.Errorf("main.go should contain %q", want)
+			}
+		}
+
+		appToml := readFile(t, tmpDir+"/cacheapp/config/en/app.toml")
+		if !containsString(appToml, "[http_cache]") {
+			t.Error("app.toml should contain an [http_cache] section")
+		}
+	})
+
+	t.Run("no HTTP caching by default", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName: "plainapp",
+			ModulePath:  "github.com/test/plainapp",
+			WithAuth:    true,
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got: %s", result.Message)
+		}
+
+		if fileExists(tmpDir + "/plainapp/internal/web/middleware/http_cache.go") {
+			t.Error("http_cache.go should not be generated without with_http_caching")
+		}
+		if containsString(readFile(t, tmpDir+"/plainapp/internal/config/config.go"), "HTTPCache") {
+			t.Error("config.go should not contain HTTPCache without with_http_caching")
+		}
+	})
+
+	t.Run("with_otel generates the tracing setup", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName:  "tracedapp",
+			ModulePath:   "github.com/test/tracedapp",
+			DatabaseType: "postgres",
+			ProjectType:  "api",
+			WithOtel:     true,
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			"internal/telemetry/telemetry.go": {"otlptracehttp.WithEndpointURL(cfg.Telemetry.Endpoint)", "otel.SetTracerProvider(provider)", "func Middleware(", "semconv.HTTPRoute(pattern)"},
+			"internal/telemetry/gorm.go":      {"type GormPlugin struct{}", "semconv.DBSystemNamePostgreSQL", `Register("telemetry:before_query"`},
+			"internal/database/database.go":   {"db.Use(telemetry.GormPlugin{})"},
+			"internal/web/router.go":          {"r.Use(telemetry.Middleware)"},
+			"internal/config/config.go":       {"Telemetry TelemetryConfig", `getEnv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT"`},
+			"cmd/web/main.go":                 {"telemetry.Setup(context.Background(), cfg)", "server.Shutdown(shutdownCtx)", "shutdownTelemetry(shutdownCtx)"},
+			"config/en/app.toml":              {"[telemetry]", `endpoint = "http://localhost:4318/v1/traces"`},
+			"go.mod":                          {"go.opentelemetry.io/otel/sdk", "go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"},
+			"Taskfile.yml":                    {"jaegertracing/jaeger"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, tmpDir+"/tracedapp/"+path)
+			for _, want := range wants {
+				if !containsString(content, want) {
+					t.Errorf("%s should contain %q", path, want)
+				}
+			}
+		}
+		if containsString(readFile(t, tmpDir+"/tracedapp/cmd/web/main.go"), "log.Fatal(http.ListenAndServe") {
+			t.Error("main.go should shut down gracefully to flush spans")
+		}
+
+		// Domains of a traced project get service spans
+		domainResult, err := scaffoldDomain(NewRegistry(tmpDir+"/tracedapp"), types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+		})
+		if err != nil || !domainResult.Success {
+			t.Fatalf("unexpected failure: %v %s", err, domainResult.Message)
+		}
+		if service := readFile(t, tmpDir+"/tracedapp/internal/services/product/product.go"); !containsString(service, `startSpan(ctx, "Create")`) {
+			t.Error("services of a with_otel project should be instrumented")
+		}
+	})
+
+	t.Run("no tracing by default", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName: "untracedapp",
+			ModulePath:  "github.com/test/untracedapp",
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		if fileExists(tmpDir + "/untracedapp/internal/telemetry/telemetry.go") {
+			t.Error("telemetry.go should not be generated without with_otel")
+		}
+		for _, path := range []string{"cmd/web/main.go", "internal/web/router.go", "internal/database/database.go", "go.mod"} {
+			content := readFile(t, tmpDir+"/untracedapp/"+path)
+			if containsString(content, "telemetry") || containsString(content, "opentelemetry") {
+				t.Errorf("%s should not reference telemetry without with_otel", path)
+			}
+		}
+	})
+
+	t.Run("error_reporting sentry reports panics and server errors", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName:    "reportedapp",
+			ModulePath:     "github.com/test/reportedapp",
+			WithAuth:       true,
+			ErrorReporting: "sentry",
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		checks := map[string][]string{
+			"internal/errorreporting/errorreporting.go": {"sentry.Init(sentry.ClientOptions{", "Release:          Release(),", `return "reportedapp@" + version`, "hub.RecoverWithContext(ctx, rec)", "func SetUser(", "func Report("},
+			"internal/config/version.go":                {`Version = "dev"`},
+			"internal/config/config.go":                 {"ErrorReporting ErrorReportingConfig", `getEnv("SENTRY_DSN", "")`},
+			"internal/web/router.go":                    {"r.Use(logging.Recoverer(logger))\n\t// Inside Recoverer, which logs and answers the panics it reports again\n\tr.Use(errorreporting.Middleware)"},
+			"internal/web/response.go":                  {"errorreporting.Report(res.r.Context(), errors.New(message))"},
+			"internal/web/middleware/auth.go":           {"errorreporting.SetUser(ctx, strconv.FormatUint(uint64(user.ID), 10), user.Email)"},
+			"cmd/web/main.go":                           {"errorreporting.Setup(cfg)", "server.Shutdown(shutdownCtx)", "errorreporting.Flush()"},
+			"config/en/app.toml":                        {"[error_reporting]"},
+			"go.mod":                                    {"github.com/getsentry/sentry-go"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, tmpDir+"/reportedapp/"+path)
+			for _, want := range wants {
+				if !containsString(content, want) {
+					t.Errorf("%s should contain %q", path, want)
+				}
+			}
+		}
+		if containsString(readFile(t, tmpDir+"/reportedapp/cmd/web/main.go"), "telemetry") {
+			t.Error("main.go should not set up tracing without with_otel")
+		}
+	})
+
+	t.Run("no error reporting by default", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName: "unreportedapp",
+			ModulePath:  "github.com/test/unreportedapp",
+			WithAuth:    true,
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		if fileExists(tmpDir + "/unreportedapp/internal/errorreporting/errorreporting.go") {
+			t.Error("errorreporting.go should not be generated without error_reporting")
+		}
+		for _, path := range []string{"cmd/web/main.go", "internal/web/router.go", "internal/web/response.go", "internal/web/middleware/auth.go", "go.mod"} {
+			if content := readFile(t, tmpDir+"/unreportedapp/"+path); containsString(content, "errorreporting") || containsString(content, "sentry") {
+				t.Errorf("%s should not reference error reporting without error_reporting", path)
+			}
+		}
+	})
+
+	t.Run("rejects an unknown error_reporting", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName:    "myapp",
+			ModulePath:     "github.com/test/myapp",
+			ErrorReporting: "rollbar",
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || !containsString(result.Message, "invalid error_reporting") {
+			t.Errorf("expected error_reporting error, got: %s", result.Message)
+		}
+	})
+
+	t.Run("with_diagnostics mounts pprof and expvar on the admin routes", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName:     "diagapp",
+			ModulePath:      "github.com/test/diagapp",
+			WithAuth:        true,
+			WithDiagnostics: true,
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		checks := map[string][]string{
+			"internal/web/diagnostics/diagnostics.go": {"func Handler(db *gorm.DB) http.Handler", `r.Get("/pprof/profile", pprof.Profile)`, `r.Handle("/vars", expvar.Handler())`},
+			"cmd/web/main.go":                         {"r.Use(authMiddleware.RequireAdmin)", `r.Mount("/admin/debug", diagnostics.Handler(db))`, "MCP:ROUTES:ADMIN:START"},
+			"config/en/menu.toml":                     {`url = "/admin/debug/pprof/"`},
+			"Taskfile.yml":                            {"profile:", "diagapp-session={{.SESSION}}", "go tool pprof"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, tmpDir+"/diagapp/"+path)
+			for _, want := range wants {
+				if !containsString(content, want) {
+					t.Errorf("%s should contain %q", path, want)
+				}
+			}
+		}
+
+		// Only mounted inside the admin group
+		main := readFile(t, tmpDir+"/diagapp/cmd/web/main.go")
+		if strings.Index(main, "diagnostics.Handler(db)") < strings.Index(main, "r.Use(authMiddleware.RequireAdmin)") {
+			t.Error("diagnostics routes should be mounted after RequireAdmin")
+		}
+		if containsString(main, "usersController") {
+			t.Error("main.go should not reference user management without with_user_management")
+		}
+	})
+
+	t.Run("with_diagnostics requires with_auth", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName:     "diagapp",
+			ModulePath:      "github.com/test/diagapp",
+			WithDiagnostics: true,
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || !containsString(result.Message, "with_diagnostics requires with_auth") {
+			t.Errorf("expected with_auth error, got: %s", result.Message)
+		}
+	})
+
+	t.Run("postgres and mysql projects get the testcontainers harness", func(t *testing.T) {
+		for _, tt := range []struct{ dbType, module, image, engine string }{
+			{dbType: "postgres", module: "tcpostgres.Run(ctx, Image,", image: `const Image = "postgres:16-alpine"`, engine: "Postgres"},
+			{dbType: "mysql", module: "tcmysql.Run(ctx, Image,", image: `const Image = "mysql:8.4"`, engine: "MySQL"},
+		} {
+			t.Run(tt.dbType, func(t *testing.T) {
+				registry, tmpDir := testRegistry(t)
+				input := types.ScaffoldProjectInput{
+					ProjectName:  "tcapp",
+					ModulePath:   "github.com/test/tcapp",
+					DatabaseType: tt.dbType,
+				}
+
+				result, err := scaffoldProject(registry, input)
+				if err != nil || !result.Success {
+					t.Fatalf("unexpected failure: %v %s", err, result.Message)
+				}
+
+				checks := map[string][]string{
+					"internal/testutil/db.go": {"func DB(t testing.TB, models ...any) *gorm.DB", `const ContainerEnv = "TEST_DATABASE"`, tt.module, tt.image, `sqlite.Open(":memory:")`},
+					"go.mod": {
+						"github.com/testcontainers/testcontainers-go v0.34.0",
+						"github.com/testcontainers/testcontainers-go/modules/" + tt.dbType + " v0.34.0",
+						"gorm.io/driver/sqlite v1.5.6",
+					},
+					"Taskfile.yml": {
+						"test:integration:\n    desc: Run tests with repositories on a " + tt.engine + " container (requires Docker)\n    env:\n      TEST_DATABASE: container\n    cmds:\n      - go test ./...",
+						"test:repositories:",
+						"- go test ./internal/repository/...",
+					},
+				}
+				for path, wants := range checks {
+					content := readFile(t, tmpDir+"/tcapp/"+path)
+					for _, want := range wants {
+						if !containsString(content, want) {
+							t.Errorf("%s should contain %q", path, want)
+						}
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("sqlite projects get no testcontainers harness", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName: "tcapp",
+			ModulePath:  "github.com/test/tcapp",
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		if fileExists(tmpDir + "/tcapp/internal/testutil/db.go") {
+			t.Error("expected no internal/testutil/db.go in a SQLite project")
+		}
+		if containsString(readFile(t, tmpDir+"/tcapp/go.mod"), "testcontainers") {
+			t.Error("go.mod should not require testcontainers in a SQLite project")
+		}
+		if containsString(readFile(t, tmpDir+"/tcapp/Taskfile.yml"), "test:integration") {
+			t.Error("Taskfile.yml should have no test:integration task in a SQLite project")
+		}
+	})
+
+	t.Run("generates structured logging", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName: "loggedapp",
+			ModulePath:  "github.com/test/loggedapp",
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+
+		checks := map[string][]string{
+			"internal/logging/logging.go":   {"func Setup(cfg *config.Config) *slog.Logger", "func RequestID(", "func RequestLogger(", "func Recoverer("},
+			"internal/logging/gorm.go":      {"func NewGormLogger(", "SlowQueryThreshold"},
+			"internal/web/router.go":        {"r.Use(logging.RequestID)", "r.Use(logging.RequestLogger(logger))"},
+			"internal/database/database.go": {"logging.NewGormLogger(slog.Default())"},
+			"internal/config/config.go":     {"type LogConfig struct", `getEnv("LOG_FORMAT"`},
+			"cmd/web/main.go":               {"logger := logging.Setup(cfg)", "web.NewRouter(cfg, logger)"},
+			"config/en/app.toml":            {"[log]"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, tmpDir+"/loggedapp/"+path)
+			for _, want := range wants {
+				if !containsString(content, want) {
+					t.Errorf("%s should contain %q", path, want)
+				}
+			}
+		}
+
+		// Domains get the logger injected into each layer
+		domainResult, err := scaffoldDomain(NewRegistry(tmpDir+"/loggedapp"), types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+		})
+		if err != nil || !domainResult.Success {
+			t.Fatalf("unexpected failure: %v %s", err, domainResult.Message)
+		}
+		main := readFile(t, tmpDir+"/loggedapp/cmd/web/main.go")
+		for _, want := range []string{"NewRepository(db, logger)", "NewService(productRepo, logger)"} {
+			if !containsString(main, want) {
+				t.Errorf("main.go should contain %q", want)
+			}
+		}
+		if service := readFile(t, tmpDir+"/loggedapp/internal/services/product/product.go"); !containsString(service, `s.logger.InfoContext(ctx, "Product created"`) {
+			t.Error("services should log changes")
+		}
+	})
+
+	t.Run("boosted requests get full pages", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName: "navapp",
+			ModulePath:  "github.com/test/navapp",
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got: %s", result.Message)
+		}
+
+		content := readFile(t, tmpDir+"/navapp/internal/web/response.go")
+		if !containsString(content, "func (res *Response) IsBoosted() bool") {
+			t.Error("response.go should include IsBoosted")
+		}
+		if !containsString(content, "HX-History-Restore-Request") {
+			t.Error("IsHTMX should treat history restore requests as full page requests")
+		}
+	})
+
 	t.Run("middleware includes CSRF protection", func(t *testing.T) {
 		registry, tmpDir := testRegistry(t)
 		input := types.ScaffoldProjectInput{
@@ -552,6 +1029,319 @@ func TestScaffoldProject(t *testing.T) {
 		}
 	})
 
+	t.Run("generates the theme layer", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName: "themedapp",
+			ModulePath:  "github.com/test/themedapp",
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			"assets/css/input.css":                  {"--color-primary: var(--primary);", `[data-theme="blue"]`, `[data-radius="full"]`, `[data-density="compact"]`},
+			"config/en/app.toml":                    {"[theme]", `palette = "neutral"`},
+			"internal/config/config.go":             {"Theme    ThemeConfig", "Palettes []string"},
+			"internal/web/middleware/middleware.go": {"func Theme(theme config.ThemeConfig)", "func GetTheme(ctx context.Context) config.ThemeConfig"},
+			"internal/web/router.go":                {"r.Use(middleware.Theme(cfg.Theme))"},
+			"internal/web/layouts/base.templ":       {"data-theme={ middleware.GetTheme(ctx).Palette }", "@components.ThemeSwitcher("},
+			"internal/web/components/common.templ":  {"templ ThemeSwitcher(current string, palettes []string)"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, tmpDir+"/themedapp/"+path)
+			for _, want := range wants {
+				if !containsString(content, want) {
+					t.Errorf("%s should contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("with_dark_mode adds the color scheme toggle", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName:  "darkapp",
+			ModulePath:   "github.com/test/darkapp",
+			WithAuth:     true,
+			WithDarkMode: true,
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			"assets/css/input.css":                  {"@custom-variant dark (&:where(.dark, .dark *));", ".dark {"},
+			"config/en/app.toml":                    {`color_scheme = "system"`},
+			"internal/config/config.go":             {"ColorScheme string `toml:\"color_scheme\"`", `ColorScheme: "system",`},
+			"internal/web/middleware/middleware.go": {`const colorSchemeCookie = "color_scheme"`, "requestTheme.ColorScheme = cookie.Value"},
+			"internal/web/layouts/base.templ":       {"data-color-scheme={ middleware.GetTheme(ctx).ColorScheme }", "@components.ColorSchemeHead()", "@components.ColorSchemeToggle(middleware.GetTheme(ctx).ColorScheme)"},
+			"internal/web/auth/views/layout.templ":  {"data-color-scheme={ middleware.GetTheme(ctx).ColorScheme }", "@components.ColorSchemeHead()"},
+			"internal/web/components/common.templ":  {"templ ColorSchemeToggle(current string)", `document.cookie = 'color_scheme=' + scheme`},
+		}
+		for path, wants := range checks {
+			content := readFile(t, tmpDir+"/darkapp/"+path)
+			for _, want := range wants {
+				if !containsString(content, want) {
+					t.Errorf("%s should contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("dark mode follows the OS by default", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		input := types.ScaffoldProjectInput{
+			ProjectName: "lightapp",
+			ModulePath:  "github.com/test/lightapp",
+		}
+
+		result, err := scaffoldProject(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got: %s", result.Message)
+		}
+
+		if css := readFile(t, tmpDir+"/lightapp/assets/css/input.css"); !containsString(css, "@media (prefers-color-scheme: dark)") || containsString(css, "@custom-variant dark") {
+			t.Error("input.css should keep the media query dark mode without with_dark_mode")
+		}
+		if containsString(readFile(t, tmpDir+"/lightapp/internal/web/layouts/base.templ"), "ColorScheme") {
+			t.Error("base.templ should have no color scheme toggle without with_dark_mode")
+		}
+	})
+
+	t.Run("with_dark_mode is not available for API projects", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+		result, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+			ProjectName:  "darkapi",
+			ModulePath:   "github.com/test/darkapi",
+			ProjectType:  "api",
+			WithDarkMode: true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure")
+		}
+	})
+
+	t.Run("css_pipeline selects the CSS build", func(t *testing.T) {
+		tests := []struct {
+			pipeline string
+			checks   map[string][]string
+			absent   []string
+		}{
+			{
+				pipeline: "tailwind",
+				checks: map[string][]string{
+					"Taskfile.yml": {"npx @tailwindcss/cli@" + generator.StackVersion("tailwind")},
+				},
+				absent: []string{"package.json", "postcss.config.mjs"},
+			},
+			{
+				pipeline: "standalone",
+				checks: map[string][]string{
+					"Taskfile.yml": {`TAILWIND_CMD: ./bin/tailwindcss`, "tailwind:install:", "https://github.com/tailwindlabs/tailwindcss/releases/download/v{{.TAILWIND_VERSION}}/"},
+				},
+				absent: []string{"package.json", "postcss.config.mjs"},
+			},
+			{
+				pipeline: "daisyui",
+				checks: map[string][]string{
+					"Taskfile.yml":                         {"npm run build:css", "npm:install:"},
+					"package.json":                         {`"daisyui": "^5"`, `"@tailwindcss/cli": "` + generator.StackVersion("tailwind") + `"`},
+					"assets/css/input.css":                 {`@plugin "daisyui"`, "--color-primary-foreground: var(--color-primary-content);"},
+					"config/en/app.toml":                   {`palette = "light"`, `"dracula"`},
+					"internal/web/layouts/base.templ":      {`<ul class="menu w-full p-4">`, `templ.KV("menu-active", active)`, "https://cdn.jsdelivr.net/npm/daisyui@5"},
+					"internal/web/components/common.templ": {`classes := "btn"`, `fieldClasses("select", props.Error, props.Class)`},
+				},
+				absent: []string{"postcss.config.mjs"},
+			},
+			{
+				pipeline: "postcss",
+				checks: map[string][]string{
+					"Taskfile.yml":       {"npm run watch:css"},
+					"package.json":       {`"@tailwindcss/postcss": "` + generator.StackVersion("tailwind") + `"`, "NODE_ENV=production postcss"},
+					"postcss.config.mjs": {`"@tailwindcss/postcss": {}`},
+				},
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(tt.pipeline, func(t *testing.T) {
+				registry, tmpDir := testRegistry(t)
+				result, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+					ProjectName: "cssapp",
+					ModulePath:  "github.com/test/cssapp",
+					WithAuth:    true,
+					CSSPipeline: tt.pipeline,
+				})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !result.Success {
+					t.Fatalf("expected success, got: %s", result.Message)
+				}
+
+				for path, wants := range tt.checks {
+					content := readFile(t, tmpDir+"/cssapp/"+path)
+					for _, want := range wants {
+						if !containsString(content, want) {
+							t.Errorf("%s should contain %q", path, want)
+						}
+					}
+				}
+				for _, path := range tt.absent {
+					if fileExists(tmpDir + "/cssapp/" + path) {
+						t.Errorf("%s should not be generated", path)
+					}
+				}
+			})
+		}
+	})
+
+	t.Run("css_pipeline rejects invalid combinations", func(t *testing.T) {
+		inputs := []types.ScaffoldProjectInput{
+			{ProjectName: "cssapp", ModulePath: "github.com/test/cssapp", CSSPipeline: "sass"},
+			{ProjectName: "cssapi", ModulePath: "github.com/test/cssapi", ProjectType: "api", CSSPipeline: "postcss"},
+			{ProjectName: "cssapp", ModulePath: "github.com/test/cssapp", CSSPipeline: "daisyui", WithDarkMode: true},
+		}
+		for _, input := range inputs {
+			registry, _ := testRegistry(t)
+			result, err := scaffoldProject(registry, input)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success {
+				t.Errorf("expected failure for %+v", input)
+			}
+		}
+	})
+
+	t.Run("icon_set selects the icons of components.Icon", func(t *testing.T) {
+		for set, want := range map[string]string{
+			"":          `const iconStrokeWidth = "1.5"`,
+			"heroicons": `const iconStrokeWidth = "1.5"`,
+			"lucide":    `const iconStrokeWidth = "2"`,
+		} {
+			registry, tmpDir := testRegistry(t)
+			result, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+				ProjectName: "iconapp",
+				ModulePath:  "github.com/test/iconapp",
+				WithAuth:    true,
+				IconSet:     set,
+			})
+			if err != nil || !result.Success {
+				t.Fatalf("%q: scaffoldProject failed: %v %+v", set, err, result)
+			}
+
+			icons := readFile(t, tmpDir+"/iconapp/internal/web/components/icons.go")
+			for _, want := range []string{want, `case "shopping-cart":`, `case "arrow-right-on-rectangle":`} {
+				if !containsString(icons, want) {
+					t.Errorf("%q: icons.go should contain %q", set, want)
+				}
+			}
+			common := readFile(t, tmpDir+"/iconapp/internal/web/components/common.templ")
+			if !containsString(common, "@templ.Raw(iconSVG(name))") {
+				t.Errorf("%q: Icon should render the icons of icons.go", set)
+			}
+			layout := readFile(t, tmpDir+"/iconapp/internal/web/layouts/base.templ")
+			if !containsString(layout, `@components.Icon(item.Icon, "w-5 h-5")`) || containsString(layout, "navIcon") {
+				t.Errorf("%q: the sidebar should use components.Icon", set)
+			}
+		}
+
+		for _, input := range []types.ScaffoldProjectInput{
+			{ProjectName: "iconapp", ModulePath: "github.com/test/iconapp", IconSet: "feather"},
+			{ProjectName: "iconapi", ModulePath: "github.com/test/iconapi", ProjectType: "api", IconSet: "lucide"},
+		} {
+			registry, _ := testRegistry(t)
+			if result, _ := scaffoldProject(registry, input); result.Success {
+				t.Errorf("expected failure for %+v", input)
+			}
+		}
+	})
+
+	t.Run("js_bundler bundles htmx and Alpine", func(t *testing.T) {
+		for _, bundler := range []string{"esbuild", "vite"} {
+			t.Run(bundler, func(t *testing.T) {
+				registry, tmpDir := testRegistry(t)
+				result, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+					ProjectName: "jsapp",
+					ModulePath:  "github.com/test/jsapp",
+					WithAuth:    true,
+					Navigation:  "boost",
+					JSBundler:   bundler,
+				})
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if !result.Success {
+					t.Fatalf("expected success, got: %s", result.Message)
+				}
+
+				checks := map[string][]string{
+					bundler + ".config.mjs":                {`"assets/js/main.js"`, "assets/dist"},
+					"assets/js/main.js":                    {`import "./htmx.js";`, "Alpine.start();"},
+					"assets/js/htmx.js":                    {"window.htmx = htmx;"},
+					"package.json":                         {`"htmx.org": "` + generator.StackVersion("htmx") + `"`, `"alpinejs": "` + generator.StackVersion("alpine") + `"`, `"build:js": `, `"` + bundler + `": `},
+					"internal/web/assets/assets.go":        {"func Path(entryPoint string) string", `const manifestPath = "assets/dist/manifest.json"`},
+					"internal/web/router.go":               {`r.Handle("/assets/dist/*"`, "immutable"},
+					"internal/web/layouts/base.templ":      {`<script type="module" src={ assets.Path("assets/js/main.js") }></script>`, `<script type="module">`},
+					"internal/web/auth/views/layout.templ": {`assets.Path("assets/js/main.js")`},
+					"Taskfile.yml":                         {"npm run watch:js", "deps: [templ, tailwind, js]", "- task: js:build", "npm:install:"},
+					".gitignore":                           {"assets/dist/"},
+				}
+				for path, wants := range checks {
+					content := readFile(t, tmpDir+"/jsapp/"+path)
+					for _, want := range wants {
+						if !containsString(content, want) {
+							t.Errorf("%s should contain %q", path, want)
+						}
+					}
+				}
+
+				layout := readFile(t, tmpDir+"/jsapp/internal/web/layouts/base.templ")
+				if containsString(layout, "unpkg.com/htmx.org@") || containsString(layout, "unpkg.com/alpinejs@") {
+					t.Error("base.templ should not load htmx or Alpine from the CDN")
+				}
+				// The CSS keeps the default pipeline
+				if !containsString(readFile(t, tmpDir+"/jsapp/Taskfile.yml"), "npx @tailwindcss/cli@") {
+					t.Error("Taskfile.yml should keep building the CSS with the Tailwind CLI")
+				}
+			})
+		}
+	})
+
+	t.Run("js_bundler is not available for API projects", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+		result, err := scaffoldProject(registry, types.ScaffoldProjectInput{
+			ProjectName: "jsapi",
+			ModulePath:  "github.com/test/jsapi",
+			ProjectType: "api",
+			JSBundler:   "vite",
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure")
+		}
+	})
+
 	t.Run("auth templates include CSRF token", func(t *testing.T) {
 		registry, tmpDir := testRegistry(t)
 		input := types.ScaffoldProjectInput{
@@ -598,6 +1388,43 @@ func TestScaffoldProject(t *testing.T) {
 	})
 }
 
+func TestScaffoldProjectMonorepo(t *testing.T) {
+	_, repo := testRegistry(t)
+	writeTestFile(t, filepath.Join(repo, ".git", "HEAD"), "ref: refs/heads/main\n")
+	writeTestFile(t, filepath.Join(repo, "go.work"), "go 1.24\n\nuse ./services/api\n")
+	writeTestFile(t, filepath.Join(repo, ".gomcp.toml"), "[project]\nroot = \"apps/web\"\n")
+	web := filepath.Join(repo, "apps", "web")
+
+	registry := NewRegistry(repo)
+	if registry.WorkingDir != web {
+		t.Fatalf("expected the registry to work in %s, got %s", web, registry.WorkingDir)
+	}
+
+	result, err := scaffoldProject(registry, types.ScaffoldProjectInput{ProjectName: "shop", ModulePath: "github.com/acme/mono/apps/web"})
+	if err != nil || !result.Success {
+		t.Fatalf("scaffold_project failed: %v %s", err, result.Message)
+	}
+	for _, path := range []string{"go.mod", "cmd/web/main.go", ".mcp/scaffold-metadata.json"} {
+		if !fileExists(filepath.Join(web, path)) {
+			t.Errorf("expected apps/web/%s", path)
+		}
+	}
+	if fileExists(filepath.Join(repo, "cmd")) || dirExists(filepath.Join(web, "shop")) {
+		t.Error("expected the project to be generated in apps/web only")
+	}
+	if !strings.Contains(strings.Join(result.NextSteps, "\n"), "go work use ./apps/web") {
+		t.Errorf("expected a go.work next step, got %v", result.NextSteps)
+	}
+
+	result, err = scaffoldDomain(registry, types.ScaffoldDomainInput{DomainName: "product", Fields: []types.FieldDef{{Name: "Name", Type: "string"}}})
+	if err != nil || !result.Success {
+		t.Fatalf("scaffold_domain failed: %v %s", err, result.Message)
+	}
+	if main := readFile(t, filepath.Join(web, "cmd", "web", "main.go")); !strings.Contains(main, `"github.com/acme/mono/apps/web/internal/repository/product"`) {
+		t.Errorf("expected main.go of apps/web to import the product repository, got:\n%s", main)
+	}
+}
+
 // containsString checks if s contains substr
 func containsString(s, substr string) bool {
 	for i := 0; i <= len(s)-len(substr); i++ {
@@ -0,0 +1,369 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// sseExtensionVersion is the version of the htmx SSE extension, which connects the list views to the hub.
+const sseExtensionVersion = "2.2.2"
+
+// sseExtensionScript loads the htmx SSE extension from the CDN.
+const sseExtensionScript = `<script src="https://unpkg.com/htmx-ext-sse@` + sseExtensionVersion + `"></script>`
+
+// sseExtensionImport loads the htmx SSE extension in the assets/js bundle of js_bundler projects.
+const sseExtensionImport = `import "htmx-ext-sse";`
+
+// realtimeHubPath is the path of the realtime hub, the package scaffold_realtime generates.
+var realtimeHubPath = filepath.Join("internal", "realtime", "realtime.go")
+
+// RegisterScaffoldRealtime registers the scaffold_realtime tool.
+func RegisterScaffoldRealtime(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_realtime",
+		Description: `Add live updates: list views reload when a record is created, updated or deleted in another session.
+
+Generates:
+- internal/realtime/realtime.go: the Hub, which streams server-sent events (SSE) to the browsers.
+  Publish never blocks; idle streams get a heartbeat every 25s
+- internal/services/{domain}/realtime.go: NewRealtimeService, a Service decorator publishing
+  a {domain} event with the action (created, updated, deleted) and record ID after successful writes
+
+Wires main.go (the hub, the service decorators, the event streams and closing the streams on
+shutdown) and the base layout (the htmx SSE extension script; with js_bundler, an import in
+assets/js/main.js instead). Each domain's stream is mounted at GET /events/{path} (e.g.,
+/events/orders) in the domain's route group, behind the same middleware as its pages; the
+streams of domains owned by users only carry the session user's records. The list views of
+the domains connect to their stream with hx-ext="sse" and reload their current page, search
+and filters on each event, unless an inline edit is open.
+
+Events carry only the action and ID; the list reloads through its own routes, so a session
+never sees records it could not list. The hub keeps its subscribers in memory: apps running
+several instances need to relay events through a shared broker. Hubs generated before the
+per-domain streams must be deleted to be regenerated.
+
+domains lists the domains with live updates (default: every domain in the scaffold metadata).
+Their metadata records with_realtime, which new domains set with scaffold_domain with_realtime: true.
+Re-running the tool adds domains and keeps the existing files.
+
+Example:
+  scaffold_realtime: { domains: ["order", "ticket"] }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldRealtimeInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldRealtime)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldRealtime(registry *Registry, input types.ScaffoldRealtimeInput) (types.ScaffoldResult, error) {
+	// Get module path from go.mod
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	metaStore := metadata.NewStore(registry.WorkingDir)
+	meta, err := metaStore.Load()
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to read scaffold metadata: %v", err)), nil
+	}
+
+	var domains []string
+	if len(input.Domains) == 0 {
+		for name := range meta.Domains {
+			domains = append(domains, name)
+		}
+		sort.Strings(domains)
+	} else {
+		for _, name := range input.Domains {
+			if _, ok := meta.Domains[name]; !ok {
+				return types.NewErrorResult(fmt.Sprintf("no scaffold metadata for domain '%s': scaffold it with scaffold_domain first", name)), nil
+			}
+			domains = appendUnique(domains, name)
+		}
+	}
+
+	if realtimeHubOutdated(registry.WorkingDir) {
+		return types.NewErrorResult(outdatedRealtimeHubMessage), nil
+	}
+
+	// Create generator; existing realtime files are kept so re-runs only add domains
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+
+	if err := gen.GenerateFileIfNotExists("realtime/realtime.go.tmpl", realtimeHubPath, nil); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", realtimeHubPath, err)), nil
+	}
+	for _, name := range domains {
+		data := generator.NewDomainData(meta.Domains[name].Input, modulePath)
+		servicePath := realtimeServicePath(name)
+		if err := gen.GenerateFileIfNotExists("realtime/service_events.go.tmpl", servicePath, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", servicePath, err)), nil
+		}
+	}
+
+	result := gen.Result()
+
+	var unwired, unconnected []string
+	var bundled bool
+	if !input.DryRun {
+		mainGoPath := filepath.Join(registry.WorkingDir, "cmd", "web", "main.go")
+		if utils.FileExists(mainGoPath) {
+			inputs := make([]types.ScaffoldDomainInput, len(domains))
+			for i, name := range domains {
+				inputs[i] = meta.Domains[name].Input
+			}
+			var err error
+			unwired, err = injectRealtimeWiring(mainGoPath, modulePath, inputs)
+			if err != nil {
+				fmt.Printf("Warning: could not wire the realtime hub into main.go: %v\n", err)
+				unwired = domains
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, "cmd/web/main.go")
+			}
+		}
+
+		// js_bundler projects bundle htmx from assets/js rather than loading it from the CDN
+		mainJSPath := filepath.Join("assets", "js", "main.js")
+		bundled = utils.FileExists(filepath.Join(registry.WorkingDir, mainJSPath))
+		if bundled {
+			if injected, err := importSSEExtension(filepath.Join(registry.WorkingDir, mainJSPath)); err != nil {
+				fmt.Printf("Warning: could not import the htmx SSE extension in %s: %v\n", mainJSPath, err)
+			} else if injected {
+				result.FilesUpdated = append(result.FilesUpdated, mainJSPath)
+			}
+		} else if !registry.IsAPIProject() {
+			layoutPath := filepath.Join("internal", "web", "layouts", "base.templ")
+			if injected, err := injectSSEExtension(filepath.Join(registry.WorkingDir, layoutPath)); err != nil {
+				fmt.Printf("Warning: could not load the htmx SSE extension in the base layout: %v\n", err)
+			} else if injected {
+				result.FilesUpdated = append(result.FilesUpdated, layoutPath)
+			}
+		}
+
+		// Connect the list views and record with_realtime in the metadata
+		for _, name := range domains {
+			domain := meta.Domains[name]
+			if !registry.IsAPIProject() && domain.Input.GetWithCrudViews() {
+				listPath := filepath.Join("internal", "web", utils.ToPackageName(name), "views", "list.templ")
+				if patched, err := connectRealtimeList(filepath.Join(registry.WorkingDir, listPath), name); err != nil {
+					unconnected = append(unconnected, name)
+				} else if patched {
+					result.FilesUpdated = append(result.FilesUpdated, listPath)
+				}
+			}
+
+			if !domain.Input.WithRealtime {
+				domain.Input.WithRealtime = true
+				if err := metaStore.SaveDomain(name, domain.Input, ScaffolderVersion); err != nil {
+					fmt.Printf("Warning: failed to save metadata for %s: %v\n", name, err)
+				}
+			}
+		}
+	}
+
+	var nextSteps []string
+	if bundled {
+		nextSteps = append(nextSteps, "npm install htmx-ext-sse@"+sseExtensionVersion, "task js:build")
+	}
+	if !registry.IsAPIProject() {
+		nextSteps = append(nextSteps, "templ generate")
+	}
+	for _, name := range unwired {
+		nextSteps = append(nextSteps, realtimeWiringStep(meta.Domains[name].Input))
+	}
+	for _, name := range unconnected {
+		nextSteps = append(nextSteps, fmt.Sprintf("Connect the %s list container in internal/web/%s/views/list.templ:%s", name, utils.ToPackageName(name), generator.RealtimeListAttrs(utils.ToVariableName(name), name)))
+	}
+	nextSteps = append(nextSteps, "Publish other changes with realtimeHub.Publish(realtime.Event{Topic: \"order\", Action: \"shipped\", ID: order.ID})")
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would add live updates to %d domain(s)", len(domains)),
+			FilesCreated: result.FilesCreated,
+			FilesUpdated: result.FilesUpdated,
+			Backups:      result.Backups,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully added live updates to %d domain(s)", len(domains)),
+		FilesCreated: result.FilesCreated,
+		FilesUpdated: result.FilesUpdated,
+		Backups:      result.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// outdatedRealtimeHubMessage explains how to update a hub that realtimeHubOutdated reports.
+const outdatedRealtimeHubMessage = "internal/realtime/realtime.go predates the per-domain event streams, which keep the events of protected domains behind their route group: delete it to regenerate it, along with the realtime.go decorators of the domains owned by users"
+
+// realtimeHubOutdated reports whether the project's realtime hub predates Hub.Stream. Those
+// hubs streamed every topic at a public /events route.
+func realtimeHubOutdated(workingDir string) bool {
+	content, err := os.ReadFile(filepath.Join(workingDir, realtimeHubPath))
+	return err == nil && !strings.Contains(string(content), ") Stream(")
+}
+
+// realtimeWiringStep returns the next step wiring a domain's live updates into main.go by hand.
+func realtimeWiringStep(input types.ScaffoldDomainInput) string {
+	name := input.DomainName
+	varName := utils.ToServiceVariableName(name)
+	routeGroup, routerVar := input.RouteGroup, "r"
+	if routeGroup != "authenticated" && routeGroup != "admin" {
+		routeGroup, routerVar = "public", "router"
+	}
+	return fmt.Sprintf("Wire the %s live updates in cmd/web/main.go: %s = %s.NewRealtimeService(%s, %s), and mount the event stream in the %s routes: %s.%s",
+		name, varName, utils.ToServiceImportAlias(name), varName, modifier.RealtimeHubVariable, routeGroup, routerVar, modifier.RealtimeStreamRoute(name, input.GetOwnedByUser()))
+}
+
+// realtimeServicePath returns the path of a domain's realtime service decorator.
+func realtimeServicePath(domainName string) string {
+	return filepath.Join("internal", "services", utils.ToPackageName(domainName), "realtime.go")
+}
+
+// injectRealtimeWiring creates the hub in main.go, wraps the domain services, mounts their
+// event streams in their route groups and closes the streams on shutdown. It returns the
+// domains whose service was not found or whose stream could not be mounted.
+func injectRealtimeWiring(mainGoPath, modulePath string, domains []types.ScaffoldDomainInput) ([]string, error) {
+	content, err := os.ReadFile(mainGoPath)
+	if err != nil {
+		return nil, err
+	}
+
+	// Without closing the streams, Shutdown would wait for them until its timeout
+	source := string(content)
+	shutdown := fmt.Sprintf("server.RegisterOnShutdown(%s.Close)", modifier.RealtimeHubVariable)
+	if server := "\tserver := &http.Server{"; strings.Contains(source, server) && !strings.Contains(source, shutdown) {
+		start := strings.Index(source, server)
+		end := start + strings.Index(source[start:], "\n") + 1
+		source = source[:end] + "\t" + shutdown + "\n" + source[end:]
+	}
+	// Hubs that predate the per-domain streams served every topic at /events
+	if start := strings.Index(source, fmt.Sprintf(`router.Get("/events", %s.ServeHTTP)`, modifier.RealtimeHubVariable)); start != -1 {
+		start = strings.LastIndex(source[:start], "\n") + 1
+		end := start + strings.Index(source[start:], "\n") + 1
+		source = source[:start] + source[end:]
+	}
+
+	injector := modifier.NewInjectorFromContent(source)
+	if err := injector.InjectImport(modulePath + "/internal/realtime"); err != nil {
+		return nil, err
+	}
+	if err := injector.InjectBetweenMarkers(modifier.MarkerServicesStart, modifier.MarkerServicesEnd, modifier.RealtimeHubVariable+" := realtime.NewHub()"); err != nil {
+		return nil, err
+	}
+
+	var unwired []string
+	for _, input := range domains {
+		if err := injector.InjectRealtimeService(input.DomainName); err != nil {
+			unwired = append(unwired, input.DomainName)
+		} else if err := injector.InjectRealtimeRoute(input.DomainName, input.RouteGroup, input.GetOwnedByUser()); err != nil {
+			unwired = append(unwired, input.DomainName)
+		}
+	}
+	return unwired, injector.SaveTo(mainGoPath)
+}
+
+// injectRealtimeService wraps a domain service in main.go in its realtime decorator and mounts
+// the domain's event stream in its route group.
+func injectRealtimeService(mainGoPath string, input types.ScaffoldDomainInput) error {
+	injector, err := modifier.NewInjector(mainGoPath)
+	if err != nil {
+		return err
+	}
+	if err := injector.InjectRealtimeService(input.DomainName); err != nil {
+		return err
+	}
+	if err := injector.InjectRealtimeRoute(input.DomainName, input.RouteGroup, input.GetOwnedByUser()); err != nil {
+		return err
+	}
+	return injector.Save()
+}
+
+// injectSSEExtension loads the htmx SSE extension after htmx in the base layout. It reports
+// whether the layout was changed.
+func injectSSEExtension(layoutPath string) (bool, error) {
+	if !utils.FileExists(layoutPath) {
+		return false, nil
+	}
+	content, err := os.ReadFile(layoutPath)
+	if err != nil {
+		return false, err
+	}
+	source := string(content)
+	if strings.Contains(source, "htmx-ext-sse@") {
+		return false, nil
+	}
+
+	start := strings.Index(source, `<script src="https://unpkg.com/htmx.org@`)
+	if start == -1 {
+		return false, fmt.Errorf("htmx script not found")
+	}
+	lineStart := strings.LastIndex(source[:start], "\n") + 1
+	end := start + strings.Index(source[start:], "\n") + 1
+	source = source[:end] + source[lineStart:start] + sseExtensionScript + "\n" + source[end:]
+	return true, os.WriteFile(layoutPath, []byte(source), 0644)
+}
+
+// importSSEExtension imports the htmx SSE extension in the assets/js entry point, after
+// htmx.js sets window.htmx. It reports whether the entry point was changed.
+func importSSEExtension(mainJSPath string) (bool, error) {
+	content, err := os.ReadFile(mainJSPath)
+	if err != nil {
+		return false, err
+	}
+	source := string(content)
+	if strings.Contains(source, sseExtensionImport) {
+		return false, nil
+	}
+
+	htmxImport := `import "./htmx.js";` + "\n"
+	if !strings.Contains(source, htmxImport) {
+		return false, fmt.Errorf("htmx.js import not found")
+	}
+	source = strings.Replace(source, htmxImport, htmxImport+sseExtensionImport+"\n", 1)
+	return true, os.WriteFile(mainJSPath, []byte(source), 0644)
+}
+
+// connectRealtimeList adds the realtime attributes to the list container of a domain's list
+// view. It reports whether the view was changed.
+func connectRealtimeList(listPath, domainName string) (bool, error) {
+	content, err := os.ReadFile(listPath)
+	if err != nil {
+		return false, err
+	}
+	source := string(content)
+	// Views connected before the per-domain streams read the public /events route
+	if old := fmt.Sprintf(`sse-connect="/events?topic=%s"`, utils.ToSnakeCase(domainName)); strings.Contains(source, old) {
+		source = strings.Replace(source, old, fmt.Sprintf(`sse-connect="%s"`, utils.ToRealtimeStreamPath(domainName)), 1)
+		return true, os.WriteFile(listPath, []byte(source), 0644)
+	}
+	if strings.Contains(source, "sse-connect=") {
+		return false, nil
+	}
+
+	varName := utils.ToVariableName(domainName)
+	container := fmt.Sprintf(`<div id="%s-list"`, varName)
+	if !strings.Contains(source, container) {
+		return false, fmt.Errorf("list container not found")
+	}
+	source = strings.Replace(source, container, container+generator.RealtimeListAttrs(varName, domainName), 1)
+	return true, os.WriteFile(listPath, []byte(source), 0644)
+}
@@ -0,0 +1,318 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldRealtime(t *testing.T) {
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, `package main
+
+import (
+	"log"
+	"net/http"
+)
+
+func main() {
+	db := database.Connect(cfg)
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+
+	router := web.NewRouter(cfg)
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	// MCP:ROUTES:END
+
+	server := &http.Server{Addr: cfg.Server.Address, Handler: router}
+	log.Fatal(server.ListenAndServe())
+}
+`)
+		layoutPath := filepath.Join(tmpDir, "internal", "web", "layouts", "base.templ")
+		if err := os.MkdirAll(filepath.Dir(layoutPath), 0755); err != nil {
+			t.Fatalf("failed to create layouts dir: %v", err)
+		}
+		layout := "package layouts\n\ntempl Base() {\n\t<head>\n\t\t<script src=\"https://unpkg.com/htmx.org@2.0.0\"></script>\n\t</head>\n}\n"
+		if err := os.WriteFile(layoutPath, []byte(layout), 0644); err != nil {
+			t.Fatalf("failed to write base.templ: %v", err)
+		}
+
+		domains := []types.ScaffoldDomainInput{
+			{DomainName: "order", Fields: []types.FieldDef{{Name: "Total", Type: "float64"}}, WithBulkActions: true},
+			{DomainName: "blog_post", Fields: []types.FieldDef{{Name: "Title", Type: "string"}}},
+		}
+		for _, input := range domains {
+			if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+				t.Fatalf("failed to scaffold %s: %v %s", input.DomainName, err, result.Message)
+			}
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("generates the hub and connects the domains", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := scaffoldRealtime(registry, types.ScaffoldRealtimeInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "realtime", "realtime.go"):             {"func (h *Hub) Publish(event Event)", "func (h *Hub) Stream(topic string, user func(*http.Request) uint) http.HandlerFunc {"},
+			filepath.Join("internal", "services", "order", "realtime.go"):    {`const realtimeTopic = "order"`, "func (s *realtimeService) BulkDelete("},
+			filepath.Join("internal", "services", "blogpost", "realtime.go"): {`const realtimeTopic = "blog_post"`, `Action: "deleted", ID: id`},
+			filepath.Join("internal", "web", "order", "views", "list.templ"): {`<div id="order-list" hx-ext="sse" sse-connect="/events/orders" hx-trigger="sse:order"`},
+			filepath.Join("internal", "web", "layouts", "base.templ"):        {"\t\t<script src=\"https://unpkg.com/htmx.org@2.0.0\"></script>\n\t\t<script src=\"https://unpkg.com/htmx-ext-sse@"},
+			filepath.Join("cmd", "web", "main.go"): {
+				"realtimeHub := realtime.NewHub()",
+				"orderService = ordersvc.NewRealtimeService(orderService, realtimeHub)",
+				"blogPostService = blogpostsvc.NewRealtimeService(blogPostService, realtimeHub)",
+				"\trouter.Get(\"/events/blog-posts\", realtimeHub.Stream(\"blog_post\", nil))\n\trouter.Get(\"/events/orders\", realtimeHub.Stream(\"order\", nil))\n",
+				"server := &http.Server{Addr: cfg.Server.Address, Handler: router}\n\tserver.RegisterOnShutdown(realtimeHub.Close)\n",
+				`"github.com/example/testapp/internal/realtime"`,
+			},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q, got:\n%s", path, want, content)
+				}
+			}
+		}
+
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, "internal", "services", "blogpost", "realtime.go")), "BulkDelete") {
+			t.Error("expected no BulkDelete for a domain without bulk actions")
+		}
+
+		// The patched list matches the template rendered with with_realtime
+		sync, err := syncDomain(registry, types.SyncDomainInput{Domain: "order"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		for _, path := range sync.FilesUpdated {
+			if strings.HasSuffix(path, "list.templ") || strings.HasSuffix(path, "realtime.go") {
+				t.Errorf("expected %s to be in sync", path)
+			}
+		}
+	})
+
+	t.Run("re-running does not duplicate wiring", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		for i := 0; i < 2; i++ {
+			if result, err := scaffoldRealtime(registry, types.ScaffoldRealtimeInput{Domains: []string{"order"}}); err != nil || !result.Success {
+				t.Fatalf("run %d failed: %v %s", i+1, err, result.Message)
+			}
+		}
+
+		counts := map[string]string{
+			filepath.Join("cmd", "web", "main.go"):                           "realtimeHub.Close",
+			filepath.Join("internal", "web", "layouts", "base.templ"):        "htmx-ext-sse@",
+			filepath.Join("internal", "web", "order", "views", "list.templ"): "sse-connect=",
+		}
+		for path, want := range counts {
+			if n := strings.Count(readFile(t, filepath.Join(tmpDir, path)), want); n != 1 {
+				t.Errorf("expected %s to contain %q once, got %d", path, want, n)
+			}
+		}
+		if n := strings.Count(readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go")), "NewRealtimeService(orderService"); n != 1 {
+			t.Errorf("expected the order service to be wrapped once, got %d", n)
+		}
+		if fileExists(filepath.Join(tmpDir, "internal", "services", "blogpost", "realtime.go")) {
+			t.Error("expected only the listed domains to publish events")
+		}
+	})
+
+	t.Run("scaffold_domain with_realtime", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		input := types.ScaffoldDomainInput{DomainName: "ticket", Fields: []types.FieldDef{{Name: "Subject", Type: "string"}}, WithRealtime: true}
+
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Fatal("expected with_realtime to require the realtime hub")
+		}
+
+		if result, err := scaffoldRealtime(registry, types.ScaffoldRealtimeInput{Domains: []string{"order"}}); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold realtime: %v %s", err, result.Message)
+		}
+		if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold ticket: %v %s", err, result.Message)
+		}
+
+		if !strings.Contains(readFile(t, filepath.Join(tmpDir, "internal", "services", "ticket", "realtime.go")), `const realtimeTopic = "ticket"`) {
+			t.Error("expected the ticket service decorator")
+		}
+		if !strings.Contains(readFile(t, filepath.Join(tmpDir, "internal", "web", "ticket", "views", "list.templ")), `sse-connect="/events/tickets"`) {
+			t.Error("expected the ticket list to connect to the hub")
+		}
+		mainGo := readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go"))
+		if !strings.Contains(mainGo, "ticketService = ticketsvc.NewRealtimeService(ticketService, realtimeHub)") {
+			t.Error("expected the ticket service to be wrapped")
+		}
+		if !strings.Contains(mainGo, `router.Get("/events/tickets", realtimeHub.Stream("ticket", nil))`) {
+			t.Error("expected the ticket event stream to be mounted")
+		}
+	})
+
+	t.Run("keeps the streams of protected domains behind their route group", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, `package main
+
+import (
+	"net/http"
+)
+
+func main() {
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	router.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAuth)
+		// MCP:ROUTES:AUTHENTICATED:START
+		// MCP:ROUTES:AUTHENTICATED:END
+	})
+	// MCP:ROUTES:END
+}
+`)
+		// Owned domains need the session user of an auth project
+		authPath := filepath.Join(tmpDir, "internal", "web", "middleware", "auth.go")
+		if err := os.MkdirAll(filepath.Dir(authPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(authPath, []byte("package middleware\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		input := types.ScaffoldDomainInput{DomainName: "order", Fields: []types.FieldDef{{Name: "Total", Type: "float64"}}, RouteGroup: "authenticated", WithBulkActions: true}
+		if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold order: %v %s", err, result.Message)
+		}
+
+		if result, err := scaffoldRealtime(registry, types.ScaffoldRealtimeInput{}); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold realtime: %v %s", err, result.Message)
+		}
+
+		mainGo := readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go"))
+		if !strings.Contains(mainGo, "\t\tr.Get(\"/events/orders\", realtimeHub.Stream(\"order\", realtimeUser))\n\t\t// MCP:ROUTES:AUTHENTICATED:END\n") {
+			t.Errorf("expected the order stream in the authenticated group, got:\n%s", mainGo)
+		}
+		if !strings.Contains(mainGo, "realtimeUser := func(r *http.Request) uint {") {
+			t.Error("expected the session user of the owned streams")
+		}
+		service := readFile(t, filepath.Join(tmpDir, "internal", "services", "order", "realtime.go"))
+		for _, want := range []string{
+			`Action: "created", ID: order.ID, UserID: order.UserID})`,
+			"if order, err := s.Service.GetByID(ctx, id); err == nil {",
+			`Action: "deleted", ID: id, UserID: owners[id]})`,
+		} {
+			if !strings.Contains(service, want) {
+				t.Errorf("expected the owned service decorator to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("updates projects from before the per-domain streams", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		hubPath := filepath.Join(tmpDir, "internal", "realtime", "realtime.go")
+		if err := os.MkdirAll(filepath.Dir(hubPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(hubPath, []byte("package realtime\n\nfunc (h *Hub) ServeHTTP(w http.ResponseWriter, r *http.Request) {}\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := scaffoldRealtime(registry, types.ScaffoldRealtimeInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || !strings.Contains(result.Message, "delete it to regenerate it") {
+			t.Fatalf("expected the outdated hub to be refused, got: %s", result.Message)
+		}
+
+		// Once the hub is deleted, the public route and the list attributes are updated
+		if err := os.Remove(hubPath); err != nil {
+			t.Fatal(err)
+		}
+		mainGoPath := filepath.Join(tmpDir, "cmd", "web", "main.go")
+		mainGo := strings.Replace(readFile(t, mainGoPath), "\t// MCP:ROUTES:PUBLIC:END", "\trouter.Get(\"/events\", realtimeHub.ServeHTTP)\n\t// MCP:ROUTES:PUBLIC:END", 1)
+		if err := os.WriteFile(mainGoPath, []byte(mainGo), 0644); err != nil {
+			t.Fatal(err)
+		}
+		listPath := filepath.Join(tmpDir, "internal", "web", "order", "views", "list.templ")
+		list := strings.Replace(readFile(t, listPath), `<div id="order-list"`, `<div id="order-list" hx-ext="sse" sse-connect="/events?topic=order"`, 1)
+		if err := os.WriteFile(listPath, []byte(list), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		if result, err := scaffoldRealtime(registry, types.ScaffoldRealtimeInput{Domains: []string{"order"}}); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold realtime: %v %s", err, result.Message)
+		}
+		if strings.Contains(readFile(t, mainGoPath), "ServeHTTP") {
+			t.Error("expected the public /events route to be removed")
+		}
+		if list := readFile(t, listPath); !strings.Contains(list, `sse-connect="/events/orders"`) || strings.Contains(list, "topic=") {
+			t.Errorf("expected the list to connect to the order stream, got:\n%s", list)
+		}
+	})
+
+	t.Run("imports the SSE extension in the js_bundler entry point", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		mainJSPath := filepath.Join(tmpDir, "assets", "js", "main.js")
+		if err := os.MkdirAll(filepath.Dir(mainJSPath), 0755); err != nil {
+			t.Fatalf("failed to create assets/js: %v", err)
+		}
+		if err := os.WriteFile(mainJSPath, []byte("import \"./htmx.js\";\nimport Alpine from \"alpinejs\";\n"), 0644); err != nil {
+			t.Fatalf("failed to write main.js: %v", err)
+		}
+
+		for i := 0; i < 2; i++ {
+			if result, err := scaffoldRealtime(registry, types.ScaffoldRealtimeInput{Domains: []string{"order"}}); err != nil || !result.Success {
+				t.Fatalf("run %d failed: %v %s", i+1, err, result.Message)
+			}
+		}
+
+		if got := readFile(t, mainJSPath); got != "import \"./htmx.js\";\nimport \"htmx-ext-sse\";\nimport Alpine from \"alpinejs\";\n" {
+			t.Errorf("expected the extension to be imported once after htmx.js, got:\n%s", got)
+		}
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, "internal", "web", "layouts", "base.templ")), "htmx-ext-sse@") {
+			t.Error("expected no CDN script when htmx is bundled")
+		}
+	})
+
+	t.Run("rejects unknown domains", func(t *testing.T) {
+		registry, _ := setup(t)
+		result, err := scaffoldRealtime(registry, types.ScaffoldRealtimeInput{Domains: []string{"invoice"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure")
+		}
+	})
+}
@@ -29,7 +29,7 @@ NOTE: The 'user' domain created by scaffold_project --with_auth has a custom rep
 with specialized methods (FindByEmail, ExistsByEmail, UpdateLastLogin, etc.). Do not
 overwrite it with this tool - extend it manually if needed.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldRepositoryInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldRepository(registry, input)
+		result, err := locked(scaffoldRepository)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -69,6 +69,7 @@ func scaffoldRepository(registry *Registry, input types.ScaffoldRepositoryInput)
 		VariableName: utils.ToVariableName(input.DomainName),
 		TableName:    utils.ToTableName(input.DomainName),
 		URLPath:      utils.ToURLPath(input.DomainName),
+		WithLogging:  registry.HasStructuredLogging(),
 	}
 
 	// Create directory
@@ -0,0 +1,250 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterScaffoldSearchIndex registers the scaffold_search_index tool.
+func RegisterScaffoldSearchIndex(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_search_index",
+		Description: `Index a domain in an external search engine (Meilisearch or Typesense) and add a search page.
+
+Generates:
+- internal/search/search.go: Indexer interface, Document/Query/Results types and NewFromEnv
+- internal/search/meilisearch.go and typesense.go: HTTP clients for both engines, with typo-tolerant
+  queries (one typo from 4 letters, two from 8) and prefix matching on the last word
+- internal/search/{domain}_index.go: index name, searchable fields, document mapping and Reindex{Models}
+- internal/services/{domain}/search_index.go: WithSearchIndex, a service wrapper that indexes on
+  create/update and removes on delete (moderated domains only index approved records)
+- internal/web/{domain}/search.go + views/search.templ: GET /{domains}/search?q= (JSON without CRUD views)
+- cmd/reindex/main.go: rebuilds indexes from the database (go run ./cmd/reindex [domain...])
+- docker-compose.search.yml: the engine's service for local development
+
+Wires main.go: creates searchIndexer, wraps the domain service and mounts the search route in the
+domain's route group.
+
+The engine is chosen at run time with SEARCH_ENGINE (default: the engine parameter), SEARCH_URL
+and SEARCH_API_KEY. fields lists the searchable string fields; by default all string fields.
+
+The domain must have been created with scaffold_domain (it is read from .mcp/scaffold-metadata.json).
+Re-running the tool regenerates the domain's index files, e.g. to change the searchable fields.
+
+Example:
+  scaffold_search_index: { domain: "product", engine: "typesense", fields: ["Name", "Description"] }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldSearchIndexInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldSearchIndex)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldSearchIndex(registry *Registry, input types.ScaffoldSearchIndexInput) (types.ScaffoldResult, error) {
+	if err := utils.ValidateDomainName(input.Domain); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
+	engine := input.Engine
+	if engine == "" {
+		engine = "meilisearch"
+	}
+	if engine != "meilisearch" && engine != "typesense" {
+		return types.NewErrorResult(fmt.Sprintf("invalid engine '%s': must be meilisearch or typesense", input.Engine)), nil
+	}
+
+	if registry.UsesWire() {
+		return types.NewErrorResult("scaffold_search_index wraps the service in cmd/web/main.go, which projects with di wire don't support yet"), nil
+	}
+
+	// Get module path from go.mod
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	metaStore := metadata.NewStore(registry.WorkingDir)
+	meta, err := metaStore.Load()
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to read scaffold metadata: %v", err)), nil
+	}
+	domain, ok := meta.Domains[input.Domain]
+	if !ok {
+		return types.NewErrorResult(fmt.Sprintf("no scaffold metadata for domain '%s': scaffold it with scaffold_domain first", input.Domain)), nil
+	}
+
+	domainData := generator.NewDomainData(domain.Input, modulePath)
+	searchFields, err := searchIndexFields(domainData.Fields, input.Fields)
+	if err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
+	data := generator.SearchIndexData{
+		DomainData:   domainData,
+		Engine:       engine,
+		SearchFields: searchFields,
+	}
+	pkgName := data.PackageName
+
+	// Create generator; the domain's files are regenerated on every run
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+	gen.SetForceOverwrite(true)
+
+	type file struct {
+		template string
+		output   string
+	}
+
+	// Shared search package and reindex command
+	searchDir := filepath.Join("internal", "search")
+	sharedFiles := []file{
+		{"search/search.go.tmpl", filepath.Join(searchDir, "search.go")},
+		{"search/meilisearch.go.tmpl", filepath.Join(searchDir, "meilisearch.go")},
+		{"search/typesense.go.tmpl", filepath.Join(searchDir, "typesense.go")},
+		{"search/reindex_main.go.tmpl", filepath.Join("cmd", "reindex", "main.go")},
+		{"search/compose.yml.tmpl", "docker-compose.search.yml"},
+	}
+	for _, f := range sharedFiles {
+		if err := gen.GenerateFileIfNotExists(f.template, f.output, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.output, err)), nil
+		}
+	}
+
+	// Domain index, service wrapper, controller and view
+	domainFiles := []file{
+		{"search/index.go.tmpl", filepath.Join(searchDir, utils.ToSnakeCase(input.Domain)+"_index.go")},
+		{"search/service.go.tmpl", filepath.Join("internal", "services", pkgName, "search_index.go")},
+		{"search/controller.go.tmpl", filepath.Join("internal", "web", pkgName, "search.go")},
+	}
+	if data.WithCrudViews {
+		domainFiles = append(domainFiles, file{"search/view.templ.tmpl", filepath.Join("internal", "web", pkgName, "views", "search.templ")})
+	}
+	for _, f := range domainFiles {
+		if err := gen.GenerateFile(f.template, f.output, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.output, err)), nil
+		}
+	}
+
+	result := gen.Result()
+
+	// Wire main.go and register the reindexer
+	if !input.DryRun {
+		mainGoPath := filepath.Join(registry.WorkingDir, "cmd", "web", "main.go")
+		if utils.FileExists(mainGoPath) {
+			if err := injectSearchWiring(mainGoPath, modulePath, input.Domain, data.RouteGroup); err != nil {
+				fmt.Printf("Warning: could not inject search wiring: %v\n", err)
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, "cmd/web/main.go")
+			}
+		}
+
+		reindexPath := filepath.Join(registry.WorkingDir, "cmd", "reindex", "main.go")
+		injector, err := modifier.NewInjector(reindexPath)
+		if err == nil {
+			err = injector.InjectReindexer(input.Domain)
+		}
+		if err == nil {
+			err = injector.Save()
+		}
+		if err != nil {
+			fmt.Printf("Warning: could not register reindexer: %v\n", err)
+		}
+	}
+
+	nextSteps := []string{
+		"templ generate",
+	}
+	nextSteps = append(nextSteps, "Start the search engine: docker compose -f docker-compose.search.yml up -d")
+	if engine == "typesense" {
+		nextSteps = append(nextSteps, "export SEARCH_API_KEY=dev (and SEARCH_URL if not http://localhost:8108)")
+	} else {
+		nextSteps = append(nextSteps, "Set SEARCH_URL and SEARCH_API_KEY if not http://localhost:7700 without a key")
+	}
+	nextSteps = append(nextSteps,
+		fmt.Sprintf("go run ./cmd/reindex %s", input.Domain),
+		fmt.Sprintf("Search at %s/search?q=", utils.ToURLPath(input.Domain)),
+	)
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would create %s search index for '%s'", engine, input.Domain),
+			FilesCreated: result.FilesCreated,
+			FilesUpdated: result.FilesUpdated,
+			Backups:      result.Backups,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully created %s search index for '%s'", engine, input.Domain),
+		FilesCreated: result.FilesCreated,
+		FilesUpdated: result.FilesUpdated,
+		Backups:      result.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// searchIndexFields returns the searchable fields: the named ones, or every string field.
+func searchIndexFields(fields []generator.FieldData, names []string) ([]generator.FieldData, error) {
+	var searchFields []generator.FieldData
+	if len(names) == 0 {
+		for _, field := range fields {
+			if field.Type == "string" && !field.IsEmbedded && field.FormType != "image" {
+				searchFields = append(searchFields, field)
+			}
+		}
+		if len(searchFields) == 0 {
+			return nil, fmt.Errorf("the domain has no string fields to search; list them in fields")
+		}
+		return searchFields, nil
+	}
+
+	for _, name := range names {
+		var found *generator.FieldData
+		for i := range fields {
+			if fields[i].Name == name {
+				found = &fields[i]
+				break
+			}
+		}
+		if found == nil {
+			return nil, fmt.Errorf("fields: unknown field '%s'", name)
+		}
+		if found.Type != "string" {
+			return nil, fmt.Errorf("fields: '%s' is %s; only string fields are searchable", name, found.Type)
+		}
+		searchFields = append(searchFields, *found)
+	}
+	return searchFields, nil
+}
+
+// injectSearchWiring wires the domain's search index and route into main.go.
+func injectSearchWiring(mainGoPath, modulePath, domainName, routeGroup string) error {
+	injector, err := modifier.NewInjector(mainGoPath)
+	if err != nil {
+		return err
+	}
+	if err := injector.InjectImport(modulePath + "/internal/search"); err != nil {
+		return err
+	}
+	if err := injector.InjectSearchIndex(domainName); err != nil {
+		return err
+	}
+	if err := injector.InjectSearchRoute(domainName, routeGroup); err != nil {
+		return err
+	}
+	return injector.Save()
+}
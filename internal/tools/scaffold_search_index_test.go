@@ -0,0 +1,148 @@
+package tools
+
+import (
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldSearchIndex(t *testing.T) {
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, `package main
+
+import (
+	"log"
+)
+
+func main() {
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	// MCP:ROUTES:END
+}
+`)
+
+		input := types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string"},
+				{Name: "Description", Type: "string", FormType: "textarea"},
+				{Name: "Price", Type: "float64"},
+			},
+		}
+		if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("generates indexer, hooks, search page and reindex command", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := scaffoldSearchIndex(registry, types.ScaffoldSearchIndexInput{Domain: "product", Engine: "typesense"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "search", "search.go"):                     {"type Indexer interface", `getEnv("SEARCH_ENGINE", "typesense")`},
+			filepath.Join("internal", "search", "meilisearch.go"):                {"func NewMeilisearch("},
+			filepath.Join("internal", "search", "typesense.go"):                  {"func NewTypesense(", `params.Set("num_typos", "2")`},
+			"docker-compose.search.yml":                                          {"image: typesense/typesense:27.1", "--api-key=${SEARCH_API_KEY:-dev}"},
+			filepath.Join("internal", "search", "product_index.go"):              {`var ProductSearchFields = []string{"name", "description"}`, "func ReindexProducts("},
+			filepath.Join("internal", "services", "product", "search_index.go"):  {"func WithSearchIndex(svc Service, indexer search.Indexer) Service"},
+			filepath.Join("internal", "web", "product", "search.go"):             {"func (c *SearchController) Search("},
+			filepath.Join("internal", "web", "product", "views", "search.templ"): {"templ ProductSearchResults("},
+			filepath.Join("cmd", "reindex", "main.go"):                           {`"product": search.ReindexProducts,`},
+			filepath.Join("cmd", "web", "main.go"):                               {"productService = productsvc.WithSearchIndex(productService, searchIndexer)", `router.Get("/products/search", productSearchController.Search)`},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("limits the index to the given fields", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := scaffoldSearchIndex(registry, types.ScaffoldSearchIndexInput{Domain: "product", Fields: []string{"Name"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "internal", "search", "product_index.go"))
+		if !strings.Contains(content, `var ProductSearchFields = []string{"name"}`) {
+			t.Errorf("expected only name to be searchable, got:\n%s", content)
+		}
+	})
+
+	t.Run("backs up the files a rerun overwrites", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		indexPath := filepath.Join("internal", "search", "product_index.go")
+
+		if result, err := scaffoldSearchIndex(registry, types.ScaffoldSearchIndexInput{Domain: "product"}); err != nil || !result.Success {
+			t.Fatalf("first run failed: %v %s", err, result.Message)
+		}
+		writeTestFile(t, filepath.Join(tmpDir, indexPath), "package search // edited\n")
+
+		result, err := scaffoldSearchIndex(registry, types.ScaffoldSearchIndexInput{Domain: "product", Fields: []string{"Name"}})
+		if err != nil || !result.Success {
+			t.Fatalf("rerun failed: %v %s", err, result.Message)
+		}
+		if !slices.Contains(result.FilesUpdated, indexPath) {
+			t.Errorf("expected %s in FilesUpdated, got %v", indexPath, result.FilesUpdated)
+		}
+		backup := result.Backups[indexPath]
+		if !strings.HasPrefix(backup, filepath.Join(".mcp", "backups")) {
+			t.Fatalf("expected the backup of %s in Backups, got %v", indexPath, result.Backups)
+		}
+		if content := readFile(t, filepath.Join(tmpDir, backup)); content != "package search // edited\n" {
+			t.Errorf("backup should hold the previous version, got %q", content)
+		}
+	})
+
+	t.Run("validates input", func(t *testing.T) {
+		registry, _ := setup(t)
+
+		tests := []struct {
+			name  string
+			input types.ScaffoldSearchIndexInput
+		}{
+			{"unknown engine", types.ScaffoldSearchIndexInput{Domain: "product", Engine: "solr"}},
+			{"unknown domain", types.ScaffoldSearchIndexInput{Domain: "order"}},
+			{"unknown field", types.ScaffoldSearchIndexInput{Domain: "product", Fields: []string{"Sku"}}},
+			{"non-string field", types.ScaffoldSearchIndexInput{Domain: "product", Fields: []string{"Price"}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := scaffoldSearchIndex(registry, tt.input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success {
+					t.Errorf("expected failure for %s", tt.name)
+				}
+			})
+		}
+	})
+}
@@ -3,9 +3,13 @@ package tools
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
 	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
 	"github.com/dbb1dev/go-mcp/internal/types"
 	"github.com/dbb1dev/go-mcp/internal/utils"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -26,6 +30,11 @@ Features:
   - distribute: Spread records evenly across related records
   - each: Create one record per related record
 - distributions: Specify value distributions for fields (e.g., 2 admins, 5 users)
+- source: seed the rows of a CSV or JSON fixture file instead (e.g., "fixtures/products.csv")
+  - Columns map to model fields by name ("unit_price", "UnitPrice" or "Unit Price"), with their
+    text converted to the field's type; foreign keys are columns too (category_id)
+  - Rows are upserted on key (default: the first unique field, or ID), so seeding twice is safe
+  - Fields default to those of the scaffolded domain; the file is read when the seeder runs
 
 Example with relationships:
   relationships: [{"field": "UserID", "model": "User", "strategy": "random"}]
@@ -33,9 +42,15 @@ Example with relationships:
 Example with distributions:
   distributions: [{"field": "Role", "values": [{"value": "\"admin\"", "count": 2}, {"value": "\"user\"", "count": 8}]}]
 
-Register the seeder in cmd/seed/main.go after generating.`,
+Example with a fixture file:
+  source: "fixtures/products.csv", key: "Sku"
+
+The seeder is registered in cmd/seed/seeders/seeders.go, generated with its runner when missing.
+go run ./cmd/seed runs the registered seeders in dependency order, and clears them in reverse
+order with -clear; a dependency cycle stops it with the seeders of the cycle. Dependencies that
+are not registered seeders (e.g., "user" of the auth system) are assumed to be seeded already.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldSeedInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldSeed(registry, input)
+		result, err := locked(scaffoldSeed)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -54,6 +69,9 @@ func scaffoldSeed(registry *Registry, input types.ScaffoldSeedInput) (types.Scaf
 		if err := utils.ValidateDomainName(dep); err != nil {
 			return types.NewErrorResult(fmt.Sprintf("dependency '%s': %v", dep, err)), nil
 		}
+		if dep == input.Domain {
+			return types.NewErrorResult(fmt.Sprintf("dependency '%s': a seeder can't depend on itself", dep)), nil
+		}
 	}
 
 	// Validate fields if provided
@@ -66,12 +84,28 @@ func scaffoldSeed(registry *Registry, input types.ScaffoldSeedInput) (types.Scaf
 		}
 	}
 
+	// Fixture seeders take their values from the file
+	if input.Source != "" {
+		if err := validateFixtureSource(input); err != nil {
+			return types.NewErrorResult(err.Error()), nil
+		}
+	}
+
 	// Get module path from go.mod
 	modulePath, err := utils.GetModulePath(registry.WorkingDir)
 	if err != nil {
 		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
 	}
 
+	// Columns of fixtures map to the fields of the scaffolded model, foreign keys included
+	domain, hasDomain, _ := metadata.NewStore(registry.WorkingDir).GetDomain(input.Domain)
+	if input.Source != "" && len(input.Fields) == 0 {
+		if !hasDomain {
+			return types.NewErrorResult(fmt.Sprintf("source needs the fields of the model: pass fields, or scaffold domain '%s' with scaffold_domain first", input.Domain)), nil
+		}
+		input.Fields = fixtureFields(domain.Input)
+	}
+
 	// Create generator
 	gen := registry.NewGenerator("")
 	gen.SetDryRun(input.DryRun)
@@ -79,6 +113,24 @@ func scaffoldSeed(registry *Registry, input types.ScaffoldSeedInput) (types.Scaf
 	// Prepare template data
 	data := buildSeedData(input, modulePath)
 
+	// Seeders of tenant-scoped domains seed every tenant
+	if hasDomain {
+		data.TenantScoped = domain.Input.TenantScoped
+	}
+
+	if input.Source != "" {
+		keyField, err := fixtureKey(input)
+		if err != nil {
+			return types.NewErrorResult(err.Error()), nil
+		}
+		data.Source = filepath.ToSlash(input.Source)
+		data.KeyField = keyField
+		data.KeyColumn = utils.ToSnakeCase(keyField)
+	}
+
+	// Repositories of projects with structured logging take a logger
+	data.WithLogging = registry.HasStructuredLogging()
+
 	// Determine output path
 	seedDir := filepath.Join("cmd", "seed", "seeders")
 	outputPath := filepath.Join(seedDir, utils.ToSnakeCase(input.Domain)+"_seeder.go")
@@ -93,6 +145,19 @@ func scaffoldSeed(registry *Registry, input types.ScaffoldSeedInput) (types.Scaf
 		return types.NewErrorResult(fmt.Sprintf("failed to generate seeder: %v", err)), nil
 	}
 
+	// Fixture seeders share the file reading and column parsing
+	if input.Source != "" {
+		if err := gen.GenerateFileIfNotExists("seed/fixtures.go.tmpl", filepath.Join(seedDir, "fixtures.go"), nil); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate fixtures.go: %v", err)), nil
+		}
+	}
+
+	// The runner orders the registered seeders by their dependencies
+	seedersPath := filepath.Join(seedDir, "seeders.go")
+	if err := gen.GenerateFileIfNotExists("seed/seeders.go.tmpl", seedersPath, nil); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to generate seeders.go: %v", err)), nil
+	}
+
 	// Get result
 	result := gen.Result()
 
@@ -103,12 +168,38 @@ func scaffoldSeed(registry *Registry, input types.ScaffoldSeedInput) (types.Scaf
 
 	nextSteps := []string{
 		"go mod tidy",
-		fmt.Sprintf("Register the seeder in cmd/seed/main.go"),
+	}
+
+	// Register the seeder with the runner
+	if !input.DryRun {
+		injector, err := modifier.NewInjector(filepath.Join(registry.WorkingDir, seedersPath))
+		if err == nil {
+			err = injector.InjectSeeder(input.Domain)
+		}
+		if err == nil {
+			err = injector.Save()
+		}
+		if err != nil {
+			nextSteps = append(nextSteps, fmt.Sprintf(`Register the seeder in seeders.All: "%s": seeders.New%sSeeder(db)`, input.Domain, data.ModelName))
+		} else {
+			result.FilesUpdated = append(result.FilesUpdated, filepath.ToSlash(seedersPath))
+		}
+	}
+
+	// Projects whose seed command predates the runner call it themselves
+	seedMain, _ := os.ReadFile(filepath.Join(registry.WorkingDir, "cmd", "seed", "main.go"))
+	if strings.Contains(string(seedMain), "seeders.Run(") {
+		nextSteps = append(nextSteps, "go run ./cmd/seed")
+	} else {
+		nextSteps = append(nextSteps, "Run the seeders from cmd/seed/main.go: seeders.Run(ctx, seeders.All(db), *clear)")
 	}
 
 	if input.WithFaker {
 		nextSteps = append([]string{"go get github.com/brianvoe/gofakeit/v6"}, nextSteps...)
 	}
+	if input.Source != "" && !utils.FileExists(filepath.Join(registry.WorkingDir, input.Source)) {
+		nextSteps = append(nextSteps, fmt.Sprintf("Create %s with a column per field, keyed by %s", input.Source, data.KeyField))
+	}
 
 	suggestedTools := []types.ToolHint{
 		{
@@ -134,6 +225,7 @@ func scaffoldSeed(registry *Registry, input types.ScaffoldSeedInput) (types.Scaf
 		Message:        fmt.Sprintf("Successfully created seeder for domain '%s'", input.Domain),
 		FilesCreated:   result.FilesCreated,
 		FilesUpdated:   result.FilesUpdated,
+		Backups:        result.Backups,
 		NextSteps:      nextSteps,
 		SuggestedTools: suggestedTools,
 	}, nil
@@ -202,3 +294,54 @@ func buildSeedData(input types.ScaffoldSeedInput, modulePath string) generator.S
 		HasDistributions: len(distributions) > 0,
 	}
 }
+
+// validateFixtureSource checks the source of a fixture seeder, which replaces the generated data.
+func validateFixtureSource(input types.ScaffoldSeedInput) error {
+	if filepath.IsAbs(input.Source) || strings.HasPrefix(filepath.Clean(input.Source), "..") {
+		return fmt.Errorf("source '%s' must be a path inside the project (e.g., fixtures/products.csv)", input.Source)
+	}
+	if ext := strings.ToLower(filepath.Ext(input.Source)); ext != ".csv" && ext != ".json" {
+		return fmt.Errorf("source '%s' must be a .csv or .json file", input.Source)
+	}
+	if input.WithFaker || len(input.Relationships) > 0 || len(input.Distributions) > 0 {
+		return fmt.Errorf("source seeds the rows of %s as they are: with_faker, relationships and distributions can't be combined with it (foreign keys are columns of the file)", input.Source)
+	}
+	return nil
+}
+
+// fixtureFields returns the fields fixture columns can set: the domain's fields and the
+// foreign keys of its belongs_to relationships.
+func fixtureFields(domain types.ScaffoldDomainInput) []types.FieldDef {
+	fields := append([]types.FieldDef{}, domain.Fields...)
+	for _, rel := range generator.NewRelationshipDataList(domain.Relationships, domain.DomainName) {
+		switch {
+		case rel.IsSelfReferential:
+			fields = append(fields, types.FieldDef{Name: rel.ForeignKey, Type: "*uint"})
+		case rel.IsBelongsTo:
+			fields = append(fields, types.FieldDef{Name: rel.ForeignKey, Type: rel.ForeignKeyField.Type})
+		}
+	}
+	return fields
+}
+
+// fixtureKey returns the field that identifies the rows of a fixture: the key input, else the
+// first unique field, else ID.
+func fixtureKey(input types.ScaffoldSeedInput) (string, error) {
+	switch input.Key {
+	case "":
+		for _, field := range input.Fields {
+			if strings.Contains(field.GORMTags, "unique") {
+				return field.Name, nil
+			}
+		}
+		return "ID", nil
+	case "ID":
+		return "ID", nil
+	}
+	for _, field := range input.Fields {
+		if field.Name == input.Key {
+			return field.Name, nil
+		}
+	}
+	return "", fmt.Errorf("key '%s' is not a field of %s", input.Key, utils.ToModelName(input.Domain))
+}
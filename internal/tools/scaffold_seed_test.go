@@ -292,6 +292,165 @@ func TestScaffoldSeed(t *testing.T) {
 			t.Error("expected NextSteps to include gofakeit installation step")
 		}
 	})
+
+	t.Run("registers seeders with the dependency runner", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		for _, input := range []types.ScaffoldSeedInput{
+			{Domain: "category"},
+			{Domain: "order_line", Dependencies: []string{"category", "user"}},
+		} {
+			result, err := scaffoldSeed(registry, input)
+			if err != nil || !result.Success {
+				t.Fatalf("unexpected failure: %v %s", err, result.Message)
+			}
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "cmd", "seed", "seeders", "seeders.go"))
+		for _, want := range []string{
+			"func Order(seeders map[string]Seeder) ([]string, error) {",
+			`"seeder dependency cycle: %s -> %s"`,
+			"func Run(ctx context.Context, seeders map[string]Seeder, clear bool) error {",
+			`"category": NewCategorySeeder(db),`,
+			`"order_line": NewOrderLineSeeder(db),`,
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected seeders.go to contain %q", want)
+			}
+		}
+		if !strings.Contains(readFile(t, filepath.Join(tmpDir, "cmd", "seed", "seeders", "order_line_seeder.go")), `func (s *OrderLineSeeder) Dependencies() []string {`) {
+			t.Error("expected the seeder to declare its dependencies")
+		}
+	})
+
+	t.Run("rejects a seeder depending on itself", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		result, err := scaffoldSeed(registry, types.ScaffoldSeedInput{Domain: "category", Dependencies: []string{"category"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || !strings.Contains(result.Message, "a seeder can't depend on itself") {
+			t.Errorf("expected a self-dependency error, got: %s", result.Message)
+		}
+	})
+
+	t.Run("generates fixture seeder from domain metadata", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		domainInput := types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields: []types.FieldDef{
+				{Name: "Sku", Type: "string", GORMTags: "uniqueIndex"},
+				{Name: "UnitPrice", Type: "float64"},
+				{Name: "ReleasedAt", Type: "*time.Time"},
+			},
+			Relationships: []types.RelationshipDef{{Type: "belongs_to", Model: "Category"}},
+		}
+		if result, err := scaffoldDomain(registry, domainInput); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+
+		result, err := scaffoldSeed(registry, types.ScaffoldSeedInput{Domain: "product", Source: "fixtures/products.csv"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "cmd", "seed", "seeders", "product_seeder.go"))
+		for _, want := range []string{
+			`const ProductFixture = "fixtures/products.csv"`,
+			"rows, err := readFixture(ProductFixture)",
+			`Where("sku = ?", item.Sku)`,
+			`case "unitprice":`,
+			"item.UnitPrice, err = fixtureFloat[float64](value)",
+			"item.ReleasedAt, err = fixtureNullable(value, fixtureTime)",
+			"item.CategoryID, err = fixtureUint[uint](value)",
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected seeder to contain %q", want)
+			}
+		}
+		if strings.Contains(content, `"github.com/brianvoe/gofakeit/v6"`) {
+			t.Error("expected fixture seeder not to import gofakeit")
+		}
+		if !strings.Contains(readFile(t, filepath.Join(tmpDir, "cmd", "seed", "seeders", "fixtures.go")), "func readFixture(path string)") {
+			t.Error("expected fixtures.go to contain readFixture")
+		}
+
+		hasFixtureStep := false
+		for _, step := range result.NextSteps {
+			if strings.Contains(step, "Create fixtures/products.csv") && strings.Contains(step, "keyed by Sku") {
+				hasFixtureStep = true
+			}
+		}
+		if !hasFixtureStep {
+			t.Errorf("expected NextSteps to ask for the fixture file, got %v", result.NextSteps)
+		}
+	})
+
+	t.Run("keys fixture rows by key or ID", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		fields := []types.FieldDef{{Name: "Name", Type: "string"}, {Name: "Code", Type: "string"}}
+		result, err := scaffoldSeed(registry, types.ScaffoldSeedInput{Domain: "country", Fields: fields, Source: "fixtures/countries.json"})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		if content := readFile(t, filepath.Join(tmpDir, "cmd", "seed", "seeders", "country_seeder.go")); !strings.Contains(content, `Where("id = ?", item.ID)`) {
+			t.Error("expected rows without a unique field to be keyed by ID")
+		}
+
+		result, err = scaffoldSeed(registry, types.ScaffoldSeedInput{Domain: "region", Fields: fields, Source: "fixtures/regions.json", Key: "Code"})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		if content := readFile(t, filepath.Join(tmpDir, "cmd", "seed", "seeders", "region_seeder.go")); !strings.Contains(content, `Where("code = ?", item.Code)`) {
+			t.Error("expected rows to be keyed by Code")
+		}
+
+		result, err = scaffoldSeed(registry, types.ScaffoldSeedInput{Domain: "city", Fields: fields, Source: "fixtures/cities.json", Key: "Zip"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || !strings.Contains(result.Message, "key 'Zip' is not a field of City") {
+			t.Errorf("expected an unknown key error, got: %s", result.Message)
+		}
+	})
+
+	t.Run("rejects invalid fixture sources", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		fields := []types.FieldDef{{Name: "Name", Type: "string"}}
+
+		tests := []struct {
+			name  string
+			input types.ScaffoldSeedInput
+			want  string
+		}{
+			{"absolute path", types.ScaffoldSeedInput{Domain: "product", Fields: fields, Source: "/tmp/products.csv"}, "must be a path inside the project"},
+			{"outside the project", types.ScaffoldSeedInput{Domain: "product", Fields: fields, Source: "../products.csv"}, "must be a path inside the project"},
+			{"unsupported format", types.ScaffoldSeedInput{Domain: "product", Fields: fields, Source: "fixtures/products.xlsx"}, "must be a .csv or .json file"},
+			{"with faker", types.ScaffoldSeedInput{Domain: "product", Fields: fields, Source: "fixtures/products.csv", WithFaker: true}, "can't be combined"},
+			{"no fields", types.ScaffoldSeedInput{Domain: "product", Source: "fixtures/products.csv"}, "source needs the fields of the model"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := scaffoldSeed(registry, tt.input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success || !strings.Contains(result.Message, tt.want) {
+					t.Errorf("expected error containing %q, got: %s", tt.want, result.Message)
+				}
+			})
+		}
+	})
 }
 
 func TestBuildSeedData(t *testing.T) {
@@ -33,7 +33,7 @@ The auth user repository has methods like FindByEmail, ExistsByEmail, UpdateLast
 that aren't part of the generic repository interface. For such domains, write the
 service manually to properly utilize the custom repository methods.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldServiceInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldService(registry, input)
+		result, err := locked(scaffoldService)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -70,6 +70,7 @@ func scaffoldService(registry *Registry, input types.ScaffoldServiceInput) (type
 		TableName:      utils.ToTableName(input.DomainName),
 		URLPath:        urlPath,
 		URLPathSegment: urlPath[1:], // Remove leading slash
+		WithLogging:    registry.HasStructuredLogging(),
 	}
 
 	// Create directory
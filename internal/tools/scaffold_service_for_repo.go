@@ -43,7 +43,7 @@ You must manually:
 2. Instantiate the service with the repository
 3. Wire it to any controllers that need it`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldServiceForRepoInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldServiceForRepo(registry, input)
+		result, err := locked(scaffoldServiceForRepo)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -0,0 +1,465 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterScaffoldSettings registers the scaffold_settings tool.
+func RegisterScaffoldSettings(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_settings",
+		Description: `Add application settings: key/value settings stored in the database, with typed accessors,
+an in-memory cache and an admin settings page.
+
+Generates:
+- config/settings.toml: the declared settings, grouped into the tabs of the settings page. Each
+  setting has a key, label, type (string, text, int, float, bool, url, email), default and help
+- internal/models/setting.go: Setting (Key, Value), migrated by RunMigrations
+- internal/settings/settings.go: settings.String, Int, Float and Bool read the cached values
+  (or the declared defaults); settings.Save validates and stores values. Init loads them at
+  startup and Refresh, run by the scheduler, picks up changes saved by other instances
+- internal/settings/brand.templ: settings.Brand, the logo (logo_url) or the site name (site_name)
+
+Web projects with auth and user management also get /admin/settings (internal/web/settings),
+one tab per group, with an admin sidebar link. The layouts use settings.SiteName() in the page
+title and settings.Brand in the sidebar. Read any setting in views with { settings.String("tagline") }.
+
+groups defaults to a general group (site_name, tagline, logo_url, support_email) and a
+maintenance group (maintenance_mode, maintenance_message). Re-running the tool appends the
+groups that are not in config/settings.toml yet.
+
+Example:
+  scaffold_settings: { groups: [{ name: "mail", settings: [{ key: "smtp_host" }, { key: "smtp_port", type: "int", default: "587" }] }] }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldSettingsInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldSettings)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldSettings(registry *Registry, input types.ScaffoldSettingsInput) (types.ScaffoldResult, error) {
+	// Get module path from go.mod
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	siteName := filepath.Base(modulePath)
+	if project, err := metadata.NewStore(registry.WorkingDir).GetProject(); err == nil && project != nil && project.Input.ProjectName != "" {
+		siteName = project.Input.ProjectName
+	}
+
+	groups := input.Groups
+	if len(groups) == 0 {
+		groups = defaultSettingsGroups(siteName)
+	}
+	groups, err = normalizeSettingsGroups(groups)
+	if err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
+	// Keep the groups already declared; their keys must not be declared again
+	settingsPath := filepath.Join("config", "settings.toml")
+	existing := ""
+	if content, err := os.ReadFile(filepath.Join(registry.WorkingDir, settingsPath)); err == nil {
+		existing = string(content)
+	}
+	declaredGroups := tomlStringValues(existing, "name")
+	declaredKeys := tomlStringValues(existing, "key")
+	var newGroups []types.SettingsGroupDef
+	var keptGroups []string
+	for _, group := range groups {
+		if declaredGroups[group.Name] {
+			keptGroups = append(keptGroups, group.Name)
+			continue
+		}
+		for _, def := range group.Settings {
+			if declaredKeys[def.Key] {
+				return types.NewErrorResult(fmt.Sprintf("setting '%s' is already declared in %s", def.Key, settingsPath)), nil
+			}
+		}
+		newGroups = append(newGroups, group)
+	}
+
+	isAPI := registry.IsAPIProject()
+	mainGoPath := filepath.Join(registry.WorkingDir, "cmd", "web", "main.go")
+	withPage := !isAPI && utils.FileExists(filepath.Join(registry.WorkingDir, "internal", "services", "auth", "auth.go")) && hasAdminRoutes(mainGoPath)
+	data := generator.SettingsData{ModulePath: modulePath, SiteName: siteName}
+
+	// Create generator; existing settings files are kept so re-runs only add groups
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+
+	type templateFile struct {
+		template string
+		output   string
+	}
+	files := []templateFile{
+		{"settings/model.go.tmpl", filepath.Join("internal", "models", "setting.go")},
+		{"settings/settings.go.tmpl", filepath.Join("internal", "settings", "settings.go")},
+		{"scheduler/scheduler.go.tmpl", filepath.Join("internal", "scheduler", "scheduler.go")},
+	}
+	if !isAPI {
+		files = append(files, templateFile{"settings/brand.templ.tmpl", filepath.Join("internal", "settings", "brand.templ")})
+	}
+	if withPage {
+		files = append(files,
+			templateFile{"settings/controller.go.tmpl", filepath.Join("internal", "web", "settings", "settings.go")},
+			templateFile{"settings/views.templ.tmpl", filepath.Join("internal", "web", "settings", "views", "settings.templ")},
+		)
+	}
+	for _, f := range files {
+		if err := gen.GenerateFileIfNotExists(f.template, f.output, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.output, err)), nil
+		}
+	}
+
+	if existing == "" {
+		if err := gen.GenerateFileFromString(settingsPath, settingsTOML(newGroups, true)); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", settingsPath, err)), nil
+		}
+	}
+
+	result := gen.Result()
+
+	if existing != "" && len(newGroups) > 0 {
+		if !input.DryRun {
+			content := strings.TrimRight(existing, "\n") + "\n\n" + settingsTOML(newGroups, false)
+			if err := os.WriteFile(filepath.Join(registry.WorkingDir, settingsPath), []byte(content), 0644); err != nil {
+				return types.NewErrorResult(fmt.Sprintf("failed to update %s: %v", settingsPath, err)), nil
+			}
+		}
+		result.FilesUpdated = append(result.FilesUpdated, filepath.ToSlash(settingsPath))
+	}
+
+	schedulerMissing := false
+	if !input.DryRun {
+		databaseGoPath := filepath.Join(registry.WorkingDir, "internal", "database", "database.go")
+		if utils.FileExists(databaseGoPath) {
+			if err := injectSettingsModel(databaseGoPath); err != nil {
+				fmt.Printf("Warning: could not migrate the Setting model in database.go: %v\n", err)
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, "internal/database/database.go")
+			}
+		}
+
+		if utils.FileExists(mainGoPath) {
+			var err error
+			schedulerMissing, err = injectSettingsWiring(mainGoPath, modulePath, withPage, registry.HasSEO())
+			if err != nil {
+				fmt.Printf("Warning: could not wire settings into main.go: %v\n", err)
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, "cmd/web/main.go")
+			}
+		}
+
+		if withPage {
+			menuPath := filepath.Join(registry.WorkingDir, "config", "en", "menu.toml")
+			if utils.FileExists(menuPath) {
+				if err := injectSettingsMenuLink(menuPath); err != nil {
+					fmt.Printf("Warning: could not add the settings link to menu.toml: %v\n", err)
+				} else {
+					result.FilesUpdated = append(result.FilesUpdated, "config/en/menu.toml")
+				}
+			}
+		}
+
+		layoutPath := filepath.Join(registry.WorkingDir, "internal", "web", "layouts", "base.templ")
+		if !isAPI && utils.FileExists(layoutPath) {
+			if err := useSettingsInLayout(layoutPath, modulePath); err != nil {
+				fmt.Printf("Warning: could not use the site name setting in base.templ: %v\n", err)
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, "internal/web/layouts/base.templ")
+			}
+		}
+	}
+
+	var nextSteps []string
+	if !isAPI {
+		nextSteps = append(nextSteps, "Run templ generate to compile the settings views")
+	}
+	if withPage {
+		nextSteps = append(nextSteps, "Open /admin/settings as an admin to edit the settings")
+	} else if !isAPI {
+		nextSteps = append(nextSteps, "The /admin/settings page needs the admin route group: scaffold the project with with_auth and with_user_management to get it")
+	}
+	nextSteps = append(nextSteps, `Read settings in Go code and views with settings.String("key"), settings.Int, settings.Float and settings.Bool`)
+	if schedulerMissing {
+		nextSteps = append(nextSteps, "Refresh the cached values in cmd/web/main.go: scheduler.Start(\"settings refresh\", settings.Refresh)")
+	}
+	if len(keptGroups) > 0 {
+		nextSteps = append(nextSteps, fmt.Sprintf("Already declared, left unchanged: %s", strings.Join(keptGroups, ", ")))
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would add application settings (%d new groups)", len(newGroups)),
+			FilesCreated: result.FilesCreated,
+			FilesUpdated: result.FilesUpdated,
+			Backups:      result.Backups,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully added application settings (%d new groups)", len(newGroups)),
+		FilesCreated: result.FilesCreated,
+		FilesUpdated: result.FilesUpdated,
+		Backups:      result.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// defaultSettingsGroups returns the settings declared when no groups are given.
+func defaultSettingsGroups(siteName string) []types.SettingsGroupDef {
+	return []types.SettingsGroupDef{
+		{
+			Name: "general",
+			Settings: []types.SettingDef{
+				{Key: "site_name", Default: siteName, Help: "Shown in the page title and the sidebar"},
+				{Key: "tagline"},
+				{Key: "logo_url", Label: "Logo URL", Type: "url", Help: "Replaces the site name in the sidebar (e.g., /assets/logo.png)"},
+				{Key: "support_email", Type: "email"},
+			},
+		},
+		{
+			Name: "maintenance",
+			Settings: []types.SettingDef{
+				{Key: "maintenance_mode", Type: "bool", Default: "false", Help: `Read it with settings.Bool("maintenance_mode")`},
+				{Key: "maintenance_message", Type: "text", Default: "We'll be back soon."},
+			},
+		},
+	}
+}
+
+// normalizeSettingsGroups validates the groups and fills in their default labels and types.
+func normalizeSettingsGroups(groups []types.SettingsGroupDef) ([]types.SettingsGroupDef, error) {
+	names := map[string]bool{}
+	keys := map[string]bool{}
+	normalized := make([]types.SettingsGroupDef, 0, len(groups))
+	for _, group := range groups {
+		if err := utils.ValidateSettingKey(group.Name); err != nil {
+			return nil, fmt.Errorf("invalid group name '%s': use lowercase snake_case starting with a letter", group.Name)
+		}
+		if names[group.Name] {
+			return nil, fmt.Errorf("duplicate group '%s'", group.Name)
+		}
+		names[group.Name] = true
+		if len(group.Settings) == 0 {
+			return nil, fmt.Errorf("group '%s' has no settings", group.Name)
+		}
+		if group.Label == "" {
+			group.Label = utils.ToLabel(group.Name)
+		}
+
+		settings := make([]types.SettingDef, 0, len(group.Settings))
+		for _, def := range group.Settings {
+			if err := utils.ValidateSettingKey(def.Key); err != nil {
+				return nil, err
+			}
+			if keys[def.Key] {
+				return nil, fmt.Errorf("duplicate setting '%s'", def.Key)
+			}
+			keys[def.Key] = true
+			if def.Type == "" {
+				def.Type = "string"
+			}
+			if err := utils.ValidateSettingType(def.Type); err != nil {
+				return nil, err
+			}
+			if err := validateSettingDefault(def); err != nil {
+				return nil, err
+			}
+			if def.Label == "" {
+				def.Label = utils.ToLabel(def.Key)
+			}
+			settings = append(settings, def)
+		}
+		group.Settings = settings
+		normalized = append(normalized, group)
+	}
+	return normalized, nil
+}
+
+// validateSettingDefault checks that the default of a numeric or bool setting parses.
+func validateSettingDefault(def types.SettingDef) error {
+	if def.Default == "" {
+		return nil
+	}
+	var err error
+	switch def.Type {
+	case "int":
+		_, err = strconv.Atoi(def.Default)
+	case "float":
+		_, err = strconv.ParseFloat(def.Default, 64)
+	case "bool":
+		_, err = strconv.ParseBool(def.Default)
+	}
+	if err != nil {
+		return fmt.Errorf("invalid default '%s' for %s setting '%s'", def.Default, def.Type, def.Key)
+	}
+	return nil
+}
+
+// settingsTOML renders settings groups as config/settings.toml entries, with the file's
+// header comment when header is true.
+func settingsTOML(groups []types.SettingsGroupDef, header bool) string {
+	var b strings.Builder
+	if header {
+		b.WriteString("# Application settings, edited on /admin/settings. Each [[groups]] entry is a tab.\n")
+		b.WriteString("# Types: string, text, int, float, bool, url, email. Defaults apply until a value is saved.\n")
+	}
+	for i, group := range groups {
+		if i > 0 || header {
+			b.WriteString("\n")
+		}
+		fmt.Fprintf(&b, "[[groups]]\nname = %q\nlabel = %q\n", group.Name, group.Label)
+		for _, def := range group.Settings {
+			fmt.Fprintf(&b, "\n[[groups.settings]]\nkey = %q\nlabel = %q\ntype = %q\n", def.Key, def.Label, def.Type)
+			if def.Default != "" {
+				fmt.Fprintf(&b, "default = %q\n", def.Default)
+			}
+			if def.Help != "" {
+				fmt.Fprintf(&b, "help = %q\n", def.Help)
+			}
+		}
+	}
+	return b.String()
+}
+
+// tomlStringValues returns the string values assigned to field in a TOML document.
+func tomlStringValues(content, field string) map[string]bool {
+	values := map[string]bool{}
+	pattern := regexp.MustCompile(`(?m)^\s*` + regexp.QuoteMeta(field) + `\s*=\s*"([^"]*)"`)
+	for _, match := range pattern.FindAllStringSubmatch(content, -1) {
+		values[match[1]] = true
+	}
+	return values
+}
+
+// injectSettingsModel migrates the Setting model.
+func injectSettingsModel(databaseGoPath string) error {
+	injector, err := modifier.NewInjector(databaseGoPath)
+	if err != nil {
+		return err
+	}
+	if err := injector.InjectModel("Setting"); err != nil {
+		return err
+	}
+	return injector.Save()
+}
+
+// injectSettingsWiring loads the settings in main.go, refreshes them on the scheduler, titles the
+// pages with the site name setting and mounts the admin settings page. It reports whether
+// main.go predates the MCP:SCHEDULER markers.
+func injectSettingsWiring(mainGoPath, modulePath string, withPage, withSEO bool) (bool, error) {
+	injector, err := modifier.NewInjector(mainGoPath)
+	if err != nil {
+		return false, err
+	}
+	if err := injector.InjectImport(modulePath + "/internal/settings"); err != nil {
+		return false, err
+	}
+	// Settings are loaded with the services so controllers and views can read them
+	if err := injector.InjectBetweenMarkers(modifier.MarkerServicesStart, modifier.MarkerServicesEnd, "settings.Init(db)"); err != nil {
+		return false, err
+	}
+	if withSEO {
+		if err := injector.InjectImport(modulePath + "/internal/web/seo"); err != nil {
+			return false, err
+		}
+		if err := injector.InjectBetweenMarkers(modifier.MarkerServicesStart, modifier.MarkerServicesEnd, "seo.SiteName = settings.SiteName"); err != nil {
+			return false, err
+		}
+	}
+
+	schedulerMissing := !injector.HasMarker(modifier.MarkerSchedulerStart)
+	if !schedulerMissing {
+		if err := injector.InjectImport(modulePath + "/internal/scheduler"); err != nil {
+			return false, err
+		}
+		if err := injector.InjectBetweenMarkers(modifier.MarkerSchedulerStart, modifier.MarkerSchedulerEnd, `scheduler.Start("settings refresh", settings.Refresh)`); err != nil {
+			return false, err
+		}
+	}
+
+	if withPage {
+		if err := injector.InjectImportWithAlias(modulePath+"/internal/web/settings", "settingsweb"); err != nil {
+			return false, err
+		}
+		if err := injector.InjectBetweenMarkers(modifier.MarkerControllersStart, modifier.MarkerControllersEnd, "settingsController := settingsweb.NewController(authService)"); err != nil {
+			return false, err
+		}
+		if err := injector.InjectBetweenMarkers(modifier.MarkerRoutesAdminStart, modifier.MarkerRoutesAdminEnd, `r.Route("/admin/settings", settingsController.RegisterRoutes)`); err != nil {
+			return false, err
+		}
+	}
+	return schedulerMissing, injector.Save()
+}
+
+// hasAdminRoutes reports whether main.go has the admin route group, which is only generated
+// with user management.
+func hasAdminRoutes(mainGoPath string) bool {
+	content, err := os.ReadFile(mainGoPath)
+	return err == nil && strings.Contains(string(content), modifier.MarkerRoutesAdminStart)
+}
+
+// injectSettingsMenuLink adds the settings page to the admin section of menu.toml.
+func injectSettingsMenuLink(menuPath string) error {
+	injector, err := modifier.NewInjector(menuPath)
+	if err != nil {
+		return err
+	}
+	if err := injector.InjectMenuLink("admin", "Settings", "/admin/settings", "cog"); err != nil {
+		return err
+	}
+	return injector.Save()
+}
+
+// layoutTitlePattern and layoutBrandPattern match the project name in the page title and the
+// sidebar heading of base.templ.
+var (
+	layoutTitlePattern = regexp.MustCompile(`<title>\{ title \} - [^<{]+</title>`)
+	layoutBrandPattern = regexp.MustCompile(`(?m)^(\s*)<h1 class="text-xl font-bold">[^<{]+</h1>`)
+)
+
+// useSettingsInLayout replaces the project name in base.templ with the site name setting.
+// Layouts with the seo package take the page title from seo.SiteName, which main.go sets.
+func useSettingsInLayout(layoutPath, modulePath string) error {
+	content, err := os.ReadFile(layoutPath)
+	if err != nil {
+		return err
+	}
+	updated := layoutTitlePattern.ReplaceAllString(string(content), "<title>{ title } - { settings.SiteName() }</title>")
+	updated = layoutBrandPattern.ReplaceAllString(updated, "$1<h1 class=\"text-xl font-bold\">\n$1\t@settings.Brand(\"h-8\")\n$1</h1>")
+	if updated == string(content) {
+		return nil
+	}
+
+	importLine := fmt.Sprintf("import %q\n", modulePath+"/internal/settings")
+	if !strings.Contains(updated, importLine) {
+		anchor := "import \"context\"\n"
+		if !strings.Contains(updated, anchor) {
+			return fmt.Errorf("import block not found")
+		}
+		updated = strings.Replace(updated, anchor, anchor+importLine, 1)
+	}
+	return os.WriteFile(layoutPath, []byte(updated), 0644)
+}
@@ -0,0 +1,197 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldSettings(t *testing.T) {
+	setup := func(t *testing.T, withAuth bool) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, `package main
+
+import (
+	"log"
+)
+
+func main() {
+	db := database.Connect(cfg)
+	// MCP:SERVICES:START
+	authService := auth.NewService(userRepo, cfg)
+	// MCP:SERVICES:END
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+	// MCP:SCHEDULER:START
+	// MCP:SCHEDULER:END
+
+	// MCP:ROUTES:START
+	router.Group(func(r chi.Router) {
+		r.Use(authMiddleware.RequireAdmin)
+		// MCP:ROUTES:ADMIN:START
+		// MCP:ROUTES:ADMIN:END
+	})
+	// MCP:ROUTES:END
+}
+`)
+		setupWizardDatabaseGo(t, tmpDir, `package database
+
+import (
+	"github.com/example/testapp/internal/models"
+	"gorm.io/gorm"
+)
+
+func RunMigrations(db *gorm.DB) error {
+	return db.AutoMigrate(
+		// MCP:MODELS:START
+		// MCP:MODELS:END
+	)
+}
+`)
+		files := map[string]string{
+			filepath.Join("config", "en", "menu.toml"):                "[[main]]\nlabel = \"Dashboard\"\nurl = \"/dashboard\"\nicon = \"home\"\norder = 1\n",
+			filepath.Join("internal", "web", "layouts", "base.templ"): "package layouts\n\nimport \"context\"\n\ntempl Base(title string) {\n\t<title>{ title } - testapp</title>\n\t<div>\n\t\t<h1 class=\"text-xl font-bold\">testapp</h1>\n\t</div>\n}\n",
+		}
+		if withAuth {
+			files[filepath.Join("internal", "services", "auth", "auth.go")] = "package auth\n"
+		}
+		for path, content := range files {
+			fullPath := filepath.Join(tmpDir, path)
+			if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+				t.Fatalf("failed to create %s dir: %v", path, err)
+			}
+			if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+				t.Fatalf("failed to write %s: %v", path, err)
+			}
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("generates settings and the admin page", func(t *testing.T) {
+		registry, tmpDir := setup(t, true)
+
+		result, err := scaffoldSettings(registry, types.ScaffoldSettingsInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("config", "settings.toml"):                                {"name = \"general\"", "key = \"site_name\"\nlabel = \"Site Name\"\ntype = \"string\"\ndefault = \"testapp\"", "key = \"maintenance_mode\"\nlabel = \"Maintenance Mode\"\ntype = \"bool\"\ndefault = \"false\""},
+			filepath.Join("internal", "models", "setting.go"):                       {"type Setting struct"},
+			filepath.Join("internal", "settings", "settings.go"):                    {"func Refresh(ctx context.Context) error", `return "testapp"`},
+			filepath.Join("internal", "settings", "brand.templ"):                    {"templ Brand(class string)"},
+			filepath.Join("internal", "web", "settings", "settings.go"):             {"func (c *Controller) Update(w http.ResponseWriter, r *http.Request)"},
+			filepath.Join("internal", "web", "settings", "views", "settings.templ"): {"templ SettingsContent(props SettingsProps)"},
+			filepath.Join("internal", "database", "database.go"):                    {"&models.Setting{},"},
+			filepath.Join("config", "en", "menu.toml"):                              {"[[admin]]\nlabel = \"Settings\"\nurl = \"/admin/settings\"\nicon = \"cog\"\norder = 10\n"},
+			filepath.Join("internal", "web", "layouts", "base.templ"): {
+				"import \"context\"\nimport \"github.com/example/testapp/internal/settings\"\n",
+				"<title>{ title } - { settings.SiteName() }</title>",
+				"\t\t<h1 class=\"text-xl font-bold\">\n\t\t\t@settings.Brand(\"h-8\")\n\t\t</h1>",
+			},
+			filepath.Join("cmd", "web", "main.go"): {
+				"settings.Init(db)",
+				`scheduler.Start("settings refresh", settings.Refresh)`,
+				"settingsController := settingsweb.NewController(authService)",
+				`r.Route("/admin/settings", settingsController.RegisterRoutes)`,
+				`settingsweb "github.com/example/testapp/internal/web/settings"`,
+			},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+	})
+
+	t.Run("without auth", func(t *testing.T) {
+		registry, tmpDir := setup(t, false)
+		result, err := scaffoldSettings(registry, types.ScaffoldSettingsInput{})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		if fileExists(filepath.Join(tmpDir, "internal", "web", "settings", "settings.go")) {
+			t.Error("expected no settings page without auth")
+		}
+		if !fileExists(filepath.Join(tmpDir, "internal", "settings", "settings.go")) {
+			t.Error("expected the settings package without auth")
+		}
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, "config", "en", "menu.toml")), "/admin/settings") {
+			t.Error("expected no admin link without the settings page")
+		}
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go")), "settingsController") {
+			t.Error("expected no settings controller without auth")
+		}
+	})
+
+	t.Run("re-running appends new groups", func(t *testing.T) {
+		registry, tmpDir := setup(t, true)
+		inputs := []types.ScaffoldSettingsInput{
+			{},
+			{Groups: []types.SettingsGroupDef{{Name: "mail", Settings: []types.SettingDef{{Key: "smtp_port", Type: "int", Default: "587"}}}}},
+			{},
+		}
+		for i, input := range inputs {
+			if result, err := scaffoldSettings(registry, input); err != nil || !result.Success {
+				t.Fatalf("run %d failed: %v %s", i+1, err, result.Message)
+			}
+		}
+
+		settingsTOML := readFile(t, filepath.Join(tmpDir, "config", "settings.toml"))
+		if !strings.Contains(settingsTOML, "key = \"smtp_port\"\nlabel = \"Smtp Port\"\ntype = \"int\"\ndefault = \"587\"") {
+			t.Errorf("expected the mail group to be appended, got:\n%s", settingsTOML)
+		}
+		counts := map[string]string{
+			filepath.Join("config", "settings.toml"):                  "name = \"general\"",
+			filepath.Join("cmd", "web", "main.go"):                    "settings.Init(db)",
+			filepath.Join("internal", "database", "database.go"):      "&models.Setting{},",
+			filepath.Join("config", "en", "menu.toml"):                "/admin/settings",
+			filepath.Join("internal", "web", "layouts", "base.templ"): "internal/settings\"",
+		}
+		for path, want := range counts {
+			if n := strings.Count(readFile(t, filepath.Join(tmpDir, path)), want); n != 1 {
+				t.Errorf("expected %s to contain %q once, got %d", path, want, n)
+			}
+		}
+
+		result, err := scaffoldSettings(registry, types.ScaffoldSettingsInput{Groups: []types.SettingsGroupDef{{Name: "smtp", Settings: []types.SettingDef{{Key: "smtp_port"}}}}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure for a key declared in another group")
+		}
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		registry, _ := setup(t, true)
+		inputs := map[string]types.ScaffoldSettingsInput{
+			"group name":    {Groups: []types.SettingsGroupDef{{Name: "Mail", Settings: []types.SettingDef{{Key: "host"}}}}},
+			"empty group":   {Groups: []types.SettingsGroupDef{{Name: "mail"}}},
+			"key":           {Groups: []types.SettingsGroupDef{{Name: "mail", Settings: []types.SettingDef{{Key: "smtp-host"}}}}},
+			"type":          {Groups: []types.SettingsGroupDef{{Name: "mail", Settings: []types.SettingDef{{Key: "host", Type: "json"}}}}},
+			"default":       {Groups: []types.SettingsGroupDef{{Name: "mail", Settings: []types.SettingDef{{Key: "port", Type: "int", Default: "smtp"}}}}},
+			"duplicate key": {Groups: []types.SettingsGroupDef{{Name: "a", Settings: []types.SettingDef{{Key: "host"}}}, {Name: "b", Settings: []types.SettingDef{{Key: "host"}}}}},
+		}
+		for name, input := range inputs {
+			result, err := scaffoldSettings(registry, input)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			if result.Success {
+				t.Errorf("%s: expected failure", name)
+			}
+		}
+	})
+}
@@ -6,6 +6,8 @@ import (
 	"path/filepath"
 
 	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
 	"github.com/dbb1dev/go-mcp/internal/types"
 	"github.com/dbb1dev/go-mcp/internal/utils"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
@@ -24,12 +26,17 @@ Generates data tables with Tailwind CSS styling featuring:
 - Row actions (view, edit, delete, custom)
 - Bulk actions for batch operations
 - Column formatting: text, currency, date, datetime, badge, link
+- Inline editing: format "inline" renders the domain's click-to-edit cell, for fields
+  scaffolded with inline_edit
 
 Columns support sortable: true and custom badge_config for status fields.
+Sortable headers link to ?sort=<key>&dir=asc|desc, so sort state survives reloads
+and is kept by search and pagination. Sortable keys are whitelisted in the domain
+controller's sortColumns map (between the MCP:SORT_COLUMNS markers) when it exists.
 
 Run 'templ generate' after creating tables.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldTableInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldTable(registry, input)
+		result, err := locked(scaffoldTable)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -38,6 +45,11 @@ Run 'templ generate' after creating tables.`,
 }
 
 func scaffoldTable(registry *Registry, input types.ScaffoldTableInput) (types.ScaffoldResult, error) {
+	// API-only projects have no views
+	if registry.IsAPIProject() {
+		return noViewsInAPIProject("scaffold_table"), nil
+	}
+
 	// Validate input
 	if input.TableName == "" {
 		return types.NewErrorResult("table name is required"), nil
@@ -59,6 +71,11 @@ func scaffoldTable(registry *Registry, input types.ScaffoldTableInput) (types.Sc
 		}
 	}
 
+	// Inline cells need the domain's inline edit endpoints
+	if err := validateInlineColumns(registry.WorkingDir, utils.ParseDomainPath(input.Domain), input.Columns); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
 	// Validate row actions
 	validActionTypes := map[string]bool{"view": true, "edit": true, "delete": true, "custom": true}
 	for _, action := range input.RowActions {
@@ -107,10 +124,22 @@ func scaffoldTable(registry *Registry, input types.ScaffoldTableInput) (types.Sc
 		return *conflictResult, nil
 	}
 
+	// Whitelist sortable columns in the domain controller
+	controllerPath := filepath.Join("internal", "web", domainDir, basePkgName+".go")
+	if data.WithSorting && !input.DryRun && utils.FileExists(filepath.Join(registry.WorkingDir, controllerPath)) {
+		if err := injectSortColumns(filepath.Join(registry.WorkingDir, controllerPath), input.Columns); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to whitelist sort columns: %v", err)), nil
+		}
+		result.FilesUpdated = append(result.FilesUpdated, controllerPath)
+	}
+
 	nextSteps := []string{
 		"templ generate",
 		fmt.Sprintf("Import the table in internal/web/%s/%s.go", domainDir, basePkgName),
-		fmt.Sprintf("Add list handler that uses the table in the %s controller", basePkgName),
+		fmt.Sprintf("Add list handler that uses the table in the %s controller: fill SortBy/SortDir from parseSort(r) and render %sTableContent for HTMX requests", basePkgName, data.ModelName),
+	}
+	if data.WithBulkActions {
+		nextSteps = append(nextSteps, fmt.Sprintf("Set CSRFToken in the table props; the bulk toolbar posts to %s/bulk, generated by scaffold_domain with with_bulk_actions: true", data.URLPath))
 	}
 
 	suggestedTools := []types.ToolHint{
@@ -141,11 +170,62 @@ func scaffoldTable(registry *Registry, input types.ScaffoldTableInput) (types.Sc
 		Message:        fmt.Sprintf("Successfully created table '%s' for domain '%s'", input.TableName, input.Domain),
 		FilesCreated:   result.FilesCreated,
 		FilesUpdated:   result.FilesUpdated,
+		Backups:        result.Backups,
 		NextSteps:      nextSteps,
 		SuggestedTools: suggestedTools,
 	}, nil
 }
 
+// injectSortColumns adds the table's sortable columns to the controller's sortColumns whitelist.
+func injectSortColumns(controllerPath string, columns []types.ColumnDef) error {
+	injector, err := modifier.NewInjector(controllerPath)
+	if err != nil {
+		return err
+	}
+	for _, col := range columns {
+		if !col.Sortable {
+			continue
+		}
+		if err := injector.InjectSortColumn(col.Key, utils.ToSnakeCase(col.Key)); err != nil {
+			return err
+		}
+	}
+	return injector.Save()
+}
+
+// validateInlineColumns checks that the fields of inline columns are inline editable in the
+// domain's scaffold metadata.
+func validateInlineColumns(workingDir, domainName string, columns []types.ColumnDef) error {
+	var inline []string
+	for _, col := range columns {
+		if col.Format == "inline" {
+			inline = append(inline, col.Key)
+		}
+	}
+	if len(inline) == 0 {
+		return nil
+	}
+
+	meta, err := metadata.NewStore(workingDir).Load()
+	if err != nil {
+		return fmt.Errorf("failed to read scaffold metadata: %w", err)
+	}
+	domain, ok := meta.Domains[domainName]
+	if !ok {
+		return fmt.Errorf("inline columns need the scaffold metadata of domain '%s': scaffold it with scaffold_domain first", domainName)
+	}
+	editable := make(map[string]bool)
+	for _, name := range domain.Input.InlineEditFields() {
+		editable[utils.ToSnakeCase(name)] = true
+	}
+	for _, key := range inline {
+		if !editable[utils.ToSnakeCase(key)] {
+			return fmt.Errorf("column '%s': format inline needs the field in the domain's inline_edit", key)
+		}
+	}
+	return nil
+}
+
 // buildTableData creates TableData from ScaffoldTableInput.
 // baseDomain is the base name extracted from nested paths (e.g., "users" from "admin/users").
 func buildTableData(input types.ScaffoldTableInput, modulePath, baseDomain string) generator.TableData {
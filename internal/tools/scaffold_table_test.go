@@ -1,6 +1,7 @@
 package tools
 
 import (
+	"os"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -225,6 +226,114 @@ func TestScaffoldTable(t *testing.T) {
 		}
 	})
 
+	t.Run("wires sortable columns", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		controllerDir := filepath.Join(tmpDir, "internal", "web", "product")
+		if err := os.MkdirAll(controllerDir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		controller := `package product
+
+var sortColumns = map[string]string{
+	// MCP:SORT_COLUMNS:START
+	"id": "id",
+	// MCP:SORT_COLUMNS:END
+}
+`
+		if err := os.WriteFile(filepath.Join(controllerDir, "product.go"), []byte(controller), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		input := types.ScaffoldTableInput{
+			TableName: "product_table",
+			Domain:    "product",
+			Columns: []types.ColumnDef{
+				{Key: "name", Label: "Name"},
+				{Key: "unitPrice", Label: "Price", Sortable: true, Format: "currency"},
+			},
+		}
+		result, err := scaffoldTable(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		content := readFile(t, filepath.Join(controllerDir, "views", "product_table.templ"))
+		for _, want := range []string{
+			`props.sortURL("unitPrice")`,
+			`query.Set("sort", sortBy)`,
+			`BaseURL:     props.tableURL(props.SortBy, props.SortDir)`,
+			`templ ProductTableContent(props ProductTableProps)`,
+			`hx-include="#product-table-sort, #product-table-dir"`,
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected table to contain %q", want)
+			}
+		}
+		if strings.Contains(content, `props.sortURL("name")`) {
+			t.Error("expected non-sortable column header not to link")
+		}
+
+		controller = readFile(t, filepath.Join(controllerDir, "product.go"))
+		if !strings.Contains(controller, `"unitPrice": "unit_price",`) {
+			t.Errorf("expected sortable column to be whitelisted, got:\n%s", controller)
+		}
+		if strings.Contains(controller, `"name": "name",`) {
+			t.Error("expected non-sortable column not to be whitelisted")
+		}
+	})
+
+	t.Run("renders inline columns as editable cells", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		domain := types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields: []types.FieldDef{
+				{Name: "Name", Type: "string", InlineEdit: true},
+				{Name: "Price", Type: "float64"},
+			},
+		}
+		if result, err := scaffoldDomain(registry, domain); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+
+		input := types.ScaffoldTableInput{
+			TableName: "product_table",
+			Domain:    "product",
+			Columns: []types.ColumnDef{
+				{Key: "name", Label: "Name", Format: "inline"},
+				{Key: "price", Label: "Price", Format: "currency"},
+			},
+		}
+		result, err := scaffoldTable(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "internal", "web", "product", "views", "product_table.templ"))
+		if !strings.Contains(content, `@ProductInlineCell(item, "name", "/products")`) {
+			t.Error("expected the name column to render an inline cell")
+		}
+
+		input.TableName = "price_table"
+		input.Columns = []types.ColumnDef{{Key: "price", Label: "Price", Format: "inline"}}
+		result, err = scaffoldTable(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Error("expected failure for an inline column of a field that is not inline editable")
+		}
+	})
+
 	t.Run("defaults for pagination, sorting, search", func(t *testing.T) {
 		registry, tmpDir := testRegistry(t)
 		setupGoMod(t, tmpDir, "github.com/example/testapp")
@@ -0,0 +1,337 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterScaffoldTenancy registers the scaffold_tenancy tool.
+func RegisterScaffoldTenancy(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_tenancy",
+		Description: `Add multi-tenancy: a Tenant model, tenant resolution per request and automatic TenantID scoping.
+
+Generates:
+- internal/models/tenant.go: Tenant (Slug, Name, Active), migrated by RunMigrations
+- internal/tenancy/tenancy.go: the tenant context (WithTenant, FromContext, AllTenants, ForEachTenant),
+  the Scope GORM scope and Register, which installs GORM callbacks on the connection:
+  every query, update and delete of a tenant-scoped model (one with a TenantID field) is limited
+  to the context's tenant, and created records get its TenantID
+- internal/tenancy/middleware.go: Middleware resolves the tenant from the subdomain (acme.localhost,
+  TENANT_BASE_DOMAIN) and/or a header (X-Tenant, TENANT_HEADER); unknown tenants get a 404.
+  RequireTenant rejects requests without a tenant
+- cmd/seed/seeders/tenant_seeder.go: seeds the tenants and runs other seeders once per tenant
+
+Wires database.go (Tenant migration, tenancy.Register in Connect) and main.go (tenancy.Middleware).
+
+Scopes the scaffolded domains: domains lists them (default: every domain in the scaffold metadata).
+Their models get a TenantID column and their metadata records tenant_scoped, so seeders generated
+by scaffold_seed seed every tenant. Domains with with_cache are left unscoped. New domains opt in
+with scaffold_domain tenant_scoped: true.
+
+Queries on tenant-scoped models without a tenant fail with tenancy.ErrNoTenant; code working across
+tenants (jobs, cmd/reindex) uses tenancy.AllTenants(ctx) or tenancy.ForEachTenant. Unique indexes
+stay global: add tenant_id to them by hand.
+
+Re-running the tool scopes more domains and keeps the existing tenancy files.
+
+Example:
+  scaffold_tenancy: { resolution: "subdomain", tenants: ["acme", "globex"] }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldTenancyInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldTenancy)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldTenancy(registry *Registry, input types.ScaffoldTenancyInput) (types.ScaffoldResult, error) {
+	if err := utils.ValidateTenantResolution(input.Resolution); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	resolution := input.Resolution
+	if resolution == "" {
+		resolution = "both"
+	}
+
+	header := input.Header
+	if header == "" {
+		header = "X-Tenant"
+	}
+	if err := utils.ValidateHeaderName(header); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
+	slugs := input.Tenants
+	if len(slugs) == 0 {
+		slugs = []string{"default"}
+	}
+	var tenants []generator.TenantSeedData
+	for _, slug := range slugs {
+		if err := utils.ValidateTenantSlug(slug); err != nil {
+			return types.NewErrorResult(err.Error()), nil
+		}
+		tenants = append(tenants, generator.TenantSeedData{Slug: slug, Name: utils.ToLabel(slug)})
+	}
+
+	// Get module path from go.mod
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	metaStore := metadata.NewStore(registry.WorkingDir)
+	meta, err := metaStore.Load()
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to read scaffold metadata: %v", err)), nil
+	}
+
+	// Pick the domains to scope; cached domains are skipped unless named explicitly
+	var domains, skipped []string
+	if len(input.Domains) == 0 {
+		for name, domain := range meta.Domains {
+			if domain.Input.WithCache {
+				skipped = append(skipped, name)
+				continue
+			}
+			domains = append(domains, name)
+		}
+		sort.Strings(domains)
+		sort.Strings(skipped)
+	} else {
+		for _, name := range input.Domains {
+			domain, ok := meta.Domains[name]
+			if !ok {
+				return types.NewErrorResult(fmt.Sprintf("no scaffold metadata for domain '%s': scaffold it with scaffold_domain first", name)), nil
+			}
+			if domain.Input.WithCache {
+				return types.NewErrorResult(fmt.Sprintf("domain '%s' uses with_cache, whose cache keys are shared by all tenants", name)), nil
+			}
+			domains = appendUnique(domains, name)
+		}
+	}
+
+	data := generator.TenancyData{
+		ModulePath:      modulePath,
+		Resolution:      resolution,
+		ResolutionLabel: tenantResolutionLabel(resolution, header),
+		Header:          header,
+		Tenants:         tenants,
+	}
+
+	// Create generator; existing tenancy files are kept so re-runs only scope more domains
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+
+	files := []struct {
+		template string
+		output   string
+	}{
+		{"tenancy/model.go.tmpl", filepath.Join("internal", "models", "tenant.go")},
+		{"tenancy/tenancy.go.tmpl", filepath.Join("internal", "tenancy", "tenancy.go")},
+		{"tenancy/middleware.go.tmpl", filepath.Join("internal", "tenancy", "middleware.go")},
+		{"tenancy/seeder.go.tmpl", filepath.Join("cmd", "seed", "seeders", "tenant_seeder.go")},
+	}
+	for _, f := range files {
+		if err := gen.GenerateFileIfNotExists(f.template, f.output, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.output, err)), nil
+		}
+	}
+
+	result := gen.Result()
+
+	if !input.DryRun {
+		databaseGoPath := filepath.Join(registry.WorkingDir, "internal", "database", "database.go")
+		if utils.FileExists(databaseGoPath) {
+			if err := injectTenancyDatabase(databaseGoPath, modulePath); err != nil {
+				fmt.Printf("Warning: could not wire tenancy into database.go: %v\n", err)
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, "internal/database/database.go")
+			}
+		}
+
+		mainGoPath := filepath.Join(registry.WorkingDir, "cmd", "web", "main.go")
+		if utils.FileExists(mainGoPath) {
+			if err := injectTenancyMiddleware(mainGoPath, modulePath); err != nil {
+				fmt.Printf("Warning: could not wire tenancy into main.go: %v\n", err)
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, "cmd/web/main.go")
+			}
+		}
+	}
+
+	// Scope the domains: TenantID on the model, tenant_scoped in the metadata
+	for _, name := range domains {
+		domain := meta.Domains[name]
+		pkgName := utils.ToPackageName(name)
+		modelPath := filepath.Join("internal", "models", pkgName+".go")
+		repoPath := filepath.Join("internal", "repository", pkgName, pkgName+".go")
+		if input.DryRun {
+			result.FilesUpdated = append(result.FilesUpdated, modelPath)
+			continue
+		}
+
+		if err := scopeModelToTenant(filepath.Join(registry.WorkingDir, modelPath)); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to scope domain '%s': %v", name, err)), nil
+		}
+		result.FilesUpdated = append(result.FilesUpdated, modelPath)
+
+		// The scheduler publishes the records of every tenant
+		if domain.Input.WithScheduling {
+			if err := scopeSchedulerToAllTenants(filepath.Join(registry.WorkingDir, repoPath), modulePath); err != nil {
+				fmt.Printf("Warning: could not let the %s scheduler run across tenants: %v\n", name, err)
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, repoPath)
+			}
+		}
+
+		if !domain.Input.TenantScoped {
+			domain.Input.TenantScoped = true
+			if err := metaStore.SaveDomain(name, domain.Input, ScaffolderVersion); err != nil {
+				fmt.Printf("Warning: failed to save metadata for %s: %v\n", name, err)
+			}
+		}
+	}
+
+	nextSteps := []string{
+		"Call seeders.NewTenantSeeder(db).Seed(ctx) in cmd/seed/main.go, then go run ./cmd/seed",
+	}
+	switch resolution {
+	case "header":
+		nextSteps = append(nextSteps, fmt.Sprintf("Send the tenant slug in the %s header: curl -H '%s: %s' localhost:8080/...", header, header, slugs[0]))
+	default:
+		nextSteps = append(nextSteps, fmt.Sprintf("Open http://%s.localhost:8080 (set TENANT_BASE_DOMAIN in production)", slugs[0]))
+	}
+	nextSteps = append(nextSteps,
+		"Wrap tenant-only route groups in tenancy.RequireTenant",
+		"Use tenancy.AllTenants(ctx) or tenancy.ForEachTenant in jobs that work across tenants",
+	)
+	if len(skipped) > 0 {
+		nextSteps = append(nextSteps, fmt.Sprintf("Not scoped (with_cache): %s", strings.Join(skipped, ", ")))
+	}
+
+	suggestedTools := []types.ToolHint{
+		{
+			Tool:        "scaffold_domain",
+			Description: "Add tenant-scoped domains with tenant_scoped: true",
+			Priority:    "optional",
+		},
+		{
+			Tool:        "scaffold_seed",
+			Description: "Generate seeders that seed every tenant",
+			Priority:    "optional",
+		},
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:        true,
+			Message:        fmt.Sprintf("Dry run: Would add multi-tenancy (%d domains scoped)", len(domains)),
+			FilesCreated:   result.FilesCreated,
+			FilesUpdated:   result.FilesUpdated,
+			Backups:        result.Backups,
+			NextSteps:      nextSteps,
+			SuggestedTools: suggestedTools,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:        true,
+		Message:        fmt.Sprintf("Successfully added multi-tenancy (%d domains scoped)", len(domains)),
+		FilesCreated:   result.FilesCreated,
+		FilesUpdated:   result.FilesUpdated,
+		Backups:        result.Backups,
+		NextSteps:      nextSteps,
+		SuggestedTools: suggestedTools,
+	}, nil
+}
+
+// tenantResolutionLabel describes how requests name their tenant, for doc comments.
+func tenantResolutionLabel(resolution, header string) string {
+	switch resolution {
+	case "subdomain":
+		return "its subdomain"
+	case "header":
+		return "the " + header + " header"
+	default:
+		return "its subdomain, or the " + header + " header when it has none"
+	}
+}
+
+// injectTenancyDatabase migrates the Tenant model and registers the tenancy callbacks.
+func injectTenancyDatabase(databaseGoPath, modulePath string) error {
+	injector, err := modifier.NewInjector(databaseGoPath)
+	if err != nil {
+		return err
+	}
+	if err := injector.InjectImport(modulePath + "/internal/tenancy"); err != nil {
+		return err
+	}
+	if err := injector.InjectModel("Tenant"); err != nil {
+		return err
+	}
+	if err := injector.InjectDBPlugin("tenancy.Register(db)"); err != nil {
+		return err
+	}
+	return injector.Save()
+}
+
+// injectTenancyMiddleware resolves the tenant of every request in main.go.
+func injectTenancyMiddleware(mainGoPath, modulePath string) error {
+	injector, err := modifier.NewInjector(mainGoPath)
+	if err != nil {
+		return err
+	}
+	if err := injector.InjectImport(modulePath + "/internal/tenancy"); err != nil {
+		return err
+	}
+	if err := injector.InjectMiddleware("tenancy.Middleware(db)"); err != nil {
+		return err
+	}
+	return injector.Save()
+}
+
+// scopeModelToTenant adds the TenantID field to a domain model.
+func scopeModelToTenant(modelPath string) error {
+	injector, err := modifier.NewInjector(modelPath)
+	if err != nil {
+		return err
+	}
+	if err := injector.InjectTenantID(); err != nil {
+		return err
+	}
+	return injector.Save()
+}
+
+// scopeSchedulerToAllTenants lets a domain repository's UpdatePublishStates run across tenants.
+func scopeSchedulerToAllTenants(repoPath, modulePath string) error {
+	injector, err := modifier.NewInjector(repoPath)
+	if err != nil {
+		return err
+	}
+	content := injector.Content()
+	if strings.Contains(content, "tenancy.AllTenants(ctx)") {
+		return nil
+	}
+	signature := "func (r *repository) UpdatePublishStates(ctx context.Context, now time.Time) (int64, error) {\n"
+	if !strings.Contains(content, signature) {
+		return fmt.Errorf("UpdatePublishStates not found")
+	}
+	content = strings.Replace(content, signature, signature+"\t// The scheduler moves the records of every tenant\n\tctx = tenancy.AllTenants(ctx)\n", 1)
+	injector = modifier.NewInjectorFromContent(content)
+	if err := injector.InjectImport(modulePath + "/internal/tenancy"); err != nil {
+		return err
+	}
+	return injector.SaveTo(repoPath)
+}
@@ -0,0 +1,253 @@
+package tools
+
+import (
+	"go/parser"
+	"go/token"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldTenancy(t *testing.T) {
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		// A main.go scaffolded before the MCP:MIDDLEWARE markers
+		setupMainGo(t, tmpDir, `package main
+
+import (
+	"log"
+)
+
+func main() {
+	db := database.Connect(cfg)
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+	// MCP:SCHEDULER:START
+	// MCP:SCHEDULER:END
+
+	router := web.NewRouter(cfg)
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	// MCP:ROUTES:END
+}
+`)
+		setupWizardDatabaseGo(t, tmpDir, `package database
+
+import (
+	"log"
+
+	"github.com/example/testapp/internal/models"
+	"gorm.io/gorm"
+)
+
+func Connect(cfg *config.Config) *gorm.DB {
+	db, err := gorm.Open(dialector, &gorm.Config{})
+	if err != nil {
+		log.Fatalf("Failed to connect to database: %v", err)
+	}
+
+	// Ensure base model is used
+	_ = models.BaseModel{}
+
+	return db
+}
+
+func RunMigrations(db *gorm.DB) error {
+	return db.AutoMigrate(
+		// MCP:MODELS:START
+		// MCP:MODELS:END
+	)
+}
+`)
+
+		domains := []types.ScaffoldDomainInput{
+			{DomainName: "product", Fields: []types.FieldDef{{Name: "Name", Type: "string"}}},
+			{DomainName: "post", Fields: []types.FieldDef{{Name: "Title", Type: "string"}}, WithScheduling: true},
+			{DomainName: "category", Fields: []types.FieldDef{{Name: "Name", Type: "string"}}, WithCache: true},
+		}
+		for _, input := range domains {
+			if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+				t.Fatalf("failed to scaffold %s: %v %s", input.DomainName, err, result.Message)
+			}
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("generates tenancy and scopes every domain", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := scaffoldTenancy(registry, types.ScaffoldTenancyInput{Tenants: []string{"acme", "globex"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "models", "tenant.go"):            {"type Tenant struct", `Slug   string `},
+			filepath.Join("internal", "tenancy", "tenancy.go"):          {"func Register(db *gorm.DB) error", "func Scope(ctx context.Context) func(*gorm.DB) *gorm.DB", "func ForEachTenant("},
+			filepath.Join("internal", "tenancy", "middleware.go"):       {`cfg.Header = "X-Tenant"`, "cfg.subdomain(r.Host)", "r.Header.Get(cfg.Header)"},
+			filepath.Join("cmd", "seed", "seeders", "tenant_seeder.go"): {`{Slug: "acme", Name: "Acme", Active: true},`, `{Slug: "globex", Name: "Globex", Active: true},`},
+			filepath.Join("internal", "database", "database.go"):        {"&models.Tenant{},", "if err := tenancy.Register(db); err != nil {", `"github.com/example/testapp/internal/tenancy"`},
+			filepath.Join("cmd", "web", "main.go"):                      {"// MCP:MIDDLEWARE:START\n\trouter.Use(tenancy.Middleware(db))\n\t// MCP:MIDDLEWARE:END", `"github.com/example/testapp/internal/tenancy"`},
+			filepath.Join("internal", "models", "product.go"):           {modifier.TenantIDField},
+			filepath.Join("internal", "models", "post.go"):              {modifier.TenantIDField},
+			filepath.Join("internal", "repository", "post", "post.go"):  {"ctx = tenancy.AllTenants(ctx)"},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+
+		// Cached domains are left unscoped
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, "internal", "models", "category.go")), "TenantID") {
+			t.Error("expected the cached category domain to stay unscoped")
+		}
+
+		meta, err := metadata.NewStore(tmpDir).Load()
+		if err != nil {
+			t.Fatalf("failed to load metadata: %v", err)
+		}
+		if !meta.Domains["product"].Input.TenantScoped || meta.Domains["category"].Input.TenantScoped {
+			t.Error("expected metadata to record tenant_scoped for product but not for the cached category")
+		}
+	})
+
+	t.Run("re-running does not duplicate wiring", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		for i := 0; i < 2; i++ {
+			if result, err := scaffoldTenancy(registry, types.ScaffoldTenancyInput{Domains: []string{"product"}}); err != nil || !result.Success {
+				t.Fatalf("run %d failed: %v %s", i+1, err, result.Message)
+			}
+		}
+
+		counts := map[string]string{
+			filepath.Join("cmd", "web", "main.go"):               "tenancy.Middleware(db)",
+			filepath.Join("internal", "database", "database.go"): "tenancy.Register(db)",
+			filepath.Join("internal", "models", "product.go"):    "TenantID",
+		}
+		for path, want := range counts {
+			if n := strings.Count(readFile(t, filepath.Join(tmpDir, path)), want); n != 1 {
+				t.Errorf("expected %s to contain %q once, got %d", path, want, n)
+			}
+		}
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, "internal", "models", "post.go")), "TenantID") {
+			t.Error("expected post to stay unscoped when domains lists only product")
+		}
+	})
+
+	t.Run("sync_domain keeps a single TenantID", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		if result, err := scaffoldTenancy(registry, types.ScaffoldTenancyInput{Domains: []string{"product", "post"}}); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold tenancy: %v %s", err, result.Message)
+		}
+
+		// post was scoped by an earlier scaffold_tenancy, which put TenantID in the relationships section
+		postPath := filepath.Join(tmpDir, "internal", "models", "post.go")
+		post := strings.Replace(readFile(t, postPath), "\t"+modifier.TenantIDField+"\n", "", 1)
+		post = strings.Replace(post, "\t// MCP:RELATIONSHIPS:END", "\tTenantID uint `gorm:\"not null;default:0;index\" json:\"-\"`\n\t// MCP:RELATIONSHIPS:END", 1)
+		writeTestFile(t, postPath, post)
+
+		for _, domain := range []string{"product", "post"} {
+			if result, err := syncDomain(registry, types.SyncDomainInput{Domain: domain}); err != nil || !result.Success {
+				t.Fatalf("failed to sync %s: %v %s", domain, err, result.Message)
+			}
+			path := filepath.Join(tmpDir, "internal", "models", domain+".go")
+			content := readFile(t, path)
+			if n := strings.Count(content, "TenantID "); n != 1 {
+				t.Errorf("expected %s to declare TenantID once, got %d", domain, n)
+			}
+			if _, err := parser.ParseFile(token.NewFileSet(), path, content, 0); err != nil {
+				t.Errorf("expected %s to parse: %v", domain, err)
+			}
+		}
+	})
+
+	t.Run("header resolution", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		result, err := scaffoldTenancy(registry, types.ScaffoldTenancyInput{Resolution: "header", Header: "X-Org"})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		content := readFile(t, filepath.Join(tmpDir, "internal", "tenancy", "middleware.go"))
+		if !strings.Contains(content, `cfg.Header = "X-Org"`) {
+			t.Error("expected the X-Org default header")
+		}
+		if strings.Contains(content, "cfg.subdomain") || strings.Contains(content, `"net"`) {
+			t.Error("expected no subdomain resolution")
+		}
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		registry, _ := setup(t)
+		inputs := map[string]types.ScaffoldTenancyInput{
+			"resolution":    {Resolution: "path"},
+			"tenant slug":   {Tenants: []string{"Acme Corp"}},
+			"header":        {Header: "X Tenant"},
+			"unknown":       {Domains: []string{"order"}},
+			"cached domain": {Domains: []string{"category"}},
+		}
+		for name, input := range inputs {
+			result, err := scaffoldTenancy(registry, input)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			if result.Success {
+				t.Errorf("%s: expected failure", name)
+			}
+		}
+	})
+
+	t.Run("tenant_scoped domains", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		input := types.ScaffoldDomainInput{DomainName: "invoice", Fields: []types.FieldDef{{Name: "Number", Type: "string"}}, TenantScoped: true}
+		result, err := scaffoldDomain(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Fatal("expected tenant_scoped to require scaffold_tenancy")
+		}
+
+		if result, err := scaffoldTenancy(registry, types.ScaffoldTenancyInput{}); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold tenancy: %v %s", err, result.Message)
+		}
+		if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold tenant-scoped domain: %v %s", err, result.Message)
+		}
+		if !strings.Contains(readFile(t, filepath.Join(tmpDir, "internal", "models", "invoice.go")), "TenantID  uint") {
+			t.Error("expected invoice to have a TenantID")
+		}
+
+		cached := types.ScaffoldDomainInput{DomainName: "tag", Fields: []types.FieldDef{{Name: "Name", Type: "string"}}, TenantScoped: true, WithCache: true}
+		if result, _ := scaffoldDomain(registry, cached); result.Success {
+			t.Error("expected tenant_scoped with with_cache to fail")
+		}
+
+		seed, err := scaffoldSeed(registry, types.ScaffoldSeedInput{Domain: "invoice", Fields: input.Fields})
+		if err != nil || !seed.Success {
+			t.Fatalf("failed to scaffold seeder: %v %s", err, seed.Message)
+		}
+		if !strings.Contains(readFile(t, filepath.Join(tmpDir, "cmd", "seed", "seeders", "invoice_seeder.go")), "return tenancy.ForEachTenant(ctx, s.db, s.Seed)") {
+			t.Error("expected the invoice seeder to seed every tenant")
+		}
+	})
+}
@@ -0,0 +1,136 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterScaffoldTests registers the scaffold_tests tool.
+func RegisterScaffoldTests(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_tests",
+		Description: `Generate table-driven unit tests for a domain's service and controller.
+
+Generates:
+- internal/services/{domain}/repository_mock_test.go: mockRepository, an in-memory Repository with
+  an err field that makes every method fail, for testing error paths
+- internal/services/{domain}/{domain}_test.go: tests for Create (fields are stored, repository
+  errors are returned), GetByID, List (pagination), Update (given fields change, others are kept)
+  and Delete, including not-found IDs
+
+Domains with optimistic locking also test stale versions, with scheduled publishing invalid
+schedules, with bulk actions BulkDelete and with moderation Approve, Reject and moderation hooks.
+The mock ignores query options, so filtering and ordering are left to repository tests.
+
+Generates for the domain's HTML controller (not in API-only projects):
+- internal/web/{domain}/{domain}_test.go: httptest tests against a stub service for Create and
+  Update (every form value reaches the service, redirects, HTMX and JSON responses, service
+  errors re-render the form), checkboxes with their hidden "false" field, Delete, not-found
+  and invalid IDs. Authenticated and admin domains also test the route group's middleware:
+  anonymous requests go to /login and, for admin domains, signed-in users get 403
+
+The domain must have been created with scaffold_domain (it is read from .mcp/scaffold-metadata.json).
+Existing test files are not overwritten: they are reported as conflicts with the proposed content.
+
+Example:
+  scaffold_tests: { domain: "product" }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldTestsInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldTests)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldTests(registry *Registry, input types.ScaffoldTestsInput) (types.ScaffoldResult, error) {
+	if err := utils.ValidateDomainName(input.Domain); err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+
+	// Get module path from go.mod
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	metaStore := metadata.NewStore(registry.WorkingDir)
+	meta, err := metaStore.Load()
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to read scaffold metadata: %v", err)), nil
+	}
+	domain, ok := meta.Domains[input.Domain]
+	if !ok {
+		return types.NewErrorResult(fmt.Sprintf("no scaffold metadata for domain '%s': scaffold it with scaffold_domain first", input.Domain)), nil
+	}
+
+	// The service constructor takes a logger in projects with structured logging
+	data := generator.NewDomainData(domain.Input, modulePath)
+	data.WithLogging = registry.HasStructuredLogging()
+
+	serviceDir := filepath.Join("internal", "services", data.PackageName)
+	servicePath := filepath.Join(serviceDir, data.PackageName+".go")
+	if !utils.FileExists(filepath.Join(registry.WorkingDir, servicePath)) {
+		return types.NewErrorResult(fmt.Sprintf("service not found at %s: scaffold the domain with scaffold_domain first", servicePath)), nil
+	}
+
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+
+	files := []struct{ template, output string }{
+		{"tests/repository_mock_test.go.tmpl", filepath.Join(serviceDir, "repository_mock_test.go")},
+		{"tests/service_test.go.tmpl", filepath.Join(serviceDir, data.PackageName+"_test.go")},
+	}
+
+	// API-only projects have JSON controllers without forms, so only HTML controllers get tests
+	controllerDir := filepath.Join("internal", "web", data.PackageName)
+	withControllerTests := !registry.IsAPIProject() && utils.FileExists(filepath.Join(registry.WorkingDir, controllerDir, data.PackageName+".go"))
+	if withControllerTests {
+		files = append(files, struct{ template, output string }{
+			"tests/controller_test.go.tmpl", filepath.Join(controllerDir, data.PackageName+"_test.go"),
+		})
+	}
+	for _, f := range files {
+		if err := gen.GenerateFile(f.template, f.output, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.output, err)), nil
+		}
+	}
+
+	result := gen.Result()
+
+	// Tests the team has extended are never overwritten
+	if conflictResult := CheckForConflicts(result); conflictResult != nil {
+		return *conflictResult, nil
+	}
+
+	nextSteps := []string{
+		fmt.Sprintf("go test ./internal/services/%s/", data.PackageName),
+		fmt.Sprintf("Add cases for the domain's business rules to the tables in %s_test.go", data.PackageName),
+	}
+	if withControllerTests {
+		nextSteps = append(nextSteps, fmt.Sprintf("templ generate && go test ./internal/web/%s/", data.PackageName))
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would create tests for '%s'", input.Domain),
+			FilesCreated: result.FilesCreated,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully created tests for '%s'", input.Domain),
+		FilesCreated: result.FilesCreated,
+		NextSteps:    nextSteps,
+	}, nil
+}
@@ -0,0 +1,290 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldTests(t *testing.T) {
+	setup := func(t *testing.T, input types.ScaffoldDomainInput) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+		return registry, tmpDir
+	}
+	product := types.ScaffoldDomainInput{
+		DomainName: "product",
+		Fields: []types.FieldDef{
+			{Name: "Name", Type: "string"},
+			{Name: "Price", Type: "float64"},
+			{Name: "ReleasedAt", Type: "*time.Time"},
+		},
+	}
+
+	t.Run("generates service tests and an in-memory repository", func(t *testing.T) {
+		registry, tmpDir := setup(t, product)
+
+		result, err := scaffoldTests(registry, types.ScaffoldTestsInput{Domain: "product"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		serviceDir := filepath.Join("internal", "services", "product")
+		checks := map[string][]string{
+			filepath.Join(serviceDir, "repository_mock_test.go"): {
+				"productrepo.Repository\n",
+				"func newMockRepository(records ...models.Product) *mockRepository",
+				"func (r *mockRepository) FindAll(",
+			},
+			filepath.Join(serviceDir, "product_test.go"): {
+				"func TestServiceCreate(t *testing.T)",
+				`Name: "Name 1",`,
+				"Price: float64(1.5),",
+				"ReleasedAt: ptr(time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)),",
+				`Name: ptr("Name 2"),`,
+				"wantErr: ErrProductNotFound",
+				"func TestServiceList(t *testing.T)",
+				"func TestServiceDelete(t *testing.T)",
+			},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, serviceDir, "product_test.go"))
+		for _, unwanted := range []string{"TestServiceBulkDelete", "TestServiceModeration", "ErrProductConflict"} {
+			if strings.Contains(content, unwanted) {
+				t.Errorf("expected no %s without the domain option", unwanted)
+			}
+		}
+	})
+
+	t.Run("covers the domain options", func(t *testing.T) {
+		input := product
+		input.WithOptimisticLocking = true
+		input.WithBulkActions = true
+		input.WithModeration = true
+		input.WithScheduling = true
+		registry, tmpDir := setup(t, input)
+
+		result, err := scaffoldTests(registry, types.ScaffoldTestsInput{Domain: "product"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		mock := readFile(t, filepath.Join(tmpDir, "internal", "services", "product", "repository_mock_test.go"))
+		for _, want := range []string{"return productrepo.ErrVersionConflict", "func (r *mockRepository) DeleteByIDs(", "func (r *mockRepository) UpdateModerationStatus("} {
+			if !strings.Contains(mock, want) {
+				t.Errorf("expected mock repository to contain %q", want)
+			}
+		}
+		tests := readFile(t, filepath.Join(tmpDir, "internal", "services", "product", "product_test.go"))
+		for _, want := range []string{"wantErr: ErrProductConflict", "wantErr: ErrProductInvalidSchedule", "func TestServiceBulkDelete(", "moderate: Service.Approve"} {
+			if !strings.Contains(tests, want) {
+				t.Errorf("expected service tests to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("keeps fixtures to the field rules and rejects each broken rule", func(t *testing.T) {
+		minQty, maxSku := float64(5), float64(10)
+		input := types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields: []types.FieldDef{
+				{Name: "Sku", Type: "string", Required: true, Max: &maxSku, Pattern: `[A-Z]{3}-\d+`},
+				{Name: "Qty", Type: "int", Min: &minQty},
+			},
+		}
+		registry, tmpDir := setup(t, input)
+
+		result, err := scaffoldTests(registry, types.ScaffoldTestsInput{Domain: "product"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "internal", "services", "product", "product_test.go"))
+		for _, want := range []string{
+			`Sku: "AAA-0",`,
+			"Qty: 5,",
+			`Sku: ptr("BBB-1"),`,
+			"wantInvalid ValidationError",
+			`name: "rejects an empty Sku",`,
+			`wantInvalid: ValidationError{"sku": "Sku must be at most 10 characters"},`,
+			`wantInvalid: ValidationError{"sku": "Sku is not in the expected format"},`,
+			`wantInvalid: ValidationError{"qty": "Qty must be at least 5"},`,
+			"Qty: ptr(4),",
+			"if !errors.As(err, &invalid) || !reflect.DeepEqual(invalid, tt.wantInvalid) {",
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected service tests to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("generates controller tests", func(t *testing.T) {
+		input := product
+		input.Fields = append(input.Fields, types.FieldDef{Name: "Active", Type: "bool", FormType: "checkbox"})
+		input.RouteGroup = "admin"
+		registry, tmpDir := setup(t, input)
+
+		result, err := scaffoldTests(registry, types.ScaffoldTestsInput{Domain: "product"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "internal", "web", "product", "product_test.go"))
+		for _, want := range []string{
+			"productsvc.Service\n",
+			`form.Set("name", "Name 1")`,
+			`form.Set("price", "1.5")`,
+			"service.created.Price, float64(1.5); got != want",
+			"service.updated.Name, \"Name 2\"; got == nil || *got != want",
+			"func TestControllerCheckboxes(t *testing.T)",
+			`form["active"] = tt.values`,
+			"func TestControllerDelete(t *testing.T)",
+			"r.Use(authMiddleware.RequireAdmin)",
+			`name: "forbids users who are not admins"`,
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected controller tests to contain %q", want)
+			}
+		}
+		if strings.Contains(content, `"released_at"`) {
+			t.Error("expected no form value for a pointer field")
+		}
+	})
+
+	t.Run("stubs the ownership checks of owned domains", func(t *testing.T) {
+		owned := true
+		input := product
+		input.RouteGroup = "authenticated"
+		input.OwnedByUser = &owned
+		input.WithBulkActions = true
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		// Owned domains need the session user of an auth project
+		authPath := filepath.Join(tmpDir, "internal", "web", "middleware", "auth.go")
+		if err := os.MkdirAll(filepath.Dir(authPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(authPath, []byte("package middleware\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+
+		result, err := scaffoldTests(registry, types.ScaffoldTestsInput{Domain: "product"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "internal", "web", "product", "product_test.go"))
+		for _, want := range []string{
+			"func (s *stubService) GetOwnedByID(ctx context.Context, id, userID uint) (*models.Product, error) {",
+			"func (s *stubService) OwnedIDs(ctx context.Context, ids []uint, userID uint) ([]uint, error) {",
+			"func TestControllerOwnership(t *testing.T) {",
+			"service := &stubService{ownerID: 42}",
+			"service.ownerID = owner.ID",
+			`name: "does not find other users' products"`,
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected controller tests to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("generates no controller tests in API projects", func(t *testing.T) {
+		registry, tmpDir := setup(t, product)
+		if err := metadata.NewStore(tmpDir).SaveProject(types.ScaffoldProjectInput{ProjectName: "testapp", ProjectType: "api"}, ScaffolderVersion); err != nil {
+			t.Fatalf("failed to save project metadata: %v", err)
+		}
+
+		result, err := scaffoldTests(registry, types.ScaffoldTestsInput{Domain: "product"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success || len(result.FilesCreated) != 2 {
+			t.Fatalf("expected the service tests only, got %v: %s", result.FilesCreated, result.Message)
+		}
+		if fileExists(filepath.Join(tmpDir, "internal", "web", "product", "product_test.go")) {
+			t.Error("expected no controller tests in an API project")
+		}
+	})
+
+	t.Run("reports existing tests as conflicts", func(t *testing.T) {
+		registry, tmpDir := setup(t, product)
+		testPath := filepath.Join(tmpDir, "internal", "services", "product", "product_test.go")
+		if err := os.WriteFile(testPath, []byte("package product\n"), 0644); err != nil {
+			t.Fatalf("failed to write tests: %v", err)
+		}
+
+		result, err := scaffoldTests(registry, types.ScaffoldTestsInput{Domain: "product"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || len(result.Conflicts) == 0 {
+			t.Fatalf("expected a conflict, got: %s", result.Message)
+		}
+		if content := readFile(t, testPath); content != "package product\n" {
+			t.Errorf("expected existing tests to be kept, got:\n%s", content)
+		}
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		registry, tmpDir := setup(t, product)
+
+		result, err := scaffoldTests(registry, types.ScaffoldTestsInput{Domain: "product", DryRun: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success || len(result.FilesCreated) != 3 {
+			t.Fatalf("expected three files to be listed, got %v: %s", result.FilesCreated, result.Message)
+		}
+		if fileExists(filepath.Join(tmpDir, "internal", "services", "product", "product_test.go")) {
+			t.Error("expected dry run not to write tests")
+		}
+	})
+
+	t.Run("requires a scaffolded domain", func(t *testing.T) {
+		registry, _ := setup(t, product)
+
+		for _, domain := range []string{"order", "Bad Name"} {
+			result, err := scaffoldTests(registry, types.ScaffoldTestsInput{Domain: domain})
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Success {
+				t.Errorf("expected failure for domain %q", domain)
+			}
+		}
+	})
+}
@@ -0,0 +1,210 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// tsTypesDir is the directory of the generated TypeScript types.
+var tsTypesDir = filepath.Join("web", "src", "types")
+
+// tsTypesFormat names the templates and files of a scaffold_ts_types format.
+type tsTypesFormat struct {
+	domainTemplate string
+	commonTemplate string
+	indexTemplate  string
+	// ext is the extension of the generated files
+	ext   string
+	index string
+}
+
+// tsTypesFormats are the formats of scaffold_ts_types.
+var tsTypesFormats = map[string]tsTypesFormat{
+	"dts": {
+		domainTemplate: "typescript/types.d.ts.tmpl",
+		commonTemplate: "typescript/common.d.ts.tmpl",
+		indexTemplate:  "typescript/index.d.ts.tmpl",
+		ext:            ".d.ts",
+		index:          "index.d.ts",
+	},
+	"zod": {
+		domainTemplate: "typescript/schemas.ts.tmpl",
+		commonTemplate: "typescript/common.ts.tmpl",
+		indexTemplate:  "typescript/index.ts.tmpl",
+		ext:            ".ts",
+		index:          "index.ts",
+	},
+}
+
+// tsTypesPath returns the path of a domain's TypeScript types.
+func tsTypesPath(domainName, format string) string {
+	return filepath.Join(tsTypesDir, utils.ToKebabCase(domainName)+tsTypesFormats[format].ext)
+}
+
+// tsTypesExport returns the line of the types index that re-exports a domain's types.
+func tsTypesExport(domainName string) string {
+	return fmt.Sprintf("export * from \"./%s\";", utils.ToKebabCase(domainName))
+}
+
+// RegisterScaffoldTSTypes registers the scaffold_ts_types tool.
+func RegisterScaffoldTSTypes(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_ts_types",
+		Description: `Generate TypeScript types for the domain DTOs in web/src/types.
+
+Frontend code next to the server-rendered app (islands, widgets, fetch calls) gets the same
+shapes as the Go DTOs: the response, the create and update inputs and the list page of each domain.
+
+Formats:
+- dts (default): TypeScript declarations, web/src/types/{domain}.d.ts. No dependencies.
+- zod: zod schemas with their inferred types, web/src/types/{domain}.ts, to validate responses
+  and form input at run time (npm install zod)
+
+Generated files:
+- web/src/types/{domain}.d.ts or .ts: one per domain
+- web/src/types/common: the ListResult page envelope and the ErrorResponse body
+- web/src/types/index: re-exports every domain between MCP:TYPES markers
+
+JSON field names match the DTO json tags. Times are ISO 8601 strings; pointer and slice fields
+may be null; fields of custom Go types are unknown.
+
+The types stay in sync with the domains: scaffold_domain adds the types of new domains, and
+sync_domain regenerates the types of a domain with its other files.
+
+Domains are read from scaffold metadata (.mcp/scaffold-metadata.json). domains limits the
+types to a subset; by default all are included. Re-running the tool regenerates (overwrites)
+web/src/types.`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldTSTypesInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldTSTypes)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldTSTypes(registry *Registry, input types.ScaffoldTSTypesInput) (types.ScaffoldResult, error) {
+	if input.Format == "" {
+		input.Format = "dts"
+	}
+	format, ok := tsTypesFormats[input.Format]
+	if !ok {
+		return types.NewErrorResult(fmt.Sprintf("invalid format '%s': must be dts or zod", input.Format)), nil
+	}
+	if existing := registry.TSTypesFormat(); existing != "" && existing != input.Format {
+		return types.NewErrorResult(fmt.Sprintf("web/src/types already has %s types: delete web/src/types to switch to %s", existing, input.Format)), nil
+	}
+
+	// Get module path from go.mod
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+
+	metaStore := metadata.NewStore(registry.WorkingDir)
+	meta, err := metaStore.Load()
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to read scaffold metadata: %v", err)), nil
+	}
+
+	names := input.Domains
+	if len(names) == 0 {
+		for name := range meta.Domains {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	if len(names) == 0 {
+		return types.NewErrorResult("no domains with metadata found: scaffold a domain first, or check .mcp/scaffold-metadata.json exists"), nil
+	}
+
+	domains := make([]generator.DomainData, 0, len(names))
+	var missing []string
+	for _, name := range names {
+		domain, ok := meta.Domains[name]
+		if !ok {
+			missing = append(missing, name)
+			continue
+		}
+		domains = append(domains, generator.NewDomainData(domain.Input, modulePath))
+	}
+	if len(missing) > 0 {
+		return types.NewErrorResult(fmt.Sprintf("no scaffold metadata for domain(s): %s", strings.Join(missing, ", "))), nil
+	}
+
+	// Create generator
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+	gen.SetForceOverwrite(true)
+
+	if err := gen.EnsureDir(tsTypesDir); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to create directory: %v", err)), nil
+	}
+
+	data := generator.TypeScriptData{Domains: domains}
+	if err := gen.GenerateFile(format.commonTemplate, filepath.Join(tsTypesDir, "common"+format.ext), data); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to generate common types: %v", err)), nil
+	}
+	if err := gen.GenerateFile(format.indexTemplate, filepath.Join(tsTypesDir, format.index), data); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to generate types index: %v", err)), nil
+	}
+	for _, domain := range domains {
+		if err := gen.GenerateFile(format.domainTemplate, tsTypesPath(domain.DomainName, input.Format), domain); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s types: %v", domain.DomainName, err)), nil
+		}
+	}
+
+	result := gen.Result()
+
+	nextSteps := []string{
+		fmt.Sprintf("import type { %s } from \"./types\";", domains[0].ModelName),
+	}
+	if input.Format == "zod" {
+		nextSteps = []string{
+			"npm install zod",
+			fmt.Sprintf("import { %sSchema } from \"./types\"; %sSchema.parse(await response.json())", domains[0].ModelName, domains[0].ModelName),
+		}
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would create %s types for %d domain(s)", input.Format, len(domains)),
+			FilesCreated: result.FilesCreated,
+			FilesUpdated: result.FilesUpdated,
+			Backups:      result.Backups,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully created %s types for %d domain(s)", input.Format, len(domains)),
+		FilesCreated: result.FilesCreated,
+		FilesUpdated: result.FilesUpdated,
+		Backups:      result.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// injectTSTypesExport adds a domain to the index of the TypeScript types.
+func injectTSTypesExport(workingDir, domainName, format string) error {
+	injector, err := modifier.NewInjector(filepath.Join(workingDir, tsTypesDir, tsTypesFormats[format].index))
+	if err != nil {
+		return err
+	}
+	if err := injector.InjectBetweenMarkers(modifier.MarkerTypesStart, modifier.MarkerTypesEnd, tsTypesExport(domainName)); err != nil {
+		return err
+	}
+	return injector.Save()
+}
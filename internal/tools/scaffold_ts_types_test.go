@@ -0,0 +1,149 @@
+package tools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldTSTypes(t *testing.T) {
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+
+		for _, input := range []types.ScaffoldDomainInput{
+			{DomainName: "customer", Fields: []types.FieldDef{{Name: "Name", Type: "string", Required: true}}},
+			{
+				DomainName:    "order_item",
+				Fields:        []types.FieldDef{{Name: "Quantity", Type: "int"}, {Name: "ShippedAt", Type: "*time.Time"}},
+				Relationships: []types.RelationshipDef{{Type: "belongs_to", Model: "Customer"}},
+			},
+		} {
+			if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+				t.Fatalf("failed to scaffold domain %s: %v %s", input.DomainName, err, result.Message)
+			}
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("generates declarations for every domain", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := scaffoldTSTypes(registry, types.ScaffoldTSTypesInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		typesDir := filepath.Join(tmpDir, "web", "src", "types")
+		index := readFile(t, filepath.Join(typesDir, "index.d.ts"))
+		for _, want := range []string{`export * from "./common";`, `export * from "./customer";`, `export * from "./order-item";`} {
+			if !strings.Contains(index, want) {
+				t.Errorf("expected index.d.ts to contain %q", want)
+			}
+		}
+
+		content := readFile(t, filepath.Join(typesDir, "order-item.d.ts"))
+		for _, want := range []string{
+			"export interface OrderItem {",
+			"  quantity: number;",
+			"  shipped_at: string | null;",
+			"  customer?: OrderItemCustomerSummary;",
+			"export type OrderItemList = ListResult<OrderItem>;",
+			"export interface CreateOrderItemInput {",
+			"  customer_id: number;",
+			"  quantity?: number;",
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected order-item.d.ts to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("generates zod schemas", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := scaffoldTSTypes(registry, types.ScaffoldTSTypesInput{Format: "zod", Domains: []string{"customer"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		content := readFile(t, filepath.Join(tmpDir, "web", "src", "types", "customer.ts"))
+		for _, want := range []string{
+			`import { z } from "zod";`,
+			"export const CustomerSchema = z.object({",
+			"  created_at: z.string().datetime({ offset: true }),",
+			"export type Customer = z.infer<typeof CustomerSchema>;",
+			"  name: z.string().min(1),",
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected customer.ts to contain %q", want)
+			}
+		}
+		if fileExists(filepath.Join(tmpDir, "web", "src", "types", "order-item.ts")) {
+			t.Error("expected order items to be left out")
+		}
+	})
+
+	t.Run("scaffold_domain adds the types of new domains", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		if result, err := scaffoldTSTypes(registry, types.ScaffoldTSTypesInput{Format: "zod"}); err != nil || !result.Success {
+			t.Fatalf("failed to generate types: %v %s", err, result.Message)
+		}
+		if result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{DomainName: "invoice", Fields: []types.FieldDef{{Name: "Total", Type: "float64"}}}); err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+
+		typesDir := filepath.Join(tmpDir, "web", "src", "types")
+		if !strings.Contains(readFile(t, filepath.Join(typesDir, "invoice.ts")), "  total: z.number(),") {
+			t.Error("expected the invoice schema")
+		}
+		if !strings.Contains(readFile(t, filepath.Join(typesDir, "index.ts")), `export * from "./invoice";`) {
+			t.Error("expected the index to export the invoice types")
+		}
+
+		result, err := syncDomain(registry, types.SyncDomainInput{Domain: "invoice"})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.FilesUpdated) > 0 {
+			t.Errorf("expected the invoice types to be in sync, got updates: %v", result.FilesUpdated)
+		}
+	})
+
+	t.Run("validates input", func(t *testing.T) {
+		registry, _ := setup(t)
+		if result, err := scaffoldTSTypes(registry, types.ScaffoldTSTypesInput{}); err != nil || !result.Success {
+			t.Fatalf("failed to generate types: %v %s", err, result.Message)
+		}
+
+		tests := []struct {
+			name  string
+			input types.ScaffoldTSTypesInput
+		}{
+			{"unknown format", types.ScaffoldTSTypesInput{Format: "flow"}},
+			{"other format than the existing types", types.ScaffoldTSTypesInput{Format: "zod"}},
+			{"unknown domain", types.ScaffoldTSTypesInput{Domains: []string{"refund"}}},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := scaffoldTSTypes(registry, tt.input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success {
+					t.Error("expected failure")
+				}
+			})
+		}
+	})
+}
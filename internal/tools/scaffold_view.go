@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"path/filepath"
 
+	"github.com/dbb1dev/go-mcp/internal/config"
 	"github.com/dbb1dev/go-mcp/internal/generator"
 	"github.com/dbb1dev/go-mcp/internal/types"
 	"github.com/dbb1dev/go-mcp/internal/utils"
@@ -82,7 +83,7 @@ Examples:
 
 Run 'templ generate' after creating views.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldViewInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldView(registry, input)
+		result, err := locked(scaffoldView)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -91,11 +92,23 @@ Run 'templ generate' after creating views.`,
 }
 
 func scaffoldView(registry *Registry, input types.ScaffoldViewInput) (types.ScaffoldResult, error) {
+	// API-only projects have no views
+	if registry.IsAPIProject() {
+		return noViewsInAPIProject("scaffold_view"), nil
+	}
+
 	// Validate input - support nested paths like "admin/users"
 	if err := utils.ValidateDomainPath(input.DomainName); err != nil {
 		return types.NewErrorResult(err.Error()), nil
 	}
 
+	// Fill in the project defaults of .gomcp.toml
+	defaults, err := registry.Defaults()
+	if err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	input.Layout = config.Or(input.Layout, defaults.Layout)
+
 	if err := utils.ValidateViewType(input.ViewType); err != nil {
 		return types.NewErrorResult(err.Error()), nil
 	}
@@ -196,6 +209,7 @@ func scaffoldView(registry *Registry, input types.ScaffoldViewInput) (types.Scaf
 		Message:        fmt.Sprintf("Successfully created %s view '%s' for domain '%s'", input.ViewType, input.ViewName, input.DomainName),
 		FilesCreated:   result.FilesCreated,
 		FilesUpdated:   result.FilesUpdated,
+		Backups:        result.Backups,
 		NextSteps:      nextSteps,
 		SuggestedTools: suggestedTools,
 	}, nil
@@ -0,0 +1,328 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// webhookProviders describes the inbound webhook providers, by name.
+var webhookProviders = map[string]generator.WebhookProviderData{
+	"stripe":  {Name: "stripe", Label: "Stripe", SecretEnv: "STRIPE_WEBHOOK_SECRET", Verifier: "StripeVerifier"},
+	"github":  {Name: "github", Label: "GitHub", SecretEnv: "GITHUB_WEBHOOK_SECRET", Verifier: "GitHubVerifier"},
+	"generic": {Name: "generic", Label: "Generic", SecretEnv: "WEBHOOK_SECRET", Verifier: "Verify"},
+}
+
+// RegisterScaffoldWebhooks registers the scaffold_webhooks tool.
+func RegisterScaffoldWebhooks(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "scaffold_webhooks",
+		Description: `Add webhooks: signed outbound deliveries of domain events, and inbound webhook receivers.
+
+Generates:
+- internal/models/webhook.go: WebhookEndpoint (URL, Secret, Events, Active) and WebhookDelivery
+  (one row per event and endpoint, with its attempts and outcome), migrated by RunMigrations
+- internal/webhooks/webhooks.go: the Dispatcher. Emit stores a delivery for every active endpoint
+  subscribed to the event type; CreateEndpoint registers a URL and generates its secret;
+  Redeliver retries a delivery. Deliveries are signed with the endpoint secret:
+  X-Webhook-Signature: t=<unix time>,v1=<hex HMAC-SHA256 of "<t>.<body>">
+- internal/webhooks/delivery.go: Deliver, run by the scheduler, posts the due deliveries.
+  Failures are retried with exponential backoff (1m, 2m, 4m, ... up to 6h) until max_attempts
+  (default 10, WEBHOOK_MAX_ATTEMPTS at runtime)
+- internal/services/{domain}/webhooks.go: NewWebhookService, a Service decorator emitting
+  {domain}.created, {domain}.updated and {domain}.deleted after successful writes
+
+With providers (stripe, github, generic):
+- internal/webhooks/inbound.go: Receiver and the signature verifiers (Stripe-Signature,
+  X-Hub-Signature-256, X-Webhook-Signature), with a 5 minute replay tolerance for timestamps
+- internal/web/webhooks/webhooks.go: POST /webhooks/{provider} with a handler to fill in per
+  provider. Secrets come from STRIPE_WEBHOOK_SECRET, GITHUB_WEBHOOK_SECRET and WEBHOOK_SECRET;
+  a receiver without its secret answers 503. Web projects exempt /webhooks/ from CSRF.
+
+Wires database.go (migrations) and main.go (the dispatcher, the service decorators, the delivery
+task and the receiver routes). domains lists the domains that emit events (default: every domain
+in the scaffold metadata). Re-running the tool adds domains and keeps the existing files.
+
+Example:
+  scaffold_webhooks: { domains: ["order"], providers: ["stripe", "github"] }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldWebhooksInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(scaffoldWebhooks)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func scaffoldWebhooks(registry *Registry, input types.ScaffoldWebhooksInput) (types.ScaffoldResult, error) {
+	data := generator.WebhooksData{
+		MaxAttempts: input.MaxAttempts,
+		WithCSRF:    !registry.IsAPIProject(),
+	}
+	if data.MaxAttempts == 0 {
+		data.MaxAttempts = 10
+	}
+	if data.MaxAttempts < 1 || data.MaxAttempts > 50 {
+		return types.NewErrorResult(fmt.Sprintf("invalid max_attempts %d: must be between 1 and 50", input.MaxAttempts)), nil
+	}
+
+	seen := map[string]bool{}
+	for _, name := range input.Providers {
+		if err := utils.ValidateWebhookProvider(name); err != nil {
+			return types.NewErrorResult(err.Error()), nil
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		data.Providers = append(data.Providers, webhookProviders[name])
+	}
+	data.HasStripe, data.HasGitHub, data.HasGeneric = seen["stripe"], seen["github"], seen["generic"]
+
+	// Get module path from go.mod
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to get module path: %v", err)), nil
+	}
+	data.ModulePath = modulePath
+
+	meta, err := metadata.NewStore(registry.WorkingDir).Load()
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to read scaffold metadata: %v", err)), nil
+	}
+
+	var domains []string
+	if len(input.Domains) == 0 {
+		for name := range meta.Domains {
+			domains = append(domains, name)
+		}
+		sort.Strings(domains)
+	} else {
+		for _, name := range input.Domains {
+			if _, ok := meta.Domains[name]; !ok {
+				return types.NewErrorResult(fmt.Sprintf("no scaffold metadata for domain '%s': scaffold it with scaffold_domain first", name)), nil
+			}
+			domains = appendUnique(domains, name)
+		}
+	}
+
+	// Create generator; existing webhook files are kept so re-runs only add domains
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+
+	type templateFile struct {
+		template string
+		output   string
+	}
+	files := []templateFile{
+		{"webhooks/model.go.tmpl", filepath.Join("internal", "models", "webhook.go")},
+		{"webhooks/webhooks.go.tmpl", filepath.Join("internal", "webhooks", "webhooks.go")},
+		{"webhooks/delivery.go.tmpl", filepath.Join("internal", "webhooks", "delivery.go")},
+		{"scheduler/scheduler.go.tmpl", filepath.Join("internal", "scheduler", "scheduler.go")},
+	}
+	if len(data.Providers) > 0 {
+		files = append(files,
+			templateFile{"webhooks/inbound.go.tmpl", filepath.Join("internal", "webhooks", "inbound.go")},
+			templateFile{"webhooks/receivers.go.tmpl", filepath.Join("internal", "web", "webhooks", "webhooks.go")},
+		)
+	}
+	for _, f := range files {
+		if err := gen.GenerateFileIfNotExists(f.template, f.output, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", f.output, err)), nil
+		}
+	}
+
+	for _, name := range domains {
+		pkgName := utils.ToPackageName(name)
+		domainData := generator.WebhookDomainData{
+			ModulePath:      modulePath,
+			PackageName:     pkgName,
+			ModelName:       utils.ToModelName(name),
+			VariableName:    utils.ToVariableName(name),
+			EventPrefix:     utils.ToSnakeCase(name),
+			WithBulkActions: meta.Domains[name].Input.GetWithBulkActions(),
+		}
+		servicePath := filepath.Join("internal", "services", pkgName, "webhooks.go")
+		if err := gen.GenerateFileIfNotExists("webhooks/service_events.go.tmpl", servicePath, domainData); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate %s: %v", servicePath, err)), nil
+		}
+	}
+
+	result := gen.Result()
+
+	var unwired []string
+	schedulerMissing := false
+	if !input.DryRun {
+		databaseGoPath := filepath.Join(registry.WorkingDir, "internal", "database", "database.go")
+		if utils.FileExists(databaseGoPath) {
+			if err := injectWebhookModels(databaseGoPath); err != nil {
+				fmt.Printf("Warning: could not migrate the webhook models in database.go: %v\n", err)
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, "internal/database/database.go")
+			}
+		}
+
+		mainGoPath := filepath.Join(registry.WorkingDir, "cmd", "web", "main.go")
+		if utils.FileExists(mainGoPath) {
+			var err error
+			unwired, schedulerMissing, err = injectWebhookWiring(mainGoPath, modulePath, domains, len(data.Providers) > 0)
+			if err != nil {
+				fmt.Printf("Warning: could not wire webhooks into main.go: %v\n", err)
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, "cmd/web/main.go")
+			}
+		}
+
+		routerGoPath := filepath.Join(registry.WorkingDir, "internal", "web", "router.go")
+		if data.WithCSRF && len(data.Providers) > 0 && utils.FileExists(routerGoPath) {
+			if err := injectWebhookCSRFExemption(routerGoPath, modulePath); err != nil {
+				fmt.Printf("Warning: could not exempt the webhook receivers from CSRF in router.go: %v\n", err)
+			} else {
+				result.FilesUpdated = append(result.FilesUpdated, "internal/web/router.go")
+			}
+		}
+	}
+
+	nextSteps := []string{
+		"Register an endpoint: webhookDispatcher.CreateEndpoint(ctx, \"https://example.com/hooks\", \"order.created\") and share its Secret with the receiver",
+		"Emit other events from your services with webhooks.Emitter.Emit(ctx, \"invoice.paid\", invoice)",
+	}
+	if schedulerMissing {
+		nextSteps = append(nextSteps, "Start the deliveries in cmd/web/main.go: scheduler.Start(\"webhook deliveries\", webhookDispatcher.Deliver)")
+	}
+	for _, name := range unwired {
+		varName := utils.ToServiceVariableName(name)
+		nextSteps = append(nextSteps, fmt.Sprintf("Wrap the %s service in cmd/web/main.go: %s = %s.NewWebhookService(%s, webhookDispatcher)", name, varName, utils.ToServiceImportAlias(name), varName))
+	}
+	if len(data.Providers) > 0 {
+		var secrets []string
+		for _, provider := range data.Providers {
+			secrets = append(secrets, provider.SecretEnv)
+		}
+		nextSteps = append(nextSteps,
+			fmt.Sprintf("Set %s, then fill in the handlers in internal/web/webhooks/webhooks.go", strings.Join(secrets, ", ")),
+			"Point the providers at https://<your host>/webhooks/{provider}, sending application/json",
+		)
+	}
+
+	if input.DryRun {
+		return types.ScaffoldResult{
+			Success:      true,
+			Message:      fmt.Sprintf("Dry run: Would add webhooks (%d domains, %d receivers)", len(domains), len(data.Providers)),
+			FilesCreated: result.FilesCreated,
+			FilesUpdated: result.FilesUpdated,
+			Backups:      result.Backups,
+			NextSteps:    nextSteps,
+		}, nil
+	}
+
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      fmt.Sprintf("Successfully added webhooks (%d domains, %d receivers)", len(domains), len(data.Providers)),
+		FilesCreated: result.FilesCreated,
+		FilesUpdated: result.FilesUpdated,
+		Backups:      result.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// injectWebhookModels migrates the webhook models.
+func injectWebhookModels(databaseGoPath string) error {
+	injector, err := modifier.NewInjector(databaseGoPath)
+	if err != nil {
+		return err
+	}
+	for _, model := range []string{"WebhookEndpoint", "WebhookDelivery"} {
+		if err := injector.InjectModel(model); err != nil {
+			return err
+		}
+	}
+	return injector.Save()
+}
+
+// injectWebhookWiring creates the dispatcher in main.go, wraps the domain services, starts the
+// deliveries and mounts the receivers. It returns the domains whose service was not found and
+// whether main.go predates the MCP:SCHEDULER markers.
+func injectWebhookWiring(mainGoPath, modulePath string, domains []string, withReceivers bool) ([]string, bool, error) {
+	injector, err := modifier.NewInjector(mainGoPath)
+	if err != nil {
+		return nil, false, err
+	}
+	schedulerMissing := !injector.HasMarker(modifier.MarkerSchedulerStart)
+	if schedulerMissing {
+		if err := injector.InjectBetweenMarkers(modifier.MarkerServicesStart, modifier.MarkerServicesEnd, modifier.WebhookDispatcherVariable+" := webhooks.NewDispatcher(db)"); err != nil {
+			return nil, false, err
+		}
+	} else {
+		if err := injector.InjectImport(modulePath + "/internal/scheduler"); err != nil {
+			return nil, false, err
+		}
+		if err := injector.InjectWebhookDispatcher(); err != nil {
+			return nil, false, err
+		}
+	}
+	if err := injector.InjectImport(modulePath + "/internal/webhooks"); err != nil {
+		return nil, false, err
+	}
+
+	var unwired []string
+	for _, name := range domains {
+		if err := injector.InjectWebhookService(name); err != nil {
+			unwired = append(unwired, name)
+		}
+	}
+
+	if withReceivers {
+		if err := injector.InjectImportWithAlias(modulePath+"/internal/web/webhooks", "webhooksweb"); err != nil {
+			return nil, false, err
+		}
+		if err := injector.InjectBetweenMarkers(modifier.MarkerControllersStart, modifier.MarkerControllersEnd, "webhooksController := webhooksweb.NewController()"); err != nil {
+			return nil, false, err
+		}
+		route := `router.Route("/webhooks", webhooksController.RegisterRoutes)`
+		if injector.HasMarker(modifier.MarkerRoutesPublicStart) {
+			err = injector.InjectBetweenMarkers(modifier.MarkerRoutesPublicStart, modifier.MarkerRoutesPublicEnd, route)
+		} else {
+			err = injector.InjectBetweenMarkers(modifier.MarkerRoutesStart, modifier.MarkerRoutesEnd, route)
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	return unwired, schedulerMissing, injector.Save()
+}
+
+// injectWebhookCSRFExemption runs webhooks.SkipCSRF before the CSRF middleware in router.go.
+func injectWebhookCSRFExemption(routerGoPath, modulePath string) error {
+	injector, err := modifier.NewInjector(routerGoPath)
+	if err != nil {
+		return err
+	}
+	content := injector.Content()
+	if strings.Contains(content, "webhooks.SkipCSRF") {
+		return nil
+	}
+	if !strings.Contains(content, "\tr.Use(middleware.CSRF(cfg))\n") {
+		return fmt.Errorf("CSRF middleware not found")
+	}
+	// Insert before the CSRF comment block, or right before the middleware without one
+	anchor := "\t// CSRF protection"
+	if !strings.Contains(content, anchor) {
+		anchor = "\tr.Use(middleware.CSRF(cfg))\n"
+	}
+	content = strings.Replace(content, anchor, "\t// Inbound webhooks are authenticated by their signature instead of a CSRF token\n\tr.Use(webhooks.SkipCSRF)\n\n"+anchor, 1)
+	injector = modifier.NewInjectorFromContent(content)
+	if err := injector.InjectImport(modulePath + "/internal/webhooks"); err != nil {
+		return err
+	}
+	return injector.SaveTo(routerGoPath)
+}
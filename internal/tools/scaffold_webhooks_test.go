@@ -0,0 +1,182 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestScaffoldWebhooks(t *testing.T) {
+	setup := func(t *testing.T) (*Registry, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		setupMainGo(t, tmpDir, `package main
+
+import (
+	"log"
+)
+
+func main() {
+	db := database.Connect(cfg)
+	// MCP:REPOS:START
+	// MCP:REPOS:END
+	// MCP:SERVICES:START
+	// MCP:SERVICES:END
+	// MCP:CONTROLLERS:START
+	// MCP:CONTROLLERS:END
+	// MCP:SCHEDULER:START
+	// MCP:SCHEDULER:END
+
+	router := web.NewRouter(cfg)
+
+	// MCP:ROUTES:START
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	// MCP:ROUTES:END
+}
+`)
+		setupWizardDatabaseGo(t, tmpDir, `package database
+
+import (
+	"github.com/example/testapp/internal/models"
+	"gorm.io/gorm"
+)
+
+func RunMigrations(db *gorm.DB) error {
+	return db.AutoMigrate(
+		// MCP:MODELS:START
+		// MCP:MODELS:END
+	)
+}
+`)
+		routerPath := filepath.Join(tmpDir, "internal", "web", "router.go")
+		if err := os.MkdirAll(filepath.Dir(routerPath), 0755); err != nil {
+			t.Fatalf("failed to create internal/web dir: %v", err)
+		}
+		router := "package web\n\nimport (\n\t\"github.com/go-chi/chi/v5\"\n)\n\nfunc NewRouter(cfg *config.Config) *chi.Mux {\n\tr := chi.NewRouter()\n\n\t// CSRF protection - uses session secret from config\n\tr.Use(middleware.CSRF(cfg))\n\treturn r\n}\n"
+		if err := os.WriteFile(routerPath, []byte(router), 0644); err != nil {
+			t.Fatalf("failed to write router.go: %v", err)
+		}
+
+		domains := []types.ScaffoldDomainInput{
+			{DomainName: "order", Fields: []types.FieldDef{{Name: "Total", Type: "float64"}}, WithBulkActions: true},
+			{DomainName: "blog_post", Fields: []types.FieldDef{{Name: "Title", Type: "string"}}},
+		}
+		for _, input := range domains {
+			if result, err := scaffoldDomain(registry, input); err != nil || !result.Success {
+				t.Fatalf("failed to scaffold %s: %v %s", input.DomainName, err, result.Message)
+			}
+		}
+		return registry, tmpDir
+	}
+
+	t.Run("generates outbound webhooks and receivers", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+
+		result, err := scaffoldWebhooks(registry, types.ScaffoldWebhooksInput{Providers: []string{"stripe", "github"}, MaxAttempts: 5})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		checks := map[string][]string{
+			filepath.Join("internal", "models", "webhook.go"):                {"type WebhookEndpoint struct", "type WebhookDelivery struct"},
+			filepath.Join("internal", "webhooks", "webhooks.go"):             {"const DefaultMaxAttempts = 5", "func Sign(secret string, timestamp time.Time, body []byte) string"},
+			filepath.Join("internal", "webhooks", "delivery.go"):             {"func (d *Dispatcher) Deliver(ctx context.Context) error", "func Backoff(attempts int) time.Duration"},
+			filepath.Join("internal", "webhooks", "inbound.go"):              {"func StripeVerifier(secret string) Verifier", "func GitHubVerifier(secret string) Verifier", "func SkipCSRF(next http.Handler) http.Handler"},
+			filepath.Join("internal", "web", "webhooks", "webhooks.go"):      {`c.receivers["stripe"] = hooks.Receiver(hooks.StripeVerifier(os.Getenv("STRIPE_WEBHOOK_SECRET")), handleStripe)`, "func handleGitHub("},
+			filepath.Join("internal", "services", "order", "webhooks.go"):    {`s.emit(ctx, "order.created", order)`, "func (s *webhookService) BulkDelete("},
+			filepath.Join("internal", "services", "blogpost", "webhooks.go"): {`s.emit(ctx, "blog_post.deleted", map[string]uint{"id": id})`},
+			filepath.Join("internal", "database", "database.go"):             {"&models.WebhookEndpoint{},", "&models.WebhookDelivery{},"},
+			filepath.Join("internal", "web", "router.go"):                    {"\tr.Use(webhooks.SkipCSRF)\n\n\t// CSRF protection", `"github.com/example/testapp/internal/webhooks"`},
+			filepath.Join("cmd", "web", "main.go"): {
+				"webhookDispatcher := webhooks.NewDispatcher(db)",
+				"orderService = ordersvc.NewWebhookService(orderService, webhookDispatcher)",
+				"blogPostService = blogpostsvc.NewWebhookService(blogPostService, webhookDispatcher)",
+				`scheduler.Start("webhook deliveries", webhookDispatcher.Deliver)`,
+				"webhooksController := webhooksweb.NewController()",
+				`router.Route("/webhooks", webhooksController.RegisterRoutes)`,
+				`webhooksweb "github.com/example/testapp/internal/web/webhooks"`,
+			},
+		}
+		for path, wants := range checks {
+			content := readFile(t, filepath.Join(tmpDir, path))
+			for _, want := range wants {
+				if !strings.Contains(content, want) {
+					t.Errorf("expected %s to contain %q", path, want)
+				}
+			}
+		}
+
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, "internal", "services", "blogpost", "webhooks.go")), "BulkDelete") {
+			t.Error("expected no BulkDelete for a domain without bulk actions")
+		}
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, "internal", "web", "webhooks", "webhooks.go")), "handleGeneric") {
+			t.Error("expected no generic receiver")
+		}
+	})
+
+	t.Run("without providers", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		result, err := scaffoldWebhooks(registry, types.ScaffoldWebhooksInput{Domains: []string{"order"}})
+		if err != nil || !result.Success {
+			t.Fatalf("unexpected failure: %v %s", err, result.Message)
+		}
+		if fileExists(filepath.Join(tmpDir, "internal", "webhooks", "inbound.go")) || fileExists(filepath.Join(tmpDir, "internal", "web", "webhooks", "webhooks.go")) {
+			t.Error("expected no receivers without providers")
+		}
+		if fileExists(filepath.Join(tmpDir, "internal", "services", "blogpost", "webhooks.go")) {
+			t.Error("expected only the listed domains to emit events")
+		}
+		if strings.Contains(readFile(t, filepath.Join(tmpDir, "internal", "web", "router.go")), "SkipCSRF") {
+			t.Error("expected router.go to be unchanged without receivers")
+		}
+	})
+
+	t.Run("re-running does not duplicate wiring", func(t *testing.T) {
+		registry, tmpDir := setup(t)
+		for i := 0; i < 2; i++ {
+			if result, err := scaffoldWebhooks(registry, types.ScaffoldWebhooksInput{Providers: []string{"generic"}}); err != nil || !result.Success {
+				t.Fatalf("run %d failed: %v %s", i+1, err, result.Message)
+			}
+		}
+
+		counts := map[string]string{
+			filepath.Join("cmd", "web", "main.go"):               "webhookDispatcher := webhooks.NewDispatcher(db)",
+			filepath.Join("internal", "database", "database.go"): "&models.WebhookDelivery{},",
+			filepath.Join("internal", "web", "router.go"):        "r.Use(webhooks.SkipCSRF)",
+		}
+		for path, want := range counts {
+			if n := strings.Count(readFile(t, filepath.Join(tmpDir, path)), want); n != 1 {
+				t.Errorf("expected %s to contain %q once, got %d", path, want, n)
+			}
+		}
+		if n := strings.Count(readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go")), "NewWebhookService(orderService"); n != 1 {
+			t.Errorf("expected the order service to be wrapped once, got %d", n)
+		}
+	})
+
+	t.Run("rejects invalid input", func(t *testing.T) {
+		registry, _ := setup(t)
+		inputs := map[string]types.ScaffoldWebhooksInput{
+			"provider":     {Providers: []string{"paypal"}},
+			"max attempts": {MaxAttempts: 100},
+			"unknown":      {Domains: []string{"invoice"}},
+		}
+		for name, input := range inputs {
+			result, err := scaffoldWebhooks(registry, input)
+			if err != nil {
+				t.Fatalf("%s: unexpected error: %v", name, err)
+			}
+			if result.Success {
+				t.Errorf("%s: expected failure", name)
+			}
+		}
+	})
+}
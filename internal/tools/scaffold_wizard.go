@@ -3,8 +3,11 @@ package tools
 import (
 	"context"
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
 
+	"github.com/dbb1dev/go-mcp/internal/config"
 	"github.com/dbb1dev/go-mcp/internal/generator"
 	"github.com/dbb1dev/go-mcp/internal/metadata"
 	"github.com/dbb1dev/go-mcp/internal/modifier"
@@ -27,6 +30,13 @@ Step types:
 - has_many: Add multiple related items (select_existing or create_inline mode)
 - summary: Review all selections before submit
 
+Conditional steps:
+- condition shows a step only when the answer to a field of an earlier form step
+  matches, e.g. condition: "delivery == \"ship\"" (== or != with a quoted value)
+- Next and Back skip the steps that do not apply, and the progress indicator hides
+  them as soon as the answer changes (Alpine.js)
+- The first and last steps always show; conditions need with_drafts
+
 Layout options (same as scaffold_domain):
 - "dashboard" (default): Views wrapped in DashboardPage layout with sidebar
 - "base": Views wrapped in BasePage layout without sidebar
@@ -47,6 +57,17 @@ Draft persistence:
 - Users can resume incomplete wizards via /domain/wizard/{draft_id}
 - Old drafts are cleaned up automatically
 
+Tests (with_tests: true):
+- Generates internal/web/{domain}/wizard_{name}_test.go with an httptest harness
+  on in-memory SQLite (gorm.io/driver/sqlite)
+- Walks every step, asserting draft persistence and resume, rejection of malformed
+  and unknown drafts, and creation of the record on submit
+- has_many steps select fixture children, which the draft must carry to submit
+
+Strict mode (strict: true, or MCP_SCAFFOLD_STRICT=true for every call):
+- Failing to inject the DI wiring or save the metadata fails the scaffold
+- The generated files are removed and main.go and database.go restored
+
 Examples:
 
 1. Simple public wizard (guest checkout):
@@ -85,9 +106,20 @@ Examples:
      ]
    }
 
+4. Checkout that asks for an address only for shipped orders:
+   scaffold_wizard: {
+     wizard_name: "checkout",
+     domain: "order",
+     steps: [
+       {name: "Delivery", type: "form", fields: ["email", "delivery"]},
+       {name: "Shipping", type: "form", fields: ["address", "city"], condition: "delivery == \"ship\""},
+       {name: "Review", type: "summary"}
+     ]
+   }
+
 Use dry_run: true to preview all generated files first.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.ScaffoldWizardInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := scaffoldWizard(registry, input)
+		result, err := locked(scaffoldWizard)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -96,6 +128,11 @@ Use dry_run: true to preview all generated files first.`,
 }
 
 func scaffoldWizard(registry *Registry, input types.ScaffoldWizardInput) (types.ScaffoldResult, error) {
+	// API-only projects have no views
+	if registry.IsAPIProject() {
+		return noViewsInAPIProject("scaffold_wizard"), nil
+	}
+
 	// Validate input
 	if input.WizardName == "" {
 		return types.NewErrorResult("wizard_name is required"), nil
@@ -103,6 +140,14 @@ func scaffoldWizard(registry *Registry, input types.ScaffoldWizardInput) (types.
 	if input.Domain == "" {
 		return types.NewErrorResult("domain is required"), nil
 	}
+
+	// Fill in the project defaults of .gomcp.toml
+	defaults, err := registry.Defaults()
+	if err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	input.Layout = config.Or(input.Layout, defaults.Layout)
+	input.RouteGroup = config.Or(input.RouteGroup, defaults.RouteGroup)
 	if len(input.Steps) == 0 {
 		return types.NewErrorResult("at least one step is required"), nil
 	}
@@ -118,6 +163,7 @@ func scaffoldWizard(registry *Registry, input types.ScaffoldWizardInput) (types.
 	}
 
 	// Validate steps
+	formFields := make(map[string]bool)
 	for i, step := range input.Steps {
 		if step.Name == "" {
 			return types.NewErrorResult(fmt.Sprintf("step %d: name is required", i+1)), nil
@@ -135,6 +181,26 @@ func scaffoldWizard(registry *Registry, input types.ScaffoldWizardInput) (types.
 		if step.HasManyMode != "" && step.HasManyMode != "select_existing" && step.HasManyMode != "create_inline" {
 			return types.NewErrorResult(fmt.Sprintf("step %d: invalid has_many_mode '%s', must be select_existing or create_inline", i+1, step.HasManyMode)), nil
 		}
+		if step.Condition != "" {
+			field, _, _, err := utils.ParseWizardCondition(step.Condition)
+			if err != nil {
+				return types.NewErrorResult(fmt.Sprintf("step %d: %v", i+1, err)), nil
+			}
+			if i == 0 || i == len(input.Steps)-1 {
+				return types.NewErrorResult(fmt.Sprintf("step %d: the first and last steps always show and cannot have a condition", i+1)), nil
+			}
+			if !input.GetWithDrafts() {
+				return types.NewErrorResult(fmt.Sprintf("step %d: conditions need with_drafts, which keeps the answers of earlier steps", i+1)), nil
+			}
+			if !formFields[field] {
+				return types.NewErrorResult(fmt.Sprintf("step %d: condition field '%s' is not a field of an earlier form step", i+1, field)), nil
+			}
+		}
+		if stepType == "form" {
+			for _, field := range step.Fields {
+				formFields[field] = true
+			}
+		}
 	}
 
 	// Get module path from go.mod
@@ -146,9 +212,11 @@ func scaffoldWizard(registry *Registry, input types.ScaffoldWizardInput) (types.
 	// Create generator
 	gen := registry.NewGenerator("")
 	gen.SetDryRun(input.DryRun)
+	gen.SetBuffered(true)
 
 	// Prepare template data
 	data := generator.NewWizardData(input, modulePath)
+	data.WithLogging = registry.HasStructuredLogging()
 
 	// Create directories
 	pkgName := utils.ToPackageName(input.Domain)
@@ -180,6 +248,13 @@ func scaffoldWizard(registry *Registry, input types.ScaffoldWizardInput) (types.
 
 	// Generate wizard components if they don't exist
 	wizardComponentsPath := filepath.Join("internal", "web", "components", "wizard.templ")
+	if data.HasConditions {
+		// Conditional steps set the Condition of WizardStepProps, which older components lack
+		content, err := os.ReadFile(filepath.Join(registry.WorkingDir, wizardComponentsPath))
+		if err == nil && !strings.Contains(string(content), "Condition") {
+			return types.NewErrorResult("internal/web/components/wizard.templ predates conditional steps: delete it to regenerate it, or add a Condition field to WizardStepProps that sets x-show on the step"), nil
+		}
+	}
 	if err := gen.GenerateFileIfNotExists("components/wizard.templ.tmpl", wizardComponentsPath, data); err != nil {
 		return types.NewErrorResult(fmt.Sprintf("failed to generate wizard components: %v", err)), nil
 	}
@@ -199,31 +274,17 @@ func scaffoldWizard(registry *Registry, input types.ScaffoldWizardInput) (types.
 	// Generate step views based on step types
 	for i, step := range data.Steps {
 		stepViewPath := filepath.Join("internal", "web", pkgName, "views", fmt.Sprintf("wizard_%s_step%d.templ", wizardName, i+1))
-		var templatePath string
-		switch step.Type {
-		case "form":
-			templatePath = "wizard/step_form.templ.tmpl"
-		case "select":
-			templatePath = "wizard/step_select.templ.tmpl"
-		case "has_many":
-			templatePath = "wizard/step_has_many.templ.tmpl"
-		case "summary":
-			templatePath = "wizard/step_summary.templ.tmpl"
-		default:
-			templatePath = "wizard/step_form.templ.tmpl"
-		}
-
-		// Create step-specific data
-		stepData := struct {
-			generator.WizardData
-			Step generator.WizardStepData
-		}{
-			WizardData: data,
-			Step:       step,
+		stepData := wizardStepData{WizardData: data, Step: step}
+		if err := gen.GenerateFile(wizardStepTemplate(step.Type), stepViewPath, stepData); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate step %d view: %v", i+1, err)), nil
 		}
+	}
 
-		if err := gen.GenerateFile(templatePath, stepViewPath, stepData); err != nil {
-			return types.NewErrorResult(fmt.Sprintf("failed to generate step %d view: %v", i+1, err)), nil
+	// Generate the integration test if requested
+	if data.WithTests {
+		testPath := filepath.Join("internal", "web", pkgName, "wizard_"+wizardName+"_test.go")
+		if err := gen.GenerateFile("wizard/controller_test.go.tmpl", testPath, data); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to generate wizard test: %v", err)), nil
 		}
 	}
 
@@ -260,11 +321,19 @@ func scaffoldWizard(registry *Registry, input types.ScaffoldWizardInput) (types.
 		return *conflictResult, nil
 	}
 
+	// Write the generated files in one pass, or none of them
+	if err := gen.Flush(); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to write files: %v", err)), nil
+	}
+
 	nextSteps := []string{
 		"go mod tidy",
 		"templ generate (wizard components auto-generated)",
 		"Add wizard link to domain views (e.g., a 'New with Wizard' button)",
 	}
+	if data.WithTests {
+		nextSteps = append(nextSteps, fmt.Sprintf("go test ./internal/web/%s/ -run %sWizard", pkgName, data.WizardNamePascal))
+	}
 
 	suggestedTools := []types.ToolHint{
 		{
@@ -285,13 +354,25 @@ func scaffoldWizard(registry *Registry, input types.ScaffoldWizardInput) (types.
 		}, nil
 	}
 
+	warnings := &scaffoldWarnings{strict: input.Strict || registry.Strict}
+	rollback := newFileRollback(registry.WorkingDir)
+	if warnings.strict {
+		rollback.track(
+			filepath.Join("cmd", "web", "main.go"),
+			filepath.Join("internal", "database", "database.go"),
+			filepath.Join(".mcp", "scaffold-metadata.json"),
+		)
+	}
+
 	// Inject DI wiring into main.go and database.go
 	mainGoPath := filepath.Join(registry.WorkingDir, "cmd", "web", "main.go")
 	databaseGoPath := filepath.Join(registry.WorkingDir, "internal", "database", "database.go")
 	if utils.FileExists(mainGoPath) {
 		if err := injectWizardWiring(mainGoPath, databaseGoPath, modulePath, pkgName, data); err != nil {
-			// Log warning but don't fail
-			fmt.Printf("Warning: could not inject wizard DI wiring: %v\n", err)
+			warnings.add("could not inject wizard DI wiring: %v", err)
+			if warnings.failed() {
+				return types.NewErrorResult(strictFailure(rollback, result.FilesCreated, warnings)), nil
+			}
 		} else {
 			result.FilesUpdated = append(result.FilesUpdated, "cmd/web/main.go")
 			if data.WithDrafts && utils.FileExists(databaseGoPath) {
@@ -303,8 +384,11 @@ func scaffoldWizard(registry *Registry, input types.ScaffoldWizardInput) (types.
 	// Save scaffold metadata for future sync/upgrade capabilities
 	metaStore := metadata.NewStore(registry.WorkingDir)
 	if err := metaStore.SaveWizard(input.WizardName, input.Domain, input, ScaffolderVersion); err != nil {
-		// Log warning but don't fail - metadata is optional
-		fmt.Printf("Warning: could not save wizard metadata: %v\n", err)
+		// Metadata is optional unless strict
+		warnings.add("could not save wizard metadata: %v", err)
+		if warnings.failed() {
+			return types.NewErrorResult(strictFailure(rollback, result.FilesCreated, warnings)), nil
+		}
 	} else {
 		result.FilesUpdated = append(result.FilesUpdated, ".mcp/scaffold-metadata.json")
 	}
@@ -314,6 +398,7 @@ func scaffoldWizard(registry *Registry, input types.ScaffoldWizardInput) (types.
 		Message:        fmt.Sprintf("Successfully created wizard '%s' for domain '%s' with %d steps", input.WizardName, input.Domain, len(input.Steps)),
 		FilesCreated:   result.FilesCreated,
 		FilesUpdated:   result.FilesUpdated,
+		Backups:        result.Backups,
 		NextSteps:      nextSteps,
 		SuggestedTools: suggestedTools,
 	}, nil
@@ -379,3 +464,23 @@ func injectWizardWiring(mainGoPath, databaseGoPath, modulePath, pkgName string,
 
 	return nil
 }
+
+// wizardStepData is the template data for a single wizard step view.
+type wizardStepData struct {
+	generator.WizardData
+	Step generator.WizardStepData
+}
+
+// wizardStepTemplate returns the view template for a wizard step type.
+func wizardStepTemplate(stepType string) string {
+	switch stepType {
+	case "select":
+		return "wizard/step_select.templ.tmpl"
+	case "has_many":
+		return "wizard/step_has_many.templ.tmpl"
+	case "summary":
+		return "wizard/step_summary.templ.tmpl"
+	default:
+		return "wizard/step_form.templ.tmpl"
+	}
+}
@@ -14,6 +14,7 @@ func TestScaffoldWizard(t *testing.T) {
 		registry, tmpDir := testRegistry(t)
 		setupGoMod(t, tmpDir, "github.com/test/myapp")
 
+		noDrafts := false
 		tests := []struct {
 			name    string
 			input   types.ScaffoldWizardInput
@@ -70,6 +71,58 @@ func TestScaffoldWizard(t *testing.T) {
 				},
 				wantErr: "step 1: invalid has_many_mode 'invalid'",
 			},
+			{
+				name: "invalid condition",
+				input: types.ScaffoldWizardInput{
+					WizardName: "create",
+					Domain:     "order",
+					Steps: []types.WizardStepDef{
+						{Name: "Delivery", Fields: []string{"delivery"}},
+						{Name: "Shipping", Condition: "delivery = ship"},
+						{Name: "Review", Type: "summary"},
+					},
+				},
+				wantErr: "step 2: invalid condition",
+			},
+			{
+				name: "condition on the first step",
+				input: types.ScaffoldWizardInput{
+					WizardName: "create",
+					Domain:     "order",
+					Steps: []types.WizardStepDef{
+						{Name: "Shipping", Condition: `delivery == "ship"`},
+						{Name: "Review", Type: "summary"},
+					},
+				},
+				wantErr: "step 1: the first and last steps always show",
+			},
+			{
+				name: "condition on an unknown field",
+				input: types.ScaffoldWizardInput{
+					WizardName: "create",
+					Domain:     "order",
+					Steps: []types.WizardStepDef{
+						{Name: "Delivery", Fields: []string{"delivery"}},
+						{Name: "Shipping", Fields: []string{"address"}, Condition: `address == "home"`},
+						{Name: "Review", Type: "summary"},
+					},
+				},
+				wantErr: "step 2: condition field 'address' is not a field of an earlier form step",
+			},
+			{
+				name: "condition without drafts",
+				input: types.ScaffoldWizardInput{
+					WizardName: "create",
+					Domain:     "order",
+					WithDrafts: &noDrafts,
+					Steps: []types.WizardStepDef{
+						{Name: "Delivery", Fields: []string{"delivery"}},
+						{Name: "Shipping", Condition: `delivery == "ship"`},
+						{Name: "Review", Type: "summary"},
+					},
+				},
+				wantErr: "step 2: conditions need with_drafts",
+			},
 		}
 
 		for _, tt := range tests {
@@ -224,6 +277,102 @@ func TestScaffoldWizard(t *testing.T) {
 		}
 	})
 
+	t.Run("generates integration tests with with_tests", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/test/myapp")
+
+		input := types.ScaffoldWizardInput{
+			WizardName: "create_order",
+			Domain:     "order",
+			Steps: []types.WizardStepDef{
+				{Name: "Details", Type: "form", Fields: []string{"email"}},
+				{Name: "Items", Type: "has_many", ChildDomain: "order_item"},
+				{Name: "Review", Type: "summary"},
+			},
+			WithTests: true,
+		}
+
+		result, err := scaffoldWizard(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		testPath := filepath.Join(tmpDir, "internal", "web", "order", "wizard_create_order_test.go")
+		if !fileExists(testPath) {
+			t.Fatal("expected wizard test file to be created")
+		}
+		content := readFile(t, testPath)
+		for _, want := range []string{
+			"func TestCreateOrderWizard_Walkthrough(",
+			"func TestCreateOrderWizard_InvalidDrafts(",
+			"httptest.NewRecorder()",
+			"&models.OrderItem{}",
+			`"order_item_items"`,
+		} {
+			if !strings.Contains(content, want) {
+				t.Errorf("expected test file to contain %q", want)
+			}
+		}
+	})
+
+	t.Run("skips integration tests by default", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/test/myapp")
+
+		input := types.ScaffoldWizardInput{
+			WizardName: "quick",
+			Domain:     "task",
+			Steps: []types.WizardStepDef{
+				{Name: "Details", Type: "form"},
+			},
+		}
+
+		if _, err := scaffoldWizard(registry, input); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if fileExists(filepath.Join(tmpDir, "internal", "web", "task", "wizard_quick_test.go")) {
+			t.Error("expected no wizard test file without with_tests")
+		}
+	})
+
+	t.Run("strict fails and rolls back when wiring cannot be injected", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/test/myapp")
+		// main.go without the MCP markers
+		const mainGo = "package main\n\nfunc main() {}\n"
+		setupWizardMainGo(t, tmpDir, mainGo)
+
+		result, err := scaffoldWizard(registry, types.ScaffoldWizardInput{
+			WizardName: "onboarding",
+			Domain:     "client",
+			Steps:      []types.WizardStepDef{{Name: "Details", Type: "form"}},
+			Strict:     true,
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success {
+			t.Fatal("expected strict mode to fail")
+		}
+		if !strings.Contains(result.Message, "could not inject wizard DI wiring") {
+			t.Errorf("unexpected message: %s", result.Message)
+		}
+		for _, path := range []string{
+			filepath.Join("internal", "models", "wizard_draft.go"),
+			filepath.Join("internal", "web", "client"),
+		} {
+			if fileExists(filepath.Join(tmpDir, path)) {
+				t.Errorf("expected %s to be rolled back", path)
+			}
+		}
+		if readFile(t, filepath.Join(tmpDir, "cmd", "web", "main.go")) != mainGo {
+			t.Error("expected main.go to be restored")
+		}
+	})
+
 	t.Run("dry run does not create files", func(t *testing.T) {
 		registry, tmpDir := testRegistry(t)
 		setupGoMod(t, tmpDir, "github.com/test/myapp")
@@ -420,6 +569,112 @@ func TestScaffoldWizard(t *testing.T) {
 			t.Error("expected custom wizard.templ to NOT be overwritten")
 		}
 	})
+	t.Run("generates conditional steps", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/test/myapp")
+
+		input := types.ScaffoldWizardInput{
+			WizardName: "checkout",
+			Domain:     "order",
+			WithTests:  true,
+			Steps: []types.WizardStepDef{
+				{Name: "Delivery", Fields: []string{"email", "delivery"}},
+				{Name: "Shipping", Fields: []string{"address"}, Condition: `delivery == "ship"`},
+				{Name: "Pickup", Fields: []string{"store"}, Condition: `delivery != "ship"`},
+				{Name: "Review", Type: "summary"},
+			},
+		}
+
+		result, err := scaffoldWizard(registry, input)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		viewsDir := filepath.Join(tmpDir, "internal", "web", "order", "views")
+		view := readFile(t, filepath.Join(viewsDir, "wizard_checkout.templ"))
+		for _, want := range []string{
+			`Condition: "delivery === 'ship'"`,
+			`Condition: "delivery !== 'ship'"`,
+			`return checkoutAnswer(stepData, "delivery") == "ship"`,
+			`return checkoutAnswer(stepData, "delivery") != "ship"`,
+			"func CheckoutNextStep(step int, stepData map[string]interface{}) int {",
+			"func CheckoutPrevStep(step int, stepData map[string]interface{}) int {",
+			"<div x-data={ checkoutState(props.StepData) }>",
+		} {
+			if !strings.Contains(view, want) {
+				t.Errorf("wizard view missing %q", want)
+			}
+		}
+
+		step1 := readFile(t, filepath.Join(viewsDir, "wizard_checkout_step1.templ"))
+		if strings.Count(step1, `"x-model"`) != 1 || !strings.Contains(step1, `templ.Attributes{"x-model": "delivery"}`) {
+			t.Error("expected only the delivery input to update the Alpine state")
+		}
+		step4 := readFile(t, filepath.Join(viewsDir, "wizard_checkout_step4.templ"))
+		if !strings.Contains(step4, "CheckoutPrevStep(4, props.StepData)") {
+			t.Error("expected Back to skip the steps that do not apply")
+		}
+
+		controller := readFile(t, filepath.Join(tmpDir, "internal", "web", "order", "wizard_checkout.go"))
+		for _, want := range []string{
+			"if !views.CheckoutStepApplies(2, stepData) {",
+			"nextStep := views.CheckoutNextStep(1, stepData)",
+			`resp.Redirect(fmt.Sprintf("/orders/wizard/checkout/step/%d?draft_id=%d", nextStep, draft.ID))`,
+		} {
+			if !strings.Contains(controller, want) {
+				t.Errorf("wizard controller missing %q", want)
+			}
+		}
+
+		// The walkthrough test takes the shipping branch and skips the pickup step
+		test := readFile(t, filepath.Join(tmpDir, "internal", "web", "order", "wizard_checkout_test.go"))
+		for _, want := range []string{
+			`form.Set("delivery", "ship")`,
+			"h.assertDraft(draftID, 4, want)",
+			"// Step 3: Pickup does not apply and is skipped",
+		} {
+			if !strings.Contains(test, want) {
+				t.Errorf("wizard test missing %q", want)
+			}
+		}
+
+		components := readFile(t, filepath.Join(tmpDir, "internal", "web", "components", "wizard.templ"))
+		if !strings.Contains(components, "x-show={ step.Condition }") {
+			t.Error("expected the step indicator to hide steps whose condition is false")
+		}
+	})
+
+	t.Run("rejects conditions with outdated wizard components", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/test/myapp")
+
+		componentsDir := filepath.Join(tmpDir, "internal", "web", "components")
+		if err := os.MkdirAll(componentsDir, 0755); err != nil {
+			t.Fatalf("failed to create components dir: %v", err)
+		}
+		if err := os.WriteFile(filepath.Join(componentsDir, "wizard.templ"), []byte("package components\n"), 0644); err != nil {
+			t.Fatalf("failed to write wizard.templ: %v", err)
+		}
+
+		result, err := scaffoldWizard(registry, types.ScaffoldWizardInput{
+			WizardName: "checkout",
+			Domain:     "order",
+			Steps: []types.WizardStepDef{
+				{Name: "Delivery", Fields: []string{"delivery"}},
+				{Name: "Shipping", Condition: `delivery == "ship"`},
+				{Name: "Review", Type: "summary"},
+			},
+		})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if result.Success || !strings.Contains(result.Message, "predates conditional steps") {
+			t.Errorf("expected outdated components error, got %q", result.Message)
+		}
+	})
 }
 
 // Sample main.go content with markers for wizard DI wiring tests
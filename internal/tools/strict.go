@@ -0,0 +1,126 @@
+package tools
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// scaffoldWarnings collects the failures a scaffold tolerates, such as an injection that did
+// not apply. Outside strict mode they are logged and the scaffold succeeds; in strict mode
+// they fail it and its changes are rolled back.
+type scaffoldWarnings struct {
+	strict   bool
+	messages []string
+}
+
+// add records a failure.
+func (w *scaffoldWarnings) add(format string, args ...any) {
+	message := fmt.Sprintf(format, args...)
+	w.messages = append(w.messages, message)
+	if !w.strict {
+		fmt.Printf("Warning: %s\n", message)
+	}
+}
+
+// failed reports whether strict mode turns the recorded failures into an error.
+func (w *scaffoldWarnings) failed() bool {
+	return w.strict && len(w.messages) > 0
+}
+
+// fileRollback remembers the content of files before a scaffold changes them.
+type fileRollback struct {
+	workingDir string
+	// originals maps project-relative paths to their content; nil when the file did not exist.
+	originals map[string][]byte
+}
+
+// newFileRollback creates a rollback for files in workingDir.
+func newFileRollback(workingDir string) *fileRollback {
+	return &fileRollback{workingDir: workingDir, originals: map[string][]byte{}}
+}
+
+// track remembers the current content of the files, unless they are already tracked.
+func (r *fileRollback) track(relPaths ...string) {
+	for _, relPath := range relPaths {
+		if _, ok := r.originals[relPath]; ok {
+			continue
+		}
+		content, err := os.ReadFile(filepath.Join(r.workingDir, relPath))
+		if err != nil {
+			content = nil
+		}
+		r.originals[relPath] = content
+	}
+}
+
+// restore puts the tracked files back as they were and removes the created files, along with
+// the directories they leave empty. It returns the number of files restored or removed.
+func (r *fileRollback) restore(created []string) (int, error) {
+	var errs []string
+	n := 0
+	for relPath, content := range r.originals {
+		fullPath := filepath.Join(r.workingDir, relPath)
+		var err error
+		if content == nil {
+			err = os.Remove(fullPath)
+			if os.IsNotExist(err) {
+				continue
+			}
+		} else {
+			err = os.WriteFile(fullPath, content, 0644)
+		}
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		n++
+	}
+
+	for _, relPath := range created {
+		if err := os.Remove(filepath.Join(r.workingDir, relPath)); err != nil {
+			if !os.IsNotExist(err) {
+				errs = append(errs, err.Error())
+			}
+			continue
+		}
+		n++
+		// Remove the directories the scaffold created; os.Remove fails on the first non-empty one
+		for dir := filepath.Dir(relPath); dir != "." && dir != string(filepath.Separator); dir = filepath.Dir(dir) {
+			if os.Remove(filepath.Join(r.workingDir, dir)) != nil {
+				break
+			}
+		}
+	}
+
+	if len(errs) > 0 {
+		return n, fmt.Errorf("%s", strings.Join(errs, "; "))
+	}
+	return n, nil
+}
+
+// injectStep runs one injection of a scaffold that changes the files at relPaths. When it
+// fails, the files are put back as they were, so a failed injection leaves no partial edit
+// behind, in strict mode or not.
+func injectStep(workingDir string, relPaths []string, inject func() error) error {
+	step := newFileRollback(workingDir)
+	step.track(relPaths...)
+	if err := inject(); err != nil {
+		if _, restoreErr := step.restore(nil); restoreErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, restoreErr)
+		}
+		return err
+	}
+	return nil
+}
+
+// strictFailure rolls back a scaffold that failed in strict mode and returns its error message.
+func strictFailure(rollback *fileRollback, created []string, warnings *scaffoldWarnings) string {
+	message := fmt.Sprintf("strict mode: %s", strings.Join(warnings.messages, "; "))
+	n, err := rollback.restore(created)
+	if err != nil {
+		return fmt.Sprintf("%s; rollback failed: %v", message, err)
+	}
+	return fmt.Sprintf("%s; rolled back %d file(s)", message, n)
+}
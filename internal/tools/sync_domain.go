@@ -0,0 +1,189 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/modifier"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterSyncDomain registers the sync_domain tool.
+func RegisterSyncDomain(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "sync_domain",
+		Description: `Update a scaffolded domain to the current templates, file by file or hunk by hunk.
+
+Regenerates the domain from its scaffold metadata (saved by scaffold_domain) and writes the
+files that differ. Run analyze_domain first to see the diffs: its hunks are numbered in the
+"@@ -12,7 +12,9 @@ hunk 2" headers.
+
+Options:
+- files: only update these files, as analyze_domain lists them (default: every changed or missing file)
+- hunks: apply only some hunks of a file, e.g. { "internal/web/order/order.go": [1, 3] };
+  the other hunks keep the current content, so customizations outside them survive
+- dry_run: true to list the files that would be updated
+
+The previous version of every updated file is kept in .mcp/backups/<timestamp>/, and nothing
+is written when a resulting Go file doesn't parse.
+
+Examples:
+1. Take the new controller template without touching customized views:
+   sync_domain: { domain: "order", files: ["internal/web/order/order.go"] }
+
+2. Apply two hunks of the service:
+   sync_domain: { domain: "order", hunks: { "internal/services/order/order.go": [1, 2] } }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.SyncDomainInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
+		result, err := locked(syncDomain)(registry.For(ctx), input)
+		if err != nil {
+			return nil, types.NewErrorResult(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func syncDomain(registry *Registry, input types.SyncDomainInput) (types.ScaffoldResult, error) {
+	if input.Domain == "" {
+		return types.NewErrorResult("domain is required"), nil
+	}
+
+	domainMeta, exists, err := metadata.NewStore(registry.WorkingDir).GetDomain(input.Domain)
+	if err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to read domain metadata: %v", err)), nil
+	}
+	if !exists {
+		return types.NewErrorResult(fmt.Sprintf("No metadata found for domain '%s'. Only domains scaffolded with metadata tracking can be synced.", input.Domain)), nil
+	}
+
+	rendered, err := generateDomainFiles(registry, domainMeta.Input)
+	if err != nil {
+		return types.NewErrorResult(err.Error()), nil
+	}
+	paths := rendered.ListGeneratedFiles()
+
+	// Select the files to update, all of them by default
+	selected := map[string]bool{}
+	hunks := map[string][]int{}
+	for _, f := range input.Files {
+		selected[filepath.Clean(filepath.FromSlash(f))] = true
+	}
+	for f, numbers := range input.Hunks {
+		f = filepath.Clean(filepath.FromSlash(f))
+		selected[f] = true
+		hunks[f] = numbers
+	}
+	for f := range selected {
+		if !slices.Contains(paths, f) {
+			return types.NewErrorResult(fmt.Sprintf("'%s' is not a file of domain '%s', which has: %s", filepath.ToSlash(f), input.Domain, strings.Join(paths, ", "))), nil
+		}
+	}
+	if len(selected) == 0 {
+		for _, path := range paths {
+			selected[path] = true
+		}
+	}
+
+	gen := registry.NewGenerator("")
+	gen.SetDryRun(input.DryRun)
+	gen.SetForceOverwrite(true)
+	gen.SetBuffered(true)
+
+	for _, path := range paths {
+		if !selected[path] {
+			continue
+		}
+		generated := rendered.GetFileContent(path)
+		existing, err := os.ReadFile(filepath.Join(registry.WorkingDir, path))
+		if err != nil && !os.IsNotExist(err) {
+			return types.NewErrorResult(fmt.Sprintf("failed to read %s: %v", path, err)), nil
+		}
+
+		if err == nil {
+			// Keep the code extend_* tools injected between markers
+			generated = keepInjectedLines(withoutInjectedTenantID(string(existing), generated), generated)
+		}
+
+		content := generated
+		if numbers, ok := hunks[path]; ok {
+			if os.IsNotExist(err) {
+				return types.NewErrorResult(fmt.Sprintf("%s is missing, so it has no hunks: sync the whole file with files instead", path)), nil
+			}
+			lines := lineDiff(string(existing), generated)
+			count := countHunks(lines)
+			apply := map[int]bool{}
+			for _, n := range numbers {
+				if n < 1 || n > count {
+					return types.NewErrorResult(fmt.Sprintf("%s has %d hunk(s), so there is no hunk %d: run analyze_domain for the current hunks", path, count, n)), nil
+				}
+				apply[n] = true
+			}
+			content = applyHunks(lines, apply)
+		}
+
+		if err == nil && content == string(existing) {
+			continue
+		}
+		if err := gen.WriteFile(path, content); err != nil {
+			return types.NewErrorResult(fmt.Sprintf("failed to write %s: %v", path, err)), nil
+		}
+	}
+
+	// Write the files in one pass, or none of them
+	if err := gen.Flush(); err != nil {
+		return types.NewErrorResult(fmt.Sprintf("failed to write files: %v", err)), nil
+	}
+	result := gen.Result()
+
+	changed := len(result.FilesCreated) + len(result.FilesUpdated)
+	if changed == 0 {
+		return types.ScaffoldResult{
+			Success: true,
+			Message: fmt.Sprintf("Domain '%s' is up to date", input.Domain),
+		}, nil
+	}
+
+	nextSteps := []string{"go build ./..."}
+	for _, path := range slices.Concat(result.FilesCreated, result.FilesUpdated) {
+		if strings.Contains(path, ".templ") {
+			nextSteps = append([]string{"templ generate"}, nextSteps...)
+			break
+		}
+	}
+
+	message := fmt.Sprintf("Synced %d file(s) of domain '%s' with the current templates", changed, input.Domain)
+	if input.DryRun {
+		message = fmt.Sprintf("Dry run: Would sync %d file(s) of domain '%s'", changed, input.Domain)
+	}
+	return types.ScaffoldResult{
+		Success:      true,
+		Message:      message,
+		FilesCreated: result.FilesCreated,
+		FilesUpdated: result.FilesUpdated,
+		Backups:      result.Backups,
+		NextSteps:    nextSteps,
+	}, nil
+}
+
+// tenantIDFieldRegex matches the TenantID field of a tenant-scoped model.
+var tenantIDFieldRegex = regexp.MustCompile(`(?m)^\s*TenantID\s`)
+
+// injectedTenantIDRegex matches a TenantID field in a model's relationships section, where
+// earlier versions of scaffold_tenancy added it.
+var injectedTenantIDRegex = regexp.MustCompile(`(?m)^[ \t]*TenantID\s[^\n]*\n((?:[^\n]*\n)*?[ \t]*// ` + regexp.QuoteMeta(modifier.MarkerRelationshipsEnd) + `)`)
+
+// withoutInjectedTenantID drops the TenantID field earlier versions of scaffold_tenancy
+// injected into a model's relationships section, when the template now declares it.
+func withoutInjectedTenantID(existing, generated string) string {
+	start := strings.Index(existing, "// "+modifier.MarkerRelationshipsStart)
+	if start < 0 || !tenantIDFieldRegex.MatchString(generated) {
+		return existing
+	}
+	return existing[:start] + injectedTenantIDRegex.ReplaceAllString(existing[start:], "$1")
+}
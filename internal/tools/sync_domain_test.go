@@ -0,0 +1,146 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestSyncDomain(t *testing.T) {
+	controller := filepath.Join("internal", "web", "product", "product.go")
+	listView := filepath.Join("internal", "web", "product", "views", "list.templ")
+
+	// setup scaffolds a product domain, then customizes the controller in two places and the list view
+	setup := func(t *testing.T) (*Registry, string, string) {
+		t.Helper()
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+
+		generated := readFile(t, filepath.Join(tmpDir, controller))
+		pkg, rest, _ := strings.Cut(generated, "\n")
+		writeTestFile(t, filepath.Join(tmpDir, controller), pkg+"\n// custom header\n"+rest+"// custom footer\n")
+		writeTestFile(t, filepath.Join(tmpDir, listView), readFile(t, filepath.Join(tmpDir, listView))+"// custom view\n")
+		return registry, tmpDir, generated
+	}
+
+	t.Run("analyze_domain numbers the hunks", func(t *testing.T) {
+		registry, _, _ := setup(t)
+
+		analysis, err := analyzeSingleDomain(registry, metadata.NewStore(registry.WorkingDir), "product", []string{"controller"}, false)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(analysis.Files) != 1 || analysis.Files[0].Hunks != 2 {
+			t.Fatalf("expected the controller with 2 hunks, got %+v", analysis.Files)
+		}
+		for _, want := range []string{"@@ -1,5 +1,4 @@ hunk 1", "-// custom header", "hunk 2", "-// custom footer"} {
+			if !strings.Contains(analysis.Files[0].Diff, want) {
+				t.Errorf("expected the diff to contain %q, got:\n%s", want, analysis.Files[0].Diff)
+			}
+		}
+	})
+
+	t.Run("applies only the selected hunks", func(t *testing.T) {
+		registry, tmpDir, _ := setup(t)
+
+		result, err := syncDomain(registry, types.SyncDomainInput{
+			Domain: "product",
+			Hunks:  map[string][]int{filepath.ToSlash(controller): {1}},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("sync failed: %v %s", err, result.Message)
+		}
+		content := readFile(t, filepath.Join(tmpDir, controller))
+		if strings.Contains(content, "// custom header") || !strings.Contains(content, "// custom footer") {
+			t.Errorf("expected hunk 1 applied and hunk 2 left alone, got:\n%s", content)
+		}
+		if !strings.Contains(readFile(t, filepath.Join(tmpDir, listView)), "// custom view") {
+			t.Error("files that were not selected should keep their content")
+		}
+	})
+
+	t.Run("updates the selected files and backs them up", func(t *testing.T) {
+		registry, tmpDir, generated := setup(t)
+
+		result, err := syncDomain(registry, types.SyncDomainInput{Domain: "product", Files: []string{filepath.ToSlash(controller)}})
+		if err != nil || !result.Success {
+			t.Fatalf("sync failed: %v %s", err, result.Message)
+		}
+		if got := readFile(t, filepath.Join(tmpDir, controller)); got != generated {
+			t.Errorf("expected the controller to match the template, got:\n%s", got)
+		}
+		if !strings.Contains(readFile(t, filepath.Join(tmpDir, listView)), "// custom view") {
+			t.Error("the customized view should not be touched")
+		}
+		if len(result.FilesUpdated) != 1 || result.FilesUpdated[0] != controller {
+			t.Errorf("expected the controller in FilesUpdated, got %v", result.FilesUpdated)
+		}
+		if backup := result.Backups[controller]; !strings.HasPrefix(backup, filepath.Join(".mcp", "backups")) {
+			t.Errorf("expected the backup of the controller in Backups, got %v", result.Backups)
+		}
+	})
+
+	t.Run("syncs every changed or missing file by default", func(t *testing.T) {
+		registry, tmpDir, _ := setup(t)
+		model := filepath.Join(tmpDir, "internal", "models", "product.go")
+		if err := os.Remove(model); err != nil {
+			t.Fatal(err)
+		}
+
+		dry, err := syncDomain(registry, types.SyncDomainInput{Domain: "product", DryRun: true})
+		if err != nil || !dry.Success || len(dry.FilesCreated) != 1 || len(dry.FilesUpdated) != 2 {
+			t.Fatalf("unexpected dry run: %v %+v", err, dry)
+		}
+		if fileExists(model) {
+			t.Fatal("dry run should not write files")
+		}
+
+		if result, err := syncDomain(registry, types.SyncDomainInput{Domain: "product"}); err != nil || !result.Success {
+			t.Fatalf("sync failed: %v %s", err, result.Message)
+		}
+		if !fileExists(model) {
+			t.Error("expected the missing model to be recreated")
+		}
+		again, _ := syncDomain(registry, types.SyncDomainInput{Domain: "product"})
+		if !strings.Contains(again.Message, "up to date") {
+			t.Errorf("expected the domain to be up to date, got: %s", again.Message)
+		}
+	})
+
+	t.Run("validates input", func(t *testing.T) {
+		registry, _, _ := setup(t)
+
+		tests := []struct {
+			name  string
+			input types.SyncDomainInput
+			want  string
+		}{
+			{"no domain", types.SyncDomainInput{}, "domain is required"},
+			{"unknown domain", types.SyncDomainInput{Domain: "order"}, "No metadata found"},
+			{"unknown file", types.SyncDomainInput{Domain: "product", Files: []string{"main.go"}}, "is not a file of domain"},
+			{"unknown hunk", types.SyncDomainInput{Domain: "product", Hunks: map[string][]int{filepath.ToSlash(controller): {3}}}, "has 2 hunk(s)"},
+		}
+		for _, tt := range tests {
+			t.Run(tt.name, func(t *testing.T) {
+				result, err := syncDomain(registry, tt.input)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if result.Success || !strings.Contains(result.Message, tt.want) {
+					t.Errorf("expected an error containing %q, got: %s", tt.want, result.Message)
+				}
+			})
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dbb1dev/go-mcp/internal/templates"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterTemplateChanges registers the template_changes tool.
+func RegisterTemplateChanges(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "template_changes",
+		Description: `List the changes to the scaffolding templates per scaffolder version, from the template changelog.
+
+Use it to understand why analyze_domain or analyze_project reports a diff, or what a newer
+scaffolder changes before running sync_domain or upgrade_project. analyze_domain and
+analyze_project already attach the entries that explain each changed file (changes).
+The changelog is also available as the scaffold://template-changes resource.
+
+Options:
+- since: only the changes after this scaffolder version, e.g. the version a domain was scaffolded with
+- template: only the changes to one template, e.g. "views/form.templ.tmpl"
+
+Examples:
+  template_changes: {}
+  template_changes: { since: "0.1.0", template: "domain/controller.go.tmpl" }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.TemplateChangesInput) (*mcp.CallToolResult, types.TemplateChangesResult, error) {
+		result, err := templateChanges(input)
+		if err != nil {
+			return nil, types.TemplateChangesResult{Success: false, Message: err.Error()}, nil
+		}
+		return nil, result, nil
+	})
+}
+
+func templateChanges(input types.TemplateChangesInput) (types.TemplateChangesResult, error) {
+	changelog, err := templates.Changelog()
+	if err != nil {
+		return types.TemplateChangesResult{}, err
+	}
+
+	result := types.TemplateChangesResult{Success: true, CurrentVersion: ScaffolderVersion}
+	for _, change := range changelog {
+		if input.Since != "" && compareVersions(change.Version, input.Since) <= 0 {
+			continue
+		}
+		if input.Template != "" && !templates.ChangeAffects(change, input.Template) {
+			continue
+		}
+		result.Changes = append(result.Changes, change)
+	}
+
+	result.Message = fmt.Sprintf("%d template change(s)", len(result.Changes))
+	if input.Since != "" {
+		result.Message += fmt.Sprintf(" since %s", input.Since)
+	}
+	if input.Template != "" {
+		result.Message += fmt.Sprintf(" to %s", input.Template)
+	}
+	return result, nil
+}
+
+// templateChangesSince returns the changelog entries for a template that are newer than the
+// scaffolder version a file was generated with, as "0.2.0: summary".
+func templateChangesSince(changelog []types.TemplateChange, version, templatePath string) []string {
+	if templatePath == "" {
+		return nil
+	}
+	var changes []string
+	for _, change := range changelog {
+		if version != "" && compareVersions(change.Version, version) <= 0 {
+			continue
+		}
+		if templates.ChangeAffects(change, templatePath) {
+			changes = append(changes, fmt.Sprintf("%s: %s", change.Version, change.Summary))
+		}
+	}
+	return changes
+}
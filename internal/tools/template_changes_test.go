@@ -0,0 +1,74 @@
+package tools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+func TestTemplateChanges(t *testing.T) {
+	t.Run("filters by version and template", func(t *testing.T) {
+		all, err := templateChanges(types.TemplateChangesInput{})
+		if err != nil || !all.Success || len(all.Changes) == 0 {
+			t.Fatalf("expected the whole changelog, got %v %+v", err, all)
+		}
+
+		form, _ := templateChanges(types.TemplateChangesInput{Template: "views/form.templ.tmpl"})
+		if len(form.Changes) == 0 || len(form.Changes) >= len(all.Changes) {
+			t.Errorf("expected some of the %d changes for the form view, got %d", len(all.Changes), len(form.Changes))
+		}
+
+		current, _ := templateChanges(types.TemplateChangesInput{Since: ScaffolderVersion})
+		if len(current.Changes) != 0 {
+			t.Errorf("expected no changes after the current version, got %+v", current.Changes)
+		}
+	})
+
+	t.Run("templateChangesSince keeps newer entries for the template", func(t *testing.T) {
+		changelog := []types.TemplateChange{
+			{Version: "0.1.0", Templates: []string{"views/form.templ.tmpl"}, Summary: "old"},
+			{Version: "0.2.0", Templates: []string{"views/*"}, Summary: "checkbox hidden-field fix"},
+			{Version: "0.2.0", Templates: []string{"domain/model.go.tmpl"}, Summary: "other template"},
+		}
+		got := templateChangesSince(changelog, "0.1.0", "views/form.templ.tmpl")
+		if len(got) != 1 || got[0] != "0.2.0: checkbox hidden-field fix" {
+			t.Errorf("unexpected changes: %v", got)
+		}
+		if got := templateChangesSince(changelog, "0.1.0", ""); got != nil {
+			t.Errorf("files without a template have no changes, got %v", got)
+		}
+	})
+
+	t.Run("analyze_domain explains diffs since the scaffolded version", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/testapp")
+		result, err := scaffoldDomain(registry, types.ScaffoldDomainInput{
+			DomainName: "product",
+			Fields:     []types.FieldDef{{Name: "Name", Type: "string"}},
+		})
+		if err != nil || !result.Success {
+			t.Fatalf("failed to scaffold domain: %v %s", err, result.Message)
+		}
+
+		// Pretend the domain was scaffolded before the current templates
+		store := metadata.NewStore(tmpDir)
+		domain, _, _ := store.GetDomain("product")
+		if err := store.SaveDomain("product", domain.Input, "0.0.1"); err != nil {
+			t.Fatal(err)
+		}
+		controller := filepath.Join(tmpDir, "internal", "web", "product", "product.go")
+		writeTestFile(t, controller, readFile(t, controller)+"// custom\n")
+
+		analysis, err := analyzeSingleDomain(registry, store, "product", []string{"controller"}, false)
+		if err != nil || len(analysis.Files) != 1 {
+			t.Fatalf("unexpected analysis: %v %+v", err, analysis)
+		}
+		changes := strings.Join(analysis.Files[0].Changes, "\n")
+		if !strings.Contains(changes, "0.1.0: Bulk actions on list views") || strings.Contains(changes, "Optimistic locking") {
+			t.Errorf("expected the controller's changelog entries, got:\n%s", changes)
+		}
+	})
+}
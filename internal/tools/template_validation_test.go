@@ -428,13 +428,16 @@ func TestTemplateValidation_AuthAndUserManagement(t *testing.T) {
 		if !strings.Contains(content, "middleware.IsAdmin") {
 			t.Error("base layout should contain admin visibility check")
 		}
-		if !strings.Contains(content, "MCP:NAV_ITEMS_ADMIN") {
-			t.Error("base layout should contain admin nav markers")
-		}
-		if !strings.Contains(content, "/admin/users") {
-			t.Error("base layout should contain Users nav item for user management")
+		if !strings.Contains(content, "menu.Admin()") {
+			t.Error("base layout should render admin links from the menu")
 		}
 	}
+
+	// Verify menu.toml has the Users admin link
+	menuContent := readFile(t, filepath.Join(projectDir, "config/en/menu.toml"))
+	if !strings.Contains(menuContent, `url = "/admin/users"`) {
+		t.Error("menu.toml should contain Users admin link for user management")
+	}
 }
 
 func TestTemplateValidation_WizardTemplates(t *testing.T) {
@@ -28,6 +28,29 @@ func setupGoMod(t *testing.T, tmpDir, modulePath string) {
 	}
 }
 
+// setupTestUtil creates the internal/testutil/db.go of the testcontainers harness.
+func setupTestUtil(t *testing.T, tmpDir string) {
+	t.Helper()
+	dbPath := filepath.Join(tmpDir, "internal", "testutil", "db.go")
+	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		t.Fatalf("failed to create testutil dir: %v", err)
+	}
+	if err := os.WriteFile(dbPath, []byte("package testutil\n"), 0644); err != nil {
+		t.Fatalf("failed to write db.go: %v", err)
+	}
+}
+
+// writeTestFile writes a file of the project, creating its directory.
+func writeTestFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatalf("failed to create dir: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("failed to write %s: %v", path, err)
+	}
+}
+
 // fileExists checks if a file exists.
 func fileExists(path string) bool {
 	_, err := os.Stat(path)
@@ -27,7 +27,7 @@ scaffold_domain calls this automatically, but use this tool to re-wire or add mi
 
 Use dry_run: true to verify markers exist without making changes.`,
 	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.UpdateDIWiringInput) (*mcp.CallToolResult, types.ScaffoldResult, error) {
-		result, err := updateDIWiring(registry, input)
+		result, err := locked(updateDIWiring)(registry.For(ctx), input)
 		if err != nil {
 			return nil, types.NewErrorResult(err.Error()), nil
 		}
@@ -39,6 +39,9 @@ func updateDIWiring(registry *Registry, input types.UpdateDIWiringInput) (types.
 	if len(input.Domains) == 0 {
 		return types.NewErrorResult("at least one domain is required"), nil
 	}
+	if registry.UsesWire() {
+		return types.NewErrorResult("this project uses di wire: the domains are wired by their provider sets in cmd/web/wire.go, so run 'task wire' instead"), nil
+	}
 
 	// Validate domains
 	for _, domain := range input.Domains {
@@ -151,8 +154,12 @@ func updateDIWiring(registry *Registry, input types.UpdateDIWiringInput) (types.
 			return types.NewErrorResult(fmt.Sprintf("failed to inject controller for '%s': %v", domain, err)), nil
 		}
 
-		// Inject route
-		if err := injector.InjectRoute(domain); err != nil {
+		// Inject route, or let the routes registry mount the controller in the group its routes.go names
+		if injector.HasMarker(modifier.MarkerDomainRoutesStart) {
+			if err := injector.InjectDomainRoutes(domain, "public"); err != nil {
+				return types.NewErrorResult(fmt.Sprintf("failed to add '%s' to the routes registry: %v", domain, err)), nil
+			}
+		} else if err := injector.InjectRoute(domain); err != nil {
 			return types.NewErrorResult(fmt.Sprintf("failed to inject route for '%s': %v", domain, err)), nil
 		}
 	}
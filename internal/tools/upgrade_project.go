@@ -0,0 +1,214 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// RegisterUpgradeProject registers the upgrade_project tool.
+func RegisterUpgradeProject(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "upgrade_project",
+		Description: `Upgrade a project generated by an older version of this scaffolder to the current template layout.
+
+Each scaffolder version that changes the layout of generated projects ships a migration:
+scripted steps that rename MCP markers, move files and rewrite the imports of moved packages,
+so that later tools find the markers and files where they expect them. upgrade_project applies
+the migrations between the version the project follows and the current version, oldest first,
+then records the current version in .mcp/scaffold-metadata.json.
+
+The version the project follows is read from the metadata (scaffold_project and scaffold_domain
+record it). For a project without metadata, pass from.
+
+Every step runs in memory first: nothing is written when a step fails or a resulting Go file
+doesn't parse. The previous version of every changed or moved file is kept in
+.mcp/backups/<timestamp>/ (backup_dir).
+
+Migrations only cover the layout. Run analyze_project and analyze_domain afterwards to see
+what changed inside the templates.
+
+Options:
+- from: the scaffolder version the project was generated with (default: from the metadata)
+- dry_run: true to list the migrations and changes without writing files
+
+Examples:
+  upgrade_project: { dry_run: true }
+  upgrade_project: { from: "0.1.0" }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.UpgradeProjectInput) (*mcp.CallToolResult, types.UpgradeProjectResult, error) {
+		call := registry.For(ctx)
+		unlock, _, _, err := call.lockProject()
+		if err != nil {
+			return nil, types.NewUpgradeProjectError(err.Error()), nil
+		}
+		defer unlock()
+		result, err := upgradeProject(call, input, projectMigrations)
+		if err != nil {
+			return nil, types.NewUpgradeProjectError(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func upgradeProject(registry *Registry, input types.UpgradeProjectInput, migrations []projectMigration) (types.UpgradeProjectResult, error) {
+	modulePath, err := utils.GetModulePath(registry.WorkingDir)
+	if err != nil {
+		return types.NewUpgradeProjectError("go.mod not found: run upgrade_project from the root of a generated project"), nil
+	}
+
+	store := metadata.NewStore(registry.WorkingDir)
+	meta, err := store.Load()
+	if err != nil {
+		return types.NewUpgradeProjectError(fmt.Sprintf("failed to read project metadata: %v", err)), nil
+	}
+	from := input.From
+	if from == "" {
+		from = projectTemplateVersion(meta)
+	}
+	if from == "" {
+		return types.NewUpgradeProjectError("Cannot tell which scaffolder version generated the project: .mcp/scaffold-metadata.json doesn't record one. Pass from, e.g. from: \"0.1.0\"."), nil
+	}
+	if compareVersions(from, ScaffolderVersion) > 0 {
+		return types.NewUpgradeProjectError(fmt.Sprintf("The project follows the templates of %s, which is newer than this scaffolder (%s)", from, ScaffolderVersion)), nil
+	}
+
+	result := types.UpgradeProjectResult{Success: true, DryRun: input.DryRun, From: from, To: ScaffolderVersion}
+	if compareVersions(from, ScaffolderVersion) == 0 {
+		result.Message = fmt.Sprintf("The project is up to date with the templates of %s", ScaffolderVersion)
+		return result, nil
+	}
+
+	files, err := newMigrationFiles(registry.WorkingDir, modulePath)
+	if err != nil {
+		return types.NewUpgradeProjectError(fmt.Sprintf("failed to scan project: %v", err)), nil
+	}
+
+	// Apply the pending migrations, oldest first
+	for _, m := range migrations {
+		if compareVersions(m.version, from) <= 0 || compareVersions(m.version, ScaffolderVersion) > 0 {
+			continue
+		}
+		applied := types.AppliedMigration{Version: m.version, Description: m.description}
+		for _, step := range m.steps {
+			changes, err := step(files)
+			if err != nil {
+				return types.NewUpgradeProjectError(fmt.Sprintf("migration to %s failed, no files were changed: %v", m.version, err)), nil
+			}
+			applied.Changes = append(applied.Changes, changes...)
+		}
+		result.Migrations = append(result.Migrations, applied)
+	}
+
+	// Write the changed and moved files in one pass, or none of them
+	gen := registry.NewGenerator("")
+	backupDir := newBackupDir()
+	gen.SetBackupDir(backupDir)
+	gen.SetDryRun(input.DryRun)
+	gen.SetForceOverwrite(true)
+	gen.SetBuffered(true)
+
+	var writes []string
+	for path := range files.changed {
+		writes = append(writes, path)
+	}
+	for path := range files.moved {
+		if !files.changed[path] {
+			writes = append(writes, path)
+		}
+	}
+	slices.Sort(writes)
+	for _, path := range writes {
+		if err := gen.WriteFile(path, files.content[path]); err != nil {
+			return types.NewUpgradeProjectError(fmt.Sprintf("failed to write %s: %v", path, err)), nil
+		}
+	}
+
+	var moved []string
+	for to, from := range files.moved {
+		moved = append(moved, from)
+		result.FilesMoved = append(result.FilesMoved, fmt.Sprintf("%s -> %s", filepath.ToSlash(from), filepath.ToSlash(to)))
+	}
+	slices.Sort(result.FilesMoved)
+
+	if !input.DryRun {
+		for _, path := range moved {
+			if err := utils.CopyFile(filepath.Join(registry.WorkingDir, path), filepath.Join(registry.WorkingDir, backupDir, path)); err != nil {
+				return types.NewUpgradeProjectError(fmt.Sprintf("failed to back up %s: %v", path, err)), nil
+			}
+		}
+	}
+	if err := gen.Flush(); err != nil {
+		return types.NewUpgradeProjectError(fmt.Sprintf("failed to write files: %v", err)), nil
+	}
+	if !input.DryRun {
+		for _, path := range moved {
+			if err := os.Remove(filepath.Join(registry.WorkingDir, path)); err != nil && !os.IsNotExist(err) {
+				return types.NewUpgradeProjectError(fmt.Sprintf("failed to remove %s after moving it: %v", path, err)), nil
+			}
+		}
+		if len(writes) > 0 {
+			result.BackupDir = backupDir
+		}
+	}
+	result.FilesUpdated = gen.Result().FilesUpdated
+
+	if input.DryRun {
+		result.Message = fmt.Sprintf("Dry run: Would upgrade the project from %s to %s with %d migration(s), changing %d file(s)", from, ScaffolderVersion, len(result.Migrations), len(writes))
+		return result, nil
+	}
+
+	if err := store.SetTemplateVersion(ScaffolderVersion); err != nil {
+		return types.NewUpgradeProjectError(fmt.Sprintf("the migrations were applied, but recording version %s failed: %v", ScaffolderVersion, err)), nil
+	}
+
+	if len(result.Migrations) == 0 {
+		result.Message = fmt.Sprintf("No layout changes between %s and %s; the project now follows the templates of %s", from, ScaffolderVersion, ScaffolderVersion)
+	} else {
+		result.Message = fmt.Sprintf("Upgraded the project from %s to %s with %d migration(s), changing %d file(s)", from, ScaffolderVersion, len(result.Migrations), len(writes))
+	}
+	if result.BackupDir != "" {
+		result.Message += fmt.Sprintf("; the previous versions are in %s", result.BackupDir)
+	}
+	if len(writes) > 0 {
+		result.NextSteps = []string{"templ generate", "go build ./..."}
+	}
+	result.NextSteps = append(result.NextSteps, "Run analyze_project and analyze_domain to review the changes inside the templates")
+	return result, nil
+}
+
+// projectTemplateVersion returns the scaffolder version whose template layout the project
+// follows: the one upgrade_project recorded, else the oldest version that scaffolded the
+// project or one of its domains. It is empty when the metadata doesn't record one.
+func projectTemplateVersion(meta *metadata.ProjectMetadata) string {
+	if meta.TemplateVersion != "" {
+		return meta.TemplateVersion
+	}
+
+	var versions []string
+	if meta.Project != nil {
+		versions = append(versions, meta.Project.ScaffolderVersion)
+	}
+	for _, domain := range meta.Domains {
+		versions = append(versions, domain.ScaffolderVersion)
+	}
+	for _, wizard := range meta.Wizards {
+		versions = append(versions, wizard.ScaffolderVersion)
+	}
+
+	oldest := ""
+	for _, v := range versions {
+		v = strings.TrimSpace(v)
+		if v != "" && (oldest == "" || compareVersions(v, oldest) < 0) {
+			oldest = v
+		}
+	}
+	return oldest
+}
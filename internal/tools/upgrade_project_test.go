@@ -0,0 +1,139 @@
+package tools
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/metadata"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+// testMigrations move the router into its own package and rename the public routes marker.
+var testMigrations = []projectMigration{
+	{
+		version:     ScaffolderVersion,
+		description: "Router moved to internal/web/routes",
+		steps: []migrationStep{
+			moveFile("internal/web/router/router.go", "internal/web/routes/router.go"),
+			rewriteImport("internal/web/router", "internal/web/routes"),
+			renameMarker("ROUTES:PUBLIC", "ROUTES:GUEST"),
+		},
+	},
+}
+
+// setupOldLayout writes a project with the layout testMigrations upgrade from.
+func setupOldLayout(t *testing.T, tmpDir string) {
+	t.Helper()
+	setupGoMod(t, tmpDir, "github.com/example/app")
+	writeTestFile(t, filepath.Join(tmpDir, "cmd/web/main.go"), `package main
+
+import (
+	"github.com/example/app/internal/web/router"
+	"github.com/example/app/internal/web/router/middleware"
+)
+
+func main() {
+	r := router.New(middleware.Logger)
+	// MCP:ROUTES:PUBLIC:START
+	// MCP:ROUTES:PUBLIC:END
+	_ = r
+}
+`)
+	writeTestFile(t, filepath.Join(tmpDir, "internal/web/router/router.go"), "package router\n\n// MCP:ROUTES:PUBLIC:START\n// MCP:ROUTES:PUBLIC:END\n")
+}
+
+func TestUpgradeProject(t *testing.T) {
+	t.Run("applies the pending migrations and records the version", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupOldLayout(t, tmpDir)
+
+		result, err := upgradeProject(registry, types.UpgradeProjectInput{From: "0.0.1"}, testMigrations)
+		if err != nil || !result.Success {
+			t.Fatalf("upgrade failed: %v %s", err, result.Message)
+		}
+		if len(result.Migrations) != 1 || len(result.Migrations[0].Changes) != 4 {
+			t.Errorf("expected one migration with 4 changes, got %+v", result.Migrations)
+		}
+
+		mainGo := readFile(t, filepath.Join(tmpDir, "cmd/web/main.go"))
+		for _, want := range []string{`"github.com/example/app/internal/web/routes"`, `"github.com/example/app/internal/web/routes/middleware"`, "MCP:ROUTES:GUEST:START", "MCP:ROUTES:GUEST:END"} {
+			if !strings.Contains(mainGo, want) {
+				t.Errorf("main.go should contain %s, got:\n%s", want, mainGo)
+			}
+		}
+		if fileExists(filepath.Join(tmpDir, "internal/web/router/router.go")) {
+			t.Error("the router should be moved away")
+		}
+		if moved := readFile(t, filepath.Join(tmpDir, "internal/web/routes/router.go")); !strings.Contains(moved, "MCP:ROUTES:GUEST:START") {
+			t.Errorf("the moved router should have the renamed marker, got:\n%s", moved)
+		}
+		if result.BackupDir == "" || !fileExists(filepath.Join(tmpDir, result.BackupDir, "internal/web/router/router.go")) {
+			t.Errorf("expected the moved file to be backed up in %q", result.BackupDir)
+		}
+
+		meta, err := metadata.NewStore(tmpDir).Load()
+		if err != nil || meta.TemplateVersion != ScaffolderVersion {
+			t.Fatalf("expected template version %s recorded, got %q (%v)", ScaffolderVersion, meta.TemplateVersion, err)
+		}
+		again, _ := upgradeProject(registry, types.UpgradeProjectInput{}, testMigrations)
+		if !again.Success || !strings.Contains(again.Message, "up to date") {
+			t.Errorf("expected the project to be up to date, got: %s", again.Message)
+		}
+	})
+
+	t.Run("dry run writes nothing", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupOldLayout(t, tmpDir)
+		before := readFile(t, filepath.Join(tmpDir, "cmd/web/main.go"))
+
+		result, err := upgradeProject(registry, types.UpgradeProjectInput{From: "0.0.1", DryRun: true}, testMigrations)
+		if err != nil || !result.Success || len(result.FilesMoved) != 1 {
+			t.Fatalf("unexpected dry run: %v %+v", err, result)
+		}
+		if readFile(t, filepath.Join(tmpDir, "cmd/web/main.go")) != before || !fileExists(filepath.Join(tmpDir, "internal/web/router/router.go")) {
+			t.Error("dry run should not change files")
+		}
+		if meta, _ := metadata.NewStore(tmpDir).Load(); meta.TemplateVersion != "" {
+			t.Error("dry run should not record the version")
+		}
+	})
+
+	t.Run("a failing step changes nothing", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupOldLayout(t, tmpDir)
+		writeTestFile(t, filepath.Join(tmpDir, "internal/web/routes/router.go"), "package routes\n")
+		before := readFile(t, filepath.Join(tmpDir, "cmd/web/main.go"))
+
+		result, _ := upgradeProject(registry, types.UpgradeProjectInput{From: "0.0.1"}, testMigrations)
+		if result.Success || !strings.Contains(result.Message, "already exists") {
+			t.Fatalf("expected the move to fail, got: %s", result.Message)
+		}
+		if readFile(t, filepath.Join(tmpDir, "cmd/web/main.go")) != before {
+			t.Error("no file should change when a step fails")
+		}
+	})
+
+	t.Run("reads the version from the metadata", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupOldLayout(t, tmpDir)
+
+		result, _ := upgradeProject(registry, types.UpgradeProjectInput{}, testMigrations)
+		if result.Success || !strings.Contains(result.Message, "Pass from") {
+			t.Errorf("expected an unknown version error, got: %s", result.Message)
+		}
+
+		if err := metadata.NewStore(tmpDir).SaveDomain("order", types.ScaffoldDomainInput{DomainName: "order"}, "0.0.5"); err != nil {
+			t.Fatal(err)
+		}
+		result, _ = upgradeProject(registry, types.UpgradeProjectInput{DryRun: true}, testMigrations)
+		if !result.Success || result.From != "0.0.5" || len(result.Migrations) != 1 {
+			t.Errorf("expected an upgrade from the domain's version, got %+v", result)
+		}
+
+		result, _ = upgradeProject(registry, types.UpgradeProjectInput{From: "9.0.0"}, testMigrations)
+		if result.Success || !strings.Contains(result.Message, "newer than this scaffolder") {
+			t.Errorf("expected a newer version error, got: %s", result.Message)
+		}
+	})
+}
@@ -0,0 +1,407 @@
+package tools
+
+import (
+	"context"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/types"
+	"github.com/dbb1dev/go-mcp/internal/utils"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// stackReference is one way a generated project can reference a stack component version.
+type stackReference struct {
+	component string
+	// pattern matches the whole reference; its first group, if any, is the referenced version.
+	pattern *regexp.Regexp
+	// format returns the reference pinned to the given version.
+	format func(version string) string
+}
+
+// stackReferences are the version references upgrade_stack rewrites.
+// Older forms (the Tailwind v3 Play CDN, the tailwindcss npm CLI) are rewritten to the current ones.
+var stackReferences = []stackReference{
+	{
+		component: "templ",
+		pattern:   regexp.MustCompile(`github\.com/a-h/templ v([0-9][^\s]*)`),
+		format:    func(v string) string { return "github.com/a-h/templ v" + v },
+	},
+	{
+		component: "htmx",
+		pattern:   regexp.MustCompile(`htmx\.org@([0-9][^/"'\s]*)`),
+		format:    func(v string) string { return "htmx.org@" + v },
+	},
+	{
+		component: "htmx",
+		pattern:   regexp.MustCompile(`"htmx\.org": "[~^]?([0-9][^"]*)"`),
+		format:    func(v string) string { return `"htmx.org": "` + v + `"` },
+	},
+	{
+		component: "alpine",
+		pattern:   regexp.MustCompile(`alpinejs@([0-9][^/"'\s]*)`),
+		format:    func(v string) string { return "alpinejs@" + v },
+	},
+	{
+		component: "alpine",
+		pattern:   regexp.MustCompile(`"alpinejs": "[~^]?([0-9][^"]*)"`),
+		format:    func(v string) string { return `"alpinejs": "` + v + `"` },
+	},
+	{
+		component: "unpoly",
+		pattern:   regexp.MustCompile(`unpoly@([0-9][^/"'\s]*)`),
+		format:    func(v string) string { return "unpoly@" + v },
+	},
+	{
+		component: "tailwind",
+		pattern:   regexp.MustCompile(`https://cdn\.tailwindcss\.com(?:/([0-9][^?"'\s]*))?`),
+		format:    func(v string) string { return "https://cdn.jsdelivr.net/npm/@tailwindcss/browser@" + v },
+	},
+	{
+		component: "tailwind",
+		pattern:   regexp.MustCompile(`@tailwindcss/browser@([0-9][^/"'\s]*)`),
+		format:    func(v string) string { return "@tailwindcss/browser@" + v },
+	},
+	{
+		component: "tailwind",
+		pattern:   regexp.MustCompile(`npx (?:@tailwindcss/cli|tailwindcss)@(latest|[0-9][^"'\s]*)`),
+		format:    func(v string) string { return "npx @tailwindcss/cli@" + v },
+	},
+	{
+		component: "tailwind",
+		pattern:   regexp.MustCompile(`TAILWIND_VERSION: "([0-9][^"]*)"`),
+		format:    func(v string) string { return `TAILWIND_VERSION: "` + v + `"` },
+	},
+	{
+		component: "tailwind",
+		pattern:   regexp.MustCompile(`"@tailwindcss/cli": "[~^]?([0-9][^"]*)"`),
+		format:    func(v string) string { return `"@tailwindcss/cli": "` + v + `"` },
+	},
+	{
+		component: "tailwind",
+		pattern:   regexp.MustCompile(`"@tailwindcss/postcss": "[~^]?([0-9][^"]*)"`),
+		format:    func(v string) string { return `"@tailwindcss/postcss": "` + v + `"` },
+	},
+	{
+		component: "tailwind",
+		pattern:   regexp.MustCompile(`"tailwindcss": "[~^]?([0-9][^"]*)"`),
+		format:    func(v string) string { return `"tailwindcss": "` + v + `"` },
+	},
+}
+
+// upgradeStackSkipDirs are directories upgrade_stack never scans.
+var upgradeStackSkipDirs = map[string]bool{
+	".git":         true,
+	".mcp":         true,
+	"node_modules": true,
+	"vendor":       true,
+	"tmp":          true,
+	"bin":          true,
+}
+
+// RegisterUpgradeStack registers the upgrade_stack tool.
+func RegisterUpgradeStack(server *mcp.Server, registry *Registry) {
+	mcp.AddTool(server, &mcp.Tool{
+		Name: "upgrade_stack",
+		Description: `Upgrade templ, HTMX, Alpine, Tailwind and Unpoly in a generated project to the versions bundled with this scaffolder.
+
+Rewrites the version references the project templates pin:
+- templ: the github.com/a-h/templ requirement in go.mod
+- htmx, alpine, unpoly: unpkg CDN URLs in layouts and Go files, and the htmx.org and alpinejs
+  packages in package.json (js_bundler projects)
+- tailwind: the @tailwindcss/browser CDN URL and the npx @tailwindcss/cli command in Taskfile.yml
+  and the Dockerfile, the standalone CLI TAILWIND_VERSION in Taskfile.yml and the Tailwind
+  packages in package.json
+  (the v3 Play CDN https://cdn.tailwindcss.com and npx tailwindcss@latest are replaced)
+
+Generated *_templ.go files are skipped; run 'templ generate' afterwards.
+The previous version of every changed file is kept in .mcp/backups/<timestamp>/ (backup_dir).
+The result lists each version change, a unified diff per file and compatibility notes
+for major version jumps.
+
+Options:
+- components: limit the upgrade to some of templ, htmx, alpine, tailwind, unpoly (default: all)
+- dry_run: true to preview the diffs without writing files
+
+Examples:
+  upgrade_stack: { dry_run: true }
+  upgrade_stack: { components: ["htmx", "alpine"] }`,
+	}, func(ctx context.Context, req *mcp.CallToolRequest, input types.UpgradeStackInput) (*mcp.CallToolResult, types.UpgradeStackResult, error) {
+		call := registry.For(ctx)
+		unlock, _, _, err := call.lockProject()
+		if err != nil {
+			return nil, types.NewUpgradeStackError(err.Error()), nil
+		}
+		defer unlock()
+		result, err := upgradeStack(call, input)
+		if err != nil {
+			return nil, types.NewUpgradeStackError(err.Error()), nil
+		}
+		return nil, result, nil
+	})
+}
+
+func upgradeStack(registry *Registry, input types.UpgradeStackInput) (types.UpgradeStackResult, error) {
+	components := map[string]bool{}
+	for _, name := range input.Components {
+		if generator.StackVersion(name) == "" {
+			return types.NewUpgradeStackError(fmt.Sprintf("unknown component '%s': must be one of %s", name, strings.Join(stackComponentNames(), ", "))), nil
+		}
+		components[name] = true
+	}
+	if len(components) == 0 {
+		for _, name := range stackComponentNames() {
+			components[name] = true
+		}
+	}
+
+	if _, err := os.Stat(filepath.Join(registry.WorkingDir, "go.mod")); err != nil {
+		return types.NewUpgradeStackError("go.mod not found: run upgrade_stack from the root of a generated project"), nil
+	}
+
+	paths, err := upgradeStackFiles(registry.WorkingDir)
+	if err != nil {
+		return types.NewUpgradeStackError(fmt.Sprintf("failed to scan project: %v", err)), nil
+	}
+
+	result := types.UpgradeStackResult{Success: true, DryRun: input.DryRun}
+	backupDir := newBackupDir()
+	upgraded := map[string]string{} // component -> lowest version found
+	dmp := diffmatchpatch.New()
+
+	for _, relPath := range paths {
+		fullPath := filepath.Join(registry.WorkingDir, relPath)
+		content, err := os.ReadFile(fullPath)
+		if err != nil {
+			return types.NewUpgradeStackError(fmt.Sprintf("failed to read %s: %v", relPath, err)), nil
+		}
+
+		updated, upgrades := upgradeStackReferences(string(content), relPath, components)
+		if len(upgrades) == 0 {
+			continue
+		}
+		for _, u := range upgrades {
+			result.Upgrades = append(result.Upgrades, u)
+			if from, ok := upgraded[u.Component]; !ok || compareVersions(u.From, from) < 0 {
+				upgraded[u.Component] = u.From
+			}
+		}
+
+		existing, generated, lines := dmp.DiffLinesToChars(string(content), updated)
+		diffs := dmp.DiffCharsToLines(dmp.DiffMain(existing, generated, false), lines)
+		file := types.FileAnalysis{
+			Path:   relPath,
+			Status: "modified",
+			Diff:   generateUnifiedDiff(relPath, diffs),
+		}
+		for _, d := range diffs {
+			switch d.Type {
+			case diffmatchpatch.DiffInsert:
+				file.LinesAdded += strings.Count(d.Text, "\n")
+			case diffmatchpatch.DiffDelete:
+				file.LinesRemoved += strings.Count(d.Text, "\n")
+			}
+		}
+		result.Files = append(result.Files, file)
+
+		if !input.DryRun {
+			if err := utils.CopyFile(fullPath, filepath.Join(registry.WorkingDir, backupDir, relPath)); err != nil {
+				return types.NewUpgradeStackError(fmt.Sprintf("failed to back up %s: %v", relPath, err)), nil
+			}
+			result.BackupDir = backupDir
+			if err := os.WriteFile(fullPath, []byte(updated), 0644); err != nil {
+				return types.NewUpgradeStackError(fmt.Sprintf("failed to write %s: %v", relPath, err)), nil
+			}
+		}
+	}
+
+	if len(result.Upgrades) == 0 {
+		result.Message = "All stack versions are up to date"
+		return result, nil
+	}
+
+	for _, name := range stackComponentNames() {
+		if from, ok := upgraded[name]; ok {
+			result.Notes = append(result.Notes, stackCompatibilityNotes(name, from, generator.StackVersion(name))...)
+		}
+	}
+
+	if _, ok := upgraded["templ"]; ok {
+		result.NextSteps = append(result.NextSteps,
+			"go mod tidy",
+			fmt.Sprintf("go install github.com/a-h/templ/cmd/templ@v%s", generator.StackVersion("templ")),
+		)
+	}
+	result.NextSteps = append(result.NextSteps, "templ generate", "task build")
+
+	verb := "Upgraded"
+	if input.DryRun {
+		verb = "Would upgrade"
+	}
+	result.Message = fmt.Sprintf("%s %d version reference(s) in %d file(s)", verb, len(result.Upgrades), len(result.Files))
+	if result.BackupDir != "" {
+		result.Message += fmt.Sprintf("; the previous versions are in %s", result.BackupDir)
+	}
+	return result, nil
+}
+
+// upgradeStackFiles returns the project files that may reference stack versions, relative to dir.
+func upgradeStackFiles(dir string) ([]string, error) {
+	var paths []string
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if path != dir && upgradeStackSkipDirs[d.Name()] {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		name := d.Name()
+		switch {
+		case strings.HasSuffix(name, "_templ.go"):
+			return nil // regenerated by templ generate
+		case name == "go.mod",
+			name == "Dockerfile",
+			name == "package.json",
+			strings.HasSuffix(name, ".go"),
+			strings.HasSuffix(name, ".templ"),
+			strings.HasSuffix(name, ".html"),
+			strings.HasSuffix(name, ".yml"),
+			strings.HasSuffix(name, ".yaml"):
+			rel, err := filepath.Rel(dir, path)
+			if err != nil {
+				return err
+			}
+			paths = append(paths, rel)
+		}
+		return nil
+	})
+	return paths, err
+}
+
+// upgradeStackReferences pins every reference of the selected components in content to
+// the bundled version. It returns the new content and one upgrade per changed reference.
+func upgradeStackReferences(content, path string, components map[string]bool) (string, []types.StackUpgrade) {
+	var upgrades []types.StackUpgrade
+	for _, ref := range stackReferences {
+		if !components[ref.component] {
+			continue
+		}
+		to := generator.StackVersion(ref.component)
+		content = ref.pattern.ReplaceAllStringFunc(content, func(match string) string {
+			pinned := ref.format(to)
+			if match == pinned {
+				return match
+			}
+			from := "unpinned"
+			if sub := ref.pattern.FindStringSubmatch(match); len(sub) > 1 && sub[1] != "" {
+				from = sub[1]
+			}
+			upgrades = append(upgrades, types.StackUpgrade{
+				Component: ref.component,
+				File:      path,
+				From:      from,
+				To:        to,
+			})
+			return pinned
+		})
+	}
+	return content, upgrades
+}
+
+// stackCompatibilityNotes returns the notes for upgrading a component from one version to another.
+func stackCompatibilityNotes(component, from, to string) []string {
+	fromMajor, pinned := majorVersion(from)
+	toMajor, _ := majorVersion(to)
+
+	var notes []string
+	if !pinned {
+		notes = append(notes, fmt.Sprintf("%s was not pinned to an exact version; it is now pinned to %s", component, to))
+	}
+
+	switch component {
+	case "templ":
+		notes = append(notes, fmt.Sprintf("templ: the templ CLI must match go.mod; install v%s and regenerate all *_templ.go files", to))
+	case "htmx":
+		if pinned && fromMajor < 2 && toMajor >= 2 {
+			notes = append(notes, "htmx 2: extensions are no longer bundled, hx-on uses the hx-on:event syntax, and DELETE sends parameters in the query string. See https://htmx.org/migration-guide-htmx-1/")
+		}
+	case "alpine":
+		if pinned && fromMajor < 3 && toMajor >= 3 {
+			notes = append(notes, "Alpine 3: x-init no longer receives a callback, $el refers to the current element and Alpine.start() must be called when bundling. See https://alpinejs.dev/upgrade-guide")
+		}
+	case "tailwind":
+		if !pinned || fromMajor < 4 {
+			notes = append(notes, "Tailwind 4: the CLI moved to @tailwindcss/cli and the Play CDN to @tailwindcss/browser; configuration lives in CSS (@import \"tailwindcss\" and @theme) instead of tailwind.config.js. Run npx @tailwindcss/upgrade for projects that still use @tailwind directives")
+		}
+	case "unpoly":
+		if pinned && fromMajor < 3 && toMajor >= 3 {
+			notes = append(notes, "Unpoly 3: several up-* attributes and events were renamed; load unpoly-migrate.js while upgrading. See https://unpoly.com/changes/upgrading")
+		}
+	}
+
+	if pinned && toMajor < fromMajor {
+		notes = append(notes, fmt.Sprintf("%s: the project referenced a newer major version (%s) than the bundled %s; pass components to exclude it", component, from, to))
+	}
+	return notes
+}
+
+// majorVersion parses the major version of a version reference such as "2.0.0" or "v0.3.857".
+// It reports false for references that are not pinned, such as "latest" or "3.x.x".
+func majorVersion(version string) (int, bool) {
+	version = strings.TrimPrefix(version, "v")
+	head, rest, _ := strings.Cut(version, ".")
+	major, err := strconv.Atoi(head)
+	if err != nil || strings.Contains(rest, "x") {
+		return major, false
+	}
+	return major, true
+}
+
+// compareVersions compares two dotted version strings numerically.
+// Non-numeric parts (such as "x" or "latest") sort first.
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(a, "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(b, "v"), ".")
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y int = -1, -1
+		if i < len(as) {
+			if n, err := strconv.Atoi(as[i]); err == nil {
+				x = n
+			}
+		}
+		if i < len(bs) {
+			if n, err := strconv.Atoi(bs[i]); err == nil {
+				y = n
+			}
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// stackComponentNames returns the names of the bundled stack components, sorted.
+func stackComponentNames() []string {
+	names := make([]string, len(generator.Stack))
+	for i, c := range generator.Stack {
+		names[i] = c.Name
+	}
+	slices.Sort(names)
+	return names
+}
@@ -0,0 +1,217 @@
+package tools
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/dbb1dev/go-mcp/internal/generator"
+	"github.com/dbb1dev/go-mcp/internal/types"
+)
+
+// setupOutdatedStack writes a project that pins older stack versions.
+func setupOutdatedStack(t *testing.T, tmpDir string) {
+	t.Helper()
+	files := map[string]string{
+		"go.mod": "module github.com/example/app\n\ngo 1.22\n\nrequire (\n\tgithub.com/a-h/templ v0.2.543\n\tgithub.com/go-chi/chi/v5 v5.1.0\n)\n",
+		"internal/web/layouts/base.templ": `<script src="https://unpkg.com/htmx.org@1.9.10"></script>
+<script defer src="https://unpkg.com/alpinejs@3.x.x/dist/cdn.min.js"></script>
+<script src="https://cdn.tailwindcss.com"></script>
+`,
+		"internal/web/layouts/base_templ.go": `"<script src=\"https://unpkg.com/htmx.org@1.9.10\"></script>"`,
+		"Taskfile.yml": `    sh: command -v tailwindcss >/dev/null 2>&1 && echo "tailwindcss" || echo "npx tailwindcss@latest"
+`,
+	}
+	for path, content := range files {
+		fullPath := filepath.Join(tmpDir, path)
+		if err := os.MkdirAll(filepath.Dir(fullPath), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(fullPath, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestUpgradeStack(t *testing.T) {
+	t.Run("dry run previews diffs without writing", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupOutdatedStack(t, tmpDir)
+		before := readFile(t, filepath.Join(tmpDir, "internal/web/layouts/base.templ"))
+
+		result, err := upgradeStack(registry, types.UpgradeStackInput{DryRun: true})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+		if len(result.Upgrades) != 5 {
+			t.Errorf("expected 5 upgrades, got %d: %+v", len(result.Upgrades), result.Upgrades)
+		}
+		if len(result.Files) != 3 {
+			t.Fatalf("expected 3 changed files, got %d", len(result.Files))
+		}
+		for _, f := range result.Files {
+			if strings.HasSuffix(f.Path, "_templ.go") {
+				t.Errorf("generated templ file should be skipped: %s", f.Path)
+			}
+			if f.Diff == "" || f.LinesAdded == 0 || f.LinesRemoved == 0 {
+				t.Errorf("expected a diff for %s, got %+v", f.Path, f)
+			}
+		}
+		if got := readFile(t, filepath.Join(tmpDir, "internal/web/layouts/base.templ")); got != before {
+			t.Error("dry run should not modify files")
+		}
+	})
+
+	t.Run("pins every reference to the bundled versions", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupOutdatedStack(t, tmpDir)
+
+		result, err := upgradeStack(registry, types.UpgradeStackInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !result.Success {
+			t.Fatalf("expected success, got failure: %s", result.Message)
+		}
+
+		layout := readFile(t, filepath.Join(tmpDir, "internal/web/layouts/base.templ"))
+		for _, want := range []string{
+			"htmx.org@" + generator.StackVersion("htmx"),
+			"alpinejs@" + generator.StackVersion("alpine"),
+			"@tailwindcss/browser@" + generator.StackVersion("tailwind"),
+		} {
+			if !strings.Contains(layout, want) {
+				t.Errorf("layout should contain %q, got:\n%s", want, layout)
+			}
+		}
+		if goMod := readFile(t, filepath.Join(tmpDir, "go.mod")); !strings.Contains(goMod, "github.com/a-h/templ v"+generator.StackVersion("templ")) {
+			t.Errorf("go.mod should pin templ, got:\n%s", goMod)
+		}
+		if taskfile := readFile(t, filepath.Join(tmpDir, "Taskfile.yml")); !strings.Contains(taskfile, "npx @tailwindcss/cli@"+generator.StackVersion("tailwind")) {
+			t.Errorf("Taskfile should pin the Tailwind CLI, got:\n%s", taskfile)
+		}
+		if generated := readFile(t, filepath.Join(tmpDir, "internal/web/layouts/base_templ.go")); !strings.Contains(generated, "htmx.org@1.9.10") {
+			t.Error("generated templ file should not be modified")
+		}
+
+		notes := strings.Join(result.Notes, "\n")
+		for _, want := range []string{"htmx 2", "Tailwind 4", "templ CLI", "alpine was not pinned"} {
+			if !strings.Contains(notes, want) {
+				t.Errorf("notes should mention %q, got:\n%s", want, notes)
+			}
+		}
+
+		if result.BackupDir == "" {
+			t.Fatal("expected a backup directory")
+		}
+		if backup := readFile(t, filepath.Join(tmpDir, result.BackupDir, "go.mod")); !strings.Contains(backup, "github.com/a-h/templ v0.2.543") {
+			t.Errorf("backup of go.mod should hold the previous version, got:\n%s", backup)
+		}
+
+		again, err := upgradeStack(registry, types.UpgradeStackInput{})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(again.Upgrades) != 0 {
+			t.Errorf("second run should find nothing to upgrade, got %+v", again.Upgrades)
+		}
+	})
+
+	t.Run("upgrades the Tailwind CLI in the Dockerfile", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/app")
+		dockerfile := "RUN npx @tailwindcss/cli@4.0.0 -i ./assets/css/input.css -o ./assets/css/output.css --minify\n"
+		if err := os.WriteFile(filepath.Join(tmpDir, "Dockerfile"), []byte(dockerfile), 0644); err != nil {
+			t.Fatal(err)
+		}
+
+		result, err := upgradeStack(registry, types.UpgradeStackInput{Components: []string{"tailwind"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Files) != 1 || result.Files[0].Path != "Dockerfile" {
+			t.Fatalf("expected the Dockerfile to change, got %+v", result.Files)
+		}
+		if got := readFile(t, filepath.Join(tmpDir, "Dockerfile")); !strings.Contains(got, "npx @tailwindcss/cli@"+generator.StackVersion("tailwind")+" ") {
+			t.Errorf("Dockerfile should pin the Tailwind CLI, got:\n%s", got)
+		}
+	})
+
+	t.Run("upgrades package.json and the standalone CLI", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupGoMod(t, tmpDir, "github.com/example/app")
+		files := map[string]string{
+			"package.json": "{\n  \"dependencies\": {\n    \"alpinejs\": \"3.13.0\",\n    \"htmx.org\": \"^1.9.10\"\n  },\n  \"devDependencies\": {\n    \"@tailwindcss/postcss\": \"^4.0.0\",\n    \"daisyui\": \"^5\",\n    \"tailwindcss\": \"4.0.0\"\n  }\n}\n",
+			"Taskfile.yml": "vars:\n  TAILWIND_VERSION: \"4.0.0\"\n",
+		}
+		for path, content := range files {
+			if err := os.WriteFile(filepath.Join(tmpDir, path), []byte(content), 0644); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		result, err := upgradeStack(registry, types.UpgradeStackInput{Components: []string{"tailwind", "htmx", "alpine"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Upgrades) != 5 {
+			t.Errorf("expected 5 upgrades, got %d: %+v", len(result.Upgrades), result.Upgrades)
+		}
+		version := generator.StackVersion("tailwind")
+		pkg := readFile(t, filepath.Join(tmpDir, "package.json"))
+		wants := []string{
+			`"@tailwindcss/postcss": "` + version + `"`,
+			`"tailwindcss": "` + version + `"`,
+			`"daisyui": "^5"`,
+			`"htmx.org": "` + generator.StackVersion("htmx") + `"`,
+			`"alpinejs": "` + generator.StackVersion("alpine") + `"`,
+		}
+		for _, want := range wants {
+			if !strings.Contains(pkg, want) {
+				t.Errorf("package.json should contain %q, got:\n%s", want, pkg)
+			}
+		}
+		if got := readFile(t, filepath.Join(tmpDir, "Taskfile.yml")); !strings.Contains(got, `TAILWIND_VERSION: "`+version+`"`) {
+			t.Errorf("Taskfile.yml should pin the standalone CLI, got:\n%s", got)
+		}
+	})
+
+	t.Run("limits the upgrade to the given components", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupOutdatedStack(t, tmpDir)
+
+		result, err := upgradeStack(registry, types.UpgradeStackInput{Components: []string{"htmx"}})
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(result.Upgrades) != 1 || result.Upgrades[0].Component != "htmx" || result.Upgrades[0].From != "1.9.10" {
+			t.Errorf("expected only the htmx upgrade, got %+v", result.Upgrades)
+		}
+		if layout := readFile(t, filepath.Join(tmpDir, "internal/web/layouts/base.templ")); !strings.Contains(layout, "cdn.tailwindcss.com") {
+			t.Error("tailwind should not be upgraded")
+		}
+	})
+
+	t.Run("rejects unknown components", func(t *testing.T) {
+		registry, tmpDir := testRegistry(t)
+		setupOutdatedStack(t, tmpDir)
+
+		result, _ := upgradeStack(registry, types.UpgradeStackInput{Components: []string{"react"}})
+		if result.Success {
+			t.Error("expected failure for unknown component")
+		}
+	})
+
+	t.Run("requires a go.mod", func(t *testing.T) {
+		registry, _ := testRegistry(t)
+
+		result, _ := upgradeStack(registry, types.UpgradeStackInput{})
+		if result.Success {
+			t.Error("expected failure without go.mod")
+		}
+	})
+}
@@ -0,0 +1,159 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/dbb1dev/go-mcp/internal/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// workdirParam is the optional argument of every tool that selects the project of the call,
+// so one server can scaffold several projects in a session.
+const workdirParam = "workdir"
+
+const workdirDescription = "Project directory of this call, absolute or relative to the server's working directory. " +
+	"Defaults to the server's working directory. Must be inside one of the server's allowed roots (MCP_SCAFFOLD_ROOTS)."
+
+// workdirKey is the context key of the working directory of a tool call.
+type workdirKey struct{}
+
+// For returns the registry of a tool call: the registry itself, or a copy working in the
+// directory the call selected with its workdir argument. Copies share the job queue.
+func (r *Registry) For(ctx context.Context) *Registry {
+	dir, ok := ctx.Value(workdirKey{}).(string)
+	if !ok || dir == r.WorkingDir {
+		return r
+	}
+	call := *r
+	call.WorkingDir = dir
+	return &call
+}
+
+// ResolveWorkdir resolves the workdir argument of a tool call, relative to the working
+// directory, and checks that it is inside an allowed root. Symbolic links are followed,
+// so a link can't lead a call out of the roots. The directory itself needn't exist yet,
+// which lets scaffold_project create a new project.
+func (r *Registry) ResolveWorkdir(dir string) (string, error) {
+	if strings.TrimSpace(dir) == "" {
+		return "", fmt.Errorf("workdir is empty")
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(r.WorkingDir, dir)
+	}
+	dir = filepath.Clean(dir)
+	real := resolveSymlinks(dir)
+
+	roots := r.Roots
+	if len(roots) == 0 {
+		roots = []string{r.WorkingDir}
+	}
+	for _, root := range roots {
+		rel, err := filepath.Rel(resolveSymlinks(filepath.Clean(root)), real)
+		if err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+			return config.ProjectDir(dir), nil
+		}
+	}
+	return "", fmt.Errorf("workdir %s is outside the allowed roots (%s): add its directory to MCP_SCAFFOLD_ROOTS", dir, strings.Join(roots, string(filepath.ListSeparator)))
+}
+
+// resolveSymlinks resolves the symbolic links of the longest existing ancestor of path.
+func resolveSymlinks(path string) string {
+	rest := ""
+	for {
+		if real, err := filepath.EvalSymlinks(path); err == nil {
+			return filepath.Join(real, rest)
+		} else if !os.IsNotExist(err) {
+			return filepath.Join(path, rest)
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return filepath.Join(path, rest)
+		}
+		rest = filepath.Join(filepath.Base(path), rest)
+		path = parent
+	}
+}
+
+// workdirMiddleware adds the workdir argument to the input schema of every tool, and takes
+// it out of the arguments of tool calls before their input is validated, passing the
+// resolved directory to the tool through the context.
+func (r *Registry) workdirMiddleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		switch method {
+		case "tools/list":
+			result, err := next(ctx, method, req)
+			if list, ok := result.(*mcp.ListToolsResult); ok && err == nil {
+				addWorkdirParam(list)
+			}
+			return result, err
+		case "tools/call":
+			call, ok := req.(*mcp.CallToolRequest)
+			if !ok {
+				break
+			}
+			dir, err := r.takeWorkdir(call.Params)
+			if err != nil {
+				result := &mcp.CallToolResult{IsError: true, Content: []mcp.Content{&mcp.TextContent{Text: err.Error()}}}
+				return result, nil
+			}
+			if dir != "" {
+				ctx = context.WithValue(ctx, workdirKey{}, dir)
+			}
+		}
+		return next(ctx, method, req)
+	}
+}
+
+// takeWorkdir removes the workdir argument of a tool call and resolves it. It returns ""
+// when the call has none. Arguments that aren't an object are left to the tool's validation.
+func (r *Registry) takeWorkdir(params *mcp.CallToolParamsRaw) (string, error) {
+	var args map[string]json.RawMessage
+	if params == nil || json.Unmarshal(params.Arguments, &args) != nil {
+		return "", nil
+	}
+	raw, ok := args[workdirParam]
+	if !ok {
+		return "", nil
+	}
+	var dir string
+	if err := json.Unmarshal(raw, &dir); err != nil {
+		return "", fmt.Errorf("workdir must be a string")
+	}
+	delete(args, workdirParam)
+	rest, err := json.Marshal(args)
+	if err != nil {
+		return "", err
+	}
+	params.Arguments = rest
+	return r.ResolveWorkdir(dir)
+}
+
+// addWorkdirParam adds the workdir property to the input schemas of a tool list. The tools
+// are copied, as the list holds the server's own tools.
+func addWorkdirParam(list *mcp.ListToolsResult) {
+	for i, tool := range list.Tools {
+		data, err := json.Marshal(tool.InputSchema)
+		if err != nil {
+			continue
+		}
+		var schema map[string]any
+		if json.Unmarshal(data, &schema) != nil || schema == nil {
+			continue
+		}
+		properties, _ := schema["properties"].(map[string]any)
+		if properties == nil {
+			properties = map[string]any{}
+		}
+		properties[workdirParam] = map[string]any{"type": "string", "description": workdirDescription}
+		schema["properties"] = properties
+
+		copied := *tool
+		copied.InputSchema = schema
+		list.Tools[i] = &copied
+	}
+}
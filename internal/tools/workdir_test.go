@@ -0,0 +1,114 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestRegistry_ResolveWorkdir(t *testing.T) {
+	registry, tmpDir := testRegistry(t)
+	outside := t.TempDir()
+	if err := os.Symlink(outside, filepath.Join(tmpDir, "escape")); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		roots   []string
+		dir     string
+		want    string
+		wantErr string
+	}{
+		{"relative", nil, "projects/blog", filepath.Join(tmpDir, "projects", "blog"), ""},
+		{"working dir", nil, tmpDir, tmpDir, ""},
+		{"parent", nil, "..", "", "outside the allowed roots"},
+		{"other directory", nil, outside, "", "outside the allowed roots"},
+		{"symlink out of the root", nil, "escape/blog", "", "outside the allowed roots"},
+		{"allowed root", []string{tmpDir, outside}, filepath.Join(outside, "shop"), filepath.Join(outside, "shop"), ""},
+		{"empty", nil, " ", "", "workdir is empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			registry.Roots = tt.roots
+			got, err := registry.ResolveWorkdir(tt.dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("ResolveWorkdir(%q) = %q, %v, want error %q", tt.dir, got, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Errorf("ResolveWorkdir(%q) = %q, %v, want %q", tt.dir, got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestWorkdirArgument(t *testing.T) {
+	registry, tmpDir := testRegistry(t)
+	server := mcp.NewServer(&mcp.Implementation{Name: "test-server", Version: "1.0.0"}, nil)
+	registry.RegisterAll(server)
+
+	ctx := context.Background()
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	if _, err := server.Connect(ctx, serverTransport, nil); err != nil {
+		t.Fatal(err)
+	}
+	client := mcp.NewClient(&mcp.Implementation{Name: "test-client", Version: "1.0.0"}, nil)
+	session, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer session.Close()
+
+	tools, err := session.ListTools(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tool := range tools.Tools {
+		schema, _ := json.Marshal(tool.InputSchema)
+		if !strings.Contains(string(schema), `"workdir"`) {
+			t.Errorf("expected %s to take a workdir argument, got %s", tool.Name, schema)
+		}
+	}
+
+	// Two projects scaffolded in the same session
+	for _, name := range []string{"blog", "shop"} {
+		result, err := session.CallTool(ctx, &mcp.CallToolParams{
+			Name: "scaffold_project",
+			Arguments: map[string]any{
+				"project_name":   name,
+				"module_path":    "github.com/example/" + name,
+				"in_current_dir": true,
+				"workdir":        filepath.Join("projects", name),
+			},
+		})
+		if err != nil || result.IsError {
+			t.Fatalf("scaffold_project in %s failed: %v %+v", name, err, result)
+		}
+		goMod := readFile(t, filepath.Join(tmpDir, "projects", name, "go.mod"))
+		if !strings.Contains(goMod, "module github.com/example/"+name) {
+			t.Errorf("expected the %s go.mod, got:\n%s", name, goMod)
+		}
+	}
+	if fileExists(filepath.Join(tmpDir, "go.mod")) {
+		t.Error("expected nothing to be scaffolded in the server's working directory")
+	}
+
+	result, err := session.CallTool(ctx, &mcp.CallToolParams{
+		Name:      "list_domains",
+		Arguments: map[string]any{"workdir": "/etc"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.IsError || !strings.Contains(result.Content[0].(*mcp.TextContent).Text, "outside the allowed roots") {
+		t.Errorf("expected a workdir outside the roots to fail, got %+v", result)
+	}
+}
@@ -9,10 +9,37 @@ type ScaffoldProjectInput struct {
 	ModulePath string `json:"module_path"`
 	// DatabaseType is the database driver: sqlite, postgres, or mysql.
 	DatabaseType string `json:"database_type,omitempty"`
+	// ProjectType is web (default, templ + HTMX + Tailwind) or api (JSON API without views).
+	ProjectType string `json:"project_type,omitempty"`
 	// WithAuth enables authentication scaffolding.
 	WithAuth bool `json:"with_auth,omitempty"`
 	// WithUserManagement enables admin user management (requires with_auth).
 	WithUserManagement bool `json:"with_user_management,omitempty"`
+	// Navigation enables soft page navigation: none (default), boost (htmx hx-boost), or unpoly.
+	Navigation string `json:"navigation,omitempty"`
+	// WithDarkMode adds a light/dark/system color scheme toggle, kept in a cookie, and switches the
+	// Tailwind dark variant to the .dark class the layout sets from it.
+	WithDarkMode bool `json:"with_dark_mode,omitempty"`
+	// CSSPipeline is how assets/css/output.css is built: tailwind (default, the Tailwind v4 CLI
+	// through npx), standalone (the Tailwind standalone CLI, no Node.js), daisyui (Tailwind with the
+	// daisyUI plugin from npm) or postcss (PostCSS with @tailwindcss/postcss).
+	CSSPipeline string `json:"css_pipeline,omitempty"`
+	// JSBundler bundles htmx, Alpine and assets/js into hashed files in assets/dist instead of
+	// loading htmx and Alpine from CDN script tags: none (default), esbuild or vite.
+	JSBundler string `json:"js_bundler,omitempty"`
+	// IconSet is the SVG icon set of the components.Icon helper: heroicons (default) or lucide.
+	IconSet string `json:"icon_set,omitempty"`
+	// WithHTTPCaching adds ETag/If-None-Match handling and per route group Cache-Control headers.
+	WithHTTPCaching bool `json:"with_http_caching,omitempty"`
+	// WithOtel adds OpenTelemetry tracing: an OTLP exporter, HTTP server spans and GORM query spans.
+	WithOtel bool `json:"with_otel,omitempty"`
+	// ErrorReporting reports panics and server errors to a service: none (default) or sentry.
+	ErrorReporting string `json:"error_reporting,omitempty"`
+	// WithDiagnostics mounts pprof and expvar on the admin route group (requires with_auth).
+	WithDiagnostics bool `json:"with_diagnostics,omitempty"`
+	// DI is how cmd/web/main.go gets the domain services and controllers: imperative (default),
+	// constructor calls injected into main.go, or wire, google/wire provider sets per domain.
+	DI string `json:"di,omitempty"`
 	// InCurrentDir generates files in the current directory instead of a subdirectory.
 	InCurrentDir bool `json:"in_current_dir,omitempty"`
 	// DryRun previews changes without writing files.
@@ -29,7 +56,8 @@ type FieldDef struct {
 	GORMTags string `json:"gorm_tags,omitempty"`
 	// JSONTag is the JSON field name (defaults to snake_case of Name).
 	JSONTag string `json:"json_tag,omitempty"`
-	// FormType is the form input type: input, textarea, select, checkbox, date, email, password, number, rating, tags, slider.
+	// FormType is the form input type: input, textarea, select, checkbox, date, email, password, number, rating, tags, slider, image.
+	// An image field is a string holding the storage key of an uploaded image.
 	FormType string `json:"form_type,omitempty"`
 	// Required indicates if the field is required in forms.
 	Required bool `json:"required,omitempty"`
@@ -37,20 +65,56 @@ type FieldDef struct {
 	Label string `json:"label,omitempty"`
 	// Options is a list of options for select fields (e.g., ["draft", "published", "archived"]).
 	Options []string `json:"options,omitempty"`
+	// Embedded lists the fields of an embedded value type. When set, Type names the struct
+	// (e.g., "Address") and its fields are stored as prefixed columns of this model.
+	Embedded []EmbeddedFieldDef `json:"embedded,omitempty"`
+	// InlineEdit makes the field editable in place from the list view, like listing it
+	// in the domain's inline_edit.
+	InlineEdit bool `json:"inline_edit,omitempty"`
+	// Min is the smallest value of a number field, or the fewest characters of a string field.
+	Min *float64 `json:"min,omitempty"`
+	// Max is the largest value of a number field, or the most characters of a string field.
+	Max *float64 `json:"max,omitempty"`
+	// Pattern is a regular expression a string field must match as a whole (e.g., "[A-Z]{3}-[0-9]+").
+	// It is checked by the browser and the service, so use syntax JavaScript and Go share.
+	Pattern string `json:"pattern,omitempty"`
+}
+
+// EmbeddedFieldDef defines a field of an embedded value type.
+type EmbeddedFieldDef struct {
+	// Name is the field name in PascalCase (e.g., "Street").
+	Name string `json:"name"`
+	// Type is the Go type: string, int, int64, uint, float64, or bool.
+	Type string `json:"type"`
+	// JSONTag is the JSON field name (defaults to snake_case of Name).
+	JSONTag string `json:"json_tag,omitempty"`
+	// Required indicates if the field is required in forms.
+	Required bool `json:"required,omitempty"`
+	// Label is the display label for forms (defaults to Name with spaces).
+	Label string `json:"label,omitempty"`
 }
 
 // RelationshipDef defines a model relationship.
 type RelationshipDef struct {
-	// Type is the relationship type: belongs_to, has_one, has_many, many_to_many.
+	// Type is the relationship type: belongs_to, has_one, has_many, many_to_many, polymorphic.
 	Type string `json:"type"`
 	// Model is the related model name in PascalCase (e.g., "User", "OrderItem").
+	// For polymorphic relationships it is the polymorphic name (e.g., "Commentable").
 	Model string `json:"model"`
+	// Models lists the owner models of a polymorphic relationship (e.g., ["Post", "Video"]).
+	Models []string `json:"models,omitempty"`
 	// ForeignKey is the foreign key field name. Defaults to {Model}ID for belongs_to.
 	ForeignKey string `json:"foreign_key,omitempty"`
 	// References is the referenced field. Defaults to "ID".
 	References string `json:"references,omitempty"`
 	// JoinTable is the join table name (for many_to_many).
 	JoinTable string `json:"join_table,omitempty"`
+	// JoinFields are extra columns on the many_to_many join table (e.g., Role, Position).
+	// When set, an explicit join model is generated with attach/detach helpers.
+	JoinFields []FieldDef `json:"join_fields,omitempty"`
+	// NestedForm edits the children of a has_many relationship as rows in the parent's
+	// create/edit form, saved together with the parent. The child domain must be scaffolded first.
+	NestedForm bool `json:"nested_form,omitempty"`
 	// OnDelete is the delete behavior: CASCADE, SET NULL, RESTRICT. Defaults to CASCADE.
 	OnDelete string `json:"on_delete,omitempty"`
 	// Preload indicates if the relationship should be preloaded by default.
@@ -60,6 +124,37 @@ type RelationshipDef struct {
 	DisplayField string `json:"display_field,omitempty"`
 }
 
+// FilterDef defines a list filter for scaffold_domain.
+type FilterDef struct {
+	// Field is the name of the field to filter on (e.g., "Status", "Price").
+	Field string `json:"field"`
+	// Operator is the comparison: eq, contains, gt, gte, lt, lte.
+	// Defaults to contains for strings, gte for times and eq otherwise.
+	Operator string `json:"operator,omitempty"`
+	// Widget is the filter control: text, select, number, date, checkbox.
+	// Defaults from the field type (select for fields with options).
+	Widget string `json:"widget,omitempty"`
+	// Options are the choices of a select widget. Defaults to the field's options.
+	Options []string `json:"options,omitempty"`
+	// Label is the display label (defaults to the field label).
+	Label string `json:"label,omitempty"`
+}
+
+// BulkActionDef defines a custom bulk action for scaffold_domain: it sets a field
+// to a fixed value on every selected record.
+type BulkActionDef struct {
+	// Name is the action name in snake_case (e.g., "publish", "mark_shipped").
+	Name string `json:"name"`
+	// Label is the button label (defaults to the title-cased name).
+	Label string `json:"label,omitempty"`
+	// Field is the name of the field to set (e.g., "Status").
+	Field string `json:"field"`
+	// Value is the value to set, as text (e.g., "published", "true", "0").
+	Value string `json:"value"`
+	// Confirm is an optional confirmation prompt shown before the action runs.
+	Confirm string `json:"confirm,omitempty"`
+}
+
 // ScaffoldDomainInput is the input for the scaffold_domain tool.
 type ScaffoldDomainInput struct {
 	// DomainName is the domain name in singular form (e.g., "product").
@@ -75,10 +170,76 @@ type ScaffoldDomainInput struct {
 	// Layout specifies the view layout: dashboard, base, auth, none. Defaults to "dashboard".
 	Layout string `json:"layout,omitempty"`
 	// RouteGroup specifies the middleware context: public, authenticated, admin. Defaults to "public".
+	// API-only projects also accept versioned groups (api_v1, api_v2, ...) mounted under /api/vN.
 	RouteGroup string `json:"route_group,omitempty"`
+	// OwnedByUser adds a UserID owner to authenticated domains and limits every query to the
+	// session user's records. Defaults to true for route_group authenticated; set false to share them.
+	OwnedByUser *bool `json:"owned_by_user,omitempty"`
+	// WithVersionedDTO generates a response DTO frozen for the route group's API version
+	// (e.g., V1ProductResponse), so the model can change without breaking clients of that version.
+	// Requires an api_vN route group.
+	WithVersionedDTO bool `json:"with_versioned_dto,omitempty"`
 	// FormStyle specifies how forms are displayed: modal (default) or page.
 	// Modal shows forms in a popup overlay, page uses full page navigation.
 	FormStyle string `json:"form_style,omitempty"`
+	// InlineEdit lists field names that can be edited in place from the list view
+	// (e.g., ["Status", "Name"]). Fields can also set inline_edit: true. Requires with_crud_views.
+	InlineEdit []string `json:"inline_edit,omitempty"`
+	// WithOptimisticLocking adds a version column that is checked and incremented on update,
+	// rejecting stale edits with a conflict instead of silently overwriting them.
+	WithOptimisticLocking bool `json:"with_optimistic_locking,omitempty"`
+	// WithObservability instruments the service with OpenTelemetry spans and metrics
+	// (created/updated/deleted counters, list latency), using the global tracer and meter providers.
+	WithObservability bool `json:"with_observability,omitempty"`
+	// WithModeration adds a moderation status (pending/approved/rejected) to user-generated
+	// content, an admin moderation queue and approve/reject endpoints. Public views only show
+	// approved records.
+	WithModeration bool `json:"with_moderation,omitempty"`
+	// Icon is the icon of the domain's sidebar link and of its empty states (e.g., "shopping-cart"),
+	// one of the names of the project's icon set. Defaults to folder for the link and inbox for
+	// the empty states.
+	Icon string `json:"icon,omitempty"`
+	// Filters adds a filter bar above the list view (e.g., [{field: "Status", widget: "select"}]).
+	// Filter values are read from query parameters and kept across pagination.
+	Filters []FilterDef `json:"filters,omitempty"`
+	// WithBulkActions adds row checkboxes and a bulk action toolbar (delete, export to CSV)
+	// to the list view, backed by a POST {path}/bulk endpoint and batch repository operations.
+	WithBulkActions bool `json:"with_bulk_actions,omitempty"`
+	// BulkActions adds custom bulk actions to the toolbar (e.g., [{name: "publish", field: "Status",
+	// value: "published"}]). Declaring bulk actions implies with_bulk_actions.
+	BulkActions []BulkActionDef `json:"bulk_actions,omitempty"`
+	// WithScheduling adds PublishAt/UnpublishAt dates and a publish state (draft, scheduled,
+	// published, expired) that a background scheduler keeps current. Public routes only
+	// show published records.
+	WithScheduling bool `json:"with_scheduling,omitempty"`
+	// WithCache wraps the repository in a cache-aside decorator that caches FindByID and FindAll
+	// in Redis (REDIS_URL) or in memory, and invalidates them on writes.
+	WithCache bool `json:"with_cache,omitempty"`
+	// TenantScoped adds a TenantID column so every query on the domain is limited to the
+	// request's tenant and new records belong to it. Requires scaffold_tenancy.
+	TenantScoped bool `json:"tenant_scoped,omitempty"`
+	// WithRealtime publishes server-sent events on create, update and delete, and makes the list
+	// view reload when they arrive. Requires scaffold_realtime.
+	WithRealtime bool `json:"with_realtime,omitempty"`
+	// Search selects how the list search matches records: like (default) or fulltext.
+	// Full-text search uses an SQLite FTS5 table or a Postgres tsvector column with a GIN index,
+	// ranks matches by relevance and highlights them in the list view.
+	Search string `json:"search,omitempty"`
+	// SearchFields lists the fields matched by search, in order of weight
+	// (e.g., ["Title", "Body"]). Defaults to all string fields.
+	SearchFields []string `json:"search_fields,omitempty"`
+	// WithTests generates repository tests that run CRUD, pagination and preloads against
+	// in-memory SQLite.
+	WithTests bool `json:"with_tests,omitempty"`
+	// WithHooks generates GORM hooks on the model (e.g., ["before_create", "after_update"])
+	// that dispatch domain events to handlers in the service package.
+	WithHooks []string `json:"with_hooks,omitempty"`
+	// TableName overrides the table of the model. Defaults to the plural snake_case domain
+	// name. extend_model sets it.
+	TableName string `json:"table_name,omitempty"`
+	// Strict fails the scaffold and rolls back its changes when DI wiring, menu, relationship or
+	// other injections fail or the metadata cannot be saved, instead of only logging a warning.
+	Strict bool `json:"strict,omitempty"`
 	// DryRun previews changes without writing files.
 	DryRun bool `json:"dry_run,omitempty"`
 }
@@ -99,6 +260,28 @@ func (s ScaffoldDomainInput) GetWithSoftDelete() bool {
 	return *s.WithSoftDelete
 }
 
+// GetWithBulkActions reports whether bulk actions are enabled, explicitly or by declaring custom actions.
+func (s ScaffoldDomainInput) GetWithBulkActions() bool {
+	return s.WithBulkActions || len(s.BulkActions) > 0
+}
+
+// GetOwnedByUser reports whether the domain's records belong to users: by default, when the
+// route group is authenticated.
+func (s ScaffoldDomainInput) GetOwnedByUser() bool {
+	if s.OwnedByUser == nil {
+		return s.RouteGroup == "authenticated"
+	}
+	return *s.OwnedByUser
+}
+
+// GetSearch returns the Search value with default "like".
+func (s ScaffoldDomainInput) GetSearch() string {
+	if s.Search == "" {
+		return "like"
+	}
+	return s.Search
+}
+
 // GetFormStyle returns the FormStyle value with default "modal".
 func (s ScaffoldDomainInput) GetFormStyle() string {
 	if s.FormStyle == "" {
@@ -107,6 +290,22 @@ func (s ScaffoldDomainInput) GetFormStyle() string {
 	return s.FormStyle
 }
 
+// InlineEditFields returns the names of the inline editable fields: those listed in
+// InlineEdit and those with inline_edit: true.
+func (s ScaffoldDomainInput) InlineEditFields() []string {
+	names := append([]string(nil), s.InlineEdit...)
+	listed := make(map[string]bool, len(names))
+	for _, name := range names {
+		listed[name] = true
+	}
+	for _, field := range s.Fields {
+		if field.InlineEdit && !listed[field.Name] {
+			names = append(names, field.Name)
+		}
+	}
+	return names
+}
+
 // MethodDef defines a service or repository method.
 type MethodDef struct {
 	// Name is the method name in PascalCase.
@@ -221,7 +420,8 @@ type ColumnDef struct {
 	Label string `json:"label"`
 	// Sortable enables sorting on this column.
 	Sortable bool `json:"sortable,omitempty"`
-	// Format is the display format: text, currency, date, datetime, badge, link.
+	// Format is the display format: text, currency, date, datetime, badge, link, or inline
+	// (click-to-edit, for fields in the domain's inline_edit).
 	Format string `json:"format,omitempty"`
 	// Width is the optional CSS width.
 	Width string `json:"width,omitempty"`
@@ -259,10 +459,22 @@ type ScaffoldFormInput struct {
 	SubmitEndpoint string `json:"submit_endpoint,omitempty"`
 	// ValidationRules contains field validation rules.
 	ValidationRules map[string]string `json:"validation_rules,omitempty"`
+	// Steps splits the fields into the sections of a multi-step form.
+	Steps []FormStepDef `json:"steps,omitempty"`
 	// DryRun previews changes without writing files.
 	DryRun bool `json:"dry_run,omitempty"`
 }
 
+// FormStepDef defines a step of a multi-step form.
+type FormStepDef struct {
+	// Title is the step title shown in the progress indicator.
+	Title string `json:"title"`
+	// Description is an optional line of help under the progress indicator.
+	Description string `json:"description,omitempty"`
+	// Fields are the names of the fields on the step.
+	Fields []string `json:"fields"`
+}
+
 // RowActionDef defines a table row action.
 type RowActionDef struct {
 	// Type is view, edit, delete, or custom.
@@ -345,6 +557,11 @@ type ScaffoldModalInput struct {
 	ContentType string `json:"content_type,omitempty"`
 	// TriggerConfig defines how the modal is triggered.
 	TriggerConfig TriggerConfig `json:"trigger_config,omitempty"`
+	// Domain wires the modal to a form of a scaffolded domain (e.g., "product"): the modal loads
+	// it over HTMX, shows its validation errors and closes and reloads the list once saved.
+	Domain string `json:"domain,omitempty"`
+	// Action is the domain form the modal shows: create (default) or edit. Requires domain.
+	Action string `json:"action,omitempty"`
 	// DryRun previews changes without writing files.
 	DryRun bool `json:"dry_run,omitempty"`
 }
@@ -365,7 +582,7 @@ type PropDef struct {
 type ScaffoldComponentInput struct {
 	// ComponentName is the component name.
 	ComponentName string `json:"component_name"`
-	// ComponentType is card, modal, dropdown, form_field, table, or custom.
+	// ComponentType is card, modal, form_field, wizard, tabs, accordion, dropdown, toast, badge, tooltip, or custom.
 	ComponentType string `json:"component_type,omitempty"`
 	// Props is the list of component properties.
 	Props []PropDef `json:"props,omitempty"`
@@ -397,6 +614,10 @@ type ScaffoldPageInput struct {
 	Sections []SectionDef `json:"sections,omitempty"`
 	// CreateTomlConfig generates a TOML config file.
 	CreateTomlConfig bool `json:"create_toml_config,omitempty"`
+	// Preset generates a static page with its handler, view and TOML content instead:
+	// about, pricing, contact (with a mail form) or terms. PageName defaults to the preset
+	// and Route to /{page-name}.
+	Preset string `json:"preset,omitempty"`
 	// DryRun previews changes without writing files.
 	DryRun bool `json:"dry_run,omitempty"`
 }
@@ -457,6 +678,243 @@ type ScaffoldSeedInput struct {
 	Relationships []SeedRelationshipDef `json:"relationships,omitempty"`
 	// Distributions defines value distributions for specific fields.
 	Distributions []SeedDistributionDef `json:"distributions,omitempty"`
+	// Source is a CSV or JSON fixture file relative to the project root (e.g., "fixtures/products.csv").
+	// The seeder creates or updates a record per row instead of generating data.
+	Source string `json:"source,omitempty"`
+	// Key is the field that identifies a fixture row (e.g., "Sku"), so seeding again updates
+	// the record instead of adding a duplicate. Defaults to the first unique field, or ID.
+	Key string `json:"key,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldAPIMockInput is the input for the scaffold_api_mock tool.
+type ScaffoldAPIMockInput struct {
+	// Domains limits the mock to these domains. Defaults to every domain with scaffold metadata.
+	Domains []string `json:"domains,omitempty"`
+	// Count is the default number of fake records per domain. Defaults to 20.
+	Count int `json:"count,omitempty"`
+	// Port is the default port the mock server listens on. Defaults to 8081.
+	Port int `json:"port,omitempty"`
+	// LatencyMS is the default artificial delay added to every response, in milliseconds.
+	LatencyMS int `json:"latency_ms,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldClientInput is the input for the scaffold_client tool.
+type ScaffoldClientInput struct {
+	// Domains limits the client to these domains. Defaults to every domain with scaffold metadata.
+	Domains []string `json:"domains,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldTSTypesInput is the input for the scaffold_ts_types tool.
+type ScaffoldTSTypesInput struct {
+	// Format is dts for TypeScript declarations (.d.ts) or zod for zod schemas with their
+	// inferred types (.ts). Defaults to dts.
+	Format string `json:"format,omitempty"`
+	// Domains limits the types to these domains. Defaults to every domain with scaffold metadata.
+	Domains []string `json:"domains,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldRealtimeInput is the input for the scaffold_realtime tool.
+type ScaffoldRealtimeInput struct {
+	// Domains lists the domains that publish live updates. Defaults to every domain with scaffold metadata.
+	Domains []string `json:"domains,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldSearchIndexInput is the input for the scaffold_search_index tool.
+type ScaffoldSearchIndexInput struct {
+	// Domain is the domain name (e.g., "product"). It must have scaffold metadata.
+	Domain string `json:"domain"`
+	// Engine is the default search engine: meilisearch or typesense. Defaults to meilisearch.
+	// The SEARCH_ENGINE environment variable overrides it at run time.
+	Engine string `json:"engine,omitempty"`
+	// Fields are the searchable string fields (e.g., ["Name", "Description"]).
+	// Defaults to every string field of the domain.
+	Fields []string `json:"fields,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldTenancyInput is the input for the scaffold_tenancy tool.
+type ScaffoldTenancyInput struct {
+	// Resolution is how requests name their tenant: subdomain (acme.example.com), header
+	// (X-Tenant: acme) or both, trying the subdomain first. Defaults to both.
+	Resolution string `json:"resolution,omitempty"`
+	// Header is the default tenant header. Defaults to X-Tenant; TENANT_HEADER overrides it.
+	Header string `json:"header,omitempty"`
+	// Domains are the scaffolded domains to scope by tenant (e.g., ["product", "order"]).
+	// Defaults to every domain in the scaffold metadata.
+	Domains []string `json:"domains,omitempty"`
+	// Tenants are the slugs of the tenants created by the tenant seeder (e.g., ["acme", "globex"]).
+	// Defaults to ["default"].
+	Tenants []string `json:"tenants,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldWebhooksInput is the input for the scaffold_webhooks tool.
+type ScaffoldWebhooksInput struct {
+	// Domains lists the domains whose services emit created, updated and deleted events
+	// (e.g., ["order"]). Defaults to every domain in the scaffold metadata.
+	Domains []string `json:"domains,omitempty"`
+	// Providers lists the inbound webhook receivers to generate: stripe, github, generic.
+	Providers []string `json:"providers,omitempty"`
+	// MaxAttempts is how many times a delivery is tried before it is given up. Defaults to 10.
+	MaxAttempts int `json:"max_attempts,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldSettingsInput is the input for the scaffold_settings tool.
+type ScaffoldSettingsInput struct {
+	// Groups declares the settings, one tab per group on the admin settings page.
+	// Defaults to a general group (site name, tagline, logo, support email) and a
+	// maintenance group. Groups already in config/settings.toml are kept as they are.
+	Groups []SettingsGroupDef `json:"groups,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// SettingsGroupDef is a group of application settings, shown as a tab.
+type SettingsGroupDef struct {
+	// Name is the group name in snake_case (e.g., "mail").
+	Name string `json:"name"`
+	// Label is the tab label. Defaults to the name as a label.
+	Label string `json:"label,omitempty"`
+	// Settings are the settings of the group.
+	Settings []SettingDef `json:"settings"`
+}
+
+// SettingDef is an application setting.
+type SettingDef struct {
+	// Key identifies the setting in snake_case (e.g., "smtp_host"); keys are unique across groups.
+	Key string `json:"key"`
+	// Label is the form label. Defaults to the key as a label.
+	Label string `json:"label,omitempty"`
+	// Type is one of string, text, int, float, bool, url, email. Defaults to string.
+	Type string `json:"type,omitempty"`
+	// Default is the value used until the setting is saved (e.g., "587").
+	Default string `json:"default,omitempty"`
+	// Help is shown under the form field.
+	Help string `json:"help,omitempty"`
+}
+
+// ScaffoldBlogInput is the input for the scaffold_blog tool.
+type ScaffoldBlogInput struct {
+	// Title is the title of the blog, shown on the list pages and in the feeds. Defaults to "Blog".
+	Title string `json:"title,omitempty"`
+	// Path is where the public blog is mounted. Defaults to "/blog".
+	Path string `json:"path,omitempty"`
+	// PostsPerPage is the page size of the list pages. Defaults to 10.
+	PostsPerPage int `json:"posts_per_page,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldI18nInput is the input for the scaffold_i18n tool.
+type ScaffoldI18nInput struct {
+	// Locales are the locale codes to translate into (e.g., ["en", "es", "pt-BR"]).
+	// The default locale is always included.
+	Locales []string `json:"locales"`
+	// DefaultLocale is used when a request matches no other locale. Defaults to "en".
+	DefaultLocale string `json:"default_locale,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldDeployInput is the input for the scaffold_deploy tool.
+type ScaffoldDeployInput struct {
+	// Target is the deployment target: "docker" (Dockerfile, docker-compose.yml and .dockerignore),
+	// "kubernetes" (a kustomize base and overlays in deploy/kubernetes) or "systemd" (a unit,
+	// environment file, logrotate config and install script in deploy/systemd). Defaults to "docker".
+	Target string `json:"target,omitempty"`
+	// DatabaseType overrides the database of the project (sqlite, postgres, mysql).
+	// Defaults to the database the project was scaffolded with.
+	DatabaseType string `json:"database_type,omitempty"`
+	// WithRedis adds a Redis service. Always on when the project has the Redis cache.
+	WithRedis bool `json:"with_redis,omitempty"`
+	// Host is the public host name of the kubernetes Ingress (e.g., "shop.example.com").
+	// Environments other than production are served from {environment}.{host}.
+	// Defaults to {project}.example.com.
+	Host string `json:"host,omitempty"`
+	// Environments are the kubernetes overlays to generate. Defaults to staging and production.
+	Environments []string `json:"environments,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldHealthInput is the input for the scaffold_health tool.
+type ScaffoldHealthInput struct {
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldTestsInput is the input for the scaffold_tests tool.
+type ScaffoldTestsInput struct {
+	// Domain is the domain name (e.g., "product"). It must have scaffold metadata.
+	Domain string `json:"domain"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldFactoryInput is the input for the scaffold_factory tool.
+type ScaffoldFactoryInput struct {
+	// Domain is the domain name (e.g., "product"). It must have scaffold metadata.
+	Domain string `json:"domain"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ScaffoldCIInput is the input for the scaffold_ci tool.
+type ScaffoldCIInput struct {
+	// Provider is the CI service: "github" (GitHub Actions). Defaults to "github".
+	Provider string `json:"provider,omitempty"`
+	// DatabaseType overrides the database the tests run against (sqlite, postgres, mysql).
+	// Defaults to the database the project was scaffolded with.
+	DatabaseType string `json:"database_type,omitempty"`
+	// WithRedis adds a Redis service container. Always on when the project has the Redis cache.
+	WithRedis bool `json:"with_redis,omitempty"`
+	// WithRelease adds release tooling: .goreleaser.yaml, a release workflow run on v* tags,
+	// build info variables in the config package and release tasks in Taskfile.yml.
+	WithRelease bool `json:"with_release,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ExportDomainInput is the input for the export_domain tool.
+type ExportDomainInput struct {
+	// Domain is the domain name (e.g., "product"). It must have scaffold metadata.
+	Domain string `json:"domain"`
+	// ProjectPath is the project to export from, relative to the server's working directory.
+	// Defaults to the working directory itself.
+	ProjectPath string `json:"project_path,omitempty"`
+	// OutputPath is the file to write the export to, relative to the server's working directory
+	// (e.g., "domain-library/product.json"). The export is always returned in the result as well.
+	OutputPath string `json:"output_path,omitempty"`
+	// WithExtensions includes the custom code added with extend_repository, extend_service and
+	// extend_controller, and the sort columns added with scaffold_table.
+	WithExtensions bool `json:"with_extensions,omitempty"`
+	// WithWizards includes the wizards scaffolded for the domain.
+	WithWizards bool `json:"with_wizards,omitempty"`
+}
+
+// ImportDomainInput is the input for the import_domain tool.
+type ImportDomainInput struct {
+	// Path is a file written by export_domain, relative to the server's working directory.
+	Path string `json:"path,omitempty"`
+	// Export is an export returned by export_domain. Used when Path is empty.
+	Export *DomainExport `json:"export,omitempty"`
+	// ProjectPath is the project to import into, relative to the server's working directory.
+	// Defaults to the working directory itself.
+	ProjectPath string `json:"project_path,omitempty"`
 	// DryRun previews changes without writing files.
 	DryRun bool `json:"dry_run,omitempty"`
 }
@@ -510,6 +968,30 @@ type ExtendServiceInput struct {
 	DryRun bool `json:"dry_run,omitempty"`
 }
 
+// ExtendModelInput is the input for the extend_model tool.
+type ExtendModelInput struct {
+	// Domain is the domain name (e.g., "order").
+	Domain string `json:"domain"`
+	// Hooks is the list of GORM hooks to add (e.g., BeforeCreate).
+	Hooks []ModelHookDef `json:"hooks,omitempty"`
+	// Methods is the list of methods to add to the model (e.g., DisplayName).
+	Methods []ExtendMethodDef `json:"methods,omitempty"`
+	// TableName overrides the table of the model.
+	TableName string `json:"table_name,omitempty"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ModelHookDef defines a GORM hook to add to a model.
+type ModelHookDef struct {
+	// Name is the hook name: BeforeSave, BeforeCreate, AfterCreate, BeforeUpdate, AfterUpdate,
+	// AfterSave, BeforeDelete, AfterDelete or AfterFind.
+	Name string `json:"name"`
+	// Body is the hook implementation, with the model as m and the transaction as tx.
+	// Use [[.ModelName]], [[.VariableName]], etc. for templating.
+	Body string `json:"body,omitempty"`
+}
+
 // ExtendControllerInput is the input for the extend_controller tool.
 type ExtendControllerInput struct {
 	// Domain is the domain name (e.g., "order").
@@ -563,18 +1045,145 @@ type ScaffoldServiceForRepoInput struct {
 	DryRun bool `json:"dry_run,omitempty"`
 }
 
+// RefactorExtractServiceInput is the input for the refactor_extract_service tool.
+type RefactorExtractServiceInput struct {
+	// Domain is the domain whose service methods are moved (e.g., "order").
+	Domain string `json:"domain"`
+	// NewService is the name of the new service (e.g., "order_pricing"). This becomes the package name.
+	NewService string `json:"new_service"`
+	// Methods is the list of service methods to move (e.g., ["CalculateTotal"]).
+	Methods []string `json:"methods"`
+	// DryRun previews changes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// RenderPreviewInput is the input for the render_preview tool.
+type RenderPreviewInput struct {
+	// Template is a template path (e.g., "domain/model.go.tmpl") or a template directory
+	// ("domain", "views", "wizard") to render every template scaffolding would use.
+	Template string `json:"template"`
+	// Domain is the scaffold_domain input used to render domain/ and views/ templates.
+	Domain *ScaffoldDomainInput `json:"domain,omitempty"`
+	// Wizard is the scaffold_wizard input used to render wizard/ templates.
+	Wizard *ScaffoldWizardInput `json:"wizard,omitempty"`
+	// Step is the 1-based wizard step rendered by wizard/step_* templates. Defaults to 1.
+	Step int `json:"step,omitempty"`
+}
+
+// UpgradeStackInput is the input for the upgrade_stack tool.
+type UpgradeStackInput struct {
+	// Components limits the upgrade to these components: templ, htmx, alpine, tailwind, unpoly.
+	// If empty, upgrades all components.
+	Components []string `json:"components,omitempty"`
+	// DryRun previews the diffs without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// UpgradeProjectInput is the input for the upgrade_project tool.
+type UpgradeProjectInput struct {
+	// From is the scaffolder version whose templates the project was generated with (e.g., "0.1.0").
+	// If empty, it is read from .mcp/scaffold-metadata.json.
+	From string `json:"from,omitempty"`
+	// DryRun previews the migrations without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// LintMarkersInput is the input for the lint_markers tool.
+type LintMarkersInput struct {
+	// Fix repairs the problems that can be fixed safely.
+	Fix bool `json:"fix,omitempty"`
+	// DryRun previews the fixes without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// CheckTranslationsInput is the input for the check_translations tool.
+type CheckTranslationsInput struct {
+	// Locales limits the report to these locales. Defaults to every locale under config/.
+	Locales []string `json:"locales,omitempty"`
+	// Fix stubs the missing keys into the messages files of each locale.
+	Fix bool `json:"fix,omitempty"`
+	// DryRun previews the stubs without writing files.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ManageNavInput is the input for the manage_nav tool.
+type ManageNavInput struct {
+	// Action is list, add, remove or reorder. Defaults to list.
+	Action string `json:"action,omitempty"`
+	// Section is the menu section: main, admin, account, site or footer. Defaults to main;
+	// list shows every section when empty.
+	Section string `json:"section,omitempty"`
+	// URL identifies the link to add, update or remove (e.g., "/reports").
+	URL string `json:"url,omitempty"`
+	// Label is the link text. Required when adding a link.
+	Label string `json:"label,omitempty"`
+	// Icon is the sidebar icon: a name of the project's heroicons or lucide set in
+	// internal/web/components/icons.go (e.g., "folder", "chart-bar", "shopping-cart").
+	Icon string `json:"icon,omitempty"`
+	// Badge is a short text shown next to the sidebar label (e.g., "New").
+	Badge string `json:"badge,omitempty"`
+	// Group is the sidebar heading the link is listed under (e.g., "Reports").
+	Group string `json:"group,omitempty"`
+	// Position moves the added link to this place in its section, 1 for the top.
+	// Defaults to the end for new links and the current place for existing ones.
+	Position int `json:"position,omitempty"`
+	// URLs is the new order of the section's links for reorder; links not listed follow
+	// in their current order.
+	URLs []string `json:"urls,omitempty"`
+	// DryRun previews the change without writing menu.toml.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// JobStepDef is one tool call of a scaffold job.
+type JobStepDef struct {
+	// Tool is the scaffolding tool to run (e.g., "scaffold_domain").
+	Tool string `json:"tool"`
+	// Input is the tool input, as it would be passed to the tool directly.
+	Input map[string]any `json:"input,omitempty"`
+}
+
+// SubmitScaffoldJobInput is the input for the submit_scaffold_job tool.
+type SubmitScaffoldJobInput struct {
+	// Steps are the tool calls to run, in order.
+	Steps []JobStepDef `json:"steps"`
+	// ContinueOnError runs the remaining steps after a step fails instead of skipping them.
+	ContinueOnError bool `json:"continue_on_error,omitempty"`
+}
+
+// GetJobStatusInput is the input for the get_job_status tool.
+type GetJobStatusInput struct {
+	// JobID is the ID returned by submit_scaffold_job.
+	JobID string `json:"job_id"`
+}
+
 // AnalyzeDomainInput is the input for the analyze_domain tool.
 type AnalyzeDomainInput struct {
 	// Domain is the domain name to analyze (e.g., "order").
 	// If empty, analyzes all domains with metadata.
 	Domain string `json:"domain,omitempty"`
-	// Layers filters which layers to analyze: model, repository, service, controller, views.
+	// Layers filters which layers to analyze: model, repository, service, controller, views,
+	// types (the TypeScript types of scaffold_ts_types).
 	// If empty, analyzes all layers.
 	Layers []string `json:"layers,omitempty"`
 	// ShowUnchanged includes files with no differences in the output.
 	ShowUnchanged bool `json:"show_unchanged,omitempty"`
 }
 
+// SyncDomainInput is the input for the sync_domain tool.
+type SyncDomainInput struct {
+	// Domain is the domain to regenerate from its scaffold metadata (e.g., "order").
+	Domain string `json:"domain"`
+	// Files are the files to update, as analyze_domain lists them.
+	// If empty, every changed or missing file of the domain is updated.
+	Files []string `json:"files,omitempty"`
+	// Hunks maps a file to the numbers of the hunks to apply, as in the "@@ ... @@ hunk N"
+	// headers of analyze_domain. A file listed here gets only those hunks; the rest of it
+	// keeps its current content.
+	Hunks map[string][]int `json:"hunks,omitempty"`
+	// DryRun previews the files that would be updated without writing them.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
 // AnalyzeDomainResult is the output from the analyze_domain tool.
 type AnalyzeDomainResult struct {
 	Success       bool                  `json:"success"`
@@ -600,6 +1209,93 @@ type FileAnalysis struct {
 	Diff         string `json:"diff,omitempty"`
 	LinesAdded   int    `json:"lines_added,omitempty"`
 	LinesRemoved int    `json:"lines_removed,omitempty"`
+	// Hunks is the number of hunks in Diff, which sync_domain can apply one by one.
+	Hunks int `json:"hunks,omitempty"`
+	// Changes explains the diff: the template changelog entries since the file was scaffolded,
+	// e.g. "0.2.0: Unchecked checkboxes post false through a hidden field".
+	Changes []string `json:"changes,omitempty"`
+}
+
+// TemplateChangesInput is the input for the template_changes tool.
+type TemplateChangesInput struct {
+	// Since lists only the changes of scaffolder versions after this one (e.g., "0.1.0").
+	Since string `json:"since,omitempty"`
+	// Template lists only the changes to this template (e.g., "views/form.templ.tmpl").
+	Template string `json:"template,omitempty"`
+}
+
+// TemplateChange is one entry of the template changelog.
+type TemplateChange struct {
+	// Version is the scaffolder version that shipped the change.
+	Version string `json:"version"`
+	// Templates are the changed templates; a pattern such as "api/*" covers a directory.
+	Templates []string `json:"templates"`
+	// Summary says what changed and why.
+	Summary string `json:"summary"`
+}
+
+// TemplateChangesResult is the output from the template_changes tool.
+type TemplateChangesResult struct {
+	Success        bool             `json:"success"`
+	Message        string           `json:"message"`
+	CurrentVersion string           `json:"current_version,omitempty"`
+	Changes        []TemplateChange `json:"changes,omitempty"`
+}
+
+// AnalyzeProjectInput is the input for the analyze_project tool.
+type AnalyzeProjectInput struct {
+	// Files limits the analysis to these project files (e.g., "cmd/web/main.go").
+	// If empty, every compared project file is analyzed.
+	Files []string `json:"files,omitempty"`
+	// ShowUnchanged includes files with no differences in the output.
+	ShowUnchanged bool `json:"show_unchanged,omitempty"`
+}
+
+// AnalyzeProjectResult is the output from the analyze_project tool.
+type AnalyzeProjectResult struct {
+	Success           bool           `json:"success"`
+	Message           string         `json:"message"`
+	ScaffoldedAt      string         `json:"scaffolded_at,omitempty"`
+	ScaffolderVersion string         `json:"scaffolder_version,omitempty"`
+	CurrentVersion    string         `json:"current_version,omitempty"`
+	HasChanges        bool           `json:"has_changes"`
+	Files             []FileAnalysis `json:"files,omitempty"`
+}
+
+// ReconstructMetadataInput is the input for the reconstruct_metadata tool.
+type ReconstructMetadataInput struct {
+	// Domains lists the domains to reconstruct (e.g., ["order_item"]).
+	// If empty, every domain with a model and a controller but no metadata is reconstructed.
+	Domains []string `json:"domains,omitempty"`
+	// Overwrite replaces the metadata of domains that already have it.
+	Overwrite bool `json:"overwrite,omitempty"`
+	// DryRun reconstructs the inputs without saving them.
+	DryRun bool `json:"dry_run,omitempty"`
+}
+
+// ReconstructMetadataResult is the output from the reconstruct_metadata tool.
+type ReconstructMetadataResult struct {
+	Success bool                  `json:"success"`
+	Message string                `json:"message"`
+	DryRun  bool                  `json:"dry_run,omitempty"`
+	Domains []ReconstructedDomain `json:"domains,omitempty"`
+	// Skipped lists the domains left alone, with the reason.
+	Skipped []string `json:"skipped,omitempty"`
+}
+
+// ReconstructedDomain is the scaffold input rebuilt for a domain from its code.
+type ReconstructedDomain struct {
+	Domain string              `json:"domain"`
+	Input  ScaffoldDomainInput `json:"input"`
+	// FilesMatching are the domain files the input regenerates exactly.
+	FilesMatching []string `json:"files_matching,omitempty"`
+	// FilesDiffering are the domain files the input regenerates differently, because they were
+	// edited or the templates changed since. analyze_domain shows the differences.
+	FilesDiffering []string `json:"files_differing,omitempty"`
+	// Notes lists what the code shows but the input could not capture, to add by hand.
+	Notes []string `json:"notes,omitempty"`
+	// Saved indicates the input was recorded in .mcp/scaffold-metadata.json.
+	Saved bool `json:"saved,omitempty"`
 }
 
 // WizardStepDef defines a step in a multi-step wizard.
@@ -618,6 +1314,9 @@ type WizardStepDef struct {
 	Searchable bool `json:"searchable,omitempty"`
 	// ValidationRules contains per-field validation rules.
 	ValidationRules map[string]string `json:"validation_rules,omitempty"`
+	// Condition shows the step only when the answer to a field of an earlier form step
+	// matches, e.g. delivery == "ship". Other steps always show.
+	Condition string `json:"condition,omitempty"`
 }
 
 // ScaffoldWizardInput is the input for the scaffold_wizard tool.
@@ -638,6 +1337,11 @@ type ScaffoldWizardInput struct {
 	SuccessRedirect string `json:"success_redirect,omitempty"`
 	// WithDrafts enables database draft persistence for wizard progress. Defaults to true.
 	WithDrafts *bool `json:"with_drafts,omitempty"`
+	// WithTests generates an httptest integration test that walks every step against in-memory SQLite.
+	WithTests bool `json:"with_tests,omitempty"`
+	// Strict fails the scaffold and rolls back its changes when the DI wiring cannot be injected
+	// or the metadata cannot be saved, instead of only logging a warning.
+	Strict bool `json:"strict,omitempty"`
 	// DryRun previews changes without writing files.
 	DryRun bool `json:"dry_run,omitempty"`
 }
@@ -3,6 +3,7 @@ package types
 import (
 	"fmt"
 	"strings"
+	"time"
 )
 
 // FileConflict represents a file that would be overwritten.
@@ -37,6 +38,8 @@ type ScaffoldResult struct {
 	FilesCreated []string `json:"files_created,omitempty"`
 	// FilesUpdated is the list of files that were updated.
 	FilesUpdated []string `json:"files_updated,omitempty"`
+	// Backups maps the updated files to the path of their previous version under .mcp/backups.
+	Backups map[string]string `json:"backups,omitempty"`
 	// NextSteps is the list of suggested next actions (shell commands).
 	NextSteps []string `json:"next_steps,omitempty"`
 	// SuggestedTools hints at which MCP tools to call next.
@@ -228,6 +231,11 @@ type DomainInfo struct {
 	HasController bool `json:"has_controller"`
 	// Views is the list of view files.
 	Views []string `json:"views,omitempty"`
+	// HasMetadata indicates the domain is recorded in .mcp/scaffold-metadata.json.
+	HasMetadata bool `json:"has_metadata"`
+	// Unmanaged indicates a domain with a model and a controller but no metadata, such as one
+	// scaffolded before metadata tracking: analyze_domain and sync_domain can't see it.
+	Unmanaged bool `json:"unmanaged,omitempty"`
 }
 
 // ListDomainsResult is the result of the list_domains tool.
@@ -238,6 +246,8 @@ type ListDomainsResult struct {
 	Message string `json:"message,omitempty"`
 	// Domains is the list of domain info.
 	Domains []DomainInfo `json:"domains,omitempty"`
+	// SuggestedTool suggests reconstruct_metadata when there are unmanaged domains.
+	SuggestedTool *ToolHint `json:"suggested_tool,omitempty"`
 }
 
 // NewListDomainsResult creates a successful list domains result.
@@ -256,6 +266,382 @@ func NewListDomainsError(message string) ListDomainsResult {
 	}
 }
 
+// DomainExport is a portable copy of a scaffolded domain. It is written by export_domain
+// and re-created in another project by import_domain.
+type DomainExport struct {
+	// Version is the export format version.
+	Version string `json:"version"`
+	// ScaffolderVersion is the scaffolder version that created the domain.
+	ScaffolderVersion string `json:"scaffolder_version"`
+	// ExportedAt is when the export was made.
+	ExportedAt time.Time `json:"exported_at"`
+	// Domain is the scaffold_domain input recorded in the project metadata.
+	Domain ScaffoldDomainInput `json:"domain"`
+	// Wizards are the scaffold_wizard inputs for the domain's wizards.
+	Wizards []ScaffoldWizardInput `json:"wizards,omitempty"`
+	// Extensions is the custom code found between the domain's injection markers.
+	Extensions []DomainExtension `json:"extensions,omitempty"`
+}
+
+// DomainExtension is custom code between a pair of injection markers in a domain file.
+type DomainExtension struct {
+	// Layer is the file the code belongs to: repository, service, or controller.
+	Layer string `json:"layer"`
+	// Section is the marker name without prefix and suffix (e.g., "REPO_METHODS"
+	// for MCP:REPO_METHODS:START and MCP:REPO_METHODS:END).
+	Section string `json:"section"`
+	// Code is the code between the markers.
+	Code string `json:"code"`
+}
+
+// ExportDomainResult is the result of the export_domain tool.
+type ExportDomainResult struct {
+	// Success indicates if the operation succeeded.
+	Success bool `json:"success"`
+	// Message describes the result.
+	Message string `json:"message,omitempty"`
+	// FilesCreated lists the export file, if one was written.
+	FilesCreated []string `json:"files_created,omitempty"`
+	// Export is the exported domain. Pass it to import_domain as-is.
+	Export *DomainExport `json:"export,omitempty"`
+}
+
+// NewExportDomainError creates an error export domain result.
+func NewExportDomainError(message string) ExportDomainResult {
+	return ExportDomainResult{
+		Success: false,
+		Message: message,
+	}
+}
+
+// RouteInfo describes an HTTP route registered by a scaffolded controller.
+type RouteInfo struct {
+	// Domain is the domain package that owns the route (e.g., "product").
+	Domain string `json:"domain"`
+	// Method is the HTTP method (GET, POST, PUT, PATCH, DELETE).
+	Method string `json:"method"`
+	// Path is the full route path including the mount path (e.g., "/products/{id}").
+	Path string `json:"path"`
+	// Handler is the controller method that handles the route (e.g., "Show").
+	Handler string `json:"handler"`
+	// RouteGroup is the middleware context: public, authenticated, or admin.
+	// Empty if the controller is not mounted in main.go.
+	RouteGroup string `json:"route_group,omitempty"`
+}
+
+// TemplateRender is the output of rendering a single template.
+type TemplateRender struct {
+	// Template is the template path (e.g., "domain/model.go.tmpl").
+	Template string `json:"template"`
+	// Path is the file scaffold_domain would write the output to, if any.
+	Path string `json:"path,omitempty"`
+	// Step is the wizard step the template was rendered for, if any.
+	Step int `json:"step,omitempty"`
+	// Output is the rendered content.
+	Output string `json:"output"`
+}
+
+// RenderPreviewResult is the result of the render_preview tool.
+type RenderPreviewResult struct {
+	// Success indicates if the operation succeeded.
+	Success bool `json:"success"`
+	// Message describes the result.
+	Message string `json:"message,omitempty"`
+	// Renders is the rendered output, one entry per template.
+	Renders []TemplateRender `json:"renders,omitempty"`
+}
+
+// NewRenderPreviewResult creates a successful render preview result.
+func NewRenderPreviewResult(message string, renders []TemplateRender) RenderPreviewResult {
+	return RenderPreviewResult{
+		Success: true,
+		Message: message,
+		Renders: renders,
+	}
+}
+
+// NewRenderPreviewError creates an error render preview result.
+func NewRenderPreviewError(message string) RenderPreviewResult {
+	return RenderPreviewResult{
+		Success: false,
+		Message: message,
+	}
+}
+
+// StackUpgrade describes one version reference changed by upgrade_stack.
+type StackUpgrade struct {
+	// Component is the stack component (templ, htmx, alpine, tailwind, unpoly).
+	Component string `json:"component"`
+	// File is the file containing the reference, relative to the project root.
+	File string `json:"file"`
+	// From is the referenced version, or "unpinned" when the reference had no exact version.
+	From string `json:"from"`
+	// To is the bundled version the reference was pinned to.
+	To string `json:"to"`
+}
+
+// UpgradeStackResult is the result of the upgrade_stack tool.
+type UpgradeStackResult struct {
+	// Success indicates if the operation succeeded.
+	Success bool `json:"success"`
+	// Message describes the result.
+	Message string `json:"message"`
+	// DryRun indicates no files were written.
+	DryRun bool `json:"dry_run,omitempty"`
+	// Upgrades lists every version reference that changed.
+	Upgrades []StackUpgrade `json:"upgrades,omitempty"`
+	// Files contains a unified diff per changed file.
+	Files []FileAnalysis `json:"files,omitempty"`
+	// BackupDir is the directory that keeps the previous version of the changed files.
+	BackupDir string `json:"backup_dir,omitempty"`
+	// Notes are compatibility notes for the upgraded components.
+	Notes []string `json:"notes,omitempty"`
+	// NextSteps are commands to run after the upgrade.
+	NextSteps []string `json:"next_steps,omitempty"`
+}
+
+// NewUpgradeStackError creates an error upgrade stack result.
+func NewUpgradeStackError(message string) UpgradeStackResult {
+	return UpgradeStackResult{
+		Success: false,
+		Message: message,
+	}
+}
+
+// UpgradeProjectResult is the result of the upgrade_project tool.
+type UpgradeProjectResult struct {
+	// Success indicates if the operation succeeded.
+	Success bool `json:"success"`
+	// Message describes the result.
+	Message string `json:"message"`
+	// DryRun indicates no files were written.
+	DryRun bool `json:"dry_run,omitempty"`
+	// From is the scaffolder version the project followed before the upgrade.
+	From string `json:"from,omitempty"`
+	// To is the scaffolder version the project follows after the upgrade.
+	To string `json:"to,omitempty"`
+	// Migrations lists the migrations that were applied, oldest first.
+	Migrations []AppliedMigration `json:"migrations,omitempty"`
+	// FilesUpdated is the list of files that were changed, with the path of their backup.
+	FilesUpdated []string `json:"files_updated,omitempty"`
+	// FilesMoved lists the moved files as "old -> new".
+	FilesMoved []string `json:"files_moved,omitempty"`
+	// BackupDir is the directory that keeps the previous version of the changed and moved files.
+	BackupDir string `json:"backup_dir,omitempty"`
+	// NextSteps are commands to run after the upgrade.
+	NextSteps []string `json:"next_steps,omitempty"`
+}
+
+// AppliedMigration is one template migration applied by upgrade_project.
+type AppliedMigration struct {
+	// Version is the scaffolder version that introduced the template layout.
+	Version string `json:"version"`
+	// Description says what changed in the layout.
+	Description string `json:"description"`
+	// Changes lists what each step changed in the project.
+	Changes []string `json:"changes,omitempty"`
+}
+
+// NewUpgradeProjectError creates an error upgrade project result.
+func NewUpgradeProjectError(message string) UpgradeProjectResult {
+	return UpgradeProjectResult{
+		Success: false,
+		Message: message,
+	}
+}
+
+// MarkerIssue describes one MCP marker problem found by lint_markers.
+type MarkerIssue struct {
+	// File is the file containing the marker, relative to the project root.
+	File string `json:"file"`
+	// Line is the 1-based line of the marker.
+	Line int `json:"line"`
+	// Marker is the marker as it should read (e.g., "MCP:ROUTES:START").
+	Marker string `json:"marker"`
+	// Kind is malformed, duplicate, orphan_start, orphan_end, overlapping or in_string.
+	Kind string `json:"kind"`
+	// Message describes the problem.
+	Message string `json:"message"`
+	// Fixable indicates lint_markers can fix the problem with fix: true.
+	Fixable bool `json:"fixable"`
+	// Fixed indicates the problem was fixed (or would be, in a dry run).
+	Fixed bool `json:"fixed,omitempty"`
+}
+
+// LintMarkersResult is the result of the lint_markers tool.
+type LintMarkersResult struct {
+	// Success indicates if the operation succeeded.
+	Success bool `json:"success"`
+	// Message describes the result.
+	Message string `json:"message"`
+	// DryRun indicates no files were written.
+	DryRun bool `json:"dry_run,omitempty"`
+	// Issues lists every marker problem found.
+	Issues []MarkerIssue `json:"issues,omitempty"`
+	// Files contains a unified diff per fixed file.
+	Files []FileAnalysis `json:"files,omitempty"`
+	// NextSteps are suggested actions for the remaining problems.
+	NextSteps []string `json:"next_steps,omitempty"`
+}
+
+// NewLintMarkersError creates an error lint markers result.
+func NewLintMarkersError(message string) LintMarkersResult {
+	return LintMarkersResult{
+		Success: false,
+		Message: message,
+	}
+}
+
+// LocaleTranslations is the translation report of one locale.
+type LocaleTranslations struct {
+	// Locale is the locale code (e.g., "es").
+	Locale string `json:"locale"`
+	// Keys is the number of keys the locale defines.
+	Keys int `json:"keys"`
+	// Missing lists the keys used by the code or defined by the default locale that the
+	// locale does not define.
+	Missing []string `json:"missing,omitempty"`
+	// Unused lists the keys the locale defines that no view or Go file uses.
+	Unused []string `json:"unused,omitempty"`
+	// Stubbed is the number of missing keys written to the locale's files (or that would be, in a dry run).
+	Stubbed int `json:"stubbed,omitempty"`
+}
+
+// CheckTranslationsResult is the result of the check_translations tool.
+type CheckTranslationsResult struct {
+	// Success indicates if the operation succeeded.
+	Success bool `json:"success"`
+	// Message describes the result.
+	Message string `json:"message"`
+	// DryRun indicates no files were written.
+	DryRun bool `json:"dry_run,omitempty"`
+	// DefaultLocale is the locale the others fall back to.
+	DefaultLocale string `json:"default_locale,omitempty"`
+	// UsedKeys is the number of distinct keys used by the views and Go code.
+	UsedKeys int `json:"used_keys"`
+	// Locales contains the report of each locale, the default first.
+	Locales []LocaleTranslations `json:"locales,omitempty"`
+	// Files contains a unified diff per stubbed messages file.
+	Files []FileAnalysis `json:"files,omitempty"`
+	// NextSteps are suggested actions for the missing and unused keys.
+	NextSteps []string `json:"next_steps,omitempty"`
+}
+
+// NewCheckTranslationsError creates an error check translations result.
+func NewCheckTranslationsError(message string) CheckTranslationsResult {
+	return CheckTranslationsResult{
+		Success: false,
+		Message: message,
+	}
+}
+
+// NavItem is a link of config/en/menu.toml.
+type NavItem struct {
+	Label string `json:"label"`
+	URL   string `json:"url"`
+	Icon  string `json:"icon,omitempty"`
+	Badge string `json:"badge,omitempty"`
+	Group string `json:"group,omitempty"`
+	Order int    `json:"order"`
+}
+
+// NavSection is a section of config/en/menu.toml and its links, sorted by order.
+type NavSection struct {
+	// Section is main, admin, account, site or footer.
+	Section string    `json:"section"`
+	Items   []NavItem `json:"items"`
+}
+
+// ManageNavResult is the result of the manage_nav tool.
+type ManageNavResult struct {
+	// Success indicates if the operation succeeded.
+	Success bool `json:"success"`
+	// Message describes the result.
+	Message string `json:"message"`
+	// DryRun indicates menu.toml was not written.
+	DryRun bool `json:"dry_run,omitempty"`
+	// Sections contains the menu after the change.
+	Sections []NavSection `json:"sections,omitempty"`
+	// Files contains the unified diff of menu.toml.
+	Files []FileAnalysis `json:"files,omitempty"`
+	// BackupDir is the directory that keeps the previous version of menu.toml.
+	BackupDir string `json:"backup_dir,omitempty"`
+	// NextSteps are suggested actions after the change.
+	NextSteps []string `json:"next_steps,omitempty"`
+}
+
+// NewManageNavError creates an error manage nav result.
+func NewManageNavError(message string) ManageNavResult {
+	return ManageNavResult{
+		Success: false,
+		Message: message,
+	}
+}
+
+// JobStepResult is the outcome of one step of a scaffold job.
+type JobStepResult struct {
+	// Tool is the tool the step runs.
+	Tool string `json:"tool"`
+	// Status is pending, running, succeeded, failed or skipped.
+	Status string `json:"status"`
+	// Message is the tool result message, or why the step was skipped.
+	Message string `json:"message,omitempty"`
+	// FilesCreated is the list of files the step created.
+	FilesCreated []string `json:"files_created,omitempty"`
+	// FilesUpdated is the list of files the step updated.
+	FilesUpdated []string `json:"files_updated,omitempty"`
+	// Backups maps the files the step updated to the path of their previous version.
+	Backups map[string]string `json:"backups,omitempty"`
+	// Conflicts is the list of files the step refused to overwrite.
+	Conflicts []FileConflict `json:"conflicts,omitempty"`
+	// DurationMS is how long the step ran, in milliseconds.
+	DurationMS int64 `json:"duration_ms,omitempty"`
+}
+
+// JobReport summarizes a finished scaffold job.
+type JobReport struct {
+	// Succeeded is the number of steps that succeeded.
+	Succeeded int `json:"succeeded"`
+	// Failed is the number of steps that failed.
+	Failed int `json:"failed"`
+	// Skipped is the number of steps skipped after a failure.
+	Skipped int `json:"skipped"`
+	// FilesCreated lists the files created by all steps, without duplicates.
+	FilesCreated []string `json:"files_created,omitempty"`
+	// FilesUpdated lists the files updated by all steps, without duplicates.
+	FilesUpdated []string `json:"files_updated,omitempty"`
+	// NextSteps are the suggested commands of all steps, without duplicates.
+	NextSteps []string `json:"next_steps,omitempty"`
+}
+
+// ScaffoldJobResult is the result of the submit_scaffold_job and get_job_status tools.
+type ScaffoldJobResult struct {
+	// Success indicates if the request succeeded; a failed job is still reported successfully.
+	Success bool `json:"success"`
+	// Message describes the result.
+	Message string `json:"message"`
+	// JobID identifies the job in get_job_status.
+	JobID string `json:"job_id,omitempty"`
+	// Status is queued, running, succeeded or failed.
+	Status string `json:"status,omitempty"`
+	// Completed is the number of finished steps (succeeded, failed or skipped).
+	Completed int `json:"completed"`
+	// Total is the number of steps.
+	Total int `json:"total"`
+	// Steps holds the status and result of each step, in order.
+	Steps []JobStepResult `json:"steps,omitempty"`
+	// Report summarizes the job once it has finished.
+	Report *JobReport `json:"report,omitempty"`
+}
+
+// NewScaffoldJobError creates an error scaffold job result.
+func NewScaffoldJobError(message string) ScaffoldJobResult {
+	return ScaffoldJobResult{
+		Success: false,
+		Message: message,
+	}
+}
+
 // ReportBugResult is the result of the report_bug tool.
 type ReportBugResult struct {
 	// Success indicates if the bug was reported successfully.
@@ -208,7 +208,8 @@ func IsEmpty(dir string) (bool, error) {
 	return len(entries) == 0, nil
 }
 
-// FindProjectRoot finds the project root by looking for go.mod.
+// FindProjectRoot finds the project root by looking for go.mod. The search stops at the
+// root of a git repository, so a go.mod outside the repository is never used.
 func FindProjectRoot(startPath string) (string, error) {
 	absPath, err := AbsolutePath(startPath)
 	if err != nil {
@@ -221,6 +222,9 @@ func FindProjectRoot(startPath string) (string, error) {
 		if FileExists(goMod) {
 			return current, nil
 		}
+		if DirExists(filepath.Join(current, ".git")) {
+			return "", fmt.Errorf("could not find go.mod in repository %s", current)
+		}
 
 		parent := filepath.Dir(current)
 		if parent == current {
@@ -231,23 +235,43 @@ func FindProjectRoot(startPath string) (string, error) {
 	}
 }
 
-// GetModulePath reads the module path from go.mod.
+// GetModulePath returns the import path of projectRoot, read from go.mod. A project
+// without a go.mod of its own, such as apps/web of a monorepo with one go.mod at its
+// root, is a package of the nearest module above it: github.com/acme/mono/apps/web.
 func GetModulePath(projectRoot string) (string, error) {
-	goModPath := filepath.Join(projectRoot, "go.mod")
+	moduleRoot, err := FindProjectRoot(projectRoot)
+	if err != nil {
+		// Report the missing go.mod of the project itself
+		moduleRoot = projectRoot
+	}
+
+	goModPath := filepath.Join(moduleRoot, "go.mod")
 	content, err := ReadFileString(goModPath)
 	if err != nil {
 		return "", err
 	}
 
+	modulePath := ""
 	lines := strings.Split(content, "\n")
 	for _, line := range lines {
 		line = strings.TrimSpace(line)
 		if strings.HasPrefix(line, "module ") {
-			return strings.TrimPrefix(line, "module "), nil
+			modulePath = strings.TrimSpace(strings.TrimPrefix(line, "module "))
+			break
 		}
 	}
+	if modulePath == "" {
+		return "", fmt.Errorf("module path not found in go.mod")
+	}
 
-	return "", fmt.Errorf("module path not found in go.mod")
+	absRoot, err := AbsolutePath(projectRoot)
+	if err != nil {
+		return "", err
+	}
+	if rel, err := filepath.Rel(moduleRoot, absRoot); err == nil && rel != "." {
+		modulePath += "/" + filepath.ToSlash(rel)
+	}
+	return modulePath, nil
 }
 
 // CreateTempDir creates a temporary directory with a prefix.
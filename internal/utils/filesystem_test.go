@@ -499,6 +499,34 @@ func TestGetModulePath(t *testing.T) {
 	}
 }
 
+func TestGetModulePath_Subdirectory(t *testing.T) {
+	repo := t.TempDir()
+	if err := os.Mkdir(filepath.Join(repo, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repo, "go.mod"), []byte("module github.com/acme/mono\n\ngo 1.21\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	web := filepath.Join(repo, "apps", "web")
+	if err := os.MkdirAll(web, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := GetModulePath(web)
+	if err != nil || got != "github.com/acme/mono/apps/web" {
+		t.Errorf("GetModulePath() = %q, %v, want the package of the repository module", got, err)
+	}
+
+	// A go.mod outside the git repository is not used
+	nested := filepath.Join(repo, "vendor-repo")
+	if err := os.MkdirAll(filepath.Join(nested, ".git"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := GetModulePath(nested); err == nil {
+		t.Errorf("GetModulePath() = %q, want an error outside the module's repository", got)
+	}
+}
+
 func TestCreateTempDir(t *testing.T) {
 	dir, err := CreateTempDir("test-prefix")
 	if err != nil {
@@ -148,6 +148,12 @@ func ToURLPath(domain string) string {
 	return "/" + plural
 }
 
+// ToRealtimeStreamPath returns the URL path of a domain's realtime event stream.
+// Example: "userProfile" -> "/events/user-profiles"
+func ToRealtimeStreamPath(domain string) string {
+	return "/events" + ToURLPath(domain)
+}
+
 // Pluralize returns the plural form of a word.
 // Examples: "user" -> "users", "category" -> "categories", "person" -> "people"
 func Pluralize(s string) string {
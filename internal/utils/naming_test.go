@@ -335,6 +335,12 @@ func TestToURLPath(t *testing.T) {
 	}
 }
 
+func TestToRealtimeStreamPath(t *testing.T) {
+	if got := ToRealtimeStreamPath("userProfile"); got != "/events/user-profiles" {
+		t.Errorf("ToRealtimeStreamPath(%q) = %q, want %q", "userProfile", got, "/events/user-profiles")
+	}
+}
+
 func TestPluralize(t *testing.T) {
 	tests := []struct {
 		input string
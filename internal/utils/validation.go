@@ -4,6 +4,7 @@ package utils
 import (
 	"fmt"
 	"regexp"
+	"strconv"
 	"strings"
 	"unicode"
 )
@@ -14,9 +15,33 @@ var validProjectNameRegex = regexp.MustCompile(`^[a-zA-Z][a-zA-Z0-9_-]*$`)
 // validModulePathRegex matches valid Go module paths.
 var validModulePathRegex = regexp.MustCompile(`^[a-zA-Z0-9][-a-zA-Z0-9_.]*(/[-a-zA-Z0-9_.]+)*$`)
 
+// validBulkActionNameRegex matches snake_case bulk action names.
+var validBulkActionNameRegex = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// apiVersionRouteGroupRegex matches versioned API route groups (api_v1, api_v2, ...).
+var apiVersionRouteGroupRegex = regexp.MustCompile(`^api_(v[1-9][0-9]*)$`)
+
+// validTenantSlugRegex matches tenant slugs, which double as subdomain labels.
+var validTenantSlugRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// validHostNameRegex matches DNS host names made of lowercase labels.
+var validHostNameRegex = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?(\.[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?)*$`)
+
+// validSettingKeyRegex matches setting keys and settings group names.
+var validSettingKeyRegex = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// validHeaderNameRegex matches HTTP header names.
+var validHeaderNameRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9-]*$`)
+
+// validTableNameRegex matches SQL table names, optionally qualified by a schema.
+var validTableNameRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*(\.[a-zA-Z_][a-zA-Z0-9_]*)?$`)
+
 // validIdentifierRegex matches valid Go identifiers.
 var validIdentifierRegex = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_]*$`)
 
+// wizardConditionRegex matches a wizard step condition such as delivery == "ship".
+var wizardConditionRegex = regexp.MustCompile(`^([a-z][a-z0-9_]*)\s*(==|!=)\s*(?:"([^"'\\]*)"|'([^"'\\]*)')$`)
+
 // validDatabaseTypes are the supported database types.
 var validDatabaseTypes = map[string]bool{
 	"":         true, // empty defaults to sqlite
@@ -25,6 +50,80 @@ var validDatabaseTypes = map[string]bool{
 	"mysql":    true,
 }
 
+// validNavigationModes are the supported project navigation modes.
+var validNavigationModes = map[string]bool{
+	"":       true, // empty defaults to none
+	"none":   true,
+	"boost":  true,
+	"unpoly": true,
+}
+
+// validProjectTypes are the supported project types.
+var validProjectTypes = map[string]bool{
+	"":    true, // empty defaults to web
+	"web": true,
+	"api": true,
+}
+
+// validCSSPipelines are the supported ways of building a project's CSS.
+var validCSSPipelines = map[string]bool{
+	"":           true, // empty defaults to tailwind
+	"tailwind":   true,
+	"standalone": true,
+	"daisyui":    true,
+	"postcss":    true,
+}
+
+// validIconSets are the supported SVG icon sets of a project.
+var validIconSets = map[string]bool{
+	"":          true, // empty defaults to heroicons
+	"heroicons": true,
+	"lucide":    true,
+}
+
+// validJSBundlers are the supported bundlers of a project's JavaScript.
+var validJSBundlers = map[string]bool{
+	"":        true, // empty defaults to none
+	"none":    true,
+	"esbuild": true,
+	"vite":    true,
+}
+
+// validPagePresets are the static page presets of scaffold_page.
+var validPagePresets = map[string]bool{
+	"":        true, // empty scaffolds a blank page
+	"about":   true,
+	"pricing": true,
+	"contact": true,
+	"terms":   true,
+}
+
+// validMenuSections are the sections of config/en/menu.toml.
+var validMenuSections = map[string]bool{
+	"main":    true,
+	"admin":   true,
+	"account": true,
+	"site":    true,
+	"footer":  true,
+}
+
+// validMenuIconRegex matches an icon name of the sidebar (e.g., "folder", "chart-bar").
+var validMenuIconRegex = regexp.MustCompile(`^[a-z][a-z0-9]*(-[a-z0-9]+)*$`)
+
+// validErrorReportingProviders are the supported project error reporting providers.
+var validErrorReportingProviders = map[string]bool{
+	"":       true, // empty defaults to none
+	"none":   true,
+	"sentry": true,
+}
+
+// validDIModes are the supported ways of wiring a project's domains.
+var validDIModes = map[string]bool{
+	"":           true, // empty defaults to imperative
+	"imperative": true,
+	"wire":       true,
+}
+
 // validViewTypes are the supported view types.
 var validViewTypes = map[string]bool{
 	"list":   true,
@@ -35,6 +134,13 @@ var validViewTypes = map[string]bool{
 	"custom": true,
 }
 
+// validSearchModes are the supported list search modes.
+var validSearchModes = map[string]bool{
+	"":         true, // empty defaults to like
+	"like":     true,
+	"fulltext": true,
+}
+
 // validFormTypes are the supported form field types.
 var validFormTypes = map[string]bool{
 	"":         true, // empty defaults to input
@@ -52,6 +158,7 @@ var validFormTypes = map[string]bool{
 	"rating":   true,
 	"tags":     true,
 	"slider":   true,
+	"image":    true,
 }
 
 // validGoTypes are commonly valid Go types for model fields.
@@ -93,6 +200,16 @@ var validGoTypes = map[string]bool{
 	"[]uint":   true,
 }
 
+// inlineEditableTypes are the field types that support inline editing in list views.
+var inlineEditableTypes = map[string]bool{
+	"string":  true,
+	"int":     true,
+	"int64":   true,
+	"uint":    true,
+	"float64": true,
+	"bool":    true,
+}
+
 // validModalTypes are the supported modal types.
 var validModalTypes = map[string]bool{
 	"dialog":  true,
@@ -100,6 +217,13 @@ var validModalTypes = map[string]bool{
 	"confirm": true,
 }
 
+// validModalActions are the domain forms a modal can show.
+var validModalActions = map[string]bool{
+	"":       true, // empty defaults to create
+	"create": true,
+	"edit":   true,
+}
+
 // validConfigTypes are the supported config types.
 var validConfigTypes = map[string]bool{
 	"page":     true,
@@ -201,6 +325,149 @@ func ValidateDatabaseType(dbType string) error {
 	return nil
 }
 
+// ValidateNavigation validates a project navigation mode.
+func ValidateNavigation(mode string) error {
+	if !validNavigationModes[mode] {
+		return fmt.Errorf("invalid navigation '%s': must be one of none, boost, unpoly", mode)
+	}
+	return nil
+}
+
+// ValidateProjectType validates a project type.
+func ValidateProjectType(projectType string) error {
+	if !validProjectTypes[projectType] {
+		return fmt.Errorf("invalid project_type '%s': must be web or api", projectType)
+	}
+	return nil
+}
+
+// ValidateCSSPipeline validates a project CSS pipeline.
+func ValidateCSSPipeline(pipeline string) error {
+	if !validCSSPipelines[pipeline] {
+		return fmt.Errorf("invalid css_pipeline '%s': must be one of tailwind, standalone, daisyui, postcss", pipeline)
+	}
+	return nil
+}
+
+// ValidateIconSet validates a project icon set.
+func ValidateIconSet(set string) error {
+	if !validIconSets[set] {
+		return fmt.Errorf("invalid icon_set '%s': must be heroicons or lucide", set)
+	}
+	return nil
+}
+
+// ValidateJSBundler validates a project JavaScript bundler.
+func ValidateJSBundler(bundler string) error {
+	if !validJSBundlers[bundler] {
+		return fmt.Errorf("invalid js_bundler '%s': must be one of none, esbuild, vite", bundler)
+	}
+	return nil
+}
+
+// ValidatePagePreset validates a scaffold_page preset.
+func ValidatePagePreset(preset string) error {
+	if !validPagePresets[preset] {
+		return fmt.Errorf("invalid preset '%s': must be one of about, pricing, contact, terms", preset)
+	}
+	return nil
+}
+
+// ValidateMenuSection validates a section of config/en/menu.toml.
+func ValidateMenuSection(section string) error {
+	if !validMenuSections[section] {
+		return fmt.Errorf("invalid section '%s': must be one of main, admin, account, site, footer", section)
+	}
+	return nil
+}
+
+// ValidateMenuURL validates the url of a menu link: a path of the app or an absolute
+// http(s) URL.
+func ValidateMenuURL(url string) error {
+	if url == "" {
+		return fmt.Errorf("url is required")
+	}
+	if strings.ContainsAny(url, " \t\n\"'<>") {
+		return fmt.Errorf("invalid url '%s': must not contain spaces, quotes or angle brackets", url)
+	}
+	if !strings.HasPrefix(url, "/") && !strings.HasPrefix(url, "https://") && !strings.HasPrefix(url, "http://") {
+		return fmt.Errorf("invalid url '%s': must start with / or http(s)://", url)
+	}
+	return nil
+}
+
+// ValidateMenuIcon validates the icon name of a sidebar link.
+func ValidateMenuIcon(icon string) error {
+	if !validMenuIconRegex.MatchString(icon) {
+		return fmt.Errorf("invalid icon '%s': must be a lowercase name such as folder or chart-bar", icon)
+	}
+	return nil
+}
+
+// ValidateErrorReporting validates a project error reporting provider.
+func ValidateErrorReporting(provider string) error {
+	if !validErrorReportingProviders[provider] {
+		return fmt.Errorf("invalid error_reporting '%s': must be none or sentry", provider)
+	}
+	return nil
+}
+
+// ValidateDI validates a project dependency injection mode.
+func ValidateDI(mode string) error {
+	if !validDIModes[mode] {
+		return fmt.Errorf("invalid di '%s': must be imperative or wire", mode)
+	}
+	return nil
+}
+
+// validLayouts are the supported view layouts.
+var validLayouts = map[string]bool{
+	"":          true, // empty defaults to dashboard
+	"dashboard": true,
+	"base":      true,
+	"auth":      true,
+	"none":      true,
+}
+
+// ValidateLayout validates a view layout.
+func ValidateLayout(layout string) error {
+	if !validLayouts[layout] {
+		return fmt.Errorf("invalid layout '%s': must be dashboard, base, auth, or none", layout)
+	}
+	return nil
+}
+
+// ValidateFormStyle validates a form style.
+func ValidateFormStyle(style string) error {
+	switch style {
+	case "", "modal", "page":
+		return nil
+	}
+	return fmt.Errorf("invalid form_style '%s': must be modal or page", style)
+}
+
+// ValidateRouteGroup validates a route group: public, authenticated, admin or api_vN.
+// An empty route group is valid and means public.
+func ValidateRouteGroup(routeGroup string) error {
+	switch routeGroup {
+	case "", "public", "authenticated", "admin":
+		return nil
+	}
+	if APIVersionFromRouteGroup(routeGroup) == "" {
+		return fmt.Errorf("invalid route_group '%s': must be public, authenticated, admin or api_vN (e.g., api_v1)", routeGroup)
+	}
+	return nil
+}
+
+// APIVersionFromRouteGroup returns the API version of a versioned route group
+// ("api_v2" -> "v2"), or an empty string for other route groups.
+func APIVersionFromRouteGroup(routeGroup string) string {
+	if m := apiVersionRouteGroupRegex.FindStringSubmatch(routeGroup); m != nil {
+		return m[1]
+	}
+	return ""
+}
+
 // ValidateDomainName validates a domain name.
 func ValidateDomainName(name string) error {
 	if name == "" {
@@ -348,7 +615,323 @@ func ValidateFieldType(fieldType string) error {
 // ValidateFormType validates a form field type.
 func ValidateFormType(formType string) error {
 	if !validFormTypes[formType] {
-		return fmt.Errorf("invalid form type '%s': must be one of input, textarea, select, checkbox, switch, date, time, datetime, email, password, number, rating, tags, slider", formType)
+		return fmt.Errorf("invalid form type '%s': must be one of input, textarea, select, checkbox, switch, date, time, datetime, email, password, number, rating, tags, slider, image", formType)
+	}
+	return nil
+}
+
+// ValidateInlineEditType validates that a field type supports inline editing.
+func ValidateInlineEditType(fieldType string) error {
+	if !inlineEditableTypes[fieldType] {
+		return fmt.Errorf("type '%s' does not support inline editing: must be one of string, int, int64, uint, float64, bool", fieldType)
+	}
+	return nil
+}
+
+// ValidateFieldRules validates the min, max and pattern rules of a field. Min and max bound
+// the value of number fields and the length of string fields; pattern applies to string fields.
+func ValidateFieldRules(fieldType, formType string, min, max *float64, pattern string) error {
+	if min == nil && max == nil && pattern == "" {
+		return nil
+	}
+	switch fieldType {
+	case "string":
+		if formType == "image" {
+			return fmt.Errorf("min, max and pattern do not apply to image fields")
+		}
+		for _, bound := range []*float64{min, max} {
+			if bound != nil && (*bound < 0 || *bound != float64(int(*bound))) {
+				return fmt.Errorf("min and max of a string field are lengths and must be whole numbers of 0 or more")
+			}
+		}
+		if pattern != "" {
+			if formType == "textarea" {
+				return fmt.Errorf("pattern does not apply to textarea fields")
+			}
+			if _, err := regexp.Compile(pattern); err != nil {
+				return fmt.Errorf("invalid pattern: %v", err)
+			}
+		}
+	case "int", "int64", "uint", "float64":
+		if pattern != "" {
+			return fmt.Errorf("pattern applies to string fields only")
+		}
+		for _, bound := range []*float64{min, max} {
+			if bound != nil && fieldType != "float64" && *bound != float64(int64(*bound)) {
+				return fmt.Errorf("min and max of a %s field must be whole numbers", fieldType)
+			}
+			if bound != nil && fieldType == "uint" && *bound < 0 {
+				return fmt.Errorf("min and max of a uint field must be 0 or more")
+			}
+		}
+	default:
+		return fmt.Errorf("type '%s' does not support min, max or pattern: must be one of string, int, int64, uint, float64", fieldType)
+	}
+	if min != nil && max != nil && *min > *max {
+		return fmt.Errorf("min must not be greater than max")
+	}
+	return nil
+}
+
+// ValidateEmbeddedType validates the struct name of an embedded value type.
+func ValidateEmbeddedType(typeName string) error {
+	if typeName == "" {
+		return fmt.Errorf("embedded type name is required")
+	}
+	if validGoTypes[typeName] || !validIdentifierRegex.MatchString(typeName) {
+		return fmt.Errorf("embedded type '%s' must be a struct name such as Address", typeName)
+	}
+	if !unicode.IsUpper(rune(typeName[0])) {
+		return fmt.Errorf("embedded type '%s' must be in PascalCase", typeName)
+	}
+	return nil
+}
+
+// ValidateEmbeddedFieldType validates the type of a field inside an embedded value type.
+// Embedded fields share the scalar types supported by inline editing.
+func ValidateEmbeddedFieldType(fieldType string) error {
+	if !inlineEditableTypes[fieldType] {
+		return fmt.Errorf("type '%s' is not supported in embedded types: must be one of string, int, int64, uint, float64, bool", fieldType)
+	}
+	return nil
+}
+
+// ValidateJoinFieldType validates the type of an extra column on a many_to_many join table.
+// Join fields share the scalar types supported by inline editing.
+func ValidateJoinFieldType(fieldType string) error {
+	if !inlineEditableTypes[fieldType] {
+		return fmt.Errorf("type '%s' is not supported on join tables: must be one of string, int, int64, uint, float64, bool", fieldType)
+	}
+	return nil
+}
+
+// ValidateNestedFieldType validates the type of a child field edited in a nested form row.
+// Nested rows share the scalar types supported by inline editing.
+func ValidateNestedFieldType(fieldType string) error {
+	if !inlineEditableTypes[fieldType] {
+		return fmt.Errorf("type '%s' is not supported in nested forms: must be one of string, int, int64, uint, float64, bool", fieldType)
+	}
+	return nil
+}
+
+// filterableTypes are the field types list filters can compare.
+var filterableTypes = map[string]bool{
+	"string": true, "int": true, "int64": true, "uint": true, "float64": true,
+	"bool": true, "time.Time": true, "*time.Time": true,
+}
+
+// ValidateFilter validates a list filter on a field of the given type.
+func ValidateFilter(fieldType, operator, widget string) error {
+	if !filterableTypes[fieldType] {
+		return fmt.Errorf("type '%s' cannot be filtered: must be one of string, int, int64, uint, float64, bool, time.Time, *time.Time", fieldType)
+	}
+
+	numeric := fieldType == "int" || fieldType == "int64" || fieldType == "uint" || fieldType == "float64"
+	date := fieldType == "time.Time" || fieldType == "*time.Time"
+
+	switch operator {
+	case "eq":
+		if date {
+			return fmt.Errorf("time fields are filtered by range: use gt, gte, lt or lte")
+		}
+	case "contains":
+		if fieldType != "string" {
+			return fmt.Errorf("operator 'contains' requires a string field")
+		}
+	case "gt", "gte", "lt", "lte":
+		if !numeric && !date {
+			return fmt.Errorf("operator '%s' requires a numeric or time field", operator)
+		}
+	default:
+		return fmt.Errorf("invalid operator '%s': must be one of eq, contains, gt, gte, lt, lte", operator)
+	}
+
+	switch widget {
+	case "text":
+		if fieldType != "string" {
+			return fmt.Errorf("widget 'text' requires a string field")
+		}
+	case "select":
+		if date {
+			return fmt.Errorf("widget 'select' is not supported for time fields")
+		}
+	case "number":
+		if !numeric {
+			return fmt.Errorf("widget 'number' requires a numeric field")
+		}
+	case "date":
+		if !date {
+			return fmt.Errorf("widget 'date' requires a time field")
+		}
+	case "checkbox":
+		if fieldType != "bool" {
+			return fmt.Errorf("widget 'checkbox' requires a bool field")
+		}
+	default:
+		return fmt.Errorf("invalid widget '%s': must be one of text, select, number, date, checkbox", widget)
+	}
+	return nil
+}
+
+// ValidateBulkActionName validates the name of a custom bulk action.
+// delete and export are built into every bulk toolbar and cannot be redefined.
+func ValidateBulkActionName(name string) error {
+	if !validBulkActionNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid bulk action name '%s': must be snake_case, starting with a letter", name)
+	}
+	if name == "delete" || name == "export" {
+		return fmt.Errorf("bulk action '%s' is built in", name)
+	}
+	return nil
+}
+
+// ValidateBulkActionValue validates the value a custom bulk action sets on a field of the given type.
+func ValidateBulkActionValue(fieldType, value string) error {
+	var err error
+	switch fieldType {
+	case "string":
+		return nil
+	case "int", "int64":
+		_, err = strconv.ParseInt(value, 10, 64)
+	case "uint":
+		_, err = strconv.ParseUint(value, 10, 32)
+	case "float64":
+		_, err = strconv.ParseFloat(value, 64)
+	case "bool":
+		_, err = strconv.ParseBool(value)
+	default:
+		return fmt.Errorf("type '%s' cannot be set by a bulk action: must be one of string, int, int64, uint, float64, bool", fieldType)
+	}
+	if err != nil {
+		return fmt.Errorf("value '%s' is not a valid %s", value, fieldType)
+	}
+	return nil
+}
+
+// ValidateSearchMode validates a list search mode.
+func ValidateSearchMode(mode string) error {
+	if !validSearchModes[mode] {
+		return fmt.Errorf("invalid search mode '%s': must be one of like, fulltext", mode)
+	}
+	return nil
+}
+
+// ValidateTableName validates a table name override, such as "tbl_customers" or "legacy.customers".
+func ValidateTableName(name string) error {
+	if !validTableNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid table name '%s': must be letters, digits and underscores, optionally prefixed by a schema", name)
+	}
+	return nil
+}
+
+// validModelHooks are the GORM hooks scaffold_domain can generate.
+var validModelHooks = map[string]bool{
+	"before_save":   true,
+	"before_create": true,
+	"after_create":  true,
+	"before_update": true,
+	"after_update":  true,
+	"after_save":    true,
+	"before_delete": true,
+	"after_delete":  true,
+	"after_find":    true,
+}
+
+// ValidateModelHook validates the name of a GORM hook (e.g., "before_create").
+func ValidateModelHook(hook string) error {
+	if !validModelHooks[hook] {
+		return fmt.Errorf("invalid hook '%s': must be one of before_save, before_create, after_create, before_update, after_update, after_save, before_delete, after_delete, after_find", hook)
+	}
+	return nil
+}
+
+// validTenantResolutions are the ways a request can name its tenant.
+var validTenantResolutions = map[string]bool{
+	"":          true, // empty defaults to both
+	"subdomain": true,
+	"header":    true,
+	"both":      true,
+}
+
+// ValidateTenantResolution validates how requests name their tenant.
+func ValidateTenantResolution(resolution string) error {
+	if !validTenantResolutions[resolution] {
+		return fmt.Errorf("invalid resolution '%s': must be one of subdomain, header, both", resolution)
+	}
+	return nil
+}
+
+// ValidateTenantSlug validates a tenant slug: a lowercase DNS label such as "acme" or "acme-corp".
+func ValidateTenantSlug(slug string) error {
+	if !validTenantSlugRegex.MatchString(slug) {
+		return fmt.Errorf("invalid tenant slug '%s': use lowercase letters, digits and hyphens (a valid subdomain)", slug)
+	}
+	return nil
+}
+
+// ValidateHostName validates a DNS host name such as "shop.example.com".
+func ValidateHostName(host string) error {
+	if len(host) > 253 || !validHostNameRegex.MatchString(host) {
+		return fmt.Errorf("invalid host '%s': use lowercase DNS labels separated by dots", host)
+	}
+	return nil
+}
+
+// ValidateDeployEnvironment validates a deployment environment name, such as "staging".
+// Environment names become part of namespaces and host names, so they must be DNS labels.
+func ValidateDeployEnvironment(env string) error {
+	if len(env) > 32 || !validTenantSlugRegex.MatchString(env) {
+		return fmt.Errorf("invalid environment '%s': use lowercase letters, digits and hyphens", env)
+	}
+	return nil
+}
+
+// ValidateHeaderName validates an HTTP header name.
+func ValidateHeaderName(name string) error {
+	if !validHeaderNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid header name '%s'", name)
+	}
+	return nil
+}
+
+// validWebhookProviders are the providers inbound webhook receivers can verify.
+var validWebhookProviders = map[string]bool{
+	"stripe":  true,
+	"github":  true,
+	"generic": true,
+}
+
+// ValidateWebhookProvider validates an inbound webhook provider.
+func ValidateWebhookProvider(provider string) error {
+	if !validWebhookProviders[provider] {
+		return fmt.Errorf("invalid webhook provider '%s': must be one of stripe, github, generic", provider)
+	}
+	return nil
+}
+
+// validSettingTypes are the types of application settings.
+var validSettingTypes = map[string]bool{
+	"string": true,
+	"text":   true,
+	"int":    true,
+	"float":  true,
+	"bool":   true,
+	"url":    true,
+	"email":  true,
+}
+
+// ValidateSettingType validates the type of an application setting.
+func ValidateSettingType(settingType string) error {
+	if !validSettingTypes[settingType] {
+		return fmt.Errorf("invalid setting type '%s': must be one of string, text, int, float, bool, url, email", settingType)
+	}
+	return nil
+}
+
+// ValidateSettingKey validates a setting key or settings group name, such as "site_name".
+func ValidateSettingKey(key string) error {
+	if !validSettingKeyRegex.MatchString(key) {
+		return fmt.Errorf("invalid setting key '%s': use lowercase snake_case starting with a letter", key)
 	}
 	return nil
 }
@@ -375,6 +958,14 @@ func ValidateModalType(modalType string) error {
 	return nil
 }
 
+// ValidateModalAction validates the domain form of a modal.
+func ValidateModalAction(action string) error {
+	if !validModalActions[action] {
+		return fmt.Errorf("invalid action '%s': must be create or edit", action)
+	}
+	return nil
+}
+
 // ValidateConfigType validates a config type.
 func ValidateConfigType(configType string) error {
 	if configType == "" {
@@ -452,6 +1043,7 @@ var validRelationshipTypes = map[string]bool{
 	"has_one":      true,
 	"has_many":     true,
 	"many_to_many": true,
+	"polymorphic":  true,
 }
 
 // validOnDeleteActions are the supported ON DELETE actions.
@@ -469,7 +1061,7 @@ func ValidateRelationshipType(relType string) error {
 		return fmt.Errorf("relationship type is required")
 	}
 	if !validRelationshipTypes[relType] {
-		return fmt.Errorf("invalid relationship type '%s': must be belongs_to, has_one, has_many, or many_to_many", relType)
+		return fmt.Errorf("invalid relationship type '%s': must be belongs_to, has_one, has_many, many_to_many, or polymorphic", relType)
 	}
 	return nil
 }
@@ -498,3 +1090,13 @@ func ValidateOnDelete(action string) error {
 	}
 	return nil
 }
+
+// ParseWizardCondition splits a wizard step condition such as delivery == "ship" into the
+// field it reads, the operator (== or !=) and the quoted value the answer is compared with.
+func ParseWizardCondition(condition string) (field, op, value string, err error) {
+	m := wizardConditionRegex.FindStringSubmatch(strings.TrimSpace(condition))
+	if m == nil {
+		return "", "", "", fmt.Errorf("invalid condition '%s': must compare a field with a quoted value, e.g. delivery == \"ship\"", condition)
+	}
+	return m[1], m[2], m[3] + m[4], nil
+}
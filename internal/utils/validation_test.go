@@ -287,6 +287,7 @@ func TestValidateFormType(t *testing.T) {
 		{"rating", "rating", false},
 		{"tags", "tags", false},
 		{"slider", "slider", false},
+		{"image", "image", false},
 
 		// Invalid types
 		{"invalid radio", "radio", true},
@@ -364,6 +365,48 @@ func TestValidateModalType(t *testing.T) {
 	}
 }
 
+func TestValidateModalAction(t *testing.T) {
+	for _, action := range []string{"", "create", "edit"} {
+		if err := ValidateModalAction(action); err != nil {
+			t.Errorf("ValidateModalAction(%q) unexpected error: %v", action, err)
+		}
+	}
+	for _, action := range []string{"delete", "Create", "new"} {
+		if err := ValidateModalAction(action); err == nil {
+			t.Errorf("ValidateModalAction(%q) expected error", action)
+		}
+	}
+}
+
+func TestParseWizardCondition(t *testing.T) {
+	tests := []struct {
+		condition string
+		field     string
+		op        string
+		value     string
+	}{
+		{`delivery == "ship"`, "delivery", "==", "ship"},
+		{`delivery=='pickup'`, "delivery", "==", "pickup"},
+		{` gift_wrap != "" `, "gift_wrap", "!=", ""},
+		{`plan != "free plan"`, "plan", "!=", "free plan"},
+	}
+	for _, tt := range tests {
+		field, op, value, err := ParseWizardCondition(tt.condition)
+		if err != nil {
+			t.Errorf("ParseWizardCondition(%q) unexpected error: %v", tt.condition, err)
+			continue
+		}
+		if field != tt.field || op != tt.op || value != tt.value {
+			t.Errorf("ParseWizardCondition(%q) = %q, %q, %q; want %q, %q, %q", tt.condition, field, op, value, tt.field, tt.op, tt.value)
+		}
+	}
+	for _, condition := range []string{"", "delivery", `delivery = "ship"`, "delivery == ship", `Delivery == "ship"`, `delivery == "it's"`, `delivery == "a" || plan == "b"`} {
+		if _, _, _, err := ParseWizardCondition(condition); err == nil {
+			t.Errorf("ParseWizardCondition(%q) expected error", condition)
+		}
+	}
+}
+
 func TestValidateConfigType(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -563,6 +606,7 @@ func TestValidateRelationshipType(t *testing.T) {
 		{"has_one", "has_one", false, ""},
 		{"has_many", "has_many", false, ""},
 		{"many_to_many", "many_to_many", false, ""},
+		{"polymorphic", "polymorphic", false, ""},
 
 		// Invalid types
 		{"empty", "", true, "relationship type is required"},
@@ -630,6 +674,432 @@ func TestValidateRelationshipModel(t *testing.T) {
 	}
 }
 
+func TestValidateEmbeddedType(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+		errMsg  string
+	}{
+		{"simple", "Address", false, ""},
+		{"two words", "GeoPoint", false, ""},
+
+		{"empty", "", true, "embedded type name is required"},
+		{"builtin", "string", true, "must be a struct name"},
+		{"qualified", "models.Address", true, "must be a struct name"},
+		{"lowercase", "address", true, "must be in PascalCase"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateEmbeddedType(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateEmbeddedType(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+				return
+			}
+			if tt.wantErr && tt.errMsg != "" && err != nil {
+				if !strings.Contains(err.Error(), tt.errMsg) {
+					t.Errorf("ValidateEmbeddedType(%q) error = %v, want error containing %q", tt.input, err, tt.errMsg)
+				}
+			}
+		})
+	}
+}
+
+func TestValidateEmbeddedFieldType(t *testing.T) {
+	for _, valid := range []string{"string", "int", "int64", "uint", "float64", "bool"} {
+		if err := ValidateEmbeddedFieldType(valid); err != nil {
+			t.Errorf("ValidateEmbeddedFieldType(%q) unexpected error: %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"time.Time", "*string", "[]string", "Address"} {
+		if err := ValidateEmbeddedFieldType(invalid); err == nil {
+			t.Errorf("ValidateEmbeddedFieldType(%q) expected error", invalid)
+		}
+	}
+}
+
+func TestValidateJoinFieldType(t *testing.T) {
+	for _, valid := range []string{"string", "int", "int64", "uint", "float64", "bool"} {
+		if err := ValidateJoinFieldType(valid); err != nil {
+			t.Errorf("ValidateJoinFieldType(%q) unexpected error: %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"time.Time", "*string", "[]string", "Address"} {
+		if err := ValidateJoinFieldType(invalid); err == nil {
+			t.Errorf("ValidateJoinFieldType(%q) expected error", invalid)
+		}
+	}
+}
+
+func TestValidateNestedFieldType(t *testing.T) {
+	for _, valid := range []string{"string", "int", "int64", "uint", "float64", "bool"} {
+		if err := ValidateNestedFieldType(valid); err != nil {
+			t.Errorf("ValidateNestedFieldType(%q) unexpected error: %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"time.Time", "*time.Time", "[]string", "Address"} {
+		if err := ValidateNestedFieldType(invalid); err == nil {
+			t.Errorf("ValidateNestedFieldType(%q) expected error", invalid)
+		}
+	}
+}
+
+func TestValidateFilter(t *testing.T) {
+	tests := []struct {
+		fieldType, operator, widget string
+		wantErr                     bool
+	}{
+		{"string", "contains", "text", false},
+		{"string", "eq", "select", false},
+		{"float64", "gte", "number", false},
+		{"int", "eq", "select", false},
+		{"bool", "eq", "checkbox", false},
+		{"bool", "eq", "select", false},
+		{"time.Time", "lte", "date", false},
+		{"*time.Time", "gt", "date", false},
+		{"[]string", "eq", "text", true},
+		{"string", "like", "text", true},
+		{"int", "contains", "number", true},
+		{"bool", "gte", "checkbox", true},
+		{"string", "eq", "slider", true},
+		{"int", "eq", "text", true},
+		{"string", "eq", "checkbox", true},
+		{"time.Time", "eq", "select", true},
+		{"time.Time", "eq", "date", true},
+	}
+	for _, tt := range tests {
+		err := ValidateFilter(tt.fieldType, tt.operator, tt.widget)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateFilter(%q, %q, %q) error = %v, wantErr %v", tt.fieldType, tt.operator, tt.widget, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateBulkActionName(t *testing.T) {
+	for _, valid := range []string{"publish", "mark_shipped", "archive2"} {
+		if err := ValidateBulkActionName(valid); err != nil {
+			t.Errorf("ValidateBulkActionName(%q) unexpected error: %v", valid, err)
+		}
+	}
+	for _, invalid := range []string{"", "Publish", "mark-shipped", "2fa", "delete", "export"} {
+		if err := ValidateBulkActionName(invalid); err == nil {
+			t.Errorf("ValidateBulkActionName(%q) expected error", invalid)
+		}
+	}
+}
+
+func TestValidateBulkActionValue(t *testing.T) {
+	tests := []struct {
+		fieldType, value string
+		wantErr          bool
+	}{
+		{"string", "published", false},
+		{"string", "", false},
+		{"int", "-3", false},
+		{"uint", "7", false},
+		{"float64", "9.99", false},
+		{"bool", "true", false},
+		{"int", "many", true},
+		{"uint", "-1", true},
+		{"bool", "yes", true},
+		{"time.Time", "2024-01-01", true},
+		{"*string", "draft", true},
+	}
+	for _, tt := range tests {
+		err := ValidateBulkActionValue(tt.fieldType, tt.value)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateBulkActionValue(%q, %q) error = %v, wantErr %v", tt.fieldType, tt.value, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateFieldRules(t *testing.T) {
+	n := func(v float64) *float64 { return &v }
+	tests := []struct {
+		fieldType, formType string
+		min, max            *float64
+		pattern             string
+		wantErr             bool
+	}{
+		{"string", "", n(3), n(100), "[A-Z]+", false},
+		{"string", "textarea", nil, n(500), "", false},
+		{"float64", "", n(0.5), n(99.5), "", false},
+		{"int", "", n(-10), nil, "", false},
+		{"bool", "", nil, nil, "", false},
+		{"string", "", n(2.5), nil, "", true},
+		{"string", "", n(-1), nil, "", true},
+		{"string", "textarea", nil, nil, "[a-z]+", true},
+		{"string", "image", nil, n(10), "", true},
+		{"string", "", nil, nil, "[a-z", true},
+		{"int", "", n(1.5), nil, "", true},
+		{"uint", "", n(-1), nil, "", true},
+		{"int", "", nil, nil, "[0-9]+", true},
+		{"float64", "", n(10), n(1), "", true},
+		{"time.Time", "", n(1), nil, "", true},
+	}
+	for _, tt := range tests {
+		err := ValidateFieldRules(tt.fieldType, tt.formType, tt.min, tt.max, tt.pattern)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ValidateFieldRules(%q, %q, %v, %v, %q) error = %v, wantErr %v", tt.fieldType, tt.formType, tt.min, tt.max, tt.pattern, err, tt.wantErr)
+		}
+	}
+}
+
+func TestValidateSearchMode(t *testing.T) {
+	for _, mode := range []string{"", "like", "fulltext"} {
+		if err := ValidateSearchMode(mode); err != nil {
+			t.Errorf("ValidateSearchMode(%q) unexpected error: %v", mode, err)
+		}
+	}
+	for _, mode := range []string{"fts", "FULLTEXT", "regex"} {
+		if err := ValidateSearchMode(mode); err == nil {
+			t.Errorf("ValidateSearchMode(%q) expected error", mode)
+		}
+	}
+}
+
+func TestValidateTableName(t *testing.T) {
+	for _, name := range []string{"customers", "tbl_customers", "legacy.customers", "_t2"} {
+		if err := ValidateTableName(name); err != nil {
+			t.Errorf("ValidateTableName(%q) unexpected error: %v", name, err)
+		}
+	}
+	for _, name := range []string{"", "2customers", "my-table", "a.b.c", "users; drop"} {
+		if err := ValidateTableName(name); err == nil {
+			t.Errorf("ValidateTableName(%q) expected error", name)
+		}
+	}
+}
+
+func TestValidateModelHook(t *testing.T) {
+	for _, hook := range []string{"before_create", "after_update", "after_find"} {
+		if err := ValidateModelHook(hook); err != nil {
+			t.Errorf("ValidateModelHook(%q) unexpected error: %v", hook, err)
+		}
+	}
+	for _, hook := range []string{"", "BeforeCreate", "before_validate", "after-save"} {
+		if err := ValidateModelHook(hook); err == nil {
+			t.Errorf("ValidateModelHook(%q) expected error", hook)
+		}
+	}
+}
+
+func TestValidateTenantResolution(t *testing.T) {
+	for _, resolution := range []string{"", "subdomain", "header", "both"} {
+		if err := ValidateTenantResolution(resolution); err != nil {
+			t.Errorf("ValidateTenantResolution(%q) unexpected error: %v", resolution, err)
+		}
+	}
+	for _, resolution := range []string{"path", "Header", "cookie"} {
+		if err := ValidateTenantResolution(resolution); err == nil {
+			t.Errorf("ValidateTenantResolution(%q) expected error", resolution)
+		}
+	}
+}
+
+func TestValidateTenantSlug(t *testing.T) {
+	for _, slug := range []string{"acme", "acme-corp", "a", "tenant1"} {
+		if err := ValidateTenantSlug(slug); err != nil {
+			t.Errorf("ValidateTenantSlug(%q) unexpected error: %v", slug, err)
+		}
+	}
+	for _, slug := range []string{"", "Acme", "acme.corp", "-acme", "acme-", "acme_corp"} {
+		if err := ValidateTenantSlug(slug); err == nil {
+			t.Errorf("ValidateTenantSlug(%q) expected error", slug)
+		}
+	}
+}
+
+func TestValidateHostName(t *testing.T) {
+	for _, host := range []string{"shop.example.com", "localhost", "my-shop.example.co.uk"} {
+		if err := ValidateHostName(host); err != nil {
+			t.Errorf("ValidateHostName(%q) unexpected error: %v", host, err)
+		}
+	}
+	for _, host := range []string{"", "Shop.example.com", "shop..example.com", "-shop.example.com", "shop.example.com.", "https://shop.example.com"} {
+		if err := ValidateHostName(host); err == nil {
+			t.Errorf("ValidateHostName(%q) expected error", host)
+		}
+	}
+}
+
+func TestValidateDeployEnvironment(t *testing.T) {
+	for _, env := range []string{"staging", "production", "qa-2"} {
+		if err := ValidateDeployEnvironment(env); err != nil {
+			t.Errorf("ValidateDeployEnvironment(%q) unexpected error: %v", env, err)
+		}
+	}
+	for _, env := range []string{"", "Staging", "pre_prod", "qa-"} {
+		if err := ValidateDeployEnvironment(env); err == nil {
+			t.Errorf("ValidateDeployEnvironment(%q) expected error", env)
+		}
+	}
+}
+
+func TestValidateWebhookProvider(t *testing.T) {
+	for _, provider := range []string{"stripe", "github", "generic"} {
+		if err := ValidateWebhookProvider(provider); err != nil {
+			t.Errorf("ValidateWebhookProvider(%q) unexpected error: %v", provider, err)
+		}
+	}
+	for _, provider := range []string{"", "Stripe", "paypal"} {
+		if err := ValidateWebhookProvider(provider); err == nil {
+			t.Errorf("ValidateWebhookProvider(%q) expected error", provider)
+		}
+	}
+}
+
+func TestValidateSettingType(t *testing.T) {
+	for _, settingType := range []string{"string", "text", "int", "float", "bool", "url", "email"} {
+		if err := ValidateSettingType(settingType); err != nil {
+			t.Errorf("ValidateSettingType(%q) unexpected error: %v", settingType, err)
+		}
+	}
+	for _, settingType := range []string{"", "String", "image", "time.Duration"} {
+		if err := ValidateSettingType(settingType); err == nil {
+			t.Errorf("ValidateSettingType(%q) expected error", settingType)
+		}
+	}
+}
+
+func TestValidateSettingKey(t *testing.T) {
+	for _, key := range []string{"site_name", "logo", "smtp_port2"} {
+		if err := ValidateSettingKey(key); err != nil {
+			t.Errorf("ValidateSettingKey(%q) unexpected error: %v", key, err)
+		}
+	}
+	for _, key := range []string{"", "SiteName", "site-name", "_site", "2fa", "site.name"} {
+		if err := ValidateSettingKey(key); err == nil {
+			t.Errorf("ValidateSettingKey(%q) expected error", key)
+		}
+	}
+}
+
+func TestValidateNavigation(t *testing.T) {
+	for _, mode := range []string{"", "none", "boost", "unpoly"} {
+		if err := ValidateNavigation(mode); err != nil {
+			t.Errorf("ValidateNavigation(%q) unexpected error: %v", mode, err)
+		}
+	}
+	for _, mode := range []string{"spa", "BOOST", "turbo"} {
+		if err := ValidateNavigation(mode); err == nil {
+			t.Errorf("ValidateNavigation(%q) expected error", mode)
+		}
+	}
+}
+
+func TestValidateErrorReporting(t *testing.T) {
+	for _, provider := range []string{"", "none", "sentry"} {
+		if err := ValidateErrorReporting(provider); err != nil {
+			t.Errorf("ValidateErrorReporting(%q) unexpected error: %v", provider, err)
+		}
+	}
+	for _, provider := range []string{"Sentry", "rollbar", "bugsnag"} {
+		if err := ValidateErrorReporting(provider); err == nil {
+			t.Errorf("ValidateErrorReporting(%q) expected error", provider)
+		}
+	}
+}
+
+func TestValidateIconSet(t *testing.T) {
+	for _, set := range []string{"", "heroicons", "lucide"} {
+		if err := ValidateIconSet(set); err != nil {
+			t.Errorf("ValidateIconSet(%q) unexpected error: %v", set, err)
+		}
+	}
+	for _, set := range []string{"Lucide", "feather", "fontawesome"} {
+		if err := ValidateIconSet(set); err == nil {
+			t.Errorf("ValidateIconSet(%q) expected error", set)
+		}
+	}
+}
+
+func TestValidateCSSPipeline(t *testing.T) {
+	for _, pipeline := range []string{"", "tailwind", "standalone", "daisyui", "postcss"} {
+		if err := ValidateCSSPipeline(pipeline); err != nil {
+			t.Errorf("ValidateCSSPipeline(%q) unexpected error: %v", pipeline, err)
+		}
+	}
+	for _, pipeline := range []string{"Tailwind", "sass", "vite"} {
+		if err := ValidateCSSPipeline(pipeline); err == nil {
+			t.Errorf("ValidateCSSPipeline(%q) expected error", pipeline)
+		}
+	}
+}
+
+func TestValidateJSBundler(t *testing.T) {
+	for _, bundler := range []string{"", "none", "esbuild", "vite"} {
+		if err := ValidateJSBundler(bundler); err != nil {
+			t.Errorf("ValidateJSBundler(%q) unexpected error: %v", bundler, err)
+		}
+	}
+	for _, bundler := range []string{"Vite", "webpack", "rollup"} {
+		if err := ValidateJSBundler(bundler); err == nil {
+			t.Errorf("ValidateJSBundler(%q) expected error", bundler)
+		}
+	}
+}
+
+func TestValidatePagePreset(t *testing.T) {
+	for _, preset := range []string{"", "about", "pricing", "contact", "terms"} {
+		if err := ValidatePagePreset(preset); err != nil {
+			t.Errorf("ValidatePagePreset(%q) unexpected error: %v", preset, err)
+		}
+	}
+	for _, preset := range []string{"About", "faq", "privacy"} {
+		if err := ValidatePagePreset(preset); err == nil {
+			t.Errorf("ValidatePagePreset(%q) expected error", preset)
+		}
+	}
+}
+
+func TestValidateMenuLink(t *testing.T) {
+	for _, section := range []string{"main", "admin", "account", "site", "footer"} {
+		if err := ValidateMenuSection(section); err != nil {
+			t.Errorf("ValidateMenuSection(%q) unexpected error: %v", section, err)
+		}
+	}
+	for _, section := range []string{"", "Main", "sidebar"} {
+		if err := ValidateMenuSection(section); err == nil {
+			t.Errorf("ValidateMenuSection(%q) expected error", section)
+		}
+	}
+	for _, url := range []string{"/", "/admin/users", "/search?q=x", "https://docs.example.com"} {
+		if err := ValidateMenuURL(url); err != nil {
+			t.Errorf("ValidateMenuURL(%q) unexpected error: %v", url, err)
+		}
+	}
+	for _, url := range []string{"", "reports", "javascript:alert(1)", "/a b", `/"x"`} {
+		if err := ValidateMenuURL(url); err == nil {
+			t.Errorf("ValidateMenuURL(%q) expected error", url)
+		}
+	}
+	for _, icon := range []string{"folder", "chart-bar", "x2"} {
+		if err := ValidateMenuIcon(icon); err != nil {
+			t.Errorf("ValidateMenuIcon(%q) unexpected error: %v", icon, err)
+		}
+	}
+	for _, icon := range []string{"", "Folder", "chart bar", "-x", "x-"} {
+		if err := ValidateMenuIcon(icon); err == nil {
+			t.Errorf("ValidateMenuIcon(%q) expected error", icon)
+		}
+	}
+}
+
+func TestValidateDI(t *testing.T) {
+	for _, mode := range []string{"", "imperative", "wire"} {
+		if err := ValidateDI(mode); err != nil {
+			t.Errorf("ValidateDI(%q) unexpected error: %v", mode, err)
+		}
+	}
+	for _, mode := range []string{"Wire", "fx", "dig"} {
+		if err := ValidateDI(mode); err == nil {
+			t.Errorf("ValidateDI(%q) expected error", mode)
+		}
+	}
+}
+
 func TestValidateOnDelete(t *testing.T) {
 	tests := []struct {
 		name    string
@@ -669,3 +1139,44 @@ func TestValidateOnDelete(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateProjectType(t *testing.T) {
+	for _, projectType := range []string{"", "web", "api"} {
+		if err := ValidateProjectType(projectType); err != nil {
+			t.Errorf("ValidateProjectType(%q) unexpected error: %v", projectType, err)
+		}
+	}
+	for _, projectType := range []string{"API", "rest", "cli"} {
+		if err := ValidateProjectType(projectType); err == nil {
+			t.Errorf("ValidateProjectType(%q) expected error", projectType)
+		}
+	}
+}
+
+func TestValidateRouteGroup(t *testing.T) {
+	for _, routeGroup := range []string{"", "public", "authenticated", "admin", "api_v1", "api_v12"} {
+		if err := ValidateRouteGroup(routeGroup); err != nil {
+			t.Errorf("ValidateRouteGroup(%q) unexpected error: %v", routeGroup, err)
+		}
+	}
+	for _, routeGroup := range []string{"Admin", "api", "api_v0", "api_1", "api_v1beta"} {
+		if err := ValidateRouteGroup(routeGroup); err == nil {
+			t.Errorf("ValidateRouteGroup(%q) expected error", routeGroup)
+		}
+	}
+}
+
+func TestAPIVersionFromRouteGroup(t *testing.T) {
+	tests := map[string]string{
+		"api_v1":        "v1",
+		"api_v2":        "v2",
+		"public":        "",
+		"authenticated": "",
+		"api_v0":        "",
+	}
+	for routeGroup, want := range tests {
+		if got := APIVersionFromRouteGroup(routeGroup); got != want {
+			t.Errorf("APIVersionFromRouteGroup(%q) = %q, want %q", routeGroup, got, want)
+		}
+	}
+}